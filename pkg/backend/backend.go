@@ -187,10 +187,16 @@ type Backend interface {
 	// Import imports resources into a stack.
 	Import(ctx context.Context, stack Stack, op UpdateOperation,
 		imports []deploy.Import) (sdkDisplay.ResourceChanges, result.Result)
-	// Refresh refreshes the stack's state from the cloud provider.
-	Refresh(ctx context.Context, stack Stack, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
-	// Destroy destroys all of this stack's resources.
-	Destroy(ctx context.Context, stack Stack, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
+	// Refresh refreshes the stack's state from the cloud provider. If op.Opts.PreviewOnly is set, the plan generated
+	// by the preview, if any, is returned.
+	Refresh(
+		ctx context.Context, stack Stack, op UpdateOperation,
+	) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result)
+	// Destroy destroys all of this stack's resources. If op.Opts.PreviewOnly is set, the plan generated by the
+	// preview, if any, is returned.
+	Destroy(
+		ctx context.Context, stack Stack, op UpdateOperation,
+	) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result)
 	// Watch watches the project's working directory for changes and automatically updates the active stack.
 	Watch(ctx context.Context, stack Stack, op UpdateOperation, paths []string) result.Result
 
@@ -255,6 +261,36 @@ type SpecificDeploymentExporter interface {
 	ExportDeploymentForVersion(ctx context.Context, stack Stack, version string) (*apitype.UntypedDeployment, error)
 }
 
+// DeploymentRestorer is an interface defining an additional capability of a Backend, specifically the ability to
+// import a deployment as a new entry in the stack's update history, so that rolling a stack back to a previous
+// version is itself recorded. This isn't a requirement for all backends and should be checked for dynamically.
+type DeploymentRestorer interface {
+	// RestoreDeployment imports the given deployment into the stack and records an update with the given message
+	// in the stack's history.
+	RestoreDeployment(ctx context.Context, stack Stack, deployment *apitype.UntypedDeployment, message string) error
+}
+
+// StackLocker is an interface defining an additional capability of a Backend, specifically the ability to hold the
+// lock on a stack across several operations, such as when moving resources between stacks. Locks are reentrant, so
+// operations run while the lock is held take it again and succeed. This isn't a requirement for all backends and
+// should be checked for dynamically.
+type StackLocker interface {
+	// Lock takes the lock on the given stack, failing if another process holds it.
+	Lock(ctx context.Context, stackRef StackReference) error
+	// Unlock releases the lock on the given stack once it has been released as many times as it was taken.
+	Unlock(ctx context.Context, stackRef StackReference)
+}
+
+// PolicyGroupStackManager is an interface defining an additional capability of a Backend, specifically the ability to
+// choose which stacks a Policy Group applies to. The Pulumi Cloud manages this in the Pulumi Console. This isn't a
+// requirement for all backends and should be checked for dynamically.
+type PolicyGroupStackManager interface {
+	// AddStackToPolicyGroup makes the given Policy Group apply to the given stack, creating the group if needed.
+	AddStackToPolicyGroup(ctx context.Context, orgName, policyGroup string, stackRef StackReference) error
+	// RemoveStackFromPolicyGroup stops the given Policy Group from applying to the given stack.
+	RemoveStackFromPolicyGroup(ctx context.Context, orgName, policyGroup string, stackRef StackReference) error
+}
+
 // UpdateOperation is a complete stack update operation (preview, update, import, refresh, or destroy).
 type UpdateOperation struct {
 	Proj               *workspace.Project
@@ -116,6 +116,8 @@ func RenderDiffEvent(event engine.Event, seen map[resource.URN]engine.StepEventM
 		// that the display is appropriate for both.
 	case engine.ResourceOperationFailed:
 		return renderDiffResourceOperationFailedEvent(event.Payload().(engine.ResourceOperationFailedPayload), opts)
+	case engine.ResourceOperationRetry:
+		return renderDiffResourceOperationRetryEvent(event.Payload().(engine.ResourceOperationRetryPayload), opts)
 	case engine.ResourceOutputsEvent:
 		return renderDiffResourceOutputsEvent(event.Payload().(engine.ResourceOutputsEventPayload), seen, opts)
 	case engine.ResourcePreEvent:
@@ -376,6 +378,19 @@ func renderDiffResourceOperationFailedEvent(
 	return ""
 }
 
+func renderDiffResourceOperationRetryEvent(
+	payload engine.ResourceOperationRetryPayload, opts Options,
+) string {
+	return opts.Color.Colorize(fmt.Sprintf("%s%s %s: %s%s\n",
+		colors.SpecWarning, payload.Metadata.Op, payload.Metadata.URN, renderRetryMessage(payload), colors.Reset))
+}
+
+// renderRetryMessage describes a failed attempt at a resource operation that is about to be retried.
+func renderRetryMessage(payload engine.ResourceOperationRetryPayload) string {
+	return fmt.Sprintf("attempt %d of %d failed, retrying in %v: %s",
+		payload.Attempt, payload.MaxAttempts, payload.Delay, payload.Error)
+}
+
 func renderDiff(
 	out io.Writer,
 	metadata engine.StepEventMetadata,
@@ -171,6 +171,19 @@ func ConvertEngineEvent(e engine.Event, showSecrets bool) (apitype.EngineEvent,
 			Steps:    p.Steps,
 		}
 
+	case engine.ResourceOperationRetry:
+		p, ok := e.Payload().(engine.ResourceOperationRetryPayload)
+		if !ok {
+			return apiEvent, eventTypePayloadMismatch
+		}
+		apiEvent.ResOpRetryEvent = &apitype.ResOpRetryEvent{
+			Metadata:     convertStepEventMetadata(p.Metadata, showSecrets),
+			Attempt:      p.Attempt,
+			MaxAttempts:  p.MaxAttempts,
+			DelaySeconds: p.Delay.Seconds(),
+			Error:        p.Error,
+		}
+
 	case engine.PolicyLoadEvent:
 		apiEvent.PolicyLoadEvent = &apitype.PolicyLoadEvent{}
 
@@ -233,6 +246,8 @@ func convertStepEventMetadata(md engine.StepEventMetadata, showSecrets bool) api
 		DetailedDiff: detailedDiff,
 		Logical:      md.Logical,
 		Provider:     md.Provider,
+		Undeclared:   md.Undeclared,
+		Drifted:      md.Drifted,
 	}
 }
 
@@ -381,6 +396,16 @@ func ConvertJSONEvent(apiEvent apitype.EngineEvent) (engine.Event, error) {
 			Steps:    p.Steps,
 		})
 
+	case apiEvent.ResOpRetryEvent != nil:
+		p := apiEvent.ResOpRetryEvent
+		event = engine.NewEvent(engine.ResourceOperationRetryPayload{
+			Metadata:    convertJSONStepEventMetadata(p.Metadata),
+			Attempt:     p.Attempt,
+			MaxAttempts: p.MaxAttempts,
+			Delay:       time.Duration(p.DelaySeconds * float64(time.Second)),
+			Error:       p.Error,
+		})
+
 	case apiEvent.PolicyLoadEvent != nil:
 		event = engine.NewEvent(engine.PolicyLoadEventPayload{})
 
@@ -449,6 +474,8 @@ func convertJSONStepEventMetadata(md apitype.StepEventMetadata) engine.StepEvent
 		DetailedDiff: detailedDiff,
 		Logical:      md.Logical,
 		Provider:     md.Provider,
+		Undeclared:   md.Undeclared,
+		Drifted:      md.Drifted,
 	}
 }
 
@@ -199,7 +199,7 @@ func ShowPreviewDigest(events <-chan engine.Event, done chan<- bool, opts Option
 
 				digest.Steps = append(digest.Steps, step)
 			}
-		case engine.ResourceOutputsEvent, engine.ResourceOperationFailed:
+		case engine.ResourceOutputsEvent, engine.ResourceOperationFailed, engine.ResourceOperationRetry:
 		// Because we are only JSON serializing previews, we don't need to worry about outputs
 		// resolving or operations failing.
 
@@ -43,6 +43,7 @@ type Options struct {
 	ShowReplacementSteps   bool                // true to show the replacement steps in the plan.
 	ShowSameResources      bool                // true to show the resources that aren't updated in addition to updates.
 	ShowReads              bool                // true to show resources that are being read in
+	ShowCriticalPath       bool                // true to show the critical path of the deployment once it completes.
 	TruncateOutput         bool                // true if we should truncate long outputs
 	SuppressOutputs        bool                // true to suppress output summarization, e.g. if contains sensitive info.
 	SuppressPermalink      bool                // true to suppress state permalink
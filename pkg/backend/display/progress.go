@@ -31,6 +31,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/graph"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
@@ -38,6 +39,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"golang.org/x/exp/maps"
 )
 
 // DiagInfo contains the bundle of diagnostic information for a single resource.
@@ -151,12 +153,31 @@ type ProgressDisplay struct {
 type opStopwatch struct {
 	start map[resource.URN]time.Time
 	end   map[resource.URN]time.Time
+
+	// The timings of the completed operations, and the states of the resources they were performed on, which are
+	// analyzed to find the critical path of the deployment.
+	timings map[resource.URN]graph.OperationTiming
+	states  map[resource.URN]*resource.State
 }
 
 func newOpStopwatch() opStopwatch {
 	return opStopwatch{
-		start: map[resource.URN]time.Time{},
-		end:   map[resource.URN]time.Time{},
+		start:   map[resource.URN]time.Time{},
+		end:     map[resource.URN]time.Time{},
+		timings: map[resource.URN]graph.OperationTiming{},
+		states:  map[resource.URN]*resource.State{},
+	}
+}
+
+// record records the timing of the given step's operation, which finished at the given time.
+func (sw *opStopwatch) record(step engine.StepEventMetadata, end time.Time) {
+	start, ok := sw.start[step.URN]
+	if !ok || step.Res == nil {
+		return
+	}
+	timing := graph.OperationTiming{Start: start, End: end}
+	if deploy.RecordTiming(sw.timings, step.Op, step.URN, step.Res.Custom, timing) && step.Res.State != nil {
+		sw.states[step.URN] = step.Res.State
 	}
 }
 
@@ -178,6 +199,9 @@ func getEventUrnAndMetadata(event engine.Event) (resource.URN, *engine.StepEvent
 	case engine.ResourceOperationFailed:
 		payload := event.Payload().(engine.ResourceOperationFailedPayload)
 		return payload.Metadata.URN, &payload.Metadata
+	case engine.ResourceOperationRetry:
+		payload := event.Payload().(engine.ResourceOperationRetryPayload)
+		return payload.Metadata.URN, &payload.Metadata
 	case engine.DiagEvent:
 		return event.Payload().(engine.DiagEventPayload).URN, nil
 	case engine.PolicyRemediationEvent:
@@ -497,6 +521,7 @@ func (display *ProgressDisplay) processEndSteps() {
 	// In that case, we want to abruptly terminate the display so as not to confuse.
 	if !wroteMandatoryPolicyViolations {
 		display.printSummary(hasError)
+		display.printCriticalPath()
 	}
 }
 
@@ -775,6 +800,16 @@ func (display *ProgressDisplay) printSummary(hasError bool) {
 	display.println(msg)
 }
 
+// printCriticalPath prints the analysis of the timings of the deployment's operations, if it was requested.
+func (display *ProgressDisplay) printCriticalPath() {
+	if !display.opts.ShowCriticalPath || display.isPreview {
+		return
+	}
+
+	analysis := graph.AnalyzeTimings(maps.Values(display.opStopwatch.states), display.opStopwatch.timings)
+	display.println(RenderTimingAnalysis(analysis, display.opts))
+}
+
 func (display *ProgressDisplay) mergeStreamPayloadsToSinglePayload(
 	payloads []engine.DiagEventPayload,
 ) engine.DiagEventPayload {
@@ -984,6 +1019,7 @@ func (display *ProgressDisplay) processNormalEvent(event engine.Event) {
 		end := time.Now()
 		display.m.Lock()
 		display.opStopwatch.end[step.URN] = end
+		display.opStopwatch.record(step, end)
 		display.m.Unlock()
 
 		// Is this the stack outputs event? If so, we'll need to print it out at the end of the plan.
@@ -1003,6 +1039,8 @@ func (display *ProgressDisplay) processNormalEvent(event engine.Event) {
 		}
 	} else if event.Type == engine.ResourceOperationFailed {
 		row.SetFailed()
+	} else if event.Type == engine.ResourceOperationRetry {
+		row.RecordRetryEvent(event)
 	} else if event.Type == engine.DiagEvent {
 		// also record this diagnostic so we print it at the end.
 		row.RecordDiagEvent(event)
@@ -1107,6 +1145,8 @@ func (display *ProgressDisplay) getStepStatus(step engine.StepEventMetadata, don
 		status = display.getStepInProgressDescription(step)
 	}
 	status = addRetainStatusFlag(status, step)
+	status = addUndeclaredStatusFlag(status, step)
+	status = addDriftedStatusFlag(status, step)
 	return status
 }
 
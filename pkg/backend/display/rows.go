@@ -65,6 +65,7 @@ type ResourceRow interface {
 	RecordDiagEvent(diagEvent engine.Event)
 	RecordPolicyViolationEvent(diagEvent engine.Event)
 	RecordPolicyRemediationEvent(diagEvent engine.Event)
+	RecordRetryEvent(retryEvent engine.Event)
 }
 
 // Implementation of a Row, used for the header of the grid.
@@ -132,6 +133,9 @@ type resourceRowData struct {
 	policyPayloads            []engine.PolicyViolationEventPayload
 	policyRemediationPayloads []engine.PolicyRemediationEventPayload
 
+	// The last retry of an operation on the resource, if any.
+	lastRetry *engine.ResourceOperationRetryPayload
+
 	// If this row should be hidden by default.  We will hide unless we have any child nodes
 	// we need to show.
 	hideRowIfUnnecessary bool
@@ -244,6 +248,12 @@ func (data *resourceRowData) RecordPolicyRemediationEvent(event engine.Event) {
 	data.policyRemediationPayloads = append(data.policyRemediationPayloads, tPayload)
 }
 
+// RecordRetryEvent records that an operation on the resource failed and is being retried.
+func (data *resourceRowData) RecordRetryEvent(event engine.Event) {
+	tPayload := event.Payload().(engine.ResourceOperationRetryPayload)
+	data.lastRetry = &tPayload
+}
+
 type column int
 
 const (
@@ -349,6 +359,24 @@ func addRetainStatusFlag(status string, step engine.StepEventMetadata) string {
 	return status
 }
 
+// addUndeclaredStatusFlag adds a "[undeclared]" suffix to the input string if the step refreshed a resource that
+// still exists but that the program no longer declares.
+func addUndeclaredStatusFlag(status string, step engine.StepEventMetadata) string {
+	if !step.Undeclared || step.Op == deploy.OpDelete || step.Op == deploy.OpRefresh {
+		return status
+	}
+	return status + "[undeclared]"
+}
+
+// addDriftedStatusFlag adds a "[drifted]" suffix to the input string if the step refreshed a resource whose inputs
+// differ from the ones that the program declares.
+func addDriftedStatusFlag(status string, step engine.StepEventMetadata) string {
+	if !step.Drifted || step.Op == deploy.OpDelete || step.Op == deploy.OpRefresh {
+		return status
+	}
+	return status + "[drifted]"
+}
+
 func (data *resourceRowData) getInfoColumn() string {
 	step := data.step
 	switch step.Op {
@@ -384,6 +412,16 @@ func (data *resourceRowData) getInfoColumn() string {
 		appendDiagMessage("[" + changes + "]")
 	}
 
+	// If the current operation has been retried, show how many attempts it has taken. While it is still in progress,
+	// also show why the last attempt failed.
+	if retry := data.lastRetry; retry != nil && retry.Metadata.Op == data.step.Op {
+		if data.IsDone() {
+			appendDiagMessage(fmt.Sprintf("%s%d attempts%s", colors.SpecWarning, retry.Attempt+1, colors.Reset))
+		} else {
+			appendDiagMessage(colors.SpecWarning + renderRetryMessage(*retry) + colors.Reset)
+		}
+	}
+
 	diagInfo := data.diagInfo
 	if data.display.done {
 		// If we are done, show a summary of how many messages were printed.
@@ -0,0 +1,164 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/graph"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// longestWaits is the number of operations listed in the longest waits section of a timing analysis.
+const longestWaits = 5
+
+// RenderTimingAnalysis renders the analysis of a deployment's operation timings: the critical path of operations that
+// determined how long the deployment took, the operations that waited longest to start, and how many operations ran
+// in parallel.
+func RenderTimingAnalysis(analysis *graph.TimingAnalysis, opts Options) string {
+	out := &bytes.Buffer{}
+	fprintIgnoreError(out, opts.Color.Colorize(
+		fmt.Sprintf("%sCritical path:%s\n", colors.SpecHeadline, colors.Reset)))
+	if len(analysis.CriticalPath) == 0 {
+		fprintIgnoreError(out, "    no resource operations were timed\n")
+		return out.String()
+	}
+
+	renderTimings(out, analysis.CriticalPath, false)
+	fprintIgnoreError(out, fmt.Sprintf("    %s of work on the critical path, out of %s in total\n",
+		formatTiming(analysis.CriticalPathWork), formatTiming(analysis.Duration)))
+
+	waits := make([]graph.ResourceTiming, 0, len(analysis.Resources))
+	for _, timing := range analysis.Resources {
+		if timing.Wait > 0 {
+			waits = append(waits, timing)
+		}
+	}
+	sort.SliceStable(waits, func(i, j int) bool { return waits[i].Wait > waits[j].Wait })
+	if len(waits) > longestWaits {
+		waits = waits[:longestWaits]
+	}
+	if len(waits) > 0 {
+		fprintIgnoreError(out, opts.Color.Colorize(
+			fmt.Sprintf("\n%sLongest waits:%s\n", colors.SpecHeadline, colors.Reset)))
+		renderTimings(out, waits, true)
+	}
+
+	fprintIgnoreError(out, opts.Color.Colorize(
+		fmt.Sprintf("\n%sParallelism:%s\n", colors.SpecHeadline, colors.Reset)))
+	fprintIgnoreError(out, fmt.Sprintf("    at most %d %s at once, %.2f on average\n",
+		analysis.PeakParallelism, english.PluralWord(analysis.PeakParallelism, "operation", ""),
+		analysis.AverageParallelism))
+	if analysis.Idle > 0 {
+		fprintIgnoreError(out, fmt.Sprintf("    no operations were running for %s\n", formatTiming(analysis.Idle)))
+	}
+	return out.String()
+}
+
+// ReadEventLogTimings reads the timings of the operations of a deployment from its event log, as written by
+// --event-log. An operation starts with its resource's pre-event and finishes with its outputs event. The events'
+// timestamps are whole seconds, and so are the timings.
+func ReadEventLogTimings(r io.Reader) (map[resource.URN]graph.OperationTiming, error) {
+	starts := map[resource.URN]time.Time{}
+	timings := map[resource.URN]graph.OperationTiming{}
+	decoder := json.NewDecoder(r)
+	for {
+		var event apitype.EngineEvent
+		if err := decoder.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return timings, nil
+			}
+			return nil, fmt.Errorf("reading event log: %w", err)
+		}
+
+		at := time.Unix(int64(event.Timestamp), 0)
+		switch {
+		case event.ResourcePreEvent != nil && !event.ResourcePreEvent.Planning:
+			starts[resource.URN(event.ResourcePreEvent.Metadata.URN)] = at
+		case event.ResOutputsEvent != nil && !event.ResOutputsEvent.Planning:
+			step := event.ResOutputsEvent.Metadata
+			urn := resource.URN(step.URN)
+			start, ok := starts[urn]
+			if !ok {
+				continue
+			}
+			res := step.New
+			if res == nil {
+				res = step.Old
+			}
+			custom := res != nil && res.Custom
+			deploy.RecordTiming(timings, display.StepOp(step.Op), urn, custom, graph.OperationTiming{Start: start, End: at})
+		}
+	}
+}
+
+// renderTimings renders a table of the given operation timings, optionally including what each operation waited on.
+func renderTimings(out *bytes.Buffer, timings []graph.ResourceTiming, waitedOn bool) {
+	header := []string{"Type", "Name", "Work", "Wait"}
+	if waitedOn {
+		header = append(header, "Waited on")
+	}
+	rows := [][]string{header}
+	for _, timing := range timings {
+		row := []string{
+			string(timing.URN.Type()), timing.URN.Name(), formatTiming(timing.Work), formatTiming(timing.Wait),
+		}
+		if waitedOn {
+			var name string
+			if timing.WaitedOn != "" {
+				name = timing.WaitedOn.Name()
+			}
+			row = append(row, name)
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, column := range row {
+			if width := colors.MeasureColorizedString(column); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	for _, row := range rows {
+		// renderRow starts each row with a single space.
+		fprintIgnoreError(out, strings.TrimRight("   "+renderRow(row, widths), " ")+"\n")
+	}
+}
+
+// formatTiming formats a duration to hundredths of a second below a minute, and to whole seconds above.
+func formatTiming(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,112 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/graph"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestRenderTimingAnalysis(t *testing.T) {
+	t.Parallel()
+
+	urn := func(name string) resource.URN {
+		return resource.NewURN("dev", "proj", "", "pkgA:m:typA", name)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds float64) time.Time { return start.Add(time.Duration(seconds * float64(time.Second))) }
+
+	resources := []*resource.State{
+		{URN: urn("a")},
+		{URN: urn("b"), Dependencies: []resource.URN{urn("a")}},
+		{URN: urn("c")},
+	}
+	analysis := graph.AnalyzeTimings(resources, map[resource.URN]graph.OperationTiming{
+		urn("a"): {Start: at(0), End: at(0.5)},
+		urn("b"): {Start: at(2), End: at(75)},
+		urn("c"): {Start: at(1), End: at(1.25)},
+	})
+
+	assert.Equal(t, "Critical path:\n"+
+		"    Type         Name  Work   Wait\n"+
+		"    pkgA:m:typA  a     0.50s  0s\n"+
+		"    pkgA:m:typA  b     1m13s  1.50s\n"+
+		"    1m14s of work on the critical path, out of 1m15s in total\n"+
+		"\n"+
+		"Longest waits:\n"+
+		"    Type         Name  Work   Wait   Waited on\n"+
+		"    pkgA:m:typA  b     1m13s  1.50s  a\n"+
+		"    pkgA:m:typA  c     0.25s  1.00s\n"+
+		"\n"+
+		"Parallelism:\n"+
+		"    at most 1 operation at once, 0.98 on average\n"+
+		"    no operations were running for 1.25s\n",
+		RenderTimingAnalysis(analysis, Options{Color: colors.Never}))
+
+	assert.Equal(t, "Critical path:\n    no resource operations were timed\n",
+		RenderTimingAnalysis(graph.AnalyzeTimings(resources, nil), Options{Color: colors.Never}))
+}
+
+func TestReadEventLogTimings(t *testing.T) {
+	t.Parallel()
+
+	urn := func(name string) string {
+		return string(resource.NewURN("dev", "proj", "", "pkgA:m:typA", name))
+	}
+	step := func(op apitype.OpType, name string, custom bool) apitype.StepEventMetadata {
+		return apitype.StepEventMetadata{
+			Op: op, URN: urn(name), New: &apitype.StepEventStateMetadata{URN: urn(name), Custom: custom},
+		}
+	}
+	events := []apitype.EngineEvent{
+		// The preview's events are not timed.
+		{Timestamp: 90, ResourcePreEvent: &apitype.ResourcePreEvent{Metadata: step("create", "a", true), Planning: true}},
+		{Timestamp: 91, ResOutputsEvent: &apitype.ResOutputsEvent{Metadata: step("create", "a", true), Planning: true}},
+		{Timestamp: 100, ResourcePreEvent: &apitype.ResourcePreEvent{Metadata: step("create", "a", true)}},
+		{Timestamp: 100, ResourcePreEvent: &apitype.ResourcePreEvent{Metadata: step("same", "b", true)}},
+		{Timestamp: 100, ResOutputsEvent: &apitype.ResOutputsEvent{Metadata: step("same", "b", true)}},
+		{Timestamp: 101, ResourcePreEvent: &apitype.ResourcePreEvent{Metadata: step("update", "c", true)}},
+		{Timestamp: 101, ResourcePreEvent: &apitype.ResourcePreEvent{Metadata: step("create", "d", false)}},
+		{Timestamp: 103, ResOutputsEvent: &apitype.ResOutputsEvent{Metadata: step("create", "a", true)}},
+		{Timestamp: 104, ResOutputsEvent: &apitype.ResOutputsEvent{Metadata: step("create", "d", false)}},
+		// c's update failed, so it has no outputs event.
+		{Timestamp: 105, ResOpFailedEvent: &apitype.ResOpFailedEvent{Metadata: step("update", "c", true)}},
+	}
+	var log bytes.Buffer
+	encoder := json.NewEncoder(&log)
+	for _, event := range events {
+		require.NoError(t, encoder.Encode(event))
+	}
+
+	timings, err := ReadEventLogTimings(&log)
+	require.NoError(t, err)
+	assert.Equal(t, map[resource.URN]graph.OperationTiming{
+		resource.URN(urn("a")): {Start: time.Unix(100, 0), End: time.Unix(103, 0)},
+	}, timings)
+
+	_, err = ReadEventLogTimings(strings.NewReader("{"))
+	assert.ErrorContains(t, err, "reading event log")
+}
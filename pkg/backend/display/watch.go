@@ -83,6 +83,12 @@ func ShowWatchEvents(op string, events <-chan engine.Event, done chan<- bool, op
 				PrintfWithWatchPrefix(time.Now(), p.Metadata.URN.Name(),
 					"failed %s %s\n", p.Metadata.Op, p.Metadata.URN.Type())
 			}
+		case engine.ResourceOperationRetry:
+			p := e.Payload().(engine.ResourceOperationRetryPayload)
+			if shouldShow(p.Metadata, opts) {
+				PrintfWithWatchPrefix(time.Now(), p.Metadata.URN.Name(),
+					"retrying %s %s: %s\n", p.Metadata.Op, p.Metadata.URN.Type(), renderRetryMessage(p))
+			}
 		default:
 			contract.Failf("unknown event type '%s'", e.Type)
 		}
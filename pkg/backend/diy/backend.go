@@ -24,6 +24,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -89,6 +90,16 @@ type Backend interface {
 
 	// Upgrade to the latest state store version.
 	Upgrade(ctx context.Context, opts *UpgradeOptions) error
+
+	// CancelStaleLocks removes the locks on a stack whose lease has expired or whose owning process is no longer
+	// running, and returns the number of locks removed.
+	CancelStaleLocks(ctx context.Context, stackRef backend.StackReference) (int, error)
+
+	// PruneHistory removes the update history and checkpoint backups of a stack that aren't kept by the given
+	// retention policy. If retention is nil, the policy configured in the backend's metadata is used.
+	PruneHistory(
+		ctx context.Context, stackRef backend.StackReference, retention *HistoryRetention,
+	) (*PruneHistoryResult, error)
 }
 
 type diyBackend struct {
@@ -104,9 +115,17 @@ type diyBackend struct {
 	mutex  sync.Mutex
 
 	lockID string
+	// lockLease is the lease duration written to the locks taken by this backend.
+	lockLease time.Duration
+	// leases maps the path of each lock held by this backend to a function that stops renewing its lease.
+	leases     map[string]func()
+	leaseMutex sync.Mutex
 
 	gzip bool
 
+	// retention is the history retention policy configured in the backend's metadata, if any.
+	retention *HistoryRetention
+
 	Env env.Env
 
 	// The current project, if any.
@@ -119,6 +138,12 @@ type diyBackend struct {
 	store referenceStore
 }
 
+// Assert we implement the backend.SpecificDeploymentExporter and backend.DeploymentRestorer interfaces.
+var (
+	_ backend.SpecificDeploymentExporter = &diyBackend{}
+	_ backend.DeploymentRestorer         = &diyBackend{}
+)
+
 type diyBackendReference struct {
 	name    tokens.StackName
 	project tokens.Name
@@ -181,6 +206,8 @@ func (r *diyBackendReference) Validate() error       { return r.store.ValidateRe
 func (r *diyBackendReference) StackBasePath() string { return r.store.StackBasePath(r) }
 func (r *diyBackendReference) HistoryDir() string    { return r.store.HistoryDir(r) }
 func (r *diyBackendReference) BackupDir() string     { return r.store.BackupDir(r) }
+func (r *diyBackendReference) JournalDir() string    { return r.store.JournalDir(r) }
+func (r *diyBackendReference) TagsPath() string      { return r.store.TagsPath(r) }
 
 func IsDIYBackendURL(urlstr string) bool {
 	u, err := url.Parse(urlstr)
@@ -271,6 +298,18 @@ func newDIYBackend(
 
 	gzipCompression := opts.Env.GetBool(env.DIYBackendGzip)
 
+	lockLease := defaultLockLease
+	if v := opts.Env.GetString(env.DIYBackendLockLease); v != "" {
+		lockLease, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", env.DIYBackendLockLease.Var().Name(), err)
+		}
+		if lockLease < minLockLease {
+			return nil, fmt.Errorf("invalid %s: %q is shorter than the minimum lease of %v",
+				env.DIYBackendLockLease.Var().Name(), v, minLockLease)
+		}
+	}
+
 	wbucket := &wrappedBucket{bucket: bucket}
 	bucket = nil // prevent accidental use of unwrapped bucket
 
@@ -280,6 +319,7 @@ func newDIYBackend(
 		url:         u,
 		bucket:      wbucket,
 		lockID:      lockID.String(),
+		lockLease:   lockLease,
 		gzip:        gzipCompression,
 		Env:         opts.Env,
 	}
@@ -292,6 +332,7 @@ func newDIYBackend(
 	if err != nil {
 		return nil, err
 	}
+	backend.retention = meta.Retention
 
 	// projectMode tracks whether the current state supports project-scoped stacks.
 	// Historically, the diy backend did not support this.
@@ -435,7 +476,7 @@ func (b *diyBackend) Upgrade(ctx context.Context, opts *UpgradeOptions) error {
 	// This ensures that if permissions are borked for any reason,
 	// (e.g., we can write to .pulumi/*/*" but not ".pulumi/*.")
 	// we don't leave the bucket in a completely inaccessible state.
-	meta := pulumiMeta{Version: 1}
+	meta := pulumiMeta{Version: 1, Retention: b.retention}
 	if err := meta.WriteTo(ctx, b.bucket); err != nil {
 		var s strings.Builder
 		fmt.Fprintf(&s, "Could not write new state metadata file: %v\n", err)
@@ -613,26 +654,8 @@ func (b *diyBackend) SetCurrentProject(project *workspace.Project) {
 	b.currentProject.Store(project)
 }
 
-func (b *diyBackend) GetPolicyPack(ctx context.Context, policyPack string,
-	d diag.Sink,
-) (backend.PolicyPack, error) {
-	return nil, errors.New("DIY backend does not support resource policy")
-}
-
-func (b *diyBackend) ListPolicyGroups(ctx context.Context, orgName string, _ backend.ContinuationToken) (
-	apitype.ListPolicyGroupsResponse, backend.ContinuationToken, error,
-) {
-	return apitype.ListPolicyGroupsResponse{}, nil, errors.New("DIY backend does not support resource policy")
-}
-
-func (b *diyBackend) ListPolicyPacks(ctx context.Context, orgName string, _ backend.ContinuationToken) (
-	apitype.ListPolicyPacksResponse, backend.ContinuationToken, error,
-) {
-	return apitype.ListPolicyPacksResponse{}, nil, errors.New("DIY backend does not support resource policy")
-}
-
 func (b *diyBackend) SupportsTags() bool {
-	return false
+	return true
 }
 
 func (b *diyBackend) SupportsOrganizations() bool {
@@ -731,7 +754,7 @@ func (b *diyBackend) CreateStack(ctx context.Context, stackRef backend.StackRefe
 		return nil, err
 	}
 
-	stack := newStack(diyStackRef, b)
+	stack := newStack(diyStackRef, b, map[apitype.StackTagName]string{})
 	b.d.Infof(diag.Message("", "Created stack '%s'"), stack.Ref())
 
 	return stack, nil
@@ -751,7 +774,12 @@ func (b *diyBackend) GetStack(ctx context.Context, stackRef backend.StackReferen
 		return nil, err
 	}
 
-	return newStack(diyStackRef, b), nil
+	tags, err := b.getStackTags(ctx, diyStackRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStack(diyStackRef, b, tags), nil
 }
 
 func (b *diyBackend) ListStacks(
@@ -763,8 +791,8 @@ func (b *diyBackend) ListStacks(
 		return nil, nil, err
 	}
 
-	// Note that the provided stack filter is only partially honored, since fields like organizations and tags
-	// aren't persisted in the diy backend.
+	// Note that the provided stack filter is only partially honored, since organizations aren't persisted in the
+	// diy backend.
 	results := slice.Prealloc[backend.StackSummary](len(stacks))
 	for _, stackRef := range stacks {
 		// We can check for project name filter here, but be careful about legacy stores where project is always blank.
@@ -773,6 +801,16 @@ func (b *diyBackend) ListStacks(
 			continue
 		}
 
+		if filter.TagName != nil {
+			tags, err := b.getStackTags(ctx, stackRef)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !matchesTagFilter(filter, tags) {
+				continue
+			}
+		}
+
 		chk, err := b.getCheckpoint(ctx, stackRef)
 		if err != nil {
 			// There is a race between listing stacks and getting their checkpoints.  If there's an error getting
@@ -888,11 +926,11 @@ func (b *diyBackend) renameStack(ctx context.Context, oldRef *diyBackendReferenc
 	file := b.stackPath(ctx, oldRef)
 	backupTarget(ctx, b.bucket, file, false)
 
-	// And rename the history folder as well.
+	// And rename the history folder and tags as well.
 	if err = b.renameHistory(ctx, oldRef, newRef); err != nil {
 		return err
 	}
-	return err
+	return b.renameStackTags(ctx, oldRef, newRef)
 }
 
 func (b *diyBackend) GetLatestConfiguration(ctx context.Context,
@@ -969,10 +1007,10 @@ func (b *diyBackend) Import(ctx context.Context, stack backend.Stack,
 
 func (b *diyBackend) Refresh(ctx context.Context, stack backend.Stack,
 	op backend.UpdateOperation,
-) (sdkDisplay.ResourceChanges, result.Result) {
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	err := b.Lock(ctx, stack.Ref())
 	if err != nil {
-		return nil, result.FromError(err)
+		return nil, nil, result.FromError(err)
 	}
 	defer b.Unlock(ctx, stack.Ref())
 
@@ -983,21 +1021,19 @@ func (b *diyBackend) Refresh(ctx context.Context, stack backend.Stack,
 			ShowLink: true,
 		}
 
-		op.Opts.Engine.GeneratePlan = false
-		_, changes, res := b.apply(
-			ctx, apitype.RefreshUpdate, stack, op, opts, nil /*events*/)
-		return changes, res
+		return b.apply(ctx, apitype.RefreshUpdate, stack, op, opts, nil /*events*/)
 	}
 
-	return backend.PreviewThenPromptThenExecute(ctx, apitype.RefreshUpdate, stack, op, b.apply)
+	changes, res := backend.PreviewThenPromptThenExecute(ctx, apitype.RefreshUpdate, stack, op, b.apply)
+	return nil, changes, res
 }
 
 func (b *diyBackend) Destroy(ctx context.Context, stack backend.Stack,
 	op backend.UpdateOperation,
-) (sdkDisplay.ResourceChanges, result.Result) {
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	err := b.Lock(ctx, stack.Ref())
 	if err != nil {
-		return nil, result.FromError(err)
+		return nil, nil, result.FromError(err)
 	}
 	defer b.Unlock(ctx, stack.Ref())
 
@@ -1008,13 +1044,11 @@ func (b *diyBackend) Destroy(ctx context.Context, stack backend.Stack,
 			ShowLink: true,
 		}
 
-		op.Opts.Engine.GeneratePlan = false
-		_, changes, res := b.apply(
-			ctx, apitype.DestroyUpdate, stack, op, opts, nil /*events*/)
-		return changes, res
+		return b.apply(ctx, apitype.DestroyUpdate, stack, op, opts, nil /*events*/)
 	}
 
-	return backend.PreviewThenPromptThenExecute(ctx, apitype.DestroyUpdate, stack, op, b.apply)
+	changes, res := backend.PreviewThenPromptThenExecute(ctx, apitype.DestroyUpdate, stack, op, b.apply)
+	return nil, changes, res
 }
 
 func (b *diyBackend) Query(ctx context.Context, op backend.QueryOperation) error {
@@ -1051,12 +1085,28 @@ func (b *diyBackend) apply(
 			colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stackRef)
 	}
 
+	// Enforce the Policy Packs enabled for this stack.
+	if err := b.appendRequiredPolicies(ctx, diyStackRef, &op); err != nil {
+		return nil, nil, result.FromError(err)
+	}
+
 	// Start the update.
 	update, err := b.newUpdate(ctx, op.SecretsProvider, diyStackRef, op)
 	if err != nil {
 		return nil, nil, result.FromError(err)
 	}
 
+	// Like the service, we use the start of a real update to pick up any metadata changes in the stack's tags.
+	if kind != apitype.PreviewUpdate && !opts.DryRun {
+		tags, err := backend.GetMergedStackTags(ctx, stack, op.Root, op.Proj, op.StackConfiguration.Config)
+		if err != nil {
+			return nil, nil, result.FromError(fmt.Errorf("getting stack tags: %w", err))
+		}
+		if err := b.UpdateStackTags(ctx, stack, tags); err != nil {
+			return nil, nil, result.FromError(err)
+		}
+	}
+
 	// Spawn a display loop to show events on the CLI.
 	displayEvents := make(chan engine.Event)
 	displayDone := make(chan bool)
@@ -1110,9 +1160,9 @@ func (b *diyBackend) apply(
 	case apitype.ResourceImportUpdate:
 		_, changes, updateErr = engine.Import(update, engineCtx, op.Opts.Engine, op.Imports, opts.DryRun)
 	case apitype.RefreshUpdate:
-		_, changes, updateErr = engine.Refresh(update, engineCtx, op.Opts.Engine, opts.DryRun)
+		plan, changes, updateErr = engine.Refresh(update, engineCtx, op.Opts.Engine, opts.DryRun)
 	case apitype.DestroyUpdate:
-		_, changes, updateErr = engine.Destroy(update, engineCtx, op.Opts.Engine, opts.DryRun)
+		plan, changes, updateErr = engine.Destroy(update, engineCtx, op.Opts.Engine, opts.DryRun)
 	case apitype.StackImportUpdate, apitype.RenameUpdate:
 		contract.Failf("unexpected %s event", kind)
 	default:
@@ -1166,6 +1216,13 @@ func (b *diyBackend) apply(
 	if !opts.DryRun {
 		saveErr = b.addToHistory(ctx, diyStackRef, info)
 		backupErr = b.backupStack(ctx, diyStackRef)
+
+		if b.retention != nil && saveErr == nil && backupErr == nil {
+			// Failing to prune shouldn't fail the update; the next update or `pulumi stack history prune` will retry.
+			if _, err := b.pruneHistory(ctx, diyStackRef, b.retention, time.Now()); err != nil {
+				b.d.Warningf(diag.Message("", "could not prune stack history: %v"), err)
+			}
+		}
 	}
 
 	if updateRes != nil {
@@ -1306,6 +1363,53 @@ func (b *diyBackend) ExportDeployment(ctx context.Context,
 	}, nil
 }
 
+// ExportDeploymentForVersion exports the checkpoint that was saved with the given update of the stack, as numbered
+// in the stack's history. Updates are numbered from the oldest, which is version 1, and keep their numbers when
+// older updates are pruned.
+func (b *diyBackend) ExportDeploymentForVersion(ctx context.Context,
+	stk backend.Stack, version string,
+) (*apitype.UntypedDeployment, error) {
+	diyStackRef, err := b.getReference(stk.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(version)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid version %q: versions are positive integers", version)
+	}
+
+	entries, err := b.listHistoryEntries(ctx, diyStackRef)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := b.findHistoryVersion(ctx, entries, n)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("version %d of stack %s does not exist", n, diyStackRef)
+	}
+
+	chk, err := b.readHistoryEntryCheckpoint(ctx, entries, entry)
+	if err != nil {
+		return nil, err
+	}
+	if chk.Latest == nil {
+		return nil, fmt.Errorf("version %d of stack %s has no deployment", n, diyStackRef)
+	}
+
+	data, err := encoding.JSON.Marshal(chk.Latest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apitype.UntypedDeployment{
+		Version:    3,
+		Deployment: json.RawMessage(data),
+	}, nil
+}
+
 func (b *diyBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 	deployment *apitype.UntypedDeployment,
 ) error {
@@ -1320,14 +1424,58 @@ func (b *diyBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 	}
 	defer b.Unlock(ctx, diyStackRef)
 
-	stackName := diyStackRef.FullyQualifiedName()
+	return b.importDeployment(ctx, diyStackRef, deployment)
+}
+
+// RestoreDeployment imports the given deployment and records the import in the stack's history.
+func (b *diyBackend) RestoreDeployment(ctx context.Context, stk backend.Stack,
+	deployment *apitype.UntypedDeployment, message string,
+) error {
+	diyStackRef, err := b.getReference(stk.Ref())
+	if err != nil {
+		return err
+	}
+
+	err = b.Lock(ctx, diyStackRef)
+	if err != nil {
+		return err
+	}
+	defer b.Unlock(ctx, diyStackRef)
+
+	start := time.Now().Unix()
+	if err := b.importDeployment(ctx, diyStackRef, deployment); err != nil {
+		return err
+	}
+
+	info := backend.UpdateInfo{
+		Kind:      apitype.StackImportUpdate,
+		StartTime: start,
+		Message:   message,
+		Result:    backend.SucceededResult,
+		EndTime:   time.Now().Unix(),
+	}
+	if err := b.addToHistory(ctx, diyStackRef, info); err != nil {
+		return err
+	}
+	return b.backupStack(ctx, diyStackRef)
+}
+
+// importDeployment replaces the stack's checkpoint with the given deployment. The caller must hold the stack's lock.
+func (b *diyBackend) importDeployment(ctx context.Context, ref *diyBackendReference,
+	deployment *apitype.UntypedDeployment,
+) error {
+	stackName := ref.FullyQualifiedName()
 	chk, err := stack.MarshalUntypedDeploymentToVersionedCheckpoint(stackName, deployment)
 	if err != nil {
 		return err
 	}
 
-	_, _, err = b.saveCheckpoint(ctx, diyStackRef, chk)
-	return err
+	if _, _, err = b.saveCheckpoint(ctx, ref, chk); err != nil {
+		return err
+	}
+
+	// The imported deployment replaces any progress recorded by an interrupted update.
+	return b.removeJournal(ctx, ref)
 }
 
 func (b *diyBackend) CurrentUser() (string, []string, *workspace.TokenInformation, error) {
@@ -1346,8 +1494,18 @@ func (b *diyBackend) getStacks(ctx context.Context) ([]*diyBackendReference, err
 func (b *diyBackend) UpdateStackTags(ctx context.Context,
 	stack backend.Stack, tags map[apitype.StackTagName]string,
 ) error {
-	// The diy backend does not currently persist tags.
-	return errors.New("stack tags not supported in diy mode")
+	diyStackRef, err := b.getReference(stack.Ref())
+	if err != nil {
+		return err
+	}
+
+	if err := b.saveStackTags(ctx, diyStackRef, tags); err != nil {
+		return err
+	}
+	if s, ok := stack.(*diyStack); ok {
+		s.tags = tags
+	}
+	return nil
 }
 
 func (b *diyBackend) CancelCurrentUpdate(ctx context.Context, stackRef backend.StackReference) error {
@@ -1366,12 +1524,8 @@ func (b *diyBackend) CancelCurrentUpdate(ctx context.Context, stackRef backend.S
 			continue
 		}
 
-		err := b.bucket.Delete(ctx, file.Key)
-		if err != nil {
-			// Race condition, don't error if the file was delete between us calling list and now
-			if gcerrors.Code(err) == gcerrors.NotFound {
-				return nil
-			}
+		// Don't error if the lock was deleted between us calling list and now.
+		if err := b.deleteLock(ctx, file.Key); err != nil {
 			return err
 		}
 	}
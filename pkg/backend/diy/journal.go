@@ -0,0 +1,304 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// defaultJournalCompactThreshold is the number of journal entries written before the journal is compacted into a
+// full checkpoint.
+const defaultJournalCompactThreshold = 100
+
+// journalEntry is a single entry in a stack's update journal.
+//
+// Resources are identified by their index in a table that starts out as the resources of the base checkpoint, in
+// order. Each entry adds the states of new and modified resources to the table and lists the table indices that
+// make up the snapshot.
+//
+// Blob storage doesn't support appending to an object, so each entry is stored as its own object in the stack's
+// JournalDir, named by the generation of the checkpoint it applies to and its sequence number within that
+// generation (see journalEntryName). Entries of other generations are never replayed, so a journal that was only
+// partially removed can't be mixed up with a later one.
+type journalEntry struct {
+	// Base is the manifest time of the checkpoint this journal applies to. Entries that don't match the stack's
+	// checkpoint are left over from a previous update and are ignored.
+	Base time.Time `json:"base"`
+	// Manifest is the manifest of the snapshot.
+	Manifest apitype.ManifestV1 `json:"manifest"`
+	// SecretsProviders is the secrets provider used to encrypt the states in this entry.
+	SecretsProviders *apitype.SecretsProvidersV1 `json:"secrets_providers,omitempty"`
+	// Resources are the table indices of the snapshot's resources, in order.
+	Resources []int `json:"resources"`
+	// States are the states of resources that are new or have changed since the previous entry.
+	States map[int]apitype.ResourceV3 `json:"states,omitempty"`
+	// PendingOperations are all of the snapshot's pending operations.
+	PendingOperations []apitype.OperationV2 `json:"pending_operations,omitempty"`
+}
+
+// diyJournalPersister is a backend.JournalPersister that appends the changes made by each step to a journal in
+// blob storage, and periodically compacts them into a full checkpoint.
+type diyJournalPersister struct {
+	// TODO[pulumi/pulumi#12593]:
+	// Remove this once SnapshotPersister is updated to take a context.
+	ctx context.Context
+
+	ref       *diyBackendReference
+	backend   *diyBackend
+	threshold int
+
+	// base is the manifest time of the last checkpoint written by this persister.
+	base time.Time
+	// ids maps every state that has been persisted since the last checkpoint to its index in the journal's table.
+	ids map[*resource.State]int
+	// entries is the number of journal entries written since the last checkpoint.
+	entries int
+}
+
+var _ backend.JournalPersister = (*diyJournalPersister)(nil)
+
+// Save writes the snapshot as a full checkpoint and discards the journal.
+func (jp *diyJournalPersister) Save(snapshot *deploy.Snapshot) error {
+	if _, err := jp.backend.saveStack(jp.ctx, jp.ref, snapshot, snapshot.SecretsManager); err != nil {
+		return err
+	}
+
+	jp.base = snapshot.Manifest.Time
+	jp.ids = make(map[*resource.State]int, len(snapshot.Resources))
+	for i, res := range snapshot.Resources {
+		jp.ids[res] = i
+	}
+	jp.entries = 0
+
+	return jp.backend.removeJournal(jp.ctx, jp.ref)
+}
+
+// Append writes the changes since the last save as a journal entry. The first save of an update, and every save
+// after the compaction threshold is reached, writes a full checkpoint instead.
+func (jp *diyJournalPersister) Append(snapshot *deploy.Snapshot, dirty map[*resource.State]bool) error {
+	if jp.ids == nil || jp.entries >= jp.threshold {
+		return jp.Save(snapshot)
+	}
+
+	var enc config.Encrypter = config.NewPanicCrypter()
+	entry := journalEntry{
+		Base:      jp.base,
+		Manifest:  snapshot.Manifest.Serialize(),
+		Resources: make([]int, 0, len(snapshot.Resources)),
+		States:    make(map[int]apitype.ResourceV3),
+	}
+	if sm := snapshot.SecretsManager; sm != nil {
+		e, err := sm.Encrypter()
+		if err != nil {
+			return fmt.Errorf("getting encrypter for journal: %w", err)
+		}
+		enc = e
+		entry.SecretsProviders = &apitype.SecretsProvidersV1{Type: sm.Type(), State: sm.State()}
+	}
+
+	for _, res := range snapshot.Resources {
+		id, has := jp.ids[res]
+		if !has {
+			id = len(jp.ids)
+			jp.ids[res] = id
+		}
+		if !has || dirty[res] {
+			state, err := stack.SerializeResource(jp.ctx, res, enc, false /* showSecrets */)
+			if err != nil {
+				return fmt.Errorf("serializing resource: %w", err)
+			}
+			entry.States[id] = state
+		}
+		entry.Resources = append(entry.Resources, id)
+	}
+	for _, op := range snapshot.PendingOperations {
+		sop, err := stack.SerializeOperation(jp.ctx, op, enc, false /* showSecrets */)
+		if err != nil {
+			return err
+		}
+		entry.PendingOperations = append(entry.PendingOperations, sop)
+	}
+
+	m, ext := encoding.JSON, ".json"
+	if jp.backend.gzip {
+		m, ext = encoding.Gzip(m), ".json"+encoding.GZIPExt
+	}
+	byts, err := m.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshalling journal entry: %w", err)
+	}
+
+	file := path.Join(jp.ref.JournalDir(), journalEntryName(jp.base, jp.entries+1)+ext)
+	if err := jp.backend.bucket.WriteAll(jp.ctx, file, byts, nil); err != nil {
+		return fmt.Errorf("writing journal entry: %w", err)
+	}
+	jp.entries++
+	return nil
+}
+
+func (b *diyBackend) newJournalPersister(ctx context.Context, ref *diyBackendReference) *diyJournalPersister {
+	threshold := b.Env.GetInt(env.DIYBackendJournalCompactThreshold)
+	if threshold <= 0 {
+		threshold = defaultJournalCompactThreshold
+	}
+	return &diyJournalPersister{ctx: ctx, ref: ref, backend: b, threshold: threshold}
+}
+
+// journalEntryName returns the name, without extension, of the journal entry with the given sequence number that
+// applies to the checkpoint with the given manifest time.
+func journalEntryName(base time.Time, seq int) string {
+	return fmt.Sprintf("%d-%08d", base.UnixNano(), seq)
+}
+
+// parseJournalEntryName parses a name returned by journalEntryName, ignoring its extension.
+func parseJournalEntryName(name string) (generation int64, seq int, ok bool) {
+	name, _, _ = strings.Cut(name, ".")
+	gen, num, found := strings.Cut(name, "-")
+	if !found {
+		return 0, 0, false
+	}
+	generation, err := strconv.ParseInt(gen, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err = strconv.Atoi(num)
+	if err != nil {
+		return 0, 0, false
+	}
+	return generation, seq, true
+}
+
+// removeJournal deletes the journal of a stack, if any.
+func (b *diyBackend) removeJournal(ctx context.Context, ref *diyBackendReference) error {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	return removeAllByPrefix(ctx, b.bucket, ref.JournalDir())
+}
+
+// replayJournal applies the journal left behind by an update that didn't complete to the checkpoint of a stack.
+//
+// Only entries of the given checkpoint's generation are considered; anything else is left over from an earlier
+// update. Entries are replayed in order up to the first missing or unreadable entry. If no entries apply to the
+// given checkpoint, it is returned unchanged.
+func (b *diyBackend) replayJournal(
+	ctx context.Context, ref *diyBackendReference, chk *apitype.CheckpointV3,
+) (*apitype.CheckpointV3, error) {
+	files, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	if err != nil {
+		return nil, fmt.Errorf("listing journal: %w", err)
+	}
+
+	type journalFile struct {
+		key string
+		seq int
+	}
+	if chk.Latest == nil {
+		return chk, nil
+	}
+	generation := chk.Latest.Manifest.Time.UnixNano()
+
+	var journal []journalFile
+	for _, file := range files {
+		if file.IsDir {
+			continue
+		}
+		gen, seq, ok := parseJournalEntryName(objectName(file))
+		if !ok || gen != generation {
+			continue
+		}
+		journal = append(journal, journalFile{key: file.Key, seq: seq})
+	}
+	if len(journal) == 0 {
+		return chk, nil
+	}
+	sort.Slice(journal, func(i, j int) bool { return journal[i].seq < journal[j].seq })
+
+	table := make(map[int]apitype.ResourceV3, len(chk.Latest.Resources))
+	for i, res := range chk.Latest.Resources {
+		table[i] = res
+	}
+
+	var last *journalEntry
+	replayed := 0
+	for i, file := range journal {
+		if file.seq != i+1 {
+			logging.V(5).Infof("journal for %s is missing entry %d; ignoring later entries", ref, i+1)
+			break
+		}
+
+		byts, err := b.bucket.ReadAll(ctx, file.key)
+		if err != nil {
+			return nil, fmt.Errorf("reading journal entry: %w", err)
+		}
+		m := encoding.JSON
+		if encoding.IsCompressed(byts) {
+			m = encoding.Gzip(m)
+		}
+		var entry journalEntry
+		if err := m.Unmarshal(byts, &entry); err != nil {
+			logging.V(5).Infof("journal entry %s is unreadable; ignoring later entries: %v", file.key, err)
+			break
+		}
+		if !entry.Base.Equal(chk.Latest.Manifest.Time) {
+			logging.V(5).Infof("journal entry %s does not apply to its checkpoint; ignoring later entries", file.key)
+			break
+		}
+
+		for id, state := range entry.States {
+			table[id] = state
+		}
+		last = &entry
+		replayed++
+	}
+	if last == nil {
+		return chk, nil
+	}
+
+	resources := make([]apitype.ResourceV3, 0, len(last.Resources))
+	for _, id := range last.Resources {
+		res, has := table[id]
+		if !has {
+			return nil, fmt.Errorf("journal for %s is corrupt: unknown resource %d", ref, id)
+		}
+		resources = append(resources, res)
+	}
+
+	logging.V(5).Infof("replayed %d journal entries for %s", replayed, ref)
+	result := *chk
+	result.Latest = &apitype.DeploymentV3{
+		Manifest:          last.Manifest,
+		SecretsProviders:  last.SecretsProviders,
+		Resources:         resources,
+		PendingOperations: last.PendingOperations,
+	}
+	return &result, nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+func newJournalTestBackend(t *testing.T, threshold string) (*diyBackend, *diyBackendReference) {
+	t.Helper()
+
+	ctx := context.Background()
+	e := env.NewEnv(env.MapStore{
+		env.DIYBackendJournal.Var().Name():                 "true",
+		env.DIYBackendJournalCompactThreshold.Var().Name(): threshold,
+	})
+	b, err := newDIYBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()), nil,
+		&diyBackendOptions{Env: e})
+	require.NoError(t, err)
+	ref, err := b.parseStackReference("organization/project/dev")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+	return b, ref
+}
+
+func newJournalTestSnapshot(resources ...*resource.State) *deploy.Snapshot {
+	manifest := deploy.Manifest{Time: time.Now()}
+	manifest.Magic = manifest.NewMagic()
+	return deploy.NewSnapshot(manifest, b64.NewBase64SecretsManager(), resources, nil)
+}
+
+func newJournalTestResource(name string) *resource.State {
+	return &resource.State{
+		URN:     resource.NewURN("dev", "project", "", "a:b:c", name),
+		Type:    "a:b:c",
+		Inputs:  resource.PropertyMap{},
+		Outputs: resource.PropertyMap{},
+	}
+}
+
+// Tests that the progress of an interrupted update is recovered from the journal.
+func TestJournalPersister_recovery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newJournalTestBackend(t, "")
+	persister, ok := b.newSnapshotPersister(ctx, ref).(*diyJournalPersister)
+	require.True(t, ok)
+
+	a, c := newJournalTestResource("a"), newJournalTestResource("c")
+	// The first save of an update is always a full checkpoint.
+	require.NoError(t, persister.Append(newJournalTestSnapshot(a), nil))
+	assert.Equal(t, 0, persister.entries)
+
+	c.Outputs["secret"] = resource.MakeSecret(resource.NewStringProperty("shh"))
+	require.NoError(t, persister.Append(newJournalTestSnapshot(c, a), map[*resource.State]bool{c: true}))
+	a.Outputs["x"] = resource.NewStringProperty("y")
+	require.NoError(t, persister.Append(newJournalTestSnapshot(c, a), map[*resource.State]bool{a: true}))
+	assert.Equal(t, 2, persister.entries)
+
+	// Simulate a crash by reading the stack without compacting the journal.
+	snap, err := b.getSnapshot(ctx, b64.Base64SecretsProvider, ref)
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 2)
+	assert.Equal(t, c.URN, snap.Resources[0].URN)
+	assert.Equal(t, c.Outputs, snap.Resources[0].Outputs)
+	assert.Equal(t, a.URN, snap.Resources[1].URN)
+	assert.Equal(t, a.Outputs, snap.Resources[1].Outputs)
+
+	// Compacting writes a full checkpoint and removes the journal.
+	require.NoError(t, persister.Save(newJournalTestSnapshot(c)))
+	files, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	snap, err = b.getSnapshot(ctx, b64.Base64SecretsProvider, ref)
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 1)
+	assert.Equal(t, c.URN, snap.Resources[0].URN)
+}
+
+func TestJournalPersister_threshold(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newJournalTestBackend(t, "2")
+	persister, ok := b.newSnapshotPersister(ctx, ref).(*diyJournalPersister)
+	require.True(t, ok)
+
+	a := newJournalTestResource("a")
+	for i := 0; i < 3; i++ {
+		require.NoError(t, persister.Append(newJournalTestSnapshot(a), map[*resource.State]bool{a: true}))
+	}
+	assert.Equal(t, 2, persister.entries)
+
+	// The next save exceeds the threshold and compacts the journal.
+	require.NoError(t, persister.Append(newJournalTestSnapshot(a), map[*resource.State]bool{a: true}))
+	assert.Equal(t, 0, persister.entries)
+	files, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+// Tests that a journal left behind by an earlier update is ignored once a newer checkpoint has been written.
+func TestJournalPersister_stale(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newJournalTestBackend(t, "")
+	persister, ok := b.newSnapshotPersister(ctx, ref).(*diyJournalPersister)
+	require.True(t, ok)
+
+	a, c := newJournalTestResource("a"), newJournalTestResource("c")
+	require.NoError(t, persister.Append(newJournalTestSnapshot(a), nil))
+	require.NoError(t, persister.Append(newJournalTestSnapshot(a, c), nil))
+
+	// Write a new checkpoint without removing the journal, as if we crashed while compacting.
+	_, err := b.saveStack(ctx, ref, newJournalTestSnapshot(c), nil)
+	require.NoError(t, err)
+
+	snap, err := b.getSnapshot(ctx, b64.Base64SecretsProvider, ref)
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 1)
+	assert.Equal(t, c.URN, snap.Resources[0].URN)
+}
+
+// Tests that entries left behind by a partially removed journal aren't mixed up with the journal of a later
+// checkpoint.
+func TestJournalPersister_partialRemoval(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newJournalTestBackend(t, "")
+	persister, ok := b.newSnapshotPersister(ctx, ref).(*diyJournalPersister)
+	require.True(t, ok)
+
+	a, c, d := newJournalTestResource("a"), newJournalTestResource("c"), newJournalTestResource("d")
+	require.NoError(t, persister.Append(newJournalTestSnapshot(a), nil))
+	require.NoError(t, persister.Append(newJournalTestSnapshot(a, c), nil))
+	require.NoError(t, persister.Append(newJournalTestSnapshot(a, c, d), nil))
+	files, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	stale, err := b.bucket.ReadAll(ctx, files[1].Key)
+	require.NoError(t, err)
+
+	// Compact, then put back the second entry as if removing it had failed.
+	require.NoError(t, persister.Save(newJournalTestSnapshot(a)))
+	require.NoError(t, b.bucket.WriteAll(ctx, files[1].Key, stale, nil))
+
+	// The journal of the new checkpoint is replayed, and the stale entry is ignored.
+	require.NoError(t, persister.Append(newJournalTestSnapshot(a, d), nil))
+	snap, err := b.getSnapshot(ctx, b64.Base64SecretsProvider, ref)
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 2)
+	assert.Equal(t, a.URN, snap.Resources[0].URN)
+	assert.Equal(t, d.URN, snap.Resources[1].URN)
+}
+
+type journalTestRegisterEvent struct {
+	deploy.SourceEvent
+}
+
+func (journalTestRegisterEvent) Goal() *resource.Goal               { return nil }
+func (journalTestRegisterEvent) Hooks() *deploy.ResourceHooks       { return nil }
+func (journalTestRegisterEvent) Done(result *deploy.RegisterResult) {}
+
+// Tests that an update that crashes in the middle of a create-before-delete replacement recovers the old resource
+// as pending deletion, rather than as a second live resource with the same URN.
+func TestJournalPersister_replaceRecovery(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newJournalTestBackend(t, "")
+	persister, ok := b.newSnapshotPersister(ctx, ref).(*diyJournalPersister)
+	require.True(t, ok)
+
+	a, c := newJournalTestResource("a"), newJournalTestResource("c")
+	base := newJournalTestSnapshot(a, c)
+	manager := backend.NewSnapshotManager(persister, base.SecretsManager, base)
+
+	// Write a journal entry for a same step first, so that the replacement itself is journaled rather than saved as a
+	// full checkpoint.
+	same := deploy.NewSameStep(nil, journalTestRegisterEvent{}, a, newJournalTestResource("a"))
+	mutation, err := manager.BeginMutation(same)
+	require.NoError(t, err)
+	require.NoError(t, mutation.End(same, true /* successful */))
+
+	cPrime := newJournalTestResource("c")
+	step := deploy.NewCreateReplacementStep(nil, journalTestRegisterEvent{}, c, cPrime, nil, nil, nil, true)
+	mutation, err = manager.BeginMutation(step)
+	require.NoError(t, err)
+	// Applying the step marks the old resource for deletion in place.
+	c.Delete = true
+	require.NoError(t, mutation.End(step, true /* successful */))
+	assert.Positive(t, persister.entries)
+
+	// Simulate a crash by reading the stack without closing the manager.
+	snap, err := b.getSnapshot(ctx, b64.Base64SecretsProvider, ref)
+	require.NoError(t, err)
+	require.NoError(t, snap.VerifyIntegrity())
+	var live, deleted int
+	for _, res := range snap.Resources {
+		if res.URN != c.URN {
+			continue
+		}
+		if res.Delete {
+			deleted++
+		} else {
+			live++
+		}
+	}
+	assert.Equal(t, 1, live)
+	assert.Equal(t, 1, deleted)
+}
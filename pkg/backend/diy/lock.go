@@ -25,6 +25,9 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/shirou/gopsutil/v3/process"
+	"gocloud.dev/gcerrors"
+
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
@@ -33,14 +36,27 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
+const (
+	// defaultLockLease is the lease duration used for stack locks when DIY_BACKEND_LOCK_LEASE is not set.
+	defaultLockLease = 5 * time.Minute
+	// minLockLease is the shortest lease that DIY_BACKEND_LOCK_LEASE may set, so that renewals have time to land.
+	minLockLease = time.Second
+)
+
 type lockContent struct {
 	Pid       int       `json:"pid"`
 	Username  string    `json:"username"`
 	Hostname  string    `json:"hostname"`
 	Timestamp time.Time `json:"timestamp"`
+
+	// Renewed is the last time the holder of the lock renewed its lease.
+	Renewed time.Time `json:"renewed,omitempty"`
+	// LeaseDuration is how long the lock remains valid after it was last renewed. Locks written by older
+	// versions of the CLI have no lease and never expire.
+	LeaseDuration time.Duration `json:"leaseDuration,omitempty"`
 }
 
-func newLockContent() (*lockContent, error) {
+func newLockContent(lease time.Duration) (*lockContent, error) {
 	u, err := user.Current()
 	if err != nil {
 		return nil, err
@@ -49,15 +65,118 @@ func newLockContent() (*lockContent, error) {
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now()
 	return &lockContent{
-		Pid:       os.Getpid(),
-		Username:  u.Username,
-		Hostname:  hostname,
-		Timestamp: time.Now(),
+		Pid:           os.Getpid(),
+		Username:      u.Username,
+		Hostname:      hostname,
+		Timestamp:     now,
+		Renewed:       now,
+		LeaseDuration: lease,
 	}, nil
 }
 
+// leaseAge returns how long ago the lock was last renewed.
+func (l *lockContent) leaseAge(now time.Time) time.Duration {
+	renewed := l.Renewed
+	if renewed.IsZero() {
+		renewed = l.Timestamp
+	}
+	return now.Sub(renewed)
+}
+
+// expired returns true if the lock has a lease and that lease has not been renewed in time.
+func (l *lockContent) expired(now time.Time) bool {
+	return l.LeaseDuration > 0 && l.leaseAge(now) > l.LeaseDuration
+}
+
+// stale returns true if the lock was taken by a process on this host that is no longer running.
+func (l *lockContent) stale() bool {
+	hostname, err := os.Hostname()
+	if err != nil || hostname != l.Hostname || l.Pid <= 0 {
+		return false
+	}
+	alive, err := process.PidExists(int32(l.Pid))
+	return err == nil && !alive
+}
+
+// describe returns a human readable description of who holds the lock and the state of its lease.
+func (l *lockContent) describe(now time.Time) string {
+	desc := fmt.Sprintf("created by %v@%v (pid %v) at %v",
+		l.Username,
+		l.Hostname,
+		l.Pid,
+		l.Timestamp.Format(time.RFC3339),
+	)
+	if l.LeaseDuration > 0 {
+		desc += fmt.Sprintf(", lease renewed %v ago (expires after %v)",
+			l.leaseAge(now).Round(time.Second), l.LeaseDuration)
+	}
+	if l.stale() {
+		desc += fmt.Sprintf(" [stale: process %v is no longer running]", l.Pid)
+	}
+	return desc
+}
+
+// leaseContent is the content of the file that the holder of a lock rewrites to renew its lease. The lock file
+// itself is never rewritten, so that renewing a lease can't recreate a lock that another process just deleted.
+type leaseContent struct {
+	Renewed time.Time `json:"renewed"`
+}
+
+// leasePath returns the key of the lease file for the lock file with the given key. Lease files live in a
+// subdirectory of the stack's lock directory, so they are never mistaken for locks.
+func leasePath(lockKey string) string {
+	dir, name := path.Split(lockKey)
+	return path.Join(dir, "leases", name)
+}
+
+// readLock reads the lock file with the given key, along with the latest renewal of its lease. It returns nil if
+// the lock file no longer exists.
+func (b *diyBackend) readLock(ctx context.Context, key string) (*lockContent, error) {
+	content, err := b.bucket.ReadAll(ctx, key)
+	if err != nil {
+		// The lock may have been released between listing and reading it.
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	l := &lockContent{}
+	if err := json.Unmarshal(content, &l); err != nil {
+		return nil, err
+	}
+
+	content, err = b.bucket.ReadAll(ctx, leasePath(key))
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return l, nil
+		}
+		return nil, err
+	}
+	var lease leaseContent
+	if err := json.Unmarshal(content, &lease); err != nil {
+		return nil, err
+	}
+	if lease.Renewed.After(l.Renewed) {
+		l.Renewed = lease.Renewed
+	}
+	return l, nil
+}
+
+// deleteLock deletes the lock file with the given key and its lease file. It is not an error for either to be
+// missing already.
+func (b *diyBackend) deleteLock(ctx context.Context, key string) error {
+	for _, k := range []string{key, leasePath(key)} {
+		if err := b.bucket.Delete(ctx, k); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return err
+		}
+	}
+	return nil
+}
+
 // checkForLock looks for any existing locks for this stack, and returns a helpful diagnostic if there is one.
+// Locks whose lease has expired are reclaimed.
 func (b *diyBackend) checkForLock(ctx context.Context, stackRef backend.StackReference) error {
 	stackName := stackRef.FullyQualifiedName()
 	allFiles, err := listBucket(ctx, b.bucket, stackLockDir(stackName))
@@ -69,40 +188,40 @@ func (b *diyBackend) checkForLock(ctx context.Context, stackRef backend.StackRef
 	// We need to convert it to a slash path (/) to compare it to
 	// the keys in the bucket which are always slash paths.
 	wantLock := filepath.ToSlash(b.lockPath(stackRef))
-	var lockKeys []string
+	now := time.Now()
+	var lockDescriptions []string
 	for _, file := range allFiles {
-		if file.IsDir {
+		if file.IsDir || file.Key == wantLock {
 			continue
 		}
-		if file.Key != wantLock {
-			lockKeys = append(lockKeys, file.Key)
-		}
-	}
 
-	if len(lockKeys) > 0 {
-		errorString := fmt.Sprintf("the stack is currently locked by %v lock(s). Either wait for the other "+
-			"process(es) to end or delete the lock file with `pulumi cancel`.", len(lockKeys))
+		l, err := b.readLock(ctx, file.Key)
+		if err != nil {
+			return err
+		}
+		if l == nil {
+			continue
+		}
 
-		for _, lock := range lockKeys {
-			content, err := b.bucket.ReadAll(ctx, lock)
-			if err != nil {
-				return err
-			}
-			l := &lockContent{}
-			err = json.Unmarshal(content, &l)
-			if err != nil {
+		if l.expired(now) {
+			if err := b.deleteLock(ctx, file.Key); err != nil {
 				return err
 			}
-
-			errorString += fmt.Sprintf("\n  %v: created by %v@%v (pid %v) at %v",
-				b.url+"/"+lock,
-				l.Username,
-				l.Hostname,
-				l.Pid,
-				l.Timestamp.Format(time.RFC3339),
-			)
+			b.d.Infof(diag.Message("", "Reclaimed expired lock %v held by %v@%v (lease not renewed for %v)"),
+				b.url+"/"+file.Key, l.Username, l.Hostname, l.leaseAge(now).Round(time.Second))
+			continue
 		}
 
+		lockDescriptions = append(lockDescriptions,
+			fmt.Sprintf("\n  %v: %v", b.url+"/"+file.Key, l.describe(now)))
+	}
+
+	if len(lockDescriptions) > 0 {
+		errorString := fmt.Sprintf("the stack is currently locked by %v lock(s). Either wait for the other "+
+			"process(es) to end or delete the lock file with `pulumi cancel`.", len(lockDescriptions))
+		for _, desc := range lockDescriptions {
+			errorString += desc
+		}
 		return errors.New(errorString)
 	}
 	return nil
@@ -114,7 +233,7 @@ func (b *diyBackend) Lock(ctx context.Context, stackRef backend.StackReference)
 	if err != nil {
 		return err
 	}
-	lockContent, err := newLockContent()
+	lockContent, err := newLockContent(b.lockLease)
 	if err != nil {
 		return err
 	}
@@ -131,11 +250,13 @@ func (b *diyBackend) Lock(ctx context.Context, stackRef backend.StackReference)
 		b.Unlock(ctx, stackRef)
 		return err
 	}
+	b.startLeaseRenewal(stackRef, lockContent)
 	return nil
 }
 
 func (b *diyBackend) Unlock(ctx context.Context, stackRef backend.StackReference) {
-	err := b.bucket.Delete(ctx, b.lockPath(stackRef))
+	b.stopLeaseRenewal(stackRef)
+	err := b.deleteLock(ctx, b.lockPath(stackRef))
 	if err != nil {
 		b.d.Errorf(
 			diag.Message("", "there was a problem deleting the lock at %v, manual clean up may be required: %v"),
@@ -144,6 +265,130 @@ func (b *diyBackend) Unlock(ctx context.Context, stackRef backend.StackReference
 	}
 }
 
+// startLeaseRenewal starts a background goroutine that renews the lease on the given lock until the lock is
+// released with Unlock.
+func (b *diyBackend) startLeaseRenewal(stackRef backend.StackReference, l *lockContent) {
+	if l.LeaseDuration <= 0 {
+		return
+	}
+
+	lockPath := b.lockPath(stackRef)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	b.leaseMutex.Lock()
+	if b.leases == nil {
+		b.leases = make(map[string]func())
+	}
+	if stop, has := b.leases[lockPath]; has {
+		stop()
+	}
+	b.leases[lockPath] = func() {
+		cancel()
+		<-done
+	}
+	b.leaseMutex.Unlock()
+
+	go func() {
+		defer close(done)
+
+		// Renew well before the lease runs out so that a slow write doesn't let it lapse.
+		ticker := time.NewTicker(l.LeaseDuration / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !b.renewLease(ctx, lockPath, l) {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// renewLease writes a fresh renewal time to the lease file of the given lock. It returns false if the lock no longer
+// exists, for example because it was removed with `pulumi cancel`, in which case renewal should stop. Only the lease
+// file is written, so if the lock is deleted while it is being renewed the lock stays deleted, and the lease file
+// left behind is removed at the next renewal.
+func (b *diyBackend) renewLease(ctx context.Context, lockPath string, l *lockContent) bool {
+	exists, err := b.bucket.Exists(ctx, lockPath)
+	if err != nil {
+		if ctx.Err() == nil {
+			b.d.Warningf(diag.Message("", "failed to renew the lease on lock %v: %v"), path.Join(b.url, lockPath), err)
+		}
+		return true
+	}
+	if !exists {
+		if ctx.Err() == nil {
+			b.d.Warningf(diag.Message("", "the lock at %v was removed by another process"), path.Join(b.url, lockPath))
+		}
+		if err := b.deleteLock(ctx, lockPath); err != nil && ctx.Err() == nil {
+			b.d.Warningf(diag.Message("", "failed to delete the lease on lock %v: %v"), path.Join(b.url, lockPath), err)
+		}
+		return false
+	}
+
+	l.Renewed = time.Now()
+	content, err := json.Marshal(leaseContent{Renewed: l.Renewed})
+	contract.AssertNoErrorf(err, "marshaling lease content")
+	if err := b.bucket.WriteAll(ctx, leasePath(lockPath), content, nil); err != nil && ctx.Err() == nil {
+		b.d.Warningf(diag.Message("", "failed to renew the lease on lock %v: %v"), path.Join(b.url, lockPath), err)
+	}
+	return true
+}
+
+// stopLeaseRenewal stops renewing the lease on the given lock, if it is being renewed.
+func (b *diyBackend) stopLeaseRenewal(stackRef backend.StackReference) {
+	lockPath := b.lockPath(stackRef)
+
+	b.leaseMutex.Lock()
+	stop, has := b.leases[lockPath]
+	delete(b.leases, lockPath)
+	b.leaseMutex.Unlock()
+
+	if has {
+		stop()
+	}
+}
+
+// CancelStaleLocks deletes the locks on the given stack whose lease has expired or whose owning process is
+// known to no longer be running, leaving any live locks in place. It returns the number of locks removed.
+func (b *diyBackend) CancelStaleLocks(ctx context.Context, stackRef backend.StackReference) (int, error) {
+	allFiles, err := listBucket(ctx, b.bucket, stackLockDir(stackRef.FullyQualifiedName()))
+	if err != nil {
+		// Don't error if it just wasn't found
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	now := time.Now()
+	removed := 0
+	for _, file := range allFiles {
+		if file.IsDir {
+			continue
+		}
+
+		l, err := b.readLock(ctx, file.Key)
+		if err != nil {
+			return removed, err
+		}
+		if l == nil || (!l.expired(now) && !l.stale()) {
+			continue
+		}
+
+		if err := b.deleteLock(ctx, file.Key); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
 func lockDir() string {
 	return path.Join(workspace.BookkeepingDir, workspace.LockDir)
 }
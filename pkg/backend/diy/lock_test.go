@@ -0,0 +1,260 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+// writeForeignLock writes a lock for the given stack as if it had been taken by another backend instance.
+func writeForeignLock(t *testing.T, b *diyBackend, stackRef backend.StackReference, l *lockContent) string {
+	t.Helper()
+
+	content, err := json.Marshal(l)
+	require.NoError(t, err)
+	key := path.Join(stackLockDir(stackRef.FullyQualifiedName()), "foreign.json")
+	require.NoError(t, b.bucket.WriteAll(context.Background(), key, content, nil))
+	return key
+}
+
+func newLockTestBackend(t *testing.T, e env.Env) (*diyBackend, backend.StackReference) {
+	t.Helper()
+
+	ctx := context.Background()
+	b, err := newDIYBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()), nil,
+		&diyBackendOptions{Env: e})
+	require.NoError(t, err)
+
+	stackRef, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, stackRef, "", nil)
+	require.NoError(t, err)
+	return b, stackRef
+}
+
+func TestLockContent_Lease(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	legacy := &lockContent{Timestamp: now.Add(-24 * time.Hour)}
+	assert.False(t, legacy.expired(now), "locks without a lease never expire")
+
+	live := &lockContent{
+		Timestamp:     now.Add(-time.Hour),
+		Renewed:       now.Add(-time.Minute),
+		LeaseDuration: 5 * time.Minute,
+	}
+	assert.False(t, live.expired(now))
+	assert.Equal(t, time.Minute, live.leaseAge(now))
+
+	expired := &lockContent{
+		Timestamp:     now.Add(-time.Hour),
+		Renewed:       now.Add(-10 * time.Minute),
+		LeaseDuration: 5 * time.Minute,
+	}
+	assert.True(t, expired.expired(now))
+}
+
+func TestLockContent_Stale(t *testing.T) {
+	t.Parallel()
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	self := &lockContent{Pid: os.Getpid(), Hostname: hostname}
+	assert.False(t, self.stale())
+
+	otherHost := &lockContent{Pid: 1 << 30, Hostname: hostname + "-other"}
+	assert.False(t, otherHost.stale(), "we can't tell if processes on other hosts are running")
+
+	dead := &lockContent{Pid: 1 << 30, Hostname: hostname}
+	assert.True(t, dead.stale())
+	assert.Contains(t, dead.describe(time.Now()), "stale")
+}
+
+func TestCheckForLock_ReclaimsExpiredLease(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, stackRef := newLockTestBackend(t, nil)
+
+	key := writeForeignLock(t, b, stackRef, &lockContent{
+		Pid:           1,
+		Username:      "someone",
+		Hostname:      "elsewhere",
+		Timestamp:     time.Now().Add(-time.Hour),
+		Renewed:       time.Now().Add(-time.Hour),
+		LeaseDuration: time.Minute,
+	})
+
+	require.NoError(t, b.checkForLock(ctx, stackRef))
+	exists, err := b.bucket.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, exists, "expired lock should have been reclaimed")
+}
+
+func TestCheckForLock_ReportsLease(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, stackRef := newLockTestBackend(t, nil)
+
+	writeForeignLock(t, b, stackRef, &lockContent{
+		Pid:           1,
+		Username:      "someone",
+		Hostname:      "elsewhere",
+		Timestamp:     time.Now().Add(-time.Hour),
+		Renewed:       time.Now().Add(-time.Minute),
+		LeaseDuration: 5 * time.Minute,
+	})
+
+	err := b.checkForLock(ctx, stackRef)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "someone@elsewhere (pid 1)")
+	assert.Contains(t, err.Error(), "lease renewed 1m0s ago (expires after 5m0s)")
+}
+
+func TestLock_RenewsLease(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, stackRef := newLockTestBackend(t, env.NewEnv(env.MapStore{
+		env.DIYBackendLockLease.Var().Name(): "1s",
+	}))
+
+	require.NoError(t, b.Lock(ctx, stackRef))
+	defer b.Unlock(ctx, stackRef)
+
+	before, err := b.readLock(ctx, b.lockPath(stackRef))
+	require.NoError(t, err)
+	require.NotNil(t, before)
+	assert.Equal(t, time.Second, before.LeaseDuration)
+
+	assert.Eventually(t, func() bool {
+		after, err := b.readLock(ctx, b.lockPath(stackRef))
+		return err == nil && after != nil && after.Renewed.After(before.Renewed)
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// Renewals go to the lease file, the lock file itself is left as it was written.
+	content, err := b.bucket.ReadAll(ctx, b.lockPath(stackRef))
+	require.NoError(t, err)
+	var written lockContent
+	require.NoError(t, json.Unmarshal(content, &written))
+	assert.True(t, written.Renewed.Equal(before.Renewed))
+}
+
+func TestLock_RenewalStopsAfterCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, stackRef := newLockTestBackend(t, env.NewEnv(env.MapStore{
+		env.DIYBackendLockLease.Var().Name(): "1s",
+	}))
+
+	require.NoError(t, b.Lock(ctx, stackRef))
+	require.NoError(t, b.CancelCurrentUpdate(ctx, stackRef))
+
+	// Give the renewal loop a few chances to run, it must not recreate the lock.
+	time.Sleep(time.Second)
+	exists, err := b.bucket.Exists(ctx, b.lockPath(stackRef))
+	require.NoError(t, err)
+	assert.False(t, exists)
+	b.stopLeaseRenewal(stackRef)
+}
+
+func TestNew_invalidLockLease(t *testing.T) {
+	t.Parallel()
+
+	_, err := newDIYBackend(context.Background(), diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()), nil,
+		&diyBackendOptions{Env: env.NewEnv(env.MapStore{
+			env.DIYBackendLockLease.Var().Name(): "forever",
+		})})
+	assert.ErrorContains(t, err, "invalid PULUMI_DIY_BACKEND_LOCK_LEASE")
+
+	_, err = newDIYBackend(context.Background(), diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()), nil,
+		&diyBackendOptions{Env: env.NewEnv(env.MapStore{
+			env.DIYBackendLockLease.Var().Name(): "2ns",
+		})})
+	assert.ErrorContains(t, err, `invalid PULUMI_DIY_BACKEND_LOCK_LEASE: "2ns" is shorter than the minimum lease of 1s`)
+}
+
+func TestRenewLease_DoesNotRecreateDeletedLock(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, stackRef := newLockTestBackend(t, nil)
+
+	// A renewal that finds the lock already deleted, say by `pulumi cancel`, stops and cleans up its lease.
+	lockPath := b.lockPath(stackRef)
+	l, err := newLockContent(time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, b.bucket.WriteAll(ctx, leasePath(lockPath), []byte(`{"renewed":"2024-01-01T00:00:00Z"}`), nil))
+	assert.False(t, b.renewLease(ctx, lockPath, l))
+
+	for _, key := range []string{lockPath, leasePath(lockPath)} {
+		exists, err := b.bucket.Exists(ctx, key)
+		require.NoError(t, err)
+		assert.False(t, exists, key)
+	}
+}
+
+func TestCancelStaleLocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, stackRef := newLockTestBackend(t, nil)
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	// A live lock held by this process should survive.
+	require.NoError(t, b.Lock(ctx, stackRef))
+	defer b.Unlock(ctx, stackRef)
+
+	// A lock held by a process that no longer exists on this host should be removed.
+	key := writeForeignLock(t, b, stackRef, &lockContent{
+		Pid:           1 << 30,
+		Username:      "someone",
+		Hostname:      hostname,
+		Timestamp:     time.Now(),
+		Renewed:       time.Now(),
+		LeaseDuration: time.Hour,
+	})
+
+	removed, err := b.CancelStaleLocks(ctx, stackRef)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	exists, err := b.bucket.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = b.bucket.Exists(ctx, b.lockPath(stackRef))
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -49,6 +49,12 @@ type pulumiMeta struct {
 	// Does not use "omitempty" to differentiate
 	// between a missing field and a zero value.
 	Version int `json:"version" yaml:"version"`
+
+	// Retention is an optional policy that limits how much update history
+	// and how many checkpoint backups are kept for each stack.
+	//
+	// If unset, history is kept forever.
+	Retention *HistoryRetention `json:"retention,omitempty" yaml:"retention,omitempty"`
 }
 
 // ensurePulumiMeta loads the Pulumi state metadata file from the bucket.
@@ -132,6 +138,8 @@ func readPulumiMeta(ctx context.Context, b Bucket) (*pulumiMeta, error) {
 	var state struct {
 		// Version 0 is valid, so we need to use a pointer.
 		Version *int `yaml:"version"`
+
+		Retention *HistoryRetention `yaml:"retention"`
 	}
 
 	if err := yaml.Unmarshal(metaBody, &state); err != nil {
@@ -142,8 +150,15 @@ func readPulumiMeta(ctx context.Context, b Bucket) (*pulumiMeta, error) {
 		return nil, fmt.Errorf("corrupt store: missing version in %q", pulumiMetaPath)
 	}
 
+	if state.Retention != nil {
+		if err := state.Retention.Validate(); err != nil {
+			return nil, fmt.Errorf("corrupt store: invalid retention in %q: %w", pulumiMetaPath, err)
+		}
+	}
+
 	return &pulumiMeta{
-		Version: *state.Version,
+		Version:   *state.Version,
+		Retention: state.Retention,
 	}, nil
 }
 
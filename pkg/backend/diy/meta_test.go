@@ -145,6 +145,11 @@ func TestEnsurePulumiMeta_corruption(t *testing.T) {
 			give:    `version: foo`,
 			wantErr: `corrupt store: unmarshal ".pulumi/meta.yaml"`,
 		},
+		{
+			desc:    "negative retention",
+			give:    "version: 1\nretention:\n  keepLast: -1",
+			wantErr: `corrupt store: invalid retention in ".pulumi/meta.yaml": keepLast must not be negative`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -173,6 +178,14 @@ func TestMeta_roundTrip(t *testing.T) {
 		{desc: "zero", give: pulumiMeta{Version: 0}},
 		{desc: "one", give: pulumiMeta{Version: 1}},
 		{desc: "future", give: pulumiMeta{Version: 42}},
+		{
+			desc: "retention",
+			give: pulumiMeta{Version: 1, Retention: &HistoryRetention{
+				KeepLast:         5,
+				KeepFor:          "720h",
+				DeltaCheckpoints: true,
+			}},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,737 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gocloud.dev/gcerrors"
+	"golang.org/x/exp/slices"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	resourceanalyzer "github.com/pulumi/pulumi/pkg/v3/resource/analyzer"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/slice"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// The diy backend keeps published Policy Packs and Policy Groups in the bucket under PoliciesDir:
+//
+//	.pulumi/policies/groups.json                  the Policy Groups, see diyPolicyGroups
+//	.pulumi/policies/packs/<name>/<version>.json  metadata about a published Policy Pack version
+//	.pulumi/policies/packs/<name>/<version>.tgz   the published Policy Pack itself
+//
+// Policy Packs enabled for a group are installed locally and enforced on every preview and update of the stacks
+// in that group.
+
+// PoliciesDir is a path under the state's root directory
+// where the diy backend stores Policy Packs and Policy Groups.
+var PoliciesDir = path.Join(workspace.BookkeepingDir, workspace.PolicyDir)
+
+// diyPolicyOrg is the organization name that owns all Policy Packs in a diy backend. Like stack references,
+// Policy Pack references in a diy backend are scoped to the single "organization" organization.
+const diyPolicyOrg = "organization"
+
+// defaultPolicyGroup is the name of the Policy Group that applies to every stack.
+const defaultPolicyGroup = "default-policy-group"
+
+func policyGroupsPath() string {
+	return path.Join(PoliciesDir, "groups.json")
+}
+
+func policyPackDir(name string) string {
+	return path.Join(PoliciesDir, "packs", name)
+}
+
+func policyPackMetadataPath(name, versionTag string) string {
+	return path.Join(policyPackDir(name), versionTag+".json")
+}
+
+func policyPackTarballPath(name, versionTag string) string {
+	return path.Join(policyPackDir(name), versionTag+".tgz")
+}
+
+// diyPolicyGroups is the contents of the Policy Groups file.
+type diyPolicyGroups struct {
+	Groups []*diyPolicyGroup `json:"groups"`
+}
+
+// diyPolicyGroup is a set of Policy Packs enforced on a set of stacks.
+type diyPolicyGroup struct {
+	Name string `json:"name"`
+	// IsOrgDefault is true for the default Policy Group, which applies to every stack.
+	IsOrgDefault bool `json:"isOrgDefault"`
+	// Stacks are the fully qualified names of the stacks this group applies to.
+	Stacks []string `json:"stacks,omitempty"`
+	// PolicyPacks are the Policy Packs enabled for this group.
+	PolicyPacks []diyEnabledPolicyPack `json:"policyPacks,omitempty"`
+}
+
+// appliesTo returns true if this group's Policy Packs should be enforced on the given stack.
+func (g *diyPolicyGroup) appliesTo(stack tokens.QName) bool {
+	return g.IsOrgDefault || slices.Contains(g.Stacks, string(stack))
+}
+
+// diyEnabledPolicyPack is a Policy Pack version enabled for a Policy Group.
+type diyEnabledPolicyPack struct {
+	Name       string                      `json:"name"`
+	VersionTag string                      `json:"versionTag"`
+	Config     map[string]*json.RawMessage `json:"config,omitempty"`
+}
+
+// diyPolicyPackVersion is the metadata stored next to each published Policy Pack version.
+type diyPolicyPackVersion struct {
+	apitype.CreatePolicyPackRequest
+	// Version is the sequence number of this version, starting at 1.
+	Version   int       `json:"version"`
+	Published time.Time `json:"published"`
+}
+
+func (b *diyBackend) GetPolicyPack(ctx context.Context, policyPack string,
+	d diag.Sink,
+) (backend.PolicyPack, error) {
+	ref, err := parseDIYPolicyPackReference(policyPack)
+	if err != nil {
+		return nil, err
+	}
+	return &diyPolicyPack{ref: ref, b: b}, nil
+}
+
+func (b *diyBackend) ListPolicyGroups(ctx context.Context, orgName string, _ backend.ContinuationToken) (
+	apitype.ListPolicyGroupsResponse, backend.ContinuationToken, error,
+) {
+	groups, err := b.getPolicyGroups(ctx)
+	if err != nil {
+		return apitype.ListPolicyGroupsResponse{}, nil, err
+	}
+
+	var numStacks int
+	if stacks, err := b.getStacks(ctx); err == nil {
+		numStacks = len(stacks)
+	}
+
+	summaries := slice.Prealloc[apitype.PolicyGroupSummary](len(groups.Groups))
+	for _, g := range groups.Groups {
+		summary := apitype.PolicyGroupSummary{
+			Name:                  g.Name,
+			IsOrgDefault:          g.IsOrgDefault,
+			NumStacks:             len(g.Stacks),
+			NumEnabledPolicyPacks: len(g.PolicyPacks),
+		}
+		if g.IsOrgDefault {
+			summary.NumStacks = numStacks
+		}
+		summaries = append(summaries, summary)
+	}
+	return apitype.ListPolicyGroupsResponse{PolicyGroups: summaries}, nil, nil
+}
+
+func (b *diyBackend) ListPolicyPacks(ctx context.Context, orgName string, _ backend.ContinuationToken) (
+	apitype.ListPolicyPacksResponse, backend.ContinuationToken, error,
+) {
+	names, err := b.listPolicyPackNames(ctx, "")
+	if err != nil {
+		return apitype.ListPolicyPacksResponse{}, nil, err
+	}
+
+	var packs []apitype.PolicyPackWithVersions
+	for _, name := range names {
+		versions, err := b.getPolicyPackVersions(ctx, name)
+		if err != nil {
+			return apitype.ListPolicyPacksResponse{}, nil, err
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		pack := apitype.PolicyPackWithVersions{
+			Name:        versions[0].Name,
+			DisplayName: versions[0].DisplayName,
+		}
+		for _, v := range versions {
+			pack.Versions = append(pack.Versions, v.Version)
+			pack.VersionTags = append(pack.VersionTags, v.VersionTag)
+		}
+		packs = append(packs, pack)
+	}
+	return apitype.ListPolicyPacksResponse{PolicyPacks: packs}, nil, nil
+}
+
+// listPolicyPackNames returns the names of the Policy Packs stored under the given name prefix. Names may contain
+// slashes, which nest their versions into subdirectories, so this walks the whole tree under the prefix.
+func (b *diyBackend) listPolicyPackNames(ctx context.Context, prefix string) ([]string, error) {
+	dirs, err := listBucket(ctx, b.bucket, policyPackDir(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("error listing policy packs: %w", err)
+	}
+
+	var names []string
+	for _, dir := range dirs {
+		if !dir.IsDir {
+			continue
+		}
+		name := path.Join(prefix, objectName(dir))
+		names = append(names, name)
+		nested, err := b.listPolicyPackNames(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, nested...)
+	}
+	return names, nil
+}
+
+// getPolicyGroups reads the Policy Groups file. If there isn't one yet, a default group with no Policy Packs is
+// returned.
+func (b *diyBackend) getPolicyGroups(ctx context.Context) (*diyPolicyGroups, error) {
+	groups := &diyPolicyGroups{}
+	data, err := b.bucket.ReadAll(ctx, policyGroupsPath())
+	if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return nil, fmt.Errorf("could not read policy groups: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, groups); err != nil {
+			return nil, fmt.Errorf("could not parse policy groups %s: %w", policyGroupsPath(), err)
+		}
+	}
+
+	for _, g := range groups.Groups {
+		if g.IsOrgDefault {
+			return groups, nil
+		}
+	}
+	groups.Groups = append([]*diyPolicyGroup{{Name: defaultPolicyGroup, IsOrgDefault: true}}, groups.Groups...)
+	return groups, nil
+}
+
+var _ backend.PolicyGroupStackManager = (*diyBackend)(nil)
+
+func (b *diyBackend) AddStackToPolicyGroup(
+	ctx context.Context, orgName, policyGroup string, stackRef backend.StackReference,
+) error {
+	groups, err := b.getPolicyGroups(ctx)
+	if err != nil {
+		return err
+	}
+
+	stack := string(stackRef.FullyQualifiedName())
+	var group *diyPolicyGroup
+	for _, g := range groups.Groups {
+		if g.Name == policyGroup {
+			group = g
+			break
+		}
+	}
+	switch {
+	case group == nil:
+		group = &diyPolicyGroup{Name: policyGroup}
+		groups.Groups = append(groups.Groups, group)
+	case group.IsOrgDefault:
+		return fmt.Errorf("policy group %q already applies to every stack", policyGroup)
+	case slices.Contains(group.Stacks, stack):
+		return fmt.Errorf("policy group %q already applies to stack %s", policyGroup, stack)
+	}
+	group.Stacks = append(group.Stacks, stack)
+	sort.Strings(group.Stacks)
+
+	return b.savePolicyGroups(ctx, groups)
+}
+
+func (b *diyBackend) RemoveStackFromPolicyGroup(
+	ctx context.Context, orgName, policyGroup string, stackRef backend.StackReference,
+) error {
+	groups, err := b.getPolicyGroups(ctx)
+	if err != nil {
+		return err
+	}
+
+	stack := string(stackRef.FullyQualifiedName())
+	for _, g := range groups.Groups {
+		if g.Name != policyGroup {
+			continue
+		}
+		if g.IsOrgDefault {
+			return fmt.Errorf("policy group %q applies to every stack; stacks can't be removed from it", policyGroup)
+		}
+		if !slices.Contains(g.Stacks, stack) {
+			return fmt.Errorf("policy group %q does not apply to stack %s", policyGroup, stack)
+		}
+		g.Stacks = slices.DeleteFunc(g.Stacks, func(s string) bool { return s == stack })
+		return b.savePolicyGroups(ctx, groups)
+	}
+	return fmt.Errorf("policy group %q does not exist", policyGroup)
+}
+
+func (b *diyBackend) savePolicyGroups(ctx context.Context, groups *diyPolicyGroups) error {
+	data, err := json.MarshalIndent(groups, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshalling policy groups: %w", err)
+	}
+	if err := b.bucket.WriteAll(ctx, policyGroupsPath(), data, nil); err != nil {
+		return fmt.Errorf("could not write policy groups: %w", err)
+	}
+	return nil
+}
+
+// nextPolicyPackVersion returns the sequence number of the next version of a Policy Pack with the given published
+// versions, which must be oldest first. Numbers aren't reused after versions are removed, so the latest version is
+// always the one with the highest number.
+func nextPolicyPackVersion(versions []*diyPolicyPackVersion) int {
+	if len(versions) == 0 {
+		return 1
+	}
+	return versions[len(versions)-1].Version + 1
+}
+
+// getPolicyPackVersions returns the published versions of a Policy Pack, oldest first.
+func (b *diyBackend) getPolicyPackVersions(ctx context.Context, name string) ([]*diyPolicyPackVersion, error) {
+	files, err := listBucket(ctx, b.bucket, policyPackDir(name))
+	if err != nil {
+		return nil, fmt.Errorf("error listing versions of policy pack %q: %w", name, err)
+	}
+
+	var versions []*diyPolicyPackVersion
+	for _, file := range files {
+		if file.IsDir || filepath.Ext(file.Key) != ".json" {
+			continue
+		}
+		data, err := b.bucket.ReadAll(ctx, file.Key)
+		if err != nil {
+			return nil, fmt.Errorf("could not read policy pack metadata: %w", err)
+		}
+		var version diyPolicyPackVersion
+		if err := json.Unmarshal(data, &version); err != nil {
+			return nil, fmt.Errorf("could not parse policy pack metadata %s: %w", file.Key, err)
+		}
+		versions = append(versions, &version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+// getRequiredPolicies returns the Policy Packs enabled for any Policy Group that applies to the given stack.
+func (b *diyBackend) getRequiredPolicies(
+	ctx context.Context, ref *diyBackendReference,
+) ([]engine.RequiredPolicy, error) {
+	groups, err := b.getPolicyGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []engine.RequiredPolicy
+	seen := map[string]bool{}
+	for _, g := range groups.Groups {
+		if !g.appliesTo(ref.FullyQualifiedName()) {
+			continue
+		}
+		for _, p := range g.PolicyPacks {
+			key := p.Name + "@" + p.VersionTag
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			policies = append(policies, &diyRequiredPolicy{pack: p, b: b})
+		}
+	}
+	return policies, nil
+}
+
+// diyRequiredPolicy is a Policy Pack enabled in a diy backend that must be enforced on an update.
+type diyRequiredPolicy struct {
+	pack diyEnabledPolicyPack
+	b    *diyBackend
+}
+
+var _ engine.RequiredPolicy = (*diyRequiredPolicy)(nil)
+
+func (rp *diyRequiredPolicy) Name() string                        { return rp.pack.Name }
+func (rp *diyRequiredPolicy) Version() string                     { return rp.pack.VersionTag }
+func (rp *diyRequiredPolicy) OrgName() string                     { return diyPolicyOrg }
+func (rp *diyRequiredPolicy) Config() map[string]*json.RawMessage { return rp.pack.Config }
+
+func (rp *diyRequiredPolicy) Install(ctx context.Context) (string, error) {
+	policyPackPath, installed, err := workspace.GetPolicyPath(rp.OrgName(),
+		strings.ReplaceAll(rp.pack.Name, tokens.QNameDelimiter, "_"), rp.pack.VersionTag)
+	if err != nil {
+		// Failed to get a sensible PolicyPack path.
+		return "", err
+	} else if installed {
+		// We've already installed the PolicyPack. Return.
+		return policyPackPath, nil
+	}
+
+	fmt.Printf("Installing policy pack %s %s...\r\n", rp.pack.Name, rp.pack.VersionTag)
+
+	tarball, err := rp.b.bucket.ReadAll(ctx, policyPackTarballPath(rp.pack.Name, rp.pack.VersionTag))
+	if err != nil {
+		return "", fmt.Errorf("could not read policy pack %s %s: %w", rp.pack.Name, rp.pack.VersionTag, err)
+	}
+
+	return policyPackPath, backend.InstallPolicyPack(ctx, policyPackPath, io.NopCloser(bytes.NewReader(tarball)))
+}
+
+// diyPolicyPackReference is a reference to a Policy Pack stored in a diy backend.
+type diyPolicyPackReference struct {
+	// name of the PolicyPack. This may be empty when publishing, in which case it is determined from the
+	// Policy Pack being published.
+	name tokens.QName
+}
+
+var _ backend.PolicyPackReference = (*diyPolicyPackReference)(nil)
+
+// parseDIYPolicyPackReference parses a reference of the form [organization/]<policy-pack-name>. Policy Pack names may
+// contain slashes, so only a leading "organization/" is taken to be the organization; anything else is part of the
+// name.
+func parseDIYPolicyPackReference(s string) (*diyPolicyPackReference, error) {
+	name := s
+	if org, rest, has := strings.Cut(s, "/"); has && (org == "" || org == diyPolicyOrg) {
+		name = rest
+	}
+	if name != "" && !tokens.IsQName(name) {
+		return nil, fmt.Errorf("invalid policy pack name %q", name)
+	}
+	return &diyPolicyPackReference{name: tokens.QName(name)}, nil
+}
+
+func (pr *diyPolicyPackReference) String() string {
+	return fmt.Sprintf("%s/%s", diyPolicyOrg, pr.name)
+}
+
+func (pr *diyPolicyPackReference) OrgName() string {
+	return diyPolicyOrg
+}
+
+func (pr *diyPolicyPackReference) Name() tokens.QName {
+	return pr.name
+}
+
+func (pr *diyPolicyPackReference) CloudConsoleURL() string {
+	return ""
+}
+
+// diyPolicyPack is the diy backend implementation of the PolicyPack interface.
+type diyPolicyPack struct {
+	ref *diyPolicyPackReference
+	b   *diyBackend
+}
+
+var _ backend.PolicyPack = (*diyPolicyPack)(nil)
+
+func (pack *diyPolicyPack) Ref() backend.PolicyPackReference {
+	return pack.ref
+}
+
+func (pack *diyPolicyPack) Backend() backend.Backend {
+	return pack.b
+}
+
+// policyPackVersionTagRE matches the version tags the Pulumi Cloud accepts, which are also safe to use in paths.
+var policyPackVersionTagRE = regexp.MustCompile("^[a-zA-Z0-9-_.]{1,100}$")
+
+func (pack *diyPolicyPack) Publish(ctx context.Context, op backend.PublishOperation) result.Result {
+	//
+	// Get PolicyPack metadata from the plugin.
+	//
+
+	fmt.Println("Obtaining policy metadata from policy plugin")
+
+	abs, err := filepath.Abs(op.PlugCtx.Pwd)
+	if err != nil {
+		return result.FromError(err)
+	}
+
+	analyzer, err := op.PlugCtx.Host.PolicyAnalyzer(tokens.QName(abs), op.PlugCtx.Pwd, nil /*opts*/)
+	if err != nil {
+		return result.FromError(err)
+	}
+
+	analyzerInfo, err := analyzer.GetAnalyzerInfo()
+	if err != nil {
+		return result.FromError(err)
+	}
+
+	if analyzerInfo.Version == "" {
+		return result.Errorf("policy pack %q does not have a version; upgrade the policy SDK it uses "+
+			"to publish it to a DIY backend", analyzerInfo.Name)
+	}
+	if !policyPackVersionTagRE.MatchString(analyzerInfo.Version) {
+		return result.Errorf("invalid version %q - version may only contain alphanumeric, hyphens, or underscores. "+
+			"It must also be between 1 and 100 characters long.", analyzerInfo.Version)
+	}
+	if org, _, has := strings.Cut(analyzerInfo.Name, "/"); has && org == diyPolicyOrg {
+		return result.Errorf("invalid policy pack name %q - names must not start with %q, which is read as the "+
+			"organization", analyzerInfo.Name, diyPolicyOrg+"/")
+	}
+	pack.ref.name = tokens.QName(analyzerInfo.Name)
+
+	versions, err := pack.b.getPolicyPackVersions(ctx, analyzerInfo.Name)
+	if err != nil {
+		return result.FromError(err)
+	}
+	for _, v := range versions {
+		if v.VersionTag == analyzerInfo.Version {
+			return result.Errorf("version %s of policy pack %q has already been published",
+				analyzerInfo.Version, analyzerInfo.Name)
+		}
+	}
+
+	policies := make([]apitype.Policy, len(analyzerInfo.Policies))
+	for i, policy := range analyzerInfo.Policies {
+		configSchema, err := convertPolicyConfigSchema(policy.ConfigSchema)
+		if err != nil {
+			return result.FromError(err)
+		}
+
+		policies[i] = apitype.Policy{
+			Name:             policy.Name,
+			DisplayName:      policy.DisplayName,
+			Description:      policy.Description,
+			EnforcementLevel: policy.EnforcementLevel,
+			Message:          policy.Message,
+			ConfigSchema:     configSchema,
+		}
+	}
+
+	fmt.Println("Compressing policy pack")
+
+	packTarball, err := backend.PackPolicyPack(ctx, op.PolicyPack, op.PlugCtx.Pwd)
+	if err != nil {
+		return result.FromError(err)
+	}
+
+	//
+	// Publish.
+	//
+
+	fmt.Printf("Publishing %q - version %s to %s\n", analyzerInfo.Name, analyzerInfo.Version, pack.b.url)
+
+	metadata := diyPolicyPackVersion{
+		CreatePolicyPackRequest: apitype.CreatePolicyPackRequest{
+			Name:        analyzerInfo.Name,
+			DisplayName: analyzerInfo.DisplayName,
+			VersionTag:  analyzerInfo.Version,
+			Policies:    policies,
+		},
+		Version:   nextPolicyPackVersion(versions),
+		Published: time.Now(),
+	}
+	metadataJSON, err := json.MarshalIndent(metadata, "", "    ")
+	if err != nil {
+		return result.FromError(fmt.Errorf("marshalling policy pack metadata: %w", err))
+	}
+
+	// Write the tarball first so that a version is never listed without its contents.
+	err = pack.b.bucket.WriteAll(ctx, policyPackTarballPath(analyzerInfo.Name, analyzerInfo.Version), packTarball, nil)
+	if err != nil {
+		return result.FromError(fmt.Errorf("could not write policy pack: %w", err))
+	}
+	err = pack.b.bucket.WriteAll(ctx, policyPackMetadataPath(analyzerInfo.Name, analyzerInfo.Version), metadataJSON, nil)
+	if err != nil {
+		return result.FromError(fmt.Errorf("could not write policy pack metadata: %w", err))
+	}
+
+	return nil
+}
+
+// resolveVersion returns the published version matching the given tag, or the latest version if the tag is nil.
+func (pack *diyPolicyPack) resolveVersion(ctx context.Context, versionTag *string) (*diyPolicyPackVersion, error) {
+	versions, err := pack.b.getPolicyPackVersions(ctx, string(pack.ref.name))
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("policy pack %q has not been published", pack.ref.name)
+	}
+	if versionTag == nil {
+		return versions[len(versions)-1], nil
+	}
+	for _, v := range versions {
+		if v.VersionTag == *versionTag {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("version %s of policy pack %q has not been published", *versionTag, pack.ref.name)
+}
+
+func (pack *diyPolicyPack) Enable(ctx context.Context, policyGroup string, op backend.PolicyPackOperation) error {
+	version, err := pack.resolveVersion(ctx, op.VersionTag)
+	if err != nil {
+		return err
+	}
+
+	groups, err := pack.b.getPolicyGroups(ctx)
+	if err != nil {
+		return err
+	}
+
+	var group *diyPolicyGroup
+	for _, g := range groups.Groups {
+		if (policyGroup == "" && g.IsOrgDefault) || (policyGroup != "" && g.Name == policyGroup) {
+			group = g
+			break
+		}
+	}
+	if group == nil {
+		group = &diyPolicyGroup{Name: policyGroup}
+		groups.Groups = append(groups.Groups, group)
+	}
+
+	// Only one version of a Policy Pack may be enabled for a group at a time.
+	enabled := diyEnabledPolicyPack{Name: version.Name, VersionTag: version.VersionTag, Config: op.Config}
+	replaced := false
+	for i, p := range group.PolicyPacks {
+		if p.Name == version.Name {
+			group.PolicyPacks[i] = enabled
+			replaced = true
+		}
+	}
+	if !replaced {
+		group.PolicyPacks = append(group.PolicyPacks, enabled)
+	}
+
+	return pack.b.savePolicyGroups(ctx, groups)
+}
+
+func (pack *diyPolicyPack) Disable(ctx context.Context, policyGroup string, op backend.PolicyPackOperation) error {
+	groups, err := pack.b.getPolicyGroups(ctx)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, g := range groups.Groups {
+		if (policyGroup == "" && !g.IsOrgDefault) || (policyGroup != "" && g.Name != policyGroup) {
+			continue
+		}
+		found = true
+		g.PolicyPacks = slices.DeleteFunc(g.PolicyPacks, func(p diyEnabledPolicyPack) bool {
+			return p.Name == string(pack.ref.name) && (op.VersionTag == nil || p.VersionTag == *op.VersionTag)
+		})
+	}
+	if !found {
+		return fmt.Errorf("policy group %q does not exist", policyGroup)
+	}
+
+	return pack.b.savePolicyGroups(ctx, groups)
+}
+
+func (pack *diyPolicyPack) Validate(ctx context.Context, op backend.PolicyPackOperation) error {
+	version, err := pack.resolveVersion(ctx, op.VersionTag)
+	if err != nil {
+		return err
+	}
+
+	schema := make(map[string]apitype.PolicyConfigSchema)
+	for _, policy := range version.Policies {
+		if policy.ConfigSchema != nil {
+			schema[policy.Name] = *policy.ConfigSchema
+		}
+	}
+	return resourceanalyzer.ValidatePolicyPackConfig(schema, op.Config)
+}
+
+func (pack *diyPolicyPack) Remove(ctx context.Context, op backend.PolicyPackOperation) error {
+	groups, err := pack.b.getPolicyGroups(ctx)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups.Groups {
+		for _, p := range g.PolicyPacks {
+			if p.Name == string(pack.ref.name) && (op.VersionTag == nil || p.VersionTag == *op.VersionTag) {
+				return fmt.Errorf("policy pack %q is enabled for policy group %q; disable it before removing it",
+					pack.ref.name, g.Name)
+			}
+		}
+	}
+
+	versions, err := pack.b.getPolicyPackVersions(ctx, string(pack.ref.name))
+	if err != nil {
+		return err
+	}
+	removed := false
+	for _, v := range versions {
+		if op.VersionTag != nil && v.VersionTag != *op.VersionTag {
+			continue
+		}
+		for _, key := range []string{
+			policyPackMetadataPath(v.Name, v.VersionTag),
+			policyPackTarballPath(v.Name, v.VersionTag),
+		} {
+			if err := pack.b.bucket.Delete(ctx, key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return fmt.Errorf("could not remove policy pack: %w", err)
+			}
+		}
+		removed = true
+	}
+	if !removed {
+		return errors.New("no matching policy pack versions to remove")
+	}
+	return nil
+}
+
+// convertPolicyConfigSchema converts a Policy's config schema to the form stored in the Policy Pack metadata,
+// which matches the form used by the Pulumi Cloud.
+func convertPolicyConfigSchema(schema *plugin.AnalyzerPolicyConfigSchema) (*apitype.PolicyConfigSchema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	properties := map[string]*json.RawMessage{}
+	for k, v := range schema.Properties {
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw := json.RawMessage(bytes)
+		properties[k] = &raw
+	}
+	return &apitype.PolicyConfigSchema{
+		Type:       apitype.Object,
+		Properties: properties,
+		Required:   schema.Required,
+	}, nil
+}
+
+// appendRequiredPolicies adds the Policy Packs enabled for the stack to the operation, so that they are enforced
+// alongside any Policy Packs passed with --policy-pack.
+func (b *diyBackend) appendRequiredPolicies(
+	ctx context.Context, ref *diyBackendReference, op *backend.UpdateOperation,
+) error {
+	contract.Requiref(op != nil, "op", "must not be nil")
+
+	policies, err := b.getRequiredPolicies(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("loading policy groups: %w", err)
+	}
+	op.Opts.Engine.RequiredPolicies = append(op.Opts.Engine.RequiredPolicies, policies...)
+	return nil
+}
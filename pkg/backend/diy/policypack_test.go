@@ -0,0 +1,238 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+// publishTestPolicyPack writes the metadata and tarball of a Policy Pack version as if it had been published.
+func publishTestPolicyPack(t *testing.T, b *diyBackend, name, versionTag string, version int) {
+	t.Helper()
+
+	ctx := context.Background()
+	metadata, err := json.Marshal(diyPolicyPackVersion{
+		CreatePolicyPackRequest: apitype.CreatePolicyPackRequest{
+			Name:       name,
+			VersionTag: versionTag,
+			Policies: []apitype.Policy{{
+				Name: "no-public-buckets",
+				ConfigSchema: &apitype.PolicyConfigSchema{
+					Type: apitype.Object,
+				},
+			}},
+		},
+		Version:   version,
+		Published: time.Now(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, b.bucket.WriteAll(ctx, policyPackMetadataPath(name, versionTag), metadata, nil))
+	require.NoError(t, b.bucket.WriteAll(ctx, policyPackTarballPath(name, versionTag), []byte("tgz"), nil))
+}
+
+func newPolicyTestBackend(t *testing.T) *diyBackend {
+	t.Helper()
+
+	b, err := newDIYBackend(context.Background(), diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()),
+		nil, nil)
+	require.NoError(t, err)
+	return b
+}
+
+func TestParseDIYPolicyPackReference(t *testing.T) {
+	t.Parallel()
+
+	ref, err := parseDIYPolicyPackReference("organization/security")
+	require.NoError(t, err)
+	assert.Equal(t, "security", string(ref.Name()))
+	assert.Equal(t, "organization", ref.OrgName())
+
+	ref, err = parseDIYPolicyPackReference("/")
+	require.NoError(t, err)
+	assert.Equal(t, "", string(ref.Name()))
+
+	// Names may contain slashes, with or without the organization in front of them.
+	ref, err = parseDIYPolicyPackReference("team/network/strict")
+	require.NoError(t, err)
+	assert.Equal(t, "team/network/strict", string(ref.Name()))
+	ref, err = parseDIYPolicyPackReference("organization/team/network/strict")
+	require.NoError(t, err)
+	assert.Equal(t, "team/network/strict", string(ref.Name()))
+}
+
+func TestNextPolicyPackVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := newPolicyTestBackend(t)
+	versions, err := b.getPolicyPackVersions(ctx, "security")
+	require.NoError(t, err)
+	assert.Equal(t, 1, nextPolicyPackVersion(versions))
+
+	publishTestPolicyPack(t, b, "security", "0.0.1", 1)
+	publishTestPolicyPack(t, b, "security", "0.0.2", 2)
+	publishTestPolicyPack(t, b, "security", "0.0.3", 3)
+	pack := &diyPolicyPack{ref: &diyPolicyPackReference{name: "security"}, b: b}
+	removed := "0.0.2"
+	require.NoError(t, pack.Remove(ctx, backend.PolicyPackOperation{VersionTag: &removed}))
+
+	// The number of a removed version isn't reused, so the next version is still the latest.
+	versions, err = b.getPolicyPackVersions(ctx, "security")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 4, nextPolicyPackVersion(versions))
+}
+
+func TestPolicyPackEnableDisable(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := newPolicyTestBackend(t)
+	publishTestPolicyPack(t, b, "security", "0.0.1", 1)
+	publishTestPolicyPack(t, b, "security", "0.0.2", 2)
+
+	packs, _, err := b.ListPolicyPacks(ctx, "organization", nil)
+	require.NoError(t, err)
+	require.Len(t, packs.PolicyPacks, 1)
+	assert.Equal(t, []string{"0.0.1", "0.0.2"}, packs.PolicyPacks[0].VersionTags)
+
+	pack, err := b.GetPolicyPack(ctx, "organization/security", nil)
+	require.NoError(t, err)
+
+	// Enabling without a version picks the latest.
+	require.NoError(t, pack.Enable(ctx, "", backend.PolicyPackOperation{}))
+
+	ref, err := b.parseStackReference("organization/project/dev")
+	require.NoError(t, err)
+	policies, err := b.getRequiredPolicies(ctx, ref)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	assert.Equal(t, "security", policies[0].Name())
+	assert.Equal(t, "0.0.2", policies[0].Version())
+
+	// A named group only applies to its stacks.
+	older := "0.0.1"
+	require.NoError(t, pack.Enable(ctx, "prod", backend.PolicyPackOperation{VersionTag: &older}))
+	prodRef, err := b.parseStackReference("organization/project/prod")
+	require.NoError(t, err)
+	require.NoError(t, b.AddStackToPolicyGroup(ctx, "organization", "prod", prodRef))
+	err = b.AddStackToPolicyGroup(ctx, "organization", "prod", prodRef)
+	assert.ErrorContains(t, err, `policy group "prod" already applies to stack organization/project/prod`)
+	err = b.AddStackToPolicyGroup(ctx, "organization", defaultPolicyGroup, prodRef)
+	assert.ErrorContains(t, err, "already applies to every stack")
+
+	policies, err = b.getRequiredPolicies(ctx, ref)
+	require.NoError(t, err)
+	assert.Len(t, policies, 1)
+
+	policies, err = b.getRequiredPolicies(ctx, prodRef)
+	require.NoError(t, err)
+	assert.Len(t, policies, 2)
+
+	summaries, _, err := b.ListPolicyGroups(ctx, "organization", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []apitype.PolicyGroupSummary{
+		{Name: defaultPolicyGroup, IsOrgDefault: true, NumEnabledPolicyPacks: 1},
+		{Name: "prod", NumStacks: 1, NumEnabledPolicyPacks: 1},
+	}, summaries.PolicyGroups)
+
+	// Removing the stack from the group stops its Policy Packs from applying to it.
+	require.NoError(t, b.RemoveStackFromPolicyGroup(ctx, "organization", "prod", prodRef))
+	policies, err = b.getRequiredPolicies(ctx, prodRef)
+	require.NoError(t, err)
+	assert.Len(t, policies, 1)
+	err = b.RemoveStackFromPolicyGroup(ctx, "organization", "prod", prodRef)
+	assert.ErrorContains(t, err, `policy group "prod" does not apply to stack organization/project/prod`)
+	err = b.RemoveStackFromPolicyGroup(ctx, "organization", "staging", prodRef)
+	assert.ErrorContains(t, err, `policy group "staging" does not exist`)
+
+	// Packs can't be removed while they're enabled.
+	err = pack.Remove(ctx, backend.PolicyPackOperation{})
+	assert.ErrorContains(t, err, "is enabled for policy group")
+
+	require.NoError(t, pack.Disable(ctx, "", backend.PolicyPackOperation{}))
+	require.NoError(t, pack.Disable(ctx, "prod", backend.PolicyPackOperation{}))
+	policies, err = b.getRequiredPolicies(ctx, prodRef)
+	require.NoError(t, err)
+	assert.Empty(t, policies)
+
+	require.NoError(t, pack.Remove(ctx, backend.PolicyPackOperation{}))
+	packs, _, err = b.ListPolicyPacks(ctx, "organization", nil)
+	require.NoError(t, err)
+	assert.Empty(t, packs.PolicyPacks)
+}
+
+func TestPolicyPackEnable_unpublished(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := newPolicyTestBackend(t)
+	publishTestPolicyPack(t, b, "security", "0.0.1", 1)
+
+	pack, err := b.GetPolicyPack(ctx, "organization/security", nil)
+	require.NoError(t, err)
+
+	missing := "1.0.0"
+	err = pack.Enable(ctx, "", backend.PolicyPackOperation{VersionTag: &missing})
+	assert.ErrorContains(t, err, "version 1.0.0 of policy pack \"security\" has not been published")
+
+	other, err := b.GetPolicyPack(ctx, "organization/other", nil)
+	require.NoError(t, err)
+	err = other.Enable(ctx, "", backend.PolicyPackOperation{})
+	assert.ErrorContains(t, err, "policy pack \"other\" has not been published")
+}
+
+func TestPolicyPackValidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := newPolicyTestBackend(t)
+	publishTestPolicyPack(t, b, "security", "0.0.1", 1)
+
+	pack, err := b.GetPolicyPack(ctx, "organization/security", nil)
+	require.NoError(t, err)
+
+	version := "0.0.1"
+	require.NoError(t, pack.Validate(ctx, backend.PolicyPackOperation{VersionTag: &version}))
+}
+
+func TestListPolicyPacks_nestedNames(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b := newPolicyTestBackend(t)
+	publishTestPolicyPack(t, b, "security", "0.0.1", 1)
+	publishTestPolicyPack(t, b, "security/aws", "1.0.0", 1)
+	publishTestPolicyPack(t, b, "team/network/strict", "2.0.0", 1)
+
+	packs, _, err := b.ListPolicyPacks(ctx, "organization", nil)
+	require.NoError(t, err)
+	var names []string
+	for _, p := range packs.PolicyPacks {
+		names = append(names, p.Name)
+	}
+	assert.ElementsMatch(t, []string{"security", "security/aws", "team/network/strict"}, names)
+}
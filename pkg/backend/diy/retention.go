@@ -0,0 +1,612 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// HistoryRetention describes which update history entries and checkpoint backups of a stack are kept.
+//
+// An entry is kept if it is one of the KeepLast most recent entries, or if it is newer than KeepFor.
+// A policy that sets neither keeps everything.
+type HistoryRetention struct {
+	// KeepLast is the number of most recent entries to keep.
+	KeepLast int `json:"keepLast,omitempty" yaml:"keepLast,omitempty"`
+	// KeepFor keeps all entries newer than this duration. It is a duration string such as "720h", as accepted by
+	// time.ParseDuration.
+	KeepFor string `json:"keepFor,omitempty" yaml:"keepFor,omitempty"`
+	// DeltaCheckpoints stores every retained history checkpoint except the most recent one
+	// as a delta against the checkpoint of the update that followed it.
+	DeltaCheckpoints bool `json:"deltaCheckpoints,omitempty" yaml:"deltaCheckpoints,omitempty"`
+}
+
+// Validate returns an error if the retention policy is invalid.
+func (r *HistoryRetention) Validate() error {
+	if r.KeepLast < 0 {
+		return errors.New("keepLast must not be negative")
+	}
+	if r.KeepFor != "" {
+		keepFor, err := time.ParseDuration(r.KeepFor)
+		if err != nil {
+			return fmt.Errorf("keepFor must be a duration such as '720h': %w", err)
+		}
+		if keepFor < 0 {
+			return errors.New("keepFor must not be negative")
+		}
+	}
+	return nil
+}
+
+// keepFor returns the parsed KeepFor duration, or zero if it isn't set. The policy must have been validated.
+func (r *HistoryRetention) keepFor() time.Duration {
+	if r.KeepFor == "" {
+		return 0
+	}
+	keepFor, err := time.ParseDuration(r.KeepFor)
+	contract.AssertNoErrorf(err, "retention policy must be validated")
+	return keepFor
+}
+
+// prunes returns true if this policy removes anything.
+func (r *HistoryRetention) prunes() bool {
+	return r.KeepLast > 0 || r.keepFor() > 0
+}
+
+// keep returns true if the entry at the given position, counting from the most recent, should be kept.
+func (r *HistoryRetention) keep(index int, timestamp time.Time, now time.Time) bool {
+	if !r.prunes() {
+		return true
+	}
+	keepFor := r.keepFor()
+	return (r.KeepLast > 0 && index < r.KeepLast) || (keepFor > 0 && now.Sub(timestamp) < keepFor)
+}
+
+// PruneHistoryResult summarizes the changes made by PruneHistory.
+type PruneHistoryResult struct {
+	// HistoryEntriesRemoved is the number of update history entries that were removed.
+	HistoryEntriesRemoved int
+	// BackupsRemoved is the number of checkpoint backups that were removed.
+	BackupsRemoved int
+	// CheckpointsCompacted is the number of history checkpoints that were rewritten as deltas.
+	CheckpointsCompacted int
+}
+
+// historyEntry is the set of files written to a stack's history directory for one update.
+type historyEntry struct {
+	// timestamp is the Unix time in nanoseconds that identifies the entry.
+	timestamp int64
+	// files are the keys of all the files that make up this entry.
+	files []string
+	// history is the key of the update's history file, if the entry has one.
+	history string
+	// checkpoint is the key of the full checkpoint, if the entry has one.
+	checkpoint string
+	// delta is the key of the delta checkpoint, if the entry has one.
+	delta string
+}
+
+const (
+	historySuffix    = ".history.json"
+	checkpointSuffix = ".checkpoint.json"
+	deltaSuffix      = ".checkpoint.delta.json"
+)
+
+// listHistoryEntries returns the entries in a stack's history directory, most recent first.
+func (b *diyBackend) listHistoryEntries(ctx context.Context, ref *diyBackendReference) ([]*historyEntry, error) {
+	allFiles, err := listBucket(ctx, b.bucket, ref.HistoryDir())
+	if err != nil {
+		// History doesn't exist until a stack has been updated.
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	prefix := ref.name.String() + "-"
+	entries := map[int64]*historyEntry{}
+	for _, file := range allFiles {
+		if file.IsDir {
+			continue
+		}
+
+		// The filename format is <stack-name>-<timestamp>.<kind>.json[.gz].
+		name := objectName(file)
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(name, prefix)
+		dot := strings.Index(rest, ".")
+		if dot == -1 {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(rest[:dot], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		entry, has := entries[timestamp]
+		if !has {
+			entry = &historyEntry{timestamp: timestamp}
+			entries[timestamp] = entry
+		}
+		entry.files = append(entry.files, file.Key)
+
+		kind := strings.TrimSuffix(rest[dot:], encoding.GZIPExt)
+		switch kind {
+		case historySuffix:
+			entry.history = file.Key
+		case checkpointSuffix:
+			entry.checkpoint = file.Key
+		case deltaSuffix:
+			entry.delta = file.Key
+		}
+	}
+
+	result := make([]*historyEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].timestamp > result[j].timestamp })
+	return result, nil
+}
+
+// readHistoryEntryInfo reads the update information saved with a history entry, which must have a history file.
+func (b *diyBackend) readHistoryEntryInfo(ctx context.Context, entry *historyEntry) (backend.UpdateInfo, error) {
+	var update backend.UpdateInfo
+	byts, err := b.bucket.ReadAll(ctx, entry.history)
+	if err != nil {
+		return update, fmt.Errorf("reading history file %s: %w", entry.history, err)
+	}
+	m := encoding.JSON
+	if encoding.IsCompressed(byts) {
+		m = encoding.Gzip(m)
+	}
+	if err := m.Unmarshal(byts, &update); err != nil {
+		return update, fmt.Errorf("reading history file %s: %w", entry.history, err)
+	}
+	return update, nil
+}
+
+// findHistoryVersion returns the history entry of the given version of a stack. entries must be ordered most recent
+// first. Each entry records its version when it is written; entries written by older versions of the CLI don't,
+// and are numbered by their position instead, as they were when they were written.
+func (b *diyBackend) findHistoryVersion(
+	ctx context.Context, entries []*historyEntry, version int,
+) (*historyEntry, error) {
+	var updates []*historyEntry
+	for _, entry := range entries {
+		if entry.history != "" {
+			updates = append(updates, entry)
+		}
+	}
+	for i, entry := range updates {
+		update, err := b.readHistoryEntryInfo(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		v := update.Version
+		if v == 0 {
+			v = len(updates) - i
+		}
+		if v == version {
+			return entry, nil
+		}
+		if v < version {
+			// Versions only increase, so there's no point looking at older entries.
+			break
+		}
+	}
+	return nil, nil
+}
+
+// nextHistoryVersion returns the version to record for the next update of a stack: one more than the version of its
+// most recent history entry.
+func (b *diyBackend) nextHistoryVersion(ctx context.Context, ref *diyBackendReference) (int, error) {
+	entries, err := b.listHistoryEntries(ctx, ref)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, entry := range entries {
+		if entry.history != "" {
+			count++
+		}
+	}
+	for _, entry := range entries {
+		if entry.history == "" {
+			continue
+		}
+		update, err := b.readHistoryEntryInfo(ctx, entry)
+		if err != nil {
+			return 0, err
+		}
+		if update.Version == 0 {
+			// Written by an older CLI, which numbered entries by position.
+			return count + 1, nil
+		}
+		return update.Version + 1, nil
+	}
+	return 1, nil
+}
+
+// recordHistoryVersions writes the version of each of the given history entries into its history file, for entries
+// written by older versions of the CLI that didn't record it. This must be done before any entries are removed,
+// as those entries are numbered by their position. entries must be ordered most recent first.
+func (b *diyBackend) recordHistoryVersions(ctx context.Context, entries []*historyEntry) error {
+	var updates []*historyEntry
+	for _, entry := range entries {
+		if entry.history != "" {
+			updates = append(updates, entry)
+		}
+	}
+	// Start from the oldest entry, which is version 1.
+	for i := len(updates) - 1; i >= 0; i-- {
+		entry := updates[i]
+		update, err := b.readHistoryEntryInfo(ctx, entry)
+		if err != nil {
+			return err
+		}
+		if update.Version != 0 {
+			// Every entry after the first that records its version also records it.
+			return nil
+		}
+		update.Version = len(updates) - i
+
+		m := encoding.JSON
+		if strings.HasSuffix(entry.history, encoding.GZIPExt) {
+			m = encoding.Gzip(m)
+		}
+		byts, err := m.Marshal(&update)
+		if err != nil {
+			return err
+		}
+		if err := b.bucket.WriteAll(ctx, entry.history, byts, nil); err != nil {
+			return fmt.Errorf("writing history file %s: %w", entry.history, err)
+		}
+	}
+	return nil
+}
+
+// backupTimestamp extracts the Unix time in nanoseconds from the name of a checkpoint backup,
+// which has the format <stack-name>.<timestamp>.json[.gz].
+func backupTimestamp(stackName, fileName string) (int64, bool) {
+	rest, ok := strings.CutPrefix(fileName, stackName+".")
+	if !ok {
+		return 0, false
+	}
+	timestamp, _, _ := strings.Cut(rest, ".")
+	t, err := strconv.ParseInt(timestamp, 10, 64)
+	return t, err == nil
+}
+
+// PruneHistory removes the update history entries and checkpoint backups of a stack that aren't kept by the given
+// retention policy, and optionally compacts the remaining history checkpoints into deltas. If retention is nil, the
+// policy configured in the backend's metadata is used.
+func (b *diyBackend) PruneHistory(
+	ctx context.Context, stackRef backend.StackReference, retention *HistoryRetention,
+) (*PruneHistoryResult, error) {
+	if retention == nil {
+		retention = b.retention
+	}
+	if retention == nil {
+		return nil, fmt.Errorf("no retention policy is configured in %s", pulumiMetaPath)
+	}
+	if err := retention.Validate(); err != nil {
+		return nil, err
+	}
+
+	ref, err := b.getReference(stackRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.pruneHistory(ctx, ref, retention, time.Now())
+}
+
+func (b *diyBackend) pruneHistory(
+	ctx context.Context, ref *diyBackendReference, retention *HistoryRetention, now time.Time,
+) (*PruneHistoryResult, error) {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	var result PruneHistoryResult
+
+	entries, err := b.listHistoryEntries(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("listing history: %w", err)
+	}
+
+	if retention.prunes() {
+		if err := b.recordHistoryVersions(ctx, entries); err != nil {
+			return nil, err
+		}
+	}
+
+	var kept []*historyEntry
+	for i, entry := range entries {
+		if retention.keep(i, time.Unix(0, entry.timestamp), now) {
+			kept = append(kept, entry)
+			continue
+		}
+
+		for _, file := range entry.files {
+			if err := b.bucket.Delete(ctx, file); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return nil, fmt.Errorf("deleting history file: %w", err)
+			}
+		}
+		result.HistoryEntriesRemoved++
+	}
+
+	if retention.DeltaCheckpoints {
+		compacted, err := b.compactHistoryCheckpoints(ctx, ref, kept)
+		if err != nil {
+			return nil, err
+		}
+		result.CheckpointsCompacted = compacted
+	}
+
+	backups, err := listBucket(ctx, b.bucket, ref.BackupDir())
+	if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return nil, fmt.Errorf("listing backups: %w", err)
+	}
+	type backupFile struct {
+		key       string
+		timestamp int64
+	}
+	var backupFiles []backupFile
+	for _, file := range backups {
+		if file.IsDir {
+			continue
+		}
+		if timestamp, ok := backupTimestamp(ref.name.String(), objectName(file)); ok {
+			backupFiles = append(backupFiles, backupFile{key: file.Key, timestamp: timestamp})
+		}
+	}
+	sort.Slice(backupFiles, func(i, j int) bool { return backupFiles[i].timestamp > backupFiles[j].timestamp })
+	for i, file := range backupFiles {
+		if retention.keep(i, time.Unix(0, file.timestamp), now) {
+			continue
+		}
+		if err := b.bucket.Delete(ctx, file.key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+			return nil, fmt.Errorf("deleting backup: %w", err)
+		}
+		result.BackupsRemoved++
+	}
+
+	logging.V(7).Infof("Pruned history of stack %s: %+v", ref.FullyQualifiedName(), result)
+	return &result, nil
+}
+
+// checkpointDelta is the contents of a history checkpoint stored as a delta against the checkpoint of the update
+// that followed it.
+type checkpointDelta struct {
+	// Successor is the timestamp of the history entry this delta is relative to.
+	Successor int64 `json:"successor"`
+	// Checkpoint is the checkpoint without its resources.
+	Checkpoint apitype.CheckpointV3 `json:"checkpoint"`
+	// Resources lists the checkpoint's resources in order.
+	Resources []checkpointDeltaResource `json:"resources,omitempty"`
+}
+
+// checkpointDeltaResource is a resource in a delta checkpoint. Exactly one of Same and Resource is set.
+type checkpointDeltaResource struct {
+	// Same is the index of an identical resource in the successor's checkpoint.
+	Same *int `json:"same,omitempty"`
+	// Resource is the resource, if it doesn't appear in the successor's checkpoint.
+	Resource *apitype.ResourceV3 `json:"resource,omitempty"`
+}
+
+// compactHistoryCheckpoints rewrites every full history checkpoint except the most recent one as a delta against
+// its successor. entries must be ordered most recent first. Checkpoints are compacted from the most recent, so once
+// an entry that is already a delta is reached, the older entries have been compacted by an earlier call and are left
+// alone; this keeps the cost of each call down to the entries written since the last one.
+func (b *diyBackend) compactHistoryCheckpoints(
+	ctx context.Context, ref *diyBackendReference, entries []*historyEntry,
+) (int, error) {
+	compacted := 0
+	var successor *historyEntry
+	var successorCheckpoint *apitype.CheckpointV3
+	for _, entry := range entries {
+		if entry.checkpoint == "" {
+			if entry.delta != "" {
+				break
+			}
+			continue
+		}
+
+		chk, err := b.readHistoryEntryCheckpoint(ctx, entries, entry)
+		if err != nil {
+			return compacted, err
+		}
+
+		if successor != nil {
+			delta, err := newCheckpointDelta(successor.timestamp, chk, successorCheckpoint)
+			if err != nil {
+				return compacted, err
+			}
+
+			m, ext := encoding.JSON, ""
+			if b.gzip {
+				m, ext = encoding.Gzip(m), encoding.GZIPExt
+			}
+			byts, err := m.Marshal(delta)
+			if err != nil {
+				return compacted, fmt.Errorf("marshalling checkpoint delta: %w", err)
+			}
+
+			deltaFile := path.Join(ref.HistoryDir(), fmt.Sprintf("%s-%d%s%s", ref.name, entry.timestamp, deltaSuffix, ext))
+			if err := b.bucket.WriteAll(ctx, deltaFile, byts, nil); err != nil {
+				return compacted, fmt.Errorf("writing checkpoint delta: %w", err)
+			}
+			if err := b.bucket.Delete(ctx, entry.checkpoint); err != nil {
+				return compacted, fmt.Errorf("deleting history checkpoint: %w", err)
+			}
+			entry.delta, entry.checkpoint = deltaFile, ""
+			compacted++
+		}
+
+		successor, successorCheckpoint = entry, chk
+	}
+	return compacted, nil
+}
+
+// newCheckpointDelta computes the delta of chk against the checkpoint of its successor.
+func newCheckpointDelta(
+	successor int64, chk, successorCheckpoint *apitype.CheckpointV3,
+) (*checkpointDelta, error) {
+	index := map[string]int{}
+	if successorCheckpoint.Latest != nil {
+		for i, res := range successorCheckpoint.Latest.Resources {
+			key, err := json.Marshal(res)
+			if err != nil {
+				return nil, err
+			}
+			index[string(key)] = i
+		}
+	}
+
+	delta := &checkpointDelta{Successor: successor, Checkpoint: *chk}
+	if chk.Latest != nil {
+		latest := *chk.Latest
+		latest.Resources = nil
+		delta.Checkpoint.Latest = &latest
+
+		for i := range chk.Latest.Resources {
+			res := &chk.Latest.Resources[i]
+			key, err := json.Marshal(res)
+			if err != nil {
+				return nil, err
+			}
+			if same, has := index[string(key)]; has {
+				delta.Resources = append(delta.Resources, checkpointDeltaResource{Same: &same})
+			} else {
+				delta.Resources = append(delta.Resources, checkpointDeltaResource{Resource: res})
+			}
+		}
+	}
+	return delta, nil
+}
+
+// readHistoryEntryCheckpoint reads the checkpoint saved with a history entry, resolving deltas as needed. entries
+// are all of the stack's history entries, as returned by listHistoryEntries.
+func (b *diyBackend) readHistoryEntryCheckpoint(
+	ctx context.Context, entries []*historyEntry, entry *historyEntry,
+) (*apitype.CheckpointV3, error) {
+	byTimestamp := make(map[int64]*historyEntry, len(entries))
+	for _, e := range entries {
+		byTimestamp[e.timestamp] = e
+	}
+
+	// Follow the chain of deltas forward to the first entry with a full checkpoint.
+	type link struct {
+		entry *historyEntry
+		delta *checkpointDelta
+	}
+	var chain []link
+	for entry.checkpoint == "" {
+		if entry.delta == "" {
+			return nil, fmt.Errorf("history entry %d has no checkpoint", entry.timestamp)
+		}
+		delta, err := b.readCheckpointDelta(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+		successor, has := byTimestamp[delta.Successor]
+		if !has || successor.timestamp <= entry.timestamp {
+			return nil, fmt.Errorf("history checkpoint %s refers to missing update %d", entry.delta, delta.Successor)
+		}
+		chain = append(chain, link{entry: entry, delta: delta})
+		entry = successor
+	}
+
+	byts, err := b.bucket.ReadAll(ctx, entry.checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("reading history checkpoint %s: %w", entry.checkpoint, err)
+	}
+	m := encoding.JSON
+	if encoding.IsCompressed(byts) {
+		m = encoding.Gzip(m)
+	}
+	chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(m, byts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Then apply the deltas back to the entry we were asked for.
+	for i := len(chain) - 1; i >= 0; i-- {
+		chk, err = applyCheckpointDelta(chain[i].entry, chain[i].delta, chk)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return chk, nil
+}
+
+// readCheckpointDelta reads the delta checkpoint of a history entry.
+func (b *diyBackend) readCheckpointDelta(ctx context.Context, entry *historyEntry) (*checkpointDelta, error) {
+	byts, err := b.bucket.ReadAll(ctx, entry.delta)
+	if err != nil {
+		return nil, fmt.Errorf("reading history checkpoint %s: %w", entry.delta, err)
+	}
+	m := encoding.JSON
+	if encoding.IsCompressed(byts) {
+		m = encoding.Gzip(m)
+	}
+	var delta checkpointDelta
+	if err := m.Unmarshal(byts, &delta); err != nil {
+		return nil, fmt.Errorf("reading history checkpoint %s: %w", entry.delta, err)
+	}
+	return &delta, nil
+}
+
+// applyCheckpointDelta rebuilds the checkpoint of a history entry from its delta and the checkpoint of its successor.
+func applyCheckpointDelta(
+	entry *historyEntry, delta *checkpointDelta, successorCheckpoint *apitype.CheckpointV3,
+) (*apitype.CheckpointV3, error) {
+	chk := delta.Checkpoint
+	if chk.Latest != nil {
+		latest := *chk.Latest
+		for _, r := range delta.Resources {
+			switch {
+			case r.Resource != nil:
+				latest.Resources = append(latest.Resources, *r.Resource)
+			case r.Same != nil && successorCheckpoint.Latest != nil &&
+				*r.Same >= 0 && *r.Same < len(successorCheckpoint.Latest.Resources):
+				latest.Resources = append(latest.Resources, successorCheckpoint.Latest.Resources[*r.Same])
+			default:
+				return nil, fmt.Errorf("history checkpoint %s is corrupt", entry.delta)
+			}
+		}
+		chk.Latest = &latest
+	}
+	return &chk, nil
+}
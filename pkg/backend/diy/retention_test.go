@@ -0,0 +1,350 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+// writeTestHistoryEntry writes a history entry, checkpoint and backup for the stack at the given time.
+func writeTestHistoryEntry(
+	t *testing.T, b *diyBackend, ref *diyBackendReference, at time.Time, resources ...apitype.ResourceV3,
+) {
+	t.Helper()
+
+	ctx := context.Background()
+	chk, err := json.Marshal(apitype.VersionedCheckpoint{
+		Version: 3,
+		Checkpoint: mustMarshalJSON(t, apitype.CheckpointV3{
+			Stack:  ref.FullyQualifiedName(),
+			Latest: &apitype.DeploymentV3{Resources: resources},
+		}),
+	})
+	require.NoError(t, err)
+
+	prefix := path.Join(ref.HistoryDir(), fmt.Sprintf("%s-%d", ref.name, at.UnixNano()))
+	require.NoError(t, b.bucket.WriteAll(ctx, prefix+".history.json", []byte(`{"kind":"update"}`), nil))
+	require.NoError(t, b.bucket.WriteAll(ctx, prefix+".checkpoint.json", chk, nil))
+	backup := path.Join(ref.BackupDir(), fmt.Sprintf("%s.%d.json", ref.name, at.UnixNano()))
+	require.NoError(t, b.bucket.WriteAll(ctx, backup, chk, nil))
+}
+
+func mustMarshalJSON(t *testing.T, v interface{}) json.RawMessage {
+	t.Helper()
+
+	bytes, err := json.Marshal(v)
+	require.NoError(t, err)
+	return bytes
+}
+
+func newRetentionTestBackend(t *testing.T) (*diyBackend, *diyBackendReference) {
+	t.Helper()
+
+	ctx := context.Background()
+	b, err := newDIYBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()), nil, nil)
+	require.NoError(t, err)
+	ref, err := b.parseStackReference("organization/project/dev")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+	return b, ref
+}
+
+func TestHistoryRetention_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&HistoryRetention{}).Validate())
+	assert.NoError(t, (&HistoryRetention{KeepLast: 3, KeepFor: "1h"}).Validate())
+	assert.ErrorContains(t, (&HistoryRetention{KeepLast: -1}).Validate(), "keepLast must not be negative")
+	assert.ErrorContains(t, (&HistoryRetention{KeepFor: "-1h"}).Validate(), "keepFor must not be negative")
+	assert.ErrorContains(t, (&HistoryRetention{KeepFor: "30"}).Validate(), "keepFor must be a duration")
+}
+
+func TestPruneHistory(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tests := []struct {
+		desc      string
+		retention HistoryRetention
+		wantKept  int
+	}{
+		{desc: "keep everything", retention: HistoryRetention{}, wantKept: 5},
+		{desc: "keep last", retention: HistoryRetention{KeepLast: 2}, wantKept: 2},
+		{desc: "keep for", retention: HistoryRetention{KeepFor: "150m"}, wantKept: 3},
+		{desc: "keep either", retention: HistoryRetention{KeepLast: 4, KeepFor: "1h"}, wantKept: 4},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			b, ref := newRetentionTestBackend(t)
+			// Entries are 0, 1, 2, 3, and 4 hours old.
+			for i := 0; i < 5; i++ {
+				writeTestHistoryEntry(t, b, ref, now.Add(-time.Duration(i)*time.Hour))
+			}
+
+			res, err := b.pruneHistory(ctx, ref, &tt.retention, now)
+			require.NoError(t, err)
+			assert.Equal(t, 5-tt.wantKept, res.HistoryEntriesRemoved)
+			assert.Equal(t, 5-tt.wantKept, res.BackupsRemoved)
+
+			updates, err := b.GetHistory(ctx, ref, 0, 0)
+			require.NoError(t, err)
+			require.Len(t, updates, tt.wantKept)
+			// The updates that are kept keep their version numbers.
+			for i, update := range updates {
+				assert.Equal(t, 5-i, update.Version)
+			}
+
+			entries, err := b.listHistoryEntries(ctx, ref)
+			require.NoError(t, err)
+			require.Len(t, entries, tt.wantKept)
+			// The most recent entries are the ones that are kept.
+			assert.Equal(t, now.UnixNano(), entries[0].timestamp)
+		})
+	}
+}
+
+func TestPruneHistory_deltas(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newRetentionTestBackend(t)
+
+	a := apitype.ResourceV3{URN: resource.URN("urn:pulumi:dev::project::a:b:c::a"), Type: "a:b:c"}
+	bRes := apitype.ResourceV3{URN: resource.URN("urn:pulumi:dev::project::a:b:c::b"), Type: "a:b:c"}
+	bChanged := bRes
+	bChanged.Outputs = map[string]interface{}{"x": "y"}
+
+	now := time.Now()
+	writeTestHistoryEntry(t, b, ref, now.Add(-2*time.Hour), a)
+	writeTestHistoryEntry(t, b, ref, now.Add(-time.Hour), a, bRes)
+	writeTestHistoryEntry(t, b, ref, now, a, bChanged)
+
+	before, err := b.listHistoryEntries(ctx, ref)
+	require.NoError(t, err)
+	var want []*apitype.CheckpointV3
+	for _, entry := range before {
+		chk, err := b.readHistoryEntryCheckpoint(ctx, before, entry)
+		require.NoError(t, err)
+		want = append(want, chk)
+	}
+
+	res, err := b.pruneHistory(ctx, ref, &HistoryRetention{DeltaCheckpoints: true}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.HistoryEntriesRemoved)
+	assert.Equal(t, 2, res.CheckpointsCompacted)
+
+	after, err := b.listHistoryEntries(ctx, ref)
+	require.NoError(t, err)
+	require.Len(t, after, 3)
+	assert.NotEmpty(t, after[0].checkpoint, "the most recent checkpoint should be kept in full")
+	for i, entry := range after {
+		if i > 0 {
+			assert.Empty(t, entry.checkpoint)
+			assert.NotEmpty(t, entry.delta)
+		}
+		chk, err := b.readHistoryEntryCheckpoint(ctx, after, entry)
+		require.NoError(t, err)
+		assert.Equal(t, want[i], chk)
+	}
+
+	// Compacting again is a no-op.
+	res, err = b.pruneHistory(ctx, ref, &HistoryRetention{DeltaCheckpoints: true}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.CheckpointsCompacted)
+
+	// Pruning removes the oldest entries first, so a delta never outlives the entry it refers to.
+	res, err = b.pruneHistory(ctx, ref, &HistoryRetention{KeepLast: 2}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.HistoryEntriesRemoved)
+	after, err = b.listHistoryEntries(ctx, ref)
+	require.NoError(t, err)
+	chk, err := b.readHistoryEntryCheckpoint(ctx, after, after[1])
+	require.NoError(t, err)
+	assert.Equal(t, want[1], chk)
+
+	// Compacting after a new update only rewrites the previous checkpoint, without reading the older deltas.
+	require.NoError(t, b.bucket.WriteAll(ctx, after[1].delta, []byte("not a delta"), nil))
+	writeTestHistoryEntry(t, b, ref, now.Add(time.Hour), a)
+	res, err = b.pruneHistory(ctx, ref, &HistoryRetention{DeltaCheckpoints: true}, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.CheckpointsCompacted)
+	after, err = b.listHistoryEntries(ctx, ref)
+	require.NoError(t, err)
+	require.Len(t, after, 3)
+	chk, err = b.readHistoryEntryCheckpoint(ctx, after, after[1])
+	require.NoError(t, err)
+	assert.Equal(t, want[0], chk)
+}
+
+func TestPruneHistory_configured(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newRetentionTestBackend(t)
+
+	_, err := b.PruneHistory(ctx, ref, nil)
+	assert.ErrorContains(t, err, "no retention policy is configured")
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		writeTestHistoryEntry(t, b, ref, now.Add(-time.Duration(i)*time.Minute))
+	}
+
+	b.retention = &HistoryRetention{KeepLast: 1}
+	res, err := b.PruneHistory(ctx, ref, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &PruneHistoryResult{HistoryEntriesRemoved: 2, BackupsRemoved: 2}, res)
+}
+
+// Tests that the retention policy survives upgrading the store.
+func TestPruneHistory_upgrade(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stateDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(stateDir, ".pulumi"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, ".pulumi", "meta.yaml"),
+		[]byte("version: 0\nretention:\n  keepLast: 1\n  keepFor: 24h\n"), 0o600))
+
+	b, err := newDIYBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(stateDir), nil, nil)
+	require.NoError(t, err)
+	want := &HistoryRetention{KeepLast: 1, KeepFor: "24h"}
+	assert.Equal(t, want, b.retention)
+
+	require.NoError(t, b.Upgrade(ctx, &UpgradeOptions{}))
+	got, err := readPulumiMeta(ctx, b.bucket)
+	require.NoError(t, err)
+	assert.Equal(t, &pulumiMeta{Version: 1, Retention: want}, got)
+}
+
+func TestExportDeploymentForVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newRetentionTestBackend(t)
+
+	// The existing updates happened before the one added below.
+	now := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		res := apitype.ResourceV3{URN: resource.URN(fmt.Sprintf("urn:pulumi:dev::project::a:b:c::v%d", i+1)), Type: "a:b:c"}
+		writeTestHistoryEntry(t, b, ref, now.Add(time.Duration(i)*time.Minute), res)
+	}
+
+	// Updates are numbered from the oldest.
+	updates, err := b.GetHistory(ctx, ref, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, updates, 3)
+	assert.Equal(t, 3, updates[0].Version)
+	assert.Equal(t, 1, updates[2].Version)
+
+	stk, err := b.GetStack(ctx, ref)
+	require.NoError(t, err)
+	dep, err := b.ExportDeploymentForVersion(ctx, stk, "2")
+	require.NoError(t, err)
+	var deployment apitype.DeploymentV3
+	require.NoError(t, json.Unmarshal(dep.Deployment, &deployment))
+	require.Len(t, deployment.Resources, 1)
+	assert.Equal(t, "v2", deployment.Resources[0].URN.Name())
+
+	_, err = b.ExportDeploymentForVersion(ctx, stk, "4")
+	assert.ErrorContains(t, err, "does not exist")
+	_, err = b.ExportDeploymentForVersion(ctx, stk, "latest")
+	assert.ErrorContains(t, err, "invalid version")
+
+	// Versions keep their numbers when older updates are pruned.
+	_, err = b.pruneHistory(ctx, ref, &HistoryRetention{KeepLast: 2}, now)
+	require.NoError(t, err)
+	updates, err = b.GetHistory(ctx, ref, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	assert.Equal(t, 3, updates[0].Version)
+	assert.Equal(t, 2, updates[1].Version)
+	dep, err = b.ExportDeploymentForVersion(ctx, stk, "2")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(dep.Deployment, &deployment))
+	assert.Equal(t, "v2", deployment.Resources[0].URN.Name())
+	_, err = b.ExportDeploymentForVersion(ctx, stk, "1")
+	assert.ErrorContains(t, err, "does not exist")
+
+	// And new updates carry on from the last version.
+	require.NoError(t, b.addToHistory(ctx, ref, backend.UpdateInfo{Kind: apitype.UpdateUpdate}))
+	updates, err = b.GetHistory(ctx, ref, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, updates, 3)
+	assert.Equal(t, 4, updates[0].Version)
+}
+
+func TestRestoreDeployment(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, ref := newRetentionTestBackend(t)
+
+	// The existing updates happened before the restore.
+	now := time.Now().Add(-time.Hour)
+	for i := 0; i < 2; i++ {
+		res := apitype.ResourceV3{URN: resource.URN(fmt.Sprintf("urn:pulumi:dev::project::a:b:c::v%d", i+1)), Type: "a:b:c"}
+		writeTestHistoryEntry(t, b, ref, now.Add(time.Duration(i)*time.Minute), res)
+	}
+
+	stk, err := b.GetStack(ctx, ref)
+	require.NoError(t, err)
+	dep, err := b.ExportDeploymentForVersion(ctx, stk, "1")
+	require.NoError(t, err)
+	require.NoError(t, b.RestoreDeployment(ctx, stk, dep, "Restored version 1"))
+
+	// The restore is recorded as a new version holding the restored state.
+	updates, err := b.GetHistory(ctx, ref, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, updates, 3)
+	assert.Equal(t, 3, updates[0].Version)
+	assert.Equal(t, apitype.StackImportUpdate, updates[0].Kind)
+	assert.Equal(t, "Restored version 1", updates[0].Message)
+
+	for _, version := range []string{"", "3"} {
+		var exported *apitype.UntypedDeployment
+		if version == "" {
+			exported, err = b.ExportDeployment(ctx, stk)
+		} else {
+			exported, err = b.ExportDeploymentForVersion(ctx, stk, version)
+		}
+		require.NoError(t, err)
+		var deployment apitype.DeploymentV3
+		require.NoError(t, json.Unmarshal(exported.Deployment, &deployment))
+		require.Len(t, deployment.Resources, 1)
+		assert.Equal(t, "v1", deployment.Resources[0].URN.Name())
+	}
+}
@@ -43,14 +43,17 @@ type diyStack struct {
 	snapshot atomic.Pointer[*deploy.Snapshot]
 	// a pointer to the backend this stack belongs to.
 	b *diyBackend
+	// the stack's tags.
+	tags map[apitype.StackTagName]string
 }
 
-func newStack(ref *diyBackendReference, b *diyBackend) backend.Stack {
+func newStack(ref *diyBackendReference, b *diyBackend, tags map[apitype.StackTagName]string) backend.Stack {
 	contract.Requiref(ref != nil, "ref", "ref was nil")
 
 	return &diyStack{
-		ref: ref,
-		b:   b,
+		ref:  ref,
+		b:    b,
+		tags: tags,
 	}
 }
 
@@ -69,7 +72,7 @@ func (s *diyStack) Snapshot(ctx context.Context, secretsProvider secrets.Provide
 	return snap, nil
 }
 func (s *diyStack) Backend() backend.Backend              { return s.b }
-func (s *diyStack) Tags() map[apitype.StackTagName]string { return nil }
+func (s *diyStack) Tags() map[apitype.StackTagName]string { return s.tags }
 
 func (s *diyStack) Remove(ctx context.Context, force bool) (bool, error) {
 	return backend.RemoveStack(ctx, s, force)
@@ -96,11 +99,15 @@ func (s *diyStack) Import(ctx context.Context, op backend.UpdateOperation,
 	return backend.ImportStack(ctx, s, op, imports)
 }
 
-func (s *diyStack) Refresh(ctx context.Context, op backend.UpdateOperation) (display.ResourceChanges, result.Result) {
+func (s *diyStack) Refresh(
+	ctx context.Context, op backend.UpdateOperation,
+) (*deploy.Plan, display.ResourceChanges, result.Result) {
 	return backend.RefreshStack(ctx, s, op)
 }
 
-func (s *diyStack) Destroy(ctx context.Context, op backend.UpdateOperation) (display.ResourceChanges, result.Result) {
+func (s *diyStack) Destroy(
+	ctx context.Context, op backend.UpdateOperation,
+) (*deploy.Plan, display.ResourceChanges, result.Result) {
 	return backend.DestroyStack(ctx, s, op)
 }
 
@@ -197,7 +197,13 @@ func (b *diyBackend) getCheckpoint(ctx context.Context, ref *diyBackendReference
 		m = encoding.Gzip(m)
 	}
 
-	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(m, bytes)
+	chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(m, bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// If an update was interrupted, its progress is in the journal rather than the checkpoint.
+	return b.replayJournal(ctx, ref, chk)
 }
 
 func (b *diyBackend) saveCheckpoint(
@@ -328,6 +334,14 @@ func (b *diyBackend) removeStack(ctx context.Context, ref *diyBackendReference)
 	file := b.stackPath(ctx, ref)
 	backupTarget(ctx, b.bucket, file, false)
 
+	if err := b.removeStackTags(ctx, ref); err != nil {
+		return err
+	}
+
+	if err := b.removeJournal(ctx, ref); err != nil {
+		return err
+	}
+
 	historyDir := ref.HistoryDir()
 	return removeAllByPrefix(ctx, b.bucket, historyDir)
 }
@@ -501,6 +515,11 @@ func (b *diyBackend) getHistory(
 		if err != nil {
 			return nil, fmt.Errorf("reading history file %s: %w", filepath, err)
 		}
+		if update.Version == 0 {
+			// Entries written by older versions of the CLI don't record their version, and are numbered by position
+			// from the oldest, which is version 1.
+			update.Version = len(historyEntries) - i
+		}
 
 		updates = append(updates, update)
 	}
@@ -557,6 +576,14 @@ func (b *diyBackend) addToHistory(ctx context.Context, ref *diyBackendReference,
 
 	dir := ref.HistoryDir()
 
+	if update.Version == 0 {
+		version, err := b.nextHistoryVersion(ctx, ref)
+		if err != nil {
+			return err
+		}
+		update.Version = version
+	}
+
 	// Prefix for the update and checkpoint files.
 	pathPrefix := path.Join(dir, fmt.Sprintf("%s-%d", ref.name, time.Now().UnixNano()))
 
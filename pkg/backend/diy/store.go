@@ -46,6 +46,10 @@ var (
 	// BackupsDir is a path under the state's root directory
 	// where the diy backend stores backups of stacks.
 	BackupsDir = filepath.Join(workspace.BookkeepingDir, workspace.BackupDir)
+
+	// JournalsDir is a path under the state's root directory
+	// where the diy backend stores the update journals of stacks.
+	JournalsDir = filepath.Join(workspace.BookkeepingDir, "journals")
 )
 
 // referenceStore stores and provides access to stack information.
@@ -75,6 +79,19 @@ type referenceStore interface {
 	// This must be under BackupsDir.
 	BackupDir(*diyBackendReference) string
 
+	// JournalDir returns the path to the directory
+	// where the journal of an in-progress update to this stack is stored.
+	//
+	// This must be under JournalsDir.
+	JournalDir(*diyBackendReference) string
+
+	// TagsPath returns the path to the file
+	// where tags for this stack are stored.
+	//
+	// This is stored next to the stack's checkpoint
+	// with an extension that isn't recognized as a stack file.
+	TagsPath(*diyBackendReference) string
+
 	// ListReferences lists all stack references in the store.
 	ListReferences(context.Context) ([]*diyBackendReference, error)
 
@@ -133,6 +150,15 @@ func (p *projectReferenceStore) BackupDir(stack *diyBackendReference) string {
 	return filepath.Join(BackupsDir, fsutil.NamePath(stack.project), stack.name.String())
 }
 
+func (p *projectReferenceStore) JournalDir(stack *diyBackendReference) string {
+	contract.Requiref(stack.project != "", "ref.project", "must not be empty")
+	return filepath.Join(JournalsDir, fsutil.NamePath(stack.project), stack.name.String())
+}
+
+func (p *projectReferenceStore) TagsPath(stack *diyBackendReference) string {
+	return p.StackBasePath(stack) + tagsExt
+}
+
 func (p *projectReferenceStore) ParseReference(stackRef string) (*diyBackendReference, error) {
 	// We accept the following forms:
 	//
@@ -354,6 +380,15 @@ func (p *legacyReferenceStore) BackupDir(stack *diyBackendReference) string {
 	return filepath.Join(BackupsDir, stack.name.String())
 }
 
+func (p *legacyReferenceStore) JournalDir(stack *diyBackendReference) string {
+	contract.Requiref(stack.project == "", "ref.project", "must be empty")
+	return filepath.Join(JournalsDir, stack.name.String())
+}
+
+func (p *legacyReferenceStore) TagsPath(stack *diyBackendReference) string {
+	return p.StackBasePath(stack) + tagsExt
+}
+
 func (p *legacyReferenceStore) ParseReference(stackRef string) (*diyBackendReference, error) {
 	parsedName, err := tokens.ParseStackName(stackRef)
 	if err != nil {
@@ -0,0 +1,117 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// tagsExt is the extension of the file that holds a stack's tags.
+//
+// This deliberately isn't one of the extensions in encoding.Marshalers
+// so that the file is never mistaken for a stack checkpoint.
+const tagsExt = ".tags"
+
+// getStackTags reads the tags for the given stack. A stack without any saved tags has no tags.
+func (b *diyBackend) getStackTags(
+	ctx context.Context, ref *diyBackendReference,
+) (map[apitype.StackTagName]string, error) {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	tagsPath := filepath.ToSlash(ref.TagsPath())
+	data, err := b.bucket.ReadAll(ctx, tagsPath)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return map[apitype.StackTagName]string{}, nil
+		}
+		return nil, fmt.Errorf("could not read stack tags: %w", err)
+	}
+
+	var tags map[apitype.StackTagName]string
+	if err := encoding.JSON.Unmarshal(data, &tags); err != nil {
+		return nil, fmt.Errorf("could not parse stack tags %s: %w", tagsPath, err)
+	}
+	if tags == nil {
+		tags = map[apitype.StackTagName]string{}
+	}
+	return tags, nil
+}
+
+// saveStackTags replaces the tags for the given stack.
+func (b *diyBackend) saveStackTags(
+	ctx context.Context, ref *diyBackendReference, tags map[apitype.StackTagName]string,
+) error {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	if tags == nil {
+		tags = map[apitype.StackTagName]string{}
+	}
+	data, err := encoding.JSON.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("marshalling stack tags: %w", err)
+	}
+	if err := b.bucket.WriteAll(ctx, filepath.ToSlash(ref.TagsPath()), data, nil); err != nil {
+		return fmt.Errorf("could not write stack tags: %w", err)
+	}
+	return nil
+}
+
+// removeStackTags deletes the tags for the given stack, if any.
+func (b *diyBackend) removeStackTags(ctx context.Context, ref *diyBackendReference) error {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	err := b.bucket.Delete(ctx, filepath.ToSlash(ref.TagsPath()))
+	if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return fmt.Errorf("could not delete stack tags: %w", err)
+	}
+	return nil
+}
+
+// renameStackTags moves the tags of a stack to its new name.
+func (b *diyBackend) renameStackTags(ctx context.Context, oldRef, newRef *diyBackendReference) error {
+	tags, err := b.getStackTags(ctx, oldRef)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	if err := b.saveStackTags(ctx, newRef, tags); err != nil {
+		return err
+	}
+	return b.removeStackTags(ctx, oldRef)
+}
+
+// matchesTagFilter returns true if the given tags satisfy the tag constraints of the filter.
+func matchesTagFilter(filter backend.ListStacksFilter, tags map[apitype.StackTagName]string) bool {
+	if filter.TagName == nil {
+		return true
+	}
+
+	value, has := tags[*filter.TagName]
+	if !has {
+		return false
+	}
+	return filter.TagValue == nil || value == *filter.TagValue
+}
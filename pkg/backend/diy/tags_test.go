@@ -0,0 +1,113 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func TestStackTags(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()), nil)
+	require.NoError(t, err)
+	assert.True(t, b.SupportsTags())
+
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	s, err := b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+	assert.Empty(t, s.Tags())
+
+	tags := map[apitype.StackTagName]string{"owner": "platform", "env": "prod"}
+	require.NoError(t, backend.UpdateStackTags(ctx, s, tags))
+	assert.Equal(t, tags, s.Tags())
+
+	// The tags should be persisted in the bucket.
+	s, err = b.GetStack(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, tags, s.Tags())
+
+	// The tags file must not be mistaken for a stack.
+	stacks, _, err := b.ListStacks(ctx, backend.ListStacksFilter{}, nil)
+	require.NoError(t, err)
+	assert.Len(t, stacks, 1)
+
+	// Renaming the stack moves its tags.
+	newRef, err := b.RenameStack(ctx, s, tokens.QName("organization/project/b"))
+	require.NoError(t, err)
+	s, err = b.GetStack(ctx, newRef)
+	require.NoError(t, err)
+	assert.Equal(t, tags, s.Tags())
+
+	// Removing the stack removes its tags.
+	_, err = b.RemoveStack(ctx, s, false)
+	require.NoError(t, err)
+	s, err = b.CreateStack(ctx, newRef, "", nil)
+	require.NoError(t, err)
+	s, err = b.GetStack(ctx, s.Ref())
+	require.NoError(t, err)
+	assert.Empty(t, s.Tags())
+}
+
+func TestListStacksTagFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()), nil)
+	require.NoError(t, err)
+
+	for name, tags := range map[string]map[apitype.StackTagName]string{
+		"organization/proj/a": {"env": "prod"},
+		"organization/proj/b": {"env": "dev"},
+		"organization/proj/c": {},
+	} {
+		ref, err := b.ParseStackReference(name)
+		require.NoError(t, err)
+		s, err := b.CreateStack(ctx, ref, "", nil)
+		require.NoError(t, err)
+		require.NoError(t, b.UpdateStackTags(ctx, s, tags))
+	}
+
+	listNames := func(filter backend.ListStacksFilter) []string {
+		stacks, token, err := b.ListStacks(ctx, filter, nil /* inContToken */)
+		require.NoError(t, err)
+		assert.Nil(t, token)
+		var names []string
+		for _, s := range stacks {
+			names = append(names, s.Name().String())
+		}
+		return names
+	}
+
+	tagName, tagValue := "env", "prod"
+	assert.ElementsMatch(t,
+		[]string{"organization/proj/a", "organization/proj/b"},
+		listNames(backend.ListStacksFilter{TagName: &tagName}))
+	assert.ElementsMatch(t,
+		[]string{"organization/proj/a"},
+		listNames(backend.ListStacksFilter{TagName: &tagName, TagValue: &tagValue}))
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
 	"github.com/pulumi/pulumi/pkg/v3/backend/httpstate/client"
+	"github.com/pulumi/pulumi/pkg/v3/backend/sqlstate"
 	sdkDisplay "github.com/pulumi/pulumi/pkg/v3/display"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/operations"
@@ -152,9 +153,9 @@ func ValueOrDefaultURL(cloudURL string) string {
 	}
 
 	// If that didn't work, see if we have a current cloud, and use that. Note we need to be careful
-	// to ignore the diy cloud.
+	// to ignore the diy and SQL backends.
 	if creds, err := workspace.GetStoredCredentials(); err == nil {
-		if creds.Current != "" && !diy.IsDIYBackendURL(creds.Current) {
+		if creds.Current != "" && !diy.IsDIYBackendURL(creds.Current) && !sqlstate.IsSQLBackendURL(creds.Current) {
 			return creds.Current
 		}
 	}
@@ -196,8 +197,11 @@ type cloudBackend struct {
 	currentProject *workspace.Project
 }
 
-// Assert we implement the backend.Backend and backend.SpecificDeploymentExporter interfaces.
-var _ backend.SpecificDeploymentExporter = &cloudBackend{}
+// Assert we implement the backend.SpecificDeploymentExporter and backend.DeploymentRestorer interfaces.
+var (
+	_ backend.SpecificDeploymentExporter = &cloudBackend{}
+	_ backend.DeploymentRestorer         = &cloudBackend{}
+)
 
 // New creates a new Pulumi backend for the given cloud API URL and token.
 func New(d diag.Sink, cloudURL string, project *workspace.Project, insecure bool) (Backend, error) {
@@ -1116,7 +1120,7 @@ func (b *cloudBackend) Import(ctx context.Context, stack backend.Stack,
 
 func (b *cloudBackend) Refresh(ctx context.Context, stack backend.Stack,
 	op backend.UpdateOperation,
-) (sdkDisplay.ResourceChanges, result.Result) {
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	if op.Opts.PreviewOnly {
 		// We can skip PreviewThenPromptThenExecute, and just go straight to Execute.
 		opts := backend.ApplierOptions{
@@ -1124,17 +1128,15 @@ func (b *cloudBackend) Refresh(ctx context.Context, stack backend.Stack,
 			ShowLink: true,
 		}
 
-		op.Opts.Engine.GeneratePlan = false
-		_, changes, res := b.apply(
-			ctx, apitype.RefreshUpdate, stack, op, opts, nil /*events*/)
-		return changes, res
+		return b.apply(ctx, apitype.RefreshUpdate, stack, op, opts, nil /*events*/)
 	}
-	return backend.PreviewThenPromptThenExecute(ctx, apitype.RefreshUpdate, stack, op, b.apply)
+	changes, res := backend.PreviewThenPromptThenExecute(ctx, apitype.RefreshUpdate, stack, op, b.apply)
+	return nil, changes, res
 }
 
 func (b *cloudBackend) Destroy(ctx context.Context, stack backend.Stack,
 	op backend.UpdateOperation,
-) (sdkDisplay.ResourceChanges, result.Result) {
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	if op.Opts.PreviewOnly {
 		// We can skip PreviewThenPromptThenExecute, and just go straight to Execute.
 		opts := backend.ApplierOptions{
@@ -1142,12 +1144,10 @@ func (b *cloudBackend) Destroy(ctx context.Context, stack backend.Stack,
 			ShowLink: true,
 		}
 
-		op.Opts.Engine.GeneratePlan = false
-		_, changes, res := b.apply(
-			ctx, apitype.DestroyUpdate, stack, op, opts, nil /*events*/)
-		return changes, res
+		return b.apply(ctx, apitype.DestroyUpdate, stack, op, opts, nil /*events*/)
 	}
-	return backend.PreviewThenPromptThenExecute(ctx, apitype.DestroyUpdate, stack, op, b.apply)
+	changes, res := backend.PreviewThenPromptThenExecute(ctx, apitype.DestroyUpdate, stack, op, b.apply)
+	return nil, changes, res
 }
 
 func (b *cloudBackend) Watch(ctx context.Context, stk backend.Stack,
@@ -1400,9 +1400,9 @@ func (b *cloudBackend) runEngineAction(
 	case apitype.ResourceImportUpdate:
 		_, changes, updateErr = engine.Import(u, engineCtx, op.Opts.Engine, op.Imports, dryRun)
 	case apitype.RefreshUpdate:
-		_, changes, updateErr = engine.Refresh(u, engineCtx, op.Opts.Engine, dryRun)
+		plan, changes, updateErr = engine.Refresh(u, engineCtx, op.Opts.Engine, dryRun)
 	case apitype.DestroyUpdate:
-		_, changes, updateErr = engine.Destroy(u, engineCtx, op.Opts.Engine, dryRun)
+		plan, changes, updateErr = engine.Destroy(u, engineCtx, op.Opts.Engine, dryRun)
 	case apitype.StackImportUpdate, apitype.RenameUpdate:
 		contract.Failf("unexpected %s event", kind)
 	default:
@@ -1644,6 +1644,19 @@ func (b *cloudBackend) ImportDeployment(ctx context.Context, stack backend.Stack
 	return nil
 }
 
+// RestoreDeployment imports a deployment into the backend. The service records every import as an update in the
+// stack's history, but its import API has no way to attach a message to the update, so the user is told that the
+// message won't be recorded rather than it being dropped silently.
+func (b *cloudBackend) RestoreDeployment(ctx context.Context, stack backend.Stack,
+	deployment *apitype.UntypedDeployment, message string,
+) error {
+	if message != "" {
+		b.d.Warningf(diag.Message("" /*urn*/, "Pulumi Cloud records this change in the stack's history as an "+
+			"import, but can't record its message %q"), message)
+	}
+	return b.ImportDeployment(ctx, stack, deployment)
+}
+
 var projectNameCleanRegexp = regexp.MustCompile("[^a-zA-Z0-9-_.]")
 
 // cleanProjectName replaces undesirable characters in project names with hyphens. At some point, these restrictions
@@ -186,15 +186,15 @@ func (s *cloudStack) Import(ctx context.Context, op backend.UpdateOperation,
 	return backend.ImportStack(ctx, s, op, imports)
 }
 
-func (s *cloudStack) Refresh(ctx context.Context, op backend.UpdateOperation) (sdkDisplay.ResourceChanges,
-	result.Result,
-) {
+func (s *cloudStack) Refresh(
+	ctx context.Context, op backend.UpdateOperation,
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	return backend.RefreshStack(ctx, s, op)
 }
 
-func (s *cloudStack) Destroy(ctx context.Context, op backend.UpdateOperation) (sdkDisplay.ResourceChanges,
-	result.Result,
-) {
+func (s *cloudStack) Destroy(
+	ctx context.Context, op backend.UpdateOperation,
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	return backend.DestroyStack(ctx, s, op)
 }
 
@@ -69,9 +69,9 @@ type MockBackend struct {
 	ImportF func(context.Context, Stack,
 		UpdateOperation, []deploy.Import) (sdkDisplay.ResourceChanges, result.Result)
 	RefreshF func(context.Context, Stack,
-		UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
+		UpdateOperation) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result)
 	DestroyF func(context.Context, Stack,
-		UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
+		UpdateOperation) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result)
 	WatchF func(context.Context, Stack,
 		UpdateOperation, []string) result.Result
 	GetLogsF func(context.Context, secrets.Provider, Stack, StackConfiguration,
@@ -270,7 +270,7 @@ func (be *MockBackend) Import(ctx context.Context, stack Stack,
 
 func (be *MockBackend) Refresh(ctx context.Context, stack Stack,
 	op UpdateOperation,
-) (sdkDisplay.ResourceChanges, result.Result) {
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	if be.RefreshF != nil {
 		return be.RefreshF(ctx, stack, op)
 	}
@@ -279,7 +279,7 @@ func (be *MockBackend) Refresh(ctx context.Context, stack Stack,
 
 func (be *MockBackend) Destroy(ctx context.Context, stack Stack,
 	op UpdateOperation,
-) (sdkDisplay.ResourceChanges, result.Result) {
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	if be.DestroyF != nil {
 		return be.DestroyF(ctx, stack, op)
 	}
@@ -450,8 +450,8 @@ type MockStack struct {
 	UpdateF   func(ctx context.Context, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
 	ImportF   func(ctx context.Context, op UpdateOperation,
 		imports []deploy.Import) (sdkDisplay.ResourceChanges, result.Result)
-	RefreshF func(ctx context.Context, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
-	DestroyF func(ctx context.Context, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
+	RefreshF func(ctx context.Context, op UpdateOperation) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result)
+	DestroyF func(ctx context.Context, op UpdateOperation) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result)
 	WatchF   func(ctx context.Context, op UpdateOperation, paths []string) result.Result
 	QueryF   func(ctx context.Context, op UpdateOperation) result.Result
 	RemoveF  func(ctx context.Context, force bool) (bool, error)
@@ -533,14 +533,18 @@ func (ms *MockStack) Import(ctx context.Context, op UpdateOperation,
 	panic("not implemented")
 }
 
-func (ms *MockStack) Refresh(ctx context.Context, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result) {
+func (ms *MockStack) Refresh(
+	ctx context.Context, op UpdateOperation,
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	if ms.RefreshF != nil {
 		return ms.RefreshF(ctx, op)
 	}
 	panic("not implemented")
 }
 
-func (ms *MockStack) Destroy(ctx context.Context, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result) {
+func (ms *MockStack) Destroy(
+	ctx context.Context, op UpdateOperation,
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
 	if ms.DestroyF != nil {
 		return ms.DestroyF(ctx, op)
 	}
@@ -0,0 +1,598 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/version"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// SnapshotRecovery is the result of rebuilding a snapshot from the events of a deployment.
+type SnapshotRecovery struct {
+	// Snapshot is the recovered snapshot.
+	Snapshot *deploy.Snapshot
+	// Applied is the number of completed resource operations that were applied to the base snapshot.
+	Applied int
+	// Pending are the resource operations that had started but not finished when the event log ended. They are
+	// recorded as pending operations in the recovered snapshot, with the exception of refreshes and sames, which do
+	// not have pending operations of their own.
+	Pending []resource.Operation
+	// Incomplete are the resource operations that finished, but whose results could not be recovered, because the event
+	// log redacted input values that the base snapshot does not have, as it does for the secret inputs of a resource
+	// that the deployment created, or because they refer to a resource whose result could not be recovered. They are
+	// recorded as pending operations in the recovered snapshot instead of being applied, and the resources that they
+	// act on keep their state in the base snapshot, if any.
+	Incomplete []resource.Operation
+	// Redacted are the resources whose recovered state contained secrets, assets or archives. The values of these are
+	// redacted in event logs, so they are taken from the base snapshot, and may be out of date.
+	Redacted []resource.URN
+	// Dropped are the properties whose values were redacted in the event log and that the base snapshot does not have,
+	// for instance the secret outputs of a resource that the deployment created. They are left out of the recovered
+	// snapshot.
+	Dropped []DroppedProperty
+}
+
+// DroppedProperty is a top-level property that was left out of the recovered state of a resource.
+type DroppedProperty struct {
+	URN resource.URN
+	Key resource.PropertyKey
+}
+
+// RecoverSnapshot rebuilds the snapshot that a deployment would have written from the events it emitted, for
+// instance when writing the final checkpoint of the deployment failed. The events of the deployment's completed
+// resource operations are applied to the base snapshot, which should be the last checkpoint that was written before
+// or during the deployment, in the same way the SnapshotManager would have applied their steps, and operations that
+// started but did not finish are recorded as pending operations.
+//
+// Event logs do not record everything about a resource: resources recovered from them keep the dependencies and other
+// options that they have in the base snapshot, or have none if they are new, and operations on default providers are
+// not logged at all. The recovered snapshot is not verified; callers should do so before using it.
+func RecoverSnapshot(base *deploy.Snapshot, events []apitype.EngineEvent) (*SnapshotRecovery, error) {
+	r := &snapshotRecoverer{
+		base:      base,
+		olds:      make(map[resource.URN][]*resource.State),
+		dones:     make(map[*resource.State]bool),
+		completed: make(map[resource.URN]bool),
+		missing:   make(map[resource.URN]bool),
+		kept:      make(map[resource.URN]bool),
+		redacted:  make(map[resource.URN]bool),
+		dropped:   make(map[resource.URN]map[resource.PropertyKey]bool),
+	}
+	if base != nil {
+		for _, res := range base.Resources {
+			r.olds[res.URN] = append(r.olds[res.URN], res)
+		}
+	}
+
+	for _, e := range events {
+		var err error
+		switch {
+		case e.ResourcePreEvent != nil:
+			if e.ResourcePreEvent.Planning {
+				return nil, errors.New("the event log was recorded by a preview, which does not change a stack's state")
+			}
+			err = r.begin(e.ResourcePreEvent.Metadata)
+		case e.ResOutputsEvent != nil:
+			err = r.end(e.ResOutputsEvent.Metadata, true)
+		case e.ResOpFailedEvent != nil:
+			err = r.end(e.ResOpFailedEvent.Metadata, resource.Status(e.ResOpFailedEvent.Status) ==
+				resource.StatusPartialFailure)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", e.Sequence, err)
+		}
+	}
+
+	return r.snap()
+}
+
+// snapshotRecoverer tracks the state of a snapshot recovery. Like the SnapshotManager, it keeps the resources of the
+// base snapshot that have been replaced or deleted separately from the new resources produced by the deployment, and
+// merges the two once all of the events have been applied.
+type snapshotRecoverer struct {
+	base *deploy.Snapshot
+	// olds are the resources of the base snapshot, by URN.
+	olds map[resource.URN][]*resource.State
+	// dones are the resources of the base snapshot that have been replaced or deleted.
+	dones map[*resource.State]bool
+	// news are the resources produced by the deployment, in the order their operations finished.
+	news []*resource.State
+	// inFlight are the operations that have started but not yet finished.
+	inFlight []apitype.StepEventMetadata
+	// completed records the resources that operations finished on.
+	completed map[resource.URN]bool
+	// incomplete are the operations that finished but could not be recovered. missing records the resources that the
+	// recovered snapshot does not have because of them, and kept the resources whose replaced state it keeps instead.
+	incomplete []resource.Operation
+	missing    map[resource.URN]bool
+	kept       map[resource.URN]bool
+	applied    int
+	redacted   map[resource.URN]bool
+	dropped    map[resource.URN]map[resource.PropertyKey]bool
+}
+
+// begin records the start of an operation. Operations on component resources do no work of their own, so they are
+// applied straight away, except for deletes, whose end is also logged.
+func (r *snapshotRecoverer) begin(md apitype.StepEventMetadata) error {
+	switch op := display.StepOp(md.Op); {
+	case isLogicalOp(op):
+		return nil
+	case op == deploy.OpDelete:
+	default:
+		if res := eventResource(md); res != nil && !res.Custom {
+			return r.apply(md)
+		}
+	}
+	r.inFlight = append(r.inFlight, md)
+	return nil
+}
+
+// end records the end of an operation, applying it if it was successful.
+func (r *snapshotRecoverer) end(md apitype.StepEventMetadata, successful bool) error {
+	op := display.StepOp(md.Op)
+	if isLogicalOp(op) {
+		return nil
+	}
+	for i, started := range r.inFlight {
+		if started.URN != md.URN {
+			continue
+		}
+		// Refreshes are logged as the operation they turned out to be when they end.
+		refresh := display.StepOp(started.Op) == deploy.OpRefresh
+		if !refresh && display.StepOp(started.Op) != op {
+			continue
+		}
+
+		r.inFlight = append(r.inFlight[:i], r.inFlight[i+1:]...)
+		if !successful {
+			return nil
+		}
+		if refresh {
+			return r.refresh(md)
+		}
+		return r.apply(md)
+	}
+
+	// The outputs of a resource whose operation has already been applied can be updated by the program. The root stack
+	// resource always reports its outputs in this way at the end of a deployment.
+	for i := len(r.news) - 1; i >= 0; i-- {
+		if string(r.news[i].URN) == md.URN && md.New != nil {
+			outputs, dropped, err := r.properties(r.news[i].URN, md.New.Outputs, r.news[i].Outputs)
+			if err != nil {
+				return err
+			}
+			r.drop(r.news[i].URN, dropped)
+			r.news[i].Outputs = outputs
+			return nil
+		}
+	}
+
+	// Otherwise the start of the operation is missing from the log, which can only happen if the log is incomplete.
+	// The end of the operation is enough to apply it.
+	if !successful {
+		return nil
+	}
+	return r.apply(md)
+}
+
+// apply applies a completed operation.
+func (r *snapshotRecoverer) apply(md apitype.StepEventMetadata) error {
+	op, urn := display.StepOp(md.Op), resource.URN(md.URN)
+	r.completed[urn] = true
+
+	switch op {
+	case deploy.OpDelete, deploy.OpDeleteReplaced, deploy.OpReadDiscard, deploy.OpDiscardReplaced:
+		if op == deploy.OpDeleteReplaced && r.kept[urn] {
+			// The replacement could not be recovered, so keep the state that it replaces for the resources that refer
+			// to it.
+			return nil
+		}
+		r.applied++
+		deleted := md.Old != nil && md.Old.Delete
+		// If the resource is not in the base snapshot, it was deleted before the base snapshot was written.
+		if old := r.old(urn, deleted); old != nil {
+			r.dones[old] = true
+		}
+		return nil
+	case deploy.OpSame, deploy.OpCreate, deploy.OpCreateReplacement, deploy.OpUpdate, deploy.OpImport,
+		deploy.OpImportReplacement, deploy.OpRead, deploy.OpReadReplacement:
+		if md.New == nil {
+			return fmt.Errorf("%v operation on %v has no new state", op, urn)
+		}
+		old := r.old(urn, false)
+		redacted := r.redacted[urn]
+		new, droppedInputs, droppedOutputs, err := r.state(md.New, old)
+		if err != nil {
+			return err
+		}
+		if len(droppedInputs) > 0 || r.refersToMissing(new) {
+			// Applying the operation would leave inputs out of the resource's state, or refer to a resource that the
+			// recovered snapshot does not have, so record it as a pending operation for the user to resolve instead.
+			if redacted {
+				r.redacted[urn] = true
+			} else {
+				delete(r.redacted, urn)
+			}
+			r.incompleteOperation(op, new, old)
+			return nil
+		}
+		r.applied++
+		r.drop(urn, droppedOutputs)
+
+		replacement := op == deploy.OpCreateReplacement || op == deploy.OpImportReplacement
+		switch {
+		case old == nil:
+		case replacement && old.ID != new.ID && !old.PendingReplacement:
+			// The resource that this replaces is deleted by a later operation. Mark it as such, as the engine does.
+			old.Delete = true
+		default:
+			// The base snapshot either has the state that this operation replaces, or already has the result of this
+			// operation, because it was written after the operation finished.
+			r.dones[old] = true
+		}
+		r.news = append(r.news, new)
+		return nil
+	default:
+		return fmt.Errorf("unexpected %v operation on %v", op, urn)
+	}
+}
+
+// refresh applies a completed refresh, which updates the state of the refreshed resource in place. Refreshes do not
+// run in dependency order, so moving the refreshed resource to the end of the snapshot would not be valid.
+func (r *snapshotRecoverer) refresh(md apitype.StepEventMetadata) error {
+	urn := resource.URN(md.URN)
+	r.applied++
+	r.completed[urn] = true
+
+	old := r.old(urn, md.Old != nil && md.Old.Delete)
+	if old == nil {
+		return nil
+	}
+	if md.New == nil {
+		r.dones[old] = true
+		return nil
+	}
+	new, droppedInputs, droppedOutputs, err := r.state(md.New, old)
+	if err != nil {
+		return err
+	}
+	r.drop(urn, droppedInputs)
+	r.drop(urn, droppedOutputs)
+	*old = *new
+	return nil
+}
+
+// incompleteOperation records a completed operation that could not be recovered as a pending operation. The resource
+// that it acts on keeps its state in the base snapshot, if any, and is missing from the recovered snapshot otherwise.
+func (r *snapshotRecoverer) incompleteOperation(op display.StepOp, new, old *resource.State) {
+	switch {
+	case op == deploy.OpCreateReplacement || op == deploy.OpImportReplacement:
+		r.kept[new.URN] = true
+	case old == nil:
+		r.missing[new.URN] = true
+	}
+	// A same does not change the resource, so keeping its state in the base snapshot is enough.
+	if typ, ok := pendingOperationType(op); ok {
+		r.incomplete = append(r.incomplete, resource.NewOperation(new, typ))
+	}
+}
+
+// refersToMissing returns true if the given state refers to a resource that is missing from the recovered snapshot.
+func (r *snapshotRecoverer) refersToMissing(state *resource.State) bool {
+	if len(r.missing) == 0 {
+		return false
+	}
+	if r.missing[state.Parent] || r.missing[state.DeletedWith] {
+		return true
+	}
+	if state.Provider != "" {
+		if ref, err := providers.ParseReference(state.Provider); err == nil && r.missing[ref.URN()] {
+			return true
+		}
+	}
+	for _, dep := range state.Dependencies {
+		if r.missing[dep] {
+			return true
+		}
+	}
+	for _, deps := range state.PropertyDependencies {
+		for _, dep := range deps {
+			if r.missing[dep] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// old returns the resource in the base snapshot with the given URN that has not been replaced or deleted, preferring
+// one whose Delete flag matches the given value.
+func (r *snapshotRecoverer) old(urn resource.URN, deleted bool) *resource.State {
+	var fallback *resource.State
+	for _, res := range r.olds[urn] {
+		if r.dones[res] {
+			continue
+		}
+		if res.Delete == deleted {
+			return res
+		}
+		if fallback == nil {
+			fallback = res
+		}
+	}
+	return fallback
+}
+
+// state reconstructs the state of a resource from its event metadata. The fields that events do not record are taken
+// from the given state of the resource in the base snapshot, if any. It also returns the inputs and outputs that were
+// left out of the state because their values were redacted.
+func (r *snapshotRecoverer) state(
+	md *apitype.StepEventStateMetadata, old *resource.State,
+) (*resource.State, []resource.PropertyKey, []resource.PropertyKey, error) {
+	urn := resource.URN(md.URN)
+	state := &resource.State{}
+	var oldInputs, oldOutputs resource.PropertyMap
+	if old != nil {
+		*state = *old
+		state.PendingReplacement = false
+		oldInputs, oldOutputs = old.Inputs, old.Outputs
+	}
+
+	inputs, droppedInputs, err := r.properties(urn, md.Inputs, oldInputs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	outputs, droppedOutputs, err := r.properties(urn, md.Outputs, oldOutputs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	state.Type = tokens.Type(md.Type)
+	state.URN = urn
+	state.Custom = md.Custom
+	state.Delete = md.Delete
+	state.ID = resource.ID(md.ID)
+	state.Parent = resource.URN(md.Parent)
+	state.Protect = md.Protect
+	state.RetainOnDelete = md.RetainOnDelete
+	state.Provider = md.Provider
+	state.InitErrors = md.InitErrors
+	state.Inputs, state.Outputs = inputs, outputs
+	return state, droppedInputs, droppedOutputs, nil
+}
+
+// properties deserializes the properties of a resource from its event metadata. Top-level properties whose values
+// were redacted are taken from the given properties of the resource in the base snapshot, or dropped and returned if it
+// does not have them, so that the placeholders of redacted values never end up in the recovered state.
+func (r *snapshotRecoverer) properties(
+	urn resource.URN, props map[string]interface{}, old resource.PropertyMap,
+) (resource.PropertyMap, []resource.PropertyKey, error) {
+	m, err := stack.DeserializeProperties(props, redactedDecrypter{}, config.NopEncrypter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%v: %w", urn, err)
+	}
+	var dropped []resource.PropertyKey
+	for k, v := range m {
+		if !isRedacted(v) {
+			continue
+		}
+		if oldValue, has := old[k]; has {
+			m[k] = oldValue
+			r.redacted[urn] = true
+			continue
+		}
+		delete(m, k)
+		dropped = append(dropped, k)
+	}
+	return m, dropped, nil
+}
+
+// drop records the properties that were left out of the recovered state of a resource.
+func (r *snapshotRecoverer) drop(urn resource.URN, keys []resource.PropertyKey) {
+	for _, k := range keys {
+		if r.dropped[urn] == nil {
+			r.dropped[urn] = make(map[resource.PropertyKey]bool)
+		}
+		r.dropped[urn][k] = true
+	}
+}
+
+// snap merges the new resources with the resources of the base snapshot that were not replaced or deleted, in the
+// same way as the SnapshotManager, and records the operations that are still in flight or could not be recovered as
+// pending operations.
+func (r *snapshotRecoverer) snap() (*SnapshotRecovery, error) {
+	resources := append([]*resource.State{}, r.news...)
+	var operations []resource.Operation
+	if r.base != nil {
+		for _, res := range r.base.Resources {
+			if !r.dones[res] {
+				resources = append(resources, res)
+			}
+		}
+		// Pending creates from the base snapshot need to be resolved by the user, unless the log shows that they
+		// finished.
+		for _, op := range r.base.PendingOperations {
+			if op.Type == resource.OperationTypeCreating && !r.completed[op.Resource.URN] {
+				operations = append(operations, op)
+			}
+		}
+	}
+
+	var pending []resource.Operation
+	for _, md := range r.inFlight {
+		op, err := r.pendingOperation(md)
+		if err != nil {
+			return nil, err
+		}
+		if op != nil {
+			pending = append(pending, *op)
+		}
+	}
+	operations = append(operations, r.incomplete...)
+	operations = append(operations, pending...)
+
+	var secretsManager secrets.Manager
+	if r.base != nil {
+		secretsManager = r.base.SecretsManager
+	}
+	manifest := deploy.Manifest{
+		Time:    time.Now(),
+		Version: version.Version,
+	}
+	manifest.Magic = manifest.NewMagic()
+
+	recovery := &SnapshotRecovery{
+		Snapshot:   deploy.NewSnapshot(manifest, secretsManager, resources, operations),
+		Applied:    r.applied,
+		Pending:    pending,
+		Incomplete: r.incomplete,
+	}
+	for _, res := range resources {
+		if r.redacted[res.URN] {
+			recovery.Redacted = append(recovery.Redacted, res.URN)
+			delete(r.redacted, res.URN)
+		}
+	}
+	for _, res := range resources {
+		recovery.Dropped = append(recovery.Dropped, r.droppedProperties(res.URN)...)
+	}
+	for _, op := range operations {
+		recovery.Dropped = append(recovery.Dropped, r.droppedProperties(op.Resource.URN)...)
+	}
+	return recovery, nil
+}
+
+// droppedProperties returns the properties that were dropped from the given resource, in order, the first time it is
+// called for the resource.
+func (r *snapshotRecoverer) droppedProperties(urn resource.URN) []DroppedProperty {
+	keys := maps.Keys(r.dropped[urn])
+	slices.Sort(keys)
+	delete(r.dropped, urn)
+
+	dropped := make([]DroppedProperty, len(keys))
+	for i, k := range keys {
+		dropped[i] = DroppedProperty{URN: urn, Key: k}
+	}
+	return dropped
+}
+
+// pendingOperation returns the pending operation for an operation that did not finish, if it has one.
+func (r *snapshotRecoverer) pendingOperation(md apitype.StepEventMetadata) (*resource.Operation, error) {
+	typ, ok := pendingOperationType(display.StepOp(md.Op))
+	if !ok {
+		return nil, nil
+	}
+	state := md.New
+	if typ == resource.OperationTypeDeleting {
+		state = md.Old
+	}
+	if state == nil {
+		return nil, fmt.Errorf("%v operation on %v has no state", md.Op, md.URN)
+	}
+
+	res, droppedInputs, droppedOutputs, err := r.state(state, r.old(resource.URN(md.URN), state.Delete))
+	if err != nil {
+		return nil, err
+	}
+	r.drop(res.URN, droppedInputs)
+	r.drop(res.URN, droppedOutputs)
+	op := resource.NewOperation(res, typ)
+	return &op, nil
+}
+
+// pendingOperationType returns the type of the pending operation that records the given operation, if it has one.
+func pendingOperationType(op display.StepOp) (resource.OperationType, bool) {
+	switch op {
+	case deploy.OpCreate, deploy.OpCreateReplacement:
+		return resource.OperationTypeCreating, true
+	case deploy.OpUpdate:
+		return resource.OperationTypeUpdating, true
+	case deploy.OpDelete, deploy.OpDeleteReplaced, deploy.OpReadDiscard, deploy.OpDiscardReplaced:
+		return resource.OperationTypeDeleting, true
+	case deploy.OpRead, deploy.OpReadReplacement:
+		return resource.OperationTypeReading, true
+	case deploy.OpImport, deploy.OpImportReplacement:
+		return resource.OperationTypeImporting, true
+	default:
+		return "", false
+	}
+}
+
+// isLogicalOp returns true if the given operation does not change a stack's state by itself.
+func isLogicalOp(op display.StepOp) bool {
+	return op == deploy.OpReplace || op == deploy.OpRemovePendingReplace || op == deploy.OpOutputChange
+}
+
+// eventResource returns the state of the resource that an operation acts on.
+func eventResource(md apitype.StepEventMetadata) *apitype.StepEventStateMetadata {
+	if md.New != nil {
+		return md.New
+	}
+	return md.Old
+}
+
+// isRedacted returns true if the given value contains secrets, assets or archives, whose values are redacted in event
+// logs.
+func isRedacted(v resource.PropertyValue) bool {
+	switch {
+	case v.IsSecret(), v.IsAsset(), v.IsArchive():
+		return true
+	case v.IsArray():
+		for _, e := range v.ArrayValue() {
+			if isRedacted(e) {
+				return true
+			}
+		}
+	case v.IsObject():
+		for _, e := range v.ObjectValue() {
+			if isRedacted(e) {
+				return true
+			}
+		}
+	case v.IsComputed():
+		return isRedacted(v.Input().Element)
+	case v.IsOutput():
+		return isRedacted(v.OutputValue().Element)
+	}
+	return false
+}
+
+// redactedDecrypter decrypts the blinded secrets in event logs to a placeholder string. The placeholders are only used
+// to find the redacted values, which are replaced or dropped before they can be recovered.
+type redactedDecrypter struct{}
+
+func (redactedDecrypter) DecryptValue(ctx context.Context, _ string) (string, error) {
+	return `"[secret]"`, nil
+}
+
+func (d redactedDecrypter) BulkDecrypt(ctx context.Context, ciphertexts []string) (map[string]string, error) {
+	return config.DefaultBulkDecrypt(ctx, d, ciphertexts)
+}
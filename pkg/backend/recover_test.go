@@ -0,0 +1,214 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func TestRecoverSnapshot(t *testing.T) {
+	t.Parallel()
+
+	newResource := func(typ tokens.Type, name string, id resource.ID, provider string) *resource.State {
+		return &resource.State{
+			Type:     typ,
+			URN:      resource.NewURN("test-stack", "test-project", "", typ, name),
+			Custom:   id != "",
+			ID:       id,
+			Provider: provider,
+			Inputs:   resource.PropertyMap{},
+			Outputs:  resource.PropertyMap{},
+		}
+	}
+	withID := func(res *resource.State, id resource.ID) *resource.State {
+		copy := *res
+		copy.ID = id
+		return &copy
+	}
+
+	prov := newResource("pulumi:providers:pkg", "prov", "p", "")
+	ref := string(prov.URN) + "::p"
+	a := newResource("pkg:m:typ", "a", "a1", ref)
+	a.Inputs["x"] = resource.NewNumberProperty(1)
+	b := newResource("pkg:m:typ", "b", "b1", ref)
+	b.Dependencies = []resource.URN{a.URN}
+	b.Outputs["password"] = resource.MakeSecret(resource.NewStringProperty("hunter2"))
+	c := newResource("pkg:m:typ", "c", "c1", ref)
+	d := newResource("pkg:m:typ", "d", "d1", ref)
+	base := NewSnapshot([]*resource.State{prov, a, b, c, d})
+
+	root := newResource(resource.RootStackType, "test-project-test-stack", "", "")
+	newA := withID(a, "a1")
+	newA.Inputs = resource.PropertyMap{"x": resource.NewNumberProperty(2)}
+	newB := withID(b, "b1")
+	newB.Outputs = resource.PropertyMap{"password": resource.MakeSecret(resource.NewStringProperty("hunter3"))}
+	e := newResource("pkg:m:typ", "e", "e1", ref)
+	e.Outputs["token"] = resource.MakeSecret(resource.NewStringProperty("s3cr3t"))
+	newD := withID(d, "d2")
+	deletedD := withID(d, "d1")
+	deletedD.Delete = true
+	f := newResource("pkg:m:typ", "f", "", ref)
+	f.Custom = true
+	g := newResource("pkg:m:typ", "g", "", ref)
+	g.Custom = true
+	h := newResource("pulumi:providers:pkg", "h", "h1", "")
+	h.Inputs["token"] = resource.MakeSecret(resource.NewStringProperty("s3cr3t"))
+	i := newResource("pkg:m:typ", "i", "i1", string(h.URN)+"::h1")
+	rootOutputs := withID(root, "")
+	rootOutputs.Outputs = resource.PropertyMap{"out": resource.NewStringProperty("v")}
+
+	state := func(res *resource.State) *apitype.StepEventStateMetadata {
+		if res == nil {
+			return nil
+		}
+		inputs, err := stack.SerializeProperties(context.Background(), res.Inputs, config.BlindingCrypter, false)
+		require.NoError(t, err)
+		outputs, err := stack.SerializeProperties(context.Background(), res.Outputs, config.BlindingCrypter, false)
+		require.NoError(t, err)
+		return &apitype.StepEventStateMetadata{
+			Type:     string(res.Type),
+			URN:      string(res.URN),
+			Custom:   res.Custom,
+			Delete:   res.Delete,
+			ID:       string(res.ID),
+			Provider: res.Provider,
+			Inputs:   inputs,
+			Outputs:  outputs,
+		}
+	}
+	metadata := func(op display.StepOp, old, new *resource.State) apitype.StepEventMetadata {
+		res := new
+		if res == nil {
+			res = old
+		}
+		return apitype.StepEventMetadata{
+			Op:   apitype.OpType(op),
+			URN:  string(res.URN),
+			Type: string(res.Type),
+			Old:  state(old),
+			New:  state(new),
+		}
+	}
+	pre := func(op display.StepOp, old, new *resource.State) apitype.EngineEvent {
+		return apitype.EngineEvent{ResourcePreEvent: &apitype.ResourcePreEvent{Metadata: metadata(op, old, new)}}
+	}
+	outputs := func(op display.StepOp, old, new *resource.State) apitype.EngineEvent {
+		return apitype.EngineEvent{ResOutputsEvent: &apitype.ResOutputsEvent{Metadata: metadata(op, old, new)}}
+	}
+	failed := func(op display.StepOp, old, new *resource.State) apitype.EngineEvent {
+		return apitype.EngineEvent{ResOpFailedEvent: &apitype.ResOpFailedEvent{Metadata: metadata(op, old, new)}}
+	}
+
+	events := []apitype.EngineEvent{
+		// The root stack is a component resource, so its create is applied as soon as it starts.
+		pre(deploy.OpCreate, nil, root),
+		pre(deploy.OpSame, prov, prov),
+		outputs(deploy.OpSame, prov, prov),
+		pre(deploy.OpUpdate, a, newA),
+		pre(deploy.OpCreate, nil, e),
+		outputs(deploy.OpUpdate, a, newA),
+		pre(deploy.OpUpdate, b, newB),
+		outputs(deploy.OpCreate, nil, e),
+		outputs(deploy.OpUpdate, b, newB),
+		pre(deploy.OpCreateReplacement, d, newD),
+		outputs(deploy.OpCreateReplacement, d, newD),
+		pre(deploy.OpReplace, d, newD),
+		outputs(deploy.OpReplace, d, newD),
+		pre(deploy.OpDelete, c, nil),
+		outputs(deploy.OpDelete, c, nil),
+		pre(deploy.OpCreate, nil, g),
+		failed(deploy.OpCreate, nil, g),
+		pre(deploy.OpCreate, nil, h),
+		outputs(deploy.OpCreate, nil, h),
+		pre(deploy.OpCreate, nil, i),
+		outputs(deploy.OpCreate, nil, i),
+		// These operations never finished.
+		pre(deploy.OpCreate, nil, f),
+		pre(deploy.OpDeleteReplaced, deletedD, nil),
+		outputs(deploy.OpCreate, nil, rootOutputs),
+	}
+	// Recover from the events as they are read from an event log.
+	bytes, err := json.Marshal(events)
+	require.NoError(t, err)
+	events = nil
+	require.NoError(t, json.Unmarshal(bytes, &events))
+
+	recovery, err := RecoverSnapshot(base, events)
+	require.NoError(t, err)
+	snap := recovery.Snapshot
+	require.NoError(t, snap.VerifyIntegrity())
+
+	assert.Equal(t, 7, recovery.Applied)
+	urns := make([]resource.URN, len(snap.Resources))
+	for i, res := range snap.Resources {
+		urns[i] = res.URN
+	}
+	assert.Equal(t, []resource.URN{root.URN, prov.URN, a.URN, e.URN, b.URN, d.URN, d.URN}, urns)
+
+	assert.Equal(t, resource.PropertyMap{"out": resource.NewStringProperty("v")}, snap.Resources[0].Outputs)
+	assert.Equal(t, resource.NewNumberProperty(2), snap.Resources[2].Inputs["x"])
+	assert.Equal(t, resource.ID("e1"), snap.Resources[3].ID)
+	// e was created by the deployment, so the last checkpoint has no value for its secret output to use instead of the
+	// redacted one.
+	assert.Equal(t, resource.PropertyMap{}, snap.Resources[3].Outputs)
+	assert.Equal(t, []DroppedProperty{{URN: e.URN, Key: "token"}}, recovery.Dropped)
+	// Secrets are redacted in event logs, so b's new password could not be recovered.
+	assert.Equal(t, b.Outputs, snap.Resources[4].Outputs)
+	assert.Equal(t, []resource.URN{a.URN}, snap.Resources[4].Dependencies)
+	assert.Equal(t, []resource.URN{b.URN}, recovery.Redacted)
+	assert.Equal(t, resource.ID("d2"), snap.Resources[5].ID)
+	assert.False(t, snap.Resources[5].Delete)
+	assert.Equal(t, resource.ID("d1"), snap.Resources[6].ID)
+	assert.True(t, snap.Resources[6].Delete)
+
+	// h was created with a secret input that the last checkpoint has no value for, so its create, and the create of i,
+	// which uses h as its provider, are recorded as pending operations instead.
+	require.Len(t, snap.PendingOperations, 4)
+	assert.Equal(t, recovery.Incomplete, snap.PendingOperations[:2])
+	assert.Equal(t, resource.OperationTypeCreating, snap.PendingOperations[0].Type)
+	assert.Equal(t, h.URN, snap.PendingOperations[0].Resource.URN)
+	assert.Equal(t, resource.PropertyMap{}, snap.PendingOperations[0].Resource.Inputs)
+	assert.Equal(t, resource.OperationTypeCreating, snap.PendingOperations[1].Type)
+	assert.Equal(t, i.URN, snap.PendingOperations[1].Resource.URN)
+
+	assert.Equal(t, recovery.Pending, snap.PendingOperations[2:])
+	assert.Equal(t, resource.OperationTypeCreating, snap.PendingOperations[2].Type)
+	assert.Equal(t, f.URN, snap.PendingOperations[2].Resource.URN)
+	assert.Equal(t, resource.OperationTypeDeleting, snap.PendingOperations[3].Type)
+	assert.Equal(t, d.URN, snap.PendingOperations[3].Resource.URN)
+	assert.True(t, snap.PendingOperations[3].Resource.Delete)
+}
+
+func TestRecoverSnapshotPreview(t *testing.T) {
+	t.Parallel()
+
+	_, err := RecoverSnapshot(nil, []apitype.EngineEvent{{
+		ResourcePreEvent: &apitype.ResourcePreEvent{Planning: true},
+	}})
+	assert.ErrorContains(t, err, "recorded by a preview")
+}
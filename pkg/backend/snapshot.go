@@ -45,6 +45,18 @@ type SnapshotPersister interface {
 	Save(snapshot *deploy.Snapshot) error
 }
 
+// JournalPersister is a SnapshotPersister that can persist the changes made to a snapshot since it was last persisted,
+// rather than the whole snapshot. The SnapshotManager calls Append after each mutation, and Save once it is closed so
+// that the persister can compact its journal into a full checkpoint.
+type JournalPersister interface {
+	SnapshotPersister
+
+	// Append persists the changes between the given snapshot and the snapshot that was last persisted. dirty holds the
+	// resource states that may have been modified in place since they were last persisted; every other state that
+	// was previously persisted is unchanged.
+	Append(snapshot *deploy.Snapshot, dirty map[*resource.State]bool) error
+}
+
 // SnapshotManager is an implementation of engine.SnapshotManager that inspects steps and performs
 // mutations on the global snapshot object serially. This implementation maintains two bits of state: the "base"
 // snapshot, which is completely immutable and represents the state of the world prior to the application
@@ -68,6 +80,8 @@ type SnapshotManager struct {
 	operations       []resource.Operation     // The set of operations known to be outstanding in this plan
 	dones            map[*resource.State]bool // The set of resources that have been operated upon already by this plan
 	completeOps      map[*resource.State]bool // The set of resources that have completed their operation
+	dirty            map[*resource.State]bool // The set of resources that may have changed since the last save
+	rebased          bool                     // True if the base snapshot may have been rewritten since the last save
 	mutationRequests chan<- mutationRequest   // The queue of mutation requests, to be retired serially by the manager
 	cancel           chan bool                // A channel used to request cancellation of any new mutation requests.
 	done             <-chan error             // A channel that sends a single result when the manager has shut down.
@@ -126,6 +140,9 @@ func (sm *SnapshotManager) mutate(mutator func() bool) error {
 func (sm *SnapshotManager) RegisterResourceOutputs(step deploy.Step) error {
 	return sm.mutate(func() bool {
 		old, new := step.Old(), step.New()
+		if new != nil {
+			sm.dirty[new] = true
+		}
 		if old != nil && new != nil && old.Outputs.DeepEquals(new.Outputs) {
 			logging.V(9).Infof("SnapshotManager: eliding RegisterResourceOutputs due to equal outputs")
 			return false
@@ -374,6 +391,9 @@ func (csm *createSnapshotMutation) End(step deploy.Step, successful bool) error
 			if old := step.Old(); old != nil && old.PendingReplacement {
 				csm.manager.markDone(old)
 			}
+
+			// The old state was marked for deletion in place, so it has changed since it was last saved.
+			csm.manager.markDirty(step.Old())
 		}
 		return true
 	})
@@ -498,6 +518,13 @@ func (rsm *refreshSnapshotMutation) End(step deploy.Step, successful bool) error
 	contract.Requiref(step.Op() == deploy.OpRefresh, "step.Op", "must be %q, got %q", deploy.OpRefresh, step.Op())
 	logging.V(9).Infof("SnapshotManager: refreshSnapshotMutation.End(..., %v)", successful)
 	return rsm.manager.mutate(func() bool {
+		rsm.manager.markDirty(step.Old())
+		rsm.manager.markDirty(step.New())
+
+		// Rebuilding the base snapshot may also change states that weren't refreshed (e.g. by pruning their
+		// dependencies), so the next save can't rely on the dirty set.
+		rsm.manager.rebased = true
+
 		// We always elide refreshes. The expectation is that all of these run before any actual mutations and that
 		// some other component will rewrite the base snapshot in-memory, so there's no action the snapshot
 		// manager needs to take other than to remember that the base snapshot--and therefore the actual snapshot--may
@@ -548,6 +575,11 @@ func (ism *importSnapshotMutation) End(step deploy.Step, successful bool) error
 		ism.manager.markOperationComplete(step.New())
 		if successful {
 			ism.manager.markNew(step.New())
+
+			// An import-replacement marks the resource it replaces for deletion in place.
+			if imp, ok := step.(*deploy.ImportStep); ok {
+				ism.manager.markDirty(imp.Original())
+			}
 		}
 		return true
 	})
@@ -558,6 +590,7 @@ func (ism *importSnapshotMutation) End(step deploy.Step, successful bool) error
 func (sm *SnapshotManager) markDone(state *resource.State) {
 	contract.Requiref(state != nil, "state", "must not be nil")
 	sm.dones[state] = true
+	sm.dirty[state] = true
 	logging.V(9).Infof("Marked old state snapshot as done: %v", state.URN)
 }
 
@@ -567,6 +600,7 @@ func (sm *SnapshotManager) markDone(state *resource.State) {
 func (sm *SnapshotManager) markNew(state *resource.State) {
 	contract.Requiref(state != nil, "state", "must not be nil")
 	sm.resources = append(sm.resources, state)
+	sm.dirty[state] = true
 	logging.V(9).Infof("Appended new state snapshot to be written: %v", state.URN)
 }
 
@@ -574,6 +608,7 @@ func (sm *SnapshotManager) markNew(state *resource.State) {
 func (sm *SnapshotManager) markOperationPending(state *resource.State, op resource.OperationType) {
 	contract.Requiref(state != nil, "state", "must not be nil")
 	sm.operations = append(sm.operations, resource.NewOperation(state, op))
+	sm.dirty[state] = true
 	logging.V(9).Infof("SnapshotManager.markPendingOperation(%s, %s)", state.URN, string(op))
 }
 
@@ -581,9 +616,17 @@ func (sm *SnapshotManager) markOperationPending(state *resource.State, op resour
 func (sm *SnapshotManager) markOperationComplete(state *resource.State) {
 	contract.Requiref(state != nil, "state", "must not be nil")
 	sm.completeOps[state] = true
+	sm.dirty[state] = true
 	logging.V(9).Infof("SnapshotManager.markOperationComplete(%s)", state.URN)
 }
 
+// markDirty records that a resource may have been modified in place, if it is not nil.
+func (sm *SnapshotManager) markDirty(state *resource.State) {
+	if state != nil {
+		sm.dirty[state] = true
+	}
+}
+
 // snap produces a new Snapshot given the base snapshot and a list of resources that the current
 // plan has created.
 func (sm *SnapshotManager) snap() *deploy.Snapshot {
@@ -666,15 +709,23 @@ func (sm *SnapshotManager) snap() *deploy.Snapshot {
 	return deploy.NewSnapshot(manifest, secretsManager, resources, operations)
 }
 
-// saveSnapshot persists the current snapshot and optionally verifies it afterwards.
-func (sm *SnapshotManager) saveSnapshot() error {
+// saveSnapshot persists the current snapshot and optionally verifies it afterwards. If the persister is a
+// JournalPersister, only the changes since the last save are persisted unless compact is true or the base snapshot
+// may have been rewritten.
+func (sm *SnapshotManager) saveSnapshot(compact bool) error {
 	snap, err := sm.snap().NormalizeURNReferences()
 	if err != nil {
 		return fmt.Errorf("failed to normalize URN references: %w", err)
 	}
-	if err := sm.persister.Save(snap); err != nil {
+	if jp, ok := sm.persister.(JournalPersister); ok && !compact && !sm.rebased {
+		err = jp.Append(snap, sm.dirty)
+	} else {
+		err = sm.persister.Save(snap)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to save snapshot: %w", err)
 	}
+	sm.dirty, sm.rebased = make(map[*resource.State]bool), false
 	if !DisableIntegrityChecking {
 		if err := snap.VerifyIntegrity(); err != nil {
 			return fmt.Errorf("failed to verify snapshot: %w", err)
@@ -695,7 +746,7 @@ serviceLoop:
 		case request := <-mutationRequests:
 			var err error
 			if request.mutator() {
-				err = sm.saveSnapshot()
+				err = sm.saveSnapshot(false /* compact */)
 				hasElidedWrites = false
 			} else {
 				hasElidedWrites = true
@@ -706,11 +757,12 @@ serviceLoop:
 		}
 	}
 
-	// If we still have elided writes once the channel has closed, flush the snapshot.
+	// If we still have elided writes once the channel has closed, flush the snapshot. Journals are always compacted
+	// into a full snapshot at the end of the plan.
 	var err error
-	if hasElidedWrites {
+	if _, journaled := sm.persister.(JournalPersister); hasElidedWrites || journaled {
 		logging.V(9).Infof("SnapshotManager: flushing elided writes...")
-		err = sm.saveSnapshot()
+		err = sm.saveSnapshot(true /* compact */)
 	}
 	done <- err
 }
@@ -725,7 +777,7 @@ func (sm *SnapshotManager) unsafeServiceLoop(mutationRequests chan mutationReque
 			request.mutator()
 			request.result <- nil
 		case <-sm.cancel:
-			done <- sm.saveSnapshot()
+			done <- sm.saveSnapshot(true /* compact */)
 			return
 		}
 	}
@@ -750,6 +802,7 @@ func NewSnapshotManager(
 		baseSnapshot:     baseSnap,
 		dones:            make(map[*resource.State]bool),
 		completeOps:      make(map[*resource.State]bool),
+		dirty:            make(map[*resource.State]bool),
 		mutationRequests: mutationRequests,
 		cancel:           cancel,
 		done:             done,
@@ -37,6 +37,7 @@ type MockRegisterResourceEvent struct {
 }
 
 func (m MockRegisterResourceEvent) Goal() *resource.Goal               { return nil }
+func (m MockRegisterResourceEvent) Hooks() *deploy.ResourceHooks       { return nil }
 func (m MockRegisterResourceEvent) Done(result *deploy.RegisterResult) {}
 
 type MockStackPersister struct {
@@ -1093,3 +1094,72 @@ func TestRecordingSameFailure(t *testing.T) {
 	assert.Len(t, snap.PendingOperations, 0)
 	assert.Equal(t, resourceA.URN, snap.Resources[0].URN)
 }
+
+type mockJournalPersister struct {
+	MockStackPersister
+
+	// Appends records the dirty resources of each call to Append.
+	Appends []map[*resource.State]bool
+}
+
+func (m *mockJournalPersister) Append(snap *deploy.Snapshot, dirty map[*resource.State]bool) error {
+	m.Appends = append(m.Appends, dirty)
+	return nil
+}
+
+// Tests that journal persisters are sent the resources changed by each mutation, and that the journal is compacted
+// into a full snapshot when the manager is closed.
+func TestJournalPersister(t *testing.T) {
+	t.Parallel()
+
+	resourceA := NewResource("a")
+	resourceB := NewResource("b")
+	snap := NewSnapshot([]*resource.State{resourceA})
+	sp := &mockJournalPersister{}
+	manager := NewSnapshotManager(sp, snap.SecretsManager, snap)
+
+	step := deploy.NewCreateStep(nil, &MockRegisterResourceEvent{}, resourceB)
+	mutation, err := manager.BeginMutation(step)
+	require.NoError(t, err)
+	require.NoError(t, mutation.End(step, true /* successful */))
+
+	resourceB.Outputs = resource.PropertyMap{"hello": resource.NewStringProperty("world")}
+	require.NoError(t, manager.RegisterResourceOutputs(deploy.NewSameStep(nil, nil, NewResource("b"), resourceB)))
+
+	require.Len(t, sp.Appends, 3)
+	assert.Equal(t, map[*resource.State]bool{resourceB: true}, sp.Appends[0])
+	assert.Equal(t, map[*resource.State]bool{resourceB: true}, sp.Appends[1])
+	assert.Equal(t, map[*resource.State]bool{resourceB: true}, sp.Appends[2])
+	assert.Empty(t, sp.SavedSnapshots)
+
+	require.NoError(t, manager.Close())
+	require.Len(t, sp.SavedSnapshots, 1)
+	assert.Equal(t, []*resource.State{resourceB, resourceA}, sp.LastSnap().Resources)
+}
+
+// Tests that the first save after a refresh is a full snapshot, as rebuilding the base snapshot may modify resources
+// that weren't refreshed.
+func TestJournalPersister_refresh(t *testing.T) {
+	t.Parallel()
+
+	resourceA := NewResource("a")
+	resourceB := NewResource("b")
+	snap := NewSnapshot([]*resource.State{resourceA})
+	sp := &mockJournalPersister{}
+	manager := NewSnapshotManager(sp, snap.SecretsManager, snap)
+
+	refresh := deploy.NewRefreshStep(nil, resourceA, nil)
+	mutation, err := manager.BeginMutation(refresh)
+	require.NoError(t, err)
+	require.NoError(t, mutation.End(refresh, true /* successful */))
+
+	step := deploy.NewCreateStep(nil, &MockRegisterResourceEvent{}, resourceB)
+	mutation, err = manager.BeginMutation(step)
+	require.NoError(t, err)
+	require.NoError(t, mutation.End(step, true /* successful */))
+
+	// The create's pending operation is saved in full; its completion is appended.
+	require.Len(t, sp.SavedSnapshots, 1)
+	require.Len(t, sp.Appends, 1)
+	assert.Equal(t, map[*resource.State]bool{resourceB: true}, sp.Appends[0])
+}
@@ -0,0 +1,1065 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlstate
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofrs/uuid"
+	user "github.com/tweekmonster/luser"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
+	sdkDisplay "github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/operations"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// Backend extends the base backend interface with specific information about SQL backends.
+type Backend interface {
+	backend.Backend
+	sql() // at the moment, no sql specific info, so just use a marker function.
+
+	// CancelStaleLocks removes the lock on a stack if its lease has expired or its owning process is no longer
+	// running, and returns the number of locks removed.
+	CancelStaleLocks(ctx context.Context, stackRef backend.StackReference) (int, error)
+}
+
+type sqlBackend struct {
+	d diag.Sink
+
+	// url is the URL provided when the sqlBackend was initialized, without any password it contained.
+	url string
+
+	// driver is the name of the database/sql driver used to talk to the database.
+	driver string
+	db     *sql.DB
+
+	// lockID identifies the locks taken by this backend instance.
+	lockID string
+	// lockLease is the lease duration written to the locks taken by this backend.
+	lockLease time.Duration
+	// locks maps the fully qualified name of each stack locked by this backend to the state of its lock.
+	locks      map[string]*heldLock
+	locksMutex sync.Mutex
+
+	// The current project, if any.
+	currentProject atomic.Pointer[workspace.Project]
+}
+
+// heldLock is a stack lock held by a backend.
+type heldLock struct {
+	// count is the number of times the lock has been taken without being released.
+	count int
+	// stop stops renewing the lock's lease.
+	stop func()
+}
+
+// Assert we implement the backend.SpecificDeploymentExporter, backend.DeploymentRestorer and backend.StackLocker
+// interfaces.
+var (
+	_ backend.SpecificDeploymentExporter = &sqlBackend{}
+	_ backend.DeploymentRestorer         = &sqlBackend{}
+	_ backend.StackLocker                = &sqlBackend{}
+)
+
+type sqlBackendReference struct {
+	name    tokens.StackName
+	project tokens.Name
+
+	// A thread-safe way to get the current project.
+	// The function reference or the pointer returned by the function may be nil.
+	currentProject func() *workspace.Project
+}
+
+func (r *sqlBackendReference) String() string {
+	// If the user has asked us to fully qualify names, we won't elide any
+	// information.
+	if cmdutil.FullyQualifyStackNames {
+		return fmt.Sprintf("organization/%s/%s", string(r.project), r.name.String())
+	}
+
+	if r.currentProject != nil {
+		proj := r.currentProject()
+		// When stringifying backend references, we take the current project (if present) into account.
+		// If the project names match, we can elide them.
+		if proj != nil && string(r.project) == string(proj.Name) {
+			return r.name.String()
+		}
+	}
+
+	return fmt.Sprintf("organization/%s/%s", string(r.project), r.name.String())
+}
+
+func (r *sqlBackendReference) Name() tokens.StackName {
+	return r.name
+}
+
+func (r *sqlBackendReference) Project() (tokens.Name, bool) {
+	return r.project, true
+}
+
+func (r *sqlBackendReference) FullyQualifiedName() tokens.QName {
+	return tokens.QName(fmt.Sprintf("organization/%s/%s", string(r.project), r.name.String()))
+}
+
+// IsSQLBackendURL returns true if the given URL refers to a database that can be used by a SQL backend.
+func IsSQLBackendURL(urlstr string) bool {
+	return strings.HasPrefix(urlstr, SQLitePrefix) ||
+		strings.HasPrefix(urlstr, PostgresPrefix) ||
+		strings.HasPrefix(urlstr, PostgreSQLPrefix)
+}
+
+// New constructs a new SQL backend, using the database at the given URL for storage. The URL must either be a
+// sqlite:// URL naming a database file, or a postgres:// URL in the form accepted by Postgres.
+//
+// If a postgres:// URL names a user but no password, the password stored by Login for the URL is used, if any.
+func New(ctx context.Context, d diag.Sink, originalURL string, project *workspace.Project) (Backend, error) {
+	return newSQLBackend(ctx, d, originalURL, project)
+}
+
+func newSQLBackend(
+	ctx context.Context, d diag.Sink, originalURL string, project *workspace.Project,
+) (*sqlBackend, error) {
+	redactedURL, _, hasPassword := redactURL(originalURL)
+	databaseURL := originalURL
+	if !hasPassword && !strings.HasPrefix(originalURL, SQLitePrefix) {
+		if account, err := workspace.GetAccount(redactedURL); err == nil && account.AccessToken != "" {
+			databaseURL = withPassword(originalURL, account.AccessToken)
+		}
+	}
+
+	driver, dsn, err := parseDatabaseURL(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database %s: %w", redactedURL, err)
+	}
+	if driver == sqliteDriver {
+		// SQLite only supports a single writer, so there's nothing to gain from more connections.
+		db.SetMaxOpenConns(1)
+	}
+
+	// Allocate a unique lock ID for this backend instance.
+	lockID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	backend := &sqlBackend{
+		d:         d,
+		url:       redactedURL,
+		driver:    driver,
+		db:        db,
+		lockID:    lockID.String(),
+		lockLease: defaultLockLease,
+	}
+	backend.currentProject.Store(project)
+
+	if err := backend.ensureSchema(ctx); err != nil {
+		contract.IgnoreClose(db)
+		return nil, fmt.Errorf("unable to open database %s: %w", redactedURL, err)
+	}
+	return backend, nil
+}
+
+// Login opens the SQL backend at the given URL and makes it the current backend. The URL is stored without its
+// password; the password is stored with the account's credentials instead, so that it is never displayed.
+func Login(ctx context.Context, d diag.Sink, url string, project *workspace.Project) (Backend, error) {
+	be, err := New(ctx, d, url, project)
+	if err != nil {
+		return nil, err
+	}
+	var account workspace.Account
+	if _, password, hasPassword := redactURL(url); hasPassword {
+		account.AccessToken = password
+	} else if existing, err := workspace.GetAccount(be.URL()); err == nil {
+		// Logging in again without a password keeps the one we already have.
+		account.AccessToken = existing.AccessToken
+	}
+	return be, workspace.StoreAccount(be.URL(), account, true)
+}
+
+func (b *sqlBackend) getReference(ref backend.StackReference) (*sqlBackendReference, error) {
+	stackRef, ok := ref.(*sqlBackendReference)
+	if !ok {
+		return nil, errors.New("bad stack reference type")
+	}
+	return stackRef, nil
+}
+
+func (b *sqlBackend) sql() {}
+
+func (b *sqlBackend) Name() string {
+	if b.driver == postgresDriver {
+		if u, err := url.Parse(b.url); err == nil && u.Host != "" {
+			return u.Host
+		}
+	}
+	name, err := os.Hostname()
+	contract.IgnoreError(err)
+	if name == "" {
+		name = "sql"
+	}
+	return name
+}
+
+func (b *sqlBackend) URL() string {
+	return b.url
+}
+
+func (b *sqlBackend) SetCurrentProject(project *workspace.Project) {
+	b.currentProject.Store(project)
+}
+
+func (b *sqlBackend) GetPolicyPack(ctx context.Context, policyPack string,
+	d diag.Sink,
+) (backend.PolicyPack, error) {
+	return nil, errors.New("SQL backend does not support resource policy")
+}
+
+func (b *sqlBackend) ListPolicyGroups(ctx context.Context, orgName string, _ backend.ContinuationToken) (
+	apitype.ListPolicyGroupsResponse, backend.ContinuationToken, error,
+) {
+	return apitype.ListPolicyGroupsResponse{}, nil, errors.New("SQL backend does not support resource policy")
+}
+
+func (b *sqlBackend) ListPolicyPacks(ctx context.Context, orgName string, _ backend.ContinuationToken) (
+	apitype.ListPolicyPacksResponse, backend.ContinuationToken, error,
+) {
+	return apitype.ListPolicyPacksResponse{}, nil, errors.New("SQL backend does not support resource policy")
+}
+
+func (b *sqlBackend) SupportsTags() bool {
+	return true
+}
+
+func (b *sqlBackend) SupportsOrganizations() bool {
+	return false
+}
+
+func (b *sqlBackend) SupportsProgress() bool {
+	return false
+}
+
+func (b *sqlBackend) ParseStackReference(stackRef string) (backend.StackReference, error) {
+	return b.parseStackReference(stackRef)
+}
+
+func (b *sqlBackend) parseStackReference(stackRef string) (*sqlBackendReference, error) {
+	// We accept the following forms:
+	//
+	// 1. <stack-name>
+	// 2. <org-name>/<stack-name>
+	// 3. <org-name>/<project-name>/<stack-name>
+	//
+	// org-name must always be "organization", as it is for diy backends.
+	if stackRef == "" {
+		return nil, errors.New("stack name must not be empty")
+	}
+
+	var name, project, org string
+	split := strings.Split(stackRef, "/") // guaranteed to have at least one element
+	switch len(split) {
+	case 1:
+		name = split[0]
+	case 2:
+		org = split[0]
+		name = split[1]
+	case 3:
+		org = split[0]
+		project = split[1]
+		name = split[2]
+	default:
+		return nil, fmt.Errorf("could not parse stack reference '%s'", stackRef)
+	}
+
+	if org != "" && org != "organization" {
+		return nil, errors.New("organization name must be 'organization'")
+	}
+
+	if project == "" {
+		currentProject := b.currentProject.Load()
+		if currentProject == nil {
+			return nil, errors.New("if you're using the --stack flag, " +
+				"pass the fully qualified name (organization/project/stack)")
+		}
+		project = currentProject.Name.String()
+	}
+	if err := tokens.ValidateProjectName(project); err != nil {
+		return nil, err
+	}
+
+	parsedName, err := tokens.ParseStackName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlBackendReference{
+		name:           parsedName,
+		project:        tokens.Name(project),
+		currentProject: b.currentProject.Load,
+	}, nil
+}
+
+// ValidateStackName verifies the stack name is valid for the SQL backend.
+func (b *sqlBackend) ValidateStackName(stackRef string) error {
+	_, err := b.ParseStackReference(stackRef)
+	return err
+}
+
+func (b *sqlBackend) DoesProjectExist(ctx context.Context, _ string, projectName string) (bool, error) {
+	var exists bool
+	err := b.db.QueryRowContext(ctx, b.rebind(
+		`SELECT EXISTS (SELECT 1 FROM pulumi_stacks WHERE project = ?)`), projectName).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("checking for project: %w", err)
+	}
+	return exists, nil
+}
+
+// Confirm the specified stack's project doesn't contradict the Pulumi.yaml of the current project.
+// If the CWD is not in a Pulumi project, does not contradict.
+func currentProjectContradictsWorkspace(stack *sqlBackendReference) bool {
+	contract.Requiref(stack != nil, "stack", "is nil")
+
+	projPath, err := workspace.DetectProjectPath()
+	if err != nil || projPath == "" {
+		return false
+	}
+
+	proj, err := workspace.LoadProject(projPath)
+	if err != nil {
+		return false
+	}
+
+	return proj.Name.String() != stack.project.String()
+}
+
+func (b *sqlBackend) CreateStack(ctx context.Context, stackRef backend.StackReference,
+	root string, opts *backend.CreateStackOptions,
+) (backend.Stack, error) {
+	if opts != nil && len(opts.Teams) > 0 {
+		return nil, backend.ErrTeamsNotSupported
+	}
+
+	sqlStackRef, err := b.getReference(stackRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentProjectContradictsWorkspace(sqlStackRef) {
+		return nil, fmt.Errorf("provided project name %q doesn't match Pulumi.yaml", sqlStackRef.project)
+	}
+
+	checkpoint, err := marshalCheckpoint(&apitype.CheckpointV3{Stack: sqlStackRef.FullyQualifiedName()})
+	if err != nil {
+		return nil, err
+	}
+
+	// The primary key on the stacks table guarantees that only one of any concurrent creates succeeds.
+	err = b.withTx(ctx, func(tx *sql.Tx) error {
+		exists, err := b.stackExists(ctx, tx, sqlStackRef)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return &backend.StackAlreadyExistsError{StackName: string(sqlStackRef.FullyQualifiedName())}
+		}
+
+		_, err = tx.ExecContext(ctx, b.rebind(
+			`INSERT INTO pulumi_stacks (project, name, checkpoint) VALUES (?, ?, ?)`),
+			string(sqlStackRef.project), sqlStackRef.name.String(), checkpoint)
+		if err != nil {
+			return fmt.Errorf("creating stack: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stack := newStack(sqlStackRef, b, map[apitype.StackTagName]string{})
+	b.d.Infof(diag.Message("", "Created stack '%s'"), stack.Ref())
+
+	return stack, nil
+}
+
+func (b *sqlBackend) GetStack(ctx context.Context, stackRef backend.StackReference) (backend.Stack, error) {
+	sqlStackRef, err := b.getReference(stackRef)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := b.stackExists(ctx, b.db, sqlStackRef)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	tags, err := b.getStackTags(ctx, b.db, sqlStackRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStack(sqlStackRef, b, tags), nil
+}
+
+func (b *sqlBackend) ListStacks(
+	ctx context.Context, filter backend.ListStacksFilter, _ backend.ContinuationToken) (
+	[]backend.StackSummary, backend.ContinuationToken, error,
+) {
+	// Note that the provided stack filter is only partially honored, since organizations aren't persisted in the
+	// SQL backend.
+	query := `SELECT s.project, s.name, s.last_update, s.resource_count FROM pulumi_stacks s WHERE 1 = 1`
+	var args []interface{}
+	if filter.Project != nil {
+		query += ` AND s.project = ?`
+		args = append(args, *filter.Project)
+	}
+	if filter.TagName != nil {
+		tagQuery := `SELECT 1 FROM pulumi_stack_tags t WHERE t.project = s.project AND t.name = s.name AND t.tag = ?`
+		args = append(args, *filter.TagName)
+		if filter.TagValue != nil {
+			tagQuery += ` AND t.value = ?`
+			args = append(args, *filter.TagValue)
+		}
+		query += ` AND EXISTS (` + tagQuery + `)`
+	}
+	query += ` ORDER BY s.project, s.name`
+
+	rows, err := b.db.QueryContext(ctx, b.rebind(query), args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing stacks: %w", err)
+	}
+	defer contract.IgnoreClose(rows)
+
+	var results []backend.StackSummary
+	for rows.Next() {
+		var project, name string
+		var lastUpdate, resourceCount sql.NullInt64
+		if err := rows.Scan(&project, &name, &lastUpdate, &resourceCount); err != nil {
+			return nil, nil, fmt.Errorf("listing stacks: %w", err)
+		}
+		stackName, err := tokens.ParseStackName(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing stacks: %w", err)
+		}
+		ref := &sqlBackendReference{
+			name:           stackName,
+			project:        tokens.Name(project),
+			currentProject: b.currentProject.Load,
+		}
+		results = append(results, newSQLStackSummary(ref, lastUpdate, resourceCount))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("listing stacks: %w", err)
+	}
+
+	return results, nil, nil
+}
+
+func (b *sqlBackend) RemoveStack(ctx context.Context, stack backend.Stack, force bool) (bool, error) {
+	sqlStackRef, err := b.getReference(stack.Ref())
+	if err != nil {
+		return false, err
+	}
+
+	err = b.Lock(ctx, sqlStackRef)
+	if err != nil {
+		return false, err
+	}
+	defer b.Unlock(ctx, sqlStackRef)
+
+	var hasResources bool
+	err = b.withTx(ctx, func(tx *sql.Tx) error {
+		var resourceCount sql.NullInt64
+		err := tx.QueryRowContext(ctx, b.rebind(
+			`SELECT resource_count FROM pulumi_stacks WHERE project = ? AND name = ?`),
+			string(sqlStackRef.project), sqlStackRef.name.String()).Scan(&resourceCount)
+		if err != nil {
+			return fmt.Errorf("reading stack: %w", err)
+		}
+
+		// Don't remove stacks that still have resources.
+		if !force && resourceCount.Valid && resourceCount.Int64 > 0 {
+			hasResources = true
+			return errors.New("refusing to remove stack because it still contains resources")
+		}
+
+		for _, table := range []string{"pulumi_history", "pulumi_stack_tags", "pulumi_stacks"} {
+			_, err := tx.ExecContext(ctx, b.rebind(
+				`DELETE FROM `+table+` WHERE project = ? AND name = ?`),
+				string(sqlStackRef.project), sqlStackRef.name.String())
+			if err != nil {
+				return fmt.Errorf("removing stack: %w", err)
+			}
+		}
+		return nil
+	})
+	return hasResources, err
+}
+
+func (b *sqlBackend) RenameStack(ctx context.Context, stack backend.Stack,
+	newName tokens.QName,
+) (backend.StackReference, error) {
+	sqlStackRef, err := b.getReference(stack.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the new stack name is valid.
+	newRef, err := b.parseStackReference(string(newName))
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.Lock(ctx, sqlStackRef)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Unlock(ctx, sqlStackRef)
+
+	err = b.withTx(ctx, func(tx *sql.Tx) error {
+		// Ensure the destination stack does not already exist.
+		exists, err := b.stackExists(ctx, tx, newRef)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("a stack named %s already exists", newRef.String())
+		}
+
+		// Get the current state from the stack to be renamed.
+		chk, err := b.getCheckpoint(ctx, tx, sqlStackRef)
+		if err != nil {
+			return fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+
+		// If we have a checkpoint, we need to rename the URNs inside it to use the new stack name.
+		chk.Stack = newRef.FullyQualifiedName()
+		if chk.Latest != nil {
+			if err = edit.RenameStack(chk.Latest, newRef.name, tokens.PackageName(newRef.project)); err != nil {
+				return err
+			}
+		}
+		checkpoint, err := marshalCheckpoint(chk)
+		if err != nil {
+			return err
+		}
+
+		// Move the stack and everything that belongs to it. The lock is released as part of the move, which
+		// makes the deferred unlock of the old name a no-op.
+		_, err = tx.ExecContext(ctx, b.rebind(
+			`UPDATE pulumi_stacks SET project = ?, name = ?, checkpoint = ?, lock_id = NULL, lock_info = NULL
+			WHERE project = ? AND name = ?`),
+			string(newRef.project), newRef.name.String(), checkpoint, string(sqlStackRef.project), sqlStackRef.name.String())
+		if err != nil {
+			return fmt.Errorf("renaming stack: %w", err)
+		}
+		for _, table := range []string{"pulumi_history", "pulumi_stack_tags"} {
+			_, err := tx.ExecContext(ctx, b.rebind(
+				`UPDATE `+table+` SET project = ?, name = ? WHERE project = ? AND name = ?`),
+				string(newRef.project), newRef.name.String(), string(sqlStackRef.project), sqlStackRef.name.String())
+			if err != nil {
+				return fmt.Errorf("renaming stack: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRef, nil
+}
+
+func (b *sqlBackend) GetLatestConfiguration(ctx context.Context,
+	stack backend.Stack,
+) (config.Map, error) {
+	hist, err := b.GetHistory(ctx, stack.Ref(), 1 /*pageSize*/, 1 /*page*/)
+	if err != nil {
+		return nil, err
+	}
+	if len(hist) == 0 {
+		return nil, backend.ErrNoPreviousDeployment
+	}
+
+	return hist[0].Config, nil
+}
+
+func (b *sqlBackend) Preview(ctx context.Context, stack backend.Stack,
+	op backend.UpdateOperation, events chan<- engine.Event,
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
+	// We can skip PreviewThenPromptThenExecute and just go straight to Execute.
+	opts := backend.ApplierOptions{
+		DryRun:   true,
+		ShowLink: true,
+	}
+	return b.apply(ctx, apitype.PreviewUpdate, stack, op, opts, events)
+}
+
+func (b *sqlBackend) Update(ctx context.Context, stack backend.Stack,
+	op backend.UpdateOperation,
+) (sdkDisplay.ResourceChanges, result.Result) {
+	err := b.Lock(ctx, stack.Ref())
+	if err != nil {
+		return nil, result.FromError(err)
+	}
+	defer b.Unlock(ctx, stack.Ref())
+
+	return backend.PreviewThenPromptThenExecute(ctx, apitype.UpdateUpdate, stack, op, b.apply)
+}
+
+func (b *sqlBackend) Import(ctx context.Context, stack backend.Stack,
+	op backend.UpdateOperation, imports []deploy.Import,
+) (sdkDisplay.ResourceChanges, result.Result) {
+	err := b.Lock(ctx, stack.Ref())
+	if err != nil {
+		return nil, result.FromError(err)
+	}
+	defer b.Unlock(ctx, stack.Ref())
+
+	op.Imports = imports
+
+	if op.Opts.PreviewOnly {
+		// We can skip PreviewThenPromptThenExecute, and just go straight to Execute.
+		opts := backend.ApplierOptions{
+			DryRun:   true,
+			ShowLink: true,
+		}
+
+		op.Opts.Engine.GeneratePlan = false
+		_, changes, res := b.apply(
+			ctx, apitype.ResourceImportUpdate, stack, op, opts, nil /*events*/)
+		return changes, res
+	}
+
+	return backend.PreviewThenPromptThenExecute(ctx, apitype.ResourceImportUpdate, stack, op, b.apply)
+}
+
+func (b *sqlBackend) Refresh(ctx context.Context, stack backend.Stack,
+	op backend.UpdateOperation,
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
+	err := b.Lock(ctx, stack.Ref())
+	if err != nil {
+		return nil, nil, result.FromError(err)
+	}
+	defer b.Unlock(ctx, stack.Ref())
+
+	if op.Opts.PreviewOnly {
+		// We can skip PreviewThenPromptThenExecute, and just go straight to Execute.
+		opts := backend.ApplierOptions{
+			DryRun:   true,
+			ShowLink: true,
+		}
+
+		return b.apply(ctx, apitype.RefreshUpdate, stack, op, opts, nil /*events*/)
+	}
+
+	changes, res := backend.PreviewThenPromptThenExecute(ctx, apitype.RefreshUpdate, stack, op, b.apply)
+	return nil, changes, res
+}
+
+func (b *sqlBackend) Destroy(ctx context.Context, stack backend.Stack,
+	op backend.UpdateOperation,
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
+	err := b.Lock(ctx, stack.Ref())
+	if err != nil {
+		return nil, nil, result.FromError(err)
+	}
+	defer b.Unlock(ctx, stack.Ref())
+
+	if op.Opts.PreviewOnly {
+		// We can skip PreviewThenPromptThenExecute, and just go straight to Execute.
+		opts := backend.ApplierOptions{
+			DryRun:   true,
+			ShowLink: true,
+		}
+
+		return b.apply(ctx, apitype.DestroyUpdate, stack, op, opts, nil /*events*/)
+	}
+
+	changes, res := backend.PreviewThenPromptThenExecute(ctx, apitype.DestroyUpdate, stack, op, b.apply)
+	return nil, changes, res
+}
+
+func (b *sqlBackend) Query(ctx context.Context, op backend.QueryOperation) error {
+	return backend.RunQuery(ctx, b, op, nil /*events*/, b.newQuery)
+}
+
+func (b *sqlBackend) Watch(ctx context.Context, stk backend.Stack,
+	op backend.UpdateOperation, paths []string,
+) result.Result {
+	return backend.Watch(ctx, b, stk, op, b.apply, paths)
+}
+
+// apply actually performs the provided type of update on a SQL hosted stack.
+func (b *sqlBackend) apply(
+	ctx context.Context, kind apitype.UpdateKind, stack backend.Stack,
+	op backend.UpdateOperation, opts backend.ApplierOptions,
+	events chan<- engine.Event,
+) (*deploy.Plan, sdkDisplay.ResourceChanges, result.Result) {
+	stackRef := stack.Ref()
+	sqlStackRef, err := b.getReference(stackRef)
+	if err != nil {
+		return nil, nil, result.FromError(err)
+	}
+
+	if currentProjectContradictsWorkspace(sqlStackRef) {
+		return nil, nil, result.Errorf("provided project name %q doesn't match Pulumi.yaml", sqlStackRef.project)
+	}
+
+	actionLabel := backend.ActionLabel(kind, opts.DryRun)
+
+	if !(op.Opts.Display.JSONDisplay || op.Opts.Display.Type == display.DisplayWatch) {
+		// Print a banner so it's clear this is a SQL backed deployment.
+		fmt.Printf(op.Opts.Display.Color.Colorize(
+			colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stackRef)
+	}
+
+	// Start the update.
+	update, err := b.newUpdate(ctx, op.SecretsProvider, sqlStackRef, op)
+	if err != nil {
+		return nil, nil, result.FromError(err)
+	}
+
+	// Like the service, we use the start of a real update to pick up any metadata changes in the stack's tags.
+	if kind != apitype.PreviewUpdate && !opts.DryRun {
+		tags, err := backend.GetMergedStackTags(ctx, stack, op.Root, op.Proj, op.StackConfiguration.Config)
+		if err != nil {
+			return nil, nil, result.FromError(fmt.Errorf("getting stack tags: %w", err))
+		}
+		if err := b.UpdateStackTags(ctx, stack, tags); err != nil {
+			return nil, nil, result.FromError(err)
+		}
+	}
+
+	// Spawn a display loop to show events on the CLI.
+	displayEvents := make(chan engine.Event)
+	displayDone := make(chan bool)
+	go display.ShowEvents(
+		strings.ToLower(actionLabel), kind, stackRef.Name(), op.Proj.Name, "",
+		displayEvents, displayDone, op.Opts.Display, opts.DryRun)
+
+	// Create a separate event channel for engine events that we'll pipe to both listening streams.
+	engineEvents := make(chan engine.Event)
+
+	scope := op.Scopes.NewScope(engineEvents, opts.DryRun)
+	eventsDone := make(chan bool)
+	go func() {
+		// Pull in all events from the engine and send them to the two listeners.
+		for e := range engineEvents {
+			displayEvents <- e
+
+			// If the caller also wants to see the events, stream them there also.
+			if events != nil {
+				events <- e
+			}
+		}
+
+		close(eventsDone)
+	}()
+
+	// Create the management machinery.
+	// We only need a snapshot manager if we're doing an update.
+	var manager *backend.SnapshotManager
+	if kind != apitype.PreviewUpdate && !opts.DryRun {
+		persister := b.newSnapshotPersister(ctx, sqlStackRef)
+		manager = backend.NewSnapshotManager(persister, op.SecretsManager, update.GetTarget().Snapshot)
+	}
+	engineCtx := &engine.Context{
+		Cancel:          scope.Context(),
+		Events:          engineEvents,
+		SnapshotManager: manager,
+		BackendClient:   backend.NewBackendClient(b, op.SecretsProvider),
+	}
+
+	// Perform the update
+	start := time.Now().Unix()
+	var plan *deploy.Plan
+	var changes sdkDisplay.ResourceChanges
+	var updateErr error
+	switch kind {
+	case apitype.PreviewUpdate:
+		plan, changes, updateErr = engine.Update(update, engineCtx, op.Opts.Engine, true)
+	case apitype.UpdateUpdate:
+		_, changes, updateErr = engine.Update(update, engineCtx, op.Opts.Engine, opts.DryRun)
+	case apitype.ResourceImportUpdate:
+		_, changes, updateErr = engine.Import(update, engineCtx, op.Opts.Engine, op.Imports, opts.DryRun)
+	case apitype.RefreshUpdate:
+		plan, changes, updateErr = engine.Refresh(update, engineCtx, op.Opts.Engine, opts.DryRun)
+	case apitype.DestroyUpdate:
+		plan, changes, updateErr = engine.Destroy(update, engineCtx, op.Opts.Engine, opts.DryRun)
+	case apitype.StackImportUpdate, apitype.RenameUpdate:
+		contract.Failf("unexpected %s event", kind)
+	default:
+		contract.Failf("Unrecognized update kind: %s", kind)
+	}
+	updateRes := result.WrapIfNonNil(updateErr)
+	end := time.Now().Unix()
+
+	// Wait for the display to finish showing all the events.
+	<-displayDone
+	scope.Close() // Don't take any cancellations anymore, we're shutting down.
+	close(engineEvents)
+	if manager != nil {
+		err = manager.Close()
+		// If the snapshot manager failed to close, we should return that error.
+		// Even though all the parts of the operation have potentially succeeded, a
+		// snapshotting failure is likely to rear its head on the next
+		// operation/invocation (e.g. an invalid snapshot that fails integrity
+		// checks, or a failure to write that means the snapshot is incomplete).
+		// Reporting now should make debugging and reporting easier.
+		if err != nil {
+			return plan, changes, result.FromError(fmt.Errorf("writing snapshot: %w", err))
+		}
+	}
+
+	// Make sure the goroutine writing to displayEvents and events has exited before proceeding.
+	<-eventsDone
+	close(displayEvents)
+
+	// Save update results.
+	backendUpdateResult := backend.SucceededResult
+	if updateRes != nil {
+		backendUpdateResult = backend.FailedResult
+	}
+	info := backend.UpdateInfo{
+		Kind:            kind,
+		StartTime:       start,
+		Message:         op.M.Message,
+		Environment:     op.M.Environment,
+		Config:          update.GetTarget().Config,
+		Result:          backendUpdateResult,
+		EndTime:         end,
+		ResourceChanges: changes,
+	}
+
+	var saveErr error
+	if !opts.DryRun {
+		saveErr = b.addToHistory(ctx, sqlStackRef, info)
+	}
+
+	if updateRes != nil {
+		// We swallow saveErr as it is less important than the updateErr.
+		return plan, changes, updateRes
+	}
+
+	if saveErr != nil {
+		return plan, changes, result.FromError(fmt.Errorf("saving update info: %w", saveErr))
+	}
+
+	return plan, changes, nil
+}
+
+func (b *sqlBackend) GetHistory(
+	ctx context.Context,
+	stackRef backend.StackReference,
+	pageSize int,
+	page int,
+) ([]backend.UpdateInfo, error) {
+	sqlStackRef, err := b.getReference(stackRef)
+	if err != nil {
+		return nil, err
+	}
+	return b.getHistory(ctx, sqlStackRef, pageSize, page)
+}
+
+func (b *sqlBackend) GetLogs(ctx context.Context,
+	secretsProvider secrets.Provider, stack backend.Stack, cfg backend.StackConfiguration,
+	query operations.LogQuery,
+) ([]operations.LogEntry, error) {
+	sqlStackRef, err := b.getReference(stack.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := b.getTarget(ctx, secretsProvider, sqlStackRef, cfg.Config, cfg.Decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	return diy.GetLogsForTarget(target, query)
+}
+
+func (b *sqlBackend) ExportDeployment(ctx context.Context,
+	stk backend.Stack,
+) (*apitype.UntypedDeployment, error) {
+	sqlStackRef, err := b.getReference(stk.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	chk, err := b.getCheckpoint(ctx, b.db, sqlStackRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	return untypedDeployment(chk)
+}
+
+// ExportDeploymentForVersion exports the checkpoint that was written by the given update of the stack, as
+// numbered in the stack's history.
+func (b *sqlBackend) ExportDeploymentForVersion(ctx context.Context,
+	stk backend.Stack, version string,
+) (*apitype.UntypedDeployment, error) {
+	sqlStackRef, err := b.getReference(stk.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	chk, err := b.getHistoryCheckpoint(ctx, sqlStackRef, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return untypedDeployment(chk)
+}
+
+func untypedDeployment(chk *apitype.CheckpointV3) (*apitype.UntypedDeployment, error) {
+	data, err := encoding.JSON.Marshal(chk.Latest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apitype.UntypedDeployment{
+		Version:    3,
+		Deployment: json.RawMessage(data),
+	}, nil
+}
+
+func (b *sqlBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
+	deployment *apitype.UntypedDeployment,
+) error {
+	sqlStackRef, err := b.getReference(stk.Ref())
+	if err != nil {
+		return err
+	}
+
+	err = b.Lock(ctx, sqlStackRef)
+	if err != nil {
+		return err
+	}
+	defer b.Unlock(ctx, sqlStackRef)
+
+	return b.importDeployment(ctx, sqlStackRef, deployment)
+}
+
+// RestoreDeployment imports the given deployment and records the import in the stack's history.
+func (b *sqlBackend) RestoreDeployment(ctx context.Context, stk backend.Stack,
+	deployment *apitype.UntypedDeployment, message string,
+) error {
+	sqlStackRef, err := b.getReference(stk.Ref())
+	if err != nil {
+		return err
+	}
+
+	err = b.Lock(ctx, sqlStackRef)
+	if err != nil {
+		return err
+	}
+	defer b.Unlock(ctx, sqlStackRef)
+
+	start := time.Now().Unix()
+	if err := b.importDeployment(ctx, sqlStackRef, deployment); err != nil {
+		return err
+	}
+
+	return b.addToHistory(ctx, sqlStackRef, backend.UpdateInfo{
+		Kind:      apitype.StackImportUpdate,
+		StartTime: start,
+		Message:   message,
+		Result:    backend.SucceededResult,
+		EndTime:   time.Now().Unix(),
+	})
+}
+
+// importDeployment replaces the stack's checkpoint with the given deployment. The caller must hold the stack's lock.
+func (b *sqlBackend) importDeployment(ctx context.Context, ref *sqlBackendReference,
+	deployment *apitype.UntypedDeployment,
+) error {
+	stackName := ref.FullyQualifiedName()
+	versioned, err := stack.MarshalUntypedDeploymentToVersionedCheckpoint(stackName, deployment)
+	if err != nil {
+		return err
+	}
+	bytes, err := encoding.JSON.Marshal(versioned)
+	if err != nil {
+		return err
+	}
+	chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(encoding.JSON, bytes)
+	if err != nil {
+		return err
+	}
+
+	return b.saveCheckpoint(ctx, ref, chk)
+}
+
+func (b *sqlBackend) CurrentUser() (string, []string, *workspace.TokenInformation, error) {
+	user, err := user.Current()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return user.Username, nil, nil, nil
+}
+
+// UpdateStackTags updates the stacks's tags, replacing all existing tags.
+func (b *sqlBackend) UpdateStackTags(ctx context.Context,
+	stack backend.Stack, tags map[apitype.StackTagName]string,
+) error {
+	sqlStackRef, err := b.getReference(stack.Ref())
+	if err != nil {
+		return err
+	}
+
+	if err := b.saveStackTags(ctx, sqlStackRef, tags); err != nil {
+		return err
+	}
+	if s, ok := stack.(*sqlStack); ok {
+		s.tags = tags
+	}
+	return nil
+}
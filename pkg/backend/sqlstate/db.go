@@ -0,0 +1,270 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlstate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	_ "github.com/lib/pq" // driver for postgres://
+	user "github.com/tweekmonster/luser"
+	"golang.org/x/exp/slices"
+	_ "modernc.org/sqlite" // driver for sqlite://
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+const (
+	// SQLitePrefix is the prefix of URLs that store state in a SQLite database file.
+	SQLitePrefix = "sqlite://"
+	// PostgresPrefix is the prefix of URLs that store state in a Postgres database.
+	PostgresPrefix = "postgres://"
+	// PostgreSQLPrefix is an alternative prefix of URLs that store state in a Postgres database.
+	PostgreSQLPrefix = "postgresql://"
+)
+
+const (
+	// postgresDriver is the name of the database/sql driver for Postgres.
+	postgresDriver = "postgres"
+	// sqliteDriver is the name of the database/sql driver for SQLite.
+	sqliteDriver = "sqlite"
+)
+
+// schemaVersion is the version of the database schema written by this version of the CLI.
+const schemaVersion = 1
+
+// schema creates the tables used by the backend. The statements are portable between SQLite and Postgres.
+//
+// Stacks are keyed by project and stack name. Each stack row holds the latest checkpoint, a summary of it so
+// that stacks can be listed without reading every checkpoint, and the lock held on the stack, if any.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS pulumi_meta (
+		name TEXT NOT NULL PRIMARY KEY,
+		value TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS pulumi_stacks (
+		project TEXT NOT NULL,
+		name TEXT NOT NULL,
+		checkpoint TEXT NOT NULL,
+		last_update BIGINT,
+		resource_count BIGINT,
+		lock_id TEXT,
+		lock_info TEXT,
+		PRIMARY KEY (project, name)
+	)`,
+	`CREATE TABLE IF NOT EXISTS pulumi_stack_tags (
+		project TEXT NOT NULL,
+		name TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY (project, name, tag)
+	)`,
+	`CREATE TABLE IF NOT EXISTS pulumi_history (
+		project TEXT NOT NULL,
+		name TEXT NOT NULL,
+		version BIGINT NOT NULL,
+		info TEXT NOT NULL,
+		checkpoint TEXT NOT NULL,
+		PRIMARY KEY (project, name, version)
+	)`,
+}
+
+// queryer is the subset of methods shared by *sql.DB and *sql.Tx.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// parseDatabaseURL returns the database/sql driver name and data source name for a backend URL.
+func parseDatabaseURL(originalURL string) (driver string, dsn string, _ error) {
+	switch {
+	case strings.HasPrefix(originalURL, PostgresPrefix), strings.HasPrefix(originalURL, PostgreSQLPrefix):
+		// lib/pq understands postgres:// URLs directly.
+		return postgresDriver, originalURL, nil
+	case strings.HasPrefix(originalURL, SQLitePrefix):
+		dsn, err := sqliteDSN(originalURL)
+		return sqliteDriver, dsn, err
+	default:
+		redacted, _, _ := redactURL(originalURL)
+		return "", "", fmt.Errorf("sql URL %s has an illegal prefix; expected one of: %s, %s, %s",
+			redacted, SQLitePrefix, PostgresPrefix, PostgreSQLPrefix)
+	}
+}
+
+// redactURL returns the given backend URL without the password it contains, if any, so that it can be displayed
+// and stored. It also returns the password, and whether the URL contained one at all.
+func redactURL(originalURL string) (redacted string, password string, hasPassword bool) {
+	if !strings.HasPrefix(originalURL, PostgresPrefix) && !strings.HasPrefix(originalURL, PostgreSQLPrefix) {
+		return originalURL, "", false
+	}
+	u, err := url.Parse(originalURL)
+	if err != nil {
+		return originalURL, "", false
+	}
+
+	if u.User != nil {
+		password, hasPassword = u.User.Password()
+		if hasPassword {
+			u.User = url.User(u.User.Username())
+		}
+	}
+	// Postgres also accepts the password as a query parameter.
+	query := u.Query()
+	if query.Has("password") {
+		if !hasPassword {
+			password, hasPassword = query.Get("password"), true
+		}
+		query.Del("password")
+		u.RawQuery = query.Encode()
+	}
+	if !hasPassword {
+		return originalURL, "", false
+	}
+	return u.String(), password, true
+}
+
+// withPassword returns the given postgres:// URL with the given password. URLs that don't name a user are returned
+// unchanged, as the password would be ignored.
+func withPassword(originalURL, password string) string {
+	u, err := url.Parse(originalURL)
+	if err != nil || u.User == nil || u.User.Username() == "" {
+		return originalURL
+	}
+	u.User = url.UserPassword(u.User.Username(), password)
+	return u.String()
+}
+
+// sqliteDSN converts a sqlite:// URL into a data source name for the SQLite driver. Like file:// URLs for the
+// diy backend, the path may be relative or start with ~.
+func sqliteDSN(originalURL string) (string, error) {
+	path, rawQuery, _ := strings.Cut(strings.TrimPrefix(originalURL, SQLitePrefix), "?")
+	if path == "" {
+		return "", fmt.Errorf("sql URL %s is missing a database path", originalURL)
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("parsing the provided URL: %w", err)
+	}
+
+	if strings.HasPrefix(path, "~") {
+		usr, err := user.Current()
+		if err != nil {
+			return "", fmt.Errorf("Could not determine current user to resolve `sqlite://~` path.: %w", err)
+		}
+		path = filepath.Join(usr.HomeDir, strings.TrimPrefix(path, "~"))
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("An IO error occurred while building the absolute path: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("creating database directory: %w", err)
+	}
+
+	// Wait for other processes rather than failing immediately when the database is busy, and take the write
+	// lock at the start of each transaction so that concurrent read-modify-write transactions can't deadlock.
+	if !slices.ContainsFunc(query["_pragma"], func(p string) bool { return strings.HasPrefix(p, "busy_timeout") }) {
+		query.Add("_pragma", "busy_timeout(10000)")
+	}
+	if !query.Has("_txlock") {
+		query.Set("_txlock", "immediate")
+	}
+	return "file:" + filepath.ToSlash(path) + "?" + query.Encode(), nil
+}
+
+// rebind rewrites the ? placeholders in a query into the form expected by the backend's driver. Question marks in
+// string literals and quoted identifiers are left alone.
+func (b *sqlBackend) rebind(query string) string {
+	if b.driver != postgresDriver {
+		return query
+	}
+
+	var sb strings.Builder
+	n := 0
+	// quote is the quote character of the literal or identifier we're in, if any. A doubled quote is an escaped
+	// quote, which leaves and immediately re-enters the literal, so it needs no special handling.
+	var quote rune
+	for _, c := range query {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			sb.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// withTx runs fn in a transaction, committing it if fn succeeds and rolling it back otherwise.
+func (b *sqlBackend) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		contract.IgnoreError(tx.Rollback())
+		return err
+	}
+	return tx.Commit()
+}
+
+// ensureSchema creates the backend's tables if they don't exist yet and checks that the database was not
+// written by a newer version of the CLI.
+func (b *sqlBackend) ensureSchema(ctx context.Context) error {
+	return b.withTx(ctx, func(tx *sql.Tx) error {
+		for _, stmt := range schema {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("creating schema: %w", err)
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, b.rebind(
+			`INSERT INTO pulumi_meta (name, value) VALUES ('version', ?) ON CONFLICT (name) DO NOTHING`),
+			strconv.Itoa(schemaVersion))
+		if err != nil {
+			return fmt.Errorf("writing schema version: %w", err)
+		}
+
+		var value string
+		err = tx.QueryRowContext(ctx, `SELECT value FROM pulumi_meta WHERE name = 'version'`).Scan(&value)
+		if err != nil {
+			return fmt.Errorf("reading schema version: %w", err)
+		}
+		version, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("corrupt store: invalid schema version %q: %w", value, err)
+		}
+		if version > schemaVersion {
+			return fmt.Errorf(
+				"state store unsupported: schema version (%d) is not supported "+
+					"by this version of the Pulumi CLI", version)
+		}
+		return nil
+	})
+}
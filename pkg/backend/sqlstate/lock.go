@@ -0,0 +1,376 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlstate
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// defaultLockLease is how long a lock remains valid after its holder last renewed it. The holder renews it in the
+// background, so only a lock whose holder has crashed or lost its connection ever expires.
+const defaultLockLease = 5 * time.Minute
+
+// lockInfo describes the holder of a stack lock and the state of its lease. It is stored alongside the lock.
+type lockInfo struct {
+	Pid       int       `json:"pid"`
+	Username  string    `json:"username"`
+	Hostname  string    `json:"hostname"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Renewed is the last time the holder of the lock renewed its lease.
+	Renewed time.Time `json:"renewed,omitempty"`
+	// LeaseDuration is how long the lock remains valid after it was last renewed. Locks without a lease never
+	// expire.
+	LeaseDuration time.Duration `json:"leaseDuration,omitempty"`
+}
+
+func newLockInfo(lease time.Duration) (*lockInfo, error) {
+	u, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &lockInfo{
+		Pid:           os.Getpid(),
+		Username:      u.Username,
+		Hostname:      hostname,
+		Timestamp:     now,
+		Renewed:       now,
+		LeaseDuration: lease,
+	}, nil
+}
+
+// parseLockInfo parses the lock_info column of a stack. It returns nil if the column is empty or unreadable.
+func parseLockInfo(holder sql.NullString) *lockInfo {
+	var l lockInfo
+	if !holder.Valid || json.Unmarshal([]byte(holder.String), &l) != nil {
+		return nil
+	}
+	return &l
+}
+
+// leaseAge returns how long ago the lock was last renewed.
+func (l *lockInfo) leaseAge(now time.Time) time.Duration {
+	renewed := l.Renewed
+	if renewed.IsZero() {
+		renewed = l.Timestamp
+	}
+	return now.Sub(renewed)
+}
+
+// expired returns true if the lock has a lease and that lease has not been renewed in time.
+func (l *lockInfo) expired(now time.Time) bool {
+	return l.LeaseDuration > 0 && l.leaseAge(now) > l.LeaseDuration
+}
+
+// stale returns true if the lock was taken by a process on this host that is no longer running.
+func (l *lockInfo) stale() bool {
+	hostname, err := os.Hostname()
+	if err != nil || hostname != l.Hostname || l.Pid <= 0 {
+		return false
+	}
+	alive, err := process.PidExists(int32(l.Pid))
+	return err == nil && !alive
+}
+
+// describeLock returns a human readable description of who holds a lock and the state of its lease.
+func describeLock(holder sql.NullString, now time.Time) string {
+	l := parseLockInfo(holder)
+	if l == nil {
+		return "held by an unknown process"
+	}
+	desc := fmt.Sprintf("created by %v@%v (pid %v) at %v",
+		l.Username, l.Hostname, l.Pid, l.Timestamp.Format(time.RFC3339))
+	if l.LeaseDuration > 0 {
+		desc += fmt.Sprintf(", lease renewed %v ago (expires after %v)",
+			l.leaseAge(now).Round(time.Second), l.LeaseDuration)
+	}
+	if l.stale() {
+		desc += fmt.Sprintf(" [stale: process %v is no longer running]", l.Pid)
+	}
+	return desc
+}
+
+// Lock takes the lock on a stack.
+//
+// Rather than lock files, the lock is a column of the stack's row. It is taken with a single conditional UPDATE,
+// which the database applies atomically under a row lock, so at most one backend can hold the lock at a time. A
+// lock whose lease has expired, or whose holder is known to no longer be running, is reclaimed.
+//
+// Locks are reentrant: taking a lock this backend already holds succeeds, and the lock is only released once
+// Unlock has been called as many times as Lock.
+func (b *sqlBackend) Lock(ctx context.Context, stackRef backend.StackReference) error {
+	ref, err := b.getReference(stackRef)
+	if err != nil {
+		return err
+	}
+	key := string(ref.FullyQualifiedName())
+
+	b.locksMutex.Lock()
+	defer b.locksMutex.Unlock()
+	if held, has := b.locks[key]; has {
+		held.count++
+		return nil
+	}
+
+	info, err := newLockInfo(b.lockLease)
+	if err != nil {
+		return err
+	}
+	acquired, err := b.tryLock(ctx, ref, info)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		// Either the stack doesn't exist or someone else holds the lock.
+		lockID, holder, err := b.readLock(ctx, ref)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		l := parseLockInfo(holder)
+		if l == nil || (!l.expired(now) && !l.stale()) {
+			return errors.New("the stack is currently locked. Either wait for the other process to end " +
+				"or remove the lock with `pulumi cancel`.\n  " + describeLock(holder, now))
+		}
+
+		// Only clear the lock as we read it. If its holder renewed or replaced it in the meantime, it's still live.
+		cleared, err := b.clearStaleLock(ctx, ref, lockID.String, holder.String)
+		if err != nil {
+			return err
+		}
+		if !cleared {
+			return errors.New("the stack is currently locked. Either wait for the other process to end " +
+				"or remove the lock with `pulumi cancel`.")
+		}
+		b.d.Infof(diag.Message("", "Reclaimed stale lock on %v held by %v@%v (lease not renewed for %v)"),
+			ref, l.Username, l.Hostname, l.leaseAge(now).Round(time.Second))
+
+		acquired, err = b.tryLock(ctx, ref, info)
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			return errors.New("the stack is currently locked. Either wait for the other process to end " +
+				"or remove the lock with `pulumi cancel`.")
+		}
+	}
+
+	if b.locks == nil {
+		b.locks = make(map[string]*heldLock)
+	}
+	b.locks[key] = &heldLock{count: 1, stop: b.startLeaseRenewal(ref, info)}
+	return nil
+}
+
+// tryLock takes the lock on a stack if nobody holds it, and returns whether it did.
+func (b *sqlBackend) tryLock(ctx context.Context, ref *sqlBackendReference, info *lockInfo) (bool, error) {
+	content, err := json.Marshal(info)
+	if err != nil {
+		return false, err
+	}
+	res, err := b.db.ExecContext(ctx, b.rebind(
+		`UPDATE pulumi_stacks SET lock_id = ?, lock_info = ?
+		WHERE project = ? AND name = ? AND lock_id IS NULL`),
+		b.lockID, string(content), string(ref.project), ref.name.String())
+	if err != nil {
+		return false, fmt.Errorf("locking stack: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return err == nil && n == 1, nil
+}
+
+// readLock reads the ID and holder of the lock on a stack.
+func (b *sqlBackend) readLock(ctx context.Context, ref *sqlBackendReference) (sql.NullString, sql.NullString, error) {
+	var lockID, holder sql.NullString
+	err := b.db.QueryRowContext(ctx, b.rebind(
+		`SELECT lock_id, lock_info FROM pulumi_stacks WHERE project = ? AND name = ?`),
+		string(ref.project), ref.name.String()).Scan(&lockID, &holder)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return lockID, holder, fmt.Errorf("no stack named '%s' found", ref)
+		}
+		return lockID, holder, fmt.Errorf("reading lock: %w", err)
+	}
+	return lockID, holder, nil
+}
+
+// clearLock releases the lock on a stack if it has the given ID.
+func (b *sqlBackend) clearLock(ctx context.Context, ref *sqlBackendReference, lockID string) error {
+	_, err := b.db.ExecContext(ctx, b.rebind(
+		`UPDATE pulumi_stacks SET lock_id = NULL, lock_info = NULL
+		WHERE project = ? AND name = ? AND lock_id = ?`),
+		string(ref.project), ref.name.String(), lockID)
+	if err != nil {
+		return fmt.Errorf("releasing lock: %w", err)
+	}
+	return nil
+}
+
+// clearStaleLock releases a lock that was found to be stale, as long as it still has the given ID and holder. Lease
+// renewals rewrite the holder, so a lock that was renewed since it was read is left in place. It returns whether the
+// lock was released.
+func (b *sqlBackend) clearStaleLock(
+	ctx context.Context, ref *sqlBackendReference, lockID, holder string,
+) (bool, error) {
+	res, err := b.db.ExecContext(ctx, b.rebind(
+		`UPDATE pulumi_stacks SET lock_id = NULL, lock_info = NULL
+		WHERE project = ? AND name = ? AND lock_id = ? AND lock_info = ?`),
+		string(ref.project), ref.name.String(), lockID, holder)
+	if err != nil {
+		return false, fmt.Errorf("releasing lock: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return err == nil && n == 1, nil
+}
+
+// startLeaseRenewal starts a background goroutine that renews the lease on a lock held by this backend. It returns
+// a function that stops renewing the lease.
+func (b *sqlBackend) startLeaseRenewal(ref *sqlBackendReference, info *lockInfo) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		// Renew well before the lease runs out so that a slow write doesn't let it lapse.
+		ticker := time.NewTicker(info.LeaseDuration / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !b.renewLease(ctx, ref, info) {
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// renewLease records a fresh renewal time on a lock held by this backend. It returns false if this backend no
+// longer holds the lock, for example because it was removed with `pulumi cancel`, in which case renewal should
+// stop. The update is conditional on the lock ID, so renewing can never take a lock that was released.
+func (b *sqlBackend) renewLease(ctx context.Context, ref *sqlBackendReference, info *lockInfo) bool {
+	info.Renewed = time.Now()
+	content, err := json.Marshal(info)
+	contract.AssertNoErrorf(err, "marshaling lock info")
+
+	res, err := b.db.ExecContext(ctx, b.rebind(
+		`UPDATE pulumi_stacks SET lock_info = ? WHERE project = ? AND name = ? AND lock_id = ?`),
+		string(content), string(ref.project), ref.name.String(), b.lockID)
+	if err != nil {
+		if ctx.Err() == nil {
+			b.d.Warningf(diag.Message("", "failed to renew the lease on the lock of %v: %v"), ref, err)
+		}
+		return true
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		if ctx.Err() == nil {
+			b.d.Warningf(diag.Message("", "the lock on %v was removed by another process"), ref)
+		}
+		return false
+	}
+	return true
+}
+
+// Unlock releases the lock on a stack if it is held by this backend, and this is the last of its holders.
+func (b *sqlBackend) Unlock(ctx context.Context, stackRef backend.StackReference) {
+	ref, err := b.getReference(stackRef)
+	if err == nil {
+		key := string(ref.FullyQualifiedName())
+
+		b.locksMutex.Lock()
+		held, has := b.locks[key]
+		if has {
+			held.count--
+			if held.count > 0 {
+				b.locksMutex.Unlock()
+				return
+			}
+			delete(b.locks, key)
+		}
+		b.locksMutex.Unlock()
+
+		if has {
+			held.stop()
+		}
+		err = b.clearLock(ctx, ref, b.lockID)
+	}
+	if err != nil {
+		b.d.Errorf(
+			diag.Message("", "there was a problem releasing the lock on %v, manual clean up may be required: %v"),
+			stackRef, err)
+	}
+}
+
+func (b *sqlBackend) CancelCurrentUpdate(ctx context.Context, stackRef backend.StackReference) error {
+	ref, err := b.getReference(stackRef)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.db.ExecContext(ctx, b.rebind(
+		`UPDATE pulumi_stacks SET lock_id = NULL, lock_info = NULL WHERE project = ? AND name = ?`),
+		string(ref.project), ref.name.String())
+	if err != nil {
+		return fmt.Errorf("cancelling update: %w", err)
+	}
+	return nil
+}
+
+// CancelStaleLocks removes the lock on a stack if its lease has expired or its owning process is known to no
+// longer be running, leaving a live lock in place. It returns the number of locks removed.
+func (b *sqlBackend) CancelStaleLocks(ctx context.Context, stackRef backend.StackReference) (int, error) {
+	ref, err := b.getReference(stackRef)
+	if err != nil {
+		return 0, err
+	}
+
+	lockID, holder, err := b.readLock(ctx, ref)
+	if err != nil {
+		return 0, err
+	}
+	l := parseLockInfo(holder)
+	if !lockID.Valid || l == nil || (!l.expired(time.Now()) && !l.stale()) {
+		return 0, nil
+	}
+	cleared, err := b.clearStaleLock(ctx, ref, lockID.String, holder.String)
+	if err != nil || !cleared {
+		return 0, err
+	}
+	return 1, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlstate
+
+import (
+	"context"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+)
+
+// sqlSnapshotPersister is a simple SnapshotManager implementation that persists snapshots
+// to the stacks table.
+type sqlSnapshotPersister struct {
+	// TODO[pulumi/pulumi#12593]:
+	// Remove this once SnapshotPersister is updated to take a context.
+	ctx context.Context
+
+	ref     *sqlBackendReference
+	backend *sqlBackend
+}
+
+func (sp *sqlSnapshotPersister) Save(snapshot *deploy.Snapshot) error {
+	return sp.backend.saveStack(sp.ctx, sp.ref, snapshot)
+}
+
+func (b *sqlBackend) newSnapshotPersister(
+	ctx context.Context,
+	ref *sqlBackendReference,
+) backend.SnapshotPersister {
+	return &sqlSnapshotPersister{ctx: ctx, ref: ref, backend: b}
+}
@@ -0,0 +1,421 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlstate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+type sqlQuery struct {
+	root string
+	proj *workspace.Project
+}
+
+func (q *sqlQuery) GetRoot() string {
+	return q.root
+}
+
+func (q *sqlQuery) GetProject() *workspace.Project {
+	return q.proj
+}
+
+// update is an implementation of engine.Update backed by SQL state.
+type update struct {
+	root    string
+	proj    *workspace.Project
+	target  *deploy.Target
+	backend *sqlBackend
+}
+
+func (u *update) GetRoot() string {
+	return u.root
+}
+
+func (u *update) GetProject() *workspace.Project {
+	return u.proj
+}
+
+func (u *update) GetTarget() *deploy.Target {
+	return u.target
+}
+
+func (b *sqlBackend) newQuery(
+	ctx context.Context,
+	op backend.QueryOperation,
+) (engine.QueryInfo, error) {
+	return &sqlQuery{root: op.Root, proj: op.Proj}, nil
+}
+
+func (b *sqlBackend) newUpdate(
+	ctx context.Context,
+	secretsProvider secrets.Provider,
+	ref *sqlBackendReference,
+	op backend.UpdateOperation,
+) (*update, error) {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	// Construct the deployment target.
+	target, err := b.getTarget(ctx, secretsProvider, ref,
+		op.StackConfiguration.Config, op.StackConfiguration.Decrypter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Construct and return a new update.
+	return &update{
+		root:    op.Root,
+		proj:    op.Proj,
+		target:  target,
+		backend: b,
+	}, nil
+}
+
+func (b *sqlBackend) getTarget(
+	ctx context.Context,
+	secretsProvider secrets.Provider,
+	ref *sqlBackendReference,
+	cfg config.Map,
+	dec config.Decrypter,
+) (*deploy.Target, error) {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+	stack, err := b.GetStack(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if stack == nil {
+		return nil, fmt.Errorf("no stack named '%s' found", ref)
+	}
+	snapshot, err := stack.Snapshot(ctx, secretsProvider)
+	if err != nil {
+		return nil, err
+	}
+	return &deploy.Target{
+		Name:         ref.Name(),
+		Organization: "organization", // like diy, there are no organizations, so we always say it's "organization"
+		Config:       cfg,
+		Decrypter:    dec,
+		Snapshot:     snapshot,
+	}, nil
+}
+
+var errStackNotFound = errors.New("stack does not exist")
+
+// stackExists returns true if the stacks table has a row for the given stack.
+func (b *sqlBackend) stackExists(ctx context.Context, q queryer, ref *sqlBackendReference) (bool, error) {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	var exists bool
+	err := q.QueryRowContext(ctx, b.rebind(
+		`SELECT EXISTS (SELECT 1 FROM pulumi_stacks WHERE project = ? AND name = ?)`),
+		string(ref.project), ref.name.String()).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	return exists, nil
+}
+
+func (b *sqlBackend) getSnapshot(ctx context.Context,
+	secretsProvider secrets.Provider, ref *sqlBackendReference,
+) (*deploy.Snapshot, error) {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	checkpoint, err := b.getCheckpoint(ctx, b.db, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	// Materialize an actual snapshot object.
+	snapshot, err := stack.DeserializeCheckpoint(ctx, secretsProvider, checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the snapshot passes verification before returning it, to catch bugs early.
+	if !backend.DisableIntegrityChecking {
+		if err := snapshot.VerifyIntegrity(); err != nil {
+			return nil, fmt.Errorf("snapshot integrity failure; refusing to use it: %w", err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// getCheckpoint loads the latest checkpoint of the given stack.
+func (b *sqlBackend) getCheckpoint(
+	ctx context.Context, q queryer, ref *sqlBackendReference,
+) (*apitype.CheckpointV3, error) {
+	var checkpoint string
+	err := q.QueryRowContext(ctx, b.rebind(
+		`SELECT checkpoint FROM pulumi_stacks WHERE project = ? AND name = ?`),
+		string(ref.project), ref.name.String()).Scan(&checkpoint)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errStackNotFound
+		}
+		return nil, err
+	}
+
+	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(encoding.JSON, []byte(checkpoint))
+}
+
+// marshalCheckpoint serializes a checkpoint in the form stored in the database.
+func marshalCheckpoint(chk *apitype.CheckpointV3) (string, error) {
+	chkJSON, err := encoding.JSON.Marshal(chk)
+	if err != nil {
+		return "", fmt.Errorf("marshalling checkpoint: %w", err)
+	}
+	byts, err := encoding.JSON.Marshal(apitype.VersionedCheckpoint{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Checkpoint: chkJSON,
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshalling checkpoint: %w", err)
+	}
+	return string(byts), nil
+}
+
+// saveCheckpoint replaces the latest checkpoint of the given stack.
+//
+// The write only succeeds if the stack is unlocked or locked by this backend, so a process that has lost its lock,
+// for example to `pulumi cancel`, can't overwrite the state written by the new holder of the lock.
+func (b *sqlBackend) saveCheckpoint(ctx context.Context, ref *sqlBackendReference, chk *apitype.CheckpointV3) error {
+	checkpoint, err := marshalCheckpoint(chk)
+	if err != nil {
+		return err
+	}
+
+	var lastUpdate, resourceCount sql.NullInt64
+	if chk.Latest != nil {
+		if t := chk.Latest.Manifest.Time; !t.IsZero() {
+			lastUpdate = sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+		}
+		resourceCount = sql.NullInt64{Int64: int64(len(chk.Latest.Resources)), Valid: true}
+	}
+
+	res, err := b.db.ExecContext(ctx, b.rebind(
+		`UPDATE pulumi_stacks SET checkpoint = ?, last_update = ?, resource_count = ?
+		WHERE project = ? AND name = ? AND (lock_id IS NULL OR lock_id = ?)`),
+		checkpoint, lastUpdate, resourceCount, string(ref.project), ref.name.String(), b.lockID)
+	if err != nil {
+		return fmt.Errorf("An IO error occurred while writing the new snapshot: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		exists, existsErr := b.stackExists(ctx, b.db, ref)
+		if existsErr == nil && !exists {
+			return errStackNotFound
+		}
+		return fmt.Errorf("writing the new snapshot: the stack is locked by another process")
+	}
+
+	logging.V(7).Infof("Saved stack %s checkpoint", ref.FullyQualifiedName())
+	return nil
+}
+
+func (b *sqlBackend) saveStack(ctx context.Context, ref *sqlBackendReference, snap *deploy.Snapshot) error {
+	contract.Requiref(ref != nil, "ref", "ref was nil")
+
+	chk := &apitype.CheckpointV3{Stack: ref.FullyQualifiedName()}
+	if snap != nil {
+		dep, err := stack.SerializeDeployment(ctx, snap, false /* showSecrets */)
+		if err != nil {
+			return fmt.Errorf("serializing deployment: %w", err)
+		}
+		chk.Latest = dep
+	}
+
+	if err := b.saveCheckpoint(ctx, ref, chk); err != nil {
+		return err
+	}
+
+	if !backend.DisableIntegrityChecking {
+		// Finally, *after* writing the checkpoint, check the integrity.  This is done afterwards so that we write
+		// out the checkpoint since it may contain resource state updates.  But we will warn the user that the
+		// checkpoint is already written and might be bad.
+		if verifyerr := snap.VerifyIntegrity(); verifyerr != nil {
+			return fmt.Errorf(
+				"%s: snapshot integrity failure; it was already written, but is invalid: %w",
+				ref.FullyQualifiedName(), verifyerr)
+		}
+	}
+
+	return nil
+}
+
+// getHistory returns stored update history. The first element of the result will be
+// the most recent update record.
+func (b *sqlBackend) getHistory(
+	ctx context.Context,
+	ref *sqlBackendReference,
+	pageSize int, page int,
+) ([]backend.UpdateInfo, error) {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	query := `SELECT version, info FROM pulumi_history WHERE project = ? AND name = ? ORDER BY version DESC`
+	args := []interface{}{string(ref.project), ref.name.String()}
+	if pageSize > 0 {
+		if page < 1 {
+			page = 1
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, pageSize, (page-1)*pageSize)
+	}
+
+	rows, err := b.db.QueryContext(ctx, b.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	defer contract.IgnoreClose(rows)
+
+	var updates []backend.UpdateInfo
+	for rows.Next() {
+		var version int
+		var info string
+		if err := rows.Scan(&version, &info); err != nil {
+			return nil, fmt.Errorf("reading history: %w", err)
+		}
+
+		var update backend.UpdateInfo
+		if err := encoding.JSON.Unmarshal([]byte(info), &update); err != nil {
+			return nil, fmt.Errorf("reading history entry %d: %w", version, err)
+		}
+		update.Version = version
+		updates = append(updates, update)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	return updates, nil
+}
+
+// getHistoryCheckpoint returns the checkpoint written by the given version of the stack's history.
+func (b *sqlBackend) getHistoryCheckpoint(
+	ctx context.Context, ref *sqlBackendReference, version string,
+) (*apitype.CheckpointV3, error) {
+	v, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	var checkpoint string
+	err = b.db.QueryRowContext(ctx, b.rebind(
+		`SELECT checkpoint FROM pulumi_history WHERE project = ? AND name = ? AND version = ?`),
+		string(ref.project), ref.name.String(), v).Scan(&checkpoint)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("version %d of stack '%s' not found", v, ref)
+		}
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(encoding.JSON, []byte(checkpoint))
+}
+
+// addToHistory saves the UpdateInfo along with a copy of the stack's current checkpoint.
+func (b *sqlBackend) addToHistory(ctx context.Context, ref *sqlBackendReference, update backend.UpdateInfo) error {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	return b.withTx(ctx, func(tx *sql.Tx) error {
+		var version int
+		err := tx.QueryRowContext(ctx, b.rebind(
+			`SELECT COALESCE(MAX(version), 0) + 1 FROM pulumi_history WHERE project = ? AND name = ?`),
+			string(ref.project), ref.name.String()).Scan(&version)
+		if err != nil {
+			return fmt.Errorf("reading history: %w", err)
+		}
+
+		update.Version = version
+		info, err := encoding.JSON.Marshal(&update)
+		if err != nil {
+			return err
+		}
+
+		// Copy the checkpoint in the same statement so that it matches the stack as the update left it.
+		_, err = tx.ExecContext(ctx, b.rebind(
+			`INSERT INTO pulumi_history (project, name, version, info, checkpoint)
+			SELECT project, name, CAST(? AS BIGINT), CAST(? AS TEXT), checkpoint FROM pulumi_stacks WHERE project = ? AND name = ?`),
+			version, string(info), string(ref.project), ref.name.String())
+		return err
+	})
+}
+
+// getStackTags reads the tags for the given stack.
+func (b *sqlBackend) getStackTags(
+	ctx context.Context, q queryer, ref *sqlBackendReference,
+) (map[apitype.StackTagName]string, error) {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	rows, err := q.QueryContext(ctx, b.rebind(
+		`SELECT tag, value FROM pulumi_stack_tags WHERE project = ? AND name = ?`),
+		string(ref.project), ref.name.String())
+	if err != nil {
+		return nil, fmt.Errorf("could not read stack tags: %w", err)
+	}
+	defer contract.IgnoreClose(rows)
+
+	tags := map[apitype.StackTagName]string{}
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("could not read stack tags: %w", err)
+		}
+		tags[name] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not read stack tags: %w", err)
+	}
+	return tags, nil
+}
+
+// saveStackTags replaces the tags for the given stack.
+func (b *sqlBackend) saveStackTags(
+	ctx context.Context, ref *sqlBackendReference, tags map[apitype.StackTagName]string,
+) error {
+	contract.Requiref(ref != nil, "ref", "must not be nil")
+
+	return b.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, b.rebind(
+			`DELETE FROM pulumi_stack_tags WHERE project = ? AND name = ?`),
+			string(ref.project), ref.name.String())
+		if err != nil {
+			return fmt.Errorf("could not write stack tags: %w", err)
+		}
+		for name, value := range tags {
+			_, err := tx.ExecContext(ctx, b.rebind(
+				`INSERT INTO pulumi_stack_tags (project, name, tag, value) VALUES (?, ?, ?, ?)`),
+				string(ref.project), ref.name.String(), name, value)
+			if err != nil {
+				return fmt.Errorf("could not write stack tags: %w", err)
+			}
+		}
+		return nil
+	})
+}
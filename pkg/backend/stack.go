@@ -50,9 +50,10 @@ type Stack interface {
 	Update(ctx context.Context, op UpdateOperation) (display.ResourceChanges, result.Result)
 	// Import resources into this stack.
 	Import(ctx context.Context, op UpdateOperation, imports []deploy.Import) (display.ResourceChanges, result.Result)
-	// Refresh this stack's state from the cloud provider.
-	Refresh(ctx context.Context, op UpdateOperation) (display.ResourceChanges, result.Result)
-	Destroy(ctx context.Context, op UpdateOperation) (display.ResourceChanges, result.Result)
+	// Refresh this stack's state from the cloud provider. If only a preview is run, the plan it generated is returned.
+	Refresh(ctx context.Context, op UpdateOperation) (*deploy.Plan, display.ResourceChanges, result.Result)
+	// Destroy this stack's resources. If only a preview is run, the plan it generated is returned.
+	Destroy(ctx context.Context, op UpdateOperation) (*deploy.Plan, display.ResourceChanges, result.Result)
 	// Watch this stack.
 	Watch(ctx context.Context, op UpdateOperation, paths []string) result.Result
 
@@ -105,12 +106,16 @@ func ImportStack(ctx context.Context, s Stack, op UpdateOperation,
 }
 
 // RefreshStack refresh's the stack's state from the cloud provider.
-func RefreshStack(ctx context.Context, s Stack, op UpdateOperation) (display.ResourceChanges, result.Result) {
+func RefreshStack(
+	ctx context.Context, s Stack, op UpdateOperation,
+) (*deploy.Plan, display.ResourceChanges, result.Result) {
 	return s.Backend().Refresh(ctx, s, op)
 }
 
 // DestroyStack destroys all of this stack's resources.
-func DestroyStack(ctx context.Context, s Stack, op UpdateOperation) (display.ResourceChanges, result.Result) {
+func DestroyStack(
+	ctx context.Context, s Stack, op UpdateOperation,
+) (*deploy.Plan, display.ResourceChanges, result.Result) {
 	return s.Backend().Destroy(ctx, s, op)
 }
 
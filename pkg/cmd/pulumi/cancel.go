@@ -15,20 +15,35 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
+	"github.com/pulumi/pulumi/pkg/v3/backend/sqlstate"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
 )
 
+// staleLockCanceller is implemented by the backends that support `pulumi cancel --stale-only`.
+type staleLockCanceller interface {
+	CancelStaleLocks(ctx context.Context, stackRef backend.StackReference) (int, error)
+}
+
+var (
+	_ staleLockCanceller = diy.Backend(nil)
+	_ staleLockCanceller = sqlstate.Backend(nil)
+)
+
 func newCancelCmd() *cobra.Command {
 	var yes bool
+	var staleOnly bool
 	var stack string
 	cmd := &cobra.Command{
 		Use:   "cancel [<stack-name>]",
@@ -41,7 +56,10 @@ func newCancelCmd() *cobra.Command {
 			"inconsistent state if a resource operation was pending when the update was canceled.\n" +
 			"\n" +
 			"After this command completes successfully, the stack will be ready for further\n" +
-			"updates.",
+			"updates.\n" +
+			"\n" +
+			"For DIY and SQL backends, `--stale-only` removes only the locks whose lease has expired or\n" +
+			"whose owning process is no longer running, and leaves live updates untouched.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			// Use the stack provided or, if missing, default to the current one.
@@ -62,8 +80,22 @@ func newCancelCmd() *cobra.Command {
 				return err
 			}
 
-			// Ensure the user really wants to do this.
 			stackName := s.Ref().Name().String()
+			if staleOnly {
+				lb, ok := s.Backend().(staleLockCanceller)
+				if !ok {
+					return errors.New("--stale-only is only supported by DIY and SQL backends")
+				}
+
+				removed, err := lb.CancelStaleLocks(ctx, s.Ref())
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Removed %d stale lock(s) for '%s'\n", removed, stackName)
+				return nil
+			}
+
+			// Ensure the user really wants to do this.
 			prompt := fmt.Sprintf("This will irreversibly cancel the currently running update for '%s'!", stackName)
 			if cmdutil.Interactive() && (!yes && !confirmPrompt(prompt, stackName, opts)) {
 				return result.FprintBailf(os.Stdout, "confirmation declined")
@@ -86,6 +118,9 @@ func newCancelCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVarP(
 		&yes, "yes", "y", false,
 		"Skip confirmation prompts, and proceed with cancellation anyway")
+	cmd.PersistentFlags().BoolVar(
+		&staleOnly, "stale-only", false,
+		"Only remove locks that have expired or whose owning process is no longer running (DIY and SQL backends only)")
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
@@ -21,12 +21,10 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"regexp"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/nbutton23/zxcvbn-go"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
@@ -34,6 +32,7 @@ import (
 	"github.com/pulumi/esc/cmd/esc/cli"
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
@@ -121,6 +120,7 @@ func newConfigCmd() *cobra.Command {
 	cmd.AddCommand(newConfigRefreshCmd(&stack))
 	cmd.AddCommand(newConfigCopyCmd(&stack))
 	cmd.AddCommand(newConfigEnvCmd(&stack))
+	cmd.AddCommand(newConfigSchemaCmd())
 
 	return cmd
 }
@@ -651,7 +651,11 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 				v = config.NewValue(value)
 
 				// If we saved a plaintext configuration value, and --plaintext was not passed, warn the user.
-				if !plaintext && looksLikeSecret(key, value) {
+				looksSecret, err := looksLikeSecret(project, key, value)
+				if err != nil {
+					return err
+				}
+				if !plaintext && looksSecret {
 					return fmt.Errorf("config value for '%s' looks like a secret; "+
 						"rerun with --secret to encrypt it, or --plaintext if you meant to store in plaintext",
 						key)
@@ -1148,36 +1152,20 @@ func getConfig(ctx context.Context, stack backend.Stack, key config.Key, path, j
 	return fmt.Errorf("configuration key '%s' not found for stack '%s'", prettyKey(key), stack.Ref())
 }
 
-// keyPattern is the regular expression a configuration key must match before we check (and error) if we think
-// it is a password
-var keyPattern = regexp.MustCompile("(?i)passwd|pass|password|pwd|secret|token")
-
-const (
-	// maxEntropyCheckLength is the maximum length of a possible secret for entropy checking.
-	maxEntropyCheckLength = 16
-	// entropyThreshold is the total entropy threshold a potential secret needs to pass before being flagged.
-	entropyThreshold = 80.0
-	// entropyCharThreshold is the per-char entropy threshold a potential secret needs to pass before being flagged.
-	entropyPerCharThreshold = 3.0
-)
-
-// looksLikeSecret returns true if a configuration value "looks" like a secret. This is always going to be a heuristic
-// that suffers from false positives, but is better (a) than our prior approach of unconditionally printing a warning
-// for all plaintext values, and (b)  to be paranoid about such things. Inspired by the gas linter and securego project.
-func looksLikeSecret(k config.Key, v string) bool {
-	if !keyPattern.MatchString(k.Name()) {
-		return false
+// looksLikeSecret returns true if a configuration value "looks" like a secret to the project's secret scanner, or to
+// the default one if the project doesn't configure it. This is always going to be a heuristic that suffers from false
+// positives, but is better (a) than our prior approach of unconditionally printing a warning for all plaintext values,
+// and (b) to be paranoid about such things. Inspired by the gas linter and securego project.
+func looksLikeSecret(project *workspace.Project, k config.Key, v string) (bool, error) {
+	scanner, err := getSecretScannerOption(project)
+	if err != nil {
+		return false, err
 	}
-
-	if len(v) > maxEntropyCheckLength {
-		v = v[:maxEntropyCheckLength]
+	if scanner == nil {
+		scanner, err = deploy.NewSecretScanner("", 0, "", nil)
+		contract.AssertNoErrorf(err, "the default secret scanner is valid")
 	}
-
-	// Compute the strength use the resulting entropy to flag whether this looks like a secret.
-	info := zxcvbn.PasswordStrength(v, nil)
-	entropyPerChar := info.Entropy / float64(len(v))
-	return info.Entropy >= entropyThreshold ||
-		(info.Entropy >= (entropyThreshold/2) && entropyPerChar >= entropyPerCharThreshold)
+	return scanner.Check(k.Name(), v) != "", nil
 }
 
 func getAndSaveSecretsManager(
@@ -1379,17 +1367,22 @@ func getStackConfigurationFromProjectStack(
 	// If there are no secrets in the configuration, we should never use the decrypter, so it is safe to return
 	// one which panics if it is used. This provides for some nice UX in the common case (since, for example, building
 	// the correct decrypter for the diy backend would involve prompting for a passphrase)
-	if !needsCrypter(workspaceStack.Config, pulumiEnv) {
-		return backend.StackConfiguration{
-			Environment: pulumiEnv,
-			Config:      workspaceStack.Config,
-			Decrypter:   config.NewPanicCrypter(),
-		}, nil
+	var crypter config.Decrypter = config.NewPanicCrypter()
+	if needsCrypter(workspaceStack.Config, pulumiEnv) {
+		crypter, err = sm.Decrypter()
+		if err != nil {
+			return backend.StackConfiguration{}, fmt.Errorf("getting configuration decrypter: %w", err)
+		}
 	}
 
-	crypter, err := sm.Decrypter()
-	if err != nil {
-		return backend.StackConfiguration{}, fmt.Errorf("getting configuration decrypter: %w", err)
+	// Check the stack's config against the project's config schema before the program gets to see it.
+	if project != nil {
+		if err := workspace.ValidateStackConfigSchema(project, workspaceStack.Config, crypter); err != nil {
+			if path, pathErr := getProjectStackPath(stack); pathErr == nil {
+				return backend.StackConfiguration{}, fmt.Errorf("%s: %w", path, err)
+			}
+			return backend.StackConfiguration{}, fmt.Errorf("stack '%s': %w", stack.Ref(), err)
+		}
 	}
 
 	return backend.StackConfiguration{
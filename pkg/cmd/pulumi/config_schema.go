@@ -0,0 +1,174 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newConfigSchemaCmd() *cobra.Command {
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "List the configuration keys that the project declares",
+		Long: "Lists the configuration keys declared in the `config` block of Pulumi.yaml, along with their types,\n" +
+			"constraints and descriptions. Stack configuration values are checked against these declarations\n" +
+			"before a program runs.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			project, _, err := readProject()
+			if err != nil {
+				return err
+			}
+
+			return listConfigSchema(os.Stdout, project, jsonOut)
+		}),
+	}
+
+	cmd.Flags().BoolVarP(
+		&jsonOut, "json", "j", false,
+		"Emit output as JSON")
+
+	return cmd
+}
+
+// listConfigSchema writes the configuration keys that a project declares, with their object properties on rows of
+// their own.
+func listConfigSchema(stdout io.Writer, project *workspace.Project, jsonOut bool) error {
+	if jsonOut {
+		config := project.Config
+		if config == nil {
+			config = map[string]workspace.ProjectConfigType{}
+		}
+		return fprintJSON(stdout, config)
+	}
+
+	if len(project.Config) == 0 {
+		fmt.Fprintln(stdout, "This project does not declare any configuration keys.")
+		return nil
+	}
+
+	keys := make([]string, 0, len(project.Config))
+	for key := range project.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var rows []cmdutil.TableRow
+	for _, key := range keys {
+		configType := project.Config[key]
+
+		var details []string
+		if configType.Secret {
+			details = append(details, "secret")
+		}
+		if configType.Default != nil {
+			details = append(details, fmt.Sprintf("default: %v", configType.Default))
+		}
+		if configType.Value != nil {
+			details = append(details, fmt.Sprintf("value: %v", configType.Value))
+		}
+		details = append(details, describeConfigConstraints(configType.Items, &configType.ProjectConfigConstraints)...)
+
+		rows = append(rows, cmdutil.TableRow{Columns: []string{
+			key,
+			workspace.InferFullTypeName(configType.TypeName(), configType.Items),
+			strings.Join(details, ", "),
+			configType.Description,
+		}})
+		rows = appendConfigPropertyRows(rows, key, &configType.ProjectConfigConstraints)
+	}
+
+	fprintTable(stdout, cmdutil.Table{
+		Headers: []string{"KEY", "TYPE", "CONSTRAINTS", "DESCRIPTION"},
+		Rows:    rows,
+	}, nil)
+	return nil
+}
+
+// appendConfigPropertyRows appends a row for each of the declared properties of an object config value at the given
+// path, and for their own properties in turn.
+func appendConfigPropertyRows(
+	rows []cmdutil.TableRow, path string, constraints *workspace.ProjectConfigConstraints,
+) []cmdutil.TableRow {
+	names := make([]string, 0, len(constraints.Properties))
+	for name := range constraints.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyType := constraints.Properties[name]
+
+		var details []string
+		for _, required := range constraints.Required {
+			if required == name {
+				details = append(details, "required")
+				break
+			}
+		}
+		details = append(details,
+			describeConfigConstraints(propertyType.Items, &propertyType.ProjectConfigConstraints)...)
+
+		propertyPath := path + "." + name
+		rows = append(rows, cmdutil.TableRow{Columns: []string{
+			propertyPath,
+			workspace.InferFullTypeName(propertyType.Type, propertyType.Items),
+			strings.Join(details, ", "),
+			propertyType.Description,
+		}})
+		rows = appendConfigPropertyRows(rows, propertyPath, &propertyType.ProjectConfigConstraints)
+	}
+	return rows
+}
+
+// describeConfigConstraints describes the constraints on a config value, and those on its items if it is an array.
+func describeConfigConstraints(
+	itemsType *workspace.ProjectConfigItemsType, constraints *workspace.ProjectConfigConstraints,
+) []string {
+	var details []string
+	if len(constraints.Enum) > 0 {
+		values := make([]string, len(constraints.Enum))
+		for i, e := range constraints.Enum {
+			values[i] = fmt.Sprintf("%v", e)
+		}
+		details = append(details, "one of: "+strings.Join(values, " | "))
+	}
+	if constraints.Pattern != "" {
+		details = append(details, "pattern: "+constraints.Pattern)
+	}
+	if constraints.Minimum != nil {
+		details = append(details, fmt.Sprintf(">= %v", *constraints.Minimum))
+	}
+	if constraints.Maximum != nil {
+		details = append(details, fmt.Sprintf("<= %v", *constraints.Maximum))
+	}
+	if itemsType != nil {
+		for _, detail := range describeConfigConstraints(itemsType.Items, &itemsType.ProjectConfigConstraints) {
+			details = append(details, "items "+detail)
+		}
+	}
+	return details
+}
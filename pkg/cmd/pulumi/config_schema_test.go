@@ -0,0 +1,108 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+const configSchemaProjectYAML = `name: test
+runtime: yaml
+config:
+  aws:region: us-west-2
+  size:
+    type: string
+    description: The size of the instances.
+    enum: [small, large]
+    default: small
+  names:
+    type: array
+    items:
+      type: string
+      pattern: ^[a-z]+$
+  database:
+    type: object
+    description: The database to connect to.
+    required: [host]
+    properties:
+      host:
+        type: string
+        description: The host name of the database.
+      port:
+        type: integer
+        minimum: 1
+        maximum: 65535
+`
+
+func TestListConfigSchema(t *testing.T) {
+	t.Parallel()
+
+	project, err := workspace.LoadProjectBytes([]byte(configSchemaProjectYAML), "Pulumi.yaml", encoding.YAML)
+	require.NoError(t, err)
+
+	var stdout bytes.Buffer
+	err = listConfigSchema(&stdout, project, false)
+	require.NoError(t, err)
+
+	const expected = `KEY            TYPE           CONSTRAINTS                            DESCRIPTION
+aws:region                    value: us-west-2
+database       object                                                The database to connect to.
+database.host  string         required                               The host name of the database.
+database.port  integer        >= 1, <= 65535
+names          array<string>  items pattern: ^[a-z]+$
+size           string         default: small, one of: small | large  The size of the instances.
+`
+	// The table pads every column, including the last, so ignore trailing whitespace.
+	lines := strings.Split(cleanStdout(stdout.String()), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	assert.Equal(t, expected, strings.Join(lines, "\n"))
+}
+
+func TestListConfigSchemaJSON(t *testing.T) {
+	t.Parallel()
+
+	project, err := workspace.LoadProjectBytes([]byte(configSchemaProjectYAML), "Pulumi.yaml", encoding.YAML)
+	require.NoError(t, err)
+
+	var stdout bytes.Buffer
+	err = listConfigSchema(&stdout, project, true)
+	require.NoError(t, err)
+
+	var schema map[string]workspace.ProjectConfigType
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &schema))
+	assert.Equal(t, []interface{}{"small", "large"}, schema["size"].Enum)
+	assert.Equal(t, []string{"host"}, schema["database"].Required)
+	assert.Equal(t, "The host name of the database.", schema["database"].Properties["host"].Description)
+}
+
+func TestListConfigSchemaEmpty(t *testing.T) {
+	t.Parallel()
+
+	var stdout bytes.Buffer
+	err := listConfigSchema(&stdout, &workspace.Project{Name: "test"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "This project does not declare any configuration keys.\n", stdout.String())
+}
@@ -18,16 +18,27 @@ import (
 	"fmt"
 	"strings"
 
+	gosecrets "gocloud.dev/secrets"
+
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/age"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/pgp"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/deepcopy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
+func init() {
+	// Support age:// and pgp:// secrets providers, on top of the cloud key management services that gocloud.dev
+	// supports.
+	gosecrets.DefaultURLMux().RegisterKeeper(age.Scheme, &age.URLOpener{})
+	gosecrets.DefaultURLMux().RegisterKeeper(pgp.Scheme, &pgp.URLOpener{})
+}
+
 func getStackEncrypter(s backend.Stack, ps *workspace.ProjectStack) (config.Encrypter, bool, error) {
 	sm, needsSave, err := getStackSecretsManager(s, ps, nil)
 	if err != nil {
@@ -116,7 +127,9 @@ func needsSaveProjectStackAfterSecretManger(stack backend.Stack,
 
 func validateSecretsProvider(typ string) error {
 	kind := strings.SplitN(typ, ":", 2)[0]
-	supportedKinds := []string{"default", "passphrase", "awskms", "azurekeyvault", "gcpkms", "hashivault"}
+	supportedKinds := []string{
+		"default", "passphrase", "awskms", "azurekeyvault", "gcpkms", "hashivault", "age", "pgp",
+	}
 	for _, supportedKind := range supportedKinds {
 		if kind == supportedKind {
 			return nil
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -32,6 +33,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -58,11 +60,18 @@ func newDestroyCmd() *cobra.Command {
 	var diffDisplay bool
 	var eventLogPath string
 	var parallel int
+	var parallelFor []string
+	var planFilePath string
+	var planSigningKeyPath string
+	var planVerifyKey string
 	var previewOnly bool
 	var refresh string
+	var savePlanFilePath string
+	var showSecrets bool
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
+	var showCriticalPath bool
 	var skipPreview bool
 	var suppressOutputs bool
 	var suppressProgress bool
@@ -70,6 +79,8 @@ func newDestroyCmd() *cobra.Command {
 	var yes bool
 	var targets *[]string
 	var targetDependents bool
+	var excludes []string
+	var excludeDependents bool
 	var excludeProtected bool
 	var continueOnError bool
 
@@ -110,6 +121,13 @@ func newDestroyCmd() *cobra.Command {
 						"must be passed in to proceed when running in non-interactive mode"))
 			}
 
+			if savePlanFilePath != "" && !previewOnly {
+				return result.FromError(errors.New("--save-plan requires --preview-only"))
+			}
+			if planSigningKeyPath != "" && savePlanFilePath == "" {
+				return result.FromError(errors.New("--plan-signing-key requires --save-plan"))
+			}
+
 			opts, err := updateFlagsToOptions(interactive, skipPreview, yes, previewOnly)
 			if err != nil {
 				return result.FromError(err)
@@ -125,6 +143,7 @@ func newDestroyCmd() *cobra.Command {
 				ShowConfig:           showConfig,
 				ShowReplacementSteps: showReplacementSteps,
 				ShowSameResources:    showSames,
+				ShowCriticalPath:     showCriticalPath,
 				SuppressOutputs:      suppressOutputs,
 				SuppressProgress:     suppressProgress,
 				IsInteractive:        interactive,
@@ -143,10 +162,13 @@ func newDestroyCmd() *cobra.Command {
 			}
 
 			if remoteArgs.remote {
+				if savePlanFilePath != "" {
+					return result.FromError(errors.New("--save-plan is not supported with --remote"))
+				}
 				err = validateUnsupportedRemoteFlags(false, nil, false, "", jsonDisplay, nil,
 					nil, refresh, showConfig, false, showReplacementSteps, showSames, false,
 					suppressOutputs, "default", targets, nil, nil,
-					targetDependents, "", stackConfigFile)
+					targetDependents, excludes, excludeDependents, planFilePath, stackConfigFile)
 				if err != nil {
 					return result.FromError(err)
 				}
@@ -193,7 +215,7 @@ func newDestroyCmd() *cobra.Command {
 				return result.FromError(err)
 			}
 
-			m, err := getUpdateMetadata(message, root, execKind, execAgent, false, cmd.Flags())
+			m, err := getUpdateMetadata(message, root, execKind, execAgent, planFilePath != "", cmd.Flags())
 			if err != nil {
 				return result.FromError(fmt.Errorf("gathering environment metadata: %w", err))
 			}
@@ -246,6 +268,14 @@ func newDestroyCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
+			parallelForOption, err := getParallelForOption(proj, parallelFor)
+			if err != nil {
+				return result.FromError(err)
+			}
+			retryPolicyOption, err := getRetryPolicyOption(proj)
+			if err != nil {
+				return result.FromError(err)
+			}
 
 			if len(*targets) > 0 && excludeProtected {
 				return result.FromError(errors.New("You cannot specify --target and --exclude-protected"))
@@ -272,19 +302,33 @@ func newDestroyCmd() *cobra.Command {
 
 			opts.Engine = engine.UpdateOptions{
 				Parallel:                  parallel,
+				ParallelFor:               parallelForOption,
+				RetryPolicy:               retryPolicyOption,
 				Debug:                     debug,
 				Refresh:                   refreshOption,
 				Targets:                   deploy.NewUrnTargets(targetUrns),
 				TargetDependents:          targetDependents,
+				Excludes:                  deploy.NewUrnTargets(excludes),
+				ExcludeDependents:         excludeDependents,
 				UseLegacyDiff:             useLegacyDiff(),
 				DisableProviderPreview:    disableProviderPreview(),
 				DisableResourceReferences: disableResourceReferences(),
 				DisableOutputValues:       disableOutputValues(),
+				GeneratePlan:              hasExperimentalCommands() || savePlanFilePath != "",
 				Experimental:              hasExperimentalCommands(),
 				ContinueOnError:           continueOnError,
 			}
 
-			_, res := s.Destroy(ctx, backend.UpdateOperation{
+			if planFilePath != "" {
+				verifyKeyPath := planVerifyKeyPath(planVerifyKey, proj, root)
+				plan, err := readPlan(planFilePath, s, proj, cfg, verifyKeyPath, decrypter, encrypter)
+				if err != nil {
+					return result.FromError(err)
+				}
+				opts.Engine.Plan = plan
+			}
+
+			plan, _, res := s.Destroy(ctx, backend.UpdateOperation{
 				Proj:               proj,
 				Root:               root,
 				M:                  m,
@@ -295,6 +339,23 @@ func newDestroyCmd() *cobra.Command {
 				Scopes:             backend.CancellationScopes,
 			})
 
+			if res == nil && savePlanFilePath != "" {
+				if err = writePlan(savePlanFilePath, plan, s, proj, encrypter, showSecrets, planSigningKeyPath); err != nil {
+					return result.FromError(err)
+				}
+
+				// Write out message on how to use the plan (if not writing out --json)
+				if !jsonDisplay {
+					var buf bytes.Buffer
+					fprintf(&buf, "Destroy plan written to '%s'", savePlanFilePath)
+					fprintf(
+						&buf,
+						"\nRun `pulumi destroy --plan='%s'` to constrain the destroy to the deletes planned by this preview",
+						savePlanFilePath)
+					cmdutil.Diag().Infof(diag.RawMessage("" /*urn*/, buf.String()))
+				}
+			}
+
 			if res == nil && protectedCount > 0 && !jsonDisplay {
 				fmt.Printf("All unprotected resources were destroyed. There are still %d protected resources"+
 					" associated with this stack.\n", protectedCount)
@@ -351,6 +412,15 @@ func newDestroyCmd() *cobra.Command {
 		"Allows destroying of dependent targets discovered but not specified in --target list")
 	cmd.PersistentFlags().BoolVar(&excludeProtected, "exclude-protected", false, "Do not destroy protected resources."+
 		" Destroy all other resources.")
+	cmd.PersistentFlags().StringArrayVar(
+		&excludes, "exclude", []string{},
+		"Specify a resource URN to ignore. These resources will not be destroyed."+
+			" Nor will the resources that they depend on."+
+			" Multiple resources can be specified using --exclude urn1 --exclude urn2."+
+			" Wildcards (*, **) are also supported")
+	cmd.PersistentFlags().BoolVar(
+		&excludeDependents, "exclude-dependents", false,
+		"Allows ignoring of dependent resources discovered but not specified in --exclude list")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().BoolVar(
@@ -362,9 +432,30 @@ func newDestroyCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism).")
+	cmd.PersistentFlags().StringArrayVar(
+		&parallelFor, "parallel-for", []string{},
+		"Allow at most N resource operations at once for a resource package or type token, as <package or type>=N."+
+			" For example, --parallel-for aws=4. May be repeated")
 	cmd.PersistentFlags().BoolVar(
 		&previewOnly, "preview-only", false,
 		"Only show a preview of the destroy, but don't perform the destroy itself")
+	cmd.PersistentFlags().StringVar(
+		&planFilePath, "plan", "",
+		"Path to a plan file to use for the destroy. The destroy will not delete resources "+
+			"that the plan does not, or resources that have been replaced since the plan was made.")
+	cmd.PersistentFlags().StringVar(
+		&savePlanFilePath, "save-plan", "",
+		"Save the deletes proposed by the preview to a plan file at the given path. "+
+			"Requires --preview-only")
+	cmd.PersistentFlags().StringVar(
+		&planSigningKeyPath, "plan-signing-key", "",
+		"Path to a PEM-encoded ed25519 private key to sign the plan file with. Requires --save-plan")
+	cmd.PersistentFlags().StringVar(
+		&planVerifyKey, "plan-verify-key", "",
+		"Path to a PEM-encoded ed25519 public key. The plan file must be signed with the matching "+
+			"private key. Defaults to the project's planVerifyKey option")
+	cmd.Flags().BoolVarP(
+		&showSecrets, "show-secrets", "", false, "Emit secrets in plaintext in the plan file. Defaults to `false`")
 	cmd.PersistentFlags().StringVarP(
 		&refresh, "refresh", "r", "",
 		"Refresh the state of the stack's resources before this update")
@@ -378,6 +469,10 @@ func newDestroyCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&showSames, "show-sames", false,
 		"Show resources that don't need to be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().BoolVar(
+		&showCriticalPath, "show-critical-path", false,
+		"Show the chain of resource operations that determined how long the deployment took, how long each "+
+			"operation waited and worked, and how many operations ran in parallel")
 	cmd.PersistentFlags().BoolVarP(
 		&skipPreview, "skip-preview", "f", false,
 		"Do not calculate a preview before performing the destroy")
@@ -0,0 +1,319 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newDriftCmd() *cobra.Command {
+	var stackName string
+	var jsonOut bool
+	var parallel int
+	var debug bool
+
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Args:  cmdutil.NoArgs,
+		Short: "Check the resources in a stack for drift",
+		Long: "Check the resources in a stack for drift.\n" +
+			"\n" +
+			"This command reads the current state of every resource in the stack from its provider and\n" +
+			"compares it to the state recorded in the stack. Each resource is reported as in sync, drifted\n" +
+			"(along with the properties that changed), or deleted outside of Pulumi. Unlike `pulumi refresh`,\n" +
+			"the stack's state is never changed.\n" +
+			"\n" +
+			"The command exits with a non-zero exit code if any resource has drifted or been deleted. Pass\n" +
+			"--json to print a machine-readable report.",
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			ctx := cmd.Context()
+			opts := display.Options{
+				Color:         cmdutil.GetGlobalColorization(),
+				IsInteractive: cmdutil.Interactive(),
+				Type:          display.DisplayProgress,
+				Debug:         debug,
+			}
+			if jsonOut {
+				// Keep stdout for the report.
+				opts.Stdout = os.Stderr
+				opts.IsInteractive = false
+			}
+
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			drift, err := detectDrift(ctx, s, opts, engine.UpdateOptions{
+				Parallel:                  parallel,
+				Debug:                     debug,
+				UseLegacyDiff:             useLegacyDiff(),
+				DisableProviderPreview:    disableProviderPreview(),
+				DisableResourceReferences: disableResourceReferences(),
+				DisableOutputValues:       disableOutputValues(),
+				Experimental:              hasExperimentalCommands(),
+			})
+			if err != nil {
+				if errors.Is(err, context.Canceled) {
+					return result.FromError(errors.New("drift detection cancelled"))
+				}
+				return PrintEngineResult(result.FromError(err))
+			}
+
+			if jsonOut {
+				if err := printJSON(makeDriftReportJSON(s.Ref().Name().String(), drift)); err != nil {
+					return result.FromError(err)
+				}
+			} else {
+				renderDrift(os.Stdout, drift, opts)
+			}
+
+			for _, d := range drift {
+				if d.Status != engine.DriftInSync {
+					return result.Bail()
+				}
+			}
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(
+		&stackConfigFile, "config-file", "",
+		"Use the configuration values in the specified file rather than detecting the file name")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false,
+		"Emit the drift report as JSON")
+	cmd.PersistentFlags().IntVarP(
+		&parallel, "parallel", "p", defaultParallel,
+		"Allow P resources to be read in parallel at once (1 for no parallelism).")
+	cmd.PersistentFlags().BoolVarP(
+		&debug, "debug", "d", false,
+		"Print detailed debugging output during resource operations")
+
+	return cmd
+}
+
+// driftUpdateInfo is the engine.UpdateInfo for a drift check.
+type driftUpdateInfo struct {
+	root    string
+	project *workspace.Project
+	target  *deploy.Target
+}
+
+func (u *driftUpdateInfo) GetRoot() string                { return u.root }
+func (u *driftUpdateInfo) GetProject() *workspace.Project { return u.project }
+func (u *driftUpdateInfo) GetTarget() *deploy.Target      { return u.target }
+
+// detectDrift checks the resources of the given stack for drift, showing the progress of the check with the given
+// display options. The stack is not locked and its state is not changed.
+func detectDrift(
+	ctx context.Context, s backend.Stack, opts display.Options, engineOpts engine.UpdateOptions,
+) ([]engine.ResourceDrift, error) {
+	proj, root, err := readProject()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, sm, err := getStackConfiguration(ctx, s, proj, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting stack configuration: %w", err)
+	}
+	decrypter, err := sm.Decrypter()
+	if err != nil {
+		return nil, fmt.Errorf("getting stack decrypter: %w", err)
+	}
+	encrypter, err := sm.Encrypter()
+	if err != nil {
+		return nil, fmt.Errorf("getting stack encrypter: %w", err)
+	}
+	stackName := s.Ref().Name()
+	err = workspace.ValidateStackConfigAndApplyProjectConfig(
+		ctx, stackName.String(), proj, cfg.Environment, cfg.Config, encrypter, decrypter)
+	if err != nil {
+		return nil, fmt.Errorf("validating stack config: %w", err)
+	}
+
+	snap, err := s.Snapshot(ctx, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, fmt.Errorf("getting snapshot: %w", err)
+	}
+
+	info := &driftUpdateInfo{
+		root:    root,
+		project: proj,
+		target: &deploy.Target{
+			Name:      stackName,
+			Config:    cfg.Config,
+			Decrypter: decrypter,
+			Snapshot:  snap,
+		},
+	}
+
+	events := make(chan engine.Event)
+	displayDone := make(chan bool)
+	go display.ShowEvents("drift", apitype.RefreshUpdate, stackName, proj.Name, "", events, displayDone, opts,
+		true /*isPreview*/)
+
+	scope := backend.CancellationScopes.NewScope(events, true /*isPreview*/)
+	drift, err := engine.Drift(info, &engine.Context{
+		Cancel:        scope.Context(),
+		Events:        events,
+		BackendClient: backend.NewBackendClient(s.Backend(), stack.DefaultSecretsProvider),
+	}, engineOpts)
+
+	<-displayDone
+	scope.Close()
+	close(events)
+	return drift, err
+}
+
+// renderDrift writes a description of the given drift to the given writer. Resources that are in sync are only
+// counted.
+func renderDrift(w io.Writer, drift []engine.ResourceDrift, opts display.Options) {
+	counts := make(map[engine.DriftStatus]int)
+	var b bytes.Buffer
+	for _, d := range drift {
+		counts[d.Status]++
+		switch d.Status {
+		case engine.DriftDrifted:
+			fmt.Fprintf(&b, "%s%s%s\n", deploy.Prefix(deploy.OpUpdate, true /*done*/), d.Old.URN, colors.Reset)
+			if diff := d.Old.Outputs.Diff(d.New.Outputs); diff != nil {
+				display.PrintObjectDiff(&b, *diff, nil,
+					false /*planning*/, 1, false /*summary*/, false /*truncateOutput*/, false /*debug*/)
+			}
+		case engine.DriftDeleted:
+			fmt.Fprintf(&b, "%s%s%s\n", deploy.Prefix(deploy.OpDelete, true /*done*/), d.Old.URN, colors.Reset)
+		}
+	}
+
+	if counts[engine.DriftDrifted]+counts[engine.DriftDeleted] == 0 {
+		fmt.Fprintf(&b, "No drift detected in %d resources\n", counts[engine.DriftInSync])
+	} else {
+		fmt.Fprintf(&b, "\nResources: %d in sync, %d drifted, %d deleted\n",
+			counts[engine.DriftInSync], counts[engine.DriftDrifted], counts[engine.DriftDeleted])
+	}
+	fmt.Fprint(w, opts.Color.Colorize(b.String()))
+}
+
+// driftReportJSON is the shape of the --json output of `pulumi drift`. While we can add fields to this structure in
+// the future, we should not change existing fields.
+type driftReportJSON struct {
+	Stack     string              `json:"stack"`
+	Summary   map[string]int      `json:"summary"`
+	Resources []resourceDriftJSON `json:"resources"`
+}
+
+// resourceDriftJSON describes the drift of a single resource.
+type resourceDriftJSON struct {
+	URN    string `json:"urn"`
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	// Diff lists the output properties that changed, for drifted resources.
+	Diff []propertyDriftJSON `json:"diff,omitempty"`
+}
+
+// propertyDriftJSON describes the change to a single output property of a drifted resource. Secret values are
+// masked.
+type propertyDriftJSON struct {
+	Path string      `json:"path"`
+	Kind string      `json:"kind"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+func makeDriftReportJSON(stackName string, drift []engine.ResourceDrift) driftReportJSON {
+	report := driftReportJSON{
+		Stack: stackName,
+		Summary: map[string]int{
+			string(engine.DriftInSync):  0,
+			string(engine.DriftDrifted): 0,
+			string(engine.DriftDeleted): 0,
+		},
+		Resources: make([]resourceDriftJSON, 0, len(drift)),
+	}
+	for _, d := range drift {
+		report.Summary[string(d.Status)]++
+
+		res := resourceDriftJSON{
+			URN:    string(d.Old.URN),
+			Type:   string(d.Old.Type),
+			ID:     string(d.Old.ID),
+			Status: string(d.Status),
+		}
+		paths := make([]string, 0, len(d.Diff))
+		for path := range d.Diff {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			res.Diff = append(res.Diff, propertyDriftJSON{
+				Path: path,
+				Kind: d.Diff[path].Kind.String(),
+				Old:  driftPropertyValue(d.Old.Outputs, path),
+				New:  driftPropertyValue(d.New.Outputs, path),
+			})
+		}
+		report.Resources = append(report.Resources, res)
+	}
+	return report
+}
+
+// driftPropertyValue returns the value at the given path in the given property map as plain data, with secrets
+// masked, or nil if there is no such value.
+func driftPropertyValue(props resource.PropertyMap, path string) interface{} {
+	p, err := resource.ParsePropertyPath(path)
+	if err != nil {
+		return nil
+	}
+	v, ok := p.Get(resource.NewObjectProperty(props))
+	if !ok {
+		return nil
+	}
+	return v.MapRepl(nil, func(v resource.PropertyValue) (interface{}, bool) {
+		switch {
+		case v.IsSecret():
+			return "[secret]", true
+		case v.IsComputed() || v.IsOutput() && !v.OutputValue().Known:
+			return "[unknown]", true
+		case v.IsOutput():
+			return v.OutputValue().Element.MapRepl(nil, nil), true
+		}
+		return nil, false
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+)
+
+func TestMakeDriftReportJSON(t *testing.T) {
+	t.Parallel()
+
+	state := func(name string, outputs resource.PropertyMap) *resource.State {
+		return &resource.State{
+			URN:     resource.URN("urn:pulumi:dev::proj::t:m:T::" + name),
+			Type:    "t:m:T",
+			ID:      resource.ID(name + "-id"),
+			Custom:  true,
+			Outputs: outputs,
+		}
+	}
+
+	old := state("drifted", resource.PropertyMap{
+		"size":     resource.NewNumberProperty(1),
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		"tags":     resource.NewObjectProperty(resource.PropertyMap{"env": resource.NewStringProperty("dev")}),
+	})
+	new := state("drifted", resource.PropertyMap{
+		"size":     resource.NewNumberProperty(2),
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter3")),
+		"tags":     resource.NewObjectProperty(resource.PropertyMap{}),
+	})
+	drift := []engine.ResourceDrift{
+		{Status: engine.DriftInSync, Old: state("same", nil), New: state("same", nil)},
+		{
+			Status: engine.DriftDrifted,
+			Old:    old,
+			New:    new,
+			Diff:   plugin.NewDetailedDiffFromObjectDiff(old.Outputs.Diff(new.Outputs), false),
+		},
+		{Status: engine.DriftDeleted, Old: state("gone", nil)},
+	}
+
+	b, err := json.Marshal(makeDriftReportJSON("dev", drift))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"stack": "dev",
+		"summary": {"in-sync": 1, "drifted": 1, "deleted": 1},
+		"resources": [
+			{"urn": "urn:pulumi:dev::proj::t:m:T::same", "type": "t:m:T", "id": "same-id", "status": "in-sync"},
+			{
+				"urn": "urn:pulumi:dev::proj::t:m:T::drifted", "type": "t:m:T", "id": "drifted-id", "status": "drifted",
+				"diff": [
+					{"path": "password", "kind": "update", "old": "[secret]", "new": "[secret]"},
+					{"path": "size", "kind": "update", "old": 1, "new": 2},
+					{"path": "tags.env", "kind": "delete", "old": "dev"}
+				]
+			},
+			{"urn": "urn:pulumi:dev::proj::t:m:T::gone", "type": "t:m:T", "id": "gone-id", "status": "deleted"}
+		]
+	}`, string(b))
+}
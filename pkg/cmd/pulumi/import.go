@@ -932,8 +932,14 @@ func newImportCmd() *cobra.Command {
 				return result.FromError(fmt.Errorf("validating stack config: %w", configErr))
 			}
 
+			secretScannerOption, err := getSecretScannerOption(proj)
+			if err != nil {
+				return result.FromError(err)
+			}
+
 			opts.Engine = engine.UpdateOptions{
 				Parallel:      parallel,
+				SecretScanner: secretScannerOption,
 				Debug:         debug,
 				UseLegacyDiff: useLegacyDiff(),
 				Experimental:  hasExperimentalCommands(),
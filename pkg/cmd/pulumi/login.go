@@ -26,6 +26,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
+	"github.com/pulumi/pulumi/pkg/v3/backend/sqlstate"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
@@ -84,7 +85,13 @@ func newLoginCmd() *cobra.Command {
 			"\n" +
 			"Azure Blob:\n" +
 			"\n" +
-			"    $ pulumi login azblob://my-pulumi-state-bucket\n",
+			"    $ pulumi login azblob://my-pulumi-state-bucket\n" +
+			"\n" +
+			"[PREVIEW] State may also be stored in a SQL database, which provides atomic locking and fast stack listing.\n" +
+			"Pass a `sqlite://<path>` URL to use a local SQLite database file, or a Postgres connection URL:\n" +
+			"\n" +
+			"    $ pulumi login sqlite://~/.pulumi/state.db\n" +
+			"    $ pulumi login postgres://user@db.acmecorp.com:5432/pulumi\n",
 		Args: cmdutil.MaximumNArgs(1),
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -145,6 +152,11 @@ func newLoginCmd() *cobra.Command {
 				if defaultOrg != "" {
 					return errors.New("unable to set default org for this type of backend")
 				}
+			} else if sqlstate.IsSQLBackendURL(cloudURL) {
+				be, err = sqlstate.Login(ctx, cmdutil.Diag(), cloudURL, project)
+				if defaultOrg != "" {
+					return errors.New("unable to set default org for this type of backend")
+				}
 			} else {
 				be, err = loginToCloud(ctx, cloudURL, project, insecure, displayOptions)
 				// if the user has specified a default org to associate with the backend
@@ -184,13 +196,13 @@ func newLoginCmd() *cobra.Command {
 
 func validateCloudBackendType(typ string) error {
 	kind := strings.SplitN(typ, ":", 2)[0]
-	supportedKinds := []string{"azblob", "gs", "s3", "file", "https", "http"}
+	supportedKinds := []string{"azblob", "gs", "s3", "file", "sqlite", "postgres", "postgresql", "https", "http"}
 	for _, supportedKind := range supportedKinds {
 		if kind == supportedKind {
 			return nil
 		}
 	}
 	return fmt.Errorf("unknown backend cloudUrl format '%s' (supported Url formats are: "+
-		"azblob://, gs://, s3://, file://, https:// and http://)",
+		"azblob://, gs://, s3://, file://, sqlite://, postgres://, https:// and http://)",
 		kind)
 }
@@ -0,0 +1,565 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	sdkDisplay "github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newPlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "[EXPERIMENTAL] Inspect deployment plans",
+		Long: "[EXPERIMENTAL] Inspect deployment plans.\n" +
+			"\n" +
+			"A plan file records the operations proposed by a preview. It is written by the --save-plan flag of\n" +
+			"`pulumi preview`, or of `pulumi refresh` and `pulumi destroy` with --preview-only, and is passed to\n" +
+			"the --plan flag of `pulumi up`, `pulumi refresh` or `pulumi destroy` to constrain the operation to\n" +
+			"the operations it records.\n" +
+			"\n" +
+			"Plan files can be signed with an ed25519 key by passing --plan-signing-key along with --save-plan,\n" +
+			"and the signature checked by passing --plan-verify-key along with --plan, or by setting the\n" +
+			"planVerifyKey project option. A signed plan is refused if there is no key to check it with. Keys\n" +
+			"are PEM files, such as those made by `openssl genpkey -algorithm ed25519 -out plan.key` and\n" +
+			"`openssl pkey -in plan.key -pubout -out plan.pub`.",
+		Args:   cmdutil.NoArgs,
+		Hidden: !hasExperimentalCommands(),
+	}
+
+	cmd.AddCommand(newPlanShowCmd())
+
+	return cmd
+}
+
+func newPlanShowCmd() *cobra.Command {
+	var stackName string
+	var verifyKeyPath string
+	var showReplacementSteps bool
+	var showSames bool
+
+	cmd := &cobra.Command{
+		Use:   "show <file>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Show the operations recorded in a plan file",
+		Long: "Show the operations recorded in a plan file.\n" +
+			"\n" +
+			"The operations are displayed in the same way as a preview's, with the changes to each resource\n" +
+			"shown against its current state in the stack that the plan was made for, or the stack given by\n" +
+			"--stack. If --verify-key is passed, the plan is refused unless it was signed with the matching\n" +
+			"private key and hasn't changed since. Secret values are always masked.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts := display.Options{
+				Color:                cmdutil.GetGlobalColorization(),
+				Type:                 display.DisplayDiff,
+				ShowReplacementSteps: showReplacementSteps,
+				ShowSameResources:    showSames,
+			}
+
+			deploymentPlan, err := readPlanFile(args[0])
+			if err != nil {
+				return err
+			}
+			var signer ed25519.PublicKey
+			if verifyKeyPath != "" {
+				if signer, err = checkPlanSignature(deploymentPlan, verifyKeyPath); err != nil {
+					return err
+				}
+			}
+
+			if stackName == "" {
+				stackName = deploymentPlan.Stack
+			}
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return err
+			}
+			snap, err := s.Snapshot(ctx, stack.DefaultSecretsProvider)
+			if err != nil {
+				return err
+			}
+
+			// Secret values are never shown, so they are left encrypted.
+			plan, err := stack.DeserializePlan(deploymentPlan, config.NopDecrypter, config.NopEncrypter)
+			if err != nil {
+				return err
+			}
+
+			var b bytes.Buffer
+			fprintf(&b, "Plan for stack %s", s.Ref())
+			if deploymentPlan.Project != "" {
+				fprintf(&b, " of project %s", deploymentPlan.Project)
+			}
+			fprintf(&b, ", made %s by pulumi %s\n",
+				plan.Manifest.Time.Format("2006-01-02 15:04:05 MST"), plan.Manifest.Version)
+			switch {
+			case signer != nil:
+				fprintf(&b, "Signed by ed25519 key %s\n\n", planKeyFingerprint(signer))
+			case deploymentPlan.Signature != nil:
+				fprintf(&b, "The plan is signed, but its signature was not checked; pass --verify-key to check it\n\n")
+			default:
+				fprintf(&b, "The plan is not signed\n\n")
+			}
+			fprintf(os.Stdout, "%s", opts.Color.Colorize(b.String()))
+
+			renderPlan(os.Stdout, plan, snap, opts)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack whose current state the plan is shown against. Defaults to the plan's stack")
+	cmd.PersistentFlags().StringVar(
+		&verifyKeyPath, "verify-key", "",
+		"Path to a PEM-encoded ed25519 public key. The plan must be signed with the matching private key")
+	cmd.PersistentFlags().BoolVar(
+		&showReplacementSteps, "show-replacement-steps", false,
+		"Show detailed resource replacement creates and deletes instead of a single step")
+	cmd.PersistentFlags().BoolVar(
+		&showSames, "show-sames", false,
+		"Show resources that don't need to be updated because they haven't changed, alongside those that do")
+
+	return cmd
+}
+
+// writePlan writes the given plan to a plan file for the given project and stack. If signingKeyPath is set, the plan
+// is signed with the ed25519 private key in the PEM file at that path.
+func writePlan(path string, plan *deploy.Plan, s backend.Stack, proj *workspace.Project, enc config.Encrypter,
+	showSecrets bool, signingKeyPath string,
+) error {
+	deploymentPlan, err := stack.SerializePlan(plan, enc, showSecrets)
+	if err != nil {
+		return err
+	}
+	deploymentPlan.Project = proj.Name
+	deploymentPlan.Stack = s.Ref().FullyQualifiedName().String()
+
+	if signingKeyPath != "" {
+		key, err := readPlanSigningKey(signingKeyPath)
+		if err != nil {
+			return err
+		}
+		if err := stack.SignPlan(&deploymentPlan, key); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer contract.IgnoreClose(f)
+
+	encoder := json.NewEncoder(f)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(deploymentPlan)
+}
+
+// readPlan reads a plan file for use with the given project and stack. Plans that were made for a different project or
+// stack, or with different configuration, are refused. If verifyKeyPath is set, the plan must be signed with the
+// private key matching the ed25519 public key in the PEM file at that path; otherwise, signed plans are refused.
+func readPlan(path string, s backend.Stack, proj *workspace.Project, cfg backend.StackConfiguration,
+	verifyKeyPath string, dec config.Decrypter, enc config.Encrypter,
+) (*deploy.Plan, error) {
+	deploymentPlan, err := readPlanFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := checkPlanSignature(deploymentPlan, verifyKeyPath); err != nil {
+		return nil, err
+	}
+
+	if deploymentPlan.Project != "" && deploymentPlan.Project != proj.Name {
+		return nil, fmt.Errorf("the plan was made for project %q, not %q", deploymentPlan.Project, proj.Name)
+	}
+	if stackName := s.Ref().FullyQualifiedName().String(); deploymentPlan.Stack != "" &&
+		deploymentPlan.Stack != stackName {
+		return nil, fmt.Errorf("the plan was made for stack %q, not %q", deploymentPlan.Stack, stackName)
+	}
+
+	plan, err := stack.DeserializePlan(deploymentPlan, dec, enc)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPlanConfig(plan.Config, cfg); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// readPlanFile reads the serialized plan in the given file.
+func readPlanFile(path string) (apitype.DeploymentPlanV1, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return apitype.DeploymentPlanV1{}, err
+	}
+	defer contract.IgnoreClose(f)
+
+	var deploymentPlan apitype.DeploymentPlanV1
+	if err := json.NewDecoder(f).Decode(&deploymentPlan); err != nil {
+		return apitype.DeploymentPlanV1{}, fmt.Errorf("could not read plan file %s: %w", path, err)
+	}
+	return deploymentPlan, nil
+}
+
+// checkPlanSignature checks that the given plan was signed with the private key matching the ed25519 public key in the
+// PEM file at verifyKeyPath, and returns that key. If verifyKeyPath is empty, there is no trusted key, so unsigned
+// plans are accepted and signed plans are refused: the key recorded in a plan's signature can't be used to check it.
+func checkPlanSignature(plan apitype.DeploymentPlanV1, verifyKeyPath string) (ed25519.PublicKey, error) {
+	if verifyKeyPath == "" {
+		if plan.Signature != nil {
+			return nil, errors.New("refusing to use the plan: it is signed, but there is no key to check its " +
+				"signature with; pass --plan-verify-key or set the planVerifyKey project option")
+		}
+		return nil, nil
+	}
+
+	trusted, err := readPlanVerificationKey(verifyKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := stack.VerifyPlanSignature(plan, trusted); err != nil {
+		return nil, fmt.Errorf("refusing to use the plan: %w", err)
+	}
+	return trusted, nil
+}
+
+// planVerifyKeyPath returns the path of the key that plan files must be signed with: the given --plan-verify-key flag
+// if it is set, and otherwise the project's planVerifyKey option, relative to the project's root directory.
+func planVerifyKeyPath(flag string, proj *workspace.Project, root string) string {
+	if flag != "" || proj.Options == nil || proj.Options.PlanVerifyKey == "" {
+		return flag
+	}
+	if filepath.IsAbs(proj.Options.PlanVerifyKey) {
+		return proj.Options.PlanVerifyKey
+	}
+	return filepath.Join(root, proj.Options.PlanVerifyKey)
+}
+
+// checkPlanConfig returns an error if the configuration that a plan was made with differs from the given stack
+// configuration. Values are compared after decryption, so re-encrypting a secret doesn't count as a change.
+func checkPlanConfig(planConfig config.Map, cfg backend.StackConfiguration) error {
+	planned, err := planConfig.Decrypt(cfg.Decrypter)
+	if err != nil {
+		return fmt.Errorf("decrypting the plan's configuration: %w", err)
+	}
+	actual, err := cfg.Config.Decrypt(cfg.Decrypter)
+	if err != nil {
+		return fmt.Errorf("decrypting the stack's configuration: %w", err)
+	}
+
+	var changed []string
+	for k, v := range planned {
+		if actualValue, has := actual[k]; !has || actualValue != v {
+			changed = append(changed, k.String())
+		}
+	}
+	for k := range actual {
+		if _, has := planned[k]; !has {
+			changed = append(changed, k.String())
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		return fmt.Errorf("the plan was made with different configuration: %s changed since the plan was made",
+			strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// readPlanSigningKey reads an ed25519 private key from a PEM-encoded PKCS #8 file.
+func readPlanSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not read private key from %s: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not hold an ed25519 private key", path)
+	}
+	return edKey, nil
+}
+
+// readPlanVerificationKey reads an ed25519 public key from a PEM-encoded PKIX file.
+func readPlanVerificationKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEMFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not read public key from %s: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not hold an ed25519 public key", path)
+	}
+	return edKey, nil
+}
+
+// readPEMFile reads the first PEM block in the given file.
+func readPEMFile(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	return block, nil
+}
+
+// planKeyFingerprint returns a short, printable identifier for the given public key, in the style of SSH's.
+func planKeyFingerprint(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// renderPlan writes the operations recorded in the given plan to w, in the format of a preview's diff display. The
+// changes to each resource are shown against its state in snap, which may be nil.
+func renderPlan(w io.Writer, plan *deploy.Plan, snap *deploy.Snapshot, opts display.Options) {
+	// Existing resources are shown in the order of the snapshot, followed by new resources, sorted by URN but with
+	// parents before their children.
+	olds := make(map[resource.URN][]*resource.State)
+	var order []resource.URN
+	emitted := make(map[resource.URN]bool)
+	if snap != nil {
+		for _, res := range snap.Resources {
+			if _, has := plan.ResourcePlans[res.URN]; has && !emitted[res.URN] {
+				order = append(order, res.URN)
+				emitted[res.URN] = true
+			}
+			olds[res.URN] = append(olds[res.URN], res)
+		}
+	}
+	news := make([]resource.URN, 0, len(plan.ResourcePlans))
+	for urn := range plan.ResourcePlans {
+		if !emitted[urn] {
+			news = append(news, urn)
+		}
+	}
+	sort.Slice(news, func(i, j int) bool { return news[i] < news[j] })
+	var visit func(urn resource.URN)
+	visit = func(urn resource.URN) {
+		if emitted[urn] {
+			return
+		}
+		emitted[urn] = true
+		if goal := plan.ResourcePlans[urn].Goal; goal != nil {
+			if _, has := plan.ResourcePlans[goal.Parent]; has {
+				visit(goal.Parent)
+			}
+		}
+		order = append(order, urn)
+	}
+	for _, urn := range news {
+		visit(urn)
+	}
+
+	var b strings.Builder
+	seen := make(map[resource.URN]engine.StepEventMetadata)
+	emit := func(event engine.Event) {
+		b.WriteString(display.RenderDiffEvent(event, seen, opts))
+	}
+	changes := sdkDisplay.ResourceChanges{}
+	for _, urn := range order {
+		rp := plan.ResourcePlans[urn]
+
+		// Find the resource's current state, preferring one that isn't pending deletion.
+		var old *resource.State
+		for _, res := range olds[urn] {
+			if old == nil || old.Delete && !res.Delete {
+				old = res
+			}
+		}
+
+		new, diffs := old, []resource.PropertyKey(nil)
+		if goal := rp.Goal; goal != nil {
+			var oldInputs resource.PropertyMap
+			new = &resource.State{
+				Type:     goal.Type,
+				URN:      urn,
+				Custom:   goal.Custom,
+				Parent:   goal.Parent,
+				Protect:  goal.Protect,
+				Provider: goal.Provider,
+			}
+			if old != nil {
+				oldInputs, new.ID = old.Inputs, old.ID
+			}
+			new.Inputs = applyPlanDiff(oldInputs, goal.InputDiff)
+			diffs = planDiffKeys(goal.InputDiff)
+		}
+		if old == nil && new == nil {
+			old = &resource.State{Type: urn.Type(), URN: urn}
+		}
+
+		for _, op := range rp.Ops {
+			if op == deploy.OpRefresh {
+				continue
+			}
+
+			metadata := engine.StepEventMetadata{
+				Op:      op,
+				URN:     urn,
+				Type:    urn.Type(),
+				Diffs:   diffs,
+				Logical: op == deploy.OpReplace || !deploy.IsReplacementStep(op),
+			}
+			if op != deploy.OpCreate {
+				metadata.Old = planStateMetadata(orState(old, new))
+			}
+			if op != deploy.OpDelete && op != deploy.OpDeleteReplaced && op != deploy.OpDiscardReplaced {
+				metadata.New = planStateMetadata(orState(new, old))
+			}
+			metadata.Res = metadata.New
+			if metadata.Res == nil {
+				metadata.Res = metadata.Old
+			}
+			metadata.Provider = metadata.Res.Provider
+
+			if metadata.Logical {
+				changes[op]++
+			}
+			emit(engine.NewEvent(engine.ResourcePreEventPayload{Metadata: metadata, Planning: true}))
+		}
+
+		for _, refresh := range rp.Refreshes {
+			refreshed := orState(old, new)
+			for _, res := range olds[urn] {
+				if res.ID == refresh.ID {
+					refreshed = res
+				}
+			}
+
+			metadata := engine.StepEventMetadata{
+				Op:      deploy.OpRefresh,
+				URN:     urn,
+				Type:    urn.Type(),
+				Old:     planStateMetadata(refreshed),
+				Res:     planStateMetadata(refreshed),
+				Logical: true,
+			}
+			if refresh.Result != deploy.OpDelete {
+				after := *refreshed
+				after.Outputs = applyPlanDiff(refreshed.Outputs, refresh.OutputDiff)
+				metadata.New = planStateMetadata(&after)
+			}
+
+			changes[refresh.Result]++
+			if refresh.Result == deploy.OpSame && !opts.ShowSameResources {
+				continue
+			}
+			emit(engine.NewEvent(engine.ResourcePreEventPayload{Metadata: metadata, Planning: true}))
+			emit(engine.NewEvent(engine.ResourceOutputsEventPayload{Metadata: metadata, Planning: true}))
+		}
+	}
+
+	emit(engine.NewEvent(engine.SummaryEventPayload{IsPreview: true, ResourceChanges: changes}))
+	fprintf(w, "%s", b.String())
+}
+
+// applyPlanDiff returns the properties that result from applying the given plan diff to props.
+func applyPlanDiff(props resource.PropertyMap, diff deploy.PlanDiff) resource.PropertyMap {
+	result := props.Copy()
+	for _, k := range diff.Deletes {
+		delete(result, k)
+	}
+	for k, v := range diff.Adds {
+		result[k] = v
+	}
+	for k, v := range diff.Updates {
+		result[k] = v
+	}
+	return result
+}
+
+// planDiffKeys returns the keys of the properties that the given plan diff changes.
+func planDiffKeys(diff deploy.PlanDiff) []resource.PropertyKey {
+	keys := append([]resource.PropertyKey{}, diff.Deletes...)
+	for k := range diff.Adds {
+		keys = append(keys, k)
+	}
+	for k := range diff.Updates {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// orState returns state if it is non-nil, and otherwise returns fallback.
+func orState(state, fallback *resource.State) *resource.State {
+	if state != nil {
+		return state
+	}
+	return fallback
+}
+
+// planStateMetadata returns the event metadata for the given resource state.
+func planStateMetadata(state *resource.State) *engine.StepEventStateMetadata {
+	if state == nil {
+		return nil
+	}
+	return &engine.StepEventStateMetadata{
+		State:          state,
+		Type:           state.Type,
+		URN:            state.URN,
+		Custom:         state.Custom,
+		Delete:         state.Delete,
+		ID:             state.ID,
+		Parent:         state.Parent,
+		Protect:        state.Protect,
+		RetainOnDelete: state.RetainOnDelete,
+		Inputs:         state.Inputs,
+		Outputs:        state.Outputs,
+		Provider:       state.Provider,
+		InitErrors:     state.InitErrors,
+	}
+}
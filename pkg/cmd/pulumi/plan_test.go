@@ -0,0 +1,266 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	sdkDisplay "github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// writePlanKeys writes a new ed25519 key pair to PEM files in dir and returns their paths.
+func writePlanKeys(t *testing.T, dir, name string) (string, string) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyPath := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}), 0o600))
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	pubPath := filepath.Join(dir, name+".pub")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes}), 0o600))
+
+	return keyPath, pubPath
+}
+
+func TestPlanFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	keyPath, pubPath := writePlanKeys(t, dir, "plan")
+	otherKeyPath, otherPubPath := writePlanKeys(t, dir, "other")
+
+	newStack := func(name string) backend.Stack {
+		return &backend.MockStack{
+			RefF: func() backend.StackReference {
+				return &backend.MockStackReference{FullyQualifiedNameV: tokens.QName("organization/proj/" + name)}
+			},
+		}
+	}
+	proj := &workspace.Project{Name: "proj"}
+	cfg := backend.StackConfiguration{
+		Config: config.Map{
+			config.MustMakeKey("proj", "size"): config.NewValue("3"),
+		},
+		Decrypter: config.NopDecrypter,
+	}
+
+	urn := resource.URN("urn:pulumi:dev::proj::pkgA:m:typA::resA")
+	plan := deploy.NewPlan(cfg.Config)
+	plan.ResourcePlans[urn] = &deploy.ResourcePlan{
+		Goal: &deploy.GoalPlan{
+			Type:      urn.Type(),
+			Name:      urn.Name(),
+			Custom:    true,
+			InputDiff: deploy.PlanDiff{Adds: resource.PropertyMap{"size": resource.NewNumberProperty(3)}},
+		},
+		Ops: []sdkDisplay.StepOp{deploy.OpCreate},
+	}
+
+	signedPath := filepath.Join(dir, "signed.json")
+	require.NoError(t, writePlan(signedPath, &plan, newStack("dev"), proj, config.NopEncrypter, false, keyPath))
+	unsignedPath := filepath.Join(dir, "unsigned.json")
+	require.NoError(t, writePlan(unsignedPath, &plan, newStack("dev"), proj, config.NopEncrypter, false, ""))
+
+	t.Run("signed", func(t *testing.T) {
+		t.Parallel()
+
+		read, err := readPlan(signedPath, newStack("dev"), proj, cfg, pubPath, config.NopDecrypter, config.NopEncrypter)
+		require.NoError(t, err)
+		assert.Equal(t, []sdkDisplay.StepOp{deploy.OpCreate}, read.ResourcePlans[urn].Ops)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := readPlan(unsignedPath, newStack("dev"), proj, cfg, "", config.NopDecrypter, config.NopEncrypter)
+		require.NoError(t, err)
+
+		_, err = readPlan(unsignedPath, newStack("dev"), proj, cfg, pubPath, config.NopDecrypter, config.NopEncrypter)
+		assert.ErrorContains(t, err, "refusing to use the plan: the plan is not signed")
+	})
+
+	t.Run("no trusted key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := readPlan(signedPath, newStack("dev"), proj, cfg, "", config.NopDecrypter, config.NopEncrypter)
+		assert.ErrorContains(t, err, "refusing to use the plan: it is signed, but there is no key to check its signature")
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := readPlan(signedPath, newStack("dev"), proj, cfg, otherPubPath, config.NopDecrypter, config.NopEncrypter)
+		assert.ErrorContains(t, err, "refusing to use the plan: the plan is not signed by the trusted key")
+	})
+
+	t.Run("resigned", func(t *testing.T) {
+		t.Parallel()
+
+		// A plan made for another stack and signed with another key records that key in its signature, but is still
+		// refused, because only the trusted key is used to check it.
+		resignedPath := filepath.Join(dir, "resigned.json")
+		require.NoError(t, writePlan(resignedPath, &plan, newStack("dev"), proj, config.NopEncrypter, false, otherKeyPath))
+		_, err := readPlan(resignedPath, newStack("dev"), proj, cfg, pubPath, config.NopDecrypter, config.NopEncrypter)
+		assert.ErrorContains(t, err, "refusing to use the plan: the plan is not signed by the trusted key")
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		t.Parallel()
+
+		deploymentPlan, err := readPlanFile(signedPath)
+		require.NoError(t, err)
+		deploymentPlan.ResourcePlans[urn].Goal.InputDiff.Adds["size"] = 300.0
+		data, err := json.Marshal(deploymentPlan)
+		require.NoError(t, err)
+		tamperedPath := filepath.Join(dir, "tampered.json")
+		require.NoError(t, os.WriteFile(tamperedPath, data, 0o600))
+
+		_, err = readPlan(tamperedPath, newStack("dev"), proj, cfg, pubPath, config.NopDecrypter, config.NopEncrypter)
+		assert.ErrorContains(t, err, "refusing to use the plan: the plan's signature does not match its contents")
+	})
+
+	t.Run("other project", func(t *testing.T) {
+		t.Parallel()
+
+		other := &workspace.Project{Name: "other"}
+		_, err := readPlan(signedPath, newStack("dev"), other, cfg, pubPath, config.NopDecrypter, config.NopEncrypter)
+		assert.ErrorContains(t, err, `the plan was made for project "proj", not "other"`)
+	})
+
+	t.Run("other stack", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := readPlan(signedPath, newStack("prod"), proj, cfg, pubPath, config.NopDecrypter, config.NopEncrypter)
+		assert.ErrorContains(t, err, `the plan was made for stack "organization/proj/dev", not "organization/proj/prod"`)
+	})
+
+	t.Run("other config", func(t *testing.T) {
+		t.Parallel()
+
+		changed := backend.StackConfiguration{
+			Config: config.Map{
+				config.MustMakeKey("proj", "size"):  config.NewValue("4"),
+				config.MustMakeKey("proj", "zones"): config.NewValue("2"),
+			},
+			Decrypter: config.NopDecrypter,
+		}
+		_, err := readPlan(signedPath, newStack("dev"), proj, changed, pubPath, config.NopDecrypter, config.NopEncrypter)
+		assert.ErrorContains(t, err,
+			"the plan was made with different configuration: proj:size, proj:zones changed since the plan was made")
+	})
+}
+
+func TestPlanVerifyKeyPath(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join("home", "proj")
+	proj := &workspace.Project{Name: "proj"}
+	assert.Equal(t, "", planVerifyKeyPath("", proj, root))
+	assert.Equal(t, "flag.pub", planVerifyKeyPath("flag.pub", proj, root))
+
+	proj.Options = &workspace.ProjectOptions{PlanVerifyKey: filepath.Join("keys", "plan.pub")}
+	assert.Equal(t, filepath.Join(root, "keys", "plan.pub"), planVerifyKeyPath("", proj, root))
+	assert.Equal(t, "flag.pub", planVerifyKeyPath("flag.pub", proj, root))
+}
+
+func TestRenderPlan(t *testing.T) {
+	t.Parallel()
+
+	typ := "pkgA:m:typA"
+	urn := func(name string) resource.URN {
+		return resource.NewURN("dev", "proj", "", "pkgA:m:typA", name)
+	}
+	snap := &deploy.Snapshot{
+		Resources: []*resource.State{
+			{
+				Type: "pkgA:m:typA", URN: urn("resA"), Custom: true, ID: "id-a",
+				Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+			},
+			{
+				Type: "pkgA:m:typA", URN: urn("resB"), Custom: true, ID: "id-b",
+				Inputs:  resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+				Outputs: resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+			},
+			{
+				Type: "pkgA:m:typA", URN: urn("resC"), Custom: true, ID: "id-c",
+				Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+			},
+		},
+	}
+
+	plan := deploy.NewPlan(nil)
+	plan.ResourcePlans[urn("resA")] = &deploy.ResourcePlan{
+		Goal: &deploy.GoalPlan{
+			Type: "pkgA:m:typA", Name: "resA", Custom: true,
+			InputDiff: deploy.PlanDiff{Updates: resource.PropertyMap{"size": resource.NewNumberProperty(2)}},
+		},
+		Ops: []sdkDisplay.StepOp{deploy.OpUpdate},
+	}
+	plan.ResourcePlans[urn("resB")] = &deploy.ResourcePlan{
+		Ops: []sdkDisplay.StepOp{deploy.OpRefresh},
+		Refreshes: []*deploy.RefreshPlan{{
+			ID:         "id-b",
+			Result:     deploy.OpUpdate,
+			OutputDiff: deploy.PlanDiff{Updates: resource.PropertyMap{"size": resource.NewNumberProperty(5)}},
+		}},
+	}
+	plan.ResourcePlans[urn("resC")] = &deploy.ResourcePlan{
+		Ops:       []sdkDisplay.StepOp{deploy.OpDelete},
+		DeleteIDs: []resource.ID{"id-c"},
+	}
+	plan.ResourcePlans[urn("resD")] = &deploy.ResourcePlan{
+		Goal: &deploy.GoalPlan{
+			Type: "pkgA:m:typA", Name: "resD", Custom: true,
+			InputDiff: deploy.PlanDiff{Adds: resource.PropertyMap{"size": resource.NewNumberProperty(7)}},
+		},
+		Ops: []sdkDisplay.StepOp{deploy.OpCreate},
+	}
+
+	var b bytes.Buffer
+	renderPlan(&b, &plan, snap, display.Options{Color: colors.Never, Type: display.DisplayDiff})
+	out := b.String()
+
+	assert.Contains(t, out, "~ "+typ+": (update)\n    [id=id-a]\n    [urn="+string(urn("resA"))+"]\n"+
+		"  ~ size: 1 => 2\n")
+	assert.Contains(t, out, "~ "+typ+": (refresh)\n    [id=id-b]\n    [urn="+string(urn("resB"))+"]\n"+
+		"    --outputs:--\n  ~ size: 1 => 5\n")
+	assert.Contains(t, out, "- "+typ+": (delete)\n    [id=id-c]\n    [urn="+string(urn("resC"))+"]\n"+
+		"    size: 1\n")
+	assert.Contains(t, out, "+ "+typ+": (create)\n    [urn="+string(urn("resD"))+"]\n    size: 7\n")
+	assert.Contains(t, out, "Resources:\n    + 1 to create\n    ~ 2 to update\n    - 1 to delete\n")
+}
@@ -35,7 +35,7 @@ func newPolicyDisableCmd() *cobra.Command {
 		Long:  "Disable a Policy Pack for a Pulumi organization",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, cliArgs []string) error {
 			ctx := cmd.Context()
-			// Obtain current PolicyPack, tied to the Pulumi Cloud backend.
+			// Obtain current PolicyPack, tied to the current backend.
 			var err error
 			policyPack, err := requirePolicyPack(ctx, cliArgs[0], loginToCloud)
 			if err != nil {
@@ -39,10 +39,13 @@ func newPolicyEnableCmd() *cobra.Command {
 		Args:  cmdutil.ExactArgs(2),
 		Short: "Enable a Policy Pack for a Pulumi organization",
 		Long: "Enable a Policy Pack for a Pulumi organization. " +
-			"Can specify latest to enable the latest version of the Policy Pack or a specific version number.",
+			"Can specify latest to enable the latest version of the Policy Pack or a specific version number.\n" +
+			"\n" +
+			"When logged into a DIY backend, the Policy Group is kept in the state bucket and its Policy Packs\n" +
+			"are enforced on every preview and update of the stacks in that group.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, cliArgs []string) error {
 			ctx := cmd.Context()
-			// Obtain current PolicyPack, tied to the Pulumi Cloud backend.
+			// Obtain current PolicyPack, tied to the current backend.
 			policyPack, err := requirePolicyPack(ctx, cliArgs[0], loginToCloud)
 			if err != nil {
 				return err
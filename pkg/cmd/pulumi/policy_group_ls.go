@@ -35,7 +35,9 @@ func newPolicyGroupCmd() *cobra.Command {
 		Args:  cmdutil.NoArgs,
 	}
 
+	cmd.AddCommand(newPolicyGroupAddStackCmd())
 	cmd.AddCommand(newPolicyGroupLsCmd())
+	cmd.AddCommand(newPolicyGroupRemoveStackCmd())
 	return cmd
 }
 
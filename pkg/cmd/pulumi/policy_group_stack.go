@@ -0,0 +1,110 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newPolicyGroupAddStackCmd() *cobra.Command {
+	var stack string
+	cmd := &cobra.Command{
+		Use:   "add-stack <policy-group-name>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Apply a Policy Group to a stack",
+		Long: "Apply a Policy Group to a stack.\n" +
+			"\n" +
+			"The Policy Packs enabled for the group are enforced on every preview and update of the stack. " +
+			"The group is created if it doesn't exist yet. This is only supported by backends that manage " +
+			"Policy Groups locally; Policy Groups in Pulumi Cloud are managed in the Pulumi Console.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			return runPolicyGroupStackCmd(cmd.Context(), stack, func(
+				m backend.PolicyGroupStackManager, orgName string, stackRef backend.StackReference,
+			) error {
+				if err := m.AddStackToPolicyGroup(cmd.Context(), orgName, args[0], stackRef); err != nil {
+					return err
+				}
+				fmt.Printf("Policy group %q now applies to stack %s\n", args[0], stackRef)
+				return nil
+			})
+		}),
+	}
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	return cmd
+}
+
+func newPolicyGroupRemoveStackCmd() *cobra.Command {
+	var stack string
+	cmd := &cobra.Command{
+		Use:   "remove-stack <policy-group-name>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Stop applying a Policy Group to a stack",
+		Long: "Stop applying a Policy Group to a stack.\n" +
+			"\n" +
+			"This is only supported by backends that manage Policy Groups locally; Policy Groups in Pulumi Cloud " +
+			"are managed in the Pulumi Console.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			return runPolicyGroupStackCmd(cmd.Context(), stack, func(
+				m backend.PolicyGroupStackManager, orgName string, stackRef backend.StackReference,
+			) error {
+				if err := m.RemoveStackFromPolicyGroup(cmd.Context(), orgName, args[0], stackRef); err != nil {
+					return err
+				}
+				fmt.Printf("Policy group %q no longer applies to stack %s\n", args[0], stackRef)
+				return nil
+			})
+		}),
+	}
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	return cmd
+}
+
+// runPolicyGroupStackCmd resolves the given stack, and runs the given change against its backend's Policy Groups.
+func runPolicyGroupStackCmd(
+	ctx context.Context, stack string,
+	change func(m backend.PolicyGroupStackManager, orgName string, stackRef backend.StackReference) error,
+) error {
+	opts := display.Options{
+		Color: cmdutil.GetGlobalColorization(),
+	}
+	s, err := requireStack(ctx, stack, stackLoadOnly, opts)
+	if err != nil {
+		return err
+	}
+
+	b := s.Backend()
+	m, ok := b.(backend.PolicyGroupStackManager)
+	if !ok {
+		return fmt.Errorf("the current backend (%s) does not support managing the stacks of policy groups; "+
+			"use the Pulumi Console instead", b.Name())
+	}
+
+	orgName, _, _, err := b.CurrentUser()
+	if err != nil {
+		return err
+	}
+	return change(m, orgName, s.Ref())
+}
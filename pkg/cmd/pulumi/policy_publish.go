@@ -23,6 +23,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -38,7 +39,10 @@ func newPolicyPublishCmd() *cobra.Command {
 		Short: "Publish a Policy Pack to the Pulumi Cloud",
 		Long: "Publish a Policy Pack to the Pulumi Cloud\n" +
 			"\n" +
-			"If an organization name is not specified, the default org (if set) or the current user account is used.",
+			"If an organization name is not specified, the default org (if set) or the current user account is used.\n" +
+			"\n" +
+			"When logged into a DIY backend, the Policy Pack is published to the state bucket instead and can be\n" +
+			"enabled with `pulumi policy enable`.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			return policyPublishCmd.Run(cmd.Context(), args)
 		}),
@@ -91,7 +95,7 @@ func (cmd *policyPublishCmd) Run(ctx context.Context, args []string) error {
 	policyPackRef := orgName + "/"
 
 	//
-	// Obtain current PolicyPack, tied to the Pulumi Cloud backend.
+	// Obtain current PolicyPack, tied to the current backend.
 	//
 
 	policyPack, err := requirePolicyPack(ctx, policyPackRef, cmd.loginToCloud)
@@ -145,7 +149,7 @@ func requirePolicyPack(
 	loginToCloud func(context.Context, string, *workspace.Project, bool, display.Options) (backend.Backend, error),
 ) (backend.PolicyPack, error) {
 	//
-	// Attempt to log into cloud backend.
+	// Attempt to log into the current backend.
 	//
 
 	// Try to read the current project
@@ -156,14 +160,20 @@ func requirePolicyPack(
 
 	cloudURL, err := workspace.GetCurrentCloudURL(project)
 	if err != nil {
-		return nil, fmt.Errorf("`pulumi policy` command requires the user to be logged into the Pulumi Cloud: %w", err)
+		return nil, fmt.Errorf("`pulumi policy` command requires the user to be logged into a backend: %w", err)
 	}
 
 	displayOptions := display.Options{
 		Color: cmdutil.GetGlobalColorization(),
 	}
 
-	b, err := loginToCloud(ctx, cloudURL, project, workspace.GetCloudInsecure(cloudURL), displayOptions)
+	// DIY backends keep their Policy Packs in the state bucket, everything else lives in the Pulumi Cloud.
+	var b backend.Backend
+	if diy.IsDIYBackendURL(cloudURL) {
+		b, err = diy.New(ctx, cmdutil.Diag(), cloudURL, project)
+	} else {
+		b, err = loginToCloud(ctx, cloudURL, project, workspace.GetCloudInsecure(cloudURL), displayOptions)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +38,7 @@ func newPolicyRmCmd() *cobra.Command {
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			yes = yes || skipConfirmations()
-			// Obtain current PolicyPack, tied to the Pulumi Cloud backend.
+			// Obtain current PolicyPack, tied to the current backend.
 			policyPack, err := requirePolicyPack(ctx, args[0], loginToCloud)
 			if err != nil {
 				return err
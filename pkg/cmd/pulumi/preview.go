@@ -244,6 +244,7 @@ func newPreviewCmd() *cobra.Command {
 	var configPath bool
 	var client string
 	var planFilePath string
+	var planSigningKeyPath string
 	var importFilePath string
 	var showSecrets bool
 
@@ -257,6 +258,7 @@ func newPreviewCmd() *cobra.Command {
 	var diffDisplay bool
 	var eventLogPath string
 	var parallel int
+	var parallelFor []string
 	var refresh string
 	var showConfig bool
 	var showPolicyRemediations bool
@@ -270,6 +272,8 @@ func newPreviewCmd() *cobra.Command {
 	var replaces []string
 	var targetReplaces []string
 	var targetDependents bool
+	var excludes []string
+	var excludeDependents bool
 
 	use, cmdArgs := "preview", cmdutil.NoArgs
 	if remoteSupported() {
@@ -324,11 +328,15 @@ func newPreviewCmd() *cobra.Command {
 				displayOpts.SuppressPermalink = false
 			}
 
+			if planSigningKeyPath != "" && planFilePath == "" {
+				return result.FromError(errors.New("--plan-signing-key requires --save-plan"))
+			}
+
 			if remoteArgs.remote {
 				err := validateUnsupportedRemoteFlags(expectNop, configArray, configPath, client, jsonDisplay,
 					policyPackPaths, policyPackConfigPaths, refresh, showConfig, showPolicyRemediations,
 					showReplacementSteps, showSames, showReads, suppressOutputs, "default", &targets, replaces,
-					targetReplaces, targetDependents, planFilePath, stackConfigFile)
+					targetReplaces, targetDependents, excludes, excludeDependents, planFilePath, stackConfigFile)
 				if err != nil {
 					return result.FromError(err)
 				}
@@ -418,11 +426,26 @@ func newPreviewCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
+			parallelForOption, err := getParallelForOption(proj, parallelFor)
+			if err != nil {
+				return result.FromError(err)
+			}
+			retryPolicyOption, err := getRetryPolicyOption(proj)
+			if err != nil {
+				return result.FromError(err)
+			}
+			secretScannerOption, err := getSecretScannerOption(proj)
+			if err != nil {
+				return result.FromError(err)
+			}
 
 			opts := backend.UpdateOptions{
 				Engine: engine.UpdateOptions{
 					LocalPolicyPacks:          engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
 					Parallel:                  parallel,
+					ParallelFor:               parallelForOption,
+					RetryPolicy:               retryPolicyOption,
+					SecretScanner:             secretScannerOption,
 					Debug:                     debug,
 					Refresh:                   refreshOption,
 					ReplaceTargets:            deploy.NewUrnTargets(replaceURNs),
@@ -432,6 +455,8 @@ func newPreviewCmd() *cobra.Command {
 					DisableOutputValues:       disableOutputValues(),
 					Targets:                   deploy.NewUrnTargets(targetURNs),
 					TargetDependents:          targetDependents,
+					Excludes:                  deploy.NewUrnTargets(excludes),
+					ExcludeDependents:         excludeDependents,
 					// If we're trying to save a plan then we _need_ to generate it. We also turn this on in
 					// experimental mode to just get more testing of it.
 					GeneratePlan: hasExperimentalCommands() || planFilePath != "",
@@ -477,7 +502,7 @@ func newPreviewCmd() *cobra.Command {
 					if err != nil {
 						return result.FromError(err)
 					}
-					if err = writePlan(planFilePath, plan, encrypter, showSecrets); err != nil {
+					if err = writePlan(planFilePath, plan, s, proj, encrypter, showSecrets, planSigningKeyPath); err != nil {
 						return result.FromError(err)
 					}
 
@@ -533,10 +558,10 @@ func newPreviewCmd() *cobra.Command {
 		"Config keys contain a path to a property in a map or list to set")
 	cmd.PersistentFlags().StringVar(
 		&planFilePath, "save-plan", "",
-		"[EXPERIMENTAL] Save the operations proposed by the preview to a plan file at the given path")
-	if !hasExperimentalCommands() {
-		contract.AssertNoErrorf(cmd.PersistentFlags().MarkHidden("save-plan"), `Could not mark "save-plan" as hidden`)
-	}
+		"Save the operations proposed by the preview to a plan file at the given path")
+	cmd.PersistentFlags().StringVar(
+		&planSigningKeyPath, "plan-signing-key", "",
+		"Path to a PEM-encoded ed25519 private key to sign the plan file with. Requires --save-plan")
 	cmd.PersistentFlags().StringVar(
 		&importFilePath, "import-file", "",
 		"Save any creates seen during the preview into an import file to use with 'pulumi import'")
@@ -566,6 +591,14 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&targetDependents, "target-dependents", false,
 		"Allows updating of dependent targets discovered but not specified in --target list")
+	cmd.PersistentFlags().StringArrayVar(
+		&excludes, "exclude", []string{},
+		"Specify a resource URN to ignore. These resources will not be updated."+
+			" Multiple resources can be specified using --exclude urn1 --exclude urn2."+
+			" Wildcards (*, **) are also supported")
+	cmd.PersistentFlags().BoolVar(
+		&excludeDependents, "exclude-dependents", false,
+		"Allows ignoring of dependent resources discovered but not specified in --exclude list")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().StringSliceVar(
@@ -583,6 +616,10 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism).")
+	cmd.PersistentFlags().StringArrayVar(
+		&parallelFor, "parallel-for", []string{},
+		"Allow at most N resource operations at once for a resource package or type token, as <package or type>=N."+
+			" For example, --parallel-for aws=4. May be repeated")
 	cmd.PersistentFlags().StringVarP(
 		&refresh, "refresh", "r", "",
 		"Refresh the state of the stack's resources before this update")
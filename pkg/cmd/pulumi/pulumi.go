@@ -318,6 +318,7 @@ func NewPulumiCmd() *cobra.Command {
 				newConsoleCmd(),
 				newImportCmd(),
 				newRefreshCmd(),
+				newDriftCmd(),
 				newStateCmd(),
 				newInstallCmd(),
 			},
@@ -386,6 +387,7 @@ func NewPulumiCmd() *cobra.Command {
 				newConvertCmd(),
 				newWatchCmd(),
 				newLogsCmd(),
+				newPlanCmd(),
 			},
 		},
 		// We have a set of options that are useful for developers of pulumi
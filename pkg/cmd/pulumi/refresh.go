@@ -15,6 +15,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -31,6 +32,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -54,16 +56,26 @@ func newRefreshCmd() *cobra.Command {
 	var diffDisplay bool
 	var eventLogPath string
 	var parallel int
+	var parallelFor []string
+	var planFilePath string
+	var planSigningKeyPath string
+	var planVerifyKey string
 	var previewOnly bool
+	var runProgram bool
+	var savePlanFilePath string
+	var showSecrets bool
 	var showConfig bool
 	var showReplacementSteps bool
 	var showSames bool
+	var showCriticalPath bool
 	var skipPreview bool
 	var suppressOutputs bool
 	var suppressProgress bool
 	var suppressPermalink string
 	var yes bool
 	var targets *[]string
+	var excludes []string
+	var excludeDependents bool
 
 	// Flags for handling pending creates
 	var skipPendingCreates bool
@@ -104,6 +116,13 @@ func newRefreshCmd() *cobra.Command {
 						"must be passed in to proceed when running in non-interactive mode"))
 			}
 
+			if savePlanFilePath != "" && !previewOnly {
+				return result.FromError(errors.New("--save-plan requires --preview-only"))
+			}
+			if planSigningKeyPath != "" && savePlanFilePath == "" {
+				return result.FromError(errors.New("--plan-signing-key requires --save-plan"))
+			}
+
 			opts, err := updateFlagsToOptions(interactive, skipPreview, yes, previewOnly)
 			if err != nil {
 				return result.FromError(err)
@@ -119,6 +138,7 @@ func newRefreshCmd() *cobra.Command {
 				ShowConfig:           showConfig,
 				ShowReplacementSteps: showReplacementSteps,
 				ShowSameResources:    showSames,
+				ShowCriticalPath:     showCriticalPath,
 				SuppressOutputs:      suppressOutputs,
 				SuppressProgress:     suppressProgress,
 				IsInteractive:        interactive,
@@ -137,10 +157,16 @@ func newRefreshCmd() *cobra.Command {
 			}
 
 			if remoteArgs.remote {
+				if runProgram {
+					return result.FromError(errors.New("--run-program is not supported with --remote"))
+				}
+				if savePlanFilePath != "" {
+					return result.FromError(errors.New("--save-plan is not supported with --remote"))
+				}
 				err = validateUnsupportedRemoteFlags(expectNop, nil, false, "", jsonDisplay, nil,
 					nil, "", showConfig, false, showReplacementSteps, showSames, false,
 					suppressOutputs, "default", targets, nil, nil,
-					false, "", stackConfigFile)
+					false, excludes, excludeDependents, planFilePath, stackConfigFile)
 				if err != nil {
 					return result.FromError(err)
 				}
@@ -174,7 +200,7 @@ func newRefreshCmd() *cobra.Command {
 				return result.FromError(err)
 			}
 
-			m, err := getUpdateMetadata(message, root, execKind, execAgent, false, cmd.Flags())
+			m, err := getUpdateMetadata(message, root, execKind, execAgent, planFilePath != "", cmd.Flags())
 			if err != nil {
 				return result.FromError(fmt.Errorf("gathering environment metadata: %w", err))
 			}
@@ -255,21 +281,50 @@ func newRefreshCmd() *cobra.Command {
 				}
 			}
 
+			parallelForOption, err := getParallelForOption(proj, parallelFor)
+			if err != nil {
+				return result.FromError(err)
+			}
+			retryPolicyOption, err := getRetryPolicyOption(proj)
+			if err != nil {
+				return result.FromError(err)
+			}
+			secretScannerOption, err := getSecretScannerOption(proj)
+			if err != nil {
+				return result.FromError(err)
+			}
+
 			targetUrns := []string{}
 			targetUrns = append(targetUrns, *targets...)
 
 			opts.Engine = engine.UpdateOptions{
 				Parallel:                  parallel,
+				ParallelFor:               parallelForOption,
+				RetryPolicy:               retryPolicyOption,
+				SecretScanner:             secretScannerOption,
 				Debug:                     debug,
 				UseLegacyDiff:             useLegacyDiff(),
 				DisableProviderPreview:    disableProviderPreview(),
 				DisableResourceReferences: disableResourceReferences(),
 				DisableOutputValues:       disableOutputValues(),
+				RefreshProgram:            runProgram,
 				Targets:                   deploy.NewUrnTargets(targetUrns),
+				Excludes:                  deploy.NewUrnTargets(excludes),
+				ExcludeDependents:         excludeDependents,
+				GeneratePlan:              hasExperimentalCommands() || savePlanFilePath != "",
 				Experimental:              hasExperimentalCommands(),
 			}
 
-			changes, res := s.Refresh(ctx, backend.UpdateOperation{
+			if planFilePath != "" {
+				verifyKeyPath := planVerifyKeyPath(planVerifyKey, proj, root)
+				plan, err := readPlan(planFilePath, s, proj, cfg, verifyKeyPath, decrypter, encrypter)
+				if err != nil {
+					return result.FromError(err)
+				}
+				opts.Engine.Plan = plan
+			}
+
+			plan, changes, res := s.Refresh(ctx, backend.UpdateOperation{
 				Proj:               proj,
 				Root:               root,
 				M:                  m,
@@ -288,6 +343,22 @@ func newRefreshCmd() *cobra.Command {
 			case expectNop && changes != nil && engine.HasChanges(changes):
 				return result.FromError(errors.New("error: no changes were expected but changes occurred"))
 			default:
+				if savePlanFilePath != "" {
+					if err = writePlan(savePlanFilePath, plan, s, proj, encrypter, showSecrets, planSigningKeyPath); err != nil {
+						return result.FromError(err)
+					}
+
+					// Write out message on how to use the plan (if not writing out --json)
+					if !jsonDisplay {
+						var buf bytes.Buffer
+						fprintf(&buf, "Refresh plan written to '%s'", savePlanFilePath)
+						fprintf(
+							&buf,
+							"\nRun `pulumi refresh --plan='%s'` to constrain the refresh to the changes found by this preview",
+							savePlanFilePath)
+						cmdutil.Diag().Infof(diag.RawMessage("" /*urn*/, buf.String()))
+					}
+				}
 				return nil
 			}
 		}),
@@ -313,6 +384,14 @@ func newRefreshCmd() *cobra.Command {
 	targets = cmd.PersistentFlags().StringArrayP(
 		"target", "t", []string{},
 		"Specify a single resource URN to refresh. Multiple resource can be specified using: --target urn1 --target urn2")
+	cmd.PersistentFlags().StringArrayVar(
+		&excludes, "exclude", []string{},
+		"Specify a resource URN to ignore. These resources will not be refreshed."+
+			" Multiple resources can be specified using --exclude urn1 --exclude urn2."+
+			" Wildcards (*, **) are also supported")
+	cmd.PersistentFlags().BoolVar(
+		&excludeDependents, "exclude-dependents", false,
+		"Allows ignoring of dependent resources discovered but not specified in --exclude list")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().BoolVar(
@@ -324,15 +403,45 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism).")
+	cmd.PersistentFlags().StringArrayVar(
+		&parallelFor, "parallel-for", []string{},
+		"Allow at most N resource operations at once for a resource package or type token, as <package or type>=N."+
+			" For example, --parallel-for aws=4. May be repeated")
 	cmd.PersistentFlags().BoolVar(
 		&previewOnly, "preview-only", false,
 		"Only show a preview of the refresh, but don't perform the refresh itself")
+	cmd.PersistentFlags().StringVar(
+		&planFilePath, "plan", "",
+		"Path to a plan file to use for the refresh. The refresh will fail without changing "+
+			"the stack's state if it finds changes that the plan does not record.")
+	cmd.PersistentFlags().StringVar(
+		&savePlanFilePath, "save-plan", "",
+		"Save the changes found by the preview to a plan file at the given path. "+
+			"Requires --preview-only")
+	cmd.PersistentFlags().StringVar(
+		&planSigningKeyPath, "plan-signing-key", "",
+		"Path to a PEM-encoded ed25519 private key to sign the plan file with. Requires --save-plan")
+	cmd.PersistentFlags().StringVar(
+		&planVerifyKey, "plan-verify-key", "",
+		"Path to a PEM-encoded ed25519 public key. The plan file must be signed with the matching "+
+			"private key. Defaults to the project's planVerifyKey option")
+	cmd.Flags().BoolVarP(
+		&showSecrets, "show-secrets", "", false, "Emit secrets in plaintext in the plan file. Defaults to `false`")
 	cmd.PersistentFlags().BoolVar(
 		&showReplacementSteps, "show-replacement-steps", false,
 		"Show detailed resource replacement creates and deletes instead of a single step")
 	cmd.PersistentFlags().BoolVar(
 		&showSames, "show-sames", false,
 		"Show resources that needn't be updated because they haven't changed, alongside those that do")
+	cmd.PersistentFlags().BoolVar(
+		&showCriticalPath, "show-critical-path", false,
+		"Show the chain of resource operations that determined how long the deployment took, how long each "+
+			"operation waited and worked, and how many operations ran in parallel")
+	cmd.PersistentFlags().BoolVar(
+		&runProgram, "run-program", false,
+		"Run the program and read resources with the inputs that it declares, to find the resources that it no "+
+			"longer declares and the resources whose inputs differ from the program's current inputs. Undeclared and "+
+			"drifted resources are reported, not changed")
 	cmd.PersistentFlags().BoolVarP(
 		&skipPreview, "skip-preview", "f", false,
 		"Do not calculate a preview before performing the refresh")
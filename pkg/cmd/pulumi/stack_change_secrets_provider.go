@@ -17,6 +17,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -25,10 +26,13 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/deepcopy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/spf13/cobra"
 )
@@ -48,7 +52,8 @@ func newStackChangeSecretsProviderCmd() *cobra.Command {
 		Args:  cmdutil.ExactArgs(1),
 		Short: "Change the secrets provider for a stack",
 		Long: "Change the secrets provider for a stack. " +
-			"Valid secret providers types are `default`, `passphrase`, `awskms`, `azurekeyvault`, `gcpkms`, `hashivault`.\n\n" +
+			"Valid secret providers types are `default`, `passphrase`, `awskms`, `azurekeyvault`, `gcpkms`, `hashivault`, " +
+			"`age`, `pgp`.\n\n" +
 			"To change to using the Pulumi Default Secrets Provider, use the following:\n" +
 			"\n" +
 			"pulumi stack change-secrets-provider default" +
@@ -64,7 +69,24 @@ func newStackChangeSecretsProviderCmd() *cobra.Command {
 			"\"azurekeyvault://mykeyvaultname.vault.azure.net/keys/mykeyname\"`\n" +
 			"* `pulumi stack change-secrets-provider " +
 			"\"gcpkms://projects/<p>/locations/<l>/keyRings/<r>/cryptoKeys/<k>\"`\n" +
-			"* `pulumi stack change-secrets-provider \"hashivault://mykey\"`",
+			"* `pulumi stack change-secrets-provider \"hashivault://mykey\"`" +
+			"\n" +
+			"\n" +
+			"To encrypt secrets to the holders of age or PGP keys, list their public keys, or the full fingerprints of\n" +
+			"their PGP encryption subkeys:\n" +
+			"\n" +
+			"* `pulumi stack change-secrets-provider \"age://?recipient=age1...&recipient=age1...\"`\n" +
+			"* `pulumi stack change-secrets-provider \"pgp://?recipient=<fingerprint>&recipient=<fingerprint>\"`\n" +
+			"\n" +
+			"The recipients are stored with the stack's data key, so the stack's secrets provider is recorded as\n" +
+			"`age://` or `pgp://`. Changing the recipients of a stack that already uses age or PGP encrypts its\n" +
+			"existing data key to the new recipients, so that its secret values don't need to be encrypted again.\n" +
+			"Use the `add-recipient` and `remove-recipient` subcommands to change a single recipient. Pass the same\n" +
+			"secrets provider the stack already uses, `age://` or `pgp://`, to generate a new data key instead.\n" +
+			"\n" +
+			"A stack that uses the `passphrase` secrets provider can have several passphrases, any one of\n" +
+			"which decrypts its secrets, so that a passphrase can be replaced without everyone who uses the\n" +
+			"stack switching at once. Use the `add-passphrase` and `remove-passphrase` subcommands to change them.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			return scspcmd.Run(ctx, args)
@@ -75,6 +97,11 @@ func newStackChangeSecretsProviderCmd() *cobra.Command {
 		&scspcmd.stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
 
+	cmd.AddCommand(newStackAddPassphraseCmd(&scspcmd.stack))
+	cmd.AddCommand(newStackRemovePassphraseCmd(&scspcmd.stack))
+	cmd.AddCommand(newStackAddRecipientCmd(&scspcmd.stack))
+	cmd.AddCommand(newStackRemoveRecipientCmd(&scspcmd.stack))
+
 	return cmd
 }
 
@@ -130,18 +157,95 @@ func (cmd *stackChangeSecretsProviderCmd) Run(ctx context.Context, args []string
 		// passphrase doesn't get saved to stack state, so if we're changing to passphrase see if
 		// the current secrets provider is empty
 		((secretsProvider == "passphrase") && (currentProjectStack.SecretsProvider == ""))
+	// Changing the recipients of an age or PGP provider keeps the stack's data key, so existing secret values
+	// don't need to be encrypted again.
+	oldProjectStack := deepcopy.Copy(currentProjectStack).(*workspace.ProjectStack)
+	rewrapKey := !rotateProvider && oldProjectStack.EncryptedKey != "" &&
+		cloud.CanRewrapDataKey(oldProjectStack.SecretsProvider, secretsProvider)
 	// Create the new secrets provider and set to the currentStack
 	if err := createSecretsManager(ctx, currentStack, secretsProvider, rotateProvider,
 		false /*creatingStack*/); err != nil {
 		return err
 	}
 
+	if rewrapKey {
+		rewrapped, err := updateCheckpointSecretsProvider(ctx, project, currentStack, oldProjectStack)
+		if err != nil {
+			return err
+		}
+		if rewrapped {
+			fmt.Fprintf(stdout, "Encrypted the stack's data key to the new recipients\n")
+			return nil
+		}
+	}
+
 	// Fixup the checkpoint
 	fmt.Fprintf(stdout, "Migrating old configuration and state to new secrets provider\n")
 	return migrateOldConfigAndCheckpointToNewSecretsProvider(
 		ctx, cmd.secretsProvider, project, currentStack, currentProjectStack, decrypter)
 }
 
+// updateCheckpointSecretsProvider records the stack's new secrets provider in its checkpoint without encrypting
+// its secret values again, which is possible when the new secrets provider has the same data key as the old one,
+// described by oldProjectStack, and the checkpoint's values are encrypted with the old one. It returns false if they
+// are not, in which case the checkpoint is left unchanged.
+func updateCheckpointSecretsProvider(ctx context.Context,
+	project *workspace.Project, currentStack backend.Stack, oldProjectStack *workspace.ProjectStack,
+) (bool, error) {
+	projectStack, err := loadProjectStack(project, currentStack)
+	if err != nil {
+		return false, err
+	}
+	newSecretsManager, _, err := getStackSecretsManager(currentStack, projectStack, nil)
+	if err != nil {
+		return false, err
+	}
+
+	checkpoint, err := currentStack.ExportDeployment(ctx)
+	if err != nil {
+		return false, err
+	}
+	deployment, err := stack.UnmarshalUntypedDeployment(ctx, checkpoint)
+	if err != nil {
+		return false, checkDeploymentVersionError(err, currentStack.Ref().Name().String())
+	}
+	if deployment.SecretsProviders != nil && deployment.SecretsProviders.Type != "" {
+		var checkpointStack workspace.ProjectStack
+		switch deployment.SecretsProviders.Type {
+		case cloud.Type:
+			if err := cloud.EditProjectStack(&checkpointStack, deployment.SecretsProviders.State); err != nil {
+				return false, err
+			}
+			if checkpointStack.SecretsProvider != oldProjectStack.SecretsProvider ||
+				checkpointStack.EncryptedKey != oldProjectStack.EncryptedKey {
+				return false, nil
+			}
+		case passphrase.Type:
+			if err := passphrase.EditProjectStack(&checkpointStack, deployment.SecretsProviders.State); err != nil {
+				return false, err
+			}
+			if checkpointStack.EncryptionSalt != oldProjectStack.EncryptionSalt {
+				return false, nil
+			}
+		default:
+			return false, nil
+		}
+	}
+
+	deployment.SecretsProviders = &apitype.SecretsProvidersV1{
+		Type:  newSecretsManager.Type(),
+		State: newSecretsManager.State(),
+	}
+	bytes, err := json.Marshal(deployment)
+	if err != nil {
+		return false, err
+	}
+	return true, currentStack.ImportDeployment(ctx, &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	})
+}
+
 func migrateOldConfigAndCheckpointToNewSecretsProvider(ctx context.Context,
 	secretsProvider secrets.Provider,
 	project *workspace.Project,
@@ -215,3 +319,216 @@ func migrateOldConfigAndCheckpointToNewSecretsProvider(ctx context.Context,
 	// Import the newly changes Deployment
 	return currentStack.ImportDeployment(ctx, &dep)
 }
+
+type stackChangePassphrasesCmd struct {
+	stdout io.Writer
+
+	stack string
+
+	// change changes the passphrases of the given project stack, and returns its secrets manager.
+	change func(info *workspace.ProjectStack) (secrets.Manager, error)
+	// done is the message printed once the passphrases have been changed, formatted with their number.
+	done string
+
+	secretsProvider secrets.Provider
+}
+
+func newStackAddPassphraseCmd(stack *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-passphrase",
+		Args:  cmdutil.NoArgs,
+		Short: "Add a passphrase that can decrypt a stack's secrets",
+		Long: "Add a passphrase that can decrypt a stack's secrets.\n" +
+			"\n" +
+			"The stack must use the `passphrase` secrets provider. Its current passphrase is read from\n" +
+			"PULUMI_CONFIG_PASSPHRASE or PULUMI_CONFIG_PASSPHRASE_FILE, or prompted for, and the passphrase to\n" +
+			"add is prompted for, or read from standard input if not interactive. The stack's data key is\n" +
+			"encrypted with the new passphrase and stored alongside its other passphrases' copies, so its\n" +
+			"secret values don't need to be encrypted again.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			scpcmd := stackChangePassphrasesCmd{
+				stack:  *stack,
+				change: passphrase.AddPassphrase,
+				done:   "Added the passphrase; the stack's secrets can be decrypted with any of its %d passphrases\n",
+			}
+			return scpcmd.Run(cmd.Context())
+		}),
+	}
+}
+
+func newStackRemovePassphraseCmd(stack *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove-passphrase",
+		Args:  cmdutil.NoArgs,
+		Short: "Remove a passphrase that can decrypt a stack's secrets",
+		Long: "Remove a passphrase that can decrypt a stack's secrets.\n" +
+			"\n" +
+			"The passphrase to remove is prompted for, or read from standard input if not interactive. A\n" +
+			"stack's last passphrase can't be removed.\n" +
+			"\n" +
+			"The stack's secret values are not encrypted again, so anyone who has used the removed passphrase\n" +
+			"to read the stack's data key can still decrypt them. Run `pulumi stack rotate-secrets` once the\n" +
+			"stack has a single passphrase to encrypt them with a new data key as well.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			scpcmd := stackChangePassphrasesCmd{
+				stack:  *stack,
+				change: passphrase.RemovePassphrase,
+				done:   "Removed the passphrase; the stack's secrets can be decrypted with any of its %d passphrases\n",
+			}
+			return scpcmd.Run(cmd.Context())
+		}),
+	}
+}
+
+func (cmd *stackChangePassphrasesCmd) Run(ctx context.Context) error {
+	stdout := cmd.stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if cmd.secretsProvider == nil {
+		cmd.secretsProvider = stack.DefaultSecretsProvider
+	}
+
+	opts := display.Options{
+		Color: cmdutil.GetGlobalColorization(),
+	}
+
+	project, _, err := readProject()
+	if err != nil {
+		return err
+	}
+	currentStack, err := requireStack(ctx, cmd.stack, stackLoadOnly, opts)
+	if err != nil {
+		return err
+	}
+	currentProjectStack, err := loadProjectStack(project, currentStack)
+	if err != nil {
+		return err
+	}
+	provider := currentProjectStack.SecretsProvider
+	if currentProjectStack.EncryptionSalt == "" ||
+		(provider != passphrase.Type && provider != "default" && provider != "") {
+		return errors.New("the stack does not use the passphrase secrets provider")
+	}
+
+	oldProjectStack := deepcopy.Copy(currentProjectStack).(*workspace.ProjectStack)
+	secretsManager, err := cmd.change(currentProjectStack)
+	if err != nil {
+		return err
+	}
+	if err := saveProjectStack(currentStack, currentProjectStack); err != nil {
+		return err
+	}
+
+	// The stack's data key hasn't changed, so its checkpoint normally just needs the new passphrases.
+	updated, err := updateCheckpointSecretsProvider(ctx, project, currentStack, oldProjectStack)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		decrypter, err := secretsManager.Decrypter()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Migrating old configuration and state to new secrets provider\n")
+		if err := migrateOldConfigAndCheckpointToNewSecretsProvider(
+			ctx, cmd.secretsProvider, project, currentStack, currentProjectStack, decrypter); err != nil {
+			return err
+		}
+	}
+
+	n, err := passphrase.KeyringSize(currentProjectStack.EncryptionSalt)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, cmd.done, n)
+	return nil
+}
+
+type stackChangeRecipientsCmd struct {
+	stdout io.Writer
+
+	stack string
+
+	// change returns the secrets provider URL that lists the recipients of the given project stack's data key, changed
+	// by the given recipient.
+	change func(info *workspace.ProjectStack, recipient string) (string, error)
+
+	secretsProvider secrets.Provider
+}
+
+func newStackAddRecipientCmd(stack *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-recipient <recipient>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Add a recipient that can decrypt a stack's secrets",
+		Long: "Add a recipient that can decrypt a stack's secrets.\n" +
+			"\n" +
+			"The stack must use the `age` or `pgp` secrets provider. The recipient is an age public key, or the\n" +
+			"full fingerprint of a PGP encryption subkey. The stack's data key is decrypted with the current user's\n" +
+			"identity and encrypted to its existing recipients and the new one, so its secret values don't need to\n" +
+			"be encrypted again.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			scrcmd := stackChangeRecipientsCmd{
+				stack:  *stack,
+				change: cloud.AddRecipient,
+			}
+			return scrcmd.Run(cmd.Context(), args)
+		}),
+	}
+}
+
+func newStackRemoveRecipientCmd(stack *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove-recipient <recipient>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Remove a recipient that can decrypt a stack's secrets",
+		Long: "Remove a recipient that can decrypt a stack's secrets.\n" +
+			"\n" +
+			"The stack must use the `age` or `pgp` secrets provider. The recipient is an age public key, or the\n" +
+			"full fingerprint of a PGP encryption subkey. A stack's last recipient can't be removed.\n" +
+			"\n" +
+			"The stack's secret values are not encrypted again, so the removed recipient can still decrypt them\n" +
+			"with a copy of the stack's data key. Run `pulumi stack rotate-secrets` to encrypt them with a new\n" +
+			"data key as well.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			scrcmd := stackChangeRecipientsCmd{
+				stack:  *stack,
+				change: cloud.RemoveRecipient,
+			}
+			return scrcmd.Run(cmd.Context(), args)
+		}),
+	}
+}
+
+func (cmd *stackChangeRecipientsCmd) Run(ctx context.Context, args []string) error {
+	opts := display.Options{
+		Color: cmdutil.GetGlobalColorization(),
+	}
+
+	project, _, err := readProject()
+	if err != nil {
+		return err
+	}
+	currentStack, err := requireStack(ctx, cmd.stack, stackLoadOnly, opts)
+	if err != nil {
+		return err
+	}
+	currentProjectStack, err := loadProjectStack(project, currentStack)
+	if err != nil {
+		return err
+	}
+
+	secretsProvider, err := cmd.change(currentProjectStack, args[0])
+	if err != nil {
+		return err
+	}
+
+	// Changing to the secrets provider that lists the new recipients encrypts the stack's data key to them.
+	scspcmd := stackChangeSecretsProviderCmd{
+		stdout:          cmd.stdout,
+		stack:           cmd.stack,
+		secretsProvider: cmd.secretsProvider,
+	}
+	return scspcmd.Run(ctx, []string{secretsProvider})
+}
@@ -19,13 +19,18 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"filippo.io/age"
+
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	agekeeper "github.com/pulumi/pulumi/pkg/v3/secrets/age"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
@@ -49,7 +54,7 @@ func TestChangeSecretsProvider_Invalid(t *testing.T) {
 	err := cmd.Run(context.Background(), []string{"not_a_secret"})
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "unknown secrets provider type 'not_a_secret' "+
-		"(supported values: default,passphrase,awskms,azurekeyvault,gcpkms,hashivault)")
+		"(supported values: default,passphrase,awskms,azurekeyvault,gcpkms,hashivault,age,pgp)")
 }
 
 func mockStdin(t *testing.T, input string) {
@@ -289,3 +294,248 @@ runtime: mock
 	require.NoError(t, err)
 	assert.Equal(t, "bar", val)
 }
+
+// Test that adding a recipient to a stack that uses the age secrets provider encrypts its data key to the new
+// recipient, without encrypting the secrets in its state and config again.
+//
+//nolint:paralleltest // mutates global state
+func TestChangeSecretsProvider_AgeRecipients(t *testing.T) {
+	ctx := context.Background()
+
+	alice, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	bob, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	keysDir := t.TempDir()
+	aliceKeys, bobKeys := filepath.Join(keysDir, "alice.txt"), filepath.Join(keysDir, "bob.txt")
+	require.NoError(t, os.WriteFile(aliceKeys, []byte(alice.String()+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(bobKeys, []byte(bob.String()+"\n"), 0o600))
+	t.Setenv(agekeeper.IdentityFileEnvVar, aliceKeys)
+
+	aliceURL := "age://?recipient=" + alice.Recipient().String()
+	bothURL := aliceURL + "&recipient=" + bob.Recipient().String()
+
+	var stdoutBuff bytes.Buffer
+	cmd := stackChangeSecretsProviderCmd{
+		stdout: &stdoutBuff,
+		stack:  "testStack",
+	}
+
+	tmpDir := t.TempDir()
+	chdir(t, tmpDir)
+
+	err = os.WriteFile("Pulumi.yaml", []byte(`
+name: testProject
+runtime: mock
+`), 0o600)
+	require.NoError(t, err)
+
+	// Write a config file with a secret encrypted to Alice.
+	cfg := workspace.ProjectStack{}
+	secretsManager, err := cloud.NewCloudSecretsManager(&cfg, aliceURL, false /* rotateSecretsProvider */)
+	require.NoError(t, err)
+	encrypter, err := secretsManager.Encrypter()
+	require.NoError(t, err)
+	secretBar, err := encrypter.EncryptValue(ctx, "bar")
+	require.NoError(t, err)
+	cfgKey := config.MustMakeKey("testStack", "secret")
+	cfg.Config = config.Map{cfgKey: config.NewSecureValue(secretBar)}
+	require.NoError(t, cfg.Save("Pulumi.testStack.yaml"))
+
+	chk, err := stack.SerializeDeployment(ctx, &deploy.Snapshot{
+		SecretsManager: secretsManager,
+		Resources: []*resource.State{
+			{
+				URN:  resource.NewURN("testStack", "testProject", "", resource.RootStackType, "testStack"),
+				Type: resource.RootStackType,
+				Outputs: resource.PropertyMap{
+					"foo": resource.MakeSecret(resource.NewStringProperty("bar")),
+				},
+			},
+		},
+	}, false)
+	require.NoError(t, err)
+	data, err := encoding.JSON.Marshal(chk)
+	require.NoError(t, err)
+	deployment := &apitype.UntypedDeployment{Version: 3, Deployment: data}
+
+	mockStack := &backend.MockStack{
+		RefF: func() backend.StackReference {
+			return &backend.MockStackReference{
+				StringV: "testStack",
+				NameV:   tokens.MustParseStackName("testStack"),
+			}
+		},
+		ExportDeploymentF: func(ctx context.Context) (*apitype.UntypedDeployment, error) {
+			return deployment, nil
+		},
+		ImportDeploymentF: func(ctx context.Context, d *apitype.UntypedDeployment) error {
+			deployment = d
+			return nil
+		},
+	}
+	mockBackendInstance(t, &backend.MockBackend{
+		GetStackF: func(ctx context.Context, stackRef backend.StackReference) (backend.Stack, error) {
+			return mockStack, nil
+		},
+	})
+
+	err = cmd.Run(ctx, []string{bothURL})
+	require.NoError(t, err)
+	require.Equal(t, "Encrypted the stack's data key to the new recipients\n", stdoutBuff.String())
+
+	// The config's secret hasn't been encrypted again.
+	project, err := workspace.LoadProject("Pulumi.yaml")
+	require.NoError(t, err)
+	projectStack, err := workspace.LoadProjectStack(project, "Pulumi.testStack.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "age://", projectStack.SecretsProvider)
+	assert.Equal(t, config.NewSecureValue(secretBar), projectStack.Config[cfgKey])
+
+	// Neither have the state's secrets, but the state records the new secrets provider.
+	v3, err := stack.UnmarshalUntypedDeployment(ctx, deployment)
+	require.NoError(t, err)
+	ciphertext := chk.Resources[0].Outputs["foo"].(apitype.SecretV1).Ciphertext
+	assert.Equal(t, ciphertext, v3.Resources[0].Outputs["foo"].(map[string]interface{})["ciphertext"])
+	assert.NotEqual(t, chk.SecretsProviders.State, v3.SecretsProviders.State)
+
+	// Bob can now decrypt the stack's secrets.
+	t.Setenv(agekeeper.IdentityFileEnvVar, bobKeys)
+	snap, err := stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewStringProperty("bar"), snap.Resources[0].Outputs["foo"].SecretValue().Element)
+	decrypter, _, err := getStackDecrypter(mockStack, projectStack)
+	require.NoError(t, err)
+	val, err := projectStack.Config[cfgKey].Value(decrypter)
+	require.NoError(t, err)
+	assert.Equal(t, "bar", val)
+
+	// Bob can remove Alice, and still decrypt the stack's secrets.
+	stdoutBuff.Reset()
+	rmcmd := stackChangeRecipientsCmd{
+		stdout: &stdoutBuff,
+		stack:  "testStack",
+		change: cloud.RemoveRecipient,
+	}
+	require.NoError(t, rmcmd.Run(ctx, []string{alice.Recipient().String()}))
+	require.Equal(t, "Encrypted the stack's data key to the new recipients\n", stdoutBuff.String())
+	projectStack, err = workspace.LoadProjectStack(project, "Pulumi.testStack.yaml")
+	require.NoError(t, err)
+	recipients, err := cloud.KeyRecipients(projectStack)
+	require.NoError(t, err)
+	assert.Equal(t, []string{bob.Recipient().String()}, recipients)
+	snap, err = stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	assert.Equal(t, resource.NewStringProperty("bar"), snap.Resources[0].Outputs["foo"].SecretValue().Element)
+}
+
+// Test that adding and removing passphrases of a stack that uses the passphrase secrets provider records them in its
+// config and state, without encrypting the secrets in either again.
+//
+//nolint:paralleltest // mutates global state
+func TestChangeSecretsProvider_AddAndRemovePassphrase(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("PULUMI_CONFIG_PASSPHRASE", "password")
+
+	salt, secretsManager, err := passphrase.NewPassphraseSecretsManager("password")
+	require.NoError(t, err)
+	encrypter, err := secretsManager.Encrypter()
+	require.NoError(t, err)
+	secretBar, err := encrypter.EncryptValue(ctx, "bar")
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	chdir(t, tmpDir)
+	err = os.WriteFile("Pulumi.yaml", []byte(`
+name: testProject
+runtime: mock
+`), 0o600)
+	require.NoError(t, err)
+	cfgKey := config.MustMakeKey("testStack", "secret")
+	cfg := workspace.ProjectStack{
+		EncryptionSalt: salt,
+		Config:         config.Map{cfgKey: config.NewSecureValue(secretBar)},
+	}
+	require.NoError(t, cfg.Save("Pulumi.testStack.yaml"))
+
+	chk, err := stack.SerializeDeployment(ctx, &deploy.Snapshot{
+		SecretsManager: secretsManager,
+		Resources: []*resource.State{
+			{
+				URN:  resource.NewURN("testStack", "testProject", "", resource.RootStackType, "testStack"),
+				Type: resource.RootStackType,
+				Outputs: resource.PropertyMap{
+					"foo": resource.MakeSecret(resource.NewStringProperty("bar")),
+				},
+			},
+		},
+	}, false)
+	require.NoError(t, err)
+	data, err := encoding.JSON.Marshal(chk)
+	require.NoError(t, err)
+	deployment := &apitype.UntypedDeployment{Version: 3, Deployment: data}
+
+	mockStack := &backend.MockStack{
+		RefF: func() backend.StackReference {
+			return &backend.MockStackReference{
+				StringV: "testStack",
+				NameV:   tokens.MustParseStackName("testStack"),
+			}
+		},
+		ExportDeploymentF: func(ctx context.Context) (*apitype.UntypedDeployment, error) {
+			return deployment, nil
+		},
+		ImportDeploymentF: func(ctx context.Context, d *apitype.UntypedDeployment) error {
+			deployment = d
+			return nil
+		},
+	}
+	mockBackendInstance(t, &backend.MockBackend{
+		GetStackF: func(ctx context.Context, stackRef backend.StackReference) (backend.Stack, error) {
+			return mockStack, nil
+		},
+	})
+
+	project, err := workspace.LoadProject("Pulumi.yaml")
+	require.NoError(t, err)
+	checkStack := func(passphrases int) {
+		projectStack, err := workspace.LoadProjectStack(project, "Pulumi.testStack.yaml")
+		require.NoError(t, err)
+		n, err := passphrase.KeyringSize(projectStack.EncryptionSalt)
+		require.NoError(t, err)
+		assert.Equal(t, passphrases, n)
+		assert.Equal(t, config.NewSecureValue(secretBar), projectStack.Config[cfgKey])
+
+		v3, err := stack.UnmarshalUntypedDeployment(ctx, deployment)
+		require.NoError(t, err)
+		var checkpointStack workspace.ProjectStack
+		require.NoError(t, passphrase.EditProjectStack(&checkpointStack, v3.SecretsProviders.State))
+		assert.Equal(t, projectStack.EncryptionSalt, checkpointStack.EncryptionSalt)
+		ciphertext := chk.Resources[0].Outputs["foo"].(apitype.SecretV1).Ciphertext
+		assert.Equal(t, ciphertext, v3.Resources[0].Outputs["foo"].(map[string]interface{})["ciphertext"])
+	}
+
+	var stdoutBuff bytes.Buffer
+	add := stackChangePassphrasesCmd{
+		stdout: &stdoutBuff,
+		stack:  "testStack",
+		change: passphrase.AddPassphrase,
+		done:   "Added %d\n",
+	}
+	mockStdin(t, "other\n")
+	require.NoError(t, add.Run(ctx))
+	assert.Equal(t, "Added 2\n", stdoutBuff.String())
+	checkStack(2)
+
+	stdoutBuff.Reset()
+	remove := stackChangePassphrasesCmd{
+		stdout: &stdoutBuff,
+		stack:  "testStack",
+		change: passphrase.RemovePassphrase,
+		done:   "Removed %d\n",
+	}
+	mockStdin(t, "password\n")
+	require.NoError(t, remove.Run(ctx))
+	assert.Equal(t, "Removed 1\n", stdoutBuff.String())
+	checkStack(1)
+}
@@ -0,0 +1,338 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	sdkDisplay "github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newStackDiffCmd() *cobra.Command {
+	var stackName string
+	var from string
+	var to string
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Args:  cmdutil.NoArgs,
+		Short: "Show the differences between two versions of a stack's state",
+		Long: "Show the differences between two versions of a stack's state.\n" +
+			"\n" +
+			"Each of --from and --to is either a version number from `pulumi stack history`, or the\n" +
+			"path of a file holding a deployment, such as one written by `pulumi stack export` or a\n" +
+			"checkpoint from a DIY backend's history directory. A value that is a whole number is always\n" +
+			"a version; to name a file whose name is a number, prefix it with ./ instead. If --to is\n" +
+			"omitted, the stack's current state is used.\n" +
+			"\n" +
+			"The resources that were added, removed, or changed are listed, along with the changes\n" +
+			"to their properties. Secret values are masked unless --show-secrets is passed.",
+		Example: "pulumi stack diff --from 41 --to 57",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if from == "" {
+				return errors.New("--from must be specified")
+			}
+
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return err
+			}
+
+			fromSnap, err := loadStackDeployment(ctx, s, from)
+			if err != nil {
+				return err
+			}
+			toSnap, err := loadStackDeployment(ctx, s, to)
+			if err != nil {
+				return err
+			}
+
+			if showSecrets {
+				log3rdPartySecretsProviderDecryptionEvent(ctx, s, "", "pulumi stack diff")
+			}
+
+			fmt.Printf("Comparing %s to %s of stack %s:\n\n", describeDeploymentSpec(from), describeDeploymentSpec(to),
+				s.Ref())
+			renderSnapshotDiff(os.Stdout, diffSnapshots(fromSnap, toSnap, showSecrets), opts)
+			return nil
+		}),
+	}
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().StringVar(&from, "from", "", "The version number or deployment file to compare from")
+	cmd.Flags().StringVar(&to, "to", "",
+		"The version number or deployment file to compare to. Defaults to the stack's current state")
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show secret values in plaintext")
+	return cmd
+}
+
+// describeDeploymentSpec describes a --from or --to value for display.
+func describeDeploymentSpec(spec string) string {
+	switch {
+	case spec == "":
+		return "the current state"
+	case isDeploymentVersion(spec):
+		return "version " + spec
+	default:
+		return spec
+	}
+}
+
+// isDeploymentVersion returns true if the given --from or --to value names a version of the stack rather than a
+// file. Whole numbers are always versions, even if a file of that name exists, so that the meaning of a command
+// doesn't depend on the working directory.
+func isDeploymentVersion(spec string) bool {
+	_, err := strconv.Atoi(spec)
+	return err == nil
+}
+
+// loadStackDeployment loads a deployment of the given stack, which is either a version from the stack's history, a
+// deployment file, or the stack's current state if spec is empty.
+func loadStackDeployment(ctx context.Context, s backend.Stack, spec string) (*deploy.Snapshot, error) {
+	var deployment *apitype.UntypedDeployment
+	var err error
+	switch {
+	case spec == "":
+		deployment, err = s.ExportDeployment(ctx)
+	case isDeploymentVersion(spec):
+		deployment, err = exportStackVersion(ctx, s, spec)
+	default:
+		deployment, err = readDeploymentFile(spec)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, checkDeploymentVersionError(err, s.Ref().Name().String())
+	}
+	return snap, nil
+}
+
+// exportStackVersion exports the given version of a stack's deployment, if the stack's backend supports it.
+func exportStackVersion(ctx context.Context, s backend.Stack, version string) (*apitype.UntypedDeployment, error) {
+	be := s.Backend()
+	specificExpBE, ok := be.(backend.SpecificDeploymentExporter)
+	if !ok {
+		return nil, fmt.Errorf("the current backend (%s) does not provide the ability to export previous deployments",
+			be.Name())
+	}
+	return specificExpBE.ExportDeploymentForVersion(ctx, s, version)
+}
+
+// readDeploymentFile reads a deployment from a file. The file may hold an exported deployment, as written by
+// `pulumi stack export`, or a versioned checkpoint, as written to a DIY backend's history. Either may be compressed.
+func readDeploymentFile(path string) (*apitype.UntypedDeployment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read deployment file: %w", err)
+	}
+	m := encoding.JSON
+	if encoding.IsCompressed(data) {
+		m = encoding.Gzip(m)
+	}
+
+	var probe struct {
+		Version    int             `json:"version"`
+		Deployment json.RawMessage `json:"deployment"`
+		Checkpoint json.RawMessage `json:"checkpoint"`
+	}
+	if err := m.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("could not read deployment file %s: %w", path, err)
+	}
+
+	switch {
+	case probe.Deployment != nil:
+		return &apitype.UntypedDeployment{Version: probe.Version, Deployment: probe.Deployment}, nil
+	case probe.Checkpoint != nil:
+		chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(m, data)
+		if err != nil {
+			return nil, fmt.Errorf("could not read checkpoint file %s: %w", path, err)
+		}
+		if chk.Latest == nil {
+			return nil, fmt.Errorf("checkpoint file %s has no deployment", path)
+		}
+		latest, err := json.Marshal(chk.Latest)
+		if err != nil {
+			return nil, err
+		}
+		return &apitype.UntypedDeployment{Version: apitype.DeploymentSchemaVersionCurrent, Deployment: latest}, nil
+	default:
+		return nil, fmt.Errorf("%s is neither a deployment nor a checkpoint file", path)
+	}
+}
+
+// resourceStateDiff describes how the state of one resource differs between two snapshots.
+type resourceStateDiff struct {
+	// Op is OpCreate for an added resource, OpDelete for a removed one, and OpUpdate for a changed one.
+	Op  sdkDisplay.StepOp
+	URN resource.URN
+	// Changes describes the changes to the resource's fields other than its inputs and outputs.
+	Changes []string
+	Inputs  *resource.ObjectDiff
+	Outputs *resource.ObjectDiff
+}
+
+// diffSnapshots compares the resources in two snapshots. Resources are matched by URN. Added and changed resources
+// are listed in the order of the new snapshot, followed by the removed resources in the order of the old one. If
+// showSecrets is false, secret values are left in place for the renderer to mask.
+func diffSnapshots(from, to *deploy.Snapshot, showSecrets bool) []resourceStateDiff {
+	// Several states can share a URN, for example while a replacement is pending deletion, so key them by URN and
+	// their position among the states with that URN.
+	type stateKey struct {
+		urn resource.URN
+		n   int
+	}
+	keyStates := func(snap *deploy.Snapshot) ([]stateKey, map[stateKey]*resource.State) {
+		if snap == nil {
+			return nil, nil
+		}
+		seen := make(map[resource.URN]int)
+		keys := make([]stateKey, 0, len(snap.Resources))
+		states := make(map[stateKey]*resource.State, len(snap.Resources))
+		for _, res := range snap.Resources {
+			key := stateKey{res.URN, seen[res.URN]}
+			seen[res.URN]++
+			keys = append(keys, key)
+			states[key] = res
+		}
+		return keys, states
+	}
+	fromKeys, fromStates := keyStates(from)
+	toKeys, toStates := keyStates(to)
+
+	props := func(m resource.PropertyMap) resource.PropertyMap {
+		if showSecrets {
+			return display.MassageSecrets(m, true)
+		}
+		return m
+	}
+
+	var diffs []resourceStateDiff
+	for _, key := range toKeys {
+		newRes := toStates[key]
+		oldRes, has := fromStates[key]
+		if !has {
+			diffs = append(diffs, resourceStateDiff{Op: deploy.OpCreate, URN: key.urn})
+			continue
+		}
+
+		d := resourceStateDiff{
+			Op:      deploy.OpUpdate,
+			URN:     key.urn,
+			Changes: diffResourceFields(oldRes, newRes),
+			Inputs:  props(oldRes.Inputs).Diff(props(newRes.Inputs)),
+			Outputs: props(oldRes.Outputs).Diff(props(newRes.Outputs)),
+		}
+		if len(d.Changes) > 0 || d.Inputs != nil || d.Outputs != nil {
+			diffs = append(diffs, d)
+		}
+	}
+	for _, key := range fromKeys {
+		if _, has := toStates[key]; !has {
+			diffs = append(diffs, resourceStateDiff{Op: deploy.OpDelete, URN: key.urn})
+		}
+	}
+	return diffs
+}
+
+// diffResourceFields describes the changes to the fields of a resource's state other than its inputs and outputs.
+func diffResourceFields(old, new *resource.State) []string {
+	var changes []string
+	field := func(name string, old, new interface{}) {
+		if fmt.Sprint(old) != fmt.Sprint(new) {
+			changes = append(changes, fmt.Sprintf("%s: %v => %v", name, old, new))
+		}
+	}
+	field("id", old.ID, new.ID)
+	field("type", old.Type, new.Type)
+	field("parent", old.Parent, new.Parent)
+	field("provider", old.Provider, new.Provider)
+	field("protect", old.Protect, new.Protect)
+	field("external", old.External, new.External)
+	field("retainOnDelete", old.RetainOnDelete, new.RetainOnDelete)
+	field("deletedWith", old.DeletedWith, new.DeletedWith)
+	field("pendingReplacement", old.PendingReplacement, new.PendingReplacement)
+	field("dependencies", old.Dependencies, new.Dependencies)
+	return changes
+}
+
+// renderSnapshotDiff writes a description of the given snapshot diff to the given writer.
+func renderSnapshotDiff(w io.Writer, diffs []resourceStateDiff, opts display.Options) {
+	var adds, deletes, updates int
+	var b bytes.Buffer
+	for _, d := range diffs {
+		b.WriteString(deploy.Prefix(d.Op, true /*done*/))
+		b.WriteString(string(d.URN))
+		b.WriteString(colors.Reset)
+		b.WriteString("\n")
+
+		switch d.Op {
+		case deploy.OpCreate:
+			adds++
+			continue
+		case deploy.OpDelete:
+			deletes++
+			continue
+		}
+		updates++
+
+		for _, change := range d.Changes {
+			fmt.Fprintf(&b, "%s    ~ %s%s\n", deploy.Color(deploy.OpUpdate), change, colors.Reset)
+		}
+		if d.Inputs != nil {
+			b.WriteString("    inputs:\n")
+			display.PrintObjectDiff(&b, *d.Inputs, nil,
+				false /*planning*/, 2, false /*summary*/, false /*truncateOutput*/, false /*debug*/)
+		}
+		if d.Outputs != nil {
+			b.WriteString("    outputs:\n")
+			display.PrintObjectDiff(&b, *d.Outputs, nil,
+				false /*planning*/, 2, false /*summary*/, false /*truncateOutput*/, false /*debug*/)
+		}
+	}
+
+	if len(diffs) == 0 {
+		b.WriteString("No differences\n")
+	} else {
+		fmt.Fprintf(&b, "\nResources: %d added, %d removed, %d changed\n", adds, deletes, updates)
+	}
+	fmt.Fprint(w, opts.Color.Colorize(b.String()))
+}
@@ -0,0 +1,141 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	t.Parallel()
+
+	kept := resource.URN("urn:pulumi:dev::proj::t:m:T::kept")
+	changed := resource.URN("urn:pulumi:dev::proj::t:m:T::changed")
+	removed := resource.URN("urn:pulumi:dev::proj::t:m:T::removed")
+	added := resource.URN("urn:pulumi:dev::proj::t:m:T::added")
+
+	from := &deploy.Snapshot{Resources: []*resource.State{
+		{URN: kept, Type: "t:m:T", Inputs: resource.PropertyMap{"a": resource.NewStringProperty("x")}},
+		{
+			URN:  changed,
+			Type: "t:m:T",
+			Inputs: resource.PropertyMap{
+				"size":     resource.NewNumberProperty(1),
+				"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+			},
+		},
+		{URN: removed, Type: "t:m:T"},
+	}}
+	to := &deploy.Snapshot{Resources: []*resource.State{
+		{URN: kept, Type: "t:m:T", Inputs: resource.PropertyMap{"a": resource.NewStringProperty("x")}},
+		{
+			URN:     changed,
+			Type:    "t:m:T",
+			Protect: true,
+			Inputs: resource.PropertyMap{
+				"size":     resource.NewNumberProperty(2),
+				"password": resource.MakeSecret(resource.NewStringProperty("swordfish")),
+			},
+		},
+		{URN: added, Type: "t:m:T"},
+	}}
+
+	diffs := diffSnapshots(from, to, false /*showSecrets*/)
+	require.Len(t, diffs, 3)
+	assert.Equal(t, deploy.OpUpdate, diffs[0].Op)
+	assert.Equal(t, changed, diffs[0].URN)
+	assert.Equal(t, []string{"protect: false => true"}, diffs[0].Changes)
+	require.NotNil(t, diffs[0].Inputs)
+	assert.Len(t, diffs[0].Inputs.Updates, 2)
+	assert.Nil(t, diffs[0].Outputs)
+	assert.Equal(t, deploy.OpCreate, diffs[1].Op)
+	assert.Equal(t, added, diffs[1].URN)
+	assert.Equal(t, deploy.OpDelete, diffs[2].Op)
+	assert.Equal(t, removed, diffs[2].URN)
+
+	// Secrets are masked unless asked for.
+	var masked bytes.Buffer
+	renderSnapshotDiff(&masked, diffs, display.Options{Color: colors.Never})
+	assert.Contains(t, masked.String(), "Resources: 1 added, 1 removed, 1 changed")
+	assert.Contains(t, masked.String(), "[secret]")
+	assert.NotContains(t, masked.String(), "swordfish")
+
+	var shown bytes.Buffer
+	renderSnapshotDiff(&shown, diffSnapshots(from, to, true /*showSecrets*/), display.Options{Color: colors.Never})
+	assert.Contains(t, shown.String(), "swordfish")
+
+	var none bytes.Buffer
+	renderSnapshotDiff(&none, diffSnapshots(from, from, false), display.Options{Color: colors.Never})
+	assert.Equal(t, "No differences\n", none.String())
+}
+
+func TestReadDeploymentFile(t *testing.T) {
+	t.Parallel()
+
+	deployment := apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{{URN: "urn:pulumi:dev::proj::t:m:T::a", Type: "t:m:T"}},
+	}
+	raw, err := json.Marshal(deployment)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeJSON := func(name string, v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		return path
+	}
+
+	// An exported deployment.
+	exported := writeJSON("export.json", apitype.UntypedDeployment{Version: 3, Deployment: raw})
+	dep, err := readDeploymentFile(exported)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(raw), string(dep.Deployment))
+
+	// A checkpoint from a DIY backend's history.
+	chk, err := json.Marshal(apitype.CheckpointV3{Stack: "dev", Latest: &deployment})
+	require.NoError(t, err)
+	checkpoint := writeJSON("dev-1.checkpoint.json", apitype.VersionedCheckpoint{Version: 3, Checkpoint: chk})
+	dep, err = readDeploymentFile(checkpoint)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(raw), string(dep.Deployment))
+
+	other := writeJSON("other.json", map[string]string{"hello": "world"})
+	_, err = readDeploymentFile(other)
+	assert.ErrorContains(t, err, "neither a deployment nor a checkpoint file")
+}
+
+func TestIsDeploymentVersion(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isDeploymentVersion("41"))
+	assert.False(t, isDeploymentVersion("./41"))
+	assert.False(t, isDeploymentVersion("dev.json"))
+	assert.False(t, isDeploymentVersion(""))
+}
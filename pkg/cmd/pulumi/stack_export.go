@@ -22,7 +22,6 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/spf13/cobra"
 
-	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -65,15 +64,7 @@ func newStackExportCmd() *cobra.Command {
 					return err
 				}
 			} else {
-				// Check that the stack and its backend supports the ability to do this.
-				be := s.Backend()
-				specificExpBE, ok := be.(backend.SpecificDeploymentExporter)
-				if !ok {
-					return fmt.Errorf("the current backend (%s) does not provide the ability to export previous deployments",
-						be.Name())
-				}
-
-				deployment, err = specificExpBE.ExportDeploymentForVersion(ctx, s, version)
+				deployment, err = exportStackVersion(ctx, s, version)
 				if err != nil {
 					return err
 				}
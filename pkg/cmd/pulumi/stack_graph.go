@@ -15,17 +15,22 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/graph"
 	"github.com/pulumi/pulumi/pkg/v3/graph/dotconv"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	resourcegraph "github.com/pulumi/pulumi/pkg/v3/resource/graph"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/spf13/cobra"
 )
 
@@ -50,6 +55,16 @@ type graphCommandOptions struct {
 	// A DOT fragment that will be inserted at the top of the digraph element. This
 	// can be used for styling the graph elements, setting graph properties etc.")
 	dotFragment string
+
+	// Whether or not to highlight the critical path of the deployment recorded in eventLogPath, and to label each
+	// resource with how long its operation waited and worked.
+	criticalPath bool
+
+	// The path of the event log of the deployment to analyze with criticalPath, as written by --event-log.
+	eventLogPath string
+
+	// The color of critical path edges in the graph. Defaults to #D62728, a red.
+	criticalPathEdgeColor string
 }
 
 func newStackGraphCmd() *cobra.Command {
@@ -63,13 +78,30 @@ func newStackGraphCmd() *cobra.Command {
 			"\n" +
 			"This command can be used to view the dependency graph that a Pulumi program\n" +
 			"emitted when it was run. This graph is output in the DOT format. This command operates\n" +
-			"on your stack's most recent deployment.",
+			"on your stack's most recent deployment.\n" +
+			"\n" +
+			"With --critical-path, the graph also shows the chain of resource operations that\n" +
+			"determined how long a deployment took, and each resource is labelled with how long\n" +
+			"its operation waited to start and how long it worked. The deployment's timings are\n" +
+			"read from the event log it wrote with --event-log, to the second.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
 			}
 
+			var timings map[resource.URN]resourcegraph.OperationTiming
+			if cmdOpts.criticalPath {
+				if cmdOpts.eventLogPath == "" {
+					return errors.New("--critical-path needs the event log of a deployment; " +
+						"pass the file written by `pulumi up --event-log` with --event-log")
+				}
+				var err error
+				if timings, err = readEventLogTimings(cmdOpts.eventLogPath); err != nil {
+					return err
+				}
+			}
+
 			s, err := requireStack(ctx, cmdOpts.stackName, stackLoadOnly, opts)
 			if err != nil {
 				return err
@@ -84,8 +116,19 @@ func newStackGraphCmd() *cobra.Command {
 				return fmt.Errorf("unable to find snapshot for stack %q", cmdOpts.stackName)
 			}
 
+			if cycle := resourcegraph.FindCycle(snap.Resources); cycle != nil {
+				cmdutil.Diag().Warningf(diag.Message("", "the stack's dependencies contain a cycle: %s"),
+					formatURNCycle(cycle))
+			}
+
 			dg := makeDependencyGraph(snap, &cmdOpts)
 
+			var analysis *resourcegraph.TimingAnalysis
+			if cmdOpts.criticalPath {
+				analysis = resourcegraph.AnalyzeTimings(snap.Resources, timings)
+				annotateCriticalPath(dg, analysis, cmdOpts.criticalPathEdgeColor)
+			}
+
 			file, err := os.Create(args[0])
 			if err != nil {
 				return err
@@ -98,6 +141,10 @@ func newStackGraphCmd() *cobra.Command {
 
 			cmd.Printf("%sWrote stack dependency graph to `%s`", cmdutil.EmojiOr("🔍 ", ""), args[0])
 			cmd.Println()
+			if analysis != nil {
+				cmd.Println()
+				cmd.Print(display.RenderTimingAnalysis(analysis, opts))
+			}
 			return file.Close()
 		}),
 	}
@@ -116,9 +163,44 @@ func newStackGraphCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&cmdOpts.dotFragment, "dot-fragment", "",
 		"An optional DOT fragment that will be inserted at the top of the digraph element. "+
 			"This can be used for styling the graph elements, setting graph properties etc.")
+	cmd.PersistentFlags().BoolVar(&cmdOpts.criticalPath, "critical-path", false,
+		"Highlights the chain of resource operations that determined how long the deployment in --event-log took, "+
+			"and labels each resource with how long its operation waited and worked")
+	cmd.PersistentFlags().StringVar(&cmdOpts.eventLogPath, "event-log", "",
+		"The event log of the deployment to analyze with --critical-path, as written by --event-log")
+	cmd.PersistentFlags().StringVar(&cmdOpts.criticalPathEdgeColor, "critical-path-edge-color", "#D62728",
+		"Sets the color of critical path edges in the graph")
 	return cmd
 }
 
+// readEventLogTimings reads the timings of a deployment's operations from the event log at the given path.
+func readEventLogTimings(path string) (map[resource.URN]resourcegraph.OperationTiming, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(f)
+
+	timings, err := display.ReadEventLogTimings(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(timings) == 0 {
+		return nil, fmt.Errorf("%s has no timed resource operations; "+
+			"pass the event log of an update, destroy or refresh", path)
+	}
+	return timings, nil
+}
+
+// formatURNCycle formats a cycle of URNs as a chain of resource names.
+func formatURNCycle(cycle []resource.URN) string {
+	names := make([]string, len(cycle))
+	for i, urn := range cycle {
+		names[i] = urn.Name()
+	}
+	return strings.Join(names, " -> ")
+}
+
 // All of the types and code within this file are to provide implementations of the interfaces
 // in the `graph` package, so that we can use the `dotconv` package to output our graph in the
 // DOT format.
@@ -192,6 +274,9 @@ type dependencyVertex struct {
 	incomingEdges []graph.Edge
 	outgoingEdges []graph.Edge
 	useShortName  bool
+	// timing is the analyzed timing of the operation on this resource, if it was operated on and the critical path
+	// was requested.
+	timing *resourcegraph.ResourceTiming
 }
 
 func (vertex *dependencyVertex) Data() interface{} {
@@ -199,10 +284,16 @@ func (vertex *dependencyVertex) Data() interface{} {
 }
 
 func (vertex *dependencyVertex) Label() string {
+	label := string(vertex.resource.URN)
 	if vertex.useShortName {
-		return vertex.resource.URN.Name()
+		label = vertex.resource.URN.Name()
 	}
-	return string(vertex.resource.URN)
+	if vertex.timing != nil {
+		// DOT interprets \n within a label as a line break.
+		label += fmt.Sprintf("\\nwork %v, waited %v",
+			vertex.timing.Work.Round(10*time.Millisecond), vertex.timing.Wait.Round(10*time.Millisecond))
+	}
+	return label
 }
 
 func (vertex *dependencyVertex) Ins() []graph.Edge {
@@ -293,3 +384,29 @@ func makeDependencyGraph(snapshot *deploy.Snapshot, opts *graphCommandOptions) *
 
 	return dg
 }
+
+// annotateCriticalPath records the analyzed timing of each resource's operation on its vertex, and adds edges between
+// consecutive operations on the critical path. Resources that no longer exist, such as those that were deleted, have
+// no vertex, so the critical path is broken around them.
+func annotateCriticalPath(dg *dependencyGraph, analysis *resourcegraph.TimingAnalysis, color string) {
+	for i := range analysis.Resources {
+		if vertex, ok := dg.vertices[analysis.Resources[i].URN]; ok {
+			vertex.timing = &analysis.Resources[i]
+		}
+	}
+
+	var previous *dependencyVertex
+	for _, timing := range analysis.CriticalPath {
+		vertex, ok := dg.vertices[timing.URN]
+		if !ok {
+			previous = nil
+			continue
+		}
+		if previous != nil {
+			edge := &dependencyEdge{to: vertex, from: previous, labels: []string{"critical path"}, color: color}
+			vertex.incomingEdges = append(vertex.incomingEdges, edge)
+			previous.outgoingEdges = append(previous.outgoingEdges, edge)
+		}
+		previous = vertex
+	}
+}
@@ -17,10 +17,13 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pulumi/pulumi/pkg/v3/graph/dotconv"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	resourcegraph "github.com/pulumi/pulumi/pkg/v3/resource/graph"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/stretchr/testify/require"
 )
@@ -149,5 +152,35 @@ func TestStackGraphCmd(t *testing.T) {
 				require.Contains(t, dotOutput, fmt.Sprintf("[label=\"%s\"]", label))
 			}
 		})
+
+		t.Run("with criticalPath flag", func(t *testing.T) {
+			t.Parallel()
+
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			analysis := resourcegraph.AnalyzeTimings(snap.Resources, map[resource.URN]resourcegraph.OperationTiming{
+				parent: {Start: start, End: start.Add(2 * time.Second)},
+				child:  {Start: start.Add(3 * time.Second), End: start.Add(5 * time.Second)},
+			})
+
+			opts := graphCommandOptions{
+				shortNodeName:         true,
+				ignoreParentEdges:     true,
+				criticalPath:          true,
+				criticalPathEdgeColor: "#D62728",
+			}
+			dg := makeDependencyGraph(&snap, &opts)
+			annotateCriticalPath(dg, analysis, opts.criticalPathEdgeColor)
+
+			var outputBuf bytes.Buffer
+			require.NoError(t, dotconv.Print(dg, &outputBuf, opts.dotFragment))
+
+			dotOutput := outputBuf.String()
+
+			require.Contains(t, dotOutput, `[label="provider"]`)
+			require.Contains(t, dotOutput, `[label="parent\nwork 2s, waited 0s"]`)
+			require.Contains(t, dotOutput, `[label="child\nwork 2s, waited 1s"]`)
+			require.Contains(t, dotOutput, ` [color = "#D62728", label = "critical path"];`)
+			require.Equal(t, 1, strings.Count(dotOutput, " -> "))
+		})
 	})
 }
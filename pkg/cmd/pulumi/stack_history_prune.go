@@ -0,0 +1,118 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+// newStackHistoryPruneCmd creates the `stack history prune` command. stack points at the parent command's
+// --stack flag.
+func newStackHistoryPruneCmd(stack *string) *cobra.Command {
+	var yes bool
+	var keepLast int
+	var keepFor time.Duration
+	var deltas bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Args:  cmdutil.NoArgs,
+		Short: "Remove old update history and checkpoint backups for a stack",
+		Long: "Remove old update history and checkpoint backups for a stack.\n" +
+			"\n" +
+			"This command deletes the update history entries and checkpoint backups of a stack\n" +
+			"that are not kept by the retention policy. An entry is kept if it is one of the\n" +
+			"`--keep-last` most recent entries, or if it is newer than `--keep-for`.\n" +
+			"\n" +
+			"If none of the policy flags are given, the retention policy configured in the\n" +
+			"backend's `.pulumi/meta.yaml` is used. With `--deltas`, retained history checkpoints\n" +
+			"other than the most recent one are stored as deltas against their successor.\n" +
+			"\n" +
+			"This command is only supported by DIY backends.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(ctx, *stack, stackLoadOnly, opts)
+			if err != nil {
+				return err
+			}
+			b, ok := s.Backend().(diy.Backend)
+			if !ok {
+				return errors.New("pruning stack history is only supported by DIY backends")
+			}
+
+			var retention *diy.HistoryRetention
+			if cmd.Flags().Changed("keep-last") || cmd.Flags().Changed("keep-for") || deltas {
+				retention = &diy.HistoryRetention{
+					KeepLast:         keepLast,
+					DeltaCheckpoints: deltas,
+				}
+				if keepFor != 0 {
+					retention.KeepFor = keepFor.String()
+				}
+			}
+
+			stackName := s.Ref().String()
+			if !yes && !cmdutil.Interactive() {
+				return errors.New("--yes must be passed in to proceed when running in non-interactive mode")
+			}
+			prompt := fmt.Sprintf("This will permanently delete old update history for '%s'!", stackName)
+			if !yes && !confirmPrompt(prompt, stackName, opts) {
+				return result.FprintBailf(os.Stdout, "confirmation declined")
+			}
+
+			res, err := b.PruneHistory(ctx, s.Ref(), retention)
+			if err != nil {
+				return fmt.Errorf("pruning history: %w", err)
+			}
+
+			fmt.Printf("Removed %d history entries and %d backups for '%s'\n",
+				res.HistoryEntriesRemoved, res.BackupsRemoved, stackName)
+			if res.CheckpointsCompacted > 0 {
+				fmt.Printf("Compacted %d checkpoints\n", res.CheckpointsCompacted)
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Skip confirmation prompts, and proceed with pruning anyway")
+	cmd.Flags().IntVar(
+		&keepLast, "keep-last", 0,
+		"Keep this many of the most recent updates")
+	cmd.Flags().DurationVar(
+		&keepFor, "keep-for", 0,
+		"Keep updates newer than this duration, for example '720h'")
+	cmd.Flags().BoolVar(
+		&deltas, "deltas", false,
+		"Store retained checkpoints as deltas against the following update")
+
+	return cmd
+}
@@ -103,6 +103,23 @@ func newStackImportCmd() *cobra.Command {
 }
 
 func saveSnapshot(ctx context.Context, s backend.Stack, snapshot *deploy.Snapshot, force bool) error {
+	dep, err := prepareImportDeployment(ctx, s, snapshot, force)
+	if err != nil {
+		return err
+	}
+
+	// Now perform the deployment.
+	if err = s.ImportDeployment(ctx, dep); err != nil {
+		return fmt.Errorf("could not import deployment: %w", err)
+	}
+	return nil
+}
+
+// prepareImportDeployment checks that the given snapshot is safe to import into the given stack, clears out any
+// pending operations, and serializes it for import.
+func prepareImportDeployment(
+	ctx context.Context, s backend.Stack, snapshot *deploy.Snapshot, force bool,
+) (*apitype.UntypedDeployment, error) {
 	stackName := s.Ref().Name()
 	var result error
 	for _, res := range snapshot.Resources {
@@ -133,7 +150,7 @@ func saveSnapshot(ctx context.Context, s backend.Stack, snapshot *deploy.Snapsho
 		}
 	}
 	if result != nil {
-		return multierror.Append(result,
+		return nil, multierror.Append(result,
 			errors.New("importing this file could be dangerous; rerun with --force to proceed anyway"))
 	}
 
@@ -149,22 +166,16 @@ func saveSnapshot(ctx context.Context, s backend.Stack, snapshot *deploy.Snapsho
 	}
 	sdp, err := stack.SerializeDeployment(ctx, snapshot, false /* showSecrets */)
 	if err != nil {
-		return fmt.Errorf("constructing deployment for upload: %w", err)
+		return nil, fmt.Errorf("constructing deployment for upload: %w", err)
 	}
 
 	bytes, err := json.Marshal(sdp)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	dep := apitype.UntypedDeployment{
+	return &apitype.UntypedDeployment{
 		Version:    apitype.DeploymentSchemaVersionCurrent,
 		Deployment: bytes,
-	}
-
-	// Now perform the deployment.
-	if err = s.ImportDeployment(ctx, &dep); err != nil {
-		return fmt.Errorf("could not import deployment: %w", err)
-	}
-	return nil
+	}, nil
 }
@@ -30,7 +30,7 @@ import (
 
 const (
 	possibleSecretsProviderChoices = "The type of the provider that should be used to encrypt and decrypt secrets\n" +
-		"(possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault)"
+		"(possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault, age, pgp)"
 )
 
 func newStackInitCmd() *cobra.Command {
@@ -64,6 +64,12 @@ func newStackInitCmd() *cobra.Command {
 			"* `pulumi stack init --secrets-provider=\"gcpkms://projects/<p>/locations/<l>/keyRings/<r>/cryptoKeys/<k>\"`\n" +
 			"* `pulumi stack init --secrets-provider=\"hashivault://mykey\"\n`" +
 			"\n" +
+			"To encrypt secrets to the holders of age or PGP keys, list their public keys, or the full fingerprints of\n" +
+			"their PGP encryption subkeys:\n" +
+			"\n" +
+			"* `pulumi stack init --secrets-provider=\"age://?recipient=age1...&recipient=age1...\"`\n" +
+			"* `pulumi stack init --secrets-provider=\"pgp://?recipient=<fingerprint>&recipient=<fingerprint>\"`\n" +
+			"\n" +
 			"A stack can be created based on the configuration of an existing stack by passing the\n" +
 			"`--copy-config-from` flag.\n" +
 			"* `pulumi stack init --copy-config-from dev`",
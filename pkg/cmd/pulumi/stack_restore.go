@@ -0,0 +1,149 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newStackRestoreCmd() *cobra.Command {
+	var stackName string
+	var version int
+	var force bool
+	var yes bool
+	var showSecrets bool
+
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Args:  cmdutil.NoArgs,
+		Short: "Restore a stack's state to a previous version",
+		Long: "Restore a stack's state to a previous version.\n" +
+			"\n" +
+			"The state as it was after the given version from `pulumi stack history` is fetched from\n" +
+			"the backend, and the differences from the stack's current state are shown. Once confirmed,\n" +
+			"the state is imported into the stack as a new entry in its history, so that the restore\n" +
+			"itself can be audited and undone.\n" +
+			"\n" +
+			"Only the stack's state is restored: no cloud resources are changed. Run `pulumi refresh`\n" +
+			"or `pulumi up` afterwards to reconcile the restored state with the resources themselves.",
+		Example: "pulumi stack restore --version 41",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if version <= 0 {
+				return errors.New("--version must be specified as a positive version number")
+			}
+
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return err
+			}
+
+			current, err := loadStackDeployment(ctx, s, "")
+			if err != nil {
+				return err
+			}
+			restoredDeployment, err := exportStackVersion(ctx, s, strconv.Itoa(version))
+			if err != nil {
+				return err
+			}
+			restored, err := stack.DeserializeUntypedDeployment(ctx, restoredDeployment, stack.DefaultSecretsProvider)
+			if err != nil {
+				return checkDeploymentVersionError(err, s.Ref().Name().String())
+			}
+
+			if showSecrets {
+				log3rdPartySecretsProviderDecryptionEvent(ctx, s, "", "pulumi stack restore")
+			}
+
+			fmt.Printf("Restoring stack %s to version %d will make the following changes to its state:\n\n",
+				s.Ref(), version)
+			diffs := diffSnapshots(current, restored, showSecrets)
+			renderSnapshotDiff(os.Stdout, diffs, opts)
+			fmt.Println()
+
+			// Check the restored state before asking for confirmation, so that a broken version is caught early.
+			dep, err := prepareImportDeployment(ctx, s, restored, force)
+			if err != nil {
+				return err
+			}
+
+			if !yes && cmdutil.Interactive() {
+				if err := confirmStateEdit(opts, "Do you want to restore this version?"); err != nil {
+					return err
+				}
+			}
+
+			if err := restoreStackVersion(ctx, s, dep, version); err != nil {
+				return err
+			}
+			fmt.Printf("Stack %s restored to version %d.\n", s.Ref(), version)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().IntVar(&version, "version", 0, "The version from the stack's history to restore")
+	cmd.Flags().BoolVarP(
+		&force, "force", "f", false,
+		"Restore the version even if its state has apparent errors (not recommended)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show secret values in plaintext")
+	return cmd
+}
+
+// restoreStackVersion imports the given deployment of a previous version of the stack. If the backend supports it,
+// the import is recorded in the stack's history.
+func restoreStackVersion(
+	ctx context.Context, s backend.Stack, dep *apitype.UntypedDeployment, version int,
+) error {
+	message := fmt.Sprintf("Restored version %d", version)
+	if err := importDeploymentWithHistory(ctx, s, dep, message); err != nil {
+		return fmt.Errorf("could not restore deployment: %w", err)
+	}
+	return nil
+}
+
+// importDeploymentWithHistory imports the given deployment into the stack. If the backend supports it, the import is
+// recorded in the stack's history with the given message.
+func importDeploymentWithHistory(
+	ctx context.Context, s backend.Stack, dep *apitype.UntypedDeployment, message string,
+) error {
+	restorer, ok := s.Backend().(backend.DeploymentRestorer)
+	if !ok {
+		cmdutil.Diag().Warningf(diag.Message("", "the current backend (%s) does not record imports in the "+
+			"stack's history; this change will not appear in `pulumi stack history`"), s.Backend().Name())
+		return s.ImportDeployment(ctx, dep)
+	}
+	return restorer.RestoreDeployment(ctx, s, dep, message)
+}
@@ -0,0 +1,226 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/deepcopy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+type stackRotateSecretsCmd struct {
+	stdout io.Writer
+
+	stack     string
+	backupDir string
+
+	secretsProvider secrets.Provider
+}
+
+func newStackRotateSecretsCmd() *cobra.Command {
+	var srscmd stackRotateSecretsCmd
+	cmd := &cobra.Command{
+		Use:   "rotate-secrets",
+		Args:  cmdutil.NoArgs,
+		Short: "Encrypt a stack's secrets with a new data key",
+		Long: "Encrypt a stack's secrets with a new data key.\n" +
+			"\n" +
+			"This command generates a new data key with the stack's current secrets provider, and encrypts\n" +
+			"every secret in the stack's configuration and state with it. A stack that uses a cloud secrets\n" +
+			"provider keeps the same key management service key, and a stack that uses the `passphrase`\n" +
+			"secrets provider keeps the same passphrase; use `pulumi stack change-secrets-provider` to change\n" +
+			"these instead. The secrets of stacks that use the Pulumi Cloud's secrets provider are managed by\n" +
+			"the Pulumi Cloud, and can't be rotated with this command.\n" +
+			"\n" +
+			"The stack's configuration and state are backed up before they are changed, to a directory under\n" +
+			"~/.pulumi/backups unless --backup-dir is given, and the new state is recorded in the stack's\n" +
+			"history. If the configuration can't be saved once the state has been, the state is restored, so\n" +
+			"that the two always use the same data key. The stack is locked while this happens, if its backend\n" +
+			"supports it.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return srscmd.Run(ctx)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&srscmd.stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().StringVar(
+		&srscmd.backupDir, "backup-dir", "",
+		"The directory to back up the stack's configuration and state to")
+
+	return cmd
+}
+
+func (cmd *stackRotateSecretsCmd) Run(ctx context.Context) error {
+	stdout := cmd.stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if cmd.secretsProvider == nil {
+		cmd.secretsProvider = stack.DefaultSecretsProvider
+	}
+
+	opts := display.Options{
+		Color: cmdutil.GetGlobalColorization(),
+	}
+
+	project, _, err := readProject()
+	if err != nil {
+		return err
+	}
+	s, err := requireStack(ctx, cmd.stack, stackLoadOnly, opts)
+	if err != nil {
+		return err
+	}
+
+	// Hold the lock on the stack until both its state and configuration have been saved, so that an update can't
+	// write state encrypted with the old data key in between.
+	if locker, ok := s.Backend().(backend.StackLocker); ok {
+		if err := locker.Lock(ctx, s.Ref()); err != nil {
+			return err
+		}
+		defer locker.Unlock(ctx, s.Ref())
+	}
+
+	ps, err := loadProjectStack(project, s)
+	if err != nil {
+		return err
+	}
+
+	// Decrypt the stack's current configuration and state before anything is changed.
+	decrypter, _, err := getStackDecrypter(s, ps)
+	if err != nil {
+		return err
+	}
+	checkpoint, err := s.ExportDeployment(ctx)
+	if err != nil {
+		return err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(ctx, checkpoint, cmd.secretsProvider)
+	if err != nil {
+		return checkDeploymentVersionError(err, s.Ref().Name().String())
+	}
+
+	// Generate the new data key, and encrypt the configuration and state with it.
+	rotated := deepcopy.Copy(ps).(*workspace.ProjectStack)
+	secretsManager, err := rotateSecretsManager(rotated)
+	if err != nil {
+		return err
+	}
+	encrypter, err := secretsManager.Encrypter()
+	if err != nil {
+		return err
+	}
+	rotated.Config, err = ps.Config.Copy(decrypter, encrypter)
+	if err != nil {
+		return err
+	}
+	snap.SecretsManager = secretsManager
+	sdep, err := stack.SerializeDeployment(ctx, snap, false /* showSecrets */)
+	if err != nil {
+		return fmt.Errorf("serializing deployment: %w", err)
+	}
+	bytes, err := json.Marshal(sdep)
+	if err != nil {
+		return err
+	}
+	deployment := &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	}
+
+	backupDir := cmd.backupDir
+	if backupDir == "" {
+		backupDir, err = workspace.GetPulumiPath(workspace.BackupDir, "secrets", project.Name.String(),
+			s.Ref().Name().String()+"."+strconv.FormatInt(time.Now().UnixNano(), 10))
+		if err != nil {
+			return err
+		}
+	}
+	if err := backupStackSecrets(backupDir, s, ps, checkpoint); err != nil {
+		return fmt.Errorf("backing up the stack: %w", err)
+	}
+	fmt.Fprintf(stdout, "Backed up the stack's configuration and state to %s\n", backupDir)
+
+	// Save the state first: if it can't be saved, the configuration is left as it was. If the configuration can't
+	// be saved afterwards, put the old state back, recording that in the stack's history too.
+	if err := importDeploymentWithHistory(ctx, s, deployment, "Rotated the secrets data key"); err != nil {
+		return fmt.Errorf("saving the stack's state: %w", err)
+	}
+	if err := saveProjectStack(s, rotated); err != nil {
+		rerr := importDeploymentWithHistory(ctx, s, checkpoint, "Restored the state from before the secrets data key "+
+			"was rotated")
+		if rerr != nil {
+			return fmt.Errorf("saving the stack's configuration: %w; restoring its state also failed, "+
+				"restore it from %s with `pulumi stack import`: %w", err, backupDir, rerr)
+		}
+		return fmt.Errorf("saving the stack's configuration: %w", err)
+	}
+
+	fmt.Fprintf(stdout, "Encrypted the stack's secrets with a new data key\n")
+	return nil
+}
+
+// rotateSecretsManager returns a secrets manager with a new data key for a stack's current secrets provider,
+// recording its details in the given project stack.
+func rotateSecretsManager(ps *workspace.ProjectStack) (secrets.Manager, error) {
+	switch {
+	case ps.SecretsProvider != passphrase.Type && ps.SecretsProvider != "default" && ps.SecretsProvider != "":
+		return cloud.NewCloudSecretsManager(ps, ps.SecretsProvider, true /* rotateSecretsProvider */)
+	case ps.EncryptionSalt != "":
+		return passphrase.RotatePassphraseSecretsManager(ps)
+	default:
+		return nil, errors.New("the stack's secrets provider does not have a data key that can be rotated")
+	}
+}
+
+// backupStackSecrets writes a stack's configuration and checkpoint to the given directory, in the formats of
+// `Pulumi.<stack>.yaml` and `pulumi stack export`.
+func backupStackSecrets(
+	dir string, s backend.Stack, ps *workspace.ProjectStack, checkpoint *apitype.UntypedDeployment,
+) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	if err := ps.Save(filepath.Join(dir, "Pulumi."+s.Ref().Name().String()+".yaml")); err != nil {
+		return err
+	}
+	bytes, err := json.MarshalIndent(checkpoint, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, s.Ref().Name().String()+".json"), bytes, 0o600)
+}
@@ -0,0 +1,177 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRestoringBackend struct {
+	backend.MockBackend
+
+	restore func(ctx context.Context, deployment *apitype.UntypedDeployment, message string) error
+	// locks is the number of times the stack's lock is held.
+	locks int
+}
+
+func (b *mockRestoringBackend) RestoreDeployment(ctx context.Context, _ backend.Stack,
+	deployment *apitype.UntypedDeployment, message string,
+) error {
+	return b.restore(ctx, deployment, message)
+}
+
+func (b *mockRestoringBackend) Lock(context.Context, backend.StackReference) error {
+	b.locks++
+	return nil
+}
+
+func (b *mockRestoringBackend) Unlock(context.Context, backend.StackReference) {
+	b.locks--
+}
+
+// Test that rotating the secrets of a stack that uses the passphrase provider encrypts its config and state with a
+// new key derived from the same passphrase, backs them up, and records the rotation in the stack's history.
+//
+//nolint:paralleltest // mutates global state
+func TestStackRotateSecrets(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("PULUMI_CONFIG_PASSPHRASE", "password")
+
+	salt, secretsManager, err := passphrase.NewPassphraseSecretsManager("password")
+	require.NoError(t, err)
+	encrypter, err := secretsManager.Encrypter()
+	require.NoError(t, err)
+	secretBar, err := encrypter.EncryptValue(ctx, "bar")
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	chdir(t, tmpDir)
+	err = os.WriteFile("Pulumi.yaml", []byte(`
+name: testProject
+runtime: mock
+`), 0o600)
+	require.NoError(t, err)
+	cfgKey := config.MustMakeKey("testStack", "secret")
+	cfg := workspace.ProjectStack{
+		EncryptionSalt: salt,
+		Config:         config.Map{cfgKey: config.NewSecureValue(secretBar)},
+	}
+	require.NoError(t, cfg.Save("Pulumi.testStack.yaml"))
+
+	chk, err := stack.SerializeDeployment(ctx, &deploy.Snapshot{
+		SecretsManager: secretsManager,
+		Resources: []*resource.State{
+			{
+				URN:  resource.NewURN("testStack", "testProject", "", resource.RootStackType, "testStack"),
+				Type: resource.RootStackType,
+				Outputs: resource.PropertyMap{
+					"foo": resource.MakeSecret(resource.NewStringProperty("bar")),
+				},
+			},
+		},
+	}, false)
+	require.NoError(t, err)
+	data, err := encoding.JSON.Marshal(chk)
+	require.NoError(t, err)
+	deployment := &apitype.UntypedDeployment{Version: 3, Deployment: data}
+
+	var messages []string
+	var mockBackend *mockRestoringBackend
+	mockBackend = &mockRestoringBackend{
+		restore: func(ctx context.Context, d *apitype.UntypedDeployment, message string) error {
+			// The state is saved while the stack is locked.
+			assert.Equal(t, 1, mockBackend.locks)
+			deployment = d
+			messages = append(messages, message)
+			return nil
+		},
+	}
+	mockStack := &backend.MockStack{
+		RefF: func() backend.StackReference {
+			return &backend.MockStackReference{
+				StringV: "testStack",
+				NameV:   tokens.MustParseStackName("testStack"),
+			}
+		},
+		BackendF: func() backend.Backend { return mockBackend },
+		ExportDeploymentF: func(ctx context.Context) (*apitype.UntypedDeployment, error) {
+			return deployment, nil
+		},
+	}
+	mockBackend.GetStackF = func(ctx context.Context, stackRef backend.StackReference) (backend.Stack, error) {
+		return mockStack, nil
+	}
+	mockBackendInstance(t, mockBackend)
+
+	var stdoutBuff bytes.Buffer
+	backupDir := filepath.Join(tmpDir, "backup")
+	cmd := stackRotateSecretsCmd{
+		stdout:    &stdoutBuff,
+		stack:     "testStack",
+		backupDir: backupDir,
+	}
+	require.NoError(t, cmd.Run(ctx))
+	assert.Equal(t, "Backed up the stack's configuration and state to "+backupDir+"\n"+
+		"Encrypted the stack's secrets with a new data key\n", stdoutBuff.String())
+	assert.Equal(t, []string{"Rotated the secrets data key"}, messages)
+	assert.Equal(t, 0, mockBackend.locks)
+
+	// The config has a new salt for the same passphrase, and its secret has been encrypted again.
+	project, err := workspace.LoadProject("Pulumi.yaml")
+	require.NoError(t, err)
+	projectStack, err := workspace.LoadProjectStack(project, "Pulumi.testStack.yaml")
+	require.NoError(t, err)
+	assert.NotEqual(t, salt, projectStack.EncryptionSalt)
+	assert.NotEqual(t, config.NewSecureValue(secretBar), projectStack.Config[cfgKey])
+	newSecretsManager, err := passphrase.GetPassphraseSecretsManager("password", projectStack.EncryptionSalt)
+	require.NoError(t, err)
+	decrypter, err := newSecretsManager.Decrypter()
+	require.NoError(t, err)
+	val, err := projectStack.Config[cfgKey].Value(decrypter)
+	require.NoError(t, err)
+	assert.Equal(t, "bar", val)
+
+	// So has the state's.
+	snap, err := stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	assert.Equal(t, newSecretsManager.State(), snap.SecretsManager.State())
+	assert.Equal(t, resource.NewStringProperty("bar"), snap.Resources[0].Outputs["foo"].SecretValue().Element)
+
+	// The old config and state have been backed up.
+	backupStack, err := workspace.LoadProjectStack(project, filepath.Join(backupDir, "Pulumi.testStack.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, salt, backupStack.EncryptionSalt)
+	assert.Equal(t, config.NewSecureValue(secretBar), backupStack.Config[cfgKey])
+	backupState, err := os.ReadFile(filepath.Join(backupDir, "testStack.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(backupState), salt)
+}
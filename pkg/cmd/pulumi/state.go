@@ -55,7 +55,9 @@ troubleshooting a stack or when performing specific edits that otherwise would r
 	cmd.AddCommand(newStateDeleteCommand())
 	cmd.AddCommand(newStateUnprotectCommand())
 	cmd.AddCommand(newStateRenameCommand())
+	cmd.AddCommand(newStateMoveCommand())
 	cmd.AddCommand(newStateUpgradeCommand())
+	cmd.AddCommand(newStateRecoverCommand())
 	return cmd
 }
 
@@ -157,14 +159,8 @@ func totalStateEdit(ctx context.Context, s backend.Stack, showPrompt bool, opts
 	}
 
 	if showPrompt && cmdutil.Interactive() {
-		confirm := false
-		surveycore.DisableColor = true
-		prompt := opts.Color.Colorize(colors.Yellow + "warning" + colors.Reset + ": ")
-		prompt += "This command will edit your stack's state directly. Confirm?"
-		if err = survey.AskOne(&survey.Confirm{
-			Message: prompt,
-		}, &confirm, surveyIcons(opts.Color)); err != nil || !confirm {
-			return result.FprintBailf(os.Stdout, "confirmation declined")
+		if err := confirmStateEdit(opts, "This command will edit your stack's state directly. Confirm?"); err != nil {
+			return err
 		}
 	}
 
@@ -181,12 +177,79 @@ func totalStateEdit(ctx context.Context, s backend.Stack, showPrompt bool, opts
 		contract.AssertNoErrorf(snap.VerifyIntegrity(), "state edit produced an invalid snapshot")
 	}
 
+	// Once we've mutated the snapshot, import it back into the backend so that it can be persisted.
+	return importSnapshot(ctx, s, snap)
+}
+
+// confirmStateEdit asks the user to confirm a state edit, returning a bail error if they decline.
+func confirmStateEdit(opts display.Options, message string) error {
+	confirm := false
+	surveycore.DisableColor = true
+	prompt := opts.Color.Colorize(colors.Yellow + "warning" + colors.Reset + ": ")
+	prompt += message
+	if err := survey.AskOne(&survey.Confirm{
+		Message: prompt,
+	}, &confirm, surveyIcons(opts.Color)); err != nil || !confirm {
+		return result.FprintBailf(os.Stdout, "confirmation declined")
+	}
+	return nil
+}
+
+// runSelectedStateEdit runs the given state edit function on every resource in the given stack that matches any of
+// the given selectors. expand may add further resources that the edit will affect, such as dependents. Before the
+// snapshot is mutated, the affected resources are listed and the user may be prompted for confirmation.
+func runSelectedStateEdit(
+	ctx context.Context, stackName string, showPrompt bool, selectors []string, verb string,
+	expand func(snap *deploy.Snapshot, selected []*resource.State) []*resource.State,
+	operation edit.OperationFunc,
+) error {
+	sels := make([]*edit.Selector, len(selectors))
+	for i, text := range selectors {
+		sel, err := edit.ParseSelector(text)
+		if err != nil {
+			return err
+		}
+		sels[i] = sel
+	}
+
+	// The confirmation prompt is shown by this edit, after the summary of the affected resources.
+	return runTotalStateEdit(ctx, stackName, false, func(opts display.Options, snap *deploy.Snapshot) error {
+		selected := edit.SelectResources(snap, sels)
+		if len(selected) == 0 {
+			return errors.New("no resources match the given selectors")
+		}
+		if expand != nil {
+			selected = expand(snap, selected)
+		}
+
+		fmt.Printf("The following %d resources will be %s:\n", len(selected), verb)
+		for _, res := range selected {
+			fmt.Printf("  - %s\n", res.URN)
+		}
+		fmt.Println()
+
+		if showPrompt && cmdutil.Interactive() {
+			if err := confirmStateEdit(opts, "This command will edit your stack's state directly. Confirm?"); err != nil {
+				return err
+			}
+		}
+
+		for _, res := range selected {
+			if err := operation(snap, res); err != nil {
+				return fmt.Errorf("%s: %w", res.URN, err)
+			}
+		}
+		return nil
+	})
+}
+
+// importSnapshot serializes the given snapshot with its secrets manager and imports it into the given stack.
+func importSnapshot(ctx context.Context, s backend.Stack, snap *deploy.Snapshot) error {
 	sdep, err := stack.SerializeDeployment(ctx, snap, false /* showSecrets */)
 	if err != nil {
 		return fmt.Errorf("serializing deployment: %w", err)
 	}
 
-	// Once we've mutated the snapshot, import it back into the backend so that it can be persisted.
 	bytes, err := json.Marshal(sdep)
 	if err != nil {
 		return err
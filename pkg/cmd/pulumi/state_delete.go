@@ -20,18 +20,22 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/pkg/v3/resource/graph"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
 )
 
+//nolint:lll
 func newStateDeleteCommand() *cobra.Command {
 	var force bool // Force deletion of protected resources
 	var stack string
 	var yes bool
 	var targetDependents bool
+	var selectors []string
 
 	cmd := &cobra.Command{
 		Use:   "delete [resource URN]",
@@ -44,16 +48,57 @@ by its Pulumi URN. If the URN is omitted, this command will prompt for it.
 Resources can't be deleted if other resources depend on it or are parented to it. Protected resources
 will not be deleted unless specifically requested using the --force flag.
 
+Rather than a single URN, the resources to delete can be chosen with one or more --select flags. A
+resource is deleted if it matches any of the selectors. The affected resources are listed before the
+stack's state is changed.
+
+` + edit.SelectorHelp + `
+
 Make sure that URNs are single-quoted to avoid having characters unexpectedly interpreted by the shell.
 
 To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 `,
-		Example: "pulumi state delete 'urn:pulumi:stage::demo::eks:index:Cluster$pulumi:providers:kubernetes::eks-provider'",
-		Args:    cmdutil.MaximumNArgs(1),
+		Example: "pulumi state delete 'urn:pulumi:stage::demo::eks:index:Cluster$pulumi:providers:kubernetes::eks-provider'\n" +
+			"pulumi state delete --select 'type=aws:s3/bucket:Bucket ancestor=*::my-component'",
+		Args: cmdutil.MaximumNArgs(1),
 
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			yes = yes || skipConfirmations()
+			// Show the confirmation prompt if the user didn't pass the --yes parameter to skip it.
+			showPrompt := !yes
+
+			var handleProtected func(*resource.State) error
+			if force {
+				handleProtected = func(res *resource.State) error {
+					cmdutil.Diag().Warningf(diag.Message(res.URN,
+						"deleting protected resource %s due to presence of --force"), res.URN)
+					return edit.UnprotectResource(nil, res)
+				}
+			}
+
+			if len(selectors) > 0 {
+				if len(args) > 0 {
+					return errors.New("a resource URN can't be given along with --select")
+				}
+				err := runSelectedStateEdit(ctx, stack, showPrompt, selectors, "deleted",
+					func(snap *deploy.Snapshot, selected []*resource.State) []*resource.State {
+						return expandStateDeletes(snap, selected, targetDependents)
+					},
+					func(snap *deploy.Snapshot, res *resource.State) error {
+						// The resource may already have gone as a dependent of one deleted before it.
+						if !slices.Contains(snap.Resources, res) {
+							return nil
+						}
+						return edit.DeleteResource(snap, res, handleProtected, targetDependents)
+					})
+				if err != nil {
+					return formatStateDeleteError(err)
+				}
+				fmt.Println("Resources deleted")
+				return nil
+			}
+
 			var urn resource.URN
 			if len(args) == 0 {
 				if !cmdutil.Interactive() {
@@ -69,38 +114,11 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 			} else {
 				urn = resource.URN(args[0])
 			}
-			// Show the confirmation prompt if the user didn't pass the --yes parameter to skip it.
-			showPrompt := !yes
-
 			err := runStateEdit(ctx, stack, showPrompt, urn, func(snap *deploy.Snapshot, res *resource.State) error {
-				var handleProtected func(*resource.State) error
-				if force {
-					handleProtected = func(res *resource.State) error {
-						cmdutil.Diag().Warningf(diag.Message(res.URN,
-							"deleting protected resource %s due to presence of --force"), res.URN)
-						return edit.UnprotectResource(nil, res)
-					}
-				}
 				return edit.DeleteResource(snap, res, handleProtected, targetDependents)
 			})
 			if err != nil {
-				switch e := err.(type) {
-				case edit.ResourceHasDependenciesError:
-					message := string(e.Condemned.URN) + " can't be safely deleted because the following resources depend on it:\n"
-					for _, dependentResource := range e.Dependencies {
-						depUrn := dependentResource.URN
-						message += fmt.Sprintf(" * %-15q (%s)\n", depUrn.Name(), depUrn)
-					}
-
-					message += "\nDelete those resources first or pass --target-dependents."
-					return errors.New(message)
-				case edit.ResourceProtectedError:
-					return fmt.Errorf(
-						"%s can't be safely deleted because it is protected. "+
-							"Re-run this command with --force to force deletion", string(e.Condemned.URN))
-				default:
-					return err
-				}
+				return formatStateDeleteError(err)
 			}
 			fmt.Println("Resource deleted")
 			return nil
@@ -113,5 +131,59 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 	cmd.Flags().BoolVar(&force, "force", false, "Force deletion of protected resources")
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
 	cmd.Flags().BoolVar(&targetDependents, "target-dependents", false, "Delete the URN and all its dependents")
+	cmd.Flags().StringArrayVar(&selectors, "select", nil,
+		"Delete all resources that match the given selector. May be specified more than once")
 	return cmd
 }
+
+// expandStateDeletes adds the dependents of the selected resources, if they are to be deleted too, and orders the
+// resources so that dependents are deleted before the resources that they depend on.
+func expandStateDeletes(
+	snap *deploy.Snapshot, selected []*resource.State, targetDependents bool,
+) []*resource.State {
+	condemned := make(map[*resource.State]bool, len(selected))
+	for _, res := range selected {
+		condemned[res] = true
+	}
+	if targetDependents {
+		dg := graph.NewDependencyGraph(snap.Resources)
+		for _, res := range selected {
+			for _, dep := range dg.OnlyDependsOn(res) {
+				condemned[dep] = true
+			}
+		}
+	}
+
+	// Dependents always come after their dependencies in a valid snapshot, so walk it backwards.
+	ordered := make([]*resource.State, 0, len(condemned))
+	for i := len(snap.Resources) - 1; i >= 0; i-- {
+		if res := snap.Resources[i]; condemned[res] {
+			ordered = append(ordered, res)
+		}
+	}
+	return ordered
+}
+
+// formatStateDeleteError turns the errors returned by edit.DeleteResource into messages suitable for the CLI.
+func formatStateDeleteError(err error) error {
+	var depsErr edit.ResourceHasDependenciesError
+	var protectedErr edit.ResourceProtectedError
+	switch {
+	case errors.As(err, &depsErr):
+		message := string(depsErr.Condemned.URN) +
+			" can't be safely deleted because the following resources depend on it:\n"
+		for _, dependentResource := range depsErr.Dependencies {
+			depUrn := dependentResource.URN
+			message += fmt.Sprintf(" * %-15q (%s)\n", depUrn.Name(), depUrn)
+		}
+
+		message += "\nDelete those resources first or pass --target-dependents."
+		return errors.New(message)
+	case errors.As(err, &protectedErr):
+		return fmt.Errorf(
+			"%s can't be safely deleted because it is protected. "+
+				"Re-run this command with --force to force deletion", string(protectedErr.Condemned.URN))
+	default:
+		return err
+	}
+}
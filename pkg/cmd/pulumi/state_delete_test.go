@@ -0,0 +1,47 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// TestExpandStateDeletes tests that selected deletes pick up dependents when asked to, and are ordered so that
+// every resource can be deleted in turn.
+func TestExpandStateDeletes(t *testing.T) {
+	t.Parallel()
+
+	a := &resource.State{URN: "urn:pulumi:dev::proj::t:m:T::a", Type: "t:m:T"}
+	b := &resource.State{URN: "urn:pulumi:dev::proj::t:m:T::b", Type: "t:m:T", Dependencies: []resource.URN{a.URN}}
+	c := &resource.State{URN: "urn:pulumi:dev::proj::t:m:T::c", Type: "t:m:T"}
+	snap := &deploy.Snapshot{Resources: []*resource.State{a, b, c}}
+
+	assert.Equal(t, []*resource.State{a}, expandStateDeletes(snap, []*resource.State{a}, false))
+	assert.Equal(t, []*resource.State{b, a}, expandStateDeletes(snap, []*resource.State{a}, true))
+
+	// Deleting in the returned order leaves a valid snapshot.
+	for _, res := range expandStateDeletes(snap, []*resource.State{a, c}, true) {
+		require.NoError(t, edit.DeleteResource(snap, res, nil, true))
+	}
+	assert.Empty(t, snap.Resources)
+	assert.NoError(t, snap.VerifyIntegrity())
+}
@@ -0,0 +1,562 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/version"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// stateMoveResult describes the outcome of moving resources between two snapshots.
+type stateMoveResult struct {
+	// Moved lists the resources that were removed from the source, in snapshot order.
+	Moved []stateMoveEntry
+	// Copied lists the providers that were copied rather than moved, because resources that remain in the source
+	// stack still use them.
+	Copied []stateMoveEntry
+	// BrokenDependencies describes each dependency between a moved resource and one that was not moved. These
+	// can't be represented across two stacks, so they are removed from both snapshots.
+	BrokenDependencies []string
+}
+
+// stateMoveEntry records the old and new URN of a resource moved between stacks.
+type stateMoveEntry struct {
+	From resource.URN
+	To   resource.URN
+}
+
+// stateMoveOperation moves the resources with the given URNs, along with their children, from the source snapshot to
+// the destination snapshot. The URNs of the moved resources are rewritten for the given destination stack and
+// project, as are any references to them. The providers used by the moved resources are moved with them, or copied
+// if resources left in the source still use them. Both snapshots are mutated in place, and both must pass
+// VerifyIntegrity before and after the move.
+func stateMoveOperation(
+	source, dest *deploy.Snapshot, urns []resource.URN, destStack tokens.QName, destProject tokens.PackageName,
+) (*stateMoveResult, error) {
+	if err := source.VerifyIntegrity(); err != nil {
+		return nil, fmt.Errorf("the source stack's state is invalid: %w", err)
+	}
+	if err := dest.VerifyIntegrity(); err != nil {
+		return nil, fmt.Errorf("the destination stack's state is invalid: %w", err)
+	}
+
+	// Find the requested resources. All of the states that share a URN are moved together, so that resources pending
+	// deletion follow their live counterparts.
+	requested := make(map[resource.URN]bool, len(urns))
+	for _, urn := range urns {
+		found := false
+		for _, res := range source.Resources {
+			if res.URN == urn {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("No such resource %q exists in the source stack", urn)
+		}
+		if urn.QualifiedType() == resource.RootStackType {
+			return nil, fmt.Errorf("the root stack resource %q cannot be moved", urn)
+		}
+		requested[urn] = true
+	}
+
+	// Children always follow their parents in a valid snapshot, so a single pass picks up every descendant.
+	moving := make(map[resource.URN]bool)
+	for _, res := range source.Resources {
+		if requested[res.URN] || (res.Parent != "" && moving[res.Parent]) {
+			moving[res.URN] = true
+		}
+	}
+
+	// referencedByRemaining returns true if any resource that isn't moving refers to the given URN.
+	referencedByRemaining := func(urn resource.URN) bool {
+		for _, res := range source.Resources {
+			if moving[res.URN] || res.URN == urn {
+				continue
+			}
+			if res.Parent == urn || res.DeletedWith == urn || providerURN(res) == urn {
+				return true
+			}
+			for _, dep := range res.Dependencies {
+				if dep == urn {
+					return true
+				}
+			}
+			for _, deps := range res.PropertyDependencies {
+				for _, dep := range deps {
+					if dep == urn {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	// Providers that were asked to be moved can't leave behind resources that still use them.
+	for urn := range moving {
+		if providers.IsProviderType(urn.Type()) {
+			for _, res := range source.Resources {
+				if !moving[res.URN] && providerURN(res) == urn {
+					return nil, fmt.Errorf("provider %q is used by %q, which is not being moved", urn, res.URN)
+				}
+			}
+		}
+	}
+
+	// Pick up the providers of the moved resources. Those that nothing else uses are moved, the rest are copied.
+	implicit := make(map[resource.URN]bool)
+	for _, res := range source.Resources {
+		if prov := providerURN(res); moving[res.URN] && prov != "" && !moving[prov] {
+			implicit[prov] = true
+		}
+	}
+	copying := make(map[resource.URN]bool)
+	for prov := range implicit {
+		if referencedByRemaining(prov) {
+			copying[prov] = true
+		} else {
+			moving[prov] = true
+		}
+	}
+
+	for _, op := range source.PendingOperations {
+		if moving[op.Resource.URN] {
+			return nil, fmt.Errorf("resource %q has a pending %s operation; run `pulumi refresh` to resolve it first",
+				op.Resource.URN, op.Type)
+		}
+	}
+
+	// Resources whose parent isn't moving are reparented to the destination's root stack resource, if it has one.
+	var destRoot resource.URN
+	destResources := make(map[resource.URN]*resource.State)
+	for _, res := range dest.Resources {
+		if res.Type == resource.RootStackType && res.Parent == "" {
+			destRoot = res.URN
+		}
+		destResources[res.URN] = res
+	}
+
+	// Compute the new URNs. Parents come before children, so a parent's new URN is always known by the time we
+	// reach its children.
+	outcome := &stateMoveResult{}
+	newURNs := make(map[resource.URN]resource.URN)
+	newParents := make(map[resource.URN]resource.URN)
+	// Maps the references of moved and copied providers to their references in the destination.
+	providerRefs := make(map[string]string)
+	// The providers that already exist in the destination and are reused rather than copied.
+	reused := make(map[resource.URN]bool)
+	for _, res := range source.Resources {
+		if !moving[res.URN] && !copying[res.URN] {
+			continue
+		}
+
+		newURN, has := newURNs[res.URN]
+		if !has {
+			parentType, newParent := tokens.Type(""), destRoot
+			if parent, has := newURNs[res.Parent]; has {
+				newParent = parent
+				if parent.QualifiedType() != resource.RootStackType {
+					parentType = parent.QualifiedType()
+				}
+			}
+			newURN = resource.NewURN(destStack, destProject, parentType, res.Type, res.URN.Name())
+
+			if existing, has := destResources[newURN]; has {
+				// An identically configured provider in the destination can stand in for a copy of ours.
+				if !implicit[res.URN] || !existing.Inputs.DeepEquals(res.Inputs) {
+					return nil, fmt.Errorf("resource %q already exists in the destination stack", newURN)
+				}
+				reused[res.URN] = true
+			}
+
+			newURNs[res.URN] = newURN
+			newParents[res.URN] = newParent
+			switch {
+			case reused[res.URN]:
+				// Nothing to report: references to the provider now point at the destination's own.
+			case copying[res.URN]:
+				outcome.Copied = append(outcome.Copied, stateMoveEntry{From: res.URN, To: newURN})
+			default:
+				outcome.Moved = append(outcome.Moved, stateMoveEntry{From: res.URN, To: newURN})
+			}
+
+		}
+
+		if providers.IsProviderType(res.Type) {
+			newID := res.ID
+			if reused[res.URN] {
+				newID = destResources[newURN].ID
+			}
+			oldRef, err := providers.NewReference(res.URN, res.ID)
+			if err != nil {
+				return nil, err
+			}
+			newRef, err := providers.NewReference(newURN, newID)
+			if err != nil {
+				return nil, err
+			}
+			providerRefs[oldRef.String()] = newRef.String()
+		}
+	}
+
+	// rewriteDeps maps the dependencies of a moved resource into the destination, dropping those that aren't.
+	rewriteDeps := func(res *resource.State, kind string, deps []resource.URN) []resource.URN {
+		var rewritten []resource.URN
+		for _, dep := range deps {
+			if newURN, has := newURNs[dep]; has {
+				rewritten = append(rewritten, newURN)
+			} else {
+				outcome.BrokenDependencies = append(outcome.BrokenDependencies,
+					fmt.Sprintf("%s %s %s", res.URN, kind, dep))
+			}
+		}
+		return rewritten
+	}
+
+	var moved []*resource.State
+	for _, res := range source.Resources {
+		if (!moving[res.URN] && !copying[res.URN]) || reused[res.URN] {
+			continue
+		}
+
+		newRes := *res
+		newRes.URN = newURNs[res.URN]
+		newRes.Parent = newParents[res.URN]
+		// Aliases refer to URNs in the source stack, which mean nothing in the destination.
+		newRes.Aliases = nil
+		if res.Provider != "" {
+			ref, has := providerRefs[res.Provider]
+			if !has {
+				return nil, fmt.Errorf("could not find the provider %q of resource %q", res.Provider, res.URN)
+			}
+			newRes.Provider = ref
+		}
+		newRes.Dependencies = rewriteDeps(res, "depends on", res.Dependencies)
+		if res.PropertyDependencies != nil {
+			newRes.PropertyDependencies = make(map[resource.PropertyKey][]resource.URN, len(res.PropertyDependencies))
+			for prop, deps := range res.PropertyDependencies {
+				newRes.PropertyDependencies[prop] = rewriteDeps(res, "depends on", deps)
+			}
+		}
+		if res.DeletedWith != "" {
+			if deletedWith := rewriteDeps(res, "is deleted with", []resource.URN{res.DeletedWith}); len(deletedWith) == 1 {
+				newRes.DeletedWith = deletedWith[0]
+			} else {
+				newRes.DeletedWith = ""
+			}
+		}
+		moved = append(moved, &newRes)
+	}
+
+	// Remove the moved resources from the source and drop any dependencies that the remaining resources had on them.
+	remaining := make([]*resource.State, 0, len(source.Resources))
+	for _, res := range source.Resources {
+		if moving[res.URN] {
+			continue
+		}
+
+		keepDeps := func(kind string, deps []resource.URN) []resource.URN {
+			var kept []resource.URN
+			for _, dep := range deps {
+				if moving[dep] {
+					outcome.BrokenDependencies = append(outcome.BrokenDependencies,
+						fmt.Sprintf("%s %s %s", res.URN, kind, dep))
+				} else {
+					kept = append(kept, dep)
+				}
+			}
+			return kept
+		}
+		res.Dependencies = keepDeps("depends on", res.Dependencies)
+		for prop, deps := range res.PropertyDependencies {
+			res.PropertyDependencies[prop] = keepDeps("depends on", deps)
+		}
+		if res.DeletedWith != "" && len(keepDeps("is deleted with", []resource.URN{res.DeletedWith})) == 0 {
+			res.DeletedWith = ""
+		}
+		remaining = append(remaining, res)
+	}
+
+	source.Resources = remaining
+	dest.Resources = append(dest.Resources, moved...)
+
+	if err := source.VerifyIntegrity(); err != nil {
+		return nil, fmt.Errorf("moving resources would leave the source stack's state invalid: %w", err)
+	}
+	if err := dest.VerifyIntegrity(); err != nil {
+		return nil, fmt.Errorf("moving resources would leave the destination stack's state invalid: %w", err)
+	}
+	return outcome, nil
+}
+
+// providerURN returns the URN of the provider of the given resource, or the empty URN if it has no provider.
+func providerURN(res *resource.State) resource.URN {
+	if res.Provider == "" {
+		return ""
+	}
+	ref, err := providers.ParseReference(res.Provider)
+	if err != nil {
+		return ""
+	}
+	return ref.URN()
+}
+
+// loadMoveStack loads a stack for `pulumi state move`. The stack is looked up in the backend at the given URL, or
+// the current backend if no URL is given.
+func loadMoveStack(
+	ctx context.Context, backendURL, stackName string, opts display.Options,
+) (backend.Stack, error) {
+	if backendURL == "" {
+		return requireStack(ctx, stackName, stackLoadOnly, opts)
+	}
+
+	project, _, err := readProject()
+	if err != nil && !errors.Is(err, workspace.ErrProjectNotFound) {
+		return nil, err
+	}
+	b, err := backendForURL(ctx, backendURL, project, opts)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := b.ParseStackReference(stackName)
+	if err != nil {
+		return nil, err
+	}
+	s, err := b.GetStack(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("no stack named '%s' found in %s", stackName, backendURL)
+	}
+	return s, nil
+}
+
+// moveDestinationSnapshot returns the snapshot of the destination stack, creating an empty one if the stack has never
+// been deployed. If the resources being moved contain secrets and the destination has no secrets manager yet, the
+// stack's configured secrets manager is used.
+func moveDestinationSnapshot(
+	ctx context.Context, dest backend.Stack, source *deploy.Snapshot,
+) (*deploy.Snapshot, error) {
+	snap, err := dest.Snapshot(ctx, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, err
+	}
+	if snap == nil {
+		manifest := deploy.Manifest{
+			Time:    time.Now(),
+			Version: version.Version,
+		}
+		manifest.Magic = manifest.NewMagic()
+		snap = deploy.NewSnapshot(manifest, nil, nil, nil)
+	}
+	if snap.SecretsManager != nil {
+		return snap, nil
+	}
+
+	hasSecrets := false
+	for _, res := range source.Resources {
+		if res.Inputs.ContainsSecrets() || res.Outputs.ContainsSecrets() {
+			hasSecrets = true
+			break
+		}
+	}
+	if !hasSecrets {
+		return snap, nil
+	}
+
+	project, _, err := readProject()
+	if err != nil {
+		return nil, fmt.Errorf("loading the destination stack's secrets configuration: %w", err)
+	}
+	ps, err := loadProjectStack(project, dest)
+	if err != nil {
+		return nil, fmt.Errorf("loading the destination stack's secrets configuration: %w", err)
+	}
+	sm, _, err := getStackSecretsManager(dest, ps, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting the destination stack's secrets manager: %w", err)
+	}
+	snap.SecretsManager = sm
+	return snap, nil
+}
+
+//nolint:lll
+func newStateMoveCommand() *cobra.Command {
+	var sourceStackName string
+	var destStackName string
+	var sourceBackend string
+	var destBackend string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "move [resource URN...]",
+		Short: "Move resources from one stack to another",
+		Long: `Move resources from one stack to another
+
+This command moves resources, along with their children, from the state of one stack to the state of another.
+The URNs of the moved resources are rewritten for the destination stack and project. The providers used by
+the moved resources are moved with them, or copied if resources left in the source stack still use them.
+Dependencies between moved resources and resources that are not moved can't be kept across stacks, and are
+removed from both stacks.
+
+Secrets in the moved resources are re-encrypted with the destination stack's secrets provider.
+
+The two stacks may live in different backends: use --source-backend and --dest-backend to name a backend
+other than the current one.
+
+Make sure that URNs are single-quoted to avoid having characters unexpectedly interpreted by the shell.
+
+To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
+`,
+		Example: "pulumi state move --source dev --dest prod 'urn:pulumi:dev::demo::aws:s3/bucket:Bucket::my-bucket'",
+		Args:    cmdutil.MinimumNArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if sourceStackName == "" || destStackName == "" {
+				return errors.New("both --source and --dest must be specified")
+			}
+
+			urns := make([]resource.URN, len(args))
+			for i, arg := range args {
+				urns[i] = resource.URN(arg)
+				if !urns[i].IsValid() {
+					return fmt.Errorf("The provided URN %q is not valid", arg)
+				}
+			}
+
+			source, err := loadMoveStack(ctx, sourceBackend, sourceStackName, opts)
+			if err != nil {
+				return err
+			}
+			dest, err := loadMoveStack(ctx, destBackend, destStackName, opts)
+			if err != nil {
+				return err
+			}
+			if source.Backend().URL() == dest.Backend().URL() &&
+				source.Ref().FullyQualifiedName() == dest.Ref().FullyQualifiedName() {
+				return errors.New("the source and destination stacks must be different")
+			}
+
+			// Hold the locks on both stacks until both have been saved, so that neither changes in between.
+			for _, s := range []backend.Stack{source, dest} {
+				locker, ok := s.Backend().(backend.StackLocker)
+				if !ok {
+					continue
+				}
+				if err := locker.Lock(ctx, s.Ref()); err != nil {
+					return err
+				}
+				defer locker.Unlock(ctx, s.Ref())
+			}
+
+			sourceSnap, err := source.Snapshot(ctx, stack.DefaultSecretsProvider)
+			if err != nil {
+				return err
+			}
+			if sourceSnap == nil {
+				return fmt.Errorf("the source stack %s has no resources", source.Ref())
+			}
+			destSnap, err := moveDestinationSnapshot(ctx, dest, sourceSnap)
+			if err != nil {
+				return err
+			}
+
+			destProject, err := moveDestinationProject(dest, destSnap, sourceSnap)
+			if err != nil {
+				return err
+			}
+
+			if !yes && cmdutil.Interactive() {
+				err := confirmStateEdit(opts, fmt.Sprintf(
+					"This command will edit the state of stacks %s and %s directly. Confirm?", source.Ref(), dest.Ref()))
+				if err != nil {
+					return err
+				}
+			}
+
+			res, err := stateMoveOperation(sourceSnap, destSnap, urns, dest.Ref().Name().Q(), destProject)
+			if err != nil {
+				return err
+			}
+			for _, broken := range res.BrokenDependencies {
+				cmdutil.Diag().Warningf(diag.Message("", "removed dependency that crosses stacks: %s"), broken)
+			}
+
+			// Save the destination first: if saving the source then fails, the resources are duplicated rather
+			// than lost.
+			if err := importSnapshot(ctx, dest, destSnap); err != nil {
+				return fmt.Errorf("saving the destination stack: %w", err)
+			}
+			if err := importSnapshot(ctx, source, sourceSnap); err != nil {
+				return fmt.Errorf("saving the source stack: %w; the moved resources are now in both stacks", err)
+			}
+
+			for _, entry := range res.Moved {
+				fmt.Printf("Moved %s to %s\n", entry.From, entry.To)
+			}
+			for _, entry := range res.Copied {
+				fmt.Printf("Copied provider %s to %s\n", entry.From, entry.To)
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&sourceStackName, "source", "", "The name of the stack to move resources from")
+	cmd.Flags().StringVar(&destStackName, "dest", "", "The name of the stack to move resources to")
+	cmd.Flags().StringVar(&sourceBackend, "source-backend", "",
+		"The URL of the backend that holds the source stack. Defaults to the current backend")
+	cmd.Flags().StringVar(&destBackend, "dest-backend", "",
+		"The URL of the backend that holds the destination stack. Defaults to the current backend")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	return cmd
+}
+
+// moveDestinationProject returns the project that the destination stack belongs to. Old DIY backends don't record
+// a project for their stacks, in which case it's taken from the destination's existing resources, and failing that
+// the source's.
+func moveDestinationProject(dest backend.Stack, destSnap, sourceSnap *deploy.Snapshot) (tokens.PackageName, error) {
+	if project, has := dest.Ref().Project(); has {
+		return tokens.PackageName(project), nil
+	}
+	for _, snap := range []*deploy.Snapshot{destSnap, sourceSnap} {
+		if len(snap.Resources) > 0 {
+			return snap.Resources[0].URN.Project(), nil
+		}
+	}
+	return "", fmt.Errorf("could not determine the project of stack %s", dest.Ref())
+}
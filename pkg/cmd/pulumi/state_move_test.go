@@ -0,0 +1,219 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// moveTestSource returns a snapshot of stack "dev" of project "src" holding a provider, a component with a child
+// that uses the provider, and another resource that uses the provider and depends on the component's child.
+func moveTestSource() *deploy.Snapshot {
+	root := resource.URN("urn:pulumi:dev::src::pulumi:pulumi:Stack::src-dev")
+	prov := resource.URN("urn:pulumi:dev::src::pulumi:providers:random::prov")
+	comp := resource.URN("urn:pulumi:dev::src::my:index:Component::comp")
+	child := resource.URN("urn:pulumi:dev::src::my:index:Component$random:index/randomPet:RandomPet::child")
+	other := resource.URN("urn:pulumi:dev::src::random:index/randomPet:RandomPet::other")
+
+	return &deploy.Snapshot{
+		Resources: []*resource.State{
+			{URN: root, Type: resource.RootStackType},
+			{URN: prov, Type: "pulumi:providers:random", ID: "prov-id", Custom: true, Parent: root},
+			{URN: comp, Type: "my:index:Component", Parent: root},
+			{
+				URN:      child,
+				Type:     "random:index/randomPet:RandomPet",
+				ID:       "child-id",
+				Custom:   true,
+				Parent:   comp,
+				Provider: string(prov) + "::prov-id",
+				Inputs: resource.PropertyMap{
+					"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+				},
+			},
+			{
+				URN:          other,
+				Type:         "random:index/randomPet:RandomPet",
+				ID:           "other-id",
+				Custom:       true,
+				Parent:       root,
+				Provider:     string(prov) + "::prov-id",
+				Dependencies: []resource.URN{child},
+				PropertyDependencies: map[resource.PropertyKey][]resource.URN{
+					"prefix": {child},
+				},
+			},
+		},
+	}
+}
+
+func moveTestDest() *deploy.Snapshot {
+	return &deploy.Snapshot{
+		Resources: []*resource.State{
+			{URN: "urn:pulumi:prod::dst::pulumi:pulumi:Stack::dst-prod", Type: resource.RootStackType},
+		},
+	}
+}
+
+func findMovedResource(snap *deploy.Snapshot, urn resource.URN) *resource.State {
+	for _, res := range snap.Resources {
+		if res.URN == urn {
+			return res
+		}
+	}
+	return nil
+}
+
+// TestStateMoveComponent tests that moving a component moves its children, copies the provider that they share with
+// resources that stay behind, and breaks the dependencies between the two stacks.
+func TestStateMoveComponent(t *testing.T) {
+	t.Parallel()
+
+	source, dest := moveTestSource(), moveTestDest()
+	res, err := stateMoveOperation(source, dest,
+		[]resource.URN{"urn:pulumi:dev::src::my:index:Component::comp"}, "prod", "dst")
+	require.NoError(t, err)
+
+	assert.Equal(t, []stateMoveEntry{
+		{
+			From: "urn:pulumi:dev::src::my:index:Component::comp",
+			To:   "urn:pulumi:prod::dst::my:index:Component::comp",
+		},
+		{
+			From: "urn:pulumi:dev::src::my:index:Component$random:index/randomPet:RandomPet::child",
+			To:   "urn:pulumi:prod::dst::my:index:Component$random:index/randomPet:RandomPet::child",
+		},
+	}, res.Moved)
+	assert.Equal(t, []stateMoveEntry{
+		{
+			From: "urn:pulumi:dev::src::pulumi:providers:random::prov",
+			To:   "urn:pulumi:prod::dst::pulumi:providers:random::prov",
+		},
+	}, res.Copied)
+	assert.Len(t, res.BrokenDependencies, 2)
+
+	// The source keeps the provider and the resource that still uses it, without its dependencies on the child.
+	require.Len(t, source.Resources, 3)
+	other := findMovedResource(source, "urn:pulumi:dev::src::random:index/randomPet:RandomPet::other")
+	require.NotNil(t, other)
+	assert.Empty(t, other.Dependencies)
+	assert.Empty(t, other.PropertyDependencies["prefix"])
+
+	// The destination gains the provider, the component and its child, rewritten for the destination.
+	require.Len(t, dest.Resources, 4)
+	comp := findMovedResource(dest, "urn:pulumi:prod::dst::my:index:Component::comp")
+	require.NotNil(t, comp)
+	assert.Equal(t, resource.URN("urn:pulumi:prod::dst::pulumi:pulumi:Stack::dst-prod"), comp.Parent)
+	child := findMovedResource(dest,
+		"urn:pulumi:prod::dst::my:index:Component$random:index/randomPet:RandomPet::child")
+	require.NotNil(t, child)
+	assert.Equal(t, comp.URN, child.Parent)
+	assert.Equal(t, "urn:pulumi:prod::dst::pulumi:providers:random::prov::prov-id", child.Provider)
+}
+
+// TestStateMoveProvider tests that a provider that nothing left in the source uses is moved rather than copied.
+func TestStateMoveProvider(t *testing.T) {
+	t.Parallel()
+
+	source, dest := moveTestSource(), moveTestDest()
+	res, err := stateMoveOperation(source, dest, []resource.URN{
+		"urn:pulumi:dev::src::my:index:Component::comp",
+		"urn:pulumi:dev::src::random:index/randomPet:RandomPet::other",
+	}, "prod", "dst")
+	require.NoError(t, err)
+
+	assert.Len(t, res.Moved, 4)
+	assert.Empty(t, res.Copied)
+	assert.Empty(t, res.BrokenDependencies)
+	require.Len(t, source.Resources, 1)
+	assert.Equal(t, resource.RootStackType, source.Resources[0].Type)
+
+	// The dependencies between moved resources are kept.
+	other := findMovedResource(dest, "urn:pulumi:prod::dst::random:index/randomPet:RandomPet::other")
+	require.NotNil(t, other)
+	child := resource.URN("urn:pulumi:prod::dst::my:index:Component$random:index/randomPet:RandomPet::child")
+	assert.Equal(t, []resource.URN{child}, other.Dependencies)
+	assert.Equal(t, []resource.URN{child}, other.PropertyDependencies["prefix"])
+}
+
+// TestStateMoveProviderInUse tests that a provider can't be moved away from the resources that use it.
+func TestStateMoveProviderInUse(t *testing.T) {
+	t.Parallel()
+
+	source, dest := moveTestSource(), moveTestDest()
+	_, err := stateMoveOperation(source, dest,
+		[]resource.URN{"urn:pulumi:dev::src::pulumi:providers:random::prov"}, "prod", "dst")
+	assert.ErrorContains(t, err, "which is not being moved")
+}
+
+// TestStateMoveConflict tests that resources are not moved over resources that already exist in the destination,
+// but that an identical provider in the destination is reused.
+func TestStateMoveConflict(t *testing.T) {
+	t.Parallel()
+
+	source, dest := moveTestSource(), moveTestDest()
+	dest.Resources = append(dest.Resources, &resource.State{
+		URN:    "urn:pulumi:prod::dst::pulumi:providers:random::prov",
+		Type:   "pulumi:providers:random",
+		ID:     "dest-prov-id",
+		Custom: true,
+	})
+
+	res, err := stateMoveOperation(source, dest,
+		[]resource.URN{"urn:pulumi:dev::src::random:index/randomPet:RandomPet::other"}, "prod", "dst")
+	require.NoError(t, err)
+	assert.Len(t, res.Moved, 1)
+	other := findMovedResource(dest, "urn:pulumi:prod::dst::random:index/randomPet:RandomPet::other")
+	require.NotNil(t, other)
+	assert.Equal(t, "urn:pulumi:prod::dst::pulumi:providers:random::prov::dest-prov-id", other.Provider)
+
+	source = moveTestSource()
+	_, err = stateMoveOperation(source, dest,
+		[]resource.URN{"urn:pulumi:dev::src::random:index/randomPet:RandomPet::other"}, "prod", "dst")
+	assert.ErrorContains(t, err, "already exists in the destination stack")
+}
+
+// TestStateMoveSecrets tests that secrets in moved resources are encrypted with the destination's secrets manager.
+func TestStateMoveSecrets(t *testing.T) {
+	t.Parallel()
+
+	source, dest := moveTestSource(), moveTestDest()
+	dest.SecretsManager = b64.NewBase64SecretsManager()
+	_, err := stateMoveOperation(source, dest,
+		[]resource.URN{"urn:pulumi:dev::src::my:index:Component::comp"}, "prod", "dst")
+	require.NoError(t, err)
+
+	dep, err := stack.SerializeDeployment(context.Background(), dest, false /* showSecrets */)
+	require.NoError(t, err)
+	require.NotNil(t, dep.SecretsProviders)
+	assert.Equal(t, b64.Type, dep.SecretsProviders.Type)
+
+	// The b64 manager "encrypts" the JSON encoding of the secret's value.
+	bytes, err := json.Marshal(dep)
+	require.NoError(t, err)
+	assert.Contains(t, string(bytes), base64.StdEncoding.EncodeToString([]byte(`"hunter2"`)))
+	assert.NotContains(t, string(bytes), "hunter2")
+}
@@ -0,0 +1,216 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+func newStateRecoverCommand() *cobra.Command {
+	var stackName string
+	var eventLog string
+	var checkpoint string
+	var file string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "recover",
+		Short: "Rebuild a stack's state from the event log of a deployment",
+		Long: `Rebuild a stack's state from the event log of a deployment
+
+This command can be used when a deployment failed to save the stack's state, for instance because writing
+its final checkpoint failed. The event log of the deployment, as written by the --event-log flag of
+'pulumi up', 'pulumi refresh', 'pulumi destroy' and 'pulumi import', is applied to the last checkpoint
+that was saved: the resource operations that finished are applied to it, and those that had started but
+not finished are recorded as pending operations. The recovered state is verified before it is saved.
+
+The last checkpoint is the stack's current state, unless a checkpoint file is given with --checkpoint.
+The recovered state replaces the stack's state, unless a file to write it to is given with --file.
+
+Event logs do not record everything about a resource. Secret values, assets and archives are redacted in
+them, so these are taken from the last checkpoint, and resources that were created by the deployment have
+no recorded dependencies. Outputs that the last checkpoint does not have are left out of the recovered
+state. Operations that left inputs that it does not have, such as the secret inputs of resources that
+the deployment created, are not applied, and are recorded as pending operations instead, along with the
+operations on resources that refer to them. Run 'pulumi refresh' afterwards to bring the state up to
+date and resolve the pending operations.`,
+		Example: "pulumi state recover --event-log events.json",
+		Args:    cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return err
+			}
+
+			var base *deploy.Snapshot
+			if checkpoint != "" {
+				deployment, err := readDeploymentFile(checkpoint)
+				if err != nil {
+					return err
+				}
+				base, err = stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+				if err != nil {
+					return checkDeploymentVersionError(err, s.Ref().Name().String())
+				}
+			} else {
+				base, err = s.Snapshot(ctx, stack.DefaultSecretsProvider)
+				if err != nil {
+					return err
+				}
+			}
+
+			events, err := loadJSONEvents(eventLog)
+			if err != nil {
+				return fmt.Errorf("error reading events: %w", err)
+			}
+			recovery, err := backend.RecoverSnapshot(base, events)
+			if err != nil {
+				return fmt.Errorf("recovering state from %s: %w", eventLog, err)
+			}
+			if !backend.DisableIntegrityChecking {
+				if err := recovery.Snapshot.VerifyIntegrity(); err != nil {
+					return fmt.Errorf("the recovered state is not valid: %w", err)
+				}
+			}
+
+			printSnapshotRecovery(os.Stdout, recovery)
+
+			if file != "" {
+				return writeSnapshot(ctx, file, recovery.Snapshot)
+			}
+
+			if !yes && cmdutil.Interactive() {
+				err := confirmStateEdit(opts, "This command will replace your stack's state with the recovered state. "+
+					"Confirm?")
+				if err != nil {
+					return err
+				}
+			}
+			if err := importSnapshot(ctx, s, recovery.Snapshot); err != nil {
+				return err
+			}
+			fmt.Println("State recovered")
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().StringVar(&eventLog, "event-log", "",
+		"The event log of the deployment to recover the state of")
+	contract.AssertNoErrorf(cmd.MarkFlagRequired("event-log"), `Could not mark "event-log" as required`)
+	cmd.Flags().StringVar(&checkpoint, "checkpoint", "",
+		"A checkpoint or exported deployment file to recover the state from, instead of the stack's current state")
+	cmd.Flags().StringVar(&file, "file", "",
+		"A filename to write the recovered state to, instead of replacing the stack's state")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+
+	return cmd
+}
+
+// printSnapshotRecovery prints a summary of a snapshot recovery.
+func printSnapshotRecovery(w io.Writer, recovery *backend.SnapshotRecovery) {
+	fmt.Fprintf(w, "Applied %d completed resource operations from the event log.\n", recovery.Applied)
+
+	if len(recovery.Pending) > 0 {
+		fmt.Fprint(w, "\nThe following operations had not finished, and have been recorded as pending operations:\n")
+		for _, op := range recovery.Pending {
+			fmt.Fprintf(w, "  - %s %s\n", op.Type, op.Resource.URN)
+		}
+	}
+
+	if len(recovery.Incomplete) > 0 {
+		fmt.Fprint(w, "\nThe following operations finished, but have inputs with secret values, assets or archives "+
+			"that event logs\ndo not record, or depend on such operations. They have been recorded as pending "+
+			"operations:\n")
+		for _, op := range recovery.Incomplete {
+			fmt.Fprintf(w, "  - %s %s\n", op.Type, op.Resource.URN)
+		}
+		fmt.Fprint(w, "Run `pulumi refresh` to resolve them, for instance with --import-pending-creates.\n")
+	}
+
+	if len(recovery.Redacted) > 0 {
+		fmt.Fprint(w, "\nThe following resources have secret values, assets or archives that event logs do not "+
+			"record.\nTheir values have been taken from the last checkpoint, and may be out of date:\n")
+		for _, urn := range recovery.Redacted {
+			fmt.Fprintf(w, "  - %s\n", urn)
+		}
+		fmt.Fprint(w, "Run `pulumi refresh` to read their current outputs.\n")
+	}
+
+	if len(recovery.Dropped) > 0 {
+		fmt.Fprint(w, "\nThe following properties have secret values, assets or archives that event logs do not "+
+			"record,\nand are not in the last checkpoint. They have been left out of the recovered state:\n")
+		for _, prop := range recovery.Dropped {
+			fmt.Fprintf(w, "  - %s of %s\n", prop.Key, prop.URN)
+		}
+		fmt.Fprint(w, "Run `pulumi refresh` to read their current outputs.\n")
+	}
+	fmt.Fprintln(w)
+}
+
+// writeSnapshot writes a snapshot to a file, in the format of `pulumi stack export`.
+func writeSnapshot(ctx context.Context, path string, snap *deploy.Snapshot) error {
+	sdep, err := stack.SerializeDeployment(ctx, snap, false /* showSecrets */)
+	if err != nil {
+		return fmt.Errorf("serializing deployment: %w", err)
+	}
+	bytes, err := json.Marshal(sdep)
+	if err != nil {
+		return err
+	}
+	deployment := apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not open file: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(deployment); err != nil {
+		contract.IgnoreClose(f)
+		return fmt.Errorf("could not write deployment: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote recovered state to `%s`\n", path)
+	return nil
+}
@@ -15,8 +15,10 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
@@ -150,6 +152,7 @@ func stateRenameOperation(
 func newStateRenameCommand() *cobra.Command {
 	var stack string
 	var yes bool
+	var selectors []string
 
 	cmd := &cobra.Command{
 		Use:   "rename [resource URN] [new name]",
@@ -161,14 +164,28 @@ by its Pulumi URN and the new name of the resource.
 
 Make sure that URNs are single-quoted to avoid having characters unexpectedly interpreted by the shell.
 
+Rather than a single URN, the resources to rename can be chosen with one or more --select flags, in which
+case the only argument is the new name. Any occurrence of {name} in the new name is replaced with the
+resource's current name. The affected resources are listed before the stack's state is changed.
+
+` + edit.SelectorHelp + `
+
 To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 `,
-		Example: "pulumi state rename 'urn:pulumi:stage::demo::eks:index:Cluster$pulumi:providers:kubernetes::eks-provider' new-name-here",
-		Args:    cmdutil.MaximumNArgs(2),
+		Example: "pulumi state rename 'urn:pulumi:stage::demo::eks:index:Cluster$pulumi:providers:kubernetes::eks-provider' new-name-here\n" +
+			"pulumi state rename --select 'type=aws:s3/bucket:Bucket' '{name}-old'",
+		Args: cmdutil.MaximumNArgs(2),
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			yes = yes || skipConfirmations()
 
+			if len(selectors) > 0 {
+				if len(args) != 1 {
+					return errors.New("exactly one argument, the new name, must be given along with --select")
+				}
+				return renameSelectedResources(ctx, stack, !yes, selectors, args[0])
+			}
+
 			if len(args) < 2 && !cmdutil.Interactive() {
 				return missingNonInteractiveArg("resource URN", "new name")
 			}
@@ -240,5 +257,33 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 		"The name of the stack to operate on. Defaults to the current stack")
 
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	cmd.Flags().StringArrayVar(&selectors, "select", nil,
+		"Rename all resources that match the given selector. May be specified more than once")
 	return cmd
 }
+
+// renameSelectedResources renames every resource that matches the given selectors. Any occurrence of {name} in the
+// new name is replaced with the resource's current name.
+func renameSelectedResources(
+	ctx context.Context, stackName string, showPrompt bool, selectors []string, newName string,
+) error {
+	err := runSelectedStateEdit(ctx, stackName, showPrompt, selectors, "renamed", nil,
+		func(snap *deploy.Snapshot, res *resource.State) error {
+			name := strings.ReplaceAll(newName, "{name}", res.URN.Name())
+			if !tokens.IsQName(name) {
+				reason := "resource names may only contain alphanumerics, underscores, hyphens, dots, and slashes"
+				return fmt.Errorf("invalid name %q for %s: %s", name, res.URN, reason)
+			}
+			// Renaming a parent changes the URNs of its children, so always use the resource's current URN.
+			if err := stateRenameOperation(res.URN, tokens.QName(name), display.Options{}, snap); err != nil {
+				return fmt.Errorf("renaming %s: %w", res.URN, err)
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Resources renamed")
+	return nil
+}
@@ -33,6 +33,7 @@ func newStateUnprotectCommand() *cobra.Command {
 	var unprotectAll bool
 	var stack string
 	var yes bool
+	var selectors []string
 
 	cmd := &cobra.Command{
 		Use:   "unprotect [resource URN]",
@@ -41,8 +42,15 @@ func newStateUnprotectCommand() *cobra.Command {
 
 This command clears the 'protect' bit on one or more resources, allowing those resources to be deleted.
 
+Rather than a single URN, the resources to unprotect can be chosen with one or more --select flags. A
+resource is unprotected if it matches any of the selectors. The affected resources are listed before the
+stack's state is changed.
+
+` + edit.SelectorHelp + `
+
 To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.`,
-		Args: cmdutil.MaximumNArgs(1),
+		Example: "pulumi state unprotect --select 'provider=*::my-provider'",
+		Args:    cmdutil.MaximumNArgs(1),
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			yes = yes || skipConfirmations()
@@ -53,6 +61,19 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.`,
 				return unprotectAllResources(ctx, stack, showPrompt)
 			}
 
+			if len(selectors) > 0 {
+				if len(args) > 0 {
+					return errors.New("a resource URN can't be given along with --select")
+				}
+				err := runSelectedStateEdit(ctx, stack, showPrompt, selectors, "unprotected", nil,
+					edit.UnprotectResource)
+				if err != nil {
+					return err
+				}
+				fmt.Println("Resources unprotected")
+				return nil
+			}
+
 			var urn resource.URN
 
 			if len(args) != 1 {
@@ -76,6 +97,8 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.`,
 		"The name of the stack to operate on. Defaults to the current stack")
 	cmd.Flags().BoolVar(&unprotectAll, "all", false, "Unprotect all resources in the checkpoint")
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	cmd.Flags().StringArrayVar(&selectors, "select", nil,
+		"Unprotect all resources that match the given selector. May be specified more than once")
 
 	return cmd
 }
@@ -66,11 +66,13 @@ func newUpCmd() *cobra.Command {
 	var diffDisplay bool
 	var eventLogPath string
 	var parallel int
+	var parallelFor []string
 	var refresh string
 	var showConfig bool
 	var showPolicyRemediations bool
 	var showReplacementSteps bool
 	var showSames bool
+	var showCriticalPath bool
 	var showReads bool
 	var skipPreview bool
 	var showFullOutput bool
@@ -84,7 +86,10 @@ func newUpCmd() *cobra.Command {
 	var replaces []string
 	var targetReplaces []string
 	var targetDependents bool
+	var excludes []string
+	var excludeDependents bool
 	var planFilePath string
+	var planVerifyKey string
 
 	// up implementation used when the source of the Pulumi program is in the current working directory.
 	upWorkingDirectory := func(ctx context.Context, opts backend.UpdateOptions, cmd *cobra.Command) result.Result {
@@ -148,9 +153,24 @@ func newUpCmd() *cobra.Command {
 		if err != nil {
 			return result.FromError(err)
 		}
+		parallelForOption, err := getParallelForOption(proj, parallelFor)
+		if err != nil {
+			return result.FromError(err)
+		}
+		retryPolicyOption, err := getRetryPolicyOption(proj)
+		if err != nil {
+			return result.FromError(err)
+		}
+		secretScannerOption, err := getSecretScannerOption(proj)
+		if err != nil {
+			return result.FromError(err)
+		}
 		opts.Engine = engine.UpdateOptions{
 			LocalPolicyPacks:          engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
 			Parallel:                  parallel,
+			ParallelFor:               parallelForOption,
+			RetryPolicy:               retryPolicyOption,
+			SecretScanner:             secretScannerOption,
 			Debug:                     debug,
 			Refresh:                   refreshOption,
 			ReplaceTargets:            deploy.NewUrnTargets(replaceURNs),
@@ -160,6 +180,8 @@ func newUpCmd() *cobra.Command {
 			DisableOutputValues:       disableOutputValues(),
 			Targets:                   deploy.NewUrnTargets(targetURNs),
 			TargetDependents:          targetDependents,
+			Excludes:                  deploy.NewUrnTargets(excludes),
+			ExcludeDependents:         excludeDependents,
 			// Trigger a plan to be generated during the preview phase which can be constrained to during the
 			// update phase.
 			GeneratePlan:    true,
@@ -176,7 +198,7 @@ func newUpCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
-			plan, err := readPlan(planFilePath, dec, enc)
+			plan, err := readPlan(planFilePath, s, proj, cfg, planVerifyKeyPath(planVerifyKey, proj, root), dec, enc)
 			if err != nil {
 				return result.FromError(err)
 			}
@@ -375,10 +397,25 @@ func newUpCmd() *cobra.Command {
 		if err != nil {
 			return result.FromError(err)
 		}
+		parallelForOption, err := getParallelForOption(proj, parallelFor)
+		if err != nil {
+			return result.FromError(err)
+		}
+		retryPolicyOption, err := getRetryPolicyOption(proj)
+		if err != nil {
+			return result.FromError(err)
+		}
+		secretScannerOption, err := getSecretScannerOption(proj)
+		if err != nil {
+			return result.FromError(err)
+		}
 
 		opts.Engine = engine.UpdateOptions{
 			LocalPolicyPacks: engine.MakeLocalPolicyPacks(policyPackPaths, policyPackConfigPaths),
 			Parallel:         parallel,
+			ParallelFor:      parallelForOption,
+			RetryPolicy:      retryPolicyOption,
+			SecretScanner:    secretScannerOption,
 			Debug:            debug,
 			Refresh:          refreshOption,
 			// If we're in experimental mode then we trigger a plan to be generated during the preview phase
@@ -469,6 +506,7 @@ func newUpCmd() *cobra.Command {
 				ShowReplacementSteps:   showReplacementSteps,
 				ShowSameResources:      showSames,
 				ShowReads:              showReads,
+				ShowCriticalPath:       showCriticalPath,
 				SuppressOutputs:        suppressOutputs,
 				SuppressProgress:       suppressProgress,
 				TruncateOutput:         !showFullOutput,
@@ -491,7 +529,7 @@ func newUpCmd() *cobra.Command {
 				err = validateUnsupportedRemoteFlags(expectNop, configArray, path, client, jsonDisplay, policyPackPaths,
 					policyPackConfigPaths, refresh, showConfig, showPolicyRemediations, showReplacementSteps, showSames,
 					showReads, suppressOutputs, secretsProvider, &targets, replaces, targetReplaces,
-					targetDependents, planFilePath, stackConfigFile)
+					targetDependents, excludes, excludeDependents, planFilePath, stackConfigFile)
 				if err != nil {
 					return result.FromError(err)
 				}
@@ -542,8 +580,8 @@ func newUpCmd() *cobra.Command {
 		"Config keys contain a path to a property in a map or list to set")
 	cmd.PersistentFlags().StringVar(
 		&secretsProvider, "secrets-provider", "default", "The type of the provider that should be used to encrypt and "+
-			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault). Only "+
-			"used when creating a new stack from an existing template")
+			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault, age, "+
+			"pgp). Only used when creating a new stack from an existing template")
 
 	cmd.PersistentFlags().StringVar(
 		&client, "client", "", "The address of an existing language runtime host to connect to")
@@ -569,6 +607,14 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&targetDependents, "target-dependents", false,
 		"Allows updating of dependent targets discovered but not specified in --target list")
+	cmd.PersistentFlags().StringArrayVar(
+		&excludes, "exclude", []string{},
+		"Specify a resource URN to ignore. These resources will not be updated."+
+			" Multiple resources can be specified using --exclude urn1 --exclude urn2."+
+			" Wildcards (*, **) are also supported")
+	cmd.PersistentFlags().BoolVar(
+		&excludeDependents, "exclude-dependents", false,
+		"Allows ignoring of dependent resources discovered but not specified in --exclude list")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().StringSliceVar(
@@ -586,6 +632,10 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVarP(
 		&parallel, "parallel", "p", defaultParallel,
 		"Allow P resource operations to run in parallel at once (1 for no parallelism).")
+	cmd.PersistentFlags().StringArrayVar(
+		&parallelFor, "parallel-for", []string{},
+		"Allow at most N resource operations at once for a resource package or type token, as <package or type>=N."+
+			" For example, --parallel-for aws=4. May be repeated")
 	cmd.PersistentFlags().StringVarP(
 		&refresh, "refresh", "r", "",
 		"Refresh the state of the stack's resources before this update")
@@ -606,6 +656,10 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&showReads, "show-reads", false,
 		"Show resources that are being read in, alongside those being managed directly in the stack")
+	cmd.PersistentFlags().BoolVar(
+		&showCriticalPath, "show-critical-path", false,
+		"Show the chain of resource operations that determined how long the deployment took, how long each "+
+			"operation waited and worked, and how many operations ran in parallel")
 
 	cmd.PersistentFlags().BoolVarP(
 		&skipPreview, "skip-preview", "f", false,
@@ -632,12 +686,13 @@ func newUpCmd() *cobra.Command {
 
 	cmd.PersistentFlags().StringVar(
 		&planFilePath, "plan", "",
-		"[EXPERIMENTAL] Path to a plan file to use for the update. The update will not "+
+		"Path to a plan file to use for the update. The update will not "+
 			"perform operations that exceed its plan (e.g. replacements instead of updates, or updates instead"+
 			"of sames).")
-	if !hasExperimentalCommands() {
-		contract.AssertNoErrorf(cmd.PersistentFlags().MarkHidden("plan"), `Could not mark "plan" as hidden`)
-	}
+	cmd.PersistentFlags().StringVar(
+		&planVerifyKey, "plan-verify-key", "",
+		"Path to a PEM-encoded ed25519 public key. The plan file must be signed with the matching "+
+			"private key. Defaults to the project's planVerifyKey option")
 
 	// Remote flags
 	remoteArgs.applyFlags(cmd)
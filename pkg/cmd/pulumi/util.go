@@ -41,17 +41,17 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/backend/diy"
 	"github.com/pulumi/pulumi/pkg/v3/backend/httpstate"
+	"github.com/pulumi/pulumi/pkg/v3/backend/sqlstate"
 	"github.com/pulumi/pulumi/pkg/v3/backend/state"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
 	"github.com/pulumi/pulumi/pkg/v3/version"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/constant"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/slice"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/ciutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -116,7 +116,7 @@ func isDIYBackend(opts display.Options) (bool, error) {
 		return false, fmt.Errorf("could not get cloud url: %w", err)
 	}
 
-	return diy.IsDIYBackendURL(url), nil
+	return diy.IsDIYBackendURL(url) || sqlstate.IsSQLBackendURL(url), nil
 }
 
 func loginToCloud(
@@ -147,6 +147,9 @@ func nonInteractiveCurrentBackend(ctx context.Context, project *workspace.Projec
 	if diy.IsDIYBackendURL(url) {
 		return diy.New(ctx, cmdutil.Diag(), url, project)
 	}
+	if sqlstate.IsSQLBackendURL(url) {
+		return sqlstate.New(ctx, cmdutil.Diag(), url, project)
+	}
 
 	insecure := workspace.GetCloudInsecure(url)
 	_, err = httpstate.NewLoginManager().Current(ctx, url, insecure, true)
@@ -166,9 +169,19 @@ func currentBackend(ctx context.Context, project *workspace.Project, opts displa
 		return nil, fmt.Errorf("could not get cloud url: %w", err)
 	}
 
+	return backendForURL(ctx, url, project, opts)
+}
+
+// backendForURL returns the backend for the given backend URL, logging in to it if necessary.
+func backendForURL(
+	ctx context.Context, url string, project *workspace.Project, opts display.Options,
+) (backend.Backend, error) {
 	if diy.IsDIYBackendURL(url) {
 		return diy.New(ctx, cmdutil.Diag(), url, project)
 	}
+	if sqlstate.IsSQLBackendURL(url) {
+		return sqlstate.New(ctx, cmdutil.Diag(), url, project)
+	}
 
 	return loginToCloud(ctx, url, project, workspace.GetCloudInsecure(url), opts)
 }
@@ -969,35 +982,63 @@ func getRefreshOption(proj *workspace.Project, refresh string) (bool, error) {
 	return false, nil
 }
 
-func writePlan(path string, plan *deploy.Plan, enc config.Encrypter, showSecrets bool) error {
-	f, err := os.Create(path)
-	if err != nil {
-		return err
+// getParallelForOption returns the per-package and per-type limits on parallelism from the project's options and the
+// given --parallel-for flags, each of the form "<package or type>=<limit>". Flags override the project's options.
+func getParallelForOption(proj *workspace.Project, parallelFor []string) (map[string]int, error) {
+	limits := make(map[string]int)
+	if proj.Options != nil {
+		for key, limit := range proj.Options.ParallelFor {
+			if limit < 1 {
+				return nil, fmt.Errorf("parallelFor limit for %q must be at least 1", key)
+			}
+			limits[key] = limit
+		}
 	}
-	defer contract.IgnoreClose(f)
 
-	deploymentPlan, err := stack.SerializePlan(plan, enc, showSecrets)
-	if err != nil {
-		return err
+	for _, flag := range parallelFor {
+		eq := strings.LastIndex(flag, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid --parallel-for %q: expected <package or type>=<limit>", flag)
+		}
+		limit, err := strconv.Atoi(flag[eq+1:])
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid --parallel-for %q: the limit must be a positive integer", flag)
+		}
+		limits[flag[:eq]] = limit
 	}
-	encoder := json.NewEncoder(f)
-	encoder.SetEscapeHTML(false)
-	encoder.SetIndent("", "    ")
-	return encoder.Encode(deploymentPlan)
+
+	if len(limits) == 0 {
+		return nil, nil
+	}
+	return limits, nil
 }
 
-func readPlan(path string, dec config.Decrypter, enc config.Encrypter) (*deploy.Plan, error) {
-	f, err := os.Open(path)
+// getRetryPolicyOption returns the retry policy set in the project's options, if any.
+func getRetryPolicyOption(proj *workspace.Project) (*resource.RetryPolicy, error) {
+	if proj.Options == nil || proj.Options.RetryPolicy == nil {
+		return nil, nil
+	}
+
+	rp := proj.Options.RetryPolicy
+	policy, err := resource.ParseRetryPolicy(rp.MaxAttempts, rp.Backoff, rp.MaxBackoff, rp.RetryOn)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid retryPolicy: %w", err)
 	}
-	defer contract.IgnoreClose(f)
+	return policy, nil
+}
 
-	var deploymentPlan apitype.DeploymentPlanV1
-	if err := json.NewDecoder(f).Decode(&deploymentPlan); err != nil {
-		return nil, err
+// getSecretScannerOption returns the secret scanner configured in the project's options, if any.
+func getSecretScannerOption(proj *workspace.Project) (*deploy.SecretScanner, error) {
+	if proj.Options == nil || proj.Options.SecretScanning == nil {
+		return nil, nil
+	}
+
+	ss := proj.Options.SecretScanning
+	scanner, err := deploy.NewSecretScanner(ss.Action, ss.EntropyThreshold, ss.KeyPattern, ss.Patterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secretScanning: %w", err)
 	}
-	return stack.DeserializePlan(deploymentPlan, dec, enc)
+	return scanner, nil
 }
 
 func buildStackName(stackName string) (string, error) {
@@ -76,6 +76,8 @@ func validateUnsupportedRemoteFlags(
 	replaces []string,
 	targetReplaces []string,
 	targetDependents bool,
+	excludes []string,
+	excludeDependents bool,
 	planFilePath string,
 	stackConfigFile string,
 ) error {
@@ -140,6 +142,12 @@ func validateUnsupportedRemoteFlags(
 	if targetDependents {
 		return errors.New("--target-dependents is not supported with --remote")
 	}
+	if len(excludes) > 0 {
+		return errors.New("--exclude is not supported with --remote")
+	}
+	if excludeDependents {
+		return errors.New("--exclude-dependents is not supported with --remote")
+	}
 	if planFilePath != "" {
 		return errors.New("--plan is not supported with --remote")
 	}
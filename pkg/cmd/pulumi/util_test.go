@@ -341,6 +341,29 @@ func TestGetRefreshOption(t *testing.T) {
 	}
 }
 
+func TestGetParallelForOption(t *testing.T) {
+	t.Parallel()
+
+	project := &workspace.Project{
+		Options: &workspace.ProjectOptions{
+			ParallelFor: map[string]int{"aws": 4, "gcp": 2},
+		},
+	}
+
+	limits, err := getParallelForOption(&workspace.Project{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, limits)
+
+	limits, err = getParallelForOption(project, []string{"aws=8", "aws:s3/bucket:Bucket=1"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"aws": 8, "gcp": 2, "aws:s3/bucket:Bucket": 1}, limits)
+
+	for _, flag := range []string{"aws", "=4", "aws=0", "aws=four"} {
+		_, err = getParallelForOption(&workspace.Project{}, []string{flag})
+		assert.Error(t, err, flag)
+	}
+}
+
 func TestStackLoadOption(t *testing.T) {
 	t.Parallel()
 
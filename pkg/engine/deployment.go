@@ -298,14 +298,20 @@ func (deployment *deployment) run(cancelCtx *Context, actions runActions,
 			Parallel:                  deployment.Options.Parallel,
 			Refresh:                   deployment.Options.Refresh,
 			RefreshOnly:               deployment.Options.isRefresh,
+			RefreshProgram:            deployment.Options.RefreshProgram,
 			ReplaceTargets:            deployment.Options.ReplaceTargets,
 			Targets:                   deployment.Options.Targets,
 			TargetDependents:          deployment.Options.TargetDependents,
+			Excludes:                  deployment.Options.Excludes,
+			ExcludeDependents:         deployment.Options.ExcludeDependents,
 			UseLegacyDiff:             deployment.Options.UseLegacyDiff,
 			DisableResourceReferences: deployment.Options.DisableResourceReferences,
 			DisableOutputValues:       deployment.Options.DisableOutputValues,
 			GeneratePlan:              deployment.Options.UpdateOptions.GeneratePlan,
 			ContinueOnError:           deployment.Options.ContinueOnError,
+			ParallelFor:               deployment.Options.ParallelFor,
+			RetryPolicy:               deployment.Options.RetryPolicy,
+			SecretScanner:             deployment.Options.SecretScanner,
 		}
 		newPlan, walkError = deployment.Deployment.Execute(ctx, opts, preview)
 		close(done)
@@ -0,0 +1,141 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// DriftStatus classifies how the actual state of a resource compares to the state recorded for it in its stack.
+type DriftStatus string
+
+const (
+	// DriftInSync means that the resource's actual state matches its recorded state.
+	DriftInSync DriftStatus = "in-sync"
+	// DriftDrifted means that the resource's actual state differs from its recorded state.
+	DriftDrifted DriftStatus = "drifted"
+	// DriftDeleted means that the resource no longer exists.
+	DriftDeleted DriftStatus = "deleted"
+)
+
+// ResourceDrift describes the drift of a single resource.
+type ResourceDrift struct {
+	Status DriftStatus
+	// Old is the resource's state as recorded in the stack.
+	Old *resource.State
+	// New is the resource's state as read from its provider, or nil if the resource has been deleted.
+	New *resource.State
+	// Diff holds the differences between the outputs of Old and New, keyed by property path. It is only set for
+	// drifted resources.
+	Diff map[string]plugin.PropertyDiff
+}
+
+// Drift reads the actual state of every custom resource in the target's snapshot and compares it to the recorded
+// state. It is a refresh that never writes a checkpoint: the refresh is always a preview, and no snapshot manager is
+// used even if the context has one. The results are returned in the order of the snapshot. Events are still sent to
+// the context's event channel, if any.
+func Drift(u UpdateInfo, ctx *Context, opts UpdateOptions) ([]ResourceDrift, error) {
+	contract.Requiref(u != nil, "u", "cannot be nil")
+	contract.Requiref(ctx != nil, "ctx", "cannot be nil")
+
+	// Steps complete in any order, so the results are put back in the order of the snapshot. Record that order now,
+	// as a refresh rewrites the snapshot's list of resources in place.
+	order := make(map[resource.URN]int)
+	if snap := u.GetTarget().Snapshot; snap != nil {
+		for i, res := range snap.Resources {
+			if _, has := order[res.URN]; !has {
+				order[res.URN] = i
+			}
+		}
+	}
+
+	events := make(chan Event)
+	done := make(chan struct{})
+	var drift []ResourceDrift
+	go func() {
+		defer close(done)
+		for e := range events {
+			if d, ok := resourceDriftFromEvent(e); ok {
+				drift = append(drift, d)
+			}
+			if ctx.Events != nil {
+				ctx.Events <- e
+			}
+		}
+	}()
+
+	refreshCtx := &Context{
+		Cancel:        ctx.Cancel,
+		Events:        events,
+		BackendClient: ctx.BackendClient,
+		ParentSpan:    ctx.ParentSpan,
+	}
+	_, _, err := Refresh(u, refreshCtx, opts, true /*dryRun*/)
+	close(events)
+	<-done
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(drift, func(i, j int) bool {
+		return order[drift[i].Old.URN] < order[drift[j].Old.URN]
+	})
+	return drift, nil
+}
+
+// resourceDriftFromEvent returns the drift of the resource whose refresh completed with the given event, if the event
+// is the completion of a refresh of a custom resource.
+func resourceDriftFromEvent(e Event) (ResourceDrift, bool) {
+	if e.Type != ResourceOutputsEvent {
+		return ResourceDrift{}, false
+	}
+	md := e.Payload().(ResourceOutputsEventPayload).Metadata
+	if md.Old == nil || md.Old.State == nil {
+		return ResourceDrift{}, false
+	}
+
+	// Component, provider, and pending-replace resources are never read by a refresh.
+	old := md.Old.State
+	if !old.Custom || providers.IsProviderType(old.Type) || old.PendingReplacement {
+		return ResourceDrift{}, false
+	}
+
+	var new *resource.State
+	if md.New != nil {
+		new = md.New.State
+	}
+
+	switch md.Op {
+	case deploy.OpSame:
+		return ResourceDrift{Status: DriftInSync, Old: old, New: new}, true
+	case deploy.OpUpdate:
+		return ResourceDrift{
+			Status: DriftDrifted,
+			Old:    old,
+			New:    new,
+			Diff:   plugin.NewDetailedDiffFromObjectDiff(old.Outputs.Diff(new.Outputs), false /*inputDiff*/),
+		}, true
+	case deploy.OpDelete:
+		return ResourceDrift{Status: DriftDeleted, Old: old}, true
+	default:
+		return ResourceDrift{}, false
+	}
+}
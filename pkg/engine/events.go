@@ -45,7 +45,7 @@ type Event struct {
 type EventPayload interface {
 	StdoutEventPayload | DiagEventPayload | PreludeEventPayload | SummaryEventPayload |
 		ResourcePreEventPayload | ResourceOutputsEventPayload | ResourceOperationFailedPayload |
-		PolicyViolationEventPayload | PolicyRemediationEventPayload | PolicyLoadEventPayload
+		ResourceOperationRetryPayload | PolicyViolationEventPayload | PolicyRemediationEventPayload | PolicyLoadEventPayload
 }
 
 func NewCancelEvent() Event {
@@ -69,6 +69,8 @@ func NewEvent[T EventPayload](payload T) Event {
 		typ = ResourceOutputsEvent
 	case ResourceOperationFailedPayload:
 		typ = ResourceOperationFailed
+	case ResourceOperationRetryPayload:
+		typ = ResourceOperationRetry
 	case PolicyViolationEventPayload:
 		typ = PolicyViolationEvent
 	case PolicyRemediationEventPayload:
@@ -96,6 +98,7 @@ const (
 	ResourcePreEvent        EventType = "resource-pre"
 	ResourceOutputsEvent    EventType = "resource-outputs"
 	ResourceOperationFailed EventType = "resource-operationfailed"
+	ResourceOperationRetry  EventType = "resource-operationretry"
 	PolicyViolationEvent    EventType = "policy-violation"
 	PolicyRemediationEvent  EventType = "policy-remediation"
 	PolicyLoadEvent         EventType = "policy-load"
@@ -178,6 +181,16 @@ type ResourceOperationFailedPayload struct {
 	Steps    int
 }
 
+// ResourceOperationRetryPayload is the payload for an event with type `resource-operationretry`, which is sent when
+// an attempt at a resource operation fails with an error that its retry policy allows to be retried.
+type ResourceOperationRetryPayload struct {
+	Metadata    StepEventMetadata
+	Attempt     int           // the attempt that failed, starting from 1.
+	MaxAttempts int           // the maximum number of attempts allowed by the retry policy.
+	Delay       time.Duration // how long the engine waits before the next attempt.
+	Error       string        // the error that the failed attempt returned.
+}
+
 type ResourceOutputsEventPayload struct {
 	Metadata StepEventMetadata
 	Planning bool
@@ -209,6 +222,8 @@ type StepEventMetadata struct {
 	DetailedDiff map[string]plugin.PropertyDiff // the rich, structured diff
 	Logical      bool                           // true if this step represents a logical operation in the program.
 	Provider     string                         // the provider that performed this step.
+	Undeclared   bool                           // true if the program no longer declares this refreshed resource.
+	Drifted      bool                           // true if the refreshed inputs differ from the program's inputs.
 }
 
 // StepEventStateMetadata contains detailed metadata about a resource's state pertaining to a given step.
@@ -354,6 +369,15 @@ func makeStepEventMetadata(op display.StepOp, step deploy.Step, debug bool) Step
 		detailedDiff = detailedDiffer.DetailedDiff()
 	}
 
+	var undeclared bool
+	if undeclarer, hasUndeclared := step.(interface{ Undeclared() bool }); hasUndeclared {
+		undeclared = undeclarer.Undeclared()
+	}
+	var drifted bool
+	if drifter, hasDrifted := step.(interface{ Drifted() bool }); hasDrifted {
+		drifted = drifter.Drifted()
+	}
+
 	return StepEventMetadata{
 		Op:           op,
 		URN:          step.URN(),
@@ -366,6 +390,8 @@ func makeStepEventMetadata(op display.StepOp, step deploy.Step, debug bool) Step
 		Res:          makeStepEventStateMetadata(step.Res(), debug),
 		Logical:      step.Logical(),
 		Provider:     step.Provider(),
+		Undeclared:   undeclared,
+		Drifted:      drifted,
 	}
 }
 
@@ -412,6 +438,20 @@ func (e *eventEmitter) resourceOperationFailedEvent(
 	}))
 }
 
+func (e *eventEmitter) resourceOperationRetryEvent(
+	step deploy.Step, attempt, maxAttempts int, delay time.Duration, err error, debug bool,
+) {
+	contract.Requiref(e != nil, "e", "!= nil")
+
+	e.sendEvent(NewEvent(ResourceOperationRetryPayload{
+		Metadata:    makeStepEventMetadata(step.Op(), step, debug),
+		Attempt:     attempt,
+		MaxAttempts: maxAttempts,
+		Delay:       delay,
+		Error:       logging.FilterString(err.Error()),
+	}))
+}
+
 func (e *eventEmitter) resourceOutputsEvent(
 	op display.StepOp, step deploy.Step, planning, debug, internal bool,
 ) {
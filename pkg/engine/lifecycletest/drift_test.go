@@ -0,0 +1,99 @@
+package lifecycletest
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// TestDrift tests that Drift classifies each custom resource as in sync, drifted, or deleted without changing the
+// snapshot.
+func TestDrift(t *testing.T) {
+	t.Parallel()
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					return resource.ID(urn.Name()), news, resource.StatusOK, nil
+				},
+				ReadF: func(
+					urn resource.URN, id resource.ID, inputs, state resource.PropertyMap,
+				) (plugin.ReadResult, resource.Status, error) {
+					switch urn.Name() {
+					case "resB":
+						outputs := state.Copy()
+						outputs["size"] = resource.NewNumberProperty(2)
+						return plugin.ReadResult{Outputs: outputs}, resource.StatusOK, nil
+					case "resC":
+						return plugin.ReadResult{}, resource.StatusOK, nil
+					default:
+						return plugin.ReadResult{Outputs: state}, resource.StatusOK, nil
+					}
+				},
+			}, nil
+		}),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		comp, err := monitor.RegisterResource("my:module:Component", "comp", false)
+		require.NoError(t, err)
+		for _, name := range []string{"resA", "resB", "resC"} {
+			_, err := monitor.RegisterResource("pkgA:m:typA", name, true, deploytest.ResourceOptions{
+				Parent: comp.URN,
+				Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+			})
+			require.NoError(t, err)
+		}
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{HostF: deploytest.NewPluginHostF(nil, nil, programF, loaders...)},
+	}
+	project := p.GetProject()
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	var drift []ResourceDrift
+	driftOp := TestOp(func(u UpdateInfo, ctx *Context, opts UpdateOptions, _ bool,
+	) (*deploy.Plan, display.ResourceChanges, error) {
+		var err error
+		drift, err = Drift(u, ctx, opts)
+		return nil, nil, err
+	})
+	_, err = driftOp.Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient,
+		func(_ workspace.Project, _ deploy.Target, entries JournalEntries, _ []Event, _ display.ResourceChanges,
+			err error,
+		) error {
+			assert.Empty(t, entries)
+			return err
+		})
+	require.NoError(t, err)
+
+	require.Len(t, drift, 3)
+	assert.Equal(t, "resA", drift[0].Old.URN.Name())
+	assert.Equal(t, DriftInSync, drift[0].Status)
+	assert.Nil(t, drift[0].Diff)
+
+	assert.Equal(t, "resB", drift[1].Old.URN.Name())
+	assert.Equal(t, DriftDrifted, drift[1].Status)
+	assert.Equal(t, map[string]plugin.PropertyDiff{"size": {Kind: plugin.DiffUpdate}}, drift[1].Diff)
+	assert.Equal(t, resource.NewNumberProperty(2), drift[1].New.Outputs["size"])
+
+	assert.Equal(t, "resC", drift[2].Old.URN.Name())
+	assert.Equal(t, DriftDeleted, drift[2].Status)
+	assert.Nil(t, drift[2].New)
+}
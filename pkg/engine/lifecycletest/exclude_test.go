@@ -0,0 +1,290 @@
+package lifecycletest
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+)
+
+// excludeTestHost returns a plugin host for a program that registers resA, resB which depends on resA, and resC.
+// Each resource's "v" input is set to the value pointed to by v, and skip lists the resources not to register.
+func excludeTestHost(
+	t *testing.T, v *string, skip *[]string, reads mapset.Set[resource.URN],
+) deploytest.PluginHostFactory {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					return "created-id", news, resource.StatusOK, nil
+				},
+				ReadF: func(
+					urn resource.URN, id resource.ID, inputs, state resource.PropertyMap,
+				) (plugin.ReadResult, resource.Status, error) {
+					reads.Add(urn)
+					return plugin.ReadResult{Inputs: inputs, Outputs: state}, resource.StatusOK, nil
+				},
+			}, nil
+		}, deploytest.WithoutGrpc),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		inputs := resource.PropertyMap{"v": resource.NewStringProperty(*v)}
+		register := func(name string, deps ...resource.URN) resource.URN {
+			for _, s := range *skip {
+				if s == name {
+					return ""
+				}
+			}
+			resp, err := monitor.RegisterResource("pkgA:m:typA", name, true, deploytest.ResourceOptions{
+				Inputs:       inputs,
+				Dependencies: deps,
+			})
+			assert.NoError(t, err)
+			return resp.URN
+		}
+
+		a := register("resA")
+		register("resB", a)
+		register("resC")
+		return nil
+	})
+
+	return deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+}
+
+// excludeTestInputs returns the "v" input of each custom resource in the snapshot, by name.
+func excludeTestInputs(snap *deploy.Snapshot) map[string]string {
+	inputs := make(map[string]string)
+	for _, res := range snap.Resources {
+		if res.Type == "pkgA:m:typA" {
+			inputs[res.URN.Name()] = res.Inputs["v"].StringValue()
+		}
+	}
+	return inputs
+}
+
+// TestExcludeUpdate tests that excluded resources are left alone during an update, along with their dependents if
+// ExcludeDependents is set.
+func TestExcludeUpdate(t *testing.T) {
+	t.Parallel()
+
+	v, skip := "old", []string{}
+	hostF := excludeTestHost(t, &v, &skip, mapset.NewSet[resource.URN]())
+	p := &TestPlan{}
+	project := p.GetProject()
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), TestUpdateOptions{HostF: hostF},
+		false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	v = "new"
+	excludes := deploy.NewUrnTargets([]string{"urn:pulumi:test::test::pkgA:m:typA::resA"})
+	updated, err := TestOp(Update).Run(project, p.GetTarget(t, snap), TestUpdateOptions{
+		HostF:         hostF,
+		UpdateOptions: UpdateOptions{Excludes: excludes},
+	}, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.NoError(t, updated.VerifyIntegrity())
+	assert.Equal(t, map[string]string{"resA": "old", "resB": "new", "resC": "new"}, excludeTestInputs(updated))
+
+	updated, err = TestOp(Update).Run(project, p.GetTarget(t, snap), TestUpdateOptions{
+		HostF:         hostF,
+		UpdateOptions: UpdateOptions{Excludes: excludes, ExcludeDependents: true},
+	}, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.NoError(t, updated.VerifyIntegrity())
+	assert.Equal(t, map[string]string{"resA": "old", "resB": "old", "resC": "new"}, excludeTestInputs(updated))
+}
+
+// TestExcludeCreate tests that resources that depend on an excluded resource that doesn't exist yet are skipped.
+func TestExcludeCreate(t *testing.T) {
+	t.Parallel()
+
+	v, skip := "v", []string{}
+	hostF := excludeTestHost(t, &v, &skip, mapset.NewSet[resource.URN]())
+	p := &TestPlan{}
+	project := p.GetProject()
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), TestUpdateOptions{
+		HostF: hostF,
+		UpdateOptions: UpdateOptions{
+			Excludes: deploy.NewUrnTargets([]string{"urn:pulumi:test::test::pkgA:m:typA::resA"}),
+		},
+	}, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.NoError(t, snap.VerifyIntegrity())
+	assert.Equal(t, map[string]string{"resC": "v"}, excludeTestInputs(snap))
+}
+
+// TestExcludeDelete tests that excluded resources, and the resources they depend on, are not deleted.
+func TestExcludeDelete(t *testing.T) {
+	t.Parallel()
+
+	v, skip := "v", []string{}
+	hostF := excludeTestHost(t, &v, &skip, mapset.NewSet[resource.URN]())
+	p := &TestPlan{}
+	project := p.GetProject()
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), TestUpdateOptions{HostF: hostF},
+		false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	// resA can't be deleted while the excluded resB depends on it.
+	destroyed, err := TestOp(Destroy).Run(project, p.GetTarget(t, snap), TestUpdateOptions{
+		HostF: hostF,
+		UpdateOptions: UpdateOptions{
+			Excludes: deploy.NewUrnTargets([]string{"**resB"}),
+		},
+	}, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.NoError(t, destroyed.VerifyIntegrity())
+	assert.Equal(t, map[string]string{"resA": "v", "resB": "v"}, excludeTestInputs(destroyed))
+
+	// Removing resources from the program doesn't delete them if they're excluded.
+	skip = []string{"resB", "resC"}
+	updated, err := TestOp(Update).Run(project, p.GetTarget(t, snap), TestUpdateOptions{
+		HostF: hostF,
+		UpdateOptions: UpdateOptions{
+			Excludes:          deploy.NewUrnTargets([]string{"urn:pulumi:test::test::pkgA:m:typA::resA"}),
+			ExcludeDependents: true,
+		},
+	}, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.NoError(t, updated.VerifyIntegrity())
+	assert.Equal(t, map[string]string{"resA": "v", "resB": "v"}, excludeTestInputs(updated))
+}
+
+// TestExcludeRefresh tests that excluded resources are not refreshed.
+func TestExcludeRefresh(t *testing.T) {
+	t.Parallel()
+
+	v, skip := "v", []string{}
+	reads := mapset.NewSet[resource.URN]()
+	hostF := excludeTestHost(t, &v, &skip, reads)
+	p := &TestPlan{}
+	project := p.GetProject()
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), TestUpdateOptions{HostF: hostF},
+		false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	_, err = TestOp(Refresh).Run(project, p.GetTarget(t, snap), TestUpdateOptions{
+		HostF: hostF,
+		UpdateOptions: UpdateOptions{
+			Excludes:          deploy.NewUrnTargets([]string{"urn:pulumi:test::test::pkgA:m:typA::resA"}),
+			ExcludeDependents: true,
+		},
+	}, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []resource.URN{"urn:pulumi:test::test::pkgA:m:typA::resC"}, reads.ToSlice())
+}
+
+// excludeReplaceTestHost returns a plugin host for a program that registers resA, and resB whose "v" input comes from
+// resA. Changing a resource's "v" input replaces it, deleting the old resource first if dbr is true.
+func excludeReplaceTestHost(t *testing.T, v *string, dbr bool) deploytest.PluginHostFactory {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap,
+					ignoreChanges []string,
+				) (plugin.DiffResult, error) {
+					if !oldInputs["v"].DeepEquals(newInputs["v"]) {
+						return plugin.DiffResult{
+							Changes:             plugin.DiffSome,
+							ReplaceKeys:         []resource.PropertyKey{"v"},
+							DeleteBeforeReplace: dbr,
+						}, nil
+					}
+					return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+				},
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					return "created-id", news, resource.StatusOK, nil
+				},
+			}, nil
+		}, deploytest.WithoutGrpc),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		a, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{"v": resource.NewStringProperty(*v)},
+		})
+		require.NoError(t, err)
+		_, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Inputs:       resource.PropertyMap{"v": resource.NewStringProperty(*v)},
+			Dependencies: []resource.URN{a.URN},
+			PropertyDeps: map[resource.PropertyKey][]resource.URN{"v": {a.URN}},
+		})
+		return err
+	})
+
+	return deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+}
+
+// TestExcludeReplace tests that the old copy of a replaced resource is deleted even if an excluded resource depends
+// on it.
+func TestExcludeReplace(t *testing.T) {
+	t.Parallel()
+
+	v := "old"
+	hostF := excludeReplaceTestHost(t, &v, false /* dbr */)
+	p := &TestPlan{}
+	project := p.GetProject()
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), TestUpdateOptions{HostF: hostF},
+		false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	v = "new"
+	updated, err := TestOp(Update).Run(project, p.GetTarget(t, snap), TestUpdateOptions{
+		HostF:         hostF,
+		UpdateOptions: UpdateOptions{Excludes: deploy.NewUrnTargets([]string{"**resB"})},
+	}, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.NoError(t, updated.VerifyIntegrity())
+	assert.Equal(t, map[string]string{"resA": "new", "resB": "old"}, excludeTestInputs(updated))
+	for _, res := range updated.Resources {
+		assert.False(t, res.Delete, "%v should have been deleted", res.URN)
+	}
+}
+
+// TestExcludeDeleteBeforeReplace tests that a resource can't be deleted before it's replaced if that would also
+// replace a resource that is excluded.
+func TestExcludeDeleteBeforeReplace(t *testing.T) {
+	t.Parallel()
+
+	v := "old"
+	hostF := excludeReplaceTestHost(t, &v, true /* dbr */)
+	p := &TestPlan{}
+	project := p.GetProject()
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), TestUpdateOptions{HostF: hostF},
+		false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	v = "new"
+	updated, err := TestOp(Update).Run(project, p.GetTarget(t, snap), TestUpdateOptions{
+		HostF:         hostF,
+		UpdateOptions: UpdateOptions{Excludes: deploy.NewUrnTargets([]string{"**resB"})},
+	}, false, p.BackendClient, nil)
+	assert.ErrorContains(t, err, "unable to replace resource")
+	assert.Equal(t, map[string]string{"resA": "old", "resB": "old"}, excludeTestInputs(updated))
+
+	// Without exclusions, both resources are replaced.
+	updated, err = TestOp(Update).Run(project, p.GetTarget(t, snap), TestUpdateOptions{HostF: hostF},
+		false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"resA": "new", "resB": "new"}, excludeTestInputs(updated))
+}
@@ -2,6 +2,7 @@ package lifecycletest
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -725,3 +726,70 @@ func TestRemoteComponentGolang(t *testing.T) {
 	}
 	p.Run(t, nil)
 }
+
+// This test validates the wiring of the Hooks option in the go SDK.
+func TestResourceHooksGolangLifecycle(t *testing.T) {
+	t.Parallel()
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					return "created-id", news, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	var ran []*pulumi.ResourceHookArgs
+	var failCreate bool
+	hook := func(_ context.Context, args *pulumi.ResourceHookArgs) error {
+		ran = append(ran, args)
+		if failCreate && !args.After {
+			return errors.New("not ready")
+		}
+		return nil
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(info plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		ctx, err := pulumi.NewContext(context.Background(), pulumi.RunInfo{
+			Project:     info.Project,
+			Stack:       info.Stack,
+			Parallel:    info.Parallel,
+			DryRun:      info.DryRun,
+			MonitorAddr: info.MonitorAddress,
+		})
+		assert.NoError(t, err)
+
+		return pulumi.RunWithContext(ctx, func(ctx *pulumi.Context) error {
+			var resA testResource
+			return ctx.RegisterResource("pkgA:m:typA", "resA", &testResourceInputs{
+				Foo: pulumi.String("bar"),
+			}, &resA, pulumi.Hooks(&pulumi.ResourceHooks{
+				BeforeCreate: []pulumi.ResourceHook{hook},
+				AfterCreate:  []pulumi.ResourceHook{hook},
+			}))
+		})
+	})
+	hostF := deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+
+	p := &TestPlan{}
+	project := p.GetProject()
+	_, err := TestOp(Update).Run(project, p.GetTarget(t, nil), TestUpdateOptions{HostF: hostF},
+		false, p.BackendClient, nil)
+	require.NoError(t, err)
+	require.Len(t, ran, 2)
+	assert.Equal(t, "create", ran[0].Operation)
+	assert.False(t, ran[0].After)
+	assert.Equal(t, pulumi.String("bar"), ran[0].NewInputs["foo"])
+	assert.True(t, ran[1].After)
+	assert.Equal(t, pulumi.ID("created-id"), ran[1].ID)
+
+	ran, failCreate = nil, true
+	_, err = TestOp(Update).Run(project, p.GetTarget(t, nil), TestUpdateOptions{HostF: hostF},
+		false, p.BackendClient, nil)
+	assert.ErrorContains(t, err, "before create hook failed: not ready")
+	assert.Len(t, ran, 1)
+}
@@ -0,0 +1,135 @@
+package lifecycletest
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// TestRefreshRunProgram tests that a refresh that evaluates the program reads resources with the inputs that the
+// program declares, follows aliases, reports drift from the program's inputs, and reports the resources that the
+// program no longer declares without deleting them.
+func TestRefreshRunProgram(t *testing.T) {
+	t.Parallel()
+
+	var readsLock sync.Mutex
+	readInputs := make(map[string]resource.PropertyMap)
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					return resource.ID(urn.Name()), news, resource.StatusOK, nil
+				},
+				ReadF: func(
+					urn resource.URN, id resource.ID, inputs, state resource.PropertyMap,
+				) (plugin.ReadResult, resource.Status, error) {
+					readsLock.Lock()
+					defer readsLock.Unlock()
+					readInputs[urn.Name()] = inputs
+					// The resource's actual inputs are the ones it was created with.
+					return plugin.ReadResult{Inputs: state.Copy(), Outputs: state}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	// The first version of the program declares three resources. The second renames resA to resA2 with an alias,
+	// changes the inputs of resB, and drops resC.
+	var programRuns atomic.Int32
+	secondVersion := false
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		programRuns.Add(1)
+		inputs := resource.PropertyMap{"size": resource.NewNumberProperty(1)}
+		if !secondVersion {
+			for _, name := range []string{"resA", "resB", "resC"} {
+				_, err := monitor.RegisterResource("pkgA:m:typA", name, true, deploytest.ResourceOptions{Inputs: inputs})
+				require.NoError(t, err)
+			}
+			return nil
+		}
+
+		resA, err := monitor.RegisterResource("pkgA:m:typA", "resA2", true, deploytest.ResourceOptions{
+			Inputs:    inputs,
+			AliasURNs: []resource.URN{"urn:pulumi:test::test::pkgA:m:typA::resA"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, resource.URN("urn:pulumi:test::test::pkgA:m:typA::resA2"), resA.URN)
+		assert.Equal(t, resource.ID("resA"), resA.ID)
+		_, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(2)},
+		})
+		require.NoError(t, err)
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{HostF: deploytest.NewPluginHostF(nil, nil, programF, loaders...)},
+	}
+	project := p.GetProject()
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), programRuns.Load())
+
+	// A plain refresh doesn't run the program.
+	secondVersion = true
+	_, err = TestOp(Refresh).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), programRuns.Load())
+
+	opts := p.Options
+	opts.RefreshProgram = true
+	after, err := TestOp(Refresh).Run(project, p.GetTarget(t, snap), opts, false, p.BackendClient,
+		func(_ workspace.Project, _ deploy.Target, _ JournalEntries, events []Event, _ display.ResourceChanges,
+			err error,
+		) error {
+			undeclared := make(map[string]bool)
+			drifted := make(map[string]bool)
+			var warnings []string
+			for _, e := range events {
+				switch e.Type {
+				case ResourceOutputsEvent:
+					md := e.Payload().(ResourceOutputsEventPayload).Metadata
+					undeclared[md.URN.Name()] = md.Undeclared
+					drifted[md.URN.Name()] = md.Drifted
+				case DiagEvent:
+					if payload := e.Payload().(DiagEventPayload); payload.Severity == diag.Warning {
+						warnings = append(warnings, payload.Message)
+					}
+				}
+			}
+			assert.Equal(t, map[string]bool{"default": false, "resA": false, "resB": false, "resC": true}, undeclared)
+			assert.Equal(t, map[string]bool{"default": false, "resA": false, "resB": true, "resC": false}, drifted)
+			require.Len(t, warnings, 1)
+			assert.Contains(t, warnings[0], "resC is no longer declared by the program")
+			return err
+		})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), programRuns.Load())
+
+	// The declared resources were read with the inputs that the program declares, and the undeclared one with the
+	// inputs that were deployed. The state records the inputs that the provider read, and nothing was deleted.
+	assert.Equal(t, resource.NewNumberProperty(1), readInputs["resA"]["size"])
+	assert.Equal(t, resource.NewNumberProperty(2), readInputs["resB"]["size"])
+	assert.Equal(t, resource.NewNumberProperty(1), readInputs["resC"]["size"])
+	require.Len(t, after.Resources, len(snap.Resources))
+	for _, res := range after.Resources {
+		if res.Custom && res.URN.Type() == "pkgA:m:typA" {
+			assert.Equal(t, resource.PropertyMap{"size": resource.NewNumberProperty(1)}, res.Inputs, res.URN)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package lifecycletest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// ResourceHookFunction wraps a function as a resource hook callback. An error returned by the function is reported to
+// the engine as a failed hook.
+func ResourceHookFunction(f func(req *pulumirpc.ResourceHookRequest) error) func([]byte) (proto.Message, error) {
+	return func(request []byte) (proto.Message, error) {
+		var req pulumirpc.ResourceHookRequest
+		if err := proto.Unmarshal(request, &req); err != nil {
+			return nil, fmt.Errorf("unmarshaling request: %w", err)
+		}
+		if err := f(&req); err != nil {
+			return &pulumirpc.ResourceHookResponse{Error: err.Error()}, nil
+		}
+		return &pulumirpc.ResourceHookResponse{}, nil
+	}
+}
+
+// hookTest is a program that registers a single resource with hooks for every operation, recording each hook that
+// runs. The resource is replaced when its "replace" input changes and updated when any other input changes.
+type hookTest struct {
+	inputs resource.PropertyMap
+	dbr    bool
+	failOn string // the hook that fails, e.g. "before create".
+
+	lock     sync.Mutex
+	ran      []string
+	requests []*pulumirpc.ResourceHookRequest
+	creates  int
+}
+
+func (h *hookTest) hostF(t *testing.T) deploytest.PluginHostFactory {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				DiffF: func(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap,
+					ignoreChanges []string,
+				) (plugin.DiffResult, error) {
+					if !oldInputs["replace"].DeepEquals(newInputs["replace"]) {
+						return plugin.DiffResult{Changes: plugin.DiffSome, ReplaceKeys: []resource.PropertyKey{"replace"}}, nil
+					}
+					return plugin.DiffResult{}, nil
+				},
+				CreateF: func(urn resource.URN, inputs resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					if !preview {
+						h.lock.Lock()
+						h.creates++
+						h.lock.Unlock()
+					}
+					return "created-id", inputs, resource.StatusOK, nil
+				},
+			}, nil
+		}, deploytest.WithoutGrpc),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		callbacks, err := deploytest.NewCallbacksServer()
+		require.NoError(t, err)
+		defer func() { require.NoError(t, callbacks.Close()) }()
+
+		hook := func(name string) []*pulumirpc.Callback {
+			cb, err := callbacks.Allocate(ResourceHookFunction(func(req *pulumirpc.ResourceHookRequest) error {
+				h.lock.Lock()
+				defer h.lock.Unlock()
+				h.ran = append(h.ran, name)
+				h.requests = append(h.requests, req)
+				if name == h.failOn {
+					return errors.New("hook failed")
+				}
+				return nil
+			}))
+			require.NoError(t, err)
+			return []*pulumirpc.Callback{cb}
+		}
+
+		_, err = monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs:              h.inputs,
+			DeleteBeforeReplace: &h.dbr,
+			Hooks: &pulumirpc.RegisterResourceRequest_ResourceHooks{
+				BeforeCreate:  hook("before create"),
+				AfterCreate:   hook("after create"),
+				BeforeUpdate:  hook("before update"),
+				AfterUpdate:   hook("after update"),
+				BeforeDelete:  hook("before delete"),
+				AfterDelete:   hook("after delete"),
+				BeforeReplace: hook("before replace"),
+				AfterReplace:  hook("after replace"),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Keep the callbacks available until the deployment has finished, so that the engine can run the hooks of
+		// the steps that follow the program, such as deleting the old resource of a replacement.
+		return monitor.SignalAndWaitForShutdown()
+	})
+
+	return deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+}
+
+// reset clears the hooks that have run so far.
+func (h *hookTest) reset() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.ran, h.requests = nil, nil
+}
+
+// TestResourceHooks tests that the engine runs a resource's hooks around its creation, update, replacement, and the
+// deletion of the resource it replaced, but not during previews.
+func TestResourceHooks(t *testing.T) {
+	t.Parallel()
+
+	h := &hookTest{inputs: resource.PropertyMap{"replace": resource.NewStringProperty("a")}}
+	p := &TestPlan{}
+	project := p.GetProject()
+	opts := TestUpdateOptions{HostF: h.hostF(t)}
+
+	_, err := TestOp(Update).Run(project, p.GetTarget(t, nil), opts, true, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Empty(t, h.ran)
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), opts, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"before create", "after create"}, h.ran)
+	before, after := h.requests[0], h.requests[1]
+	assert.Equal(t, "create", before.Operation)
+	assert.False(t, before.After)
+	assert.Empty(t, before.Id)
+	assert.Equal(t, "a", before.NewInputs.Fields["replace"].GetStringValue())
+	assert.Nil(t, before.NewOutputs)
+	assert.True(t, after.After)
+	assert.Equal(t, "created-id", after.Id)
+	assert.Equal(t, "a", after.NewOutputs.Fields["replace"].GetStringValue())
+
+	h.reset()
+	h.inputs = resource.PropertyMap{"replace": resource.NewStringProperty("a"), "size": resource.NewNumberProperty(2)}
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), opts, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"before update", "after update"}, h.ran)
+	assert.Equal(t, "update", h.requests[0].Operation)
+	assert.Nil(t, h.requests[0].OldInputs.Fields["size"])
+	assert.Equal(t, float64(2), h.requests[0].NewInputs.Fields["size"].GetNumberValue())
+
+	h.reset()
+	h.inputs = resource.PropertyMap{"replace": resource.NewStringProperty("b")}
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), opts, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"before replace", "before create", "after create", "after replace", "before delete", "after delete",
+	}, h.ran)
+	assert.Equal(t, "delete", h.requests[4].Operation)
+	assert.Equal(t, "a", h.requests[4].OldInputs.Fields["replace"].GetStringValue())
+
+	h.reset()
+	h.dbr = true
+	h.inputs = resource.PropertyMap{"replace": resource.NewStringProperty("c")}
+	_, err = TestOp(Update).Run(project, p.GetTarget(t, snap), opts, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"before replace", "before delete", "after delete", "before create", "after create", "after replace",
+	}, h.ran)
+	assert.Equal(t, "replace", h.requests[0].Operation)
+	assert.Equal(t, "delete", h.requests[1].Operation)
+	assert.Equal(t, "b", h.requests[1].OldInputs.Fields["replace"].GetStringValue())
+}
+
+// TestResourceHookFailure tests that a failed before hook fails its step without applying it, and that a failed after
+// hook fails its step once the step's results have been saved.
+func TestResourceHookFailure(t *testing.T) {
+	t.Parallel()
+
+	t.Run("before", func(t *testing.T) {
+		t.Parallel()
+
+		h := &hookTest{failOn: "before create"}
+		p := &TestPlan{}
+		snap, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), TestUpdateOptions{HostF: h.hostF(t)},
+			false, p.BackendClient, nil)
+		assert.ErrorContains(t, err, "before create hook failed: hook failed")
+		assert.Equal(t, []string{"before create"}, h.ran)
+		assert.Equal(t, 0, h.creates)
+		require.Len(t, snap.Resources, 1)
+		assert.True(t, providers.IsProviderType(snap.Resources[0].Type))
+	})
+
+	t.Run("after", func(t *testing.T) {
+		t.Parallel()
+
+		h := &hookTest{failOn: "after create"}
+		p := &TestPlan{}
+		var errs []string
+		validate := func(project workspace.Project, target deploy.Target, entries JournalEntries,
+			evts []Event, changes display.ResourceChanges, err error,
+		) error {
+			for _, evt := range evts {
+				if evt.Type == DiagEvent {
+					if e := evt.Payload().(DiagEventPayload); e.Severity == diag.Error {
+						errs = append(errs, colors.Never.Colorize(e.Message))
+					}
+				}
+			}
+			return err
+		}
+		snap, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), TestUpdateOptions{HostF: h.hostF(t)},
+			false, p.BackendClient, validate)
+		assert.ErrorContains(t, err, "after create hook failed: hook failed")
+		assert.Contains(t, errs, "after create hook failed: hook failed\n")
+		assert.Equal(t, []string{"before create", "after create"}, h.ran)
+		// The resource was still created and saved.
+		assert.Equal(t, 1, h.creates)
+		require.Len(t, snap.Resources, 2)
+		assert.Equal(t, resource.ID("created-id"), snap.Resources[1].ID)
+	})
+}
@@ -0,0 +1,227 @@
+package lifecycletest
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil/rpcerror"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// retryTestHost returns a plugin host for a program that registers a component with the given retry policy and a
+// custom resource inside it, unless dropChild is set. The provider's creates and deletes fail with the given code until
+// they have been called failures times.
+func retryTestHost(
+	t *testing.T, policy *pulumirpc.RegisterResourceRequest_RetryPolicy, code codes.Code, failures int32,
+	creates, deletes *atomic.Int32, dropChild *atomic.Bool,
+) deploytest.PluginHostFactory {
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					if creates.Add(1) <= failures {
+						return "", nil, resource.StatusOK, rpcerror.New(code, "create failed")
+					}
+					return "created-id", news, resource.StatusOK, nil
+				},
+				DeleteF: func(urn resource.URN, id resource.ID, oldInputs, oldOutputs resource.PropertyMap,
+					timeout float64,
+				) (resource.Status, error) {
+					if deletes.Add(1) <= failures {
+						return resource.StatusOK, rpcerror.New(code, "delete failed")
+					}
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}, deploytest.WithoutGrpc),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		comp, err := monitor.RegisterResource("my:module:Component", "comp", false, deploytest.ResourceOptions{
+			RetryPolicy: policy,
+		})
+		if err != nil {
+			return err
+		}
+		if dropChild != nil && dropChild.Load() {
+			return nil
+		}
+		_, err = monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Parent: comp.URN,
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+
+	return deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+}
+
+// retryEvents returns the payloads of the retry events in the given list of events.
+func retryEvents(events []Event) []ResourceOperationRetryPayload {
+	var retries []ResourceOperationRetryPayload
+	for _, e := range events {
+		if e.Type == ResourceOperationRetry {
+			retries = append(retries, e.Payload().(ResourceOperationRetryPayload))
+		}
+	}
+	return retries
+}
+
+// TestRetryCreate tests that a create that fails with a retryable error is retried, and that the resource's children
+// inherit its retry policy.
+func TestRetryCreate(t *testing.T) {
+	t.Parallel()
+
+	var creates, deletes atomic.Int32
+	policy := &pulumirpc.RegisterResourceRequest_RetryPolicy{MaxAttempts: 3, Backoff: "1ms"}
+	p := &TestPlan{}
+	project := p.GetProject()
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), TestUpdateOptions{
+		HostF: retryTestHost(t, policy, codes.Unavailable, 2, &creates, &deletes, nil),
+	}, false, p.BackendClient, func(_ workspace.Project, _ deploy.Target, _ JournalEntries,
+		events []Event, _ display.ResourceChanges, err error,
+	) error {
+		retries := retryEvents(events)
+		require.Len(t, retries, 2)
+		for i, retry := range retries {
+			assert.Equal(t, resource.URN("urn:pulumi:test::test::my:module:Component$pkgA:m:typA::resA"),
+				retry.Metadata.URN)
+			assert.Equal(t, i+1, retry.Attempt)
+			assert.Equal(t, 3, retry.MaxAttempts)
+			assert.Equal(t, time.Duration(i+1)*time.Millisecond, retry.Delay)
+			assert.Contains(t, retry.Error, "create failed")
+		}
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), creates.Load())
+	assert.Len(t, snap.Resources, 3)
+}
+
+// TestRetryExhausted tests that an operation fails once its retry policy runs out of attempts, and that errors the
+// policy doesn't list are not retried.
+func TestRetryExhausted(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		code     codes.Code
+		attempts int32
+	}{
+		{"Exhausted", codes.ResourceExhausted, 2},
+		{"NotRetryable", codes.InvalidArgument, 1},
+		// A create that timed out may have created the resource, so it isn't retried by default.
+		{"CreateDeadlineExceeded", codes.DeadlineExceeded, 1},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			var creates, deletes atomic.Int32
+			policy := &pulumirpc.RegisterResourceRequest_RetryPolicy{MaxAttempts: 2, Backoff: "1ms"}
+			p := &TestPlan{}
+
+			_, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), TestUpdateOptions{
+				HostF: retryTestHost(t, policy, c.code, 10, &creates, &deletes, nil),
+			}, false, p.BackendClient, func(_ workspace.Project, _ deploy.Target, _ JournalEntries,
+				events []Event, _ display.ResourceChanges, err error,
+			) error {
+				assert.Len(t, retryEvents(events), int(c.attempts-1))
+				return err
+			})
+			assert.Error(t, err)
+			assert.Equal(t, c.attempts, creates.Load())
+		})
+	}
+}
+
+// TestRetryProjectPolicy tests that the deployment's retry policy applies to resources without one of their own,
+// including deletes of resources that are no longer in the program.
+func TestRetryProjectPolicy(t *testing.T) {
+	t.Parallel()
+
+	var creates, deletes atomic.Int32
+	p := &TestPlan{}
+	project := p.GetProject()
+	hostF := retryTestHost(t, nil, codes.Unavailable, 1, &creates, &deletes, nil)
+	policy, err := resource.ParseRetryPolicy(2, "1ms", "", nil)
+	require.NoError(t, err)
+	opts := TestUpdateOptions{HostF: hostF, UpdateOptions: UpdateOptions{RetryPolicy: policy}}
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), opts, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), creates.Load())
+
+	snap, err = TestOp(Destroy).Run(project, p.GetTarget(t, snap), opts, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), deletes.Load())
+	assert.Empty(t, snap.Resources)
+}
+
+// TestRetryRemovedChild tests that deleting a resource that is no longer in the program uses the retry policy of its
+// parent, and that deletes are retried on errors that creates are not.
+func TestRetryRemovedChild(t *testing.T) {
+	t.Parallel()
+
+	var creates, deletes atomic.Int32
+	var dropChild atomic.Bool
+	policy := &pulumirpc.RegisterResourceRequest_RetryPolicy{MaxAttempts: 2, Backoff: "1ms"}
+	p := &TestPlan{}
+	project := p.GetProject()
+	opts := TestUpdateOptions{HostF: retryTestHost(t, policy, codes.DeadlineExceeded, 1, &creates, &deletes, &dropChild)}
+
+	// The first create times out and isn't retried, so the update fails and the next one creates the resource.
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), opts, false, p.BackendClient, nil)
+	assert.Error(t, err)
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), opts, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), creates.Load())
+
+	dropChild.Store(true)
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), opts, false, p.BackendClient,
+		func(_ workspace.Project, _ deploy.Target, _ JournalEntries, events []Event, _ display.ResourceChanges,
+			err error,
+		) error {
+			retries := retryEvents(events)
+			require.Len(t, retries, 1)
+			assert.Equal(t, resource.URN("urn:pulumi:test::test::my:module:Component$pkgA:m:typA::resA"),
+				retries[0].Metadata.URN)
+			assert.Contains(t, retries[0].Error, "delete failed")
+			return err
+		})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), deletes.Load())
+	assert.Len(t, snap.Resources, 1)
+}
+
+// TestRetryInvalidPolicy tests that registering a resource with an invalid retry policy fails.
+func TestRetryInvalidPolicy(t *testing.T) {
+	t.Parallel()
+
+	var creates, deletes atomic.Int32
+	policy := &pulumirpc.RegisterResourceRequest_RetryPolicy{MaxAttempts: 2, RetryOn: []string{"Throttled"}}
+	p := &TestPlan{}
+
+	_, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), TestUpdateOptions{
+		HostF: retryTestHost(t, policy, codes.Unavailable, 0, &creates, &deletes, nil),
+	}, false, p.BackendClient, nil)
+	assert.ErrorContains(t, err, `unknown error class "Throttled"`)
+	assert.Equal(t, int32(0), creates.Load())
+}
@@ -15,19 +15,24 @@
 package lifecycletest
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/blang/semver"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	. "github.com/pulumi/pulumi/pkg/v3/engine" //nolint:revive
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
@@ -1792,3 +1797,172 @@ func TestStackOutputsWithTargetedPlan(t *testing.T) {
 	}, false, p.BackendClient, nil)
 	assert.NoError(t, err)
 }
+
+// TestPlannedDestroy tests that a destroy can be constrained to a plan generated by a destroy preview, and that it
+// refuses to delete a resource that has been replaced since the plan was made.
+func TestPlannedDestroy(t *testing.T) {
+	t.Parallel()
+
+	var creates int
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					creates++
+					return resource.ID(fmt.Sprintf("%s-%d", urn.Name(), creates)), news, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range []string{"resA", "resB"} {
+			_, err := monitor.RegisterResource("pkgA:m:typA", name, true)
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{
+			HostF:         deploytest.NewPluginHostF(nil, nil, programF, loaders...),
+			UpdateOptions: UpdateOptions{GeneratePlan: true, Experimental: true},
+		},
+	}
+	project := p.GetProject()
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	// Plan the destroy, and check that the plan records the IDs of the resources that it deletes.
+	plan, err := TestOp(Destroy).Plan(project, p.GetTarget(t, snap), p.Options, p.BackendClient, nil)
+	require.NoError(t, err)
+	resA := plan.ResourcePlans["urn:pulumi:test::test::pkgA:m:typA::resA"]
+	require.NotNil(t, resA)
+	assert.Equal(t, []display.StepOp{deploy.OpDelete}, resA.Ops)
+	assert.Equal(t, []resource.ID{"resA-1"}, resA.DeleteIDs)
+
+	// Replace resA outside of the plan. The destroy must not delete the new resource.
+	replaced := *snap.Resources[1]
+	require.Equal(t, "resA", replaced.URN.Name())
+	replaced.ID = "resA-replaced"
+	replacedSnap := &deploy.Snapshot{
+		Resources: []*resource.State{snap.Resources[0], &replaced, snap.Resources[2]},
+	}
+	p.Options.Plan = plan.Clone()
+	validate := ExpectDiagMessage(t, regexp.QuoteMeta(
+		"delete is not allowed by the plan: resource ID changed (expected one of [resA-1])"))
+	after, err := TestOp(Destroy).Run(project, p.GetTarget(t, replacedSnap), p.Options, false, p.BackendClient,
+		validate)
+	require.NoError(t, err)
+	assert.Len(t, after.Resources, 3)
+
+	// The destroy succeeds against the resources that were planned.
+	p.Options.Plan = plan.Clone()
+	after, err = TestOp(Destroy).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	assert.Empty(t, after.Resources)
+}
+
+// TestPlannedRefresh tests that a refresh can be constrained to a plan generated by a refresh preview, that the plan
+// survives serialization, and that a refresh that finds changes the plan didn't expect records nothing.
+func TestPlannedRefresh(t *testing.T) {
+	t.Parallel()
+
+	var sizeLock sync.Mutex
+	size := 1.0
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					return resource.ID(urn.Name()), news, resource.StatusOK, nil
+				},
+				ReadF: func(
+					urn resource.URN, id resource.ID, inputs, state resource.PropertyMap,
+				) (plugin.ReadResult, resource.Status, error) {
+					if urn.Name() != "resA" {
+						return plugin.ReadResult{Inputs: inputs, Outputs: state}, resource.StatusOK, nil
+					}
+					sizeLock.Lock()
+					defer sizeLock.Unlock()
+					outputs := state.Copy()
+					outputs["size"] = resource.NewNumberProperty(size)
+					return plugin.ReadResult{Inputs: inputs, Outputs: outputs}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+	setSize := func(v float64) {
+		sizeLock.Lock()
+		defer sizeLock.Unlock()
+		size = v
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		for _, name := range []string{"resA", "resB"} {
+			_, err := monitor.RegisterResource("pkgA:m:typA", name, true, deploytest.ResourceOptions{
+				Inputs: resource.PropertyMap{"size": resource.NewNumberProperty(1)},
+			})
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{
+			HostF:         deploytest.NewPluginHostF(nil, nil, programF, loaders...),
+			UpdateOptions: UpdateOptions{GeneratePlan: true, Experimental: true},
+		},
+	}
+	project := p.GetProject()
+
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	// resA drifts, and a refresh preview plans to record that.
+	setSize(2)
+	plan, err := TestOp(Refresh).Plan(project, p.GetTarget(t, snap), p.Options, p.BackendClient, nil)
+	require.NoError(t, err)
+	resA := plan.ResourcePlans["urn:pulumi:test::test::pkgA:m:typA::resA"]
+	require.NotNil(t, resA)
+	assert.Equal(t, []display.StepOp{deploy.OpRefresh}, resA.Ops)
+	require.Len(t, resA.Refreshes, 1)
+	assert.Equal(t, deploy.OpUpdate, resA.Refreshes[0].Result)
+	resB := plan.ResourcePlans["urn:pulumi:test::test::pkgA:m:typA::resB"]
+	require.NotNil(t, resB)
+	require.Len(t, resB.Refreshes, 1)
+	assert.Equal(t, deploy.OpSame, resB.Refreshes[0].Result)
+
+	serialized, err := stack.SerializePlan(plan, config.NopEncrypter, false)
+	require.NoError(t, err)
+	plan, err = stack.DeserializePlan(serialized, config.NopDecrypter, config.NopEncrypter)
+	require.NoError(t, err)
+
+	// By the time the refresh runs, resA has drifted further than was planned. Nothing may be recorded.
+	setSize(3)
+	p.Options.Plan = plan.Clone()
+	validate := ExpectDiagMessage(t, regexp.QuoteMeta("resource violates plan: properties changed: ~~size[{2}!={3}]"))
+	after, err := TestOp(Refresh).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, validate)
+	require.NoError(t, err)
+	require.Len(t, after.Resources, 3)
+	assert.Equal(t, resource.NewNumberProperty(1), after.Resources[1].Outputs["size"])
+
+	// The refresh succeeds once the resource matches the plan.
+	setSize(2)
+	p.Options.Plan = plan.Clone()
+	after, err = TestOp(Refresh).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	require.Len(t, after.Resources, 3)
+	assert.Equal(t, resource.NewNumberProperty(2), after.Resources[1].Outputs["size"])
+
+	// A destroy can't be run with a refresh plan.
+	p.Options.Plan = plan.Clone()
+	validate = ExpectDiagMessage(t, regexp.QuoteMeta(
+		"delete is not allowed by the plan: this resource is constrained to refresh"))
+	_, err = TestOp(Destroy).Run(project, p.GetTarget(t, after), p.Options, false, p.BackendClient, validate)
+	require.NoError(t, err)
+}
@@ -58,9 +58,14 @@ func Refresh(
 		return nil, nil, err
 	}
 
+	sourceFunc := newRefreshSource
+	if opts.RefreshProgram {
+		sourceFunc = newRefreshProgramSource
+	}
+
 	return update(ctx, info, &deploymentOptions{
 		UpdateOptions: opts,
-		SourceFunc:    newRefreshSource,
+		SourceFunc:    sourceFunc,
 		Events:        emitter,
 		Diag:          newEventSink(emitter, false),
 		StatusDiag:    newEventSink(emitter, true),
@@ -89,3 +94,12 @@ func newRefreshSource(
 	// Just return an error source. Refresh doesn't use its source.
 	return deploy.NewErrorSource(proj.Name), nil
 }
+
+// newRefreshProgramSource returns a source that evaluates the program for a refresh. The program is evaluated only to
+// find the resources that it declares, never to deploy them, so it always runs as a preview.
+func newRefreshProgramSource(
+	ctx context.Context, client deploy.BackendClient, opts *deploymentOptions, proj *workspace.Project, pwd, main,
+	projectRoot string, target *deploy.Target, plugctx *plugin.Context, dryRun bool,
+) (deploy.Source, error) {
+	return newUpdateSource(ctx, client, opts, proj, pwd, main, projectRoot, target, plugctx, true /*dryRun*/)
+}
@@ -23,6 +23,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	resourceanalyzer "github.com/pulumi/pulumi/pkg/v3/resource/analyzer"
@@ -125,12 +126,25 @@ type UpdateOptions struct {
 	// the degree of parallelism for resource operations (<=1 for serial).
 	Parallel int
 
+	// limits on the degree of parallelism for specific resource packages or type tokens.
+	ParallelFor map[string]int
+
+	// the policy for retrying failed provider operations on resources that don't set their own.
+	RetryPolicy *resource.RetryPolicy
+
+	// if set, checks plaintext config, resource inputs and registered outputs for values that look like secrets.
+	SecretScanner *deploy.SecretScanner
+
 	// true if debugging output it enabled
 	Debug bool
 
 	// true if the plan should refresh before executing.
 	Refresh bool
 
+	// true if a refresh should evaluate the program, reading resources with the inputs that the program declares and
+	// reporting the resources that it no longer declares.
+	RefreshProgram bool
+
 	// Specific resources to replace during an update operation.
 	ReplaceTargets deploy.UrnTargets
 
@@ -141,6 +155,12 @@ type UpdateOptions struct {
 	// XXXTargets lists.
 	TargetDependents bool
 
+	// Specific resources to leave alone during a deployment.
+	Excludes deploy.UrnTargets
+
+	// true if resources that depend on an excluded resource should be excluded too.
+	ExcludeDependents bool
+
 	// true if the engine should use legacy diffing behavior during an update.
 	UseLegacyDiff bool
 
@@ -656,6 +676,12 @@ func (acts *updateActions) OnResourceStepPost(
 	return ctx.(SnapshotMutation).End(step, err == nil || status == resource.StatusPartialFailure)
 }
 
+func (acts *updateActions) OnResourceStepRetry(
+	step deploy.Step, attempt, maxAttempts int, delay time.Duration, err error,
+) {
+	acts.Opts.Events.resourceOperationRetryEvent(step, attempt, maxAttempts, delay, err, acts.Opts.Debug)
+}
+
 func (acts *updateActions) OnResourceOutputs(step deploy.Step) error {
 	func() {
 		acts.MapLock.Lock()
@@ -782,6 +808,12 @@ func (acts *previewActions) OnResourceStepPost(ctx interface{},
 	return nil
 }
 
+func (acts *previewActions) OnResourceStepRetry(
+	step deploy.Step, attempt, maxAttempts int, delay time.Duration, err error,
+) {
+	acts.Opts.Events.resourceOperationRetryEvent(step, attempt, maxAttempts, delay, err, acts.Opts.Debug)
+}
+
 func (acts *previewActions) OnResourceOutputs(step deploy.Step) error {
 	func() {
 		acts.MapLock.Lock()
@@ -21,6 +21,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	uuid "github.com/gofrs/uuid"
 
@@ -56,15 +57,25 @@ type Options struct {
 	Parallel                  int        // the degree of parallelism for resource operations (<=1 for serial).
 	Refresh                   bool       // whether or not to refresh before executing the deployment.
 	RefreshOnly               bool       // whether or not to exit after refreshing.
+	RefreshProgram            bool       // whether or not to evaluate the program before a refresh-only deployment.
 	Targets                   UrnTargets // If specified, only operate on specified resources.
 	ReplaceTargets            UrnTargets // If specified, mark the specified resources for replacement.
 	TargetDependents          bool       // true if we're allowing things to proceed, even with unspecified targets
+	Excludes                  UrnTargets // If specified, do not operate on the specified resources.
+	ExcludeDependents         bool       // true if resources that depend on excluded resources are excluded too.
 	UseLegacyDiff             bool       // whether or not to use legacy diffing behavior.
 	DisableResourceReferences bool       // true to disable resource reference support.
 	DisableOutputValues       bool       // true to disable output value support.
 	GeneratePlan              bool       // true to enable plan generation.
 	// true if we should continue with the deployment even if a resource operation fails.
 	ContinueOnError bool
+	// limits on the number of concurrent resource operations for specific resource packages or type tokens, on top
+	// of the overall Parallel limit.
+	ParallelFor map[string]int
+	// the policy for retrying failed provider operations on resources that don't set their own.
+	RetryPolicy *resource.RetryPolicy
+	// if set, checks plaintext config, resource inputs and outputs for values that look like secrets.
+	SecretScanner *SecretScanner
 }
 
 // DegreeOfParallelism returns the degree of parallelism that should be used during the
@@ -194,6 +205,7 @@ func (t *UrnTargets) addLiteral(urn resource.URN) {
 type StepExecutorEvents interface {
 	OnResourceStepPre(step Step) (interface{}, error)
 	OnResourceStepPost(ctx interface{}, step Step, status resource.Status, err error) error
+	OnResourceStepRetry(step Step, attempt, maxAttempts int, delay time.Duration, err error)
 	OnResourceOutputs(step Step) error
 }
 
@@ -275,6 +287,8 @@ type Deployment struct {
 	providers *providers.Registry
 	// the set of resource goals generated by the deployment.
 	goals *gsync.Map[resource.URN, *resource.Goal]
+	// the hooks registered for the resources in the deployment.
+	hooks *gsync.Map[resource.URN, *ResourceHooks]
 	// the set of new resources generated by the deployment.
 	news *gsync.Map[resource.URN, *resource.State]
 	// the set of new resource plans.
@@ -463,6 +477,7 @@ func NewDeployment(ctx *plugin.Context, target *Target, prev *Snapshot, plan *Pl
 		depGraph:             depGraph,
 		providers:            reg,
 		goals:                newGoals,
+		hooks:                &gsync.Map[resource.URN, *ResourceHooks]{},
 		news:                 newResources,
 		newPlans:             newResourcePlan(target.Config),
 	}, nil
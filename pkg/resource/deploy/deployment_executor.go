@@ -151,11 +151,24 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 
 	// Before doing anything else, optionally refresh each resource in the base checkpoint.
 	if opts.Refresh {
-		if err := ex.refresh(callerCtx, opts, preview); err != nil {
+		// If asked, first evaluate the program to find out which resources it still declares.
+		var declared map[resource.URN]*resource.Goal
+		if opts.RefreshOnly && opts.RefreshProgram {
+			var err error
+			if declared, err = ex.evaluateProgram(callerCtx, opts); err != nil {
+				return nil, err
+			}
+		}
+		if err := ex.refresh(callerCtx, opts, preview, declared); err != nil {
 			return nil, err
 		}
 		if opts.RefreshOnly {
-			return nil, nil
+			if ex.deployment.plan != nil {
+				if err := ex.checkPlanCompleted(); err != nil {
+					return nil, result.BailError(err)
+				}
+			}
+			return ex.deployment.newPlans.plan(), nil
 		}
 	} else if ex.deployment.prev != nil && len(ex.deployment.prev.PendingOperations) > 0 && !preview {
 		// Print a warning for users that there are pending operations.
@@ -214,6 +227,15 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 	//     should bail.
 	//  3. The stepExecCancel cancel context gets canceled. This means some error occurred in the step executor
 	//     and we need to bail. This can also happen if the user hits Ctrl-C.
+	//
+	// Once the source has ended, the program may still be waiting so that the steps that follow it, such as deletes,
+	// can call its callbacks. It is released by closing the source once every step has completed.
+	sourceFinished := false
+	defer func() {
+		if sourceFinished {
+			contract.IgnoreClose(src)
+		}
+	}()
 	canceled, err := func() (bool, error) {
 		logging.V(4).Infof("deploymentExecutor.Execute(...): waiting for incoming events")
 		for {
@@ -233,6 +255,8 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 				}
 
 				if event.Event == nil {
+					sourceFinished = true
+
 					// Check targets before performDeletes mutates the initial Snapshot.
 					targetErr := ex.checkTargets(opts.Targets)
 
@@ -285,37 +309,11 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 
 	logging.V(4).Infof("deploymentExecutor.Execute(...): step executor has completed")
 
-	// Check that we did operations for everything expected in the plan. We mutate ResourcePlan.Ops as we run
-	// so by the time we get here everything in the map should have an empty ops list (except for unneeded
-	// deletes). We skip this check if we already have an error, chances are if the deployment failed lots of
-	// operations wouldn't have got a chance to run so we'll spam errors about all of those failed operations
-	// making it less clear to the user what the root cause error was.
+	// Check that we did operations for everything expected in the plan. We skip this check if we already have an
+	// error, chances are if the deployment failed lots of operations wouldn't have got a chance to run so we'll spam
+	// errors about all of those failed operations making it less clear to the user what the root cause error was.
 	if err == nil && ex.deployment.plan != nil {
-		for urn, resourcePlan := range ex.deployment.plan.ResourcePlans {
-			if len(resourcePlan.Ops) != 0 {
-				if len(resourcePlan.Ops) == 1 && resourcePlan.Ops[0] == OpDelete {
-					// We haven't done a delete for this resource check if it was in the snapshot,
-					// if it's already gone this wasn't done because it wasn't needed
-					found := false
-					for i := range ex.deployment.prev.Resources {
-						if ex.deployment.prev.Resources[i].URN == urn {
-							found = true
-							break
-						}
-					}
-
-					// Didn't find the resource in the old snapshot so this was just an unneeded delete
-					if !found {
-						continue
-					}
-				}
-
-				rErr := fmt.Errorf("expected resource operations for %v but none were seen", urn)
-				logging.V(4).Infof("deploymentExecutor.Execute(...): error handling event: %v", rErr)
-				ex.reportError(urn, rErr)
-				err = errors.Join(err, rErr)
-			}
-		}
+		err = ex.checkPlanCompleted()
 		// If we made any errors above wrap it in a bail
 		if err != nil {
 			err = result.BailError(err)
@@ -359,6 +357,39 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 	return ex.deployment.newPlans.plan(), err
 }
 
+// checkPlanCompleted checks that we did operations for everything expected in the deployment's plan, reporting an
+// error for each resource that is missing operations. We mutate ResourcePlan.Ops as we run so by the time we get here
+// everything in the map should have an empty ops list (except for unneeded deletes).
+func (ex *deploymentExecutor) checkPlanCompleted() error {
+	var err error
+	for urn, resourcePlan := range ex.deployment.plan.ResourcePlans {
+		if len(resourcePlan.Ops) != 0 {
+			if len(resourcePlan.Ops) == 1 && resourcePlan.Ops[0] == OpDelete {
+				// We haven't done a delete for this resource check if it was in the snapshot,
+				// if it's already gone this wasn't done because it wasn't needed
+				found := false
+				for i := range ex.deployment.prev.Resources {
+					if ex.deployment.prev.Resources[i].URN == urn {
+						found = true
+						break
+					}
+				}
+
+				// Didn't find the resource in the old snapshot so this was just an unneeded delete
+				if !found {
+					continue
+				}
+			}
+
+			rErr := fmt.Errorf("expected resource operations for %v but none were seen", urn)
+			logging.V(4).Infof("deploymentExecutor.Execute(...): error handling event: %v", rErr)
+			ex.reportError(urn, rErr)
+			err = errors.Join(err, rErr)
+		}
+	}
+	return err
+}
+
 func (ex *deploymentExecutor) performDeletes(
 	ctx context.Context, targetsOpt UrnTargets,
 ) error {
@@ -527,8 +558,144 @@ func (ex *deploymentExecutor) importResources(
 	return ex.deployment.newPlans.plan(), nil
 }
 
-// refresh refreshes the state of the base checkpoint file for the current deployment in memory.
-func (ex *deploymentExecutor) refresh(callerCtx context.Context, opts Options, preview bool) error {
+// evaluateProgram runs the deployment's program without generating any steps for the resources that it registers,
+// and returns the goals of the resources that it declares, keyed by the URNs of their existing states (if any). Each
+// registration is answered with the resource's existing state, so the program sees the same values that it would
+// during a preview. Resources that are read rather than registered are declared with a nil goal.
+func (ex *deploymentExecutor) evaluateProgram(
+	callerCtx context.Context, opts Options,
+) (map[resource.URN]*resource.Goal, error) {
+	src, err := ex.deployment.source.Iterate(callerCtx, opts, ex.deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	// The step generator is only used to compute the URNs and aliases of the registered resources.
+	sg := newStepGenerator(ex.deployment, opts, opts.Targets, opts.ReplaceTargets)
+	declared := make(map[resource.URN]*resource.Goal)
+	for {
+		event, err := src.Next()
+		if err != nil {
+			if !result.IsBail(err) {
+				ex.reportError("", err)
+			}
+			return nil, result.BailError(err)
+		}
+		if event == nil {
+			contract.IgnoreClose(src)
+			return declared, nil
+		}
+
+		switch e := event.(type) {
+		case RegisterResourceEvent:
+			state, old, err := ex.evaluateRegistration(sg, e.Goal())
+			if err != nil {
+				ex.reportError(ex.deployment.generateEventURN(e), err)
+				return nil, result.BailError(err)
+			}
+			if old != nil {
+				declared[old.URN] = e.Goal()
+			} else {
+				declared[state.URN] = e.Goal()
+			}
+			e.Done(&RegisterResult{State: state})
+		case ReadResourceEvent:
+			urn, err := sg.generateURN(e.Parent(), e.Type(), e.Name())
+			if err != nil {
+				ex.reportError(urn, err)
+				return nil, result.BailError(err)
+			}
+			state, has := ex.deployment.Olds()[urn]
+			if !has {
+				state = &resource.State{
+					Type: e.Type(), URN: urn, Custom: true, External: true, ID: e.ID(),
+					Inputs: e.Properties(), Outputs: resource.PropertyMap{}, Parent: e.Parent(), Provider: e.Provider(),
+				}
+			}
+			declared[urn] = nil
+			e.Done(&ReadResult{State: state})
+		case RegisterResourceOutputsEvent:
+			e.Done()
+		}
+	}
+}
+
+// evaluateRegistration answers a resource registration made while evaluating the program for a refresh. It returns
+// the state to answer with and the resource's existing state, found by URN or alias, if any. The state to answer with
+// is the existing state under the resource's new URN, or a placeholder state for a resource that doesn't exist yet.
+// Providers are loaded so that the program can invoke them.
+func (ex *deploymentExecutor) evaluateRegistration(
+	sg *stepGenerator, goal *resource.Goal,
+) (*resource.State, *resource.State, error) {
+	parent, err := sg.checkParent(goal.Parent, goal.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	goal.Parent = parent
+
+	urn, err := sg.generateURN(goal.Parent, goal.Type, goal.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var old *resource.State
+	for _, urnOrAlias := range append([]resource.URN{urn}, sg.generateAliases(goal)...) {
+		if o, has := ex.deployment.Olds()[urnOrAlias]; has {
+			old = o
+			if urnOrAlias != urn {
+				sg.aliases[urn] = urnOrAlias
+			}
+			break
+		}
+	}
+
+	isProvider := providers.IsProviderType(goal.Type)
+	if old != nil {
+		if isProvider {
+			ref, err := providers.NewReference(old.URN, old.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := ex.deployment.EnsureProvider(ref.String()); err != nil {
+				return nil, nil, err
+			}
+			if old.URN != urn {
+				ex.deployment.providers.RegisterAlias(urn, old.URN)
+			}
+		}
+		state := *old
+		state.URN = urn
+		return &state, old, nil
+	}
+
+	state := &resource.State{
+		Type: goal.Type, URN: urn, Custom: goal.Custom, Inputs: goal.Properties, Outputs: resource.PropertyMap{},
+		Parent: goal.Parent, Provider: goal.Provider,
+	}
+	if isProvider {
+		// Load and configure the new provider as a preview would, giving it an unknown ID.
+		inputs, failures, err := ex.deployment.providers.Check(urn, nil, goal.Properties, true, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(failures) != 0 {
+			return nil, nil, fmt.Errorf("invalid configuration for provider %v: %v", urn, failures[0].Reason)
+		}
+		id, _, _, err := ex.deployment.providers.Create(urn, inputs, 0, true /*preview*/)
+		if err != nil {
+			return nil, nil, err
+		}
+		state.ID, state.Inputs = id, inputs
+	}
+	return state, nil, nil
+}
+
+// refresh refreshes the state of the base checkpoint file for the current deployment in memory. If declared is not
+// nil, it holds the resources that the program declares, as returned by evaluateProgram: the program's inputs are
+// used to read the declared resources, and the other resources are reported as no longer declared.
+func (ex *deploymentExecutor) refresh(
+	callerCtx context.Context, opts Options, preview bool, declared map[resource.URN]*resource.Goal,
+) error {
 	prev := ex.deployment.prev
 	if prev == nil || len(prev.Resources) == 0 {
 		return nil
@@ -544,7 +711,12 @@ func (ex *deploymentExecutor) refresh(callerCtx context.Context, opts Options, p
 	// specific targets.
 	steps := []Step{}
 	resourceToStep := map[*resource.State]Step{}
+	excluded := excludedResources(prev.Resources, opts.Excludes, opts.ExcludeDependents)
 	for _, res := range prev.Resources {
+		if reason, has := excluded[res.URN]; has {
+			ex.deployment.Diag().Infof(diag.Message(res.URN, "Not refreshing '%v' because %s"), res.URN, reason)
+			continue
+		}
 		if opts.Targets.Contains(res.URN) {
 			// For each resource we're going to refresh we need to ensure we have a provider for it
 			err := ex.deployment.EnsureProvider(res.Provider)
@@ -552,7 +724,22 @@ func (ex *deploymentExecutor) refresh(callerCtx context.Context, opts Options, p
 				return fmt.Errorf("could not load provider for resource %v: %w", res.URN, err)
 			}
 
-			step := NewRefreshStep(ex.deployment, res, nil)
+			step := NewRefreshStep(ex.deployment, res, nil).(*RefreshStep)
+			if declared != nil && !res.Delete {
+				goal, has := declared[res.URN]
+				switch {
+				case !has:
+					step.undeclared = true
+				case goal != nil && !goal.Properties.ContainsUnknowns():
+					step.programInputs = goal.Properties
+				}
+			}
+			if opts.RefreshOnly && ex.deployment.plan != nil {
+				if err := ex.constrainRefresh(step); err != nil {
+					ex.reportError(res.URN, err)
+					return result.BailError(err)
+				}
+			}
 			steps = append(steps, step)
 			resourceToStep[res] = step
 		}
@@ -565,8 +752,34 @@ func (ex *deploymentExecutor) refresh(callerCtx context.Context, opts Options, p
 	stepExec.SignalCompletion()
 	stepExec.WaitForCompletion()
 
+	// If we're generating plans, record what each refresh found. Refreshes that are part of an update are planned
+	// by the steps that follow them instead.
+	if opts.RefreshOnly && opts.GeneratePlan && stepExec.Errored() == nil {
+		for _, step := range steps {
+			step := step.(*RefreshStep)
+			resourcePlan, ok := ex.deployment.newPlans.get(step.URN())
+			if !ok {
+				resourcePlan = &ResourcePlan{}
+				ex.deployment.newPlans.set(step.URN(), resourcePlan)
+			}
+			resourcePlan.Ops = append(resourcePlan.Ops, OpRefresh)
+			resourcePlan.Refreshes = append(resourcePlan.Refreshes, NewRefreshPlan(step.old, step.new, step.ResultOp()))
+			if step.new != nil {
+				resourcePlan.Outputs = step.new.Outputs
+			}
+		}
+	}
+
 	ex.rebuildBaseState(resourceToStep)
 
+	// Warn about any resources that still exist but that the program no longer declares.
+	for _, step := range steps {
+		if step := step.(*RefreshStep); step.undeclared && step.new != nil {
+			ex.deployment.Diag().Warningf(diag.Message(step.URN(),
+				"%v is no longer declared by the program; the next update will delete it"), step.URN())
+		}
+	}
+
 	// NOTE: we use the presence of an error in the caller context in order to distinguish caller-initiated
 	// cancellation from internally-initiated cancellation.
 	canceled := callerCtx.Err() != nil
@@ -582,6 +795,34 @@ func (ex *deploymentExecutor) refresh(callerCtx context.Context, opts Options, p
 	return nil
 }
 
+// constrainRefresh checks the given refresh step against the deployment's plan and records the result that the plan
+// expects the refresh to have. A resource that the plan doesn't mention may only be refreshed if the refresh finds
+// that it hasn't changed.
+func (ex *deploymentExecutor) constrainRefresh(step *RefreshStep) error {
+	resourcePlan, ok := ex.deployment.plan.ResourcePlans[step.URN()]
+	if !ok {
+		step.plan = &RefreshPlan{ID: step.old.ID, Result: OpSame}
+		return nil
+	}
+
+	if len(resourcePlan.Ops) == 0 {
+		return fmt.Errorf("%v is not allowed by the plan: no more steps were expected for this resource", OpRefresh)
+	}
+	constraint := resourcePlan.Ops[0]
+	// As with other steps, we remove the Op from the list before doing the constraint check.
+	resourcePlan.Ops = resourcePlan.Ops[1:]
+	if !ConstrainedTo(OpRefresh, constraint) {
+		return fmt.Errorf("%v is not allowed by the plan: this resource is constrained to %v", OpRefresh, constraint)
+	}
+
+	refresh, err := resourcePlan.takeRefresh(step.old)
+	if err != nil {
+		return fmt.Errorf("%v is not allowed by the plan: %w", OpRefresh, err)
+	}
+	step.plan = refresh
+	return nil
+}
+
 func (ex *deploymentExecutor) rebuildBaseState(resourceToStep map[*resource.State]Step) {
 	// Rebuild this deployment's map of old resources and dependency graph, stripping out any deleted
 	// resources and repairing dependency lists as necessary. Note that this updates the base
@@ -31,6 +31,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -153,6 +154,10 @@ type ResourceOptions struct {
 	Transforms []*pulumirpc.Callback
 
 	SupportsResultReporting bool
+
+	RetryPolicy *pulumirpc.RegisterResourceRequest_RetryPolicy
+
+	Hooks *pulumirpc.RegisterResourceRequest_ResourceHooks
 }
 
 func (rm *ResourceMonitor) unmarshalProperties(props *structpb.Struct) (resource.PropertyMap, error) {
@@ -283,6 +288,8 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		SourcePosition:             sourcePosition,
 		Transforms:                 opts.Transforms,
 		SupportsResultReporting:    opts.SupportsResultReporting,
+		RetryPolicy:                opts.RetryPolicy,
+		Hooks:                      opts.Hooks,
 	}
 
 	ctx := context.Background()
@@ -486,6 +493,13 @@ func (rm *ResourceMonitor) RegisterStackTransform(callback *pulumirpc.Callback)
 	return err
 }
 
+// SignalAndWaitForShutdown tells the engine that the program is done registering resources, and waits until the
+// deployment has finished.
+func (rm *ResourceMonitor) SignalAndWaitForShutdown() error {
+	_, err := rm.resmon.SignalAndWaitForShutdown(context.Background(), &emptypb.Empty{})
+	return err
+}
+
 func prepareTestTimeout(timeout float64) string {
 	if timeout == 0 {
 		return ""
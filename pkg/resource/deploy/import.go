@@ -99,6 +99,7 @@ func NewImportDeployment(ctx *plugin.Context, target *Target, projectName tokens
 		prev:         prev,
 		olds:         olds,
 		goals:        newGoals,
+		hooks:        &gsync.Map[resource.URN, *ResourceHooks]{},
 		imports:      imports,
 		isImport:     true,
 		schemaLoader: schema.NewPluginLoader(ctx.Host),
@@ -114,6 +115,7 @@ type noopEvent int
 
 func (noopEvent) event()                      {}
 func (noopEvent) Goal() *resource.Goal        { return nil }
+func (noopEvent) Hooks() *ResourceHooks       { return nil }
 func (noopEvent) Done(result *RegisterResult) {}
 
 type noopOutputsEvent resource.URN
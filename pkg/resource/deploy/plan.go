@@ -201,6 +201,33 @@ type ResourcePlan struct {
 	Outputs resource.PropertyMap
 	// The random byte seed used for resource goal.
 	Seed []byte
+	// The IDs of the existing resources that the plan's delete steps are expected to delete. This is nil for plans
+	// that predate it, in which case any resource with this URN may be deleted.
+	DeleteIDs []resource.ID
+	// The expected results of the plan's refresh steps, one per existing resource with this URN.
+	Refreshes []*RefreshPlan
+}
+
+// A RefreshPlan records what a planned refresh of a single existing resource is expected to find.
+type RefreshPlan struct {
+	// the ID of the resource to refresh.
+	ID resource.ID
+	// the operation that the refresh is expected to result in: same, update, or delete.
+	Result display.StepOp
+	// the resource's output properties we expect the refresh to change (only set if Result is update).
+	OutputDiff PlanDiff
+}
+
+// NewRefreshPlan returns a RefreshPlan that records the result of refreshing the old state of a resource to the new
+// state, which is nil if the refresh found that the resource no longer exists.
+func NewRefreshPlan(old, new *resource.State, result display.StepOp) *RefreshPlan {
+	contract.Requiref(old != nil, "old", "must not be nil")
+
+	plan := &RefreshPlan{ID: old.ID, Result: result}
+	if new != nil {
+		plan.OutputDiff = NewPlanDiff(old.Outputs.Diff(new.Outputs))
+	}
+	return plan
 }
 
 func (rp *ResourcePlan) diffURNs(a, b []resource.URN) (message string, changed bool) {
@@ -523,7 +550,10 @@ func (rp *ResourcePlan) checkGoal(
 	}
 
 	if rp.Goal == nil {
-		// If the plan goal is nil it expected a delete
+		// If the plan goal is nil it expected a delete, or a refresh that doesn't run the program.
+		if len(rp.Refreshes) != 0 {
+			return errors.New("resource was only expected to be refreshed")
+		}
 		return errors.New("resource unexpectedly not deleted")
 	}
 
@@ -633,3 +663,49 @@ func (rp *ResourcePlan) checkGoal(
 
 	return nil
 }
+
+// checkDelete checks that the given resource is one that the plan expects to delete. Each planned delete may only be
+// checked once.
+func (rp *ResourcePlan) checkDelete(old *resource.State) error {
+	if rp.DeleteIDs == nil {
+		return nil
+	}
+	for i, id := range rp.DeleteIDs {
+		if id == old.ID {
+			rp.DeleteIDs = append(rp.DeleteIDs[:i:i], rp.DeleteIDs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("resource ID changed (expected one of %v)", rp.DeleteIDs)
+}
+
+// takeRefresh returns the planned refresh of the given resource and removes it from the plan, or an error if the plan
+// does not expect the resource to be refreshed.
+func (rp *ResourcePlan) takeRefresh(old *resource.State) (*RefreshPlan, error) {
+	if len(rp.Refreshes) == 0 {
+		return nil, errors.New("no refresh was expected for this resource")
+	}
+	for i, refresh := range rp.Refreshes {
+		if refresh.ID == old.ID {
+			rp.Refreshes = append(rp.Refreshes[:i:i], rp.Refreshes[i+1:]...)
+			return refresh, nil
+		}
+	}
+	ids := make([]resource.ID, len(rp.Refreshes))
+	for i, refresh := range rp.Refreshes {
+		ids[i] = refresh.ID
+	}
+	return nil, fmt.Errorf("resource ID changed (expected one of %v)", ids)
+}
+
+// checkRefresh checks that refreshing the old state of a resource to the new state, which is nil if the resource no
+// longer exists, had the result that the plan expected.
+func (p *RefreshPlan) checkRefresh(old, new *resource.State, result display.StepOp) error {
+	if result != p.Result {
+		return fmt.Errorf("refresh result changed (expected %v, got %v)", p.Result, result)
+	}
+	if new == nil {
+		return nil
+	}
+	return checkDiff(old.Outputs, new.Outputs, p.OutputDiff)
+}
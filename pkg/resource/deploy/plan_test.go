@@ -536,8 +536,61 @@ func TestResourcePlan(t *testing.T) {
 					})
 				assert.ErrorContains(t, err, "aliases changed")
 			})
+			t.Run("only refreshed", func(t *testing.T) {
+				t.Parallel()
+				rp := &ResourcePlan{Refreshes: []*RefreshPlan{{ID: "id", Result: OpSame}}}
+				err := rp.checkGoal(resource.PropertyMap{}, resource.PropertyMap{}, &resource.Goal{})
+				assert.ErrorContains(t, err, "resource was only expected to be refreshed")
+			})
 		})
 	})
+	t.Run("checkDelete", func(t *testing.T) {
+		t.Parallel()
+		t.Run("no IDs", func(t *testing.T) {
+			t.Parallel()
+			rp := &ResourcePlan{}
+			assert.NoError(t, rp.checkDelete(&resource.State{ID: "any"}))
+		})
+		t.Run("each ID once", func(t *testing.T) {
+			t.Parallel()
+			rp := &ResourcePlan{DeleteIDs: []resource.ID{"a", "b"}}
+			assert.NoError(t, rp.checkDelete(&resource.State{ID: "b"}))
+			assert.ErrorContains(t, rp.checkDelete(&resource.State{ID: "b"}), "resource ID changed (expected one of [a])")
+			assert.NoError(t, rp.checkDelete(&resource.State{ID: "a"}))
+		})
+	})
+	t.Run("takeRefresh", func(t *testing.T) {
+		t.Parallel()
+		rp := &ResourcePlan{Refreshes: []*RefreshPlan{{ID: "a"}, {ID: "b"}}}
+		refresh, err := rp.takeRefresh(&resource.State{ID: "b"})
+		assert.NoError(t, err)
+		assert.Equal(t, resource.ID("b"), refresh.ID)
+		_, err = rp.takeRefresh(&resource.State{ID: "c"})
+		assert.ErrorContains(t, err, "resource ID changed (expected one of [a])")
+		_, err = rp.takeRefresh(&resource.State{ID: "a"})
+		assert.NoError(t, err)
+		_, err = rp.takeRefresh(&resource.State{ID: "a"})
+		assert.ErrorContains(t, err, "no refresh was expected for this resource")
+	})
+}
+
+func TestRefreshPlan(t *testing.T) {
+	t.Parallel()
+
+	old := &resource.State{ID: "id", Outputs: resource.PropertyMap{"size": resource.NewNumberProperty(1)}}
+	updated := &resource.State{ID: "id", Outputs: resource.PropertyMap{"size": resource.NewNumberProperty(2)}}
+
+	plan := NewRefreshPlan(old, updated, OpUpdate)
+	assert.Equal(t, resource.ID("id"), plan.ID)
+	assert.NoError(t, plan.checkRefresh(old, updated, OpUpdate))
+	assert.ErrorContains(t, plan.checkRefresh(old, old, OpSame), "refresh result changed (expected update, got same)")
+	assert.ErrorContains(t, plan.checkRefresh(old, nil, OpDelete), "refresh result changed (expected update, got delete)")
+
+	other := &resource.State{ID: "id", Outputs: resource.PropertyMap{"size": resource.NewNumberProperty(3)}}
+	assert.ErrorContains(t, plan.checkRefresh(old, other, OpUpdate), "properties changed: ~~size[{2}!={3}]")
+
+	deleted := NewRefreshPlan(old, nil, OpDelete)
+	assert.NoError(t, deleted.checkRefresh(old, nil, OpDelete))
 }
 
 func TestCheckDiff(t *testing.T) {
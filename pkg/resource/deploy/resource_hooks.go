@@ -0,0 +1,103 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// ResourceHookArgs are the arguments passed to a resource hook.
+type ResourceHookArgs struct {
+	URN        resource.URN         // the URN of the resource.
+	ID         resource.ID          // the ID of the resource, if it has one.
+	Operation  display.StepOp       // the operation the hook is run around: OpCreate, OpUpdate, OpDelete or OpReplace.
+	After      bool                 // true if the hook is run after the operation, false if before.
+	OldInputs  resource.PropertyMap // the old input properties of the resource, if any.
+	OldOutputs resource.PropertyMap // the old output properties of the resource, if any.
+	NewInputs  resource.PropertyMap // the new input properties of the resource, if any.
+	NewOutputs resource.PropertyMap // the new output properties of the resource, if any.
+}
+
+// A ResourceHookFunction is a hook that is run before or after an operation on a resource. If it returns an error the
+// operation fails.
+type ResourceHookFunction func(ctx context.Context, args ResourceHookArgs) error
+
+// ResourceHooks are the hooks registered by the program for a resource.
+type ResourceHooks struct {
+	BeforeCreate  []ResourceHookFunction
+	AfterCreate   []ResourceHookFunction
+	BeforeUpdate  []ResourceHookFunction
+	AfterUpdate   []ResourceHookFunction
+	BeforeDelete  []ResourceHookFunction
+	AfterDelete   []ResourceHookFunction
+	BeforeReplace []ResourceHookFunction
+	AfterReplace  []ResourceHookFunction
+}
+
+// resourceHook is a hook along with the operation it is run around.
+type resourceHook struct {
+	op display.StepOp
+	fn ResourceHookFunction
+}
+
+// hooksFor pairs each of the given hooks with the operation they are run around.
+func hooksFor(op display.StepOp, fns ...[]ResourceHookFunction) []resourceHook {
+	var hooks []resourceHook
+	for _, fns := range fns {
+		for _, fn := range fns {
+			hooks = append(hooks, resourceHook{op: op, fn: fn})
+		}
+	}
+	return hooks
+}
+
+// forStep returns the hooks to run before and after the given step.
+//
+// The steps of a replacement run the create and delete hooks, and the replace hooks run around the whole replacement:
+// the before hooks run before its first step and the after hooks run once the replacement resource has been created.
+func (h *ResourceHooks) forStep(step Step) ([]resourceHook, []resourceHook) {
+	switch step := step.(type) {
+	case *CreateStep:
+		before, after := hooksFor(OpCreate, h.BeforeCreate), hooksFor(OpCreate, h.AfterCreate)
+		if !step.replacing {
+			return before, after
+		}
+		after = append(after, hooksFor(OpReplace, h.AfterReplace)...)
+		if step.pendingDelete {
+			before = append(hooksFor(OpReplace, h.BeforeReplace), before...)
+		}
+		return before, after
+	case *UpdateStep:
+		return hooksFor(OpUpdate, h.BeforeUpdate), hooksFor(OpUpdate, h.AfterUpdate)
+	case *DeleteStep:
+		// Resources pending deletion, such as the old resources of create-before-delete replacements, are deleted
+		// after the program has finished registering resources, and run the hooks of the resource that replaced them.
+		// Resources that were removed from the program have no hooks, as nothing registered any for them.
+		if step.old.External {
+			return nil, nil
+		}
+		before, after := hooksFor(OpDelete, h.BeforeDelete), hooksFor(OpDelete, h.AfterDelete)
+		// The before replace hooks of a create-before-delete replacement already ran before its create.
+		if step.replacing && !step.old.Delete {
+			before = append(hooksFor(OpReplace, h.BeforeReplace), before...)
+		}
+		return before, after
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nbutton23/zxcvbn-go"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+// SecretScanAction is what the engine does with plaintext values that look like secrets.
+type SecretScanAction string
+
+const (
+	// SecretScanWarn warns about plaintext values that look like secrets.
+	SecretScanWarn SecretScanAction = "warn"
+	// SecretScanError fails the deployment if any plaintext value looks like a secret.
+	SecretScanError SecretScanAction = "error"
+	// SecretScanSecret marks plaintext values that look like secrets as secret.
+	SecretScanSecret SecretScanAction = "secret"
+)
+
+const (
+	// maxEntropyCheckLength is the maximum length of a possible secret for entropy checking.
+	maxEntropyCheckLength = 16
+	// DefaultSecretEntropyThreshold is the total entropy threshold a potential secret needs to pass before being
+	// flagged.
+	DefaultSecretEntropyThreshold = 80.0
+	// entropyPerCharThreshold is the per-char entropy threshold a potential secret needs to pass before being
+	// flagged with half of the total entropy threshold.
+	entropyPerCharThreshold = 3.0
+)
+
+// DefaultSecretKeyPattern is the regular expression that the name of a key must match before its value's entropy is
+// checked.
+var DefaultSecretKeyPattern = regexp.MustCompile("(?i)passwd|pass|password|pwd|secret|token")
+
+// A SecretPattern is a regular expression that matches a well-known format of credential, regardless of the name
+// of the key it is the value of.
+type SecretPattern struct {
+	Name   string         // the name of the kind of credential, used in diagnostics.
+	Regexp *regexp.Regexp // the regular expression that matches credentials of this kind.
+}
+
+// DefaultSecretPatterns are the well-known token formats that every secret scanner checks for.
+var DefaultSecretPatterns = []SecretPattern{
+	{"AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"GitLab token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_\-]{20,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9\-]{10,}\b`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{"Stripe secret key", regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+	{"Pulumi access token", regexp.MustCompile(`\bpul-[0-9a-f]{40}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )*PRIVATE KEY( BLOCK)?-----`)},
+}
+
+// SecretScanner flags plaintext values that look like credentials: values that match one of its patterns, or that
+// have a high entropy and belong to a key whose name suggests a secret. This is always going to be a heuristic that
+// suffers from false positives, which is why the engine only warns about them by default.
+type SecretScanner struct {
+	// Action is what the engine does with the plaintext values that look like secrets.
+	Action SecretScanAction
+	// EntropyThreshold is the total entropy, in bits, that the first characters of a value need to have to look like
+	// a secret.
+	EntropyThreshold float64
+	// KeyPattern matches the names of the keys whose values' entropy is checked.
+	KeyPattern *regexp.Regexp
+	// Patterns match well-known formats of credential.
+	Patterns []SecretPattern
+}
+
+// NewSecretScanner returns a secret scanner with the given action, which checks for the given patterns as well as
+// DefaultSecretPatterns. An action of "" means SecretScanWarn, an entropy threshold of 0 means
+// DefaultSecretEntropyThreshold, and a key pattern of "" means DefaultSecretKeyPattern.
+func NewSecretScanner(
+	action string, entropyThreshold float64, keyPattern string, patterns []string,
+) (*SecretScanner, error) {
+	s := &SecretScanner{
+		Action:           SecretScanAction(action),
+		EntropyThreshold: entropyThreshold,
+		KeyPattern:       DefaultSecretKeyPattern,
+		Patterns:         DefaultSecretPatterns,
+	}
+
+	switch s.Action {
+	case "":
+		s.Action = SecretScanWarn
+	case SecretScanWarn, SecretScanError, SecretScanSecret:
+	default:
+		return nil, fmt.Errorf("unknown action %q; must be one of %q, %q or %q",
+			action, SecretScanWarn, SecretScanError, SecretScanSecret)
+	}
+
+	if s.EntropyThreshold < 0 {
+		return nil, fmt.Errorf("entropy threshold %v must not be negative", entropyThreshold)
+	} else if s.EntropyThreshold == 0 {
+		s.EntropyThreshold = DefaultSecretEntropyThreshold
+	}
+
+	if keyPattern != "" {
+		re, err := regexp.Compile(keyPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key pattern: %w", err)
+		}
+		s.KeyPattern = re
+	}
+
+	if len(patterns) > 0 {
+		s.Patterns = make([]SecretPattern, len(DefaultSecretPatterns), len(DefaultSecretPatterns)+len(patterns))
+		copy(s.Patterns, DefaultSecretPatterns)
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern: %w", err)
+			}
+			s.Patterns = append(s.Patterns, SecretPattern{Name: "value matching " + pattern, Regexp: re})
+		}
+	}
+
+	return s, nil
+}
+
+// Check returns a description of the kind of secret that the value of the named key looks like, or "" if it doesn't
+// look like one.
+func (s *SecretScanner) Check(key, value string) string {
+	for _, pattern := range s.Patterns {
+		if pattern.Regexp.MatchString(value) {
+			return pattern.Name
+		}
+	}
+
+	if value == "" || !s.KeyPattern.MatchString(key) {
+		return ""
+	}
+	if len(value) > maxEntropyCheckLength {
+		value = value[:maxEntropyCheckLength]
+	}
+
+	// Compute the strength use the resulting entropy to flag whether this looks like a secret.
+	info := zxcvbn.PasswordStrength(value, nil)
+	entropyPerChar := info.Entropy / float64(len(value))
+	if info.Entropy >= s.EntropyThreshold ||
+		(info.Entropy >= (s.EntropyThreshold/2) && entropyPerChar >= entropyPerCharThreshold) {
+		return "high-entropy value"
+	}
+	return ""
+}
+
+// A SecretFinding is a plaintext value that looks like a secret.
+type SecretFinding struct {
+	Path resource.PropertyPath // the path of the value.
+	Kind string                // the kind of secret the value looks like.
+}
+
+// Scan checks the plaintext string values in a property map, and returns the ones that look like secrets. If the
+// scanner's action is SecretScanSecret, it also returns a copy of the property map with those values marked secret;
+// otherwise it returns the property map unchanged.
+func (s *SecretScanner) Scan(props resource.PropertyMap) (resource.PropertyMap, []SecretFinding) {
+	var findings []SecretFinding
+	var marked resource.PropertyMap
+	for _, k := range props.StableKeys() {
+		if v, changed := s.scanValue(string(k), resource.PropertyPath{string(k)}, props[k], &findings); changed {
+			if marked == nil {
+				marked = props.Copy()
+			}
+			marked[k] = v
+		}
+	}
+	if marked == nil {
+		return props, findings
+	}
+	return marked, findings
+}
+
+// scanValue checks the plaintext string values in a property value that belongs to the named key, appending the
+// ones that look like secrets to findings. It returns the value with those marked secret, and whether it changed.
+func (s *SecretScanner) scanValue(
+	key string, path resource.PropertyPath, v resource.PropertyValue, findings *[]SecretFinding,
+) (resource.PropertyValue, bool) {
+	switch {
+	case v.IsString():
+		kind := s.Check(key, v.StringValue())
+		if kind == "" {
+			return v, false
+		}
+		*findings = append(*findings, SecretFinding{Path: path, Kind: kind})
+		if s.Action != SecretScanSecret {
+			return v, false
+		}
+		return resource.MakeSecret(v), true
+	case v.IsArray():
+		var arr []resource.PropertyValue
+		for i, e := range v.ArrayValue() {
+			if e, changed := s.scanValue(key, appendPath(path, i), e, findings); changed {
+				if arr == nil {
+					arr = append([]resource.PropertyValue{}, v.ArrayValue()...)
+				}
+				arr[i] = e
+			}
+		}
+		if arr == nil {
+			return v, false
+		}
+		return resource.NewArrayProperty(arr), true
+	case v.IsObject():
+		var obj resource.PropertyMap
+		for _, k := range v.ObjectValue().StableKeys() {
+			e, changed := s.scanValue(string(k), appendPath(path, string(k)), v.ObjectValue()[k], findings)
+			if changed {
+				if obj == nil {
+					obj = v.ObjectValue().Copy()
+				}
+				obj[k] = e
+			}
+		}
+		if obj == nil {
+			return v, false
+		}
+		return resource.NewObjectProperty(obj), true
+	case v.IsOutput() && v.OutputValue().Known && !v.OutputValue().Secret:
+		element, changed := s.scanValue(key, path, v.OutputValue().Element, findings)
+		if !changed {
+			return v, false
+		}
+		output := v.OutputValue()
+		output.Element = element
+		return resource.NewOutputProperty(output), true
+	default:
+		// Secrets are already secret, and computed values aren't known yet.
+		return v, false
+	}
+}
+
+// appendPath returns a new property path made of the given path followed by the given element.
+func appendPath(path resource.PropertyPath, element interface{}) resource.PropertyPath {
+	return append(append(resource.PropertyPath{}, path...), element)
+}
+
+// formatFindingPath formats the path of a finding. Its first element is the key of a property or config value, which
+// is written as it is rather than quoted, even if it contains characters such as the colons of config keys.
+func formatFindingPath(path resource.PropertyPath) string {
+	key := path[0].(string)
+	if len(path) == 1 {
+		return key
+	}
+	rest := resource.PropertyPath{"_"}
+	rest = append(rest, path[1:]...)
+	return key + strings.TrimPrefix(rest.String(), "_")
+}
+
+// Report reports the given findings about the named kind of value, such as "input", as diagnostics on the given
+// URN, and returns a bail error if the scanner's action is SecretScanError.
+func (s *SecretScanner) Report(sink diag.Sink, urn resource.URN, what string, findings []SecretFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	paths := make([]string, len(findings))
+	for i, finding := range findings {
+		paths[i] = formatFindingPath(finding.Path)
+		switch s.Action {
+		case SecretScanSecret:
+			sink.Infof(diag.Message(urn, "%s %q looks like a secret (%s) and has been marked secret"),
+				what, paths[i], finding.Kind)
+		case SecretScanError:
+			sink.Errorf(diag.Message(urn, "%s %q looks like a secret (%s) but is not secret"),
+				what, paths[i], finding.Kind)
+		default:
+			sink.Warningf(diag.Message(urn, "%s %q looks like a secret (%s) but is not secret; "+
+				"mark it secret, or configure options.secretScanning in Pulumi.yaml"), what, paths[i], finding.Kind)
+		}
+	}
+
+	if s.Action == SecretScanError {
+		return result.BailErrorf("plaintext %ss look like secrets: %s", what, strings.Join(paths, ", "))
+	}
+	return nil
+}
@@ -90,6 +90,8 @@ type RegisterResourceEvent interface {
 	SourceEvent
 	// Goal returns the goal state for the resource object that was allocated by the program.
 	Goal() *resource.Goal
+	// Hooks returns the hooks to run around the operations on the resource, if any.
+	Hooks() *ResourceHooks
 	// Done indicates that we are done with this step.  It must be called to perform cleanup associated with the step.
 	Done(result *RegisterResult)
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
 	opentracing "github.com/opentracing/opentracing-go"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -130,6 +131,26 @@ func (src *evalSource) Iterate(
 		return nil, fmt.Errorf("failed to convert config to map: %w", err)
 	}
 
+	// Check the plaintext configuration for values that look like secrets, and mark them secret if asked to.
+	if scanner := opts.SecretScanner; scanner != nil {
+		var findings []SecretFinding
+		configMap, findings = scanner.Scan(configMap)
+		if err := scanner.Report(src.plugctx.Diag, "", "config value", findings); err != nil {
+			return nil, err
+		}
+		if scanner.Action == SecretScanSecret && len(findings) > 0 {
+			marked := map[string]bool{}
+			for _, finding := range findings {
+				marked[finding.Path[0].(string)] = true
+			}
+			for k := range src.runinfo.Target.Config {
+				if marked[k.String()] {
+					configSecretKeys = append(configSecretKeys, k)
+				}
+			}
+		}
+	}
+
 	// First, fire up a resource monitor that will watch for and record resource creation.
 	regChan := make(chan *registerResourceEvent)
 	regOutChan := make(chan *registerResourceOutputsEvent)
@@ -147,7 +168,10 @@ func (src *evalSource) Iterate(
 		regChan:     regChan,
 		regOutChan:  regOutChan,
 		regReadChan: regReadChan,
-		finChan:     make(chan error),
+		// The channel is buffered so that the program's result can be sent once it exits, even if the iteration
+		// already ended when the program signaled that it was done.
+		finChan:     make(chan error, 1),
+		programDone: mon.programDone,
 	}
 
 	// Now invoke Run in a goroutine.  All subsequent resource creation events will come in over the gRPC channel,
@@ -165,6 +189,7 @@ type evalSourceIterator struct {
 	regOutChan  chan *registerResourceOutputsEvent // the channel that contains resource completions.
 	regReadChan chan *readResourceEvent            // the channel that contains read resource requests.
 	finChan     chan error                         // the channel that communicates completion.
+	programDone <-chan struct{}                    // closed when the program signals that it is done.
 	done        bool                               // set to true when the evaluation is done.
 }
 
@@ -200,6 +225,12 @@ func (iter *evalSourceIterator) Next() (SourceEvent, error) {
 		contract.Assertf(read != nil, "received a nil readResourceEvent")
 		logging.V(5).Infoln("EvalSourceIterator produced a read")
 		return read, nil
+	case <-iter.programDone:
+		// The program has finished registering resources, but is waiting for the deployment to finish so that the
+		// engine can still call its callbacks. It is released when the iterator is closed.
+		logging.V(5).Infof("EvalSourceIterator ended with a shutdown signal from the program.")
+		iter.done = true
+		return nil, nil
 	case err := <-iter.finChan:
 		// If we are finished, we can safely exit.  The contract with the language provider is that this implies
 		// that the language runtime has exited and so calling Close on the plugin is fine.
@@ -576,6 +607,8 @@ type resmon struct {
 	regReadChan               chan *readResourceEvent            // the channel to send resource reads to.
 	cancel                    chan bool                          // a channel that can cancel the server.
 	done                      <-chan error                       // a channel that resolves when the server completes.
+	programDone               chan struct{}                      // closed when the program signals that it is done.
+	programDoneOnce           sync.Once                          // guards the closing of programDone.
 	disableResourceReferences bool                               // true if resource references are disabled.
 	disableOutputValues       bool                               // true if output values are disabled.
 
@@ -625,6 +658,7 @@ func newResourceMonitor(src *evalSource, provs ProviderSource, regChan chan *reg
 		regOutChan:                regOutChan,
 		regReadChan:               regReadChan,
 		cancel:                    cancel,
+		programDone:               make(chan struct{}),
 		disableResourceReferences: opts.DisableResourceReferences,
 		disableOutputValues:       opts.DisableOutputValues,
 		callbacks:                 map[string]*CallbacksClient{},
@@ -1232,6 +1266,118 @@ func (rm *resmon) wrapTransformCallback(cb *pulumirpc.Callback) (TransformFuncti
 	}, nil
 }
 
+// wrapResourceHooks wraps the hook callbacks registered for a resource, returning nil if it has none.
+func (rm *resmon) wrapResourceHooks(hooks *pulumirpc.RegisterResourceRequest_ResourceHooks) (*ResourceHooks, error) {
+	if hooks == nil {
+		return nil, nil
+	}
+
+	var err error
+	wrap := func(cbs []*pulumirpc.Callback) []ResourceHookFunction {
+		if err != nil {
+			return nil
+		}
+		var fns []ResourceHookFunction
+		fns, err = slice.MapError(cbs, rm.wrapResourceHookCallback)
+		return fns
+	}
+	result := &ResourceHooks{
+		BeforeCreate:  wrap(hooks.GetBeforeCreate()),
+		AfterCreate:   wrap(hooks.GetAfterCreate()),
+		BeforeUpdate:  wrap(hooks.GetBeforeUpdate()),
+		AfterUpdate:   wrap(hooks.GetAfterUpdate()),
+		BeforeDelete:  wrap(hooks.GetBeforeDelete()),
+		AfterDelete:   wrap(hooks.GetAfterDelete()),
+		BeforeReplace: wrap(hooks.GetBeforeReplace()),
+		AfterReplace:  wrap(hooks.GetAfterReplace()),
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (rm *resmon) wrapResourceHookCallback(cb *pulumirpc.Callback) (ResourceHookFunction, error) {
+	client, err := rm.GetCallbacksClient(cb.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	token := cb.Token
+	return func(ctx context.Context, args ResourceHookArgs) error {
+		logging.V(5).Infof("ResourceHook: urn=%v op=%v after=%v", args.URN, args.Operation, args.After)
+
+		mopts := plugin.MarshalOptions{
+			KeepUnknowns:     true,
+			KeepSecrets:      true,
+			KeepResources:    true,
+			WorkingDirectory: rm.workingDirectory,
+		}
+		marshal := func(props resource.PropertyMap) (*structpb.Struct, error) {
+			if props == nil {
+				return nil, nil
+			}
+			return plugin.MarshalProperties(props, mopts)
+		}
+
+		var err error
+		hookRequest := &pulumirpc.ResourceHookRequest{
+			Urn:       string(args.URN),
+			Id:        string(args.ID),
+			Operation: string(args.Operation),
+			After:     args.After,
+		}
+		if hookRequest.OldInputs, err = marshal(args.OldInputs); err != nil {
+			return err
+		}
+		if hookRequest.OldOutputs, err = marshal(args.OldOutputs); err != nil {
+			return err
+		}
+		if hookRequest.NewInputs, err = marshal(args.NewInputs); err != nil {
+			return err
+		}
+		if hookRequest.NewOutputs, err = marshal(args.NewOutputs); err != nil {
+			return err
+		}
+
+		request, err := proto.Marshal(hookRequest)
+		if err != nil {
+			return fmt.Errorf("marshaling request: %w", err)
+		}
+
+		resp, err := client.Invoke(ctx, &pulumirpc.CallbackInvokeRequest{
+			Token:   token,
+			Request: request,
+		})
+		if err != nil {
+			logging.V(5).Infof("ResourceHook callback error: %v", err)
+			return err
+		}
+
+		var response pulumirpc.ResourceHookResponse
+		err = proto.Unmarshal(resp.Response, &response)
+		if err != nil {
+			return fmt.Errorf("unmarshaling response: %w", err)
+		}
+		if response.Error != "" {
+			return errors.New(response.Error)
+		}
+		return nil
+	}, nil
+}
+
+// SignalAndWaitForShutdown ends the evaluation of the program, and waits for the monitor to be canceled once the
+// deployment has finished. Programs that registered callbacks call this so that the callbacks remain available while
+// the engine performs the operations that follow the program, such as deleting resources.
+func (rm *resmon) SignalAndWaitForShutdown(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	rm.programDoneOnce.Do(func() { close(rm.programDone) })
+	select {
+	case <-rm.cancel:
+	case <-ctx.Done():
+	}
+	return &emptypb.Empty{}, nil
+}
+
 func (rm *resmon) RegisterStackTransform(ctx context.Context, cb *pulumirpc.Callback) (*emptypb.Empty, error) {
 	rm.stackTransformsLock.Lock()
 	defer rm.stackTransformsLock.Unlock()
@@ -1250,12 +1396,15 @@ func (rm *resmon) RegisterStackTransform(ctx context.Context, cb *pulumirpc.Call
 }
 
 // inheritFromParent returns a new goal that inherits from the given parent goal.
-// Currently only inherits DeletedWith from parent.
+// Currently only inherits DeletedWith and RetryPolicy from parent.
 func inheritFromParent(child resource.Goal, parent resource.Goal) *resource.Goal {
 	goal := child
 	if goal.DeletedWith == "" {
 		goal.DeletedWith = parent.DeletedWith
 	}
+	if goal.RetryPolicy == nil {
+		goal.RetryPolicy = parent.RetryPolicy
+	}
 	return &goal
 }
 
@@ -1764,6 +1913,18 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 		return nil, rpcerror.New(codes.InvalidArgument, fmt.Sprintf("invalid DeletedWith URN: %s", err))
 	}
 	customTimeouts := opts.CustomTimeouts
+	var retryPolicy *resource.RetryPolicy
+	if rp := req.GetRetryPolicy(); rp != nil {
+		retryPolicy, err = resource.ParseRetryPolicy(
+			int(rp.GetMaxAttempts()), rp.GetBackoff(), rp.GetMaxBackoff(), rp.GetRetryOn())
+		if err != nil {
+			return nil, rpcerror.New(codes.InvalidArgument, fmt.Sprintf("invalid RetryPolicy: %s", err))
+		}
+	}
+	hooks, err := rm.wrapResourceHooks(req.GetHooks())
+	if err != nil {
+		return nil, err
+	}
 
 	additionalSecretOutputs := opts.GetAdditionalSecretOutputs()
 
@@ -1885,6 +2046,7 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 			additionalSecretKeys, parsedAliases, id, &timeouts, replaceOnChanges, retainOnDelete, deletedWith,
 			sourcePosition,
 		)
+		goal.RetryPolicy = retryPolicy
 
 		if goal.Parent != "" {
 			rm.resGoalsLock.Lock()
@@ -1896,8 +2058,9 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 		}
 		// Send the goal state to the engine.
 		step := &registerResourceEvent{
-			goal: goal,
-			done: make(chan *RegisterResult),
+			goal:  goal,
+			hooks: hooks,
+			done:  make(chan *RegisterResult),
 		}
 
 		select {
@@ -2111,8 +2274,9 @@ func (rm *resmon) RegisterResourceOutputs(ctx context.Context,
 }
 
 type registerResourceEvent struct {
-	goal *resource.Goal       // the resource goal state produced by the iterator.
-	done chan *RegisterResult // the channel to communicate with after the resource state is available.
+	goal  *resource.Goal       // the resource goal state produced by the iterator.
+	hooks *ResourceHooks       // the optional hooks to run around operations on the resource.
+	done  chan *RegisterResult // the channel to communicate with after the resource state is available.
 }
 
 var _ RegisterResourceEvent = (*registerResourceEvent)(nil)
@@ -2123,6 +2287,10 @@ func (g *registerResourceEvent) Goal() *resource.Goal {
 	return g.goal
 }
 
+func (g *registerResourceEvent) Hooks() *ResourceHooks {
+	return g.hooks
+}
+
 func (g *registerResourceEvent) Done(result *RegisterResult) {
 	// Communicate the resulting state back to the RPC thread, which is parked awaiting our reply.
 	g.done <- result
@@ -55,6 +55,10 @@ func (g *testRegEvent) Goal() *resource.Goal {
 	return g.goal
 }
 
+func (g *testRegEvent) Hooks() *ResourceHooks {
+	return nil
+}
+
 func (g *testRegEvent) Done(result *RegisterResult) {
 	contract.Assertf(g.result == nil, "Attempt to invoke testRegEvent.Done more than once")
 	g.result = result
@@ -15,6 +15,7 @@
 package deploy
 
 import (
+	cryptorand "crypto/rand"
 	"errors"
 	"fmt"
 	"strings"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/pkg/v3/resource/graph"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -848,6 +850,11 @@ type RefreshStep struct {
 	new        *resource.State // the new resource state, to be used to query the provider
 	done       chan<- bool     // the channel to use to signal completion, if any
 	provider   plugin.Provider // the optional provider to use.
+	// the inputs declared for this resource by the program, if the program was evaluated and they are known.
+	programInputs resource.PropertyMap
+	undeclared    bool         // true if the program was evaluated and no longer declares this resource.
+	drifted       bool         // true if the inputs read differ from the inputs that the program declares.
+	plan          *RefreshPlan // the planned result of this refresh, if the refresh is constrained by a plan.
 }
 
 // NewRefreshStep creates a new Refresh step.
@@ -873,6 +880,14 @@ func (s *RefreshStep) New() *resource.State    { return s.new }
 func (s *RefreshStep) Res() *resource.State    { return s.old }
 func (s *RefreshStep) Logical() bool           { return false }
 
+// Undeclared returns true if the program was evaluated alongside the refresh and no longer declares this resource,
+// which makes the resource a candidate for deletion by the next update.
+func (s *RefreshStep) Undeclared() bool { return s.undeclared }
+
+// Drifted returns true if the program was evaluated alongside the refresh and the inputs that the provider read for
+// this resource differ from the inputs that the program now declares for it.
+func (s *RefreshStep) Drifted() bool { return s.drifted }
+
 // ResultOp returns the operation that corresponds to the change to this resource after reading its current state, if
 // any.
 func (s *RefreshStep) ResultOp() display.StepOp {
@@ -904,8 +919,26 @@ func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 		return resource.StatusOK, nil, err
 	}
 
+	// If the program was evaluated, read the resource with the inputs that it now declares, checked against the
+	// deployed inputs as they would be by an update.
+	readInputs := s.old.Inputs
+	if s.programInputs != nil {
+		randomSeed := make([]byte, 32)
+		if _, err := cryptorand.Read(randomSeed); err != nil {
+			return resource.StatusOK, nil, err
+		}
+		checked, failures, err := prov.Check(s.old.URN, s.old.Inputs, s.programInputs, false, randomSeed)
+		if err != nil {
+			return resource.StatusOK, nil, err
+		}
+		if issueCheckErrors(s.deployment, s.old, s.old.URN, failures) {
+			return resource.StatusOK, nil, fmt.Errorf("the program's inputs for %v are invalid", s.old.URN)
+		}
+		readInputs = checked
+	}
+
 	var initErrors []string
-	refreshed, rst, err := prov.Read(s.old.URN, resourceID, s.old.Inputs, s.old.Outputs)
+	refreshed, rst, err := prov.Read(s.old.URN, resourceID, readInputs, s.old.Outputs)
 	if err != nil {
 		if rst != resource.StatusPartialFailure {
 			return rst, nil, err
@@ -960,10 +993,24 @@ func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 			now := time.Now().UTC()
 			s.new.Modified = &now
 		}
+
+		// If the program was evaluated, compare the inputs that were read with the ones the program now declares. The
+		// program's inputs were never deployed, so unless the provider reports them as the resource's inputs, they
+		// are not recorded.
+		s.drifted = s.programInputs != nil && !readInputs.DeepEquals(inputs)
 	} else {
 		s.new = nil
 	}
 
+	// If the refresh is constrained by a plan, check that it found what the plan expected. If it didn't, the old state
+	// is kept so that nothing the plan didn't allow is recorded.
+	if err == nil && s.plan != nil {
+		if planErr := s.plan.checkRefresh(s.old, s.new, s.ResultOp()); planErr != nil {
+			s.new = s.old
+			return resource.StatusOK, nil, fmt.Errorf("resource violates plan: %w", planErr)
+		}
+	}
+
 	return rst, nil, err
 }
 
@@ -1070,6 +1117,9 @@ func (s *ImportStep) Logical() bool                                { return !s.r
 func (s *ImportStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *ImportStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
 
+// Original returns the state of the resource that this import replaces, if any.
+func (s *ImportStep) Original() *resource.State { return s.original }
+
 func (s *ImportStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
 	complete := func() {
 		s.reg.Done(&RegisterResult{State: s.new})
@@ -1452,6 +1502,32 @@ func ConstrainedTo(op display.StepOp, constraint display.StepOp) bool {
 	return false
 }
 
+// IsTimed returns true if the operation does work of its own, and so has a timing worth recording.
+func IsTimed(op display.StepOp) bool {
+	switch op {
+	case OpSame, OpReplace, OpRemovePendingReplace, OpOutputChange:
+		return false
+	}
+	return true
+}
+
+// RecordTiming records the timing of an operation on the given resource in a deployment's timings. Only operations on
+// custom resources are recorded, as component resources do no work of their own. The deletion of a replaced resource
+// is only recorded if the creation of its replacement has not been, as it is the creation that the resource's
+// dependents wait on. Returns true if the timing was recorded.
+func RecordTiming(timings map[resource.URN]graph.OperationTiming, op display.StepOp, urn resource.URN, custom bool,
+	timing graph.OperationTiming,
+) bool {
+	if !custom || !IsTimed(op) {
+		return false
+	}
+	if _, has := timings[urn]; has && (op == OpDeleteReplaced || op == OpDiscardReplaced) {
+		return false
+	}
+	timings[urn] = timing
+	return true
+}
+
 // getProvider fetches the provider for the given step.
 func getProvider(s Step, override plugin.Provider) (plugin.Provider, error) {
 	if override != nil {
@@ -19,13 +19,18 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/v3/util/gsync"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/promise"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil/rpcerror"
 )
 
 const (
@@ -84,6 +89,93 @@ func (c completionToken) Wait(ctx context.Context) {
 type incomingChain struct {
 	Chain          chain     // The chain we intend to execute
 	CompletionChan chan bool // A completion channel to be closed when the chain has completed execution
+	LimitKey       string    // The key of the concurrency limit that the chain counts against, if any
+}
+
+// chainLimiter limits the number of chains that may execute at once for each resource package or type, as set by
+// Options.ParallelFor. Chains over their limit are queued, in the order they were submitted, until a chain with the
+// same key completes. These limits apply on top of the step executor's overall degree of parallelism.
+// A nil chainLimiter has no limits.
+type chainLimiter struct {
+	limits map[string]int
+
+	lock    sync.Mutex
+	running map[string]int
+	queued  map[string][]incomingChain
+	pending sync.WaitGroup // tracks chains that are queued or being handed to a worker
+}
+
+func newChainLimiter(limits map[string]int) *chainLimiter {
+	return &chainLimiter{
+		limits:  limits,
+		running: make(map[string]int),
+		queued:  make(map[string][]incomingChain),
+	}
+}
+
+// key returns the key of the limit that applies to the given chain, if any. A limit for the chain's resource type
+// takes precedence over a limit for its package. Provider resources count against the package that they provide.
+func (l *chainLimiter) key(c chain) (string, bool) {
+	if l == nil || len(l.limits) == 0 || len(c) == 0 {
+		return "", false
+	}
+
+	typ := c[0].Type()
+	if _, has := l.limits[string(typ)]; has {
+		return string(typ), true
+	}
+	var pkg tokens.Package
+	if providers.IsProviderType(typ) {
+		pkg = providers.GetProviderPackage(typ)
+	} else {
+		pkg = typ.Package()
+	}
+	if _, has := l.limits[string(pkg)]; has {
+		return string(pkg), true
+	}
+	return "", false
+}
+
+// acquire takes a slot for the chain's key and returns true if one is free. Otherwise the chain is queued, to be
+// returned by the release call that frees a slot for it.
+func (l *chainLimiter) acquire(request incomingChain) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.running[request.LimitKey] < l.limits[request.LimitKey] {
+		l.running[request.LimitKey]++
+		return true
+	}
+	l.pending.Add(1)
+	l.queued[request.LimitKey] = append(l.queued[request.LimitKey], request)
+	return false
+}
+
+// release frees the slot held by a completed chain with the given key. If a chain with the same key is queued, the
+// slot is handed straight to it and the chain is returned; the caller must then submit it and call dispatched.
+func (l *chainLimiter) release(key string) (incomingChain, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if queue := l.queued[key]; len(queue) > 0 {
+		l.queued[key] = queue[1:]
+		return queue[0], true
+	}
+	l.running[key]--
+	return incomingChain{}, false
+}
+
+// dispatched records that a chain returned by release has been handed to a worker.
+func (l *chainLimiter) dispatched() {
+	l.pending.Done()
+}
+
+// wait blocks until every queued chain has been handed to a worker.
+func (l *chainLimiter) wait() {
+	if l == nil {
+		return
+	}
+	l.pending.Wait()
 }
 
 // stepExecutor is the component of the engine responsible for taking steps and executing
@@ -110,6 +202,7 @@ type stepExecutor struct {
 
 	workers        sync.WaitGroup     // WaitGroup tracking the worker goroutines that are owned by this step executor.
 	incomingChains chan incomingChain // Incoming chains that we are to execute
+	limiter        *chainLimiter      // Per-package and per-type limits on the chains that can execute at once
 
 	ctx    context.Context    // cancellation context for the current deployment.
 	cancel context.CancelFunc // CancelFunc that cancels the above context.
@@ -137,17 +230,48 @@ type stepExecutor struct {
 // Execute submits a Chain for asynchronous execution. The execution of the chain will begin as soon as there
 // is a worker available to execute it.
 func (se *stepExecutor) ExecuteSerial(chain chain) completionToken {
+	completion := make(chan bool)
+	request := incomingChain{Chain: chain, CompletionChan: completion}
+
+	// If the chain's package or type has its own limit that is already reached, queue the chain rather than handing
+	// it to a worker. It will be submitted when another chain with the same limit completes.
+	if key, limited := se.limiter.key(chain); limited {
+		request.LimitKey = key
+		if !se.limiter.acquire(request) {
+			return completionToken{channel: completion}
+		}
+	}
+
+	se.submit(request)
+	return completionToken{channel: completion}
+}
+
+// submit hands a chain to a worker.
+func (se *stepExecutor) submit(request incomingChain) {
 	// The select here is to avoid blocking on a send to se.incomingChains if a cancellation is pending.
 	// If one is pending, we should exit early - we will shortly be tearing down the engine and exiting.
-
-	completion := make(chan bool)
 	select {
-	case se.incomingChains <- incomingChain{Chain: chain, CompletionChan: completion}:
+	case se.incomingChains <- request:
 	case <-se.ctx.Done():
-		close(completion)
+		se.completeChain(request)
 	}
+}
 
-	return completionToken{channel: completion}
+// completeChain signals the completion of a chain and releases its slot in any limit that it counts against,
+// submitting the next chain that was queued behind it.
+func (se *stepExecutor) completeChain(request incomingChain) {
+	close(request.CompletionChan)
+	if request.LimitKey == "" {
+		return
+	}
+	if next, has := se.limiter.release(request.LimitKey); has {
+		// This is called from workers, so the next chain must be submitted asynchronously: a worker can't wait for
+		// itself to become free.
+		go func() {
+			defer se.limiter.dispatched()
+			se.submit(next)
+		}()
+	}
 }
 
 // Locks the step executor from executing any more steps. This is used to synchronize with the step executor.
@@ -245,6 +369,15 @@ func (se *stepExecutor) executeRegisterResourceOutputs(
 		}
 	}
 
+	// Check the plaintext outputs for values that look like secrets, and mark them secret if asked to.
+	if scanner := se.opts.SecretScanner; scanner != nil {
+		var findings []SecretFinding
+		outs, findings = scanner.Scan(outs)
+		if err := scanner.Report(se.deployment.Diag(), urn, "output", findings); err != nil {
+			return err
+		}
+	}
+
 	// If a plan is present check that these outputs match what we recorded before
 	if se.deployment.plan != nil {
 		resourcePlan, ok := se.deployment.plan.ResourcePlans[urn]
@@ -305,6 +438,8 @@ func (se *stepExecutor) Errored() error {
 // SignalCompletion signals to the stepExecutor that there are no more chains left to execute. All worker
 // threads will terminate as soon as they retire all of the work they are currently executing.
 func (se *stepExecutor) SignalCompletion() {
+	// Chains that are queued behind a limit must be handed to a worker before the channel is closed.
+	se.limiter.wait()
 	close(se.incomingChains)
 }
 
@@ -400,8 +535,15 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 		}
 	}
 
-	se.log(workerID, "applying step %v on %v (preview %v)", step.Op(), step.URN(), se.preview)
-	status, stepComplete, err := step.Apply(se.preview)
+	// Run the resource's before hooks, if it has any. A failed hook fails the step without applying it.
+	before, after := se.resourceHooks(step)
+	var status resource.Status
+	var stepComplete StepCompleteFunc
+	err := se.runResourceHooks(workerID, step, false, before)
+	if err == nil {
+		se.log(workerID, "applying step %v on %v (preview %v)", step.Op(), step.URN(), se.preview)
+		status, stepComplete, err = se.applyStep(workerID, step)
+	}
 
 	if err == nil {
 		// If we have a state object, and this is a create or update, remember it, as we may need to update it later.
@@ -419,8 +561,17 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	// state may have already been added to the snapshot manager (in the case of create before delete replacements
 	// because the Create step is run before the Replace step) and mutating the state again causes dataraces (see
 	// https://github.com/pulumi/pulumi/issues/14994).
+	var scanErr error
 	if step.New() != nil && step.Op() != OpReplace {
 		newState := step.New()
+
+		// Check the plaintext outputs that the provider returned for values that look like secrets, and mark them
+		// secret if asked to. If the scan fails the step, it does so once the step's results have been saved.
+		if scanner := se.opts.SecretScanner; scanner != nil && err == nil && newState.Custom &&
+			returnsProviderOutputs(step.Op()) {
+			newState.Outputs, scanErr = se.scanProviderOutputs(scanner, newState)
+		}
+
 		for _, k := range newState.AdditionalSecretOutputs {
 			if k == "id" {
 				se.deployment.Diag().Warningf(&diag.Diag{
@@ -484,6 +635,19 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 			return fmt.Errorf("post-step event returned an error: %w", postErr)
 		}
 	}
+	if err == nil {
+		err = scanErr
+	}
+
+	// Run the resource's after hooks now that the results of the step have been saved. A failed after hook fails the
+	// step, but the step has already happened and been recorded by then, so it is not undone. OnResourceStepPost has
+	// already run, so the failure is reported here.
+	if err == nil {
+		if err = se.runResourceHooks(workerID, step, true, after); err != nil {
+			se.log(workerID, "step %v on %v failed after hook: %v", step.Op(), step.URN(), err)
+			se.deployment.Diag().Errorf(diag.RawMessage(step.URN(), err.Error()))
+		}
+	}
 
 	// Calling stepComplete allows steps that depend on this step to continue. OnResourceStepPost saved the results
 	// of the step in the snapshot, so we are ready to go.
@@ -500,6 +664,175 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	return nil
 }
 
+// returnsProviderOutputs returns true if steps with the given operation save outputs that a provider has just
+// returned, rather than outputs that are already in the state.
+func returnsProviderOutputs(op display.StepOp) bool {
+	switch op {
+	case OpCreate, OpCreateReplacement, OpUpdate, OpRead, OpReadReplacement,
+		OpImport, OpImportReplacement, OpRefresh:
+		return true
+	default:
+		return false
+	}
+}
+
+// scanProviderOutputs checks the plaintext outputs of the given new state for values that look like secrets, and
+// returns the outputs with those marked secret if the scanner is asked to. Outputs that are the same as the input of
+// the same name have already been checked as inputs, so they are neither checked nor reported again.
+func (se *stepExecutor) scanProviderOutputs(
+	scanner *SecretScanner, newState *resource.State,
+) (resource.PropertyMap, error) {
+	scanned := make(resource.PropertyMap)
+	for k, v := range newState.Outputs {
+		if in, has := newState.Inputs[k]; !has || !in.DeepEquals(v) {
+			scanned[k] = v
+		}
+	}
+
+	scanned, findings := scanner.Scan(scanned)
+	if len(findings) == 0 {
+		return newState.Outputs, nil
+	}
+
+	outputs := newState.Outputs.Copy()
+	for k, v := range scanned {
+		outputs[k] = v
+	}
+	return outputs, scanner.Report(se.deployment.Diag(), newState.URN, "output", findings)
+}
+
+// applyStep applies the given step. If the step fails with an error that the resource's retry policy allows to be
+// retried, it is applied again after the policy's backoff, until it succeeds or runs out of attempts.
+func (se *stepExecutor) applyStep(workerID int, step Step) (resource.Status, StepCompleteFunc, error) {
+	policy := se.retryPolicy(step)
+	for attempt := 1; ; attempt++ {
+		status, stepComplete, err := step.Apply(se.preview)
+		if err == nil || policy == nil || attempt >= policy.MaxAttempts || !isRetryableError(policy, step, status, err) {
+			return status, stepComplete, err
+		}
+
+		delay := policy.Delay(attempt + 1)
+		se.log(workerID, "step %v on %v failed on attempt %d of %d, retrying in %v: %v",
+			step.Op(), step.URN(), attempt, policy.MaxAttempts, delay, err)
+		if se.opts.Events != nil {
+			se.opts.Events.OnResourceStepRetry(step, attempt, policy.MaxAttempts, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-se.ctx.Done():
+			timer.Stop()
+			return status, stepComplete, err
+		}
+	}
+}
+
+// retryPolicy returns the policy for retrying the given step, or nil if it should not be retried. Only the steps that
+// create, update or delete resources are retried, using the resource's own policy if it has one and the deployment's
+// policy otherwise.
+func (se *stepExecutor) retryPolicy(step Step) *resource.RetryPolicy {
+	if se.preview {
+		return nil
+	}
+
+	var urn resource.URN
+	switch step.Op() {
+	case OpCreate, OpUpdate, OpCreateReplacement:
+		urn = step.URN()
+	case OpDelete, OpDeleteReplaced:
+		urn = se.declaredAncestor(step.Old())
+	default:
+		return nil
+	}
+
+	policy := se.opts.RetryPolicy
+	if goal, has := se.deployment.goals.Load(urn); has && goal.RetryPolicy != nil {
+		policy = goal.RetryPolicy
+	}
+	if policy == nil || policy.MaxAttempts <= 1 {
+		return nil
+	}
+	return policy
+}
+
+// declaredAncestor returns the URN of the given resource if the program still declares it, and otherwise the URN of
+// its nearest ancestor that the program declares, so that a resource that was removed from the program is deleted
+// with the policy that it would have inherited from its parent. It returns the empty URN if there is no such ancestor.
+func (se *stepExecutor) declaredAncestor(old *resource.State) resource.URN {
+	res := old
+	for res != nil {
+		if _, has := se.deployment.goals.Load(res.URN); has {
+			return res.URN
+		}
+		if res.Parent == "" {
+			break
+		}
+		res = se.deployment.olds[res.Parent]
+	}
+	return ""
+}
+
+// resourceHooks returns the hooks to run before and after the given step. Hooks are not run during previews.
+func (se *stepExecutor) resourceHooks(step Step) ([]resourceHook, []resourceHook) {
+	if se.preview {
+		return nil, nil
+	}
+	hooks, has := se.deployment.hooks.Load(step.URN())
+	if !has {
+		return nil, nil
+	}
+	return hooks.forStep(step)
+}
+
+// runResourceHooks runs the given hooks for a step in order, stopping at the first one that fails.
+func (se *stepExecutor) runResourceHooks(workerID int, step Step, after bool, hooks []resourceHook) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	when := "before"
+	if after {
+		when = "after"
+	}
+	args := ResourceHookArgs{URN: step.URN(), After: after}
+	if old := step.Old(); old != nil {
+		args.ID, args.OldInputs, args.OldOutputs = old.ID, old.Inputs, old.Outputs
+	}
+	if new := step.New(); new != nil {
+		args.NewInputs = new.Inputs
+		if after {
+			args.NewOutputs = new.Outputs
+		}
+		if new.ID != "" {
+			args.ID = new.ID
+		}
+	}
+
+	for _, hook := range hooks {
+		se.log(workerID, "running %s %v hook on %v", when, hook.op, step.URN())
+		args.Operation = hook.op
+		if err := hook.fn(se.ctx, args); err != nil {
+			return fmt.Errorf("%s %v hook failed: %w", when, hook.op, err)
+		}
+	}
+	return nil
+}
+
+// isRetryableError returns true if the error from a failed step is one that the given retry policy retries. Errors
+// after which the resource was left partially created or updated are never retried.
+func isRetryableError(policy *resource.RetryPolicy, step Step, status resource.Status, err error) bool {
+	if status == resource.StatusPartialFailure {
+		return false
+	}
+	rpcErr, ok := rpcerror.FromError(err)
+	if !ok || rpcErr == nil {
+		return false
+	}
+	create := step.Op() == OpCreate || step.Op() == OpCreateReplacement
+	return policy.ShouldRetry(rpcErr.Code(), create)
+}
+
 // log is a simple logging helper for the step executor.
 func (se *stepExecutor) log(workerID int, msg string, args ...interface{}) {
 	if logging.V(stepExecutorLogLevel) {
@@ -543,7 +876,7 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 			se.log(workerID, "worker received chain for execution")
 			if !launchAsync {
 				se.executeChain(workerID, request.Chain)
-				close(request.CompletionChan)
+				se.completeChain(request)
 				continue
 			}
 
@@ -555,7 +888,7 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 				defer se.workers.Done()
 				se.log(newWorkerID, "launching oneshot worker")
 				se.executeChain(newWorkerID, request.Chain)
-				close(request.CompletionChan)
+				se.completeChain(request)
 			}()
 
 			oneshotWorkerID++
@@ -576,6 +909,7 @@ func newStepExecutor(ctx context.Context, cancel context.CancelFunc, deployment
 		preview:        preview,
 		ignoreErrors:   ignoreErrors,
 		incomingChains: make(chan incomingChain),
+		limiter:        newChainLimiter(opts.ParallelFor),
 		ctx:            ctx,
 		cancel:         cancel,
 	}
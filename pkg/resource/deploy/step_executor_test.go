@@ -15,13 +15,18 @@
 package deploy
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
 	"github.com/pulumi/pulumi/pkg/v3/util/gsync"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,6 +63,7 @@ func (e *mockRegisterResourceOutputsEvent) Done() {}
 type mockEvents struct {
 	OnResourceStepPreF   func(step Step) (interface{}, error)
 	OnResourceStepPostF  func(ctx interface{}, step Step, status resource.Status, err error) error
+	OnResourceStepRetryF func(step Step, attempt, maxAttempts int, delay time.Duration, err error)
 	OnResourceOutputsF   func(step Step) error
 	OnPolicyViolationF   func(resource.URN, plugin.AnalyzeDiagnostic)
 	OnPolicyRemediationF func(resource.URN, plugin.Remediation, resource.PropertyMap, resource.PropertyMap)
@@ -77,6 +83,14 @@ func (e *mockEvents) OnResourceStepPost(ctx interface{}, step Step, status resou
 	panic("unimplemented")
 }
 
+func (e *mockEvents) OnResourceStepRetry(step Step, attempt, maxAttempts int, delay time.Duration, err error) {
+	if e.OnResourceStepRetryF != nil {
+		e.OnResourceStepRetryF(step, attempt, maxAttempts, delay, err)
+		return
+	}
+	panic("unimplemented")
+}
+
 func (e *mockEvents) OnResourceOutputs(step Step) error {
 	if e.OnResourceOutputsF != nil {
 		return e.OnResourceOutputsF(step)
@@ -209,6 +223,7 @@ func TestStepExecutor(t *testing.T) {
 						Diag: &deploytest.NoopSink{},
 					},
 					goals: &gsync.Map[resource.URN, *resource.Goal]{},
+					hooks: &gsync.Map[resource.URN, *ResourceHooks]{},
 				},
 				pendingNews: gsync.Map[resource.URN, Step]{},
 			}
@@ -226,3 +241,63 @@ func TestStepExecutor(t *testing.T) {
 		})
 	})
 }
+
+func TestStepExecutorParallelFor(t *testing.T) {
+	t.Parallel()
+
+	limiter := newChainLimiter(map[string]int{"aws": 2, "gcp:storage/bucket:Bucket": 1})
+
+	var lock sync.Mutex
+	running, peak, ran := map[string]int{}, map[string]int{}, 0
+	events := &mockEvents{
+		OnResourceStepPreF: func(step Step) (interface{}, error) {
+			key, _ := limiter.key(chain{step})
+			if key == "" {
+				key = string(step.Type().Package())
+			}
+
+			lock.Lock()
+			running[key]++
+			if running[key] > peak[key] {
+				peak[key] = running[key]
+			}
+			ran++
+			lock.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			lock.Lock()
+			running[key]--
+			lock.Unlock()
+
+			// Fail the step so that it isn't applied; the executor ignores errors.
+			return nil, errors.New("done")
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	se := newStepExecutor(ctx, cancel, &Deployment{
+		ctx: &plugin.Context{Diag: &deploytest.NoopSink{}},
+	}, Options{Events: events, Parallel: 8, ParallelFor: limiter.limits}, true /*preview*/, true /*ignoreErrors*/)
+
+	var steps antichain
+	add := func(typ tokens.Type, n int) {
+		for i := 0; i < n; i++ {
+			urn := resource.NewURN("test", "test", "", typ, fmt.Sprintf("res%d", i))
+			steps = append(steps, &CreateStep{new: &resource.State{URN: urn, Type: typ}})
+		}
+	}
+	add("aws:s3/bucket:Bucket", 5)
+	add("pulumi:providers:aws", 1)
+	add("gcp:storage/bucket:Bucket", 3)
+	add("gcp:compute/instance:Instance", 3)
+
+	se.ExecuteParallel(steps).Wait(ctx)
+	se.SignalCompletion()
+	se.WaitForCompletion()
+
+	assert.Equal(t, len(steps), ran)
+	assert.LessOrEqual(t, peak["aws"], 2)
+	assert.LessOrEqual(t, peak["gcp:storage/bucket:Bucket"], 1)
+}
@@ -61,6 +61,11 @@ type stepGenerator struct {
 	// specify them with --target
 	skippedCreates map[resource.URN]bool
 
+	// a map from the URNs of resources that were excluded from this deployment to the reason they were excluded.
+	excluded map[resource.URN]string
+	// set of URNs that would have been created, but were skipped because they were excluded.
+	excludedCreates map[resource.URN]bool
+
 	pendingDeletes map[*resource.State]bool         // set of resources (not URNs!) that are pending deletion
 	providers      map[resource.URN]*resource.State // URN map of providers that we have seen so far.
 
@@ -109,6 +114,45 @@ func (sg *stepGenerator) isTargetedForUpdate(res *resource.State) bool {
 	return false
 }
 
+// excludedReason returns the reason that `res` is excluded from the deployment, or the empty string if it isn't. A
+// resource is excluded if it is in the --exclude list, if it depends on an excluded resource and
+// `--exclude-dependents` was passed, or if it depends on an excluded resource that doesn't exist yet, since it can't
+// be created or updated without it.
+func (sg *stepGenerator) excludedReason(res *resource.State) string {
+	if !sg.opts.Excludes.IsConstrained() {
+		return ""
+	}
+	if sg.opts.Excludes.Contains(res.URN) {
+		return "it was excluded"
+	}
+
+	dependencies := slices.Clone(res.Dependencies)
+	for _, deps := range res.PropertyDependencies {
+		dependencies = append(dependencies, deps...)
+	}
+	if res.Parent != "" {
+		dependencies = append(dependencies, res.Parent)
+	}
+	if res.DeletedWith != "" {
+		dependencies = append(dependencies, res.DeletedWith)
+	}
+	if res.Provider != "" {
+		ref, err := providers.ParseReference(res.Provider)
+		contract.AssertNoErrorf(err, "failed to parse provider reference: %v", res.Provider)
+		dependencies = append(dependencies, ref.URN())
+	}
+
+	for _, dep := range dependencies {
+		if sg.excludedCreates[dep] {
+			return fmt.Sprintf("it depends on '%v', which was excluded and does not exist yet", dep)
+		}
+		if _, has := sg.excluded[dep]; has && sg.opts.ExcludeDependents {
+			return fmt.Sprintf("it depends on '%v', which was excluded", dep)
+		}
+	}
+	return ""
+}
+
 func (sg *stepGenerator) isTargetedReplace(urn resource.URN) bool {
 	return sg.opts.ReplaceTargets.IsConstrained() && sg.opts.ReplaceTargets.Contains(urn)
 }
@@ -545,6 +589,21 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, err
 		}
 	}
 
+	// Check the plaintext inputs for values that look like secrets, and mark them secret if asked to.
+	if scanner := sg.opts.SecretScanner; scanner != nil {
+		var findings []SecretFinding
+		goal.Properties, findings = scanner.Scan(goal.Properties)
+		if err := scanner.Report(sg.deployment.Diag(), urn, "input", findings); err != nil {
+			return nil, err
+		}
+
+		// Mark the same values secret in the old inputs, so that marking an existing resource's inputs secret isn't
+		// seen as a change to them. Scan copies the inputs it changes, so the old state is left as it is.
+		if hasOld && scanner.Action == SecretScanSecret {
+			oldInputs, _ = scanner.Scan(oldInputs)
+		}
+	}
+
 	// Create the desired inputs from the goal state
 	inputs := goal.Properties
 	if hasOld {
@@ -571,6 +630,9 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, err
 	// Mark the URN/resource as having been seen. So we can run analyzers on all resources seen, as well as
 	// lookup providers for calculating replacement of resources that use the provider.
 	sg.deployment.goals.Store(urn, goal)
+	if hooks := event.Hooks(); hooks != nil {
+		sg.deployment.hooks.Store(urn, hooks)
+	}
 	if providers.IsProviderType(goal.Type) {
 		sg.providers[urn] = new
 	}
@@ -658,6 +720,23 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, err
 		isTargeted = sg.isTargetedForUpdate(new)
 	}
 
+	// Excluded resources are left as they are. Providers and the root stack are still updated, as for targets, but
+	// excluding one still excludes the resources that depend on it.
+	if isTargeted {
+		if reason := sg.excludedReason(new); reason != "" {
+			sg.excluded[urn] = reason
+			if isImplicitlyTargetedResource {
+				if sg.opts.Excludes.Contains(urn) {
+					sg.deployment.Diag().Warningf(diag.Message(urn,
+						"Provider and stack resources can't be excluded, so '%v' will be updated as normal"), urn)
+				}
+			} else {
+				sg.deployment.Diag().Infof(diag.Message(urn, "Skipping '%v' because %s"), urn, reason)
+				isTargeted = false
+			}
+		}
+	}
+
 	// Ensure the provider is okay with this resource and fetch the inputs to pass to subsequent methods.
 	if prov != nil {
 		var failures []plugin.CheckFailure
@@ -980,7 +1059,12 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, err
 
 	if !isTargeted {
 		sg.sames[urn] = true
-		sg.skippedCreates[urn] = true
+		if _, excluded := sg.excluded[urn]; excluded {
+			// Anything that needs this resource to exist must be skipped as well.
+			sg.excludedCreates[urn] = true
+		} else {
+			sg.skippedCreates[urn] = true
+		}
 		return []Step{NewSkippedCreateStep(sg.deployment, event, new)}, nil
 	}
 
@@ -1113,6 +1197,9 @@ func (sg *stepGenerator) generateStepsFromDiff(
 				if err != nil {
 					return nil, err
 				}
+				if err := sg.checkExcludedReplacements(urn, toReplace); err != nil {
+					return nil, err
+				}
 
 				// Deletions must occur in reverse dependency order, and `deps` is returned in dependency
 				// order, so we iterate in reverse.
@@ -1288,6 +1375,11 @@ func (sg *stepGenerator) GenerateDeletes(targetsOpt UrnTargets) ([]Step, error)
 				if !ConstrainedTo(s.Op(), constraint) {
 					return nil, fmt.Errorf("%v is not allowed by the plan: this resource is constrained to %v", s.Op(), constraint)
 				}
+				if s.Op() == OpDelete {
+					if err := resourcePlan.checkDelete(s.Old()); err != nil {
+						return nil, fmt.Errorf("%v is not allowed by the plan: %w", s.Op(), err)
+					}
+				}
 			} else {
 				if !ConstrainedTo(s.Op(), OpSame) {
 					return nil, fmt.Errorf("%v is not allowed by the plan: no steps were expected for this resource", s.Op())
@@ -1305,6 +1397,9 @@ func (sg *stepGenerator) GenerateDeletes(targetsOpt UrnTargets) ([]Step, error)
 				sg.deployment.newPlans.set(s.URN(), resourcePlan)
 			}
 			resourcePlan.Ops = append(resourcePlan.Ops, s.Op())
+			if s.Op() == OpDelete {
+				resourcePlan.DeleteIDs = append(resourcePlan.DeleteIDs, s.Old().ID)
+			}
 		}
 	}
 
@@ -1326,6 +1421,9 @@ func (sg *stepGenerator) GenerateDeletes(targetsOpt UrnTargets) ([]Step, error)
 		dels = filtered
 	}
 
+	// Never delete an excluded resource, or anything that an excluded resource depends on.
+	dels = sg.filterExcludedDeletes(dels)
+
 	deletingUnspecifiedTarget := false
 	for _, step := range dels {
 		urn := step.URN()
@@ -1391,6 +1489,112 @@ func (sg *stepGenerator) getTargetDependents(targetsOpt UrnTargets) map[resource
 	return targets
 }
 
+// filterExcludedDeletes removes the steps that would delete an excluded resource, or a resource that an excluded
+// resource depends on, and reports each one that it removes. Steps that delete the old copy of a replaced resource
+// are kept, as the resource itself lives on as its replacement.
+func (sg *stepGenerator) filterExcludedDeletes(dels []Step) []Step {
+	if !sg.opts.Excludes.IsConstrained() || len(dels) == 0 {
+		return dels
+	}
+
+	prev := sg.deployment.prev.Resources
+	excluded := excludedResources(prev, sg.opts.Excludes, sg.opts.ExcludeDependents)
+	for urn, reason := range sg.excluded {
+		if _, has := excluded[urn]; !has {
+			excluded[urn] = reason
+		}
+	}
+
+	// Excluded resources are kept, and so must everything they depend on be. Snapshots are in dependency order, so
+	// a resource's own reason for being excluded is always recorded before any of its dependents are visited.
+	dg := graph.NewDependencyGraph(prev)
+	kept := make(map[resource.URN]string)
+	for _, res := range prev {
+		reason, has := excluded[res.URN]
+		if !has {
+			continue
+		}
+		kept[res.URN] = reason
+		for _, dep := range dg.TransitiveDependenciesOf(res).ToSlice() {
+			if _, has := kept[dep.URN]; !has {
+				kept[dep.URN] = fmt.Sprintf("'%v', which was excluded, depends on it", res.URN)
+			}
+		}
+	}
+
+	filtered := make([]Step, 0, len(dels))
+	for _, step := range dels {
+		if old := step.Old(); old != nil && old.Delete {
+			filtered = append(filtered, step)
+			continue
+		}
+		if reason, has := kept[step.URN()]; has {
+			sg.deployment.Diag().Infof(diag.Message(step.URN(), "Not deleting '%v' because %s"), step.URN(), reason)
+			continue
+		}
+		filtered = append(filtered, step)
+	}
+	return filtered
+}
+
+// checkExcludedReplacements returns an error if any of the given dependents of a resource that is being deleted
+// before it is replaced is excluded, as they would have to be replaced along with it.
+func (sg *stepGenerator) checkExcludedReplacements(urn resource.URN, toReplace []dependentReplace) error {
+	if !sg.opts.Excludes.IsConstrained() || len(toReplace) == 0 {
+		return nil
+	}
+
+	excluded := excludedResources(sg.deployment.prev.Resources, sg.opts.Excludes, sg.opts.ExcludeDependents)
+	for _, dep := range toReplace {
+		if dep.res.Delete {
+			continue
+		}
+		reason, has := excluded[dep.res.URN]
+		if !has {
+			reason, has = sg.excluded[dep.res.URN]
+		}
+		if has {
+			message := fmt.Sprintf("unable to replace resource %q as part of replacing %q because %s",
+				dep.res.URN, urn, reason)
+			sg.deployment.ctx.Diag.Errorf(diag.StreamMessage(urn, message, 0))
+			sg.sawError = true
+			return result.BailErrorf(message)
+		}
+	}
+	return nil
+}
+
+// excludedResources returns the resources in the given list that are excluded by the given set of URNs, mapped to
+// the reason that they were excluded. If dependents is true, the resources that depend on an excluded resource,
+// directly or indirectly, are excluded as well.
+func excludedResources(
+	resources []*resource.State, excludes UrnTargets, dependents bool,
+) map[resource.URN]string {
+	excluded := make(map[resource.URN]string)
+	if !excludes.IsConstrained() {
+		return excluded
+	}
+
+	var dg *graph.DependencyGraph
+	if dependents {
+		dg = graph.NewDependencyGraph(resources)
+	}
+	for _, res := range resources {
+		if !excludes.Contains(res.URN) {
+			continue
+		}
+		excluded[res.URN] = "it was excluded"
+		if dependents {
+			for _, dep := range dg.DependingOn(res, nil, true) {
+				if _, has := excluded[dep.URN]; !has {
+					excluded[dep.URN] = fmt.Sprintf("it depends on '%v', which was excluded", res.URN)
+				}
+			}
+		}
+	}
+	return excluded
+}
+
 // determineAllowedResourcesToDeleteFromTargets computes the full (transitive) closure of resources
 // that need to be deleted to permit the full list of targetsOpt resources to be deleted. This list
 // will include the targetsOpt resources, but may contain more than just that, if there are dependent
@@ -2090,6 +2294,8 @@ func newStepGenerator(
 		updates:              make(map[resource.URN]bool),
 		deletes:              make(map[resource.URN]bool),
 		skippedCreates:       make(map[resource.URN]bool),
+		excluded:             make(map[resource.URN]string),
+		excludedCreates:      make(map[resource.URN]bool),
 		pendingDeletes:       make(map[*resource.State]bool),
 		providers:            make(map[resource.URN]*resource.State),
 		dependentReplaceKeys: make(map[resource.URN][]resource.PropertyKey),
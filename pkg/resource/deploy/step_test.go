@@ -17,9 +17,11 @@ package deploy
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/v3/resource/graph"
 	"github.com/pulumi/pulumi/pkg/v3/util/gsync"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -939,3 +941,29 @@ func TestSuffix(t *testing.T) {
 		assert.Equal(t, expectation, Suffix(op))
 	}
 }
+
+func TestRecordTiming(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	at := func(seconds int) graph.OperationTiming {
+		return graph.OperationTiming{Start: start, End: start.Add(time.Duration(seconds) * time.Second)}
+	}
+
+	timings := map[resource.URN]graph.OperationTiming{}
+	RecordTiming(timings, OpSame, "a", true, at(1))
+	RecordTiming(timings, OpReplace, "a", true, at(1))
+	RecordTiming(timings, OpCreate, "component", false, at(1))
+	assert.Empty(t, timings)
+
+	// The creation of a create-before-delete replacement is kept over the later deletion of the replaced resource.
+	RecordTiming(timings, OpCreateReplacement, "a", true, at(1))
+	RecordTiming(timings, OpDeleteReplaced, "a", true, at(2))
+	assert.Equal(t, at(1), timings["a"])
+
+	// The deletion of a delete-before-replace replacement is overwritten by the later creation of the replacement.
+	RecordTiming(timings, OpDeleteReplaced, "b", true, at(1))
+	assert.Equal(t, at(1), timings["b"])
+	RecordTiming(timings, OpCreateReplacement, "b", true, at(2))
+	assert.Equal(t, at(2), timings["b"])
+}
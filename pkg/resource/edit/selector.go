@@ -0,0 +1,250 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// SelectorHelp describes the selector language for use in command help text.
+const SelectorHelp = `A selector is a space-separated list of terms, all of which a resource must match. Each term is
+one of:
+
+  urn=<glob>          the resource's URN
+  type=<glob>         the resource's type token
+  name=<glob>         the resource's name
+  parent=<glob>       the URN of the resource's parent
+  ancestor=<glob>     the URN of any of the resource's ancestors
+  provider=<glob>     the URN of the resource's provider
+  prop:<path>=<glob>  the value of a property, looked up in the outputs and then the inputs
+  prop:<path>         the property is set
+
+Any term may use != rather than = to negate it, and a term without a key is taken as a URN glob. In a glob, '*'
+matches any sequence of characters and '?' matches any single character.`
+
+// Selector matches the resources in a snapshot. Selectors are parsed from strings by ParseSelector; see SelectorHelp
+// for their syntax.
+type Selector struct {
+	text  string
+	terms []selectorTerm
+}
+
+type selectorTerm struct {
+	key    string
+	path   resource.PropertyPath
+	value  *regexp.Regexp
+	negate bool
+}
+
+var selectorKeys = []string{"urn", "type", "name", "parent", "ancestor", "provider"}
+
+// ParseSelector parses a selector.
+func ParseSelector(text string) (*Selector, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return nil, errors.New("empty selector")
+	}
+
+	sel := &Selector{text: text}
+	for _, field := range fields {
+		term, err := parseSelectorTerm(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector term %q: %w", field, err)
+		}
+		sel.terms = append(sel.terms, term)
+	}
+	return sel, nil
+}
+
+func parseSelectorTerm(field string) (selectorTerm, error) {
+	var term selectorTerm
+
+	// splitValue splits the operator and value off the front of the given string.
+	splitValue := func(s string) (string, bool, bool) {
+		switch {
+		case strings.HasPrefix(s, "!="):
+			return s[2:], true, true
+		case strings.HasPrefix(s, "="):
+			return s[1:], false, true
+		}
+		return "", false, false
+	}
+
+	if rest, ok := strings.CutPrefix(field, "prop:"); ok {
+		term.key = "prop"
+		end := strings.IndexAny(rest, "!=")
+		if end == -1 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return term, errors.New("missing property path")
+		}
+		path, err := resource.ParsePropertyPath(rest[:end])
+		if err != nil {
+			return term, err
+		}
+		term.path = path
+		if end == len(rest) {
+			return term, nil
+		}
+		value, negate, ok := splitValue(rest[end:])
+		if !ok {
+			return term, errors.New("expected = or != after the property path")
+		}
+		term.value, term.negate = compileGlob(value), negate
+		return term, nil
+	}
+
+	for _, key := range selectorKeys {
+		if rest, ok := strings.CutPrefix(field, key); ok {
+			if value, negate, ok := splitValue(rest); ok {
+				term.key, term.value, term.negate = key, compileGlob(value), negate
+				return term, nil
+			}
+		}
+	}
+
+	// Anything else is a glob on the URN.
+	term.key, term.value = "urn", compileGlob(field)
+	return term, nil
+}
+
+// compileGlob compiles a glob, in which '*' matches any sequence of characters and '?' matches any one character,
+// into an anchored regular expression.
+func compileGlob(glob string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+func (s *Selector) String() string {
+	return s.text
+}
+
+// matches returns true if the given resource matches every term of the selector. byURN maps URNs to the resources
+// in the snapshot, for looking up ancestors.
+func (s *Selector) matches(res *resource.State, byURN map[resource.URN]*resource.State) bool {
+	for _, term := range s.terms {
+		if term.matches(res, byURN) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (t selectorTerm) matches(res *resource.State, byURN map[resource.URN]*resource.State) bool {
+	switch t.key {
+	case "urn":
+		return t.value.MatchString(string(res.URN))
+	case "type":
+		return t.value.MatchString(string(res.Type))
+	case "name":
+		return t.value.MatchString(res.URN.Name())
+	case "parent":
+		return res.Parent != "" && t.value.MatchString(string(res.Parent))
+	case "ancestor":
+		seen := make(map[resource.URN]bool)
+		for parent := res.Parent; parent != "" && !seen[parent]; {
+			if t.value.MatchString(string(parent)) {
+				return true
+			}
+			seen[parent] = true
+			p, has := byURN[parent]
+			if !has {
+				break
+			}
+			parent = p.Parent
+		}
+		return false
+	case "provider":
+		if res.Provider == "" {
+			return false
+		}
+		ref, err := providers.ParseReference(res.Provider)
+		return err == nil && t.value.MatchString(string(ref.URN()))
+	case "prop":
+		for _, props := range []resource.PropertyMap{res.Outputs, res.Inputs} {
+			v, has := t.path.Get(resource.NewObjectProperty(props))
+			if !has || v.IsNull() {
+				continue
+			}
+			if t.value == nil {
+				return true
+			}
+			s, ok := selectorPropertyString(v)
+			return ok && t.value.MatchString(s)
+		}
+		return false
+	}
+	return false
+}
+
+// selectorPropertyString returns the string form of a primitive property value for matching against a glob.
+func selectorPropertyString(v resource.PropertyValue) (string, bool) {
+	for v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	switch {
+	case v.IsString():
+		return v.StringValue(), true
+	case v.IsNumber():
+		return strconv.FormatFloat(v.NumberValue(), 'f', -1, 64), true
+	case v.IsBool():
+		return strconv.FormatBool(v.BoolValue()), true
+	}
+	return "", false
+}
+
+// SelectResources returns the resources in the snapshot that match any of the given selectors, in snapshot order.
+func SelectResources(snap *deploy.Snapshot, selectors []*Selector) []*resource.State {
+	if snap == nil {
+		return nil
+	}
+
+	byURN := make(map[resource.URN]*resource.State, len(snap.Resources))
+	for _, res := range snap.Resources {
+		byURN[res.URN] = res
+	}
+
+	var selected []*resource.State
+	for _, res := range snap.Resources {
+		for _, sel := range selectors {
+			if sel.matches(res, byURN) {
+				selected = append(selected, res)
+				break
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,104 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func selectorTestSnapshot() *deploy.Snapshot {
+	prov := NewProviderResource("aws", "east", "0")
+	comp := &resource.State{
+		Type: "my:index:Component",
+		URN:  "urn:pulumi:test::test::my:index:Component::comp",
+	}
+	inner := NewResource("inner", prov)
+	inner.Type = "aws:s3/bucket:Bucket"
+	inner.URN = "urn:pulumi:test::test::my:index:Component$aws:s3/bucket:Bucket::inner"
+	inner.Parent = comp.URN
+	inner.Outputs = resource.PropertyMap{
+		"tags": resource.NewObjectProperty(resource.PropertyMap{
+			"env": resource.NewStringProperty("prod"),
+		}),
+	}
+	outer := NewResource("outer", nil)
+	outer.Type = "aws:s3/bucket:Bucket"
+	outer.URN = "urn:pulumi:test::test::aws:s3/bucket:Bucket::outer"
+	outer.Inputs = resource.PropertyMap{
+		"size": resource.MakeSecret(resource.NewNumberProperty(10)),
+	}
+	return NewSnapshot([]*resource.State{prov, comp, inner, outer})
+}
+
+func TestSelectResources(t *testing.T) {
+	t.Parallel()
+
+	snap := selectorTestSnapshot()
+
+	tests := []struct {
+		selectors []string
+		expected  []string
+	}{
+		{[]string{"type=aws:s3/bucket:Bucket"}, []string{"inner", "outer"}},
+		{[]string{"type=aws:s3/bucket:Bucket ancestor=*::comp"}, []string{"inner"}},
+		{[]string{"type=aws:s3/bucket:Bucket parent!=*::comp"}, []string{"outer"}},
+		{[]string{"provider=*::east"}, []string{"inner"}},
+		{[]string{"name=*er"}, []string{"inner", "outer"}},
+		{[]string{"*::comp"}, []string{"comp"}},
+		{[]string{"prop:tags.env=prod"}, []string{"inner"}},
+		{[]string{"prop:size=1?"}, []string{"outer"}},
+		{[]string{"prop:tags"}, []string{"inner"}},
+		{[]string{"name=comp", "name=outer"}, []string{"comp", "outer"}},
+		{[]string{"type=nothing:here:Thing"}, nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.selectors[0], func(t *testing.T) {
+			t.Parallel()
+
+			sels := make([]*Selector, len(tt.selectors))
+			for i, text := range tt.selectors {
+				sel, err := ParseSelector(text)
+				require.NoError(t, err)
+				sels[i] = sel
+			}
+
+			var names []string
+			for _, res := range SelectResources(snap, sels) {
+				names = append(names, res.URN.Name())
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseSelector("  ")
+	assert.ErrorContains(t, err, "empty selector")
+
+	_, err = ParseSelector("prop:=x")
+	assert.ErrorContains(t, err, "missing property path")
+
+	_, err = ParseSelector("prop:tags!x")
+	assert.ErrorContains(t, err, "expected = or !=")
+}
@@ -186,26 +186,10 @@ func (dg *DependencyGraph) DependenciesOf(res *resource.State) mapset.Set[*resou
 	set := mapset.NewSet[*resource.State]()
 
 	dependentUrns := make(map[resource.URN]bool)
-	for _, dep := range res.Dependencies {
+	for _, dep := range dependencyURNs(res) {
 		dependentUrns[dep] = true
 	}
 
-	for _, deps := range res.PropertyDependencies {
-		for _, dep := range deps {
-			dependentUrns[dep] = true
-		}
-	}
-
-	if res.DeletedWith != "" {
-		dependentUrns[res.DeletedWith] = true
-	}
-
-	if res.Provider != "" {
-		ref, err := providers.ParseReference(res.Provider)
-		contract.AssertNoErrorf(err, "cannot parse provider reference %q", res.Provider)
-		dependentUrns[ref.URN()] = true
-	}
-
 	cursorIndex, ok := dg.index[res]
 	contract.Assertf(ok, "could not determine index for resource %s", res.URN)
 	for i := cursorIndex - 1; i >= 0; i-- {
@@ -236,6 +220,25 @@ func (dg *DependencyGraph) DependenciesOf(res *resource.State) mapset.Set[*resou
 	return set
 }
 
+// dependencyURNs returns the URNs of the resources that the given resource names as its dependencies: its provider, any
+// resources in the `Dependencies` list or the `PropertyDependencies` map, and any resource referenced by the
+// `DeletedWith` field. The resource's parent is not included. The returned URNs may contain duplicates.
+func dependencyURNs(res *resource.State) []resource.URN {
+	urns := append([]resource.URN{}, res.Dependencies...)
+	for _, deps := range res.PropertyDependencies {
+		urns = append(urns, deps...)
+	}
+	if res.DeletedWith != "" {
+		urns = append(urns, res.DeletedWith)
+	}
+	if res.Provider != "" {
+		ref, err := providers.ParseReference(res.Provider)
+		contract.AssertNoErrorf(err, "cannot parse provider reference %q", res.Provider)
+		urns = append(urns, ref.URN())
+	}
+	return urns
+}
+
 // `TransitiveDependenciesOf` calculates the set of resources upon which the
 // given resource depends, directly or indirectly. This includes the resource's
 // provider, parent, any resources in the `Dependencies` list, any resources in
@@ -0,0 +1,256 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// OperationTiming records when the operation on a resource started and finished.
+type OperationTiming struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ResourceTiming is the analyzed timing of the operation on a single resource.
+type ResourceTiming struct {
+	URN   resource.URN
+	Start time.Time
+	End   time.Time
+	// Work is the time the operation itself took.
+	Work time.Duration
+	// Wait is the time between the operation becoming possible, because the last operation it depended on finished
+	// (or the deployment started), and the operation starting.
+	Wait time.Duration
+	// WaitedOn is the URN of the last operation this operation waited on, if any.
+	WaitedOn resource.URN
+}
+
+// TimingAnalysis is the result of analyzing the timings of a deployment's operations against the dependency graph of
+// the resources they operated on.
+type TimingAnalysis struct {
+	// Resources are the timings of every operation, ordered by their start time.
+	Resources []ResourceTiming
+	// CriticalPath is the chain of dependent operations that took the longest in total, in the order they ran.
+	CriticalPath []ResourceTiming
+	// CriticalPathWork is the total time taken by the operations on the critical path.
+	CriticalPathWork time.Duration
+	// Duration is the time between the first operation starting and the last operation finishing.
+	Duration time.Duration
+	// PeakParallelism is the largest number of operations that ran at the same time.
+	PeakParallelism int
+	// AverageParallelism is the average number of operations that were running over the deployment's duration.
+	AverageParallelism float64
+	// Idle is the time during the deployment's duration that no operations were running.
+	Idle time.Duration
+}
+
+// AnalyzeTimings analyzes the timings of a deployment's operations. The resources are used to find which operations
+// depended on which: an operation on a resource depended on an operation on one of its dependencies or dependents if
+// that operation finished before it started. This covers both creates and updates, which run after the operations on
+// their dependencies, and deletes, which run after the operations on their dependents. Timings for resources that are
+// not in the given list are still analyzed, but are treated as having no dependencies.
+func AnalyzeTimings(resources []*resource.State, timings map[resource.URN]OperationTiming) *TimingAnalysis {
+	ops := make([]ResourceTiming, 0, len(timings))
+	for urn, timing := range timings {
+		if timing.Start.IsZero() || timing.End.Before(timing.Start) {
+			continue
+		}
+		ops = append(ops, ResourceTiming{
+			URN:   urn,
+			Start: timing.Start,
+			End:   timing.End,
+			Work:  timing.End.Sub(timing.Start),
+		})
+	}
+	analysis := &TimingAnalysis{Resources: ops}
+	if len(ops) == 0 {
+		return analysis
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		if !ops[i].Start.Equal(ops[j].Start) {
+			return ops[i].Start.Before(ops[j].Start)
+		}
+		if !ops[i].End.Equal(ops[j].End) {
+			return ops[i].End.Before(ops[j].End)
+		}
+		return ops[i].URN < ops[j].URN
+	})
+	index := make(map[resource.URN]int, len(ops))
+	for i, op := range ops {
+		index[op.URN] = i
+	}
+
+	neighbors := make(map[resource.URN]map[resource.URN]bool)
+	link := func(a, b resource.URN) {
+		if a == b {
+			return
+		}
+		if neighbors[a] == nil {
+			neighbors[a] = make(map[resource.URN]bool)
+		}
+		neighbors[a][b] = true
+	}
+	for _, res := range resources {
+		deps := dependencyURNs(res)
+		if res.Parent != "" {
+			deps = append(deps, res.Parent)
+		}
+		for _, dep := range deps {
+			link(res.URN, dep)
+			link(dep, res.URN)
+		}
+	}
+
+	// Operations only depend on operations that come before them in start order, so a single pass in that order
+	// computes both the waits and the longest path ending at each operation.
+	start := ops[0].Start
+	longest := make([]time.Duration, len(ops))
+	previous := make([]int, len(ops))
+	for i := range ops {
+		op := &ops[i]
+		ready, waited, prev := start, -1, -1
+		for urn := range neighbors[op.URN] {
+			j, ok := index[urn]
+			if !ok || j >= i || ops[j].End.After(op.Start) {
+				continue
+			}
+			if ops[j].End.After(ready) || (ops[j].End.Equal(ready) && (waited == -1 || j < waited)) {
+				ready, waited = ops[j].End, j
+			}
+			if prev == -1 || longest[j] > longest[prev] || (longest[j] == longest[prev] && j < prev) {
+				prev = j
+			}
+		}
+		op.Wait = op.Start.Sub(ready)
+		if waited != -1 {
+			op.WaitedOn = ops[waited].URN
+		}
+		longest[i], previous[i] = op.Work, prev
+		if prev != -1 {
+			longest[i] += longest[prev]
+		}
+	}
+
+	last := 0
+	for i := range ops {
+		if longest[i] > longest[last] {
+			last = i
+		}
+	}
+	for i := last; i != -1; i = previous[i] {
+		analysis.CriticalPath = append([]ResourceTiming{ops[i]}, analysis.CriticalPath...)
+	}
+	analysis.CriticalPathWork = longest[last]
+
+	analyzeParallelism(analysis)
+	return analysis
+}
+
+// analyzeParallelism fills in the duration and parallelism statistics of an analysis from its operations.
+func analyzeParallelism(analysis *TimingAnalysis) {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	events := make([]event, 0, 2*len(analysis.Resources))
+	var work time.Duration
+	for _, op := range analysis.Resources {
+		events = append(events, event{op.Start, 1}, event{op.End, -1})
+		work += op.Work
+	}
+	// Operations that finish at the same time as others start are not counted as running at the same time.
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		return events[i].delta < events[j].delta
+	})
+
+	running := 0
+	for i, e := range events {
+		if i > 0 && running == 0 {
+			analysis.Idle += e.at.Sub(events[i-1].at)
+		}
+		running += e.delta
+		if running > analysis.PeakParallelism {
+			analysis.PeakParallelism = running
+		}
+	}
+
+	analysis.Duration = events[len(events)-1].at.Sub(events[0].at)
+	if analysis.Duration > 0 {
+		analysis.AverageParallelism = float64(work) / float64(analysis.Duration)
+	}
+}
+
+// FindCycle returns a cycle in the dependencies between the given resources, as a list of URNs that starts and ends
+// with the same resource, or nil if there is no cycle. Dependencies on resources that are not in the list are ignored.
+func FindCycle(resources []*resource.State) []resource.URN {
+	edges := make(map[resource.URN][]resource.URN, len(resources))
+	for _, res := range resources {
+		deps := dependencyURNs(res)
+		if res.Parent != "" {
+			deps = append(deps, res.Parent)
+		}
+		edges[res.URN] = append(edges[res.URN], deps...)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[resource.URN]int, len(edges))
+	var stack []resource.URN
+	var visit func(urn resource.URN) []resource.URN
+	visit = func(urn resource.URN) []resource.URN {
+		state[urn] = visiting
+		stack = append(stack, urn)
+		for _, dep := range edges[urn] {
+			if _, ok := edges[dep]; !ok {
+				continue
+			}
+			switch state[dep] {
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						return append(append([]resource.URN{}, stack[i:]...), dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[urn] = visited
+		return nil
+	}
+
+	for _, res := range resources {
+		if state[res.URN] == unvisited {
+			if cycle := visit(res.URN); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
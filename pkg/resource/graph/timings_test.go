@@ -0,0 +1,124 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestAnalyzeTimings(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	// a is created first. b and c both depend on a, but b takes longer, so d, which depends on both, waits on b.
+	// e depends on nothing and runs alongside the others.
+	a := &resource.State{URN: "a"}
+	b := &resource.State{URN: "b", Dependencies: []resource.URN{"a"}}
+	c := &resource.State{URN: "c", PropertyDependencies: map[resource.PropertyKey][]resource.URN{"p": {"a"}}}
+	d := &resource.State{URN: "d", Dependencies: []resource.URN{"b", "c"}}
+	e := &resource.State{URN: "e"}
+
+	analysis := AnalyzeTimings([]*resource.State{a, b, c, d, e}, map[resource.URN]OperationTiming{
+		"a": {Start: at(0), End: at(2)},
+		"b": {Start: at(3), End: at(10)},
+		"c": {Start: at(2), End: at(4)},
+		"d": {Start: at(11), End: at(12)},
+		"e": {Start: at(1), End: at(5)},
+	})
+
+	urns := func(timings []ResourceTiming) []resource.URN {
+		var urns []resource.URN
+		for _, t := range timings {
+			urns = append(urns, t.URN)
+		}
+		return urns
+	}
+	assert.Equal(t, []resource.URN{"a", "e", "c", "b", "d"}, urns(analysis.Resources))
+	assert.Equal(t, []resource.URN{"a", "b", "d"}, urns(analysis.CriticalPath))
+	assert.Equal(t, 10*time.Second, analysis.CriticalPathWork)
+	assert.Equal(t, 12*time.Second, analysis.Duration)
+
+	b1 := analysis.CriticalPath[1]
+	assert.Equal(t, 7*time.Second, b1.Work)
+	assert.Equal(t, time.Second, b1.Wait)
+	assert.Equal(t, resource.URN("a"), b1.WaitedOn)
+	d1 := analysis.CriticalPath[2]
+	assert.Equal(t, time.Second, d1.Wait)
+	assert.Equal(t, resource.URN("b"), d1.WaitedOn)
+	e1 := analysis.Resources[1]
+	assert.Equal(t, time.Second, e1.Wait)
+	assert.Empty(t, e1.WaitedOn)
+
+	assert.Equal(t, 3, analysis.PeakParallelism)
+	assert.InDelta(t, 16.0/12.0, analysis.AverageParallelism, 0.001)
+	assert.Equal(t, time.Second, analysis.Idle)
+}
+
+func TestAnalyzeTimingsDeletes(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	// Deletes run in the reverse of dependency order: the child is deleted before its parent, and the resource that
+	// depends on the provider before the provider.
+	prov, provRef := makeProvider("pkg", "prov", "0")
+	parent := &resource.State{URN: "parent"}
+	child := &resource.State{URN: "child", Parent: "parent", Provider: provRef}
+
+	analysis := AnalyzeTimings([]*resource.State{prov, parent, child}, map[resource.URN]OperationTiming{
+		"child":  {Start: at(0), End: at(5)},
+		"parent": {Start: at(6), End: at(7)},
+		prov.URN: {Start: at(5), End: at(8)},
+		"gone":   {Start: at(1), End: at(2)},
+	})
+
+	require.Len(t, analysis.CriticalPath, 2)
+	assert.Equal(t, resource.URN("child"), analysis.CriticalPath[0].URN)
+	assert.Equal(t, prov.URN, analysis.CriticalPath[1].URN)
+	assert.Equal(t, 8*time.Second, analysis.CriticalPathWork)
+	assert.Equal(t, 2, analysis.PeakParallelism)
+	assert.Zero(t, analysis.Idle)
+}
+
+func TestAnalyzeTimingsEmpty(t *testing.T) {
+	t.Parallel()
+
+	analysis := AnalyzeTimings([]*resource.State{{URN: "a"}}, nil)
+	assert.Empty(t, analysis.Resources)
+	assert.Empty(t, analysis.CriticalPath)
+	assert.Zero(t, analysis.Duration)
+}
+
+func TestFindCycle(t *testing.T) {
+	t.Parallel()
+
+	a := &resource.State{URN: "a", Dependencies: []resource.URN{"c", "missing"}}
+	b := &resource.State{URN: "b", Dependencies: []resource.URN{"a"}}
+	c := &resource.State{URN: "c", DeletedWith: "b"}
+	d := &resource.State{URN: "d", Parent: "a"}
+
+	assert.Nil(t, FindCycle([]*resource.State{b, d}))
+	assert.Nil(t, FindCycle([]*resource.State{a, b, d}))
+	assert.Equal(t, []resource.URN{"a", "c", "b", "a"}, FindCycle([]*resource.State{a, b, c, d}))
+}
@@ -1,7 +1,12 @@
 package stack
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
@@ -113,11 +118,26 @@ func SerializeResourcePlan(
 		}
 	}
 
+	var refreshes []apitype.RefreshPlanV1
+	for _, refresh := range plan.Refreshes {
+		outputDiff, err := SerializePlanDiff(refresh.OutputDiff, enc, showSecrets)
+		if err != nil {
+			return apitype.ResourcePlanV1{}, err
+		}
+		refreshes = append(refreshes, apitype.RefreshPlanV1{
+			ID:         refresh.ID,
+			Result:     apitype.OpType(refresh.Result),
+			OutputDiff: outputDiff,
+		})
+	}
+
 	return apitype.ResourcePlanV1{
-		Goal:    goal,
-		Seed:    plan.Seed,
-		Steps:   steps,
-		Outputs: outputs,
+		Goal:      goal,
+		Seed:      plan.Seed,
+		Steps:     steps,
+		Outputs:   outputs,
+		DeleteIDs: plan.DeleteIDs,
+		Refreshes: refreshes,
 	}, nil
 }
 
@@ -189,11 +209,26 @@ func DeserializeResourcePlan(
 		ops[i] = display.StepOp(op)
 	}
 
+	var refreshes []*deploy.RefreshPlan
+	for _, refresh := range plan.Refreshes {
+		outputDiff, err := DeserializePlanDiff(refresh.OutputDiff, dec, enc)
+		if err != nil {
+			return nil, err
+		}
+		refreshes = append(refreshes, &deploy.RefreshPlan{
+			ID:         refresh.ID,
+			Result:     display.StepOp(refresh.Result),
+			OutputDiff: outputDiff,
+		})
+	}
+
 	return &deploy.ResourcePlan{
-		Goal:    goal,
-		Seed:    plan.Seed,
-		Ops:     ops,
-		Outputs: outputs,
+		Goal:      goal,
+		Seed:      plan.Seed,
+		Ops:       ops,
+		Outputs:   outputs,
+		DeleteIDs: plan.DeleteIDs,
+		Refreshes: refreshes,
 	}, nil
 }
 
@@ -217,3 +252,60 @@ func DeserializePlan(plan apitype.DeploymentPlanV1, dec config.Decrypter, enc co
 	}
 	return deserializedPlan, nil
 }
+
+// PlanSignatureAlgorithm is the algorithm used to sign deployment plans.
+const PlanSignatureAlgorithm = "ed25519"
+
+// SignPlan signs the given serialized plan with the given key, replacing any existing signature. The signature covers
+// every field of the plan other than the signature itself.
+func SignPlan(plan *apitype.DeploymentPlanV1, key ed25519.PrivateKey) error {
+	payload, err := planSignaturePayload(*plan)
+	if err != nil {
+		return err
+	}
+	plan.Signature = &apitype.PlanSignatureV1{
+		Algorithm: PlanSignatureAlgorithm,
+		PublicKey: key.Public().(ed25519.PublicKey),
+		Signature: ed25519.Sign(key, payload),
+	}
+	return nil
+}
+
+// VerifyPlanSignature checks that the given serialized plan was signed with the private key matching the given trusted
+// public key, and that it hasn't changed since. The public key recorded in the signature is never trusted: anyone can
+// re-sign a changed plan with a key of their own.
+func VerifyPlanSignature(plan apitype.DeploymentPlanV1, trusted ed25519.PublicKey) error {
+	sig := plan.Signature
+	if sig == nil {
+		return errors.New("the plan is not signed")
+	}
+	if sig.Algorithm != PlanSignatureAlgorithm {
+		return fmt.Errorf("unsupported plan signature algorithm %q", sig.Algorithm)
+	}
+	if !trusted.Equal(ed25519.PublicKey(sig.PublicKey)) {
+		return errors.New("the plan is not signed by the trusted key")
+	}
+
+	payload, err := planSignaturePayload(plan)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(trusted, payload, sig.Signature) {
+		return errors.New("the plan's signature does not match its contents")
+	}
+	return nil
+}
+
+// planSignaturePayload returns the bytes of the given plan that its signature covers. This is the plan's compact JSON
+// encoding without its signature, which is stable across a round trip through a plan file.
+func planSignaturePayload(plan apitype.DeploymentPlanV1) ([]byte, error) {
+	plan.Signature = nil
+
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(plan); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+)
+
+func TestPlanSignature(t *testing.T) {
+	t.Parallel()
+
+	// Build a plan with numbers, secrets, and configuration, all of which must survive a round trip through a plan
+	// file for its signature to still verify.
+	urn := resource.URN("urn:pulumi:stack::proj::pkgA:m:typA::resA")
+	plan := deploy.NewPlan(config.Map{
+		config.MustMakeKey("proj", "size"):     config.NewValue("3"),
+		config.MustMakeKey("proj", "password"): config.NewSecureValue("c2VjcmV0"),
+	})
+	plan.ResourcePlans[urn] = &deploy.ResourcePlan{
+		Goal: &deploy.GoalPlan{
+			Type:   urn.Type(),
+			Name:   urn.Name(),
+			Custom: true,
+			InputDiff: deploy.PlanDiff{
+				Adds: resource.PropertyMap{
+					"size":     resource.NewNumberProperty(0.1),
+					"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+				},
+			},
+		},
+		Ops: []display.StepOp{deploy.OpCreate},
+	}
+
+	enc, err := b64.NewBase64SecretsManager().Encrypter()
+	require.NoError(t, err)
+	serialized, err := SerializePlan(&plan, enc, false /*showSecrets*/)
+	require.NoError(t, err)
+	serialized.Project = "proj"
+	serialized.Stack = "organization/proj/stack"
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	roundTrip := func(plan apitype.DeploymentPlanV1) apitype.DeploymentPlanV1 {
+		bytes, err := json.MarshalIndent(plan, "", "    ")
+		require.NoError(t, err)
+		var result apitype.DeploymentPlanV1
+		require.NoError(t, json.Unmarshal(bytes, &result))
+		return result
+	}
+
+	t.Run("unsigned", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyPlanSignature(roundTrip(serialized), pub)
+		assert.ErrorContains(t, err, "the plan is not signed")
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		t.Parallel()
+
+		signed := serialized
+		require.NoError(t, SignPlan(&signed, key))
+		assert.NoError(t, VerifyPlanSignature(roundTrip(signed), pub))
+	})
+
+	t.Run("resigned", func(t *testing.T) {
+		t.Parallel()
+
+		// A plan that was changed and signed again with another key is refused, even though its signature is valid
+		// for the key that it records.
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		resigned := serialized
+		resigned.Stack = "organization/proj/prod"
+		require.NoError(t, SignPlan(&resigned, otherKey))
+		err = VerifyPlanSignature(roundTrip(resigned), pub)
+		assert.ErrorContains(t, err, "the plan is not signed by the trusted key")
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		t.Parallel()
+
+		signed := serialized
+		require.NoError(t, SignPlan(&signed, key))
+		tampered := roundTrip(signed)
+		tampered.Stack = "organization/proj/prod"
+		err := VerifyPlanSignature(tampered, pub)
+		assert.ErrorContains(t, err, "the plan's signature does not match its contents")
+	})
+}
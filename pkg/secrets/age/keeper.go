@@ -0,0 +1,187 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package age implements a gocloud.dev secrets keeper that encrypts to a list of age recipients, so that a
+// stack's data key can be shared between the holders of age key pairs rather than a passphrase or a cloud key
+// management service.
+//
+// URLs have the form age://?recipient=age1...&recipient=age1..., and decrypting uses the age identities in the
+// file named by PULUMI_AGE_IDENTITY_FILE, or ~/.pulumi/age/keys.txt by default. The recipients are stored along
+// with the ciphertexts the keeper produces, so that a stack's secrets provider URL doesn't need to list them: the URL
+// age:// can decrypt any of them, and the recipients of an existing ciphertext can be read with CiphertextRecipients.
+package age
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"filippo.io/age"
+	"gocloud.dev/gcerrors"
+	gosecrets "gocloud.dev/secrets"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// Scheme is the URL scheme this keeper is registered under.
+const Scheme = "age"
+
+// IdentityFileEnvVar is the environment variable that names the file age identities are read from.
+const IdentityFileEnvVar = "PULUMI_AGE_IDENTITY_FILE"
+
+// URLOpener opens age keepers from URLs of the form age://?recipient=age1...&recipient=age1.... It is registered
+// under Scheme by the programs that support age secrets providers.
+type URLOpener struct{}
+
+// OpenKeeperURL opens a keeper that encrypts to the recipients listed in the URL.
+func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*gosecrets.Keeper, error) {
+	recipients, err := Recipients(u)
+	if err != nil {
+		return nil, fmt.Errorf("open keeper %v: %w", u, err)
+	}
+	return gosecrets.NewKeeper(&keeper{recipients: recipients}), nil
+}
+
+// Recipients returns the age recipients listed in an age:// URL, in their canonical form. A URL without any
+// recipients can only be used to decrypt.
+func Recipients(u *url.URL) ([]string, error) {
+	if u.Host != "" || (u.Path != "" && u.Path != "/") {
+		return nil, errors.New("age recipients must be given with the recipient query parameter")
+	}
+	var recipients []string
+	for param, values := range u.Query() {
+		if param != "recipient" {
+			return nil, fmt.Errorf("invalid query parameter %q", param)
+		}
+		for _, value := range values {
+			recipient, err := age.ParseX25519Recipient(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recipient %q: %w", value, err)
+			}
+			recipients = append(recipients, recipient.String())
+		}
+	}
+	return recipients, nil
+}
+
+// URL returns the age:// URL that lists the given recipients.
+func URL(recipients []string) string {
+	return Scheme + "://?" + url.Values{"recipient": recipients}.Encode()
+}
+
+// envelope is the ciphertext produced by a keeper: the message encrypted by age, and the recipients it was
+// encrypted to.
+type envelope struct {
+	Recipients []string `json:"recipients"`
+	Message    []byte   `json:"message"`
+}
+
+// CiphertextRecipients returns the recipients that a ciphertext produced by a keeper was encrypted to.
+func CiphertextRecipients(ciphertext []byte) ([]string, error) {
+	var env envelope
+	if err := json.Unmarshal(ciphertext, &env); err != nil {
+		return nil, fmt.Errorf("invalid age ciphertext: %w", err)
+	}
+	return env.Recipients, nil
+}
+
+// identityFile returns the path of the file age identities are read from.
+func identityFile() (string, error) {
+	if path := os.Getenv(IdentityFileEnvVar); path != "" {
+		return path, nil
+	}
+	return workspace.GetPulumiPath("age", "keys.txt")
+}
+
+// readIdentities reads the age identities that are used to decrypt data keys.
+func readIdentities() ([]age.Identity, error) {
+	path, err := identityFile()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading age identities (set %s to use a different file): %w",
+			IdentityFileEnvVar, err)
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading age identities from %s: %w", path, err)
+	}
+	return identities, nil
+}
+
+type keeper struct {
+	recipients []string
+}
+
+func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if len(k.recipients) == 0 {
+		return nil, errors.New("at least one recipient is required to encrypt; list them with age://?recipient=age1...")
+	}
+	recipients := make([]age.Recipient, len(k.recipients))
+	for i, r := range k.recipients {
+		recipient, err := age.ParseX25519Recipient(r)
+		if err != nil {
+			return nil, err
+		}
+		recipients[i] = recipient
+	}
+
+	var message bytes.Buffer
+	w, err := age.Encrypt(&message, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{Recipients: k.recipients, Message: message.Bytes()})
+}
+
+func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	var env envelope
+	if err := json.Unmarshal(ciphertext, &env); err != nil {
+		return nil, fmt.Errorf("invalid age ciphertext: %w", err)
+	}
+	identities, err := readIdentities()
+	if err != nil {
+		return nil, err
+	}
+	r, err := age.Decrypt(bytes.NewReader(env.Message), identities...)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, errors.New("none of the age identities is a recipient of the stack's data key")
+		}
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func (k *keeper) Close() error { return nil }
+
+func (k *keeper) ErrorAs(err error, i interface{}) bool { return false }
+
+func (k *keeper) ErrorCode(error) gcerrors.ErrorCode { return gcerrors.Unknown }
@@ -0,0 +1,89 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package age
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gosecrets "gocloud.dev/secrets"
+)
+
+func init() {
+	// The CLI registers the keeper, so register it for the tests too.
+	gosecrets.DefaultURLMux().RegisterKeeper(Scheme, &URLOpener{})
+}
+
+//nolint:paralleltest // sets PULUMI_AGE_IDENTITY_FILE
+func TestKeeper(t *testing.T) {
+	ctx := context.Background()
+
+	alice, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	bob, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	identityFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(identityFile, []byte("# bob\n"+bob.String()+"\n"), 0o600))
+	t.Setenv(IdentityFileEnvVar, identityFile)
+
+	keeper, err := gosecrets.OpenKeeper(ctx,
+		"age://?recipient="+alice.Recipient().String()+"&recipient="+bob.Recipient().String())
+	require.NoError(t, err)
+	ciphertext, err := keeper.Encrypt(ctx, []byte("data key"))
+	require.NoError(t, err)
+	plaintext, err := keeper.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "data key", string(plaintext))
+
+	// The recipients are stored with the ciphertext, so a keeper that doesn't list any can decrypt it, but not encrypt.
+	recipients, err := CiphertextRecipients(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, []string{alice.Recipient().String(), bob.Recipient().String()}, recipients)
+	keeper, err = gosecrets.OpenKeeper(ctx, "age://")
+	require.NoError(t, err)
+	plaintext, err = keeper.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "data key", string(plaintext))
+	_, err = keeper.Encrypt(ctx, []byte("data key"))
+	assert.ErrorContains(t, err, "at least one recipient is required")
+
+	// Bob's identity can't decrypt a key that's only encrypted to Alice.
+	keeper, err = gosecrets.OpenKeeper(ctx, "age://?recipient="+alice.Recipient().String())
+	require.NoError(t, err)
+	ciphertext, err = keeper.Encrypt(ctx, []byte("data key"))
+	require.NoError(t, err)
+	_, err = keeper.Decrypt(ctx, ciphertext)
+	assert.ErrorContains(t, err, "none of the age identities is a recipient")
+}
+
+func TestKeeperURLs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, url := range []string{
+		"age://age1xyz",
+		"age://?recipient=nope",
+		"age://?key=age1xyz",
+	} {
+		_, err := gosecrets.OpenKeeper(ctx, url)
+		assert.Error(t, err, url)
+	}
+}
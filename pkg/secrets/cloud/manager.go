@@ -20,6 +20,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	netUrl "net/url"
 	"os"
@@ -30,10 +31,13 @@ import (
 	_ "gocloud.dev/secrets/azurekeyvault" // support for azurekeyvault://
 	"gocloud.dev/secrets/gcpkms"          // support for gcpkms://
 	_ "gocloud.dev/secrets/hashivault"    // support for hashivault://
+	"golang.org/x/exp/slices"
 	"google.golang.org/api/cloudkms/v1"
 
 	"github.com/pulumi/pulumi/pkg/v3/authhelpers"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/age"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/pgp"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
@@ -89,6 +93,166 @@ func generateNewDataKey(url string) ([]byte, error) {
 	return keeper.Encrypt(context.Background(), plaintextDataKey)
 }
 
+// CanRewrapDataKey returns true if a data key encrypted by the first secrets provider URL can be decrypted and
+// encrypted again by the second, rather than being replaced. This is the case for the age and PGP providers,
+// whose URLs list the recipients the data key is encrypted to: adding or removing recipients doesn't change the
+// data key, so secret values that were encrypted with it don't need to be encrypted again.
+//
+// The age and PGP keepers are registered for their schemes by the CLI.
+func CanRewrapDataKey(from, to string) bool {
+	fromURL, err := netUrl.Parse(from)
+	if err != nil {
+		return false
+	}
+	toURL, err := netUrl.Parse(to)
+	if err != nil {
+		return false
+	}
+	return fromURL.Scheme == toURL.Scheme && (toURL.Scheme == age.Scheme || toURL.Scheme == pgp.Scheme)
+}
+
+// rewrapDataKey decrypts a data key using the keeper for one URL, and encrypts it using the keeper for another.
+func rewrapDataKey(from, to string, encryptedDataKey []byte) ([]byte, error) {
+	ctx := context.Background()
+	fromKeeper, err := openKeeper(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	plaintextDataKey, err := fromKeeper.Decrypt(ctx, encryptedDataKey)
+	if err != nil {
+		return nil, err
+	}
+	toKeeper, err := openKeeper(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	return toKeeper.Encrypt(ctx, plaintextDataKey)
+}
+
+// recipientScheme describes a secrets provider whose URLs list the recipients that the data key is encrypted to, and
+// which stores them with the data key.
+type recipientScheme struct {
+	// recipients returns the recipients listed in a URL.
+	recipients func(u *netUrl.URL) ([]string, error)
+	// url returns the URL that lists the given recipients.
+	url func(recipients []string) string
+	// ciphertextRecipients returns the recipients that a data key was encrypted to.
+	ciphertextRecipients func(ciphertext []byte) ([]string, error)
+}
+
+// recipientSchemes are the secrets providers whose URLs list recipients, by URL scheme.
+var recipientSchemes = map[string]recipientScheme{
+	age.Scheme: {age.Recipients, age.URL, age.CiphertextRecipients},
+	pgp.Scheme: {pgp.Recipients, pgp.URL, pgp.CiphertextRecipients},
+}
+
+// KeyRecipients returns the recipients of a stack's data key if it was encrypted by an age or PGP secrets provider.
+// These are stored with the key rather than in the stack's secrets provider URL.
+func KeyRecipients(info *workspace.ProjectStack) ([]string, error) {
+	u, err := netUrl.Parse(info.SecretsProvider)
+	if err != nil || info.EncryptedKey == "" {
+		return nil, nil
+	}
+	scheme, ok := recipientSchemes[u.Scheme]
+	if !ok {
+		return nil, nil
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(info.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	return scheme.ciphertextRecipients(dataKey)
+}
+
+// AddRecipient returns the secrets provider URL that lists the recipients of a stack's data key and the given
+// recipient, for a stack that uses an age or PGP secrets provider.
+func AddRecipient(info *workspace.ProjectStack, recipient string) (string, error) {
+	scheme, recipients, recipient, err := keyRecipient(info, recipient)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(recipients, recipient) {
+		return "", fmt.Errorf("%s is already a recipient of the stack's data key", recipient)
+	}
+	return scheme.url(append(recipients, recipient)), nil
+}
+
+// RemoveRecipient returns the secrets provider URL that lists the recipients of a stack's data key except for the
+// given recipient, for a stack that uses an age or PGP secrets provider. A data key's last recipient can't be removed.
+func RemoveRecipient(info *workspace.ProjectStack, recipient string) (string, error) {
+	scheme, recipients, recipient, err := keyRecipient(info, recipient)
+	if err != nil {
+		return "", err
+	}
+	i := slices.Index(recipients, recipient)
+	if i < 0 {
+		return "", fmt.Errorf("%s is not a recipient of the stack's data key", recipient)
+	}
+	if len(recipients) == 1 {
+		return "", errors.New("the stack's data key must have at least one recipient")
+	}
+	return scheme.url(slices.Delete(recipients, i, i+1)), nil
+}
+
+// keyRecipient returns the secrets provider of a stack that uses an age or PGP secrets provider, the recipients of its
+// data key, and the given recipient in the same form as them.
+func keyRecipient(
+	info *workspace.ProjectStack, recipient string,
+) (recipientScheme, []string, string, error) {
+	u, err := netUrl.Parse(info.SecretsProvider)
+	if err != nil {
+		return recipientScheme{}, nil, "", fmt.Errorf("unable to parse the secrets provider URL: %w", err)
+	}
+	scheme, ok := recipientSchemes[u.Scheme]
+	if !ok {
+		return recipientScheme{}, nil, "", errors.New("the stack does not use the age or pgp secrets provider")
+	}
+	recipients, err := KeyRecipients(info)
+	if err != nil {
+		return recipientScheme{}, nil, "", err
+	}
+	if own, err := scheme.recipients(u); err == nil && len(own) > 0 {
+		recipients = own
+	}
+	u, err = netUrl.Parse(scheme.url([]string{recipient}))
+	if err != nil {
+		return recipientScheme{}, nil, "", err
+	}
+	parsed, err := scheme.recipients(u)
+	if err != nil {
+		return recipientScheme{}, nil, "", err
+	}
+	return scheme, recipients, parsed[0], nil
+}
+
+// withKeyRecipients returns the given secrets provider URL with the given recipients if it is an age or PGP URL that
+// doesn't list any recipients of its own.
+func withKeyRecipients(url string, recipients []string) string {
+	u, err := netUrl.Parse(url)
+	if err != nil || len(recipients) == 0 {
+		return url
+	}
+	scheme, ok := recipientSchemes[u.Scheme]
+	if !ok {
+		return url
+	}
+	if own, err := scheme.recipients(u); err != nil || len(own) > 0 {
+		return url
+	}
+	return scheme.url(recipients)
+}
+
+// storedURL returns the secrets provider URL to store for a stack, which for age and PGP secrets providers leaves out
+// the recipients, as they are stored with the data key.
+func storedURL(url string) string {
+	if u, err := netUrl.Parse(url); err == nil {
+		if _, ok := recipientSchemes[u.Scheme]; ok {
+			return u.Scheme + "://"
+		}
+	}
+	return url
+}
+
 // newCloudSecretsManager returns a secrets manager that uses the target cloud key management
 // service to encrypt/decrypt a data key used for envelope encryption of secrets values.
 func newCloudSecretsManager(url string, encryptedDataKey []byte) (*Manager, error) {
@@ -111,7 +275,7 @@ func newCloudSecretsManager(url string, encryptedDataKey []byte) (*Manager, erro
 		return nil, err
 	}
 	state, err := json.Marshal(cloudSecretsManagerState{
-		URL:          url,
+		URL:          storedURL(url),
 		EncryptedKey: encryptedDataKey,
 	})
 	if err != nil {
@@ -201,14 +365,39 @@ func NewCloudSecretsManager(info *workspace.ProjectStack,
 		secretsProvider = override
 	}
 
+	// An age or PGP secrets provider URL that doesn't list any recipients stands for the recipients of the existing
+	// data key.
+	recipients, err := KeyRecipients(info)
+	if err != nil {
+		return nil, err
+	}
+	currentProvider := withKeyRecipients(info.SecretsProvider, recipients)
+	secretsProvider = withKeyRecipients(secretsProvider, recipients)
+
 	// If we're rotating then just clear the key so we create a fresh one below
 	if rotateSecretsProvider {
 		info.EncryptedKey = ""
 	}
 
+	// if the secrets provider is only changing who the existing key is encrypted to, then encrypt the existing key
+	// for the new secrets provider
+	if info.EncryptedKey != "" && currentProvider != secretsProvider &&
+		CanRewrapDataKey(currentProvider, secretsProvider) {
+		dataKey, err := base64.StdEncoding.DecodeString(info.EncryptedKey)
+		if err != nil {
+			return nil, err
+		}
+		dataKey, err = rewrapDataKey(currentProvider, secretsProvider, dataKey)
+		if err != nil {
+			return nil, err
+		}
+		info.EncryptedKey = base64.StdEncoding.EncodeToString(dataKey)
+		currentProvider = secretsProvider
+	}
+
 	// if there is no key OR the secrets provider is changing
 	// then we need to generate the new key based on the new secrets provider
-	if info.EncryptedKey == "" || info.SecretsProvider != secretsProvider {
+	if info.EncryptedKey == "" || currentProvider != secretsProvider {
 		dataKey, err := generateNewDataKey(secretsProvider)
 		if err != nil {
 			return nil, err
@@ -222,7 +411,7 @@ func NewCloudSecretsManager(info *workspace.ProjectStack,
 		}
 		info.EncryptedKey = base64.StdEncoding.EncodeToString(dataKey)
 	}
-	info.SecretsProvider = secretsProvider
+	info.SecretsProvider = storedURL(secretsProvider)
 
 	dataKey, err := base64.StdEncoding.DecodeString(info.EncryptedKey)
 	if err != nil {
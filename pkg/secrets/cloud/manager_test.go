@@ -20,8 +20,16 @@ import (
 	"fmt"
 	"math/big"
 	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"filippo.io/age"
+
+	agekeeper "github.com/pulumi/pulumi/pkg/v3/secrets/age"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/pgp"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,6 +37,12 @@ import (
 	"gocloud.dev/secrets/driver"
 )
 
+func init() {
+	// The CLI registers the age and PGP keepers, so register them for the tests too.
+	secrets.DefaultURLMux().RegisterKeeper(agekeeper.Scheme, &agekeeper.URLOpener{})
+	secrets.DefaultURLMux().RegisterKeeper(pgp.Scheme, &pgp.URLOpener{})
+}
+
 // the main testing function, takes a kms url and tries to make a new secret manager out of it and encrypt and
 // decrypt data, this is used by the aws_test and azure_test files.
 func testURL(ctx context.Context, t *testing.T, url string) {
@@ -102,6 +116,158 @@ func TestSecretsProviderOverride(t *testing.T) {
 	})
 }
 
+//nolint:paralleltest // sets PULUMI_AGE_IDENTITY_FILE
+func TestChangeAgeRecipients(t *testing.T) {
+	ctx := context.Background()
+
+	alice, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	bob, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	identityFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(identityFile, []byte(alice.String()+"\n"), 0o600))
+	t.Setenv(agekeeper.IdentityFileEnvVar, identityFile)
+
+	aliceURL := "age://?recipient=" + alice.Recipient().String()
+	bothURL := aliceURL + "&recipient=" + bob.Recipient().String()
+	assert.True(t, CanRewrapDataKey(aliceURL, bothURL))
+	assert.False(t, CanRewrapDataKey(aliceURL, "pgp://"))
+	assert.False(t, CanRewrapDataKey("awskms://alias/a", "awskms://alias/b"))
+
+	// The recipients are stored with the data key rather than in the stack's secrets provider URL.
+	info := &workspace.ProjectStack{}
+	manager, err := NewCloudSecretsManager(info, aliceURL, false)
+	require.NoError(t, err)
+	assert.Equal(t, "age://", info.SecretsProvider)
+	keyRecipients := func(info *workspace.ProjectStack) []string {
+		recipients, err := KeyRecipients(info)
+		require.NoError(t, err)
+		return recipients
+	}
+	assert.Equal(t, []string{alice.Recipient().String()}, keyRecipients(info))
+	enc, err := manager.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue(ctx, "plaintext")
+	require.NoError(t, err)
+	aliceKey := info.EncryptedKey
+
+	// Adding a recipient encrypts the same data key to both recipients, so existing values can still be
+	// decrypted.
+	url, err := AddRecipient(info, bob.Recipient().String())
+	require.NoError(t, err)
+	assert.Equal(t, bothURL, url)
+	_, err = AddRecipient(info, alice.Recipient().String())
+	assert.ErrorContains(t, err, "is already a recipient")
+	manager, err = NewCloudSecretsManager(info, url, false)
+	require.NoError(t, err)
+	assert.Equal(t, "age://", info.SecretsProvider)
+	assert.Equal(t, []string{alice.Recipient().String(), bob.Recipient().String()}, keyRecipients(info))
+	assert.NotEqual(t, aliceKey, info.EncryptedKey)
+	dec, err := manager.Decrypter()
+	require.NoError(t, err)
+	plaintext, err := dec.DecryptValue(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", plaintext)
+
+	// Removing a recipient keeps the data key as well, but its last recipient can't be removed.
+	url, err = RemoveRecipient(info, bob.Recipient().String())
+	require.NoError(t, err)
+	assert.Equal(t, aliceURL, url)
+	_, err = NewCloudSecretsManager(info, url, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{alice.Recipient().String()}, keyRecipients(info))
+	_, err = RemoveRecipient(info, bob.Recipient().String())
+	assert.ErrorContains(t, err, "is not a recipient")
+	_, err = RemoveRecipient(info, alice.Recipient().String())
+	assert.ErrorContains(t, err, "at least one recipient")
+
+	// Rotating generates a new data key for the same recipients.
+	manager, err = NewCloudSecretsManager(info, info.SecretsProvider, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{alice.Recipient().String()}, keyRecipients(info))
+	dec, err = manager.Decrypter()
+	require.NoError(t, err)
+	_, err = dec.DecryptValue(ctx, ciphertext)
+	assert.Error(t, err)
+}
+
+//nolint:paralleltest // sets GNUPGHOME
+func TestChangePGPRecipients(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+	ctx := context.Background()
+
+	home, err := os.MkdirTemp("", "gnupg")
+	require.NoError(t, err)
+	t.Setenv("GNUPGHOME", home)
+	t.Cleanup(func() {
+		_ = exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+		os.RemoveAll(home)
+	})
+	generateKey := func(uid string) string {
+		out, err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-generate-key", uid).CombinedOutput()
+		require.NoError(t, err, string(out))
+		keys, err := exec.Command("gpg", "--list-keys", "--with-colons", uid).Output()
+		require.NoError(t, err)
+		// Encrypt to the key's encryption subkey, whose fingerprint follows it.
+		subkey := false
+		for _, line := range strings.Split(string(keys), "\n") {
+			switch fields := strings.Split(line, ":"); {
+			case fields[0] == "sub":
+				subkey = strings.Contains(fields[11], "e")
+			case fields[0] == "fpr" && subkey:
+				return fields[9]
+			}
+		}
+		require.Fail(t, "no fingerprint for "+uid)
+		return ""
+	}
+	alice, bob := generateKey("Alice <alice@example.com>"), generateKey("Bob <bob@example.com>")
+	keyRecipients := func(info *workspace.ProjectStack) []string {
+		recipients, err := KeyRecipients(info)
+		require.NoError(t, err)
+		return recipients
+	}
+
+	// The recipients are stored with the data key rather than in the stack's secrets provider URL.
+	info := &workspace.ProjectStack{}
+	manager, err := NewCloudSecretsManager(info, pgp.URL([]string{alice}), false)
+	require.NoError(t, err)
+	assert.Equal(t, "pgp://", info.SecretsProvider)
+	assert.Equal(t, []string{alice}, keyRecipients(info))
+	enc, err := manager.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue(ctx, "plaintext")
+	require.NoError(t, err)
+
+	// Adding a recipient encrypts the same data key to both recipients.
+	_, err = NewCloudSecretsManager(info, pgp.URL([]string{alice, bob}), false)
+	require.NoError(t, err)
+	assert.Equal(t, "pgp://", info.SecretsProvider)
+	assert.Equal(t, []string{alice, bob}, keyRecipients(info))
+	bothKey := info.EncryptedKey
+
+	// Loading the stack's secrets provider keeps the data key as it is.
+	manager, err = NewCloudSecretsManager(info, info.SecretsProvider, false)
+	require.NoError(t, err)
+	assert.Equal(t, bothKey, info.EncryptedKey)
+	dec, err := manager.Decrypter()
+	require.NoError(t, err)
+	plaintext, err := dec.DecryptValue(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", plaintext)
+
+	// Rotating generates a new data key for the same recipients.
+	manager, err = NewCloudSecretsManager(info, info.SecretsProvider, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{alice, bob}, keyRecipients(info))
+	dec, err = manager.Decrypter()
+	require.NoError(t, err)
+	_, err = dec.DecryptValue(ctx, ciphertext)
+	assert.Error(t, err)
+}
+
 type mockSecretsKeeperOpener struct {
 	wantURL string
 }
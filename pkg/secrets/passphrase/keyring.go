@@ -0,0 +1,224 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package passphrase
+
+import (
+	"bufio"
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// keyringVersion is the version tag of keyring states. A keyring holds several copies of a stack's data key, each
+// wrapped with a key derived from a different passphrase, so that any one of the passphrases unlocks the stack's
+// secrets. Its state has the form
+//
+//	v2:<salt>:<wrapped key>,<salt>:<wrapped key>,...
+//
+// where each salt is base64 encoded, and each wrapped key is the base64 encoded data key encrypted with the key
+// that NewSymmetricCrypterFromPassphrase derives from a passphrase and the salt.
+const keyringVersion = "v2"
+
+type keyringEntry struct {
+	salt       []byte
+	wrappedKey string
+}
+
+type keyring []keyringEntry
+
+// parseKeyring parses a keyring state.
+func parseKeyring(state string) (keyring, error) {
+	entries, ok := strings.CutPrefix(state, keyringVersion+":")
+	if !ok {
+		return nil, errors.New("unknown state version")
+	}
+
+	var k keyring
+	for _, entry := range strings.Split(entries, ",") {
+		salt, wrappedKey, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.New("malformed state value")
+		}
+		saltBytes, err := base64.StdEncoding.DecodeString(salt)
+		if err != nil {
+			return nil, err
+		}
+		k = append(k, keyringEntry{salt: saltBytes, wrappedKey: wrappedKey})
+	}
+	return k, nil
+}
+
+// String returns the keyring's state.
+func (k keyring) String() string {
+	entries := make([]string, len(k))
+	for i, entry := range k {
+		entries[i] = base64.StdEncoding.EncodeToString(entry.salt) + ":" + entry.wrappedKey
+	}
+	return keyringVersion + ":" + strings.Join(entries, ",")
+}
+
+// unlock returns the data key and the index of the entry that the given passphrase unlocks, or
+// ErrIncorrectPassphrase if it unlocks none of them.
+func (k keyring) unlock(phrase string) ([]byte, int, error) {
+	// symmetricCrypter does not use ctx, safe to pass context.Background()
+	ignoredCtx := context.Background()
+	for i, entry := range k {
+		crypter := config.NewSymmetricCrypterFromPassphrase(phrase, entry.salt)
+		decrypted, err := crypter.DecryptValue(ignoredCtx, entry.wrappedKey)
+		if err != nil {
+			continue
+		}
+		dataKey, err := base64.StdEncoding.DecodeString(decrypted)
+		if err != nil || len(dataKey) != config.SymmetricCrypterKeyBytes {
+			return nil, -1, errors.New("malformed state value")
+		}
+		return dataKey, i, nil
+	}
+	return nil, -1, ErrIncorrectPassphrase
+}
+
+// newKeyringEntry wraps a data key with a key derived from the given passphrase and a new salt.
+func newKeyringEntry(phrase string, dataKey []byte) keyringEntry {
+	salt := make([]byte, 8)
+	_, err := cryptorand.Read(salt)
+	contract.AssertNoErrorf(err, "could not read from system random")
+
+	crypter := config.NewSymmetricCrypterFromPassphrase(phrase, salt)
+	// symmetricCrypter does not use ctx, safe to use context.Background()
+	wrappedKey, err := crypter.EncryptValue(context.Background(), base64.StdEncoding.EncodeToString(dataKey))
+	contract.AssertNoErrorf(err, "could not encrypt data key")
+	return keyringEntry{salt: salt, wrappedKey: wrappedKey}
+}
+
+// AddPassphrase adds a passphrase to the keyring of a stack that uses the passphrase secrets provider, so that
+// either the stack's current passphrase or the new one can decrypt its secrets. The stack's current passphrase is read
+// in the same way as NewPromptingPassphraseSecretsManagerFromState, and the new passphrase is prompted for, or read
+// from standard input if not interactive. The stack's secrets are not encrypted again: the new keyring is stored in
+// info and unlocks the same data key.
+func AddPassphrase(info *workspace.ProjectStack) (secrets.Manager, error) {
+	if info.EncryptionSalt == "" {
+		return nil, errors.New("the stack does not use the passphrase secrets provider")
+	}
+	phrase, dataKey, err := promptForPassphrase(info.EncryptionSalt)
+	if err != nil {
+		return nil, err
+	}
+
+	// A stack with a single passphrase becomes a keyring with an entry for it.
+	k := keyring{newKeyringEntry(phrase, dataKey)}
+	if strings.HasPrefix(info.EncryptionSalt, keyringVersion+":") {
+		if k, err = parseKeyring(info.EncryptionSalt); err != nil {
+			return nil, err
+		}
+	}
+
+	newPhrase, err := readOtherPassphrase(
+		"Enter the passphrase to add", "Re-enter the passphrase to add to confirm")
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := k.unlock(newPhrase); err == nil {
+		return nil, errors.New("the passphrase can already decrypt the stack's secrets")
+	}
+	k = append(k, newKeyringEntry(newPhrase, dataKey))
+
+	info.EncryptionSalt = k.String()
+	return newLocalSecretsManager(info.EncryptionSalt, dataKey)
+}
+
+// RemovePassphrase removes a passphrase from the keyring of a stack that uses the passphrase secrets provider. The
+// passphrase to remove is prompted for, or read from standard input if not interactive. The stack's secrets are not
+// encrypted again, so anyone who has already used the removed passphrase to read the stack's data key can still
+// decrypt them: use RotatePassphraseSecretsManager to encrypt them with a new data key as well.
+func RemovePassphrase(info *workspace.ProjectStack) (secrets.Manager, error) {
+	if info.EncryptionSalt == "" {
+		return nil, errors.New("the stack does not use the passphrase secrets provider")
+	}
+	if !strings.HasPrefix(info.EncryptionSalt, keyringVersion+":") {
+		return nil, errors.New("the stack has only one passphrase, which can't be removed")
+	}
+	k, err := parseKeyring(info.EncryptionSalt)
+	if err != nil {
+		return nil, err
+	}
+	if len(k) == 1 {
+		return nil, errors.New("the stack has only one passphrase, which can't be removed")
+	}
+
+	phrase, err := readOtherPassphrase("Enter the passphrase to remove", "")
+	if err != nil {
+		return nil, err
+	}
+	dataKey, i, err := k.unlock(phrase)
+	if err != nil {
+		return nil, err
+	}
+	k = append(k[:i:i], k[i+1:]...)
+
+	info.EncryptionSalt = k.String()
+	return newLocalSecretsManager(info.EncryptionSalt, dataKey)
+}
+
+// readOtherPassphrase reads a passphrase other than the stack's current one, which is never read from
+// PULUMI_CONFIG_PASSPHRASE or PULUMI_CONFIG_PASSPHRASE_FILE. If interactive, it's prompted for, and confirmed if
+// confirmPrompt is not empty; otherwise it's read from standard input.
+func readOtherPassphrase(prompt, confirmPrompt string) (string, error) {
+	if !isInteractive() {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(scanner.Text()), nil
+	}
+
+	for {
+		first, _, err := readPassphrase(prompt, false /*useEnv*/)
+		if err != nil || confirmPrompt == "" {
+			return first, err
+		}
+		second, _, err := readPassphrase(confirmPrompt, false /*useEnv*/)
+		if err != nil {
+			return "", err
+		}
+		if first == second {
+			return first, nil
+		}
+		cmdutil.Diag().Errorf(diag.Message("", "passphrases do not match"))
+	}
+}
+
+// KeyringSize returns the number of passphrases that can decrypt the secrets of a stack with the given encryption
+// salt.
+func KeyringSize(encryptionSalt string) (int, error) {
+	if !strings.HasPrefix(encryptionSalt, keyringVersion+":") {
+		return 1, nil
+	}
+	k, err := parseKeyring(encryptionSalt)
+	if err != nil {
+		return 0, err
+	}
+	return len(k), nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package passphrase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setStdin replaces standard input with the given text for the rest of the test.
+func setStdin(t *testing.T, text string) {
+	path := filepath.Join(t.TempDir(), "stdin")
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o600))
+	f, err := os.Open(path)
+	require.NoError(t, err)
+
+	old := os.Stdin
+	os.Stdin = f
+	t.Cleanup(func() {
+		os.Stdin = old
+		f.Close()
+	})
+}
+
+// decryptWith decrypts a value with the secrets manager that the given passphrase unlocks for a state.
+func decryptWith(t *testing.T, phrase, state, ciphertext string) (string, error) {
+	clearCachedSecretsManagers()
+	sm, err := GetPassphraseSecretsManager(phrase, state)
+	if err != nil {
+		return "", err
+	}
+	dec, err := sm.Decrypter()
+	require.NoError(t, err)
+	return dec.DecryptValue(context.Background(), ciphertext)
+}
+
+//nolint:paralleltest // mutates environment variables and standard input
+func TestAddAndRemovePassphrase(t *testing.T) {
+	resetEnv := resetPassphraseTestEnvVars()
+	defer resetEnv()
+
+	os.Setenv("PULUMI_CONFIG_PASSPHRASE", "password")
+	os.Unsetenv("PULUMI_CONFIG_PASSPHRASE_FILE")
+
+	salt := "v1:fozI5u6B030=:v1:F+6ZduKKd8G0/V7L:PGMFeIzwobWRKmEAzUdaQHqC5mMRIQ=="
+	info := &workspace.ProjectStack{EncryptionSalt: salt}
+	sm, err := GetPassphraseSecretsManager("password", salt)
+	require.NoError(t, err)
+	enc, err := sm.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue(context.Background(), "secret")
+	require.NoError(t, err)
+
+	// Adding a passphrase turns the stack's salt into a keyring that either passphrase unlocks, without changing the
+	// data key.
+	setStdin(t, "other\n")
+	_, err = AddPassphrase(info)
+	require.NoError(t, err)
+	size, err := KeyringSize(info.EncryptionSalt)
+	require.NoError(t, err)
+	assert.Equal(t, 2, size)
+	for _, phrase := range []string{"password", "other"} {
+		plaintext, err := decryptWith(t, phrase, info.EncryptionSalt, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", plaintext)
+	}
+	clearCachedSecretsManagers()
+	_, err = GetPassphraseSecretsManager("wrong", info.EncryptionSalt)
+	assert.ErrorIs(t, err, ErrIncorrectPassphrase)
+
+	// A passphrase can't be added twice.
+	setStdin(t, "other\n")
+	_, err = AddPassphrase(info)
+	assert.ErrorContains(t, err, "the passphrase can already decrypt the stack's secrets")
+
+	// The data key can't be rotated while the stack has more than one passphrase.
+	_, err = RotatePassphraseSecretsManager(info)
+	assert.ErrorContains(t, err, "the stack has 2 passphrases")
+
+	// Removing a passphrase leaves the others.
+	setStdin(t, "password\n")
+	_, err = RemovePassphrase(info)
+	require.NoError(t, err)
+	clearCachedSecretsManagers()
+	_, err = GetPassphraseSecretsManager("password", info.EncryptionSalt)
+	assert.ErrorIs(t, err, ErrIncorrectPassphrase)
+	plaintext, err := decryptWith(t, "other", info.EncryptionSalt, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", plaintext)
+
+	// But the last one can't be removed.
+	setStdin(t, "other\n")
+	_, err = RemovePassphrase(info)
+	assert.ErrorContains(t, err, "the stack has only one passphrase")
+}
+
+//nolint:paralleltest // mutates environment variables and standard input
+func TestRemovePassphraseIncorrectPassphrase(t *testing.T) {
+	resetEnv := resetPassphraseTestEnvVars()
+	defer resetEnv()
+
+	os.Setenv("PULUMI_CONFIG_PASSPHRASE", "password")
+	os.Unsetenv("PULUMI_CONFIG_PASSPHRASE_FILE")
+
+	info := &workspace.ProjectStack{
+		EncryptionSalt: "v1:fozI5u6B030=:v1:F+6ZduKKd8G0/V7L:PGMFeIzwobWRKmEAzUdaQHqC5mMRIQ==",
+	}
+	setStdin(t, "other\n")
+	_, err := AddPassphrase(info)
+	require.NoError(t, err)
+	state := info.EncryptionSalt
+
+	setStdin(t, "wrong\n")
+	_, err = RemovePassphrase(info)
+	assert.ErrorIs(t, err, ErrIncorrectPassphrase)
+	assert.Equal(t, state, info.EncryptionSalt)
+}
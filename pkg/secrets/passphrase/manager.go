@@ -16,7 +16,6 @@
 package passphrase
 
 import (
-	"bufio"
 	"context"
 	cryptorand "crypto/rand"
 	"encoding/base64"
@@ -40,11 +39,20 @@ const Type = "passphrase"
 
 var ErrIncorrectPassphrase = errors.New("incorrect passphrase")
 
-// given a passphrase and an encryption state, construct a Crypter from it. Our encryption
-// state value is a version tag followed by version specific state information. Presently, we only have one version
-// we support (`v1`) which is AES-256-GCM using a key derived from a passphrase using 1,000,000 iterations of PDKDF2
-// using SHA256.
-func symmetricCrypterFromPhraseAndState(phrase string, state string) (config.Crypter, error) {
+// given a passphrase and an encryption state, return the data key the passphrase unlocks. Our encryption
+// state value is a version tag followed by version specific state information. We support two versions: `v1`, which
+// is AES-256-GCM using a key derived from a passphrase using 1,000,000 iterations of PDKDF2 using SHA256, and `v2`,
+// a keyring that holds copies of a data key wrapped with keys derived from different passphrases (see keyring.go).
+func dataKeyFromPhraseAndState(phrase string, state string) ([]byte, error) {
+	if strings.HasPrefix(state, keyringVersion+":") {
+		keyring, err := parseKeyring(state)
+		if err != nil {
+			return nil, err
+		}
+		dataKey, _, err := keyring.unlock(phrase)
+		return dataKey, err
+	}
+
 	splits := strings.SplitN(state, ":", 3)
 	if len(splits) != 3 {
 		return nil, errors.New("malformed state value")
@@ -59,7 +67,8 @@ func symmetricCrypterFromPhraseAndState(phrase string, state string) (config.Cry
 		return nil, err
 	}
 
-	decrypter := config.NewSymmetricCrypterFromPassphrase(phrase, salt)
+	key := config.KeyFromPassphrase(phrase, salt)
+	decrypter := config.NewSymmetricCrypter(key)
 	// symmetricCrypter does not use ctx, safe to pass context.Background()
 	ignoredCtx := context.Background()
 	decrypted, err := decrypter.DecryptValue(ignoredCtx, state[indexN(state, ":", 2)+1:])
@@ -67,7 +76,7 @@ func symmetricCrypterFromPhraseAndState(phrase string, state string) (config.Cry
 		return nil, ErrIncorrectPassphrase
 	}
 
-	return decrypter, nil
+	return key, nil
 }
 
 func indexN(s string, substr string, n int) int {
@@ -195,11 +204,23 @@ func GetPassphraseSecretsManager(phrase string, state string) (secrets.Manager,
 	}
 
 	// Wasn't in the cache so try to construct it and add it if there's no error.
-	crypter, err := symmetricCrypterFromPhraseAndState(phrase, state)
+	return newPassphraseSecretsManagerFromState(phrase, state)
+}
+
+// newPassphraseSecretsManagerFromState returns a passphrase-based secrets manager for the given passphrase and
+// state, checking that the passphrase is correct even if a secrets manager for the state has been cached.
+func newPassphraseSecretsManagerFromState(phrase string, state string) (secrets.Manager, error) {
+	dataKey, err := dataKeyFromPhraseAndState(phrase, state)
 	if err != nil {
 		return nil, err
 	}
+	return newLocalSecretsManager(state, dataKey)
+}
 
+// newLocalSecretsManager returns a passphrase-based secrets manager for the given state and the data key it unlocks,
+// and caches it.
+func newLocalSecretsManager(state string, dataKey []byte) (secrets.Manager, error) {
+	crypter := config.NewSymmetricCrypter(dataKey)
 	jsonState, err := json.Marshal(localSecretsManagerState{
 		Salt: state,
 	})
@@ -225,27 +246,71 @@ func newPromptingPassphraseSecretsManagerFromState(state string) (secrets.Manage
 	}
 
 	// Otherwise, prompt for the password.
+	_, dataKey, err := promptForPassphrase(state)
+	if err != nil {
+		return nil, err
+	}
+	return newLocalSecretsManager(state, dataKey)
+}
+
+// promptForPassphrase reads the passphrase for the given state from PULUMI_CONFIG_PASSPHRASE, the file specified by
+// PULUMI_CONFIG_PASSPHRASE_FILE, or otherwise by prompting for it if interactive, and returns the passphrase and the
+// data key it unlocks.
+func promptForPassphrase(state string) (string, []byte, error) {
 	const prompt = "Enter your passphrase to unlock config/secrets\n" +
 		"    (set PULUMI_CONFIG_PASSPHRASE or PULUMI_CONFIG_PASSPHRASE_FILE to remember)"
 	for {
 		phrase, interactive, phraseErr := readPassphrase(prompt, true /*useEnv*/)
 		if phraseErr != nil {
-			return nil, phraseErr
+			return "", nil, phraseErr
 		}
 
-		sm, smerr := GetPassphraseSecretsManager(phrase, state)
+		dataKey, err := dataKeyFromPhraseAndState(phrase, state)
 		switch {
-		case interactive && smerr == ErrIncorrectPassphrase:
+		case interactive && err == ErrIncorrectPassphrase:
 			cmdutil.Diag().Errorf(diag.Message("", "incorrect passphrase"))
 			continue
-		case smerr != nil:
-			return nil, smerr
+		case err != nil:
+			return "", nil, err
 		default:
-			return sm, nil
+			return phrase, dataKey, nil
 		}
 	}
 }
 
+// RotatePassphraseSecretsManager returns a passphrase-based secrets manager for the given stack that uses the
+// stack's passphrase with a new salt, so that it encrypts secrets with a new key. The stack's current passphrase is
+// read in the same way as NewPromptingPassphraseSecretsManagerFromState, and the new salt is stored in info.
+func RotatePassphraseSecretsManager(info *workspace.ProjectStack) (secrets.Manager, error) {
+	if info.EncryptionSalt == "" {
+		return nil, errors.New("the stack does not use the passphrase secrets provider")
+	}
+	// The new data key can only be wrapped for the passphrase that's given, so a keyring with other passphrases
+	// would lose them.
+	if strings.HasPrefix(info.EncryptionSalt, keyringVersion+":") {
+		keyring, err := parseKeyring(info.EncryptionSalt)
+		if err != nil {
+			return nil, err
+		}
+		if len(keyring) > 1 {
+			return nil, fmt.Errorf("the stack has %d passphrases; remove all but one before rotating its data key, "+
+				"and add them again afterwards", len(keyring))
+		}
+	}
+	phrase, _, err := promptForPassphrase(info.EncryptionSalt)
+	if err != nil {
+		return nil, err
+	}
+
+	state, sm, err := NewPassphraseSecretsManager(phrase)
+	if err != nil {
+		return nil, err
+	}
+	setCachedSecretsManager(state, sm)
+	info.EncryptionSalt = state
+	return sm, nil
+}
+
 // NewPassphraseSecretsManager returns a new passphrase-based secrets manager, from the
 // given state. Will use the passphrase found in PULUMI_CONFIG_PASSPHRASE, the file specified by
 // PULUMI_CONFIG_PASSPHRASE_FILE, or otherwise will prompt for the passphrase if interactive.
@@ -299,40 +364,34 @@ func NewPromptingPassphraseSecretsManager(info *workspace.ProjectStack,
 // promptForNewPassphrase prompts for a new passphrase, and returns the state and the secrets manager.
 func promptForNewPassphrase(rotate bool) (string, secrets.Manager, error) {
 	var phrase string
-
-	// Get a the passphrase from the user, ensuring that they match.
-	for {
-		firstMessage := "Enter your passphrase to protect config/secrets"
-		if rotate {
-			firstMessage = "Enter your new passphrase to protect config/secrets"
-
-			if !isInteractive() {
-				scanner := bufio.NewScanner(os.Stdin)
-				scanner.Scan()
-				phrase = strings.TrimSpace(scanner.Text())
-				break
-			}
-		}
-		// Here, the stack does not have an EncryptionSalt, so we will get a passphrase and create one
-		first, _, err := readPassphrase(firstMessage, !rotate)
-		if err != nil {
-			return "", nil, err
-		}
-		secondMessage := "Re-enter your passphrase to confirm"
-		if rotate {
-			secondMessage = "Re-enter your new passphrase to confirm"
-		}
-		second, _, err := readPassphrase(secondMessage, !rotate)
+	if rotate {
+		// The new passphrase must not be read from the environment, which holds the current one.
+		p, err := readOtherPassphrase(
+			"Enter your new passphrase to protect config/secrets", "Re-enter your new passphrase to confirm")
 		if err != nil {
 			return "", nil, err
 		}
+		phrase = p
+	} else {
+		// Get a the passphrase from the user, ensuring that they match.
+		for {
+			// Here, the stack does not have an EncryptionSalt, so we will get a passphrase and create one
+			first, _, err := readPassphrase("Enter your passphrase to protect config/secrets", true /*useEnv*/)
+			if err != nil {
+				return "", nil, err
+			}
+			second, _, err := readPassphrase("Re-enter your passphrase to confirm", true /*useEnv*/)
+			if err != nil {
+				return "", nil, err
+			}
 
-		if first == second {
-			phrase = first
-			break
+			if first == second {
+				phrase = first
+				break
+			}
+			// If they didn't match, print an error and try again
+			cmdutil.Diag().Errorf(diag.Message("", "passphrases do not match"))
 		}
-		// If they didn't match, print an error and try again
-		cmdutil.Diag().Errorf(diag.Message("", "passphrases do not match"))
 	}
 
 	state, sm, err := NewPassphraseSecretsManager(phrase)
@@ -1,10 +1,12 @@
 package passphrase
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"testing"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -129,3 +131,39 @@ func TestPassphraseManagerEmptyPassfileReturnsError(t *testing.T) {
 	assert.ErrorContains(t, err, "passphrase must be set with "+
 		"PULUMI_CONFIG_PASSPHRASE or PULUMI_CONFIG_PASSPHRASE_FILE environment variables")
 }
+
+//nolint:paralleltest // mutates environment variables
+func TestRotatePassphraseSecretsManager(t *testing.T) {
+	resetEnv := resetPassphraseTestEnvVars()
+	defer resetEnv()
+
+	os.Setenv("PULUMI_CONFIG_PASSPHRASE", "password")
+	os.Unsetenv("PULUMI_CONFIG_PASSPHRASE_FILE")
+
+	info := &workspace.ProjectStack{EncryptionSalt: "v1:fozI5u6B030=:v1:F+6ZduKKd8G0/V7L:PGMFeIzwobWRKmEAzUdaQHqC5mMRIQ=="}
+	oldSM, err := NewPromptingPassphraseSecretsManagerFromState([]byte(state))
+	require.NoError(t, err)
+	oldEnc, err := oldSM.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := oldEnc.EncryptValue(context.Background(), "secret")
+	require.NoError(t, err)
+
+	sm, err := RotatePassphraseSecretsManager(info)
+	require.NoError(t, err)
+	assert.NotEqual(t, "v1:fozI5u6B030=:v1:F+6ZduKKd8G0/V7L:PGMFeIzwobWRKmEAzUdaQHqC5mMRIQ==", info.EncryptionSalt)
+
+	// The new salt is for the same passphrase, but values encrypted with the old key can't be decrypted with the new.
+	clearCachedSecretsManagers()
+	sm2, err := NewPromptingPassphraseSecretsManagerFromState(sm.State())
+	require.NoError(t, err)
+	assert.NotEqual(t, &errorCrypter{}, sm2.(*localSecretsManager).crypter)
+	dec, err := sm2.Decrypter()
+	require.NoError(t, err)
+	_, err = dec.DecryptValue(context.Background(), ciphertext)
+	assert.Error(t, err)
+
+	// The current passphrase is required to rotate the key.
+	os.Setenv("PULUMI_CONFIG_PASSPHRASE", "wrong")
+	_, err = RotatePassphraseSecretsManager(info)
+	assert.ErrorIs(t, err, ErrIncorrectPassphrase)
+}
@@ -0,0 +1,169 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pgp implements a gocloud.dev secrets keeper that encrypts to a list of OpenPGP keys, so that a stack's
+// data key can be shared between the holders of PGP keys rather than a passphrase or a cloud key management
+// service.
+//
+// URLs have the form pgp://?recipient=<fingerprint>&recipient=<fingerprint>, where each fingerprint is the full
+// fingerprint of the exact key to encrypt to, which is usually an encryption subkey, as listed by
+// gpg --list-keys --with-subkey-fingerprints. Encryption and decryption are done by gpg, so the recipients' public
+// keys must be in the user's keyring, and decrypting uses the gpg agent. The recipients are stored along with the
+// ciphertexts the keeper produces, so that a stack's secrets provider URL doesn't need to list them: the URL pgp://
+// can decrypt any of them, and the recipients of an existing ciphertext can be read with CiphertextRecipients.
+package pgp
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"gocloud.dev/gcerrors"
+	gosecrets "gocloud.dev/secrets"
+)
+
+// Scheme is the URL scheme this keeper is registered under.
+const Scheme = "pgp"
+
+// GPGEnvVar is the environment variable that names the gpg executable to use.
+const GPGEnvVar = "PULUMI_GPG"
+
+// URLOpener opens PGP keepers from URLs of the form pgp://?recipient=<fingerprint>. It is registered under Scheme
+// by the programs that support PGP secrets providers.
+type URLOpener struct{}
+
+// OpenKeeperURL opens a keeper that encrypts to the recipients listed in the URL.
+func (o *URLOpener) OpenKeeperURL(ctx context.Context, u *url.URL) (*gosecrets.Keeper, error) {
+	recipients, err := Recipients(u)
+	if err != nil {
+		return nil, fmt.Errorf("open keeper %v: %w", u, err)
+	}
+	return gosecrets.NewKeeper(&keeper{recipients: recipients}), nil
+}
+
+// Recipients returns the key fingerprints listed in a pgp:// URL, in upper case. A URL without any recipients can
+// only be used to decrypt.
+func Recipients(u *url.URL) ([]string, error) {
+	if u.Host != "" || (u.Path != "" && u.Path != "/") {
+		return nil, errors.New("PGP recipients must be given with the recipient query parameter")
+	}
+	var recipients []string
+	for param, values := range u.Query() {
+		if param != "recipient" {
+			return nil, fmt.Errorf("invalid query parameter %q", param)
+		}
+		for _, value := range values {
+			if !isFingerprint(value) {
+				return nil, fmt.Errorf("invalid recipient %q: recipients must be full 40-digit key fingerprints", value)
+			}
+			recipients = append(recipients, strings.ToUpper(value))
+		}
+	}
+	return recipients, nil
+}
+
+// URL returns the pgp:// URL that lists the given recipients.
+func URL(recipients []string) string {
+	return Scheme + "://?" + url.Values{"recipient": recipients}.Encode()
+}
+
+// isFingerprint returns true if the given string is the full fingerprint of a version 4 key.
+func isFingerprint(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// envelope is the ciphertext produced by a keeper: the message encrypted by gpg, and the fingerprints of the keys it
+// was encrypted to.
+type envelope struct {
+	Recipients []string `json:"recipients"`
+	Message    []byte   `json:"message"`
+}
+
+// CiphertextRecipients returns the fingerprints of the keys that a ciphertext produced by a keeper was encrypted to.
+func CiphertextRecipients(ciphertext []byte) ([]string, error) {
+	var env envelope
+	if err := json.Unmarshal(ciphertext, &env); err != nil {
+		return nil, fmt.Errorf("invalid PGP ciphertext: %w", err)
+	}
+	return env.Recipients, nil
+}
+
+// gpg runs gpg with the given arguments and input, and returns its output.
+func gpg(ctx context.Context, input []byte, args ...string) ([]byte, error) {
+	path := os.Getenv(GPGEnvVar)
+	if path == "" {
+		path = "gpg"
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w\n%s", path, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+type keeper struct {
+	recipients []string
+}
+
+func (k *keeper) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	if len(k.recipients) == 0 {
+		return nil, errors.New("at least one recipient is required to encrypt; " +
+			"list them with pgp://?recipient=<fingerprint>")
+	}
+
+	// The recipients are pinned to the exact keys with their full fingerprints, so their keys are trusted as given
+	// rather than through the keyring's web of trust.
+	args := []string{"--batch", "--no-tty", "--trust-model", "always", "--encrypt"}
+	for _, recipient := range k.recipients {
+		args = append(args, "--recipient", "0x"+recipient+"!")
+	}
+	message, err := gpg(ctx, plaintext, args...)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(envelope{Recipients: k.recipients, Message: message})
+}
+
+func (k *keeper) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	var env envelope
+	if err := json.Unmarshal(ciphertext, &env); err != nil {
+		return nil, fmt.Errorf("invalid PGP ciphertext: %w", err)
+	}
+	return gpg(ctx, env.Message, "--use-agent", "--quiet", "--decrypt")
+}
+
+func (k *keeper) Close() error { return nil }
+
+func (k *keeper) ErrorAs(err error, i interface{}) bool { return false }
+
+func (k *keeper) ErrorCode(error) gcerrors.ErrorCode { return gcerrors.Unknown }
@@ -0,0 +1,108 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgp
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gosecrets "gocloud.dev/secrets"
+)
+
+func init() {
+	// The CLI registers the keeper, so register it for the tests too.
+	gosecrets.DefaultURLMux().RegisterKeeper(Scheme, &URLOpener{})
+}
+
+//nolint:paralleltest // sets GNUPGHOME
+func TestKeeper(t *testing.T) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+	ctx := context.Background()
+
+	// Generate a key without a passphrase in a fresh keyring.
+	home, err := os.MkdirTemp("", "gnupg")
+	require.NoError(t, err)
+	t.Setenv("GNUPGHOME", home)
+	t.Cleanup(func() {
+		_ = exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+		os.RemoveAll(home)
+	})
+	_, err = gpg(ctx, nil, "--batch", "--passphrase", "", "--quick-generate-key", "Test <test@example.com>")
+	require.NoError(t, err)
+	keys, err := gpg(ctx, nil, "--list-keys", "--with-colons")
+	require.NoError(t, err)
+	// Encrypt to the key's encryption subkey, whose fingerprint follows it.
+	var fingerprint string
+	subkey := false
+	for _, line := range strings.Split(string(keys), "\n") {
+		switch fields := strings.Split(line, ":"); {
+		case fields[0] == "sub":
+			subkey = strings.Contains(fields[11], "e")
+		case fields[0] == "fpr" && subkey && fingerprint == "":
+			fingerprint = fields[9]
+		}
+	}
+	require.NotEmpty(t, fingerprint)
+
+	keeper, err := gosecrets.OpenKeeper(ctx, "pgp://?recipient="+strings.ToLower(fingerprint))
+	require.NoError(t, err)
+	ciphertext, err := keeper.Encrypt(ctx, []byte("data key"))
+	require.NoError(t, err)
+	recipients, err := CiphertextRecipients(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, []string{fingerprint}, recipients)
+
+	// The recipients are stored with the ciphertext, so a keeper without any can decrypt it, but not encrypt.
+	keeper, err = gosecrets.OpenKeeper(ctx, "pgp://")
+	require.NoError(t, err)
+	plaintext, err := keeper.Decrypt(ctx, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "data key", string(plaintext))
+	_, err = keeper.Encrypt(ctx, []byte("data key"))
+	assert.ErrorContains(t, err, "at least one recipient is required")
+}
+
+func TestKeeperURLs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fingerprint := strings.Repeat("0123456789", 4)
+	for _, url := range []string{
+		"pgp://ABCDEF",
+		"pgp://?recipient=--armor",
+		"pgp://?recipient=alice@example.com",
+		"pgp://?recipient=ABCDEF",
+		"pgp://?recipient=0x" + fingerprint,
+		"pgp://?recipient=" + fingerprint + "!",
+		"pgp://?key=" + fingerprint,
+	} {
+		_, err := gosecrets.OpenKeeper(ctx, url)
+		assert.Error(t, err, url)
+	}
+
+	u, err := url.Parse(URL([]string{fingerprint, strings.Repeat("ABCDEF0123", 4)}))
+	require.NoError(t, err)
+	recipients, err := Recipients(u)
+	require.NoError(t, err)
+	assert.Equal(t, []string{fingerprint, strings.Repeat("ABCDEF0123", 4)}, recipients)
+}
@@ -538,6 +538,17 @@ func (l *LocalWorkspace) ChangeStackSecretsProvider(
 	return nil
 }
 
+// RotateStackSecrets encrypts the secrets of the given stack with a new data key from its current secrets provider.
+// For a stack that uses the `passphrase` provider, the stack's passphrase must be set in the workspace's
+// environment.
+func (l *LocalWorkspace) RotateStackSecrets(ctx context.Context, stackName string) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx, "stack", "rotate-secrets", "--stack", stackName)
+	if err != nil {
+		return newAutoError(fmt.Errorf("failed to rotate secrets: %w", err), stdout, stderr, errCode)
+	}
+	return nil
+}
+
 // CreateStack creates and sets a new stack with the stack name, failing if one already exists.
 func (l *LocalWorkspace) CreateStack(ctx context.Context, stackName string) error {
 	args := []string{"stack", "init", stackName}
@@ -144,6 +144,13 @@ func TestWorkspaceSecretsProvider(t *testing.T) {
 	assert.Equal(t, passwordVal, conf.Value)
 	assert.Equal(t, true, conf.Secret)
 
+	// -- rotate secrets --
+	err = s.RotateSecrets(ctx)
+	require.NoError(t, err)
+	conf, err = s.GetConfig(ctx, "MySecretDatabasePassword")
+	require.NoError(t, err)
+	assert.Equal(t, passwordVal, conf.Value)
+
 	// -- change passphrase --
 	newPassphrase := "newpassphrase"
 	err = s.Workspace().ChangeStackSecretsProvider(ctx, s.Name(), "passphrase", &ChangeSecretsProviderOptions{
@@ -215,6 +215,21 @@ func (s *Stack) ChangeSecretsProvider(
 	return s.workspace.ChangeStackSecretsProvider(ctx, s.stackName, newSecretsProvider, opts)
 }
 
+// RotateSecrets encrypts the stack's secrets with a new data key from its current secrets provider.
+// For a stack that uses the `passphrase` provider, the stack's passphrase must be set in the workspace's
+// environment.
+func (s *Stack) RotateSecrets(ctx context.Context) error {
+	stdout, stderr, errCode, err := s.runPulumiCmdSync(
+		ctx,
+		nil, /* additionalOutput */
+		nil, /* additionalErrorOutput */
+		"stack", "rotate-secrets")
+	if err != nil {
+		return newAutoError(fmt.Errorf("failed to rotate secrets: %w", err), stdout, stderr, errCode)
+	}
+	return nil
+}
+
 // Preview preforms a dry-run update to a stack, returning pending changes.
 // https://www.pulumi.com/docs/cli/commands/pulumi_preview/
 func (s *Stack) Preview(ctx context.Context, opts ...optpreview.Option) (PreviewResult, error) {
@@ -146,6 +146,12 @@ type StepEventMetadata struct {
 	Logical bool `json:"logical,omitempty"`
 	// Provider actually performing the step.
 	Provider string `json:"provider"`
+	// Undeclared is set if the step is a refresh that evaluated the program, and the program no longer declares the
+	// resource.
+	Undeclared bool `json:"undeclared,omitempty"`
+	// Drifted is set if the step is a refresh that evaluated the program, and the inputs that were read differ from the
+	// inputs that the program declares.
+	Drifted bool `json:"drifted,omitempty"`
 }
 
 // StepEventStateMetadata is the more detailed state information for a resource as it relates to
@@ -197,6 +203,16 @@ type ResOpFailedEvent struct {
 	Steps    int               `json:"steps"`
 }
 
+// ResOpRetryEvent is emitted when an attempt at a resource operation fails with an error that the
+// resource's retry policy allows to be retried, before the engine waits to try the operation again.
+type ResOpRetryEvent struct {
+	Metadata     StepEventMetadata `json:"metadata"`
+	Attempt      int               `json:"attempt"`
+	MaxAttempts  int               `json:"maxAttempts"`
+	DelaySeconds float64           `json:"delaySeconds"`
+	Error        string            `json:"error"`
+}
+
 // PolicyLoadEvent is emitted when a policy starts loading
 type PolicyLoadEvent struct{}
 
@@ -224,6 +240,7 @@ type EngineEvent struct {
 	ResourcePreEvent       *ResourcePreEvent       `json:"resourcePreEvent,omitempty"`
 	ResOutputsEvent        *ResOutputsEvent        `json:"resOutputsEvent,omitempty"`
 	ResOpFailedEvent       *ResOpFailedEvent       `json:"resOpFailedEvent,omitempty"`
+	ResOpRetryEvent        *ResOpRetryEvent        `json:"resOpRetryEvent,omitempty"`
 	PolicyEvent            *PolicyEvent            `json:"policyEvent,omitempty"`
 	PolicyRemediationEvent *PolicyRemediationEvent `json:"policyRemediationEvent,omitempty"`
 	PolicyLoadEvent        *PolicyLoadEvent        `json:"policyLoadEvent,omitempty"`
@@ -66,6 +66,20 @@ type ResourcePlanV1 struct {
 	Outputs map[string]interface{} `json:"state"`
 	// The random byte seed used for resource goal.
 	Seed []byte `json:"seed,omitempty"`
+	// The IDs of the existing resources that the delete steps are expected to delete.
+	DeleteIDs []resource.ID `json:"deleteIDs,omitempty"`
+	// The expected results of the refresh steps, one per existing resource.
+	Refreshes []RefreshPlanV1 `json:"refreshes,omitempty"`
+}
+
+// RefreshPlanV1 is the serializable version of the planned refresh of a single existing resource.
+type RefreshPlanV1 struct {
+	// the ID of the resource to refresh.
+	ID resource.ID `json:"id"`
+	// the operation that the refresh is expected to result in: same, update, or delete.
+	Result OpType `json:"result"`
+	// the resource outputs that the refresh is expected to change.
+	OutputDiff PlanDiffV1 `json:"outputDiff,omitempty"`
 }
 
 // VersionedDeploymentPlan is a version number plus a JSON document. The version number describes what
@@ -86,4 +100,22 @@ type DeploymentPlanV1 struct {
 
 	// The set of resource plans.
 	ResourcePlans map[resource.URN]ResourcePlanV1 `json:"resourcePlans,omitempty"`
+
+	// The name of the project that the plan was made for, if recorded.
+	Project tokens.PackageName `json:"project,omitempty"`
+	// The fully qualified name of the stack that the plan was made for, if recorded.
+	Stack string `json:"stack,omitempty"`
+	// An optional signature over the rest of the plan.
+	Signature *PlanSignatureV1 `json:"signature,omitempty"`
+}
+
+// PlanSignatureV1 is a signature over a deployment plan.
+type PlanSignatureV1 struct {
+	// The signature algorithm. Only "ed25519" is supported.
+	Algorithm string `json:"algorithm"`
+	// The public key of the signer. This only identifies the key that made the signature: a plan must be verified
+	// with a key that the reader already trusts, never with this one.
+	PublicKey []byte `json:"publicKey"`
+	// The signature itself.
+	Signature []byte `json:"signature"`
 }
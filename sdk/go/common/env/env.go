@@ -105,6 +105,18 @@ var (
 	DIYBackendDisableCheckpointBackups = env.Bool("DIY_BACKEND_DISABLE_CHECKPOINT_BACKUPS",
 		"If set checkpoint backups will not be written the to the backup folder.",
 		env.Alternative("DISABLE_CHECKPOINT_BACKUPS"))
+
+	DIYBackendLockLease = env.String("DIY_BACKEND_LOCK_LEASE",
+		"The lease duration of stack locks, for example '5m'. A lock that has not been renewed "+
+			"within its lease may be reclaimed by another process. Must be at least 1s. Defaults to 5m.")
+
+	DIYBackendJournal = env.Bool("DIY_BACKEND_JOURNAL",
+		"If set, updates append incremental journal entries to the state instead of rewriting the "+
+			"whole checkpoint after every step.")
+
+	DIYBackendJournalCompactThreshold = env.Int("DIY_BACKEND_JOURNAL_COMPACT_THRESHOLD",
+		"The number of journal entries after which the journal is compacted into a full checkpoint. "+
+			"Defaults to 100.")
 )
 
 // Environment variables which affect Pulumi AI integrations
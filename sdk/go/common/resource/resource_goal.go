@@ -43,7 +43,8 @@ type Goal struct {
 	// if set, the providers Delete method will not be called for this resource
 	// if specified resource is being deleted as well.
 	DeletedWith    URN
-	SourcePosition string // If set, the source location of the resource registration
+	SourcePosition string       // If set, the source location of the resource registration
+	RetryPolicy    *RetryPolicy // If set, how to retry provider operations on the resource that fail
 }
 
 // NewGoal allocates a new resource goal state.
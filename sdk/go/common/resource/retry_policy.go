@@ -0,0 +1,129 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// DefaultRetryBackoff is the delay before the first retry of a policy that doesn't set one.
+const DefaultRetryBackoff = time.Second
+
+// DefaultRetryOn is the set of gRPC status codes that a policy retries if it doesn't list any. These are the codes
+// that providers use for throttling and other transient failures.
+var DefaultRetryOn = []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded}
+
+// DefaultCreateRetryOn is the set of gRPC status codes that a policy retries for creates if it doesn't list any. An
+// aborted or timed out create may still have created the resource, so only the codes that mean that the provider
+// rejected the request are retried, to avoid creating a resource twice.
+var DefaultCreateRetryOn = []codes.Code{codes.Unavailable, codes.ResourceExhausted}
+
+// maxRetryBackoff is the maximum delay between retries of a policy that doesn't set one.
+const maxRetryBackoff = time.Hour
+
+// RetryPolicy describes how the engine retries provider operations on a resource that fail with transient errors.
+type RetryPolicy struct {
+	MaxAttempts int           // the maximum number of attempts at each operation, including the first.
+	Backoff     time.Duration // the delay before the first retry, doubled for each later retry.
+	MaxBackoff  time.Duration // the maximum delay between retries, or zero for no maximum.
+	RetryOn     []codes.Code  // the status codes of the errors to retry, or nil for the defaults.
+}
+
+// ParseRetryPolicy creates a retry policy from its serialized form, where durations are strings such as "5s" and
+// status codes are names such as "Unavailable" or "RESOURCE_EXHAUSTED". Unset fields take their default values.
+func ParseRetryPolicy(maxAttempts int, backoff, maxBackoff string, retryOn []string) (*RetryPolicy, error) {
+	if maxAttempts < 1 {
+		return nil, fmt.Errorf("maxAttempts must be at least 1, got %d", maxAttempts)
+	}
+
+	policy := &RetryPolicy{MaxAttempts: maxAttempts, Backoff: DefaultRetryBackoff}
+	if backoff != "" {
+		d, err := time.ParseDuration(backoff)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid backoff %q: expected a duration such as 5s", backoff)
+		}
+		policy.Backoff = d
+	}
+	if maxBackoff != "" {
+		d, err := time.ParseDuration(maxBackoff)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid maxBackoff %q: expected a duration such as 1m", maxBackoff)
+		}
+		policy.MaxBackoff = d
+	}
+	if len(retryOn) > 0 {
+		policy.RetryOn = make([]codes.Code, len(retryOn))
+		for i, name := range retryOn {
+			code, err := ParseRetryCode(name)
+			if err != nil {
+				return nil, err
+			}
+			policy.RetryOn[i] = code
+		}
+	}
+	return policy, nil
+}
+
+// ParseRetryCode returns the gRPC status code with the given name. Both the "ResourceExhausted" and
+// "RESOURCE_EXHAUSTED" forms of a name are accepted.
+func ParseRetryCode(name string) (codes.Code, error) {
+	normalized := strings.ReplaceAll(name, "_", "")
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if strings.EqualFold(code.String(), normalized) {
+			return code, nil
+		}
+	}
+	return codes.Unknown, fmt.Errorf("unknown error class %q: expected the name of a gRPC status code", name)
+}
+
+// ShouldRetry returns true if the policy retries errors with the given status code. If the policy doesn't list any
+// codes, creates are retried on DefaultCreateRetryOn and other operations on DefaultRetryOn.
+func (p *RetryPolicy) ShouldRetry(code codes.Code, create bool) bool {
+	retryOn := p.RetryOn
+	if retryOn == nil {
+		retryOn = DefaultRetryOn
+		if create {
+			retryOn = DefaultCreateRetryOn
+		}
+	}
+	for _, c := range retryOn {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns how long to wait before the given attempt, where the first retry is attempt 2. The delay is never
+// more than the policy's MaxBackoff, or an hour if it doesn't set one.
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = maxRetryBackoff
+	}
+
+	delay := p.Backoff
+	for i := 2; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,87 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestParseRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParseRetryPolicy(3, "", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, &RetryPolicy{MaxAttempts: 3, Backoff: DefaultRetryBackoff}, policy)
+
+	policy, err = ParseRetryPolicy(5, "2s", "5s", []string{"Unavailable", "RESOURCE_EXHAUSTED", "failedPrecondition"})
+	require.NoError(t, err)
+	assert.Equal(t, &RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     2 * time.Second,
+		MaxBackoff:  5 * time.Second,
+		RetryOn:     []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.FailedPrecondition},
+	}, policy)
+
+	_, err = ParseRetryPolicy(0, "", "", nil)
+	assert.ErrorContains(t, err, "maxAttempts must be at least 1")
+	_, err = ParseRetryPolicy(2, "soon", "", nil)
+	assert.ErrorContains(t, err, `invalid backoff "soon"`)
+	_, err = ParseRetryPolicy(2, "", "-1m", nil)
+	assert.ErrorContains(t, err, `invalid maxBackoff "-1m"`)
+	_, err = ParseRetryPolicy(2, "", "", []string{"Throttled"})
+	assert.ErrorContains(t, err, `unknown error class "Throttled"`)
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{MaxAttempts: 10, Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	delays := make([]time.Duration, 0, 5)
+	for attempt := 2; attempt <= 6; attempt++ {
+		delays = append(delays, policy.Delay(attempt))
+	}
+	assert.Equal(t, []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second,
+	}, delays)
+
+	policy.MaxBackoff = 0
+	assert.Equal(t, 64*time.Second, policy.Delay(8))
+	assert.Equal(t, time.Hour, policy.Delay(100))
+	assert.Equal(t, time.Hour, policy.Delay(math.MaxInt))
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{MaxAttempts: 3, Backoff: time.Second}
+	assert.True(t, policy.ShouldRetry(codes.Unavailable, false))
+	assert.True(t, policy.ShouldRetry(codes.DeadlineExceeded, false))
+	assert.True(t, policy.ShouldRetry(codes.Unavailable, true))
+	assert.True(t, policy.ShouldRetry(codes.ResourceExhausted, true))
+	assert.False(t, policy.ShouldRetry(codes.DeadlineExceeded, true))
+	assert.False(t, policy.ShouldRetry(codes.Aborted, true))
+	assert.False(t, policy.ShouldRetry(codes.InvalidArgument, false))
+
+	// A policy that lists its codes retries them for every operation.
+	policy.RetryOn = []codes.Code{codes.Aborted}
+	assert.True(t, policy.ShouldRetry(codes.Aborted, true))
+	assert.False(t, policy.ShouldRetry(codes.Unavailable, false))
+}
@@ -59,6 +59,21 @@ func validateStackConfigValue(
 	projectConfigType ProjectConfigType,
 	stackValue config.Value,
 	dec config.Decrypter,
+) error {
+	if err := checkStackConfigValue(projectConfigKey, projectConfigType, stackValue, dec); err != nil {
+		return fmt.Errorf("Stack '%v' with %w", stackName, err)
+	}
+	return nil
+}
+
+// checkStackConfigValue checks a stack config value, whether it was set by the stack or by one of its environments,
+// against the secretness, type and constraints that the project declares for its key. The error it returns starts
+// with "configuration key" followed by the key, so that callers can say where the value comes from.
+func checkStackConfigValue(
+	projectConfigKey string,
+	projectConfigType ProjectConfigType,
+	stackValue config.Value,
+	dec config.Decrypter,
 ) error {
 	if dec == nil {
 		return nil
@@ -67,35 +82,78 @@ func validateStackConfigValue(
 	// First check if the project says this should be secret, and if so that the stack value is
 	// secure.
 	if projectConfigType.Secret && !stackValue.Secure() {
-		validationError := fmt.Errorf(
-			"Stack '%v' with configuration key '%v' must be encrypted as it's secret",
-			stackName,
-			projectConfigKey)
-		return validationError
+		return fmt.Errorf("configuration key '%v' must be encrypted as it's secret", projectConfigKey)
+	}
+
+	content, err := stackConfigContent(stackValue, dec)
+	if err != nil {
+		return fmt.Errorf("configuration key '%v': %w", projectConfigKey, err)
 	}
 
+	if !ValidateConfigValue(*projectConfigType.Type, projectConfigType.Items, content) {
+		return fmt.Errorf("configuration key '%v' must be of type '%v'",
+			projectConfigKey, InferFullTypeName(*projectConfigType.Type, projectConfigType.Items))
+	}
+
+	err = ValidateConfigConstraints(projectConfigKey, *projectConfigType.Type, projectConfigType.Items,
+		&projectConfigType.ProjectConfigConstraints, content)
+	if err != nil {
+		return fmt.Errorf("configuration key %w", err)
+	}
+
+	return nil
+}
+
+// stackConfigContent decrypts a stack config value, and unmarshals it if it is an object.
+func stackConfigContent(stackValue config.Value, dec config.Decrypter) (interface{}, error) {
 	value, err := stackValue.Value(dec)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// Content will be a JSON string if object is true, so marshal that back into an actual structure
 	var content interface{} = value
 	if stackValue.Object() {
 		err = json.Unmarshal([]byte(value), &content)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
+	return content, nil
+}
 
-	if !ValidateConfigValue(*projectConfigType.Type, projectConfigType.Items, content) {
-		typeName := InferFullTypeName(*projectConfigType.Type, projectConfigType.Items)
-		validationError := fmt.Errorf(
-			"Stack '%v' with configuration key '%v' must be of type '%v'",
-			stackName,
-			projectConfigKey,
-			typeName)
-
-		return validationError
+// ValidateStackConfigSchema checks the values that a stack's config sets for the explicitly typed keys of its project
+// against their types and constraints, and returns an error that names the first offending key. Keys that the stack
+// doesn't set are left to ValidateStackConfigAndApplyProjectConfig, which also handles values from environments.
+func ValidateStackConfigSchema(project *Project, stackConfig config.Map, decrypter config.Decrypter) error {
+	projectName := project.Name.String()
+
+	keys := make([]string, 0, len(project.Config))
+	for k := range project.Config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, projectConfigKey := range keys {
+		projectConfigType := project.Config[projectConfigKey]
+		if !projectConfigType.IsExplicitlyTyped() || !configKeyIsNamespacedByProject(projectName, projectConfigKey) {
+			continue
+		}
+
+		key, err := parseConfigKey(projectName, projectConfigKey)
+		if err != nil {
+			return err
+		}
+		stackValue, found, err := stackConfig.Get(key, true)
+		if err != nil {
+			return fmt.Errorf("getting stack config value for key '%v': %w", key.String(), err)
+		}
+		if !found {
+			continue
+		}
+
+		if err := checkStackConfigValue(projectConfigKey, projectConfigType, stackValue, decrypter); err != nil {
+			return err
+		}
 	}
 
 	return nil
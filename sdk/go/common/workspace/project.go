@@ -23,6 +23,9 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -46,6 +49,7 @@ const (
 	integerTypeName = "integer"
 	stringTypeName  = "string"
 	booleanTypeName = "boolean"
+	objectTypeName  = "object"
 )
 
 //go:embed project.json
@@ -108,6 +112,39 @@ type ProjectBackend struct {
 type ProjectOptions struct {
 	// Refresh is the ability to always run a refresh as part of a pulumi update / preview / destroy
 	Refresh string `json:"refresh,omitempty" yaml:"refresh,omitempty"`
+	// ParallelFor limits the number of concurrent resource operations for specific resource packages or type tokens.
+	ParallelFor map[string]int `json:"parallelFor,omitempty" yaml:"parallelFor,omitempty"`
+	// RetryPolicy is the policy for retrying failed provider operations on resources that don't set their own.
+	RetryPolicy *ProjectRetryPolicy `json:"retryPolicy,omitempty" yaml:"retryPolicy,omitempty"`
+	// SecretScanning checks plaintext config, resource inputs and outputs for values that look like secrets.
+	SecretScanning *ProjectSecretScanning `json:"secretScanning,omitempty" yaml:"secretScanning,omitempty"`
+	// PlanVerifyKey is the path, relative to the project, of the ed25519 public key that plan files must be signed with.
+	PlanVerifyKey string `json:"planVerifyKey,omitempty" yaml:"planVerifyKey,omitempty"`
+}
+
+// ProjectRetryPolicy describes how provider operations that fail with transient errors are retried.
+type ProjectRetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts at each operation, including the first.
+	MaxAttempts int `json:"maxAttempts" yaml:"maxAttempts"`
+	// Backoff is the delay before the first retry, such as "5s", which is doubled for each later retry.
+	Backoff string `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	// MaxBackoff is the maximum delay between retries, such as "1m".
+	MaxBackoff string `json:"maxBackoff,omitempty" yaml:"maxBackoff,omitempty"`
+	// RetryOn lists the names of the gRPC status codes to retry, such as "Unavailable".
+	RetryOn []string `json:"retryOn,omitempty" yaml:"retryOn,omitempty"`
+}
+
+// ProjectSecretScanning describes how plaintext values that look like secrets are found, and what is done with them.
+type ProjectSecretScanning struct {
+	// Action is what is done with plaintext values that look like secrets: "warn", "error" or "secret".
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+	// EntropyThreshold is the entropy, in bits, above which the values of keys that match KeyPattern look like secrets.
+	EntropyThreshold float64 `json:"entropyThreshold,omitempty" yaml:"entropyThreshold,omitempty"`
+	// KeyPattern is a regular expression that matches the names of the keys whose values' entropy is checked.
+	KeyPattern string `json:"keyPattern,omitempty" yaml:"keyPattern,omitempty"`
+	// Patterns are regular expressions for values that look like secrets whatever their key, on top of the built-in
+	// patterns for well-known token formats.
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
 }
 
 type PluginOptions struct {
@@ -122,9 +159,31 @@ type Plugins struct {
 	Analyzers []PluginOptions `json:"analyzers,omitempty" yaml:"analyzers,omitempty"`
 }
 
+// ProjectConfigConstraints are the JSON-Schema-style constraints that a project config value must satisfy on top of
+// its type.
+type ProjectConfigConstraints struct {
+	// Enum lists the values that the value must be one of.
+	Enum []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	// Pattern is a regular expression that a string value must match anywhere in it, unless anchored. It uses Go's RE2
+	// syntax rather than the ECMA-262 syntax of JSON Schema, so features such as lookarounds and backreferences are
+	// not supported.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Minimum is the smallest that an integer value may be.
+	Minimum *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	// Maximum is the largest that an integer value may be.
+	Maximum *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	// Properties are the types of the known properties of an object value.
+	Properties map[string]*ProjectConfigItemsType `json:"properties,omitempty" yaml:"properties,omitempty"`
+	// Required lists the properties that an object value must have.
+	Required []string `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
 type ProjectConfigItemsType struct {
-	Type  string                  `json:"type,omitempty" yaml:"type,omitempty"`
-	Items *ProjectConfigItemsType `json:"items,omitempty" yaml:"items,omitempty"`
+	Type        string                  `json:"type,omitempty" yaml:"type,omitempty"`
+	Description string                  `json:"description,omitempty" yaml:"description,omitempty"`
+	Items       *ProjectConfigItemsType `json:"items,omitempty" yaml:"items,omitempty"`
+
+	ProjectConfigConstraints `yaml:",inline"`
 }
 
 type ProjectConfigType struct {
@@ -134,6 +193,8 @@ type ProjectConfigType struct {
 	Default     interface{}             `json:"default,omitempty" yaml:"default,omitempty"`
 	Value       interface{}             `json:"value,omitempty" yaml:"value,omitempty"`
 	Secret      bool                    `json:"secret,omitempty" yaml:"secret,omitempty"`
+
+	ProjectConfigConstraints `yaml:",inline"`
 }
 
 // IsExplicitlyTyped returns whether the project config type is explicitly typed.
@@ -439,6 +500,11 @@ func ValidateConfigValue(typeName string, itemsType *ProjectConfigItemsType, val
 		return ok
 	}
 
+	if typeName == objectTypeName {
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+
 	items, isArray := value.([]interface{})
 
 	if !isArray || itemsType == nil {
@@ -457,6 +523,156 @@ func ValidateConfigValue(typeName string, itemsType *ProjectConfigItemsType, val
 	return true
 }
 
+// ValidateConfigConstraints checks that a config value, which is already known to be of the given type, satisfies the
+// given constraints, as well as those of its items and properties. Errors name the offending part of the value, using
+// the given path for the value itself. They never include the value, which may be a secret.
+func ValidateConfigConstraints(
+	path, typeName string, itemsType *ProjectConfigItemsType, constraints *ProjectConfigConstraints, value interface{},
+) error {
+	if len(constraints.Enum) > 0 {
+		found := false
+		for _, e := range constraints.Enum {
+			if configValuesEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("'%v' must be one of %v", path, formatConfigEnum(constraints.Enum))
+		}
+	}
+
+	if constraints.Pattern != "" {
+		re, err := regexp.Compile(constraints.Pattern)
+		if err != nil {
+			return fmt.Errorf("'%v' has an invalid pattern: %w", path, err)
+		}
+		if s, ok := value.(string); ok && !re.MatchString(s) {
+			return fmt.Errorf("'%v' must match the pattern '%v'", path, constraints.Pattern)
+		}
+	}
+
+	if constraints.Minimum != nil || constraints.Maximum != nil {
+		if n, ok := configValueNumber(value); ok {
+			if constraints.Minimum != nil && n < *constraints.Minimum {
+				return fmt.Errorf("'%v' must be at least %v", path, *constraints.Minimum)
+			}
+			if constraints.Maximum != nil && n > *constraints.Maximum {
+				return fmt.Errorf("'%v' must be at most %v", path, *constraints.Maximum)
+			}
+		}
+	}
+
+	switch value := value.(type) {
+	case []interface{}:
+		if typeName != arrayTypeName || itemsType == nil {
+			return nil
+		}
+		for i, item := range value {
+			err := ValidateConfigConstraints(fmt.Sprintf("%v[%d]", path, i),
+				itemsType.Type, itemsType.Items, &itemsType.ProjectConfigConstraints, item)
+			if err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		if typeName != objectTypeName {
+			return nil
+		}
+		for _, name := range constraints.Required {
+			if _, ok := value[name]; !ok {
+				return fmt.Errorf("'%v' is missing the required property '%v'", path, name)
+			}
+		}
+		names := maps.Keys(constraints.Properties)
+		sort.Strings(names)
+		for _, name := range names {
+			propertyValue, ok := value[name]
+			if !ok {
+				continue
+			}
+			propertyPath, propertyType := path+"."+name, constraints.Properties[name]
+			if !ValidateConfigValue(propertyType.Type, propertyType.Items, propertyValue) {
+				return fmt.Errorf("'%v' must be of type '%v'",
+					propertyPath, InferFullTypeName(propertyType.Type, propertyType.Items))
+			}
+			err := ValidateConfigConstraints(propertyPath,
+				propertyType.Type, propertyType.Items, &propertyType.ProjectConfigConstraints, propertyValue)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// configValuesEqual returns whether two config values are equal. Scalars are compared by their text, given that stack
+// config values for integers and booleans may be strings.
+func configValuesEqual(a, b interface{}) bool {
+	isScalar := func(v interface{}) bool {
+		switch v.(type) {
+		case string, int, float64, bool:
+			return true
+		default:
+			return false
+		}
+	}
+	if isScalar(a) && isScalar(b) {
+		return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// configValueNumber returns the number that a config value is, if it is one.
+func configValueNumber(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int:
+		return float64(value), true
+	case float64:
+		return value, true
+	case string:
+		n, err := strconv.ParseFloat(value, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// formatConfigEnum formats the allowed values of a config value for an error message.
+func formatConfigEnum(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprintf("'%v'", e)
+	}
+	return strings.Join(values, ", ")
+}
+
+// validateConfigPatterns checks that the patterns of a config type, and of its items and properties, are valid regular
+// expressions.
+func validateConfigPatterns(
+	path string, itemsType *ProjectConfigItemsType, constraints *ProjectConfigConstraints,
+) error {
+	if constraints.Pattern != "" {
+		if _, err := regexp.Compile(constraints.Pattern); err != nil {
+			return fmt.Errorf("The configuration key '%v' has an invalid pattern: %w", path, err)
+		}
+	}
+	if itemsType != nil {
+		err := validateConfigPatterns(path+"[]", itemsType.Items, &itemsType.ProjectConfigConstraints)
+		if err != nil {
+			return err
+		}
+	}
+	for name, propertyType := range constraints.Properties {
+		err := validateConfigPatterns(path+"."+name, propertyType.Items, &propertyType.ProjectConfigConstraints)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func configKeyIsNamespacedByProject(projectName string, configKey string) bool {
 	return !strings.Contains(configKey, ":") || strings.HasPrefix(configKey, projectName+":")
 }
@@ -484,6 +700,10 @@ func (proj *Project) Validate() error {
 					"but does not specify the underlying type via the 'items' attribute", configKey)
 			}
 
+			if err := validateConfigPatterns(configKey, configType.Items, &configType.ProjectConfigConstraints); err != nil {
+				return err
+			}
+
 			// when we have a config _type_ with a schema
 			if configType.IsExplicitlyTyped() && configType.Default != nil {
 				if !ValidateConfigValue(configTypeName, configType.Items, configType.Default) {
@@ -492,6 +712,12 @@ func (proj *Project) Validate() error {
 						configKey,
 						inferredTypeName)
 				}
+
+				err := ValidateConfigConstraints(configKey, configTypeName, configType.Items,
+					&configType.ProjectConfigConstraints, configType.Default)
+				if err != nil {
+					return fmt.Errorf("The default value specified for configuration key '%v' is invalid: %w", configKey, err)
+				}
 			}
 
 		} else {
@@ -718,6 +718,223 @@ config:
 	assert.ErrorContains(t, configError, "Stack 'dev' with configuration key 'values' must be of type 'array<string>'")
 }
 
+const constrainedConfigProjectYaml = `
+name: test
+runtime: dotnet
+config:
+  size:
+    type: string
+    description: The size of the instances.
+    enum: [small, large]
+    default: small
+  port:
+    type: integer
+    minimum: 1024
+    maximum: 65535
+  names:
+    type: array
+    items:
+      type: string
+      pattern: ^[a-z]+$
+  database:
+    type: object
+    description: The database to connect to.
+    required: [host]
+    properties:
+      host:
+        type: string
+        description: The host name of the database.
+      port:
+        type: integer
+        minimum: 1
+`
+
+func TestProjectLoadsConfigConstraints(t *testing.T) {
+	t.Parallel()
+
+	project, err := loadProjectFromText(t, constrainedConfigProjectYaml)
+	require.NoError(t, err)
+
+	size := project.Config["size"]
+	assert.Equal(t, "The size of the instances.", size.Description)
+	assert.Equal(t, []interface{}{"small", "large"}, size.Enum)
+
+	port := project.Config["port"]
+	require.NotNil(t, port.Minimum)
+	require.NotNil(t, port.Maximum)
+	assert.Equal(t, 1024.0, *port.Minimum)
+	assert.Equal(t, 65535.0, *port.Maximum)
+
+	assert.Equal(t, "^[a-z]+$", project.Config["names"].Items.Pattern)
+
+	database := project.Config["database"]
+	assert.Equal(t, []string{"host"}, database.Required)
+	require.Contains(t, database.Properties, "host")
+	assert.Equal(t, "The host name of the database.", database.Properties["host"].Description)
+	require.NotNil(t, database.Properties["port"].Minimum)
+}
+
+func TestProjectConfigConstraintsAreValidated(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name: "default not in enum",
+			config: `
+  size:
+    type: string
+    enum: [small, large]
+    default: medium`,
+			expected: "The default value specified for configuration key 'size' is invalid: " +
+				"'size' must be one of 'small', 'large'",
+		},
+		{
+			name: "default below minimum",
+			config: `
+  port:
+    type: integer
+    minimum: 1024
+    default: 80`,
+			expected: "The default value specified for configuration key 'port' is invalid: 'port' must be at least 1024",
+		},
+		{
+			name: "invalid pattern",
+			config: `
+  names:
+    type: array
+    items:
+      type: string
+      pattern: "["`,
+			expected: "The configuration key 'names[]' has an invalid pattern",
+		},
+		{
+			name: "unknown constraint",
+			config: `
+  size:
+    type: string
+    format: uri`,
+			expected: "additionalProperties 'format' not allowed",
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := loadProjectFromText(t, "name: test\nruntime: dotnet\nconfig:"+c.config)
+			assert.ErrorContains(t, err, c.expected)
+		})
+	}
+}
+
+func TestValidateStackConfigSchema(t *testing.T) {
+	t.Parallel()
+
+	project, err := loadProjectFromText(t, constrainedConfigProjectYaml)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name: "valid",
+			config: `
+  test:size: large
+  test:port: "8080"
+  test:names: [web, api]
+  test:database:
+    host: db.example.com
+    port: 5432`,
+		},
+		{
+			name:     "not in enum",
+			config:   "\n  test:size: medium",
+			expected: "configuration key 'size' must be one of 'small', 'large'",
+		},
+		{
+			name:     "wrong type",
+			config:   "\n  test:port: http",
+			expected: "configuration key 'port' must be of type 'integer'",
+		},
+		{
+			name:     "above maximum",
+			config:   "\n  test:port: 70000",
+			expected: "configuration key 'port' must be at most 65535",
+		},
+		{
+			name:     "item does not match pattern",
+			config:   "\n  test:names: [web, API]",
+			expected: "configuration key 'names[1]' must match the pattern '^[a-z]+$'",
+		},
+		{
+			name:     "missing required property",
+			config:   "\n  test:database:\n    port: 5432",
+			expected: "configuration key 'database' is missing the required property 'host'",
+		},
+		{
+			name:     "property below minimum",
+			config:   "\n  test:database:\n    host: db\n    port: 0",
+			expected: "configuration key 'database.port' must be at least 1",
+		},
+		{
+			name:     "property of the wrong type",
+			config:   "\n  test:database:\n    host: [db]",
+			expected: "configuration key 'database.host' must be of type 'string'",
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			stack, err := loadProjectStackFromText(t, project, "config:"+c.config)
+			require.NoError(t, err)
+
+			err = ValidateStackConfigSchema(project, stack.Config, config.NewPanicCrypter())
+			if c.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, c.expected)
+			}
+
+			// The same constraints are checked when the project config is applied to the stack's.
+			err = ValidateStackConfigAndApplyProjectConfig(context.Background(), "dev", project, esc.Value{},
+				stack.Config, config.NewPanicCrypter(), config.NewPanicCrypter())
+			if c.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "Stack 'dev' with "+c.expected)
+			}
+		})
+	}
+}
+
+// TestEnvironmentConfigConstraintsAreValidated tests that config values from a stack's environment are checked
+// against the project's constraints in the same way as the stack's own config values.
+func TestEnvironmentConfigConstraintsAreValidated(t *testing.T) {
+	t.Parallel()
+
+	project, err := loadProjectFromText(t, constrainedConfigProjectYaml)
+	require.NoError(t, err)
+
+	env := esc.NewValue(map[string]esc.Value{
+		"test:port":  esc.NewValue(json.Number("8080")),
+		"test:names": esc.NewValue([]esc.Value{esc.NewValue("web"), esc.NewValue("API")}),
+		"test:database": esc.NewValue(map[string]esc.Value{
+			"host": esc.NewValue("db.example.com"),
+		}),
+	})
+	err = ValidateStackConfigAndApplyProjectConfig(context.Background(), "dev", project, env,
+		config.Map{}, config.NewPanicCrypter(), config.NewPanicCrypter())
+	assert.EqualError(t, err, "Stack 'dev' with configuration key 'names[1]' must match the pattern '^[a-z]+$'")
+}
+
 func TestLoadingConfigIsRewrittenToStackConfigDir(t *testing.T) {
 	t.Parallel()
 	projectYaml := `
@@ -41,8 +41,11 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/internal"
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -250,6 +253,25 @@ func (ctx *Context) wait() error {
 	return nil
 }
 
+// signalAndWaitForShutdown tells the engine that the program has finished registering resources. If the program
+// registered callbacks, such as resource hooks, it then waits for the deployment to finish, so that the engine can
+// still call them when it deletes resources. Engines that don't support this return Unimplemented, in which case the
+// program finishes straight away.
+func (ctx *Context) signalAndWaitForShutdown() error {
+	ctx.state.callbacksLock.Lock()
+	hasCallbacks := ctx.state.callbacks != nil
+	ctx.state.callbacksLock.Unlock()
+	if !hasCallbacks {
+		return nil
+	}
+
+	_, err := ctx.state.monitor.SignalAndWaitForShutdown(ctx.ctx, &emptypb.Empty{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	return err
+}
+
 // Organization returns the current organization name.
 func (ctx *Context) Organization() string {
 	org := ctx.state.info.Organization
@@ -499,6 +521,96 @@ func (ctx *Context) registerTransform(t XResourceTransform) (*pulumirpc.Callback
 		return rpcRes, nil
 	}
 
+	return ctx.registerCallback(callback)
+}
+
+// registerResourceHooks registers the given resource hooks, returning nil if there are none.
+func (ctx *Context) registerResourceHooks(
+	hooks *ResourceHooks,
+) (*pulumirpc.RegisterResourceRequest_ResourceHooks, error) {
+	if hooks == nil {
+		return nil, nil
+	}
+
+	var err error
+	register := func(hooks []ResourceHook) []*pulumirpc.Callback {
+		if err != nil {
+			return nil
+		}
+		var cbs []*pulumirpc.Callback
+		cbs, err = slice.MapError(hooks, ctx.registerResourceHook)
+		return cbs
+	}
+	result := &pulumirpc.RegisterResourceRequest_ResourceHooks{
+		BeforeCreate:  register(hooks.BeforeCreate),
+		AfterCreate:   register(hooks.AfterCreate),
+		BeforeUpdate:  register(hooks.BeforeUpdate),
+		AfterUpdate:   register(hooks.AfterUpdate),
+		BeforeDelete:  register(hooks.BeforeDelete),
+		AfterDelete:   register(hooks.AfterDelete),
+		BeforeReplace: register(hooks.BeforeReplace),
+		AfterReplace:  register(hooks.AfterReplace),
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// registerResourceHook registers the given resource hook as a callback.
+func (ctx *Context) registerResourceHook(hook ResourceHook) (*pulumirpc.Callback, error) {
+	callback := func(innerCtx context.Context, req []byte) (proto.Message, error) {
+		var rpcReq pulumirpc.ResourceHookRequest
+		if err := proto.Unmarshal(req, &rpcReq); err != nil {
+			return nil, fmt.Errorf("unmarshaling request: %w", err)
+		}
+
+		unmarshal := func(props *structpb.Struct) (Map, error) {
+			if props == nil {
+				return nil, nil
+			}
+			properties, err := plugin.UnmarshalProperties(props, plugin.MarshalOptions{
+				KeepUnknowns:  true,
+				KeepSecrets:   true,
+				KeepResources: true,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("unmarshaling resource hook protobuf properties: %w", err)
+			}
+			return unmarshalPropertyMap(ctx, properties)
+		}
+
+		args := &ResourceHookArgs{
+			URN:       URN(rpcReq.Urn),
+			ID:        ID(rpcReq.Id),
+			Operation: rpcReq.Operation,
+			After:     rpcReq.After,
+		}
+		var err error
+		if args.OldInputs, err = unmarshal(rpcReq.OldInputs); err != nil {
+			return nil, err
+		}
+		if args.OldOutputs, err = unmarshal(rpcReq.OldOutputs); err != nil {
+			return nil, err
+		}
+		if args.NewInputs, err = unmarshal(rpcReq.NewInputs); err != nil {
+			return nil, err
+		}
+		if args.NewOutputs, err = unmarshal(rpcReq.NewOutputs); err != nil {
+			return nil, err
+		}
+
+		if err := hook(innerCtx, args); err != nil {
+			return &pulumirpc.ResourceHookResponse{Error: err.Error()}, nil
+		}
+		return &pulumirpc.ResourceHookResponse{}, nil
+	}
+
+	return ctx.registerCallback(callback)
+}
+
+// registerCallback starts up a callback server if not already running and registers the given callback.
+func (ctx *Context) registerCallback(callback callbackFunction) (*pulumirpc.Callback, error) {
 	err := func() error {
 		ctx.state.callbacksLock.Lock()
 		defer ctx.state.callbacksLock.Unlock()
@@ -1178,6 +1290,13 @@ func (ctx *Context) registerResource(
 			transforms = append(transforms, cb)
 		}
 
+		// Register the resource hooks
+		var hooks *pulumirpc.RegisterResourceRequest_ResourceHooks
+		hooks, err = ctx.registerResourceHooks(options.Hooks)
+		if err != nil {
+			return
+		}
+
 		// Prepare the inputs for an impending operation.
 		inputs, err = ctx.prepareResourceInputs(resource, props, t, options, resState, remote, custom)
 		if err != nil {
@@ -1237,8 +1356,10 @@ func (ctx *Context) registerResource(
 				ReplaceOnChanges:        inputs.replaceOnChanges,
 				RetainOnDelete:          inputs.retainOnDelete,
 				DeletedWith:             inputs.deletedWith,
+				RetryPolicy:             inputs.retryPolicy,
 				SourcePosition:          sourcePosition,
 				Transforms:              transforms,
+				Hooks:                   hooks,
 				SupportsResultReporting: true,
 			})
 			if err != nil {
@@ -1644,6 +1765,7 @@ type resourceInputs struct {
 	replaceOnChanges        []string
 	retainOnDelete          bool
 	deletedWith             string
+	retryPolicy             *pulumirpc.RegisterResourceRequest_RetryPolicy
 }
 
 func (ctx *Context) resolveAliasParent(alias Alias, spec *pulumirpc.Alias_Spec) error {
@@ -1910,6 +2032,7 @@ func (ctx *Context) prepareResourceInputs(res Resource, props Input, t string, o
 		replaceOnChanges:        resOpts.replaceOnChanges,
 		retainOnDelete:          opts.RetainOnDelete,
 		deletedWith:             string(deletedWithURN),
+		retryPolicy:             getRetryPolicy(opts.RetryPolicy),
 	}, nil
 }
 
@@ -1923,6 +2046,18 @@ func getTimeouts(custom *CustomTimeouts) *pulumirpc.RegisterResourceRequest_Cust
 	return &timeouts
 }
 
+func getRetryPolicy(policy *RetryPolicy) *pulumirpc.RegisterResourceRequest_RetryPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &pulumirpc.RegisterResourceRequest_RetryPolicy{
+		MaxAttempts: int32(policy.MaxAttempts),
+		Backoff:     policy.Backoff,
+		MaxBackoff:  policy.MaxBackoff,
+		RetryOn:     policy.RetryOn,
+	}
+}
+
 // Helper struct for the return type of `getOpts`.
 type resourceOpts struct {
 	parentURN               URN
@@ -268,6 +268,12 @@ func (m *mockMonitor) RegisterStackTransform(ctx context.Context, in *pulumirpc.
 	panic("not implemented")
 }
 
+func (m *mockMonitor) SignalAndWaitForShutdown(ctx context.Context, in *emptypb.Empty,
+	opts ...grpc.CallOption,
+) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
 type mockEngine struct {
 	logger       *log.Logger
 	rootResource string
@@ -309,6 +309,26 @@ type CustomTimeouts struct {
 	Delete string
 }
 
+// RetryPolicy specifies how the engine retries provider operations on a resource
+// that fail with transient errors, such as throttling by the cloud provider.
+// Use it with the [Retry] option when creating new resources.
+//
+// Durations are specified as strings in the same format as [CustomTimeouts].
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts at each create, update or delete,
+	// including the first.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, which is doubled for each later retry.
+	// Defaults to 1s.
+	Backoff string
+	// MaxBackoff is the maximum delay between retries.
+	MaxBackoff string
+	// RetryOn lists the names of the gRPC status codes returned by the provider
+	// that should be retried, such as "Unavailable" or "ResourceExhausted".
+	// Defaults to Unavailable, ResourceExhausted, Aborted and DeadlineExceeded.
+	RetryOn []string
+}
+
 // ResourceOptions is a snapshot of one or more [ResourceOption]s.
 //
 // You cannot pass a ResourceOptions struct to a resource constructor.
@@ -345,6 +365,10 @@ type ResourceOptions struct {
 	// that may not be fully known yet.
 	DependsOnInputs []ResourceArrayInput
 
+	// Hooks, if set, lists the hooks to run before and after
+	// the operations on the resource.
+	Hooks *ResourceHooks
+
 	// IgnoreChanges lists properties changes to which should be ignored.
 	IgnoreChanges []string
 
@@ -375,6 +399,10 @@ type ResourceOptions struct {
 	// replacements.
 	ReplaceOnChanges []string
 
+	// RetryPolicy, if set, specifies how failed provider operations
+	// on this resource and its children are retried.
+	RetryPolicy *RetryPolicy
+
 	// Transformations is a list of functions that transform
 	// the resource's properties during construction.
 	Transformations []ResourceTransformation
@@ -426,6 +454,7 @@ type resourceOptions struct {
 	CustomTimeouts          *CustomTimeouts
 	DeleteBeforeReplace     bool
 	DependsOn               []dependencySet
+	Hooks                   *ResourceHooks
 	IgnoreChanges           []string
 	Import                  IDInput
 	Parent                  Resource
@@ -433,6 +462,7 @@ type resourceOptions struct {
 	Provider                ProviderResource
 	Providers               map[string]ProviderResource
 	ReplaceOnChanges        []string
+	RetryPolicy             *RetryPolicy
 	Transformations         []ResourceTransformation
 	Transforms              []XResourceTransform
 	URN                     string
@@ -482,6 +512,7 @@ func resourceOptionsSnapshot(ro *resourceOptions) *ResourceOptions {
 		DeleteBeforeReplace:     ro.DeleteBeforeReplace,
 		DependsOn:               dependsOn,
 		DependsOnInputs:         dependsOnInputs,
+		Hooks:                   ro.Hooks,
 		IgnoreChanges:           ro.IgnoreChanges,
 		Import:                  ro.Import,
 		Parent:                  ro.Parent,
@@ -489,6 +520,7 @@ func resourceOptionsSnapshot(ro *resourceOptions) *ResourceOptions {
 		Provider:                ro.Provider,
 		Providers:               providers,
 		ReplaceOnChanges:        ro.ReplaceOnChanges,
+		RetryPolicy:             ro.RetryPolicy,
 		Transformations:         ro.Transformations,
 		Transforms:              ro.Transforms,
 		URN:                     ro.URN,
@@ -713,6 +745,14 @@ func (ra *resourceArrayInputDependencySet) addURNs(ctx context.Context, urns urn
 	return nil
 }
 
+// Hooks is an optional set of hooks to run before and after the create, update, delete and replace
+// operations on the resource. If a hook fails, so does the operation it is run around.
+func Hooks(o *ResourceHooks) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.Hooks = o
+	})
+}
+
 // Ignore changes to any of the specified properties.
 func IgnoreChanges(o []string) ResourceOption {
 	return resourceOption(func(ro *resourceOptions) {
@@ -841,6 +881,14 @@ func ReplaceOnChanges(o []string) ResourceOption {
 	})
 }
 
+// Retry is an optional policy for retrying create, update and delete operations on the resource that fail with
+// transient errors. The policy is inherited by the resource's children unless they set their own.
+func Retry(o *RetryPolicy) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.RetryPolicy = o
+	})
+}
+
 // Timeouts is an optional configuration block used for CRUD operations
 func Timeouts(o *CustomTimeouts) ResourceOption {
 	return resourceOption(func(ro *resourceOptions) {
@@ -0,0 +1,56 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import "golang.org/x/net/context"
+
+// ResourceHookArgs is the argument bag passed to a resource hook.
+type ResourceHookArgs struct {
+	// The URN of the resource.
+	URN URN
+	// The ID of the resource, if it has one.
+	ID ID
+	// The operation the hook is run around: "create", "update", "delete" or "replace".
+	Operation string
+	// True if the hook is run after the operation, false if before.
+	After bool
+	// The old input and output properties of the resource, if any.
+	OldInputs, OldOutputs Map
+	// The new input and output properties of the resource, if any. The new outputs are only
+	// passed to the hooks that run after the operation.
+	NewInputs, NewOutputs Map
+}
+
+// ResourceHook is the callback signature for the hooks in [ResourceHooks]. If a hook returns
+// an error, the operation it is run around fails.
+type ResourceHook func(context.Context, *ResourceHookArgs) error
+
+// ResourceHooks lists the hooks the engine runs before and after the operations on a resource.
+// Use it with the [Hooks] option when creating new resources.
+//
+// Replacing a resource runs the create and delete hooks for the creation and deletion of the
+// resource, and the replace hooks around the whole replacement. The old resource of a
+// create-before-delete replacement is only deleted after the program has finished, so that
+// deletion runs no hooks. Hooks are not run during previews.
+type ResourceHooks struct {
+	BeforeCreate  []ResourceHook
+	AfterCreate   []ResourceHook
+	BeforeUpdate  []ResourceHook
+	AfterUpdate   []ResourceHook
+	BeforeDelete  []ResourceHook
+	AfterDelete   []ResourceHook
+	BeforeReplace []ResourceHook
+	AfterReplace  []ResourceHook
+}
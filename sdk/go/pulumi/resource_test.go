@@ -1007,6 +1007,13 @@ func TestNewResourceOptions(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "Hooks",
+			give: Hooks(&ResourceHooks{}),
+			want: ResourceOptions{
+				Hooks: &ResourceHooks{},
+			},
+		},
 		{
 			desc: "IgnoreChanges",
 			give: IgnoreChanges([]string{"foo"}),
@@ -1098,6 +1105,13 @@ func TestNewResourceOptions(t *testing.T) {
 				ReplaceOnChanges: []string{"foo", "bar"},
 			},
 		},
+		{
+			desc: "Retry",
+			give: Retry(&RetryPolicy{MaxAttempts: 3, RetryOn: []string{"Unavailable"}}),
+			want: ResourceOptions{
+				RetryPolicy: &RetryPolicy{MaxAttempts: 3, RetryOn: []string{"Unavailable"}},
+			},
+		},
 		{
 			desc: "Timeouts",
 			give: Timeouts(&CustomTimeouts{Create: "10s"}),
@@ -132,8 +132,11 @@ func RunWithContext(ctx *Context, body RunFunc) error {
 		return err
 	}
 
-	// Propagate the error from the body, if any.
-	return result
+	// Propagate the error from the body, if any. Otherwise, keep any callbacks available until the engine is done.
+	if result != nil {
+		return result
+	}
+	return ctx.signalAndWaitForShutdown()
 }
 
 // RunFunc executes the body of a Pulumi program.  It may register resources using the deployment context
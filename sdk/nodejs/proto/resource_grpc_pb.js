@@ -259,6 +259,20 @@ var ResourceMonitorService = exports.ResourceMonitorService = {
     responseSerialize: serialize_google_protobuf_Empty,
     responseDeserialize: deserialize_google_protobuf_Empty,
   },
+  // SignalAndWaitForShutdown lets the engine know that the program has finished registering resources, and waits
+// for the deployment to finish before returning. Programs that registered callbacks, such as resource hooks, call
+// this so that the callbacks remain available while the engine deletes resources after the program has finished.
+signalAndWaitForShutdown: {
+    path: '/pulumirpc.ResourceMonitor/SignalAndWaitForShutdown',
+    requestStream: false,
+    responseStream: false,
+    requestType: google_protobuf_empty_pb.Empty,
+    responseType: google_protobuf_empty_pb.Empty,
+    requestSerialize: serialize_google_protobuf_Empty,
+    requestDeserialize: deserialize_google_protobuf_Empty,
+    responseSerialize: serialize_google_protobuf_Empty,
+    responseDeserialize: deserialize_google_protobuf_Empty,
+  },
 };
 
 exports.ResourceMonitorClient = grpc.makeGenericClientConstructor(ResourceMonitorService);
@@ -241,6 +241,16 @@ export class RegisterResourceRequest extends jspb.Message {
     getSupportsresultreporting(): boolean;
     setSupportsresultreporting(value: boolean): RegisterResourceRequest;
 
+    hasRetrypolicy(): boolean;
+    clearRetrypolicy(): void;
+    getRetrypolicy(): RegisterResourceRequest.RetryPolicy | undefined;
+    setRetrypolicy(value?: RegisterResourceRequest.RetryPolicy): RegisterResourceRequest;
+
+    hasHooks(): boolean;
+    clearHooks(): void;
+    getHooks(): RegisterResourceRequest.ResourceHooks | undefined;
+    setHooks(value?: RegisterResourceRequest.ResourceHooks): RegisterResourceRequest;
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): RegisterResourceRequest.AsObject;
     static toObject(includeInstance: boolean, msg: RegisterResourceRequest): RegisterResourceRequest.AsObject;
@@ -288,6 +298,8 @@ export namespace RegisterResourceRequest {
         sourceposition?: pulumi_source_pb.SourcePosition.AsObject,
         transformsList: Array<pulumi_callback_pb.Callback.AsObject>,
         supportsresultreporting: boolean,
+        retrypolicy?: RegisterResourceRequest.RetryPolicy.AsObject,
+        hooks?: RegisterResourceRequest.ResourceHooks.AsObject,
     }
 
 
@@ -339,6 +351,94 @@ export namespace RegisterResourceRequest {
         }
     }
 
+    export class RetryPolicy extends jspb.Message { 
+        getMaxattempts(): number;
+        setMaxattempts(value: number): RetryPolicy;
+        getBackoff(): string;
+        setBackoff(value: string): RetryPolicy;
+        getMaxbackoff(): string;
+        setMaxbackoff(value: string): RetryPolicy;
+        clearRetryonList(): void;
+        getRetryonList(): Array<string>;
+        setRetryonList(value: Array<string>): RetryPolicy;
+        addRetryon(value: string, index?: number): string;
+
+        serializeBinary(): Uint8Array;
+        toObject(includeInstance?: boolean): RetryPolicy.AsObject;
+        static toObject(includeInstance: boolean, msg: RetryPolicy): RetryPolicy.AsObject;
+        static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+        static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+        static serializeBinaryToWriter(message: RetryPolicy, writer: jspb.BinaryWriter): void;
+        static deserializeBinary(bytes: Uint8Array): RetryPolicy;
+        static deserializeBinaryFromReader(message: RetryPolicy, reader: jspb.BinaryReader): RetryPolicy;
+    }
+
+    export namespace RetryPolicy {
+        export type AsObject = {
+            maxattempts: number,
+            backoff: string,
+            maxbackoff: string,
+            retryonList: Array<string>,
+        }
+    }
+
+    export class ResourceHooks extends jspb.Message { 
+        clearBeforecreateList(): void;
+        getBeforecreateList(): Array<pulumi_callback_pb.Callback>;
+        setBeforecreateList(value: Array<pulumi_callback_pb.Callback>): ResourceHooks;
+        addBeforecreate(value?: pulumi_callback_pb.Callback, index?: number): pulumi_callback_pb.Callback;
+        clearAftercreateList(): void;
+        getAftercreateList(): Array<pulumi_callback_pb.Callback>;
+        setAftercreateList(value: Array<pulumi_callback_pb.Callback>): ResourceHooks;
+        addAftercreate(value?: pulumi_callback_pb.Callback, index?: number): pulumi_callback_pb.Callback;
+        clearBeforeupdateList(): void;
+        getBeforeupdateList(): Array<pulumi_callback_pb.Callback>;
+        setBeforeupdateList(value: Array<pulumi_callback_pb.Callback>): ResourceHooks;
+        addBeforeupdate(value?: pulumi_callback_pb.Callback, index?: number): pulumi_callback_pb.Callback;
+        clearAfterupdateList(): void;
+        getAfterupdateList(): Array<pulumi_callback_pb.Callback>;
+        setAfterupdateList(value: Array<pulumi_callback_pb.Callback>): ResourceHooks;
+        addAfterupdate(value?: pulumi_callback_pb.Callback, index?: number): pulumi_callback_pb.Callback;
+        clearBeforedeleteList(): void;
+        getBeforedeleteList(): Array<pulumi_callback_pb.Callback>;
+        setBeforedeleteList(value: Array<pulumi_callback_pb.Callback>): ResourceHooks;
+        addBeforedelete(value?: pulumi_callback_pb.Callback, index?: number): pulumi_callback_pb.Callback;
+        clearAfterdeleteList(): void;
+        getAfterdeleteList(): Array<pulumi_callback_pb.Callback>;
+        setAfterdeleteList(value: Array<pulumi_callback_pb.Callback>): ResourceHooks;
+        addAfterdelete(value?: pulumi_callback_pb.Callback, index?: number): pulumi_callback_pb.Callback;
+        clearBeforereplaceList(): void;
+        getBeforereplaceList(): Array<pulumi_callback_pb.Callback>;
+        setBeforereplaceList(value: Array<pulumi_callback_pb.Callback>): ResourceHooks;
+        addBeforereplace(value?: pulumi_callback_pb.Callback, index?: number): pulumi_callback_pb.Callback;
+        clearAfterreplaceList(): void;
+        getAfterreplaceList(): Array<pulumi_callback_pb.Callback>;
+        setAfterreplaceList(value: Array<pulumi_callback_pb.Callback>): ResourceHooks;
+        addAfterreplace(value?: pulumi_callback_pb.Callback, index?: number): pulumi_callback_pb.Callback;
+
+        serializeBinary(): Uint8Array;
+        toObject(includeInstance?: boolean): ResourceHooks.AsObject;
+        static toObject(includeInstance: boolean, msg: ResourceHooks): ResourceHooks.AsObject;
+        static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+        static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+        static serializeBinaryToWriter(message: ResourceHooks, writer: jspb.BinaryWriter): void;
+        static deserializeBinary(bytes: Uint8Array): ResourceHooks;
+        static deserializeBinaryFromReader(message: ResourceHooks, reader: jspb.BinaryReader): ResourceHooks;
+    }
+
+    export namespace ResourceHooks {
+        export type AsObject = {
+            beforecreateList: Array<pulumi_callback_pb.Callback.AsObject>,
+            aftercreateList: Array<pulumi_callback_pb.Callback.AsObject>,
+            beforeupdateList: Array<pulumi_callback_pb.Callback.AsObject>,
+            afterupdateList: Array<pulumi_callback_pb.Callback.AsObject>,
+            beforedeleteList: Array<pulumi_callback_pb.Callback.AsObject>,
+            afterdeleteList: Array<pulumi_callback_pb.Callback.AsObject>,
+            beforereplaceList: Array<pulumi_callback_pb.Callback.AsObject>,
+            afterreplaceList: Array<pulumi_callback_pb.Callback.AsObject>,
+        }
+    }
+
 }
 
 export class RegisterResourceResponse extends jspb.Message { 
@@ -712,6 +812,79 @@ export namespace TransformResponse {
     }
 }
 
+export class ResourceHookRequest extends jspb.Message { 
+    getUrn(): string;
+    setUrn(value: string): ResourceHookRequest;
+    getId(): string;
+    setId(value: string): ResourceHookRequest;
+    getOperation(): string;
+    setOperation(value: string): ResourceHookRequest;
+    getAfter(): boolean;
+    setAfter(value: boolean): ResourceHookRequest;
+
+    hasOldInputs(): boolean;
+    clearOldInputs(): void;
+    getOldInputs(): google_protobuf_struct_pb.Struct | undefined;
+    setOldInputs(value?: google_protobuf_struct_pb.Struct): ResourceHookRequest;
+
+    hasOldOutputs(): boolean;
+    clearOldOutputs(): void;
+    getOldOutputs(): google_protobuf_struct_pb.Struct | undefined;
+    setOldOutputs(value?: google_protobuf_struct_pb.Struct): ResourceHookRequest;
+
+    hasNewInputs(): boolean;
+    clearNewInputs(): void;
+    getNewInputs(): google_protobuf_struct_pb.Struct | undefined;
+    setNewInputs(value?: google_protobuf_struct_pb.Struct): ResourceHookRequest;
+
+    hasNewOutputs(): boolean;
+    clearNewOutputs(): void;
+    getNewOutputs(): google_protobuf_struct_pb.Struct | undefined;
+    setNewOutputs(value?: google_protobuf_struct_pb.Struct): ResourceHookRequest;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ResourceHookRequest.AsObject;
+    static toObject(includeInstance: boolean, msg: ResourceHookRequest): ResourceHookRequest.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ResourceHookRequest, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ResourceHookRequest;
+    static deserializeBinaryFromReader(message: ResourceHookRequest, reader: jspb.BinaryReader): ResourceHookRequest;
+}
+
+export namespace ResourceHookRequest {
+    export type AsObject = {
+        urn: string,
+        id: string,
+        operation: string,
+        after: boolean,
+        oldInputs?: google_protobuf_struct_pb.Struct.AsObject,
+        oldOutputs?: google_protobuf_struct_pb.Struct.AsObject,
+        newInputs?: google_protobuf_struct_pb.Struct.AsObject,
+        newOutputs?: google_protobuf_struct_pb.Struct.AsObject,
+    }
+}
+
+export class ResourceHookResponse extends jspb.Message { 
+    getError(): string;
+    setError(value: string): ResourceHookResponse;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): ResourceHookResponse.AsObject;
+    static toObject(includeInstance: boolean, msg: ResourceHookResponse): ResourceHookResponse.AsObject;
+    static extensions: {[key: number]: jspb.ExtensionFieldInfo<jspb.Message>};
+    static extensionsBinary: {[key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message>};
+    static serializeBinaryToWriter(message: ResourceHookResponse, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): ResourceHookResponse;
+    static deserializeBinaryFromReader(message: ResourceHookResponse, reader: jspb.BinaryReader): ResourceHookResponse;
+}
+
+export namespace ResourceHookResponse {
+    export type AsObject = {
+        error: string,
+    }
+}
+
 export enum Result {
     SUCCESS = 0,
     FAIL = 1,
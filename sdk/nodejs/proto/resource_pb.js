@@ -33,10 +33,14 @@ goog.exportSymbol('proto.pulumirpc.RegisterResourceOutputsRequest', null, global
 goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.CustomTimeouts', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.PropertyDependencies', null, global);
+goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.ResourceHooks', null, global);
+goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.RetryPolicy', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceResponse', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceResponse.PropertyDependencies', null, global);
 goog.exportSymbol('proto.pulumirpc.ResourceCallRequest', null, global);
 goog.exportSymbol('proto.pulumirpc.ResourceCallRequest.ArgumentDependencies', null, global);
+goog.exportSymbol('proto.pulumirpc.ResourceHookRequest', null, global);
+goog.exportSymbol('proto.pulumirpc.ResourceHookResponse', null, global);
 goog.exportSymbol('proto.pulumirpc.ResourceInvokeRequest', null, global);
 goog.exportSymbol('proto.pulumirpc.Result', null, global);
 goog.exportSymbol('proto.pulumirpc.SupportsFeatureRequest', null, global);
@@ -191,6 +195,48 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.displayName = 'proto.pulumirpc.RegisterResourceRequest.CustomTimeouts';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.pulumirpc.RegisterResourceRequest.RetryPolicy.repeatedFields_, null);
+};
+goog.inherits(proto.pulumirpc.RegisterResourceRequest.RetryPolicy, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.pulumirpc.RegisterResourceRequest.RetryPolicy.displayName = 'proto.pulumirpc.RegisterResourceRequest.RetryPolicy';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.pulumirpc.RegisterResourceRequest.ResourceHooks.repeatedFields_, null);
+};
+goog.inherits(proto.pulumirpc.RegisterResourceRequest.ResourceHooks, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.pulumirpc.RegisterResourceRequest.ResourceHooks.displayName = 'proto.pulumirpc.RegisterResourceRequest.ResourceHooks';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -380,6 +426,48 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.pulumirpc.TransformResponse.displayName = 'proto.pulumirpc.TransformResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.pulumirpc.ResourceHookRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.pulumirpc.ResourceHookRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.pulumirpc.ResourceHookRequest.displayName = 'proto.pulumirpc.ResourceHookRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.pulumirpc.ResourceHookResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.pulumirpc.ResourceHookResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.pulumirpc.ResourceHookResponse.displayName = 'proto.pulumirpc.ResourceHookResponse';
+}
 
 
 
@@ -1503,7 +1591,9 @@ proto.pulumirpc.RegisterResourceRequest.toObject = function(includeInstance, msg
     sourceposition: (f = msg.getSourceposition()) && pulumi_source_pb.SourcePosition.toObject(includeInstance, f),
     transformsList: jspb.Message.toObjectList(msg.getTransformsList(),
     pulumi_callback_pb.Callback.toObject, includeInstance),
-    supportsresultreporting: jspb.Message.getBooleanFieldWithDefault(msg, 32, false)
+    supportsresultreporting: jspb.Message.getBooleanFieldWithDefault(msg, 32, false),
+    retrypolicy: (f = msg.getRetrypolicy()) && proto.pulumirpc.RegisterResourceRequest.RetryPolicy.toObject(includeInstance, f),
+    hooks: (f = msg.getHooks()) && proto.pulumirpc.RegisterResourceRequest.ResourceHooks.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -1679,6 +1769,16 @@ proto.pulumirpc.RegisterResourceRequest.deserializeBinaryFromReader = function(m
       var value = /** @type {boolean} */ (reader.readBool());
       msg.setSupportsresultreporting(value);
       break;
+    case 33:
+      var value = new proto.pulumirpc.RegisterResourceRequest.RetryPolicy;
+      reader.readMessage(value,proto.pulumirpc.RegisterResourceRequest.RetryPolicy.deserializeBinaryFromReader);
+      msg.setRetrypolicy(value);
+      break;
+    case 34:
+      var value = new proto.pulumirpc.RegisterResourceRequest.ResourceHooks;
+      reader.readMessage(value,proto.pulumirpc.RegisterResourceRequest.ResourceHooks.deserializeBinaryFromReader);
+      msg.setHooks(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1928,6 +2028,22 @@ proto.pulumirpc.RegisterResourceRequest.serializeBinaryToWriter = function(messa
       f
     );
   }
+  f = message.getRetrypolicy();
+  if (f != null) {
+    writer.writeMessage(
+      33,
+      f,
+      proto.pulumirpc.RegisterResourceRequest.RetryPolicy.serializeBinaryToWriter
+    );
+  }
+  f = message.getHooks();
+  if (f != null) {
+    writer.writeMessage(
+      34,
+      f,
+      proto.pulumirpc.RegisterResourceRequest.ResourceHooks.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -2277,567 +2393,807 @@ proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.setDelete = fun
 };
 
 
-/**
- * optional string type = 1;
- * @return {string}
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.getType = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
-};
-
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setType = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
-};
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.repeatedFields_ = [4];
 
 
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * optional string name = 2;
- * @return {string}
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getName = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.RegisterResourceRequest.RetryPolicy.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setName = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    maxattempts: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    backoff: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    maxbackoff: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    retryonList: (f = jspb.Message.getRepeatedField(msg, 4)) == null ? undefined : f
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * optional string parent = 3;
- * @return {string}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getParent = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.RegisterResourceRequest.RetryPolicy;
+  return proto.pulumirpc.RegisterResourceRequest.RetryPolicy.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setParent = function(value) {
-  return jspb.Message.setProto3StringField(this, 3, value);
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setMaxattempts(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setBackoff(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMaxbackoff(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addRetryon(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * optional bool custom = 4;
- * @return {boolean}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getCustom = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.RegisterResourceRequest.RetryPolicy.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setCustom = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 4, value);
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getMaxattempts();
+  if (f !== 0) {
+    writer.writeInt32(
+      1,
+      f
+    );
+  }
+  f = message.getBackoff();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getMaxbackoff();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getRetryonList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      4,
+      f
+    );
+  }
 };
 
 
 /**
- * optional google.protobuf.Struct object = 5;
- * @return {?proto.google.protobuf.Struct}
+ * optional int32 maxAttempts = 1;
+ * @return {number}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getObject = function() {
-  return /** @type{?proto.google.protobuf.Struct} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 5));
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.getMaxattempts = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
 };
 
 
 /**
- * @param {?proto.google.protobuf.Struct|undefined} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
-*/
-proto.pulumirpc.RegisterResourceRequest.prototype.setObject = function(value) {
-  return jspb.Message.setWrapperField(this, 5, value);
+ * @param {number} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.setMaxattempts = function(value) {
+  return jspb.Message.setProto3IntField(this, 1, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * optional string backoff = 2;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearObject = function() {
-  return this.setObject(undefined);
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.getBackoff = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.hasObject = function() {
-  return jspb.Message.getField(this, 5) != null;
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.setBackoff = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * optional bool protect = 6;
- * @return {boolean}
+ * optional string maxBackoff = 3;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getProtect = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 6, false));
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.getMaxbackoff = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * @param {string} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setProtect = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 6, value);
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.setMaxbackoff = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 /**
- * repeated string dependencies = 7;
+ * repeated string retryOn = 4;
  * @return {!Array<string>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getDependenciesList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 7));
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.getRetryonList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 4));
 };
 
 
 /**
  * @param {!Array<string>} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setDependenciesList = function(value) {
-  return jspb.Message.setField(this, 7, value || []);
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.setRetryonList = function(value) {
+  return jspb.Message.setField(this, 4, value || []);
 };
 
 
 /**
  * @param {string} value
  * @param {number=} opt_index
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.addDependencies = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 7, value, opt_index);
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.addRetryon = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 4, value, opt_index);
 };
 
 
 /**
  * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearDependenciesList = function() {
-  return this.setDependenciesList([]);
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.clearRetryonList = function() {
+  return this.setRetryonList([]);
 };
 
 
+
 /**
- * optional string provider = 8;
- * @return {string}
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getProvider = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
-};
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.repeatedFields_ = [1,2,3,4,5,6,7,8];
+
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setProvider = function(value) {
-  return jspb.Message.setProto3StringField(this, 8, value);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.RegisterResourceRequest.ResourceHooks.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * map<string, PropertyDependencies> propertyDependencies = 9;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,!proto.pulumirpc.RegisterResourceRequest.PropertyDependencies>}
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getPropertydependenciesMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,!proto.pulumirpc.RegisterResourceRequest.PropertyDependencies>} */ (
-      jspb.Message.getMapField(this, 9, opt_noLazyCreate,
-      proto.pulumirpc.RegisterResourceRequest.PropertyDependencies));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    beforecreateList: jspb.Message.toObjectList(msg.getBeforecreateList(),
+    pulumi_callback_pb.Callback.toObject, includeInstance),
+    aftercreateList: jspb.Message.toObjectList(msg.getAftercreateList(),
+    pulumi_callback_pb.Callback.toObject, includeInstance),
+    beforeupdateList: jspb.Message.toObjectList(msg.getBeforeupdateList(),
+    pulumi_callback_pb.Callback.toObject, includeInstance),
+    afterupdateList: jspb.Message.toObjectList(msg.getAfterupdateList(),
+    pulumi_callback_pb.Callback.toObject, includeInstance),
+    beforedeleteList: jspb.Message.toObjectList(msg.getBeforedeleteList(),
+    pulumi_callback_pb.Callback.toObject, includeInstance),
+    afterdeleteList: jspb.Message.toObjectList(msg.getAfterdeleteList(),
+    pulumi_callback_pb.Callback.toObject, includeInstance),
+    beforereplaceList: jspb.Message.toObjectList(msg.getBeforereplaceList(),
+    pulumi_callback_pb.Callback.toObject, includeInstance),
+    afterreplaceList: jspb.Message.toObjectList(msg.getAfterreplaceList(),
+    pulumi_callback_pb.Callback.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearPropertydependenciesMap = function() {
-  this.getPropertydependenciesMap().clear();
-  return this;};
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.RegisterResourceRequest.ResourceHooks;
+  return proto.pulumirpc.RegisterResourceRequest.ResourceHooks.deserializeBinaryFromReader(msg, reader);
+};
 
 
 /**
- * optional bool deleteBeforeReplace = 10;
- * @return {boolean}
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getDeletebeforereplace = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 10, false));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new pulumi_callback_pb.Callback;
+      reader.readMessage(value,pulumi_callback_pb.Callback.deserializeBinaryFromReader);
+      msg.addBeforecreate(value);
+      break;
+    case 2:
+      var value = new pulumi_callback_pb.Callback;
+      reader.readMessage(value,pulumi_callback_pb.Callback.deserializeBinaryFromReader);
+      msg.addAftercreate(value);
+      break;
+    case 3:
+      var value = new pulumi_callback_pb.Callback;
+      reader.readMessage(value,pulumi_callback_pb.Callback.deserializeBinaryFromReader);
+      msg.addBeforeupdate(value);
+      break;
+    case 4:
+      var value = new pulumi_callback_pb.Callback;
+      reader.readMessage(value,pulumi_callback_pb.Callback.deserializeBinaryFromReader);
+      msg.addAfterupdate(value);
+      break;
+    case 5:
+      var value = new pulumi_callback_pb.Callback;
+      reader.readMessage(value,pulumi_callback_pb.Callback.deserializeBinaryFromReader);
+      msg.addBeforedelete(value);
+      break;
+    case 6:
+      var value = new pulumi_callback_pb.Callback;
+      reader.readMessage(value,pulumi_callback_pb.Callback.deserializeBinaryFromReader);
+      msg.addAfterdelete(value);
+      break;
+    case 7:
+      var value = new pulumi_callback_pb.Callback;
+      reader.readMessage(value,pulumi_callback_pb.Callback.deserializeBinaryFromReader);
+      msg.addBeforereplace(value);
+      break;
+    case 8:
+      var value = new pulumi_callback_pb.Callback;
+      reader.readMessage(value,pulumi_callback_pb.Callback.deserializeBinaryFromReader);
+      msg.addAfterreplace(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setDeletebeforereplace = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 10, value);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.RegisterResourceRequest.ResourceHooks.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * optional string version = 11;
- * @return {string}
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getVersion = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 11, ""));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getBeforecreateList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      1,
+      f,
+      pulumi_callback_pb.Callback.serializeBinaryToWriter
+    );
+  }
+  f = message.getAftercreateList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      2,
+      f,
+      pulumi_callback_pb.Callback.serializeBinaryToWriter
+    );
+  }
+  f = message.getBeforeupdateList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      3,
+      f,
+      pulumi_callback_pb.Callback.serializeBinaryToWriter
+    );
+  }
+  f = message.getAfterupdateList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      4,
+      f,
+      pulumi_callback_pb.Callback.serializeBinaryToWriter
+    );
+  }
+  f = message.getBeforedeleteList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      5,
+      f,
+      pulumi_callback_pb.Callback.serializeBinaryToWriter
+    );
+  }
+  f = message.getAfterdeleteList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      6,
+      f,
+      pulumi_callback_pb.Callback.serializeBinaryToWriter
+    );
+  }
+  f = message.getBeforereplaceList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      7,
+      f,
+      pulumi_callback_pb.Callback.serializeBinaryToWriter
+    );
+  }
+  f = message.getAfterreplaceList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      8,
+      f,
+      pulumi_callback_pb.Callback.serializeBinaryToWriter
+    );
+  }
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * repeated Callback beforeCreate = 1;
+ * @return {!Array<!proto.pulumirpc.Callback>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setVersion = function(value) {
-  return jspb.Message.setProto3StringField(this, 11, value);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.getBeforecreateList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 1));
 };
 
 
 /**
- * repeated string ignoreChanges = 12;
- * @return {!Array<string>}
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.getIgnorechangesList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 12));
+ * @param {!Array<!proto.pulumirpc.Callback>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.setBeforecreateList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 1, value);
 };
 
 
 /**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * @param {!proto.pulumirpc.Callback=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Callback}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setIgnorechangesList = function(value) {
-  return jspb.Message.setField(this, 12, value || []);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.addBeforecreate = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 1, opt_value, proto.pulumirpc.Callback, opt_index);
 };
 
 
 /**
- * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.addIgnorechanges = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 12, value, opt_index);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.clearBeforecreateList = function() {
+  return this.setBeforecreateList([]);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * repeated Callback afterCreate = 2;
+ * @return {!Array<!proto.pulumirpc.Callback>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearIgnorechangesList = function() {
-  return this.setIgnorechangesList([]);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.getAftercreateList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 2));
 };
 
 
 /**
- * optional bool acceptSecrets = 13;
- * @return {boolean}
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.getAcceptsecrets = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 13, false));
+ * @param {!Array<!proto.pulumirpc.Callback>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.setAftercreateList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 2, value);
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * @param {!proto.pulumirpc.Callback=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Callback}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setAcceptsecrets = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 13, value);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.addAftercreate = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 2, opt_value, proto.pulumirpc.Callback, opt_index);
 };
 
 
 /**
- * repeated string additionalSecretOutputs = 14;
- * @return {!Array<string>}
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getAdditionalsecretoutputsList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 14));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.clearAftercreateList = function() {
+  return this.setAftercreateList([]);
 };
 
 
 /**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * repeated Callback beforeUpdate = 3;
+ * @return {!Array<!proto.pulumirpc.Callback>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setAdditionalsecretoutputsList = function(value) {
-  return jspb.Message.setField(this, 14, value || []);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.getBeforeupdateList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 3));
 };
 
 
 /**
- * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.addAdditionalsecretoutputs = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 14, value, opt_index);
+ * @param {!Array<!proto.pulumirpc.Callback>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.setBeforeupdateList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 3, value);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearAdditionalsecretoutputsList = function() {
-  return this.setAdditionalsecretoutputsList([]);
-};
-
-
-/**
- * repeated string aliasURNs = 15;
- * @return {!Array<string>}
+ * @param {!proto.pulumirpc.Callback=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Callback}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getAliasurnsList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 15));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.addBeforeupdate = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 3, opt_value, proto.pulumirpc.Callback, opt_index);
 };
 
 
 /**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setAliasurnsList = function(value) {
-  return jspb.Message.setField(this, 15, value || []);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.clearBeforeupdateList = function() {
+  return this.setBeforeupdateList([]);
 };
 
 
 /**
- * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * repeated Callback afterUpdate = 4;
+ * @return {!Array<!proto.pulumirpc.Callback>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.addAliasurns = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 15, value, opt_index);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.getAfterupdateList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 4));
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearAliasurnsList = function() {
-  return this.setAliasurnsList([]);
+ * @param {!Array<!proto.pulumirpc.Callback>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.setAfterupdateList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 4, value);
 };
 
 
 /**
- * optional string importId = 16;
- * @return {string}
+ * @param {!proto.pulumirpc.Callback=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Callback}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getImportid = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 16, ""));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.addAfterupdate = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 4, opt_value, proto.pulumirpc.Callback, opt_index);
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setImportid = function(value) {
-  return jspb.Message.setProto3StringField(this, 16, value);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.clearAfterupdateList = function() {
+  return this.setAfterupdateList([]);
 };
 
 
 /**
- * optional CustomTimeouts customTimeouts = 17;
- * @return {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts}
+ * repeated Callback beforeDelete = 5;
+ * @return {!Array<!proto.pulumirpc.Callback>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getCustomtimeouts = function() {
-  return /** @type{?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts} */ (
-    jspb.Message.getWrapperField(this, proto.pulumirpc.RegisterResourceRequest.CustomTimeouts, 17));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.getBeforedeleteList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 5));
 };
 
 
 /**
- * @param {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts|undefined} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * @param {!Array<!proto.pulumirpc.Callback>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
 */
-proto.pulumirpc.RegisterResourceRequest.prototype.setCustomtimeouts = function(value) {
-  return jspb.Message.setWrapperField(this, 17, value);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.setBeforedeleteList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 5, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * @param {!proto.pulumirpc.Callback=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Callback}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearCustomtimeouts = function() {
-  return this.setCustomtimeouts(undefined);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.addBeforedelete = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 5, opt_value, proto.pulumirpc.Callback, opt_index);
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.hasCustomtimeouts = function() {
-  return jspb.Message.getField(this, 17) != null;
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.clearBeforedeleteList = function() {
+  return this.setBeforedeleteList([]);
 };
 
 
 /**
- * optional bool deleteBeforeReplaceDefined = 18;
- * @return {boolean}
+ * repeated Callback afterDelete = 6;
+ * @return {!Array<!proto.pulumirpc.Callback>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getDeletebeforereplacedefined = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 18, false));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.getAfterdeleteList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 6));
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.setDeletebeforereplacedefined = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 18, value);
+ * @param {!Array<!proto.pulumirpc.Callback>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.setAfterdeleteList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 6, value);
 };
 
 
 /**
- * optional bool supportsPartialValues = 19;
- * @return {boolean}
+ * @param {!proto.pulumirpc.Callback=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Callback}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getSupportspartialvalues = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 19, false));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.addAfterdelete = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 6, opt_value, proto.pulumirpc.Callback, opt_index);
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setSupportspartialvalues = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 19, value);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.clearAfterdeleteList = function() {
+  return this.setAfterdeleteList([]);
 };
 
 
 /**
- * optional bool remote = 20;
- * @return {boolean}
+ * repeated Callback beforeReplace = 7;
+ * @return {!Array<!proto.pulumirpc.Callback>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getRemote = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 20, false));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.getBeforereplaceList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 7));
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.setRemote = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 20, value);
+ * @param {!Array<!proto.pulumirpc.Callback>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.setBeforereplaceList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 7, value);
 };
 
 
 /**
- * optional bool acceptResources = 21;
- * @return {boolean}
+ * @param {!proto.pulumirpc.Callback=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Callback}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getAcceptresources = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 21, false));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.addBeforereplace = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 7, opt_value, proto.pulumirpc.Callback, opt_index);
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setAcceptresources = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 21, value);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.clearBeforereplaceList = function() {
+  return this.setBeforereplaceList([]);
 };
 
 
 /**
- * map<string, string> providers = 22;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,string>}
+ * repeated Callback afterReplace = 8;
+ * @return {!Array<!proto.pulumirpc.Callback>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getProvidersMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,string>} */ (
-      jspb.Message.getMapField(this, 22, opt_noLazyCreate,
-      null));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.getAfterreplaceList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 8));
 };
 
 
 /**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearProvidersMap = function() {
-  this.getProvidersMap().clear();
-  return this;};
+ * @param {!Array<!proto.pulumirpc.Callback>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.setAfterreplaceList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 8, value);
+};
 
 
 /**
- * repeated string replaceOnChanges = 23;
- * @return {!Array<string>}
+ * @param {!proto.pulumirpc.Callback=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Callback}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getReplaceonchangesList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 23));
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.addAfterreplace = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 8, opt_value, proto.pulumirpc.Callback, opt_index);
 };
 
 
 /**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.ResourceHooks} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setReplaceonchangesList = function(value) {
-  return jspb.Message.setField(this, 23, value || []);
+proto.pulumirpc.RegisterResourceRequest.ResourceHooks.prototype.clearAfterreplaceList = function() {
+  return this.setAfterreplaceList([]);
 };
 
 
 /**
- * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * optional string type = 1;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.addReplaceonchanges = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 23, value, opt_index);
+proto.pulumirpc.RegisterResourceRequest.prototype.getType = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * Clears the list making it empty but non-null.
+ * @param {string} value
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearReplaceonchangesList = function() {
-  return this.setReplaceonchangesList([]);
+proto.pulumirpc.RegisterResourceRequest.prototype.setType = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string pluginDownloadURL = 24;
+ * optional string name = 2;
  * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getPlugindownloadurl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 24, ""));
+proto.pulumirpc.RegisterResourceRequest.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
@@ -2845,39 +3201,35 @@ proto.pulumirpc.RegisterResourceRequest.prototype.getPlugindownloadurl = functio
  * @param {string} value
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setPlugindownloadurl = function(value) {
-  return jspb.Message.setProto3StringField(this, 24, value);
+proto.pulumirpc.RegisterResourceRequest.prototype.setName = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * map<string, bytes> pluginChecksums = 30;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,!(string|Uint8Array)>}
+ * optional string parent = 3;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getPluginchecksumsMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,!(string|Uint8Array)>} */ (
-      jspb.Message.getMapField(this, 30, opt_noLazyCreate,
-      null));
+proto.pulumirpc.RegisterResourceRequest.prototype.getParent = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
- * Clears values from the map. The map will be non-null.
+ * @param {string} value
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearPluginchecksumsMap = function() {
-  this.getPluginchecksumsMap().clear();
-  return this;};
+proto.pulumirpc.RegisterResourceRequest.prototype.setParent = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
 
 
 /**
- * optional bool retainOnDelete = 25;
+ * optional bool custom = 4;
  * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getRetainondelete = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 25, false));
+proto.pulumirpc.RegisterResourceRequest.prototype.getCustom = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
 };
 
 
@@ -2885,863 +3237,651 @@ proto.pulumirpc.RegisterResourceRequest.prototype.getRetainondelete = function()
  * @param {boolean} value
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setRetainondelete = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 25, value);
+proto.pulumirpc.RegisterResourceRequest.prototype.setCustom = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 4, value);
 };
 
 
 /**
- * repeated Alias aliases = 26;
- * @return {!Array<!proto.pulumirpc.Alias>}
+ * optional google.protobuf.Struct object = 5;
+ * @return {?proto.google.protobuf.Struct}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getAliasesList = function() {
-  return /** @type{!Array<!proto.pulumirpc.Alias>} */ (
-    jspb.Message.getRepeatedWrapperField(this, pulumi_alias_pb.Alias, 26));
+proto.pulumirpc.RegisterResourceRequest.prototype.getObject = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 5));
 };
 
 
 /**
- * @param {!Array<!proto.pulumirpc.Alias>} value
+ * @param {?proto.google.protobuf.Struct|undefined} value
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
 */
-proto.pulumirpc.RegisterResourceRequest.prototype.setAliasesList = function(value) {
-  return jspb.Message.setRepeatedWrapperField(this, 26, value);
+proto.pulumirpc.RegisterResourceRequest.prototype.setObject = function(value) {
+  return jspb.Message.setWrapperField(this, 5, value);
 };
 
 
 /**
- * @param {!proto.pulumirpc.Alias=} opt_value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.Alias}
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.addAliases = function(opt_value, opt_index) {
-  return jspb.Message.addToRepeatedWrapperField(this, 26, opt_value, proto.pulumirpc.Alias, opt_index);
+proto.pulumirpc.RegisterResourceRequest.prototype.clearObject = function() {
+  return this.setObject(undefined);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearAliasesList = function() {
-  return this.setAliasesList([]);
+proto.pulumirpc.RegisterResourceRequest.prototype.hasObject = function() {
+  return jspb.Message.getField(this, 5) != null;
 };
 
 
 /**
- * optional string deletedWith = 27;
- * @return {string}
+ * optional bool protect = 6;
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getDeletedwith = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 27, ""));
+proto.pulumirpc.RegisterResourceRequest.prototype.getProtect = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 6, false));
 };
 
 
 /**
- * @param {string} value
+ * @param {boolean} value
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setDeletedwith = function(value) {
-  return jspb.Message.setProto3StringField(this, 27, value);
+proto.pulumirpc.RegisterResourceRequest.prototype.setProtect = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 6, value);
 };
 
 
 /**
- * optional bool aliasSpecs = 28;
- * @return {boolean}
- */
-proto.pulumirpc.RegisterResourceRequest.prototype.getAliasspecs = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 28, false));
+ * repeated string dependencies = 7;
+ * @return {!Array<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getDependenciesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 7));
 };
 
 
 /**
- * @param {boolean} value
+ * @param {!Array<string>} value
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setAliasspecs = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 28, value);
+proto.pulumirpc.RegisterResourceRequest.prototype.setDependenciesList = function(value) {
+  return jspb.Message.setField(this, 7, value || []);
 };
 
 
 /**
- * optional SourcePosition sourcePosition = 29;
- * @return {?proto.pulumirpc.SourcePosition}
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getSourceposition = function() {
-  return /** @type{?proto.pulumirpc.SourcePosition} */ (
-    jspb.Message.getWrapperField(this, pulumi_source_pb.SourcePosition, 29));
+proto.pulumirpc.RegisterResourceRequest.prototype.addDependencies = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 7, value, opt_index);
 };
 
 
 /**
- * @param {?proto.pulumirpc.SourcePosition|undefined} value
+ * Clears the list making it empty but non-null.
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
-*/
-proto.pulumirpc.RegisterResourceRequest.prototype.setSourceposition = function(value) {
-  return jspb.Message.setWrapperField(this, 29, value);
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.clearDependenciesList = function() {
+  return this.setDependenciesList([]);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ * optional string provider = 8;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearSourceposition = function() {
-  return this.setSourceposition(undefined);
+proto.pulumirpc.RegisterResourceRequest.prototype.getProvider = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.hasSourceposition = function() {
-  return jspb.Message.getField(this, 29) != null;
+proto.pulumirpc.RegisterResourceRequest.prototype.setProvider = function(value) {
+  return jspb.Message.setProto3StringField(this, 8, value);
 };
 
 
 /**
- * repeated Callback transforms = 31;
- * @return {!Array<!proto.pulumirpc.Callback>}
+ * map<string, PropertyDependencies> propertyDependencies = 9;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,!proto.pulumirpc.RegisterResourceRequest.PropertyDependencies>}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getTransformsList = function() {
-  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
-    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 31));
+proto.pulumirpc.RegisterResourceRequest.prototype.getPropertydependenciesMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,!proto.pulumirpc.RegisterResourceRequest.PropertyDependencies>} */ (
+      jspb.Message.getMapField(this, 9, opt_noLazyCreate,
+      proto.pulumirpc.RegisterResourceRequest.PropertyDependencies));
 };
 
 
 /**
- * @param {!Array<!proto.pulumirpc.Callback>} value
+ * Clears values from the map. The map will be non-null.
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
-*/
-proto.pulumirpc.RegisterResourceRequest.prototype.setTransformsList = function(value) {
-  return jspb.Message.setRepeatedWrapperField(this, 31, value);
-};
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.clearPropertydependenciesMap = function() {
+  this.getPropertydependenciesMap().clear();
+  return this;};
 
 
 /**
- * @param {!proto.pulumirpc.Callback=} opt_value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.Callback}
+ * optional bool deleteBeforeReplace = 10;
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.addTransforms = function(opt_value, opt_index) {
-  return jspb.Message.addToRepeatedWrapperField(this, 31, opt_value, proto.pulumirpc.Callback, opt_index);
+proto.pulumirpc.RegisterResourceRequest.prototype.getDeletebeforereplace = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 10, false));
 };
 
 
 /**
- * Clears the list making it empty but non-null.
+ * @param {boolean} value
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.clearTransformsList = function() {
-  return this.setTransformsList([]);
+proto.pulumirpc.RegisterResourceRequest.prototype.setDeletebeforereplace = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 10, value);
 };
 
 
 /**
- * optional bool supportsResultReporting = 32;
- * @return {boolean}
+ * optional string version = 11;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.getSupportsresultreporting = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 32, false));
+proto.pulumirpc.RegisterResourceRequest.prototype.getVersion = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 11, ""));
 };
 
 
 /**
- * @param {boolean} value
+ * @param {string} value
  * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceRequest.prototype.setSupportsresultreporting = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 32, value);
+proto.pulumirpc.RegisterResourceRequest.prototype.setVersion = function(value) {
+  return jspb.Message.setProto3StringField(this, 11, value);
 };
 
 
-
 /**
- * List of repeated fields within this message type.
- * @private {!Array<number>}
- * @const
+ * repeated string ignoreChanges = 12;
+ * @return {!Array<string>}
  */
-proto.pulumirpc.RegisterResourceResponse.repeatedFields_ = [5];
+proto.pulumirpc.RegisterResourceRequest.prototype.getIgnorechangesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 12));
+};
+
 
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.setIgnorechangesList = function(value) {
+  return jspb.Message.setField(this, 12, value || []);
+};
 
 
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.pulumirpc.RegisterResourceResponse.toObject(opt_includeInstance, this);
+proto.pulumirpc.RegisterResourceRequest.prototype.addIgnorechanges = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 12, value, opt_index);
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.pulumirpc.RegisterResourceResponse} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.toObject = function(includeInstance, msg) {
-  var f, obj = {
-    urn: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    id: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    object: (f = msg.getObject()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
-    stable: jspb.Message.getBooleanFieldWithDefault(msg, 4, false),
-    stablesList: (f = jspb.Message.getRepeatedField(msg, 5)) == null ? undefined : f,
-    propertydependenciesMap: (f = msg.getPropertydependenciesMap()) ? f.toObject(includeInstance, proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.toObject) : [],
-    result: jspb.Message.getFieldWithDefault(msg, 7, 0)
-  };
+proto.pulumirpc.RegisterResourceRequest.prototype.clearIgnorechangesList = function() {
+  return this.setIgnorechangesList([]);
+};
 
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+
+/**
+ * optional bool acceptSecrets = 13;
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getAcceptsecrets = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 13, false));
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.pulumirpc.RegisterResourceResponse}
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.pulumirpc.RegisterResourceResponse;
-  return proto.pulumirpc.RegisterResourceResponse.deserializeBinaryFromReader(msg, reader);
+proto.pulumirpc.RegisterResourceRequest.prototype.setAcceptsecrets = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 13, value);
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.pulumirpc.RegisterResourceResponse} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.pulumirpc.RegisterResourceResponse}
+ * repeated string additionalSecretOutputs = 14;
+ * @return {!Array<string>}
  */
-proto.pulumirpc.RegisterResourceResponse.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setUrn(value);
-      break;
-    case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setId(value);
-      break;
-    case 3:
-      var value = new google_protobuf_struct_pb.Struct;
-      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
-      msg.setObject(value);
-      break;
-    case 4:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setStable(value);
-      break;
-    case 5:
-      var value = /** @type {string} */ (reader.readString());
-      msg.addStables(value);
-      break;
-    case 6:
-      var value = msg.getPropertydependenciesMap();
-      reader.readMessage(value, function(message, reader) {
-        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readMessage, proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.deserializeBinaryFromReader, "", new proto.pulumirpc.RegisterResourceResponse.PropertyDependencies());
-         });
-      break;
-    case 7:
-      var value = /** @type {!proto.pulumirpc.Result} */ (reader.readEnum());
-      msg.setResult(value);
-      break;
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+proto.pulumirpc.RegisterResourceRequest.prototype.getAdditionalsecretoutputsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 14));
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.pulumirpc.RegisterResourceResponse.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.pulumirpc.RegisterResourceRequest.prototype.setAdditionalsecretoutputsList = function(value) {
+  return jspb.Message.setField(this, 14, value || []);
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.pulumirpc.RegisterResourceResponse} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getUrn();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-  f = message.getId();
-  if (f.length > 0) {
-    writer.writeString(
-      2,
-      f
-    );
-  }
-  f = message.getObject();
-  if (f != null) {
-    writer.writeMessage(
-      3,
-      f,
-      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
-    );
-  }
-  f = message.getStable();
-  if (f) {
-    writer.writeBool(
-      4,
-      f
-    );
-  }
-  f = message.getStablesList();
-  if (f.length > 0) {
-    writer.writeRepeatedString(
-      5,
-      f
-    );
-  }
-  f = message.getPropertydependenciesMap(true);
-  if (f && f.getLength() > 0) {
-    f.serializeBinary(6, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeMessage, proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.serializeBinaryToWriter);
-  }
-  f = message.getResult();
-  if (f !== 0.0) {
-    writer.writeEnum(
-      7,
-      f
-    );
-  }
+proto.pulumirpc.RegisterResourceRequest.prototype.addAdditionalsecretoutputs = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 14, value, opt_index);
 };
 
 
-
 /**
- * List of repeated fields within this message type.
- * @private {!Array<number>}
- * @const
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.repeatedFields_ = [1];
-
+proto.pulumirpc.RegisterResourceRequest.prototype.clearAdditionalsecretoutputsList = function() {
+  return this.setAdditionalsecretoutputsList([]);
+};
 
 
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * repeated string aliasURNs = 15;
+ * @return {!Array<string>}
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.toObject = function(opt_includeInstance) {
-  return proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.toObject(opt_includeInstance, this);
+proto.pulumirpc.RegisterResourceRequest.prototype.getAliasurnsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 15));
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.toObject = function(includeInstance, msg) {
-  var f, obj = {
-    urnsList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f
-  };
-
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+proto.pulumirpc.RegisterResourceRequest.prototype.setAliasurnsList = function(value) {
+  return jspb.Message.setField(this, 15, value || []);
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies}
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.pulumirpc.RegisterResourceResponse.PropertyDependencies;
-  return proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.deserializeBinaryFromReader(msg, reader);
+proto.pulumirpc.RegisterResourceRequest.prototype.addAliasurns = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 15, value, opt_index);
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies}
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.addUrns(value);
-      break;
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+proto.pulumirpc.RegisterResourceRequest.prototype.clearAliasurnsList = function() {
+  return this.setAliasurnsList([]);
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * optional string importId = 16;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.pulumirpc.RegisterResourceRequest.prototype.getImportid = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 16, ""));
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * @param {string} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getUrnsList();
-  if (f.length > 0) {
-    writer.writeRepeatedString(
-      1,
-      f
-    );
-  }
+proto.pulumirpc.RegisterResourceRequest.prototype.setImportid = function(value) {
+  return jspb.Message.setProto3StringField(this, 16, value);
 };
 
 
 /**
- * repeated string urns = 1;
- * @return {!Array<string>}
+ * optional CustomTimeouts customTimeouts = 17;
+ * @return {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts}
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.getUrnsList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
+proto.pulumirpc.RegisterResourceRequest.prototype.getCustomtimeouts = function() {
+  return /** @type{?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts} */ (
+    jspb.Message.getWrapperField(this, proto.pulumirpc.RegisterResourceRequest.CustomTimeouts, 17));
 };
 
 
 /**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} returns this
- */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.setUrnsList = function(value) {
-  return jspb.Message.setField(this, 1, value || []);
+ * @param {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts|undefined} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.prototype.setCustomtimeouts = function(value) {
+  return jspb.Message.setWrapperField(this, 17, value);
 };
 
 
 /**
- * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} returns this
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.addUrns = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
+proto.pulumirpc.RegisterResourceRequest.prototype.clearCustomtimeouts = function() {
+  return this.setCustomtimeouts(undefined);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.clearUrnsList = function() {
-  return this.setUrnsList([]);
+proto.pulumirpc.RegisterResourceRequest.prototype.hasCustomtimeouts = function() {
+  return jspb.Message.getField(this, 17) != null;
 };
 
 
 /**
- * optional string urn = 1;
- * @return {string}
+ * optional bool deleteBeforeReplaceDefined = 18;
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.getUrn = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.pulumirpc.RegisterResourceRequest.prototype.getDeletebeforereplacedefined = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 18, false));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.setUrn = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.pulumirpc.RegisterResourceRequest.prototype.setDeletebeforereplacedefined = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 18, value);
 };
 
 
 /**
- * optional string id = 2;
- * @return {string}
+ * optional bool supportsPartialValues = 19;
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.getId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.pulumirpc.RegisterResourceRequest.prototype.getSupportspartialvalues = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 19, false));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.setId = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.pulumirpc.RegisterResourceRequest.prototype.setSupportspartialvalues = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 19, value);
 };
 
 
 /**
- * optional google.protobuf.Struct object = 3;
- * @return {?proto.google.protobuf.Struct}
+ * optional bool remote = 20;
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.getObject = function() {
-  return /** @type{?proto.google.protobuf.Struct} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 3));
+proto.pulumirpc.RegisterResourceRequest.prototype.getRemote = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 20, false));
 };
 
 
 /**
- * @param {?proto.google.protobuf.Struct|undefined} value
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
-*/
-proto.pulumirpc.RegisterResourceResponse.prototype.setObject = function(value) {
-  return jspb.Message.setWrapperField(this, 3, value);
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.setRemote = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 20, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ * optional bool acceptResources = 21;
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.clearObject = function() {
-  return this.setObject(undefined);
+proto.pulumirpc.RegisterResourceRequest.prototype.getAcceptresources = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 21, false));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.hasObject = function() {
-  return jspb.Message.getField(this, 3) != null;
+proto.pulumirpc.RegisterResourceRequest.prototype.setAcceptresources = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 21, value);
 };
 
 
 /**
- * optional bool stable = 4;
- * @return {boolean}
+ * map<string, string> providers = 22;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.getStable = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
+proto.pulumirpc.RegisterResourceRequest.prototype.getProvidersMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 22, opt_noLazyCreate,
+      null));
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.setStable = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 4, value);
-};
+proto.pulumirpc.RegisterResourceRequest.prototype.clearProvidersMap = function() {
+  this.getProvidersMap().clear();
+  return this;};
 
 
 /**
- * repeated string stables = 5;
+ * repeated string replaceOnChanges = 23;
  * @return {!Array<string>}
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.getStablesList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 5));
+proto.pulumirpc.RegisterResourceRequest.prototype.getReplaceonchangesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 23));
 };
 
 
 /**
  * @param {!Array<string>} value
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.setStablesList = function(value) {
-  return jspb.Message.setField(this, 5, value || []);
+proto.pulumirpc.RegisterResourceRequest.prototype.setReplaceonchangesList = function(value) {
+  return jspb.Message.setField(this, 23, value || []);
 };
 
 
 /**
  * @param {string} value
  * @param {number=} opt_index
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.addStables = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 5, value, opt_index);
+proto.pulumirpc.RegisterResourceRequest.prototype.addReplaceonchanges = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 23, value, opt_index);
 };
 
 
 /**
  * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.clearStablesList = function() {
-  return this.setStablesList([]);
+proto.pulumirpc.RegisterResourceRequest.prototype.clearReplaceonchangesList = function() {
+  return this.setReplaceonchangesList([]);
 };
 
 
 /**
- * map<string, PropertyDependencies> propertyDependencies = 6;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies>}
+ * optional string pluginDownloadURL = 24;
+ * @return {string}
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.getPropertydependenciesMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies>} */ (
-      jspb.Message.getMapField(this, 6, opt_noLazyCreate,
-      proto.pulumirpc.RegisterResourceResponse.PropertyDependencies));
+proto.pulumirpc.RegisterResourceRequest.prototype.getPlugindownloadurl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 24, ""));
 };
 
 
 /**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ * @param {string} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.clearPropertydependenciesMap = function() {
-  this.getPropertydependenciesMap().clear();
-  return this;};
+proto.pulumirpc.RegisterResourceRequest.prototype.setPlugindownloadurl = function(value) {
+  return jspb.Message.setProto3StringField(this, 24, value);
+};
 
 
 /**
- * optional Result result = 7;
- * @return {!proto.pulumirpc.Result}
+ * map<string, bytes> pluginChecksums = 30;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,!(string|Uint8Array)>}
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.getResult = function() {
-  return /** @type {!proto.pulumirpc.Result} */ (jspb.Message.getFieldWithDefault(this, 7, 0));
+proto.pulumirpc.RegisterResourceRequest.prototype.getPluginchecksumsMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,!(string|Uint8Array)>} */ (
+      jspb.Message.getMapField(this, 30, opt_noLazyCreate,
+      null));
 };
 
 
 /**
- * @param {!proto.pulumirpc.Result} value
- * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceResponse.prototype.setResult = function(value) {
-  return jspb.Message.setProto3EnumField(this, 7, value);
-};
-
-
-
+proto.pulumirpc.RegisterResourceRequest.prototype.clearPluginchecksumsMap = function() {
+  this.getPluginchecksumsMap().clear();
+  return this;};
 
 
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * optional bool retainOnDelete = 25;
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceOutputsRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.pulumirpc.RegisterResourceOutputsRequest.toObject(opt_includeInstance, this);
+proto.pulumirpc.RegisterResourceRequest.prototype.getRetainondelete = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 25, false));
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.pulumirpc.RegisterResourceOutputsRequest} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceOutputsRequest.toObject = function(includeInstance, msg) {
-  var f, obj = {
-    urn: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    outputs: (f = msg.getOutputs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f)
-  };
-
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+proto.pulumirpc.RegisterResourceRequest.prototype.setRetainondelete = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 25, value);
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.pulumirpc.RegisterResourceOutputsRequest}
+ * repeated Alias aliases = 26;
+ * @return {!Array<!proto.pulumirpc.Alias>}
  */
-proto.pulumirpc.RegisterResourceOutputsRequest.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.pulumirpc.RegisterResourceOutputsRequest;
-  return proto.pulumirpc.RegisterResourceOutputsRequest.deserializeBinaryFromReader(msg, reader);
+proto.pulumirpc.RegisterResourceRequest.prototype.getAliasesList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Alias>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_alias_pb.Alias, 26));
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.pulumirpc.RegisterResourceOutputsRequest} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.pulumirpc.RegisterResourceOutputsRequest}
- */
-proto.pulumirpc.RegisterResourceOutputsRequest.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setUrn(value);
-      break;
-    case 2:
-      var value = new google_protobuf_struct_pb.Struct;
-      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
-      msg.setOutputs(value);
-      break;
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+ * @param {!Array<!proto.pulumirpc.Alias>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.prototype.setAliasesList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 26, value);
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * @param {!proto.pulumirpc.Alias=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Alias}
  */
-proto.pulumirpc.RegisterResourceOutputsRequest.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.pulumirpc.RegisterResourceOutputsRequest.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.pulumirpc.RegisterResourceRequest.prototype.addAliases = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 26, opt_value, proto.pulumirpc.Alias, opt_index);
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.pulumirpc.RegisterResourceOutputsRequest} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceOutputsRequest.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getUrn();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-  f = message.getOutputs();
-  if (f != null) {
-    writer.writeMessage(
-      2,
-      f,
-      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
-    );
-  }
+proto.pulumirpc.RegisterResourceRequest.prototype.clearAliasesList = function() {
+  return this.setAliasesList([]);
 };
 
 
 /**
- * optional string urn = 1;
+ * optional string deletedWith = 27;
  * @return {string}
  */
-proto.pulumirpc.RegisterResourceOutputsRequest.prototype.getUrn = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.pulumirpc.RegisterResourceRequest.prototype.getDeletedwith = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 27, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.pulumirpc.RegisterResourceOutputsRequest} returns this
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
  */
-proto.pulumirpc.RegisterResourceOutputsRequest.prototype.setUrn = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.pulumirpc.RegisterResourceRequest.prototype.setDeletedwith = function(value) {
+  return jspb.Message.setProto3StringField(this, 27, value);
 };
 
 
 /**
- * optional google.protobuf.Struct outputs = 2;
- * @return {?proto.google.protobuf.Struct}
+ * optional bool aliasSpecs = 28;
+ * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceOutputsRequest.prototype.getOutputs = function() {
-  return /** @type{?proto.google.protobuf.Struct} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 2));
+proto.pulumirpc.RegisterResourceRequest.prototype.getAliasspecs = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 28, false));
 };
 
 
 /**
- * @param {?proto.google.protobuf.Struct|undefined} value
- * @return {!proto.pulumirpc.RegisterResourceOutputsRequest} returns this
-*/
-proto.pulumirpc.RegisterResourceOutputsRequest.prototype.setOutputs = function(value) {
-  return jspb.Message.setWrapperField(this, 2, value);
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.setAliasspecs = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 28, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.RegisterResourceOutputsRequest} returns this
- */
-proto.pulumirpc.RegisterResourceOutputsRequest.prototype.clearOutputs = function() {
-  return this.setOutputs(undefined);
+ * optional SourcePosition sourcePosition = 29;
+ * @return {?proto.pulumirpc.SourcePosition}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getSourceposition = function() {
+  return /** @type{?proto.pulumirpc.SourcePosition} */ (
+    jspb.Message.getWrapperField(this, pulumi_source_pb.SourcePosition, 29));
+};
+
+
+/**
+ * @param {?proto.pulumirpc.SourcePosition|undefined} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.prototype.setSourceposition = function(value) {
+  return jspb.Message.setWrapperField(this, 29, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.clearSourceposition = function() {
+  return this.setSourceposition(undefined);
 };
 
 
@@ -3749,12 +3889,149 @@ proto.pulumirpc.RegisterResourceOutputsRequest.prototype.clearOutputs = function
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.pulumirpc.RegisterResourceOutputsRequest.prototype.hasOutputs = function() {
-  return jspb.Message.getField(this, 2) != null;
+proto.pulumirpc.RegisterResourceRequest.prototype.hasSourceposition = function() {
+  return jspb.Message.getField(this, 29) != null;
+};
+
+
+/**
+ * repeated Callback transforms = 31;
+ * @return {!Array<!proto.pulumirpc.Callback>}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getTransformsList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Callback>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_callback_pb.Callback, 31));
+};
+
+
+/**
+ * @param {!Array<!proto.pulumirpc.Callback>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.prototype.setTransformsList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 31, value);
+};
+
+
+/**
+ * @param {!proto.pulumirpc.Callback=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Callback}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.addTransforms = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 31, opt_value, proto.pulumirpc.Callback, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.clearTransformsList = function() {
+  return this.setTransformsList([]);
+};
+
+
+/**
+ * optional bool supportsResultReporting = 32;
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getSupportsresultreporting = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 32, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.setSupportsresultreporting = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 32, value);
+};
+
+
+/**
+ * optional RetryPolicy retryPolicy = 33;
+ * @return {?proto.pulumirpc.RegisterResourceRequest.RetryPolicy}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getRetrypolicy = function() {
+  return /** @type{?proto.pulumirpc.RegisterResourceRequest.RetryPolicy} */ (
+    jspb.Message.getWrapperField(this, proto.pulumirpc.RegisterResourceRequest.RetryPolicy, 33));
+};
+
+
+/**
+ * @param {?proto.pulumirpc.RegisterResourceRequest.RetryPolicy|undefined} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.prototype.setRetrypolicy = function(value) {
+  return jspb.Message.setWrapperField(this, 33, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.clearRetrypolicy = function() {
+  return this.setRetrypolicy(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.hasRetrypolicy = function() {
+  return jspb.Message.getField(this, 33) != null;
+};
+
+
+/**
+ * optional ResourceHooks hooks = 34;
+ * @return {?proto.pulumirpc.RegisterResourceRequest.ResourceHooks}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getHooks = function() {
+  return /** @type{?proto.pulumirpc.RegisterResourceRequest.ResourceHooks} */ (
+    jspb.Message.getWrapperField(this, proto.pulumirpc.RegisterResourceRequest.ResourceHooks, 34));
+};
+
+
+/**
+ * @param {?proto.pulumirpc.RegisterResourceRequest.ResourceHooks|undefined} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.prototype.setHooks = function(value) {
+  return jspb.Message.setWrapperField(this, 34, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.clearHooks = function() {
+  return this.setHooks(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.hasHooks = function() {
+  return jspb.Message.getField(this, 34) != null;
 };
 
 
 
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.pulumirpc.RegisterResourceResponse.repeatedFields_ = [5];
+
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -3770,8 +4047,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.pulumirpc.ResourceInvokeRequest.toObject(opt_includeInstance, this);
+proto.pulumirpc.RegisterResourceResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.RegisterResourceResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -3780,20 +4057,19 @@ proto.pulumirpc.ResourceInvokeRequest.prototype.toObject = function(opt_includeI
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.pulumirpc.ResourceInvokeRequest} msg The msg instance to transform.
+ * @param {!proto.pulumirpc.RegisterResourceResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.ResourceInvokeRequest.toObject = function(includeInstance, msg) {
+proto.pulumirpc.RegisterResourceResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    tok: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    args: (f = msg.getArgs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
-    provider: jspb.Message.getFieldWithDefault(msg, 3, ""),
-    version: jspb.Message.getFieldWithDefault(msg, 4, ""),
-    acceptresources: jspb.Message.getBooleanFieldWithDefault(msg, 5, false),
-    plugindownloadurl: jspb.Message.getFieldWithDefault(msg, 6, ""),
-    pluginchecksumsMap: (f = msg.getPluginchecksumsMap()) ? f.toObject(includeInstance, undefined) : [],
-    sourceposition: (f = msg.getSourceposition()) && pulumi_source_pb.SourcePosition.toObject(includeInstance, f)
+    urn: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    id: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    object: (f = msg.getObject()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
+    stable: jspb.Message.getBooleanFieldWithDefault(msg, 4, false),
+    stablesList: (f = jspb.Message.getRepeatedField(msg, 5)) == null ? undefined : f,
+    propertydependenciesMap: (f = msg.getPropertydependenciesMap()) ? f.toObject(includeInstance, proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.toObject) : [],
+    result: jspb.Message.getFieldWithDefault(msg, 7, 0)
   };
 
   if (includeInstance) {
@@ -3807,23 +4083,23 @@ proto.pulumirpc.ResourceInvokeRequest.toObject = function(includeInstance, msg)
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.pulumirpc.ResourceInvokeRequest}
+ * @return {!proto.pulumirpc.RegisterResourceResponse}
  */
-proto.pulumirpc.ResourceInvokeRequest.deserializeBinary = function(bytes) {
+proto.pulumirpc.RegisterResourceResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.pulumirpc.ResourceInvokeRequest;
-  return proto.pulumirpc.ResourceInvokeRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.pulumirpc.RegisterResourceResponse;
+  return proto.pulumirpc.RegisterResourceResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.pulumirpc.ResourceInvokeRequest} msg The message object to deserialize into.
+ * @param {!proto.pulumirpc.RegisterResourceResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.pulumirpc.ResourceInvokeRequest}
+ * @return {!proto.pulumirpc.RegisterResourceResponse}
  */
-proto.pulumirpc.ResourceInvokeRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.pulumirpc.RegisterResourceResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -3832,39 +4108,34 @@ proto.pulumirpc.ResourceInvokeRequest.deserializeBinaryFromReader = function(msg
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setTok(value);
+      msg.setUrn(value);
       break;
     case 2:
-      var value = new google_protobuf_struct_pb.Struct;
-      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
-      msg.setArgs(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
       break;
     case 3:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setProvider(value);
+      var value = new google_protobuf_struct_pb.Struct;
+      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
+      msg.setObject(value);
       break;
     case 4:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setVersion(value);
-      break;
-    case 5:
       var value = /** @type {boolean} */ (reader.readBool());
-      msg.setAcceptresources(value);
+      msg.setStable(value);
       break;
-    case 6:
+    case 5:
       var value = /** @type {string} */ (reader.readString());
-      msg.setPlugindownloadurl(value);
+      msg.addStables(value);
       break;
-    case 8:
-      var value = msg.getPluginchecksumsMap();
+    case 6:
+      var value = msg.getPropertydependenciesMap();
       reader.readMessage(value, function(message, reader) {
-        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readBytes, null, "", "");
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readMessage, proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.deserializeBinaryFromReader, "", new proto.pulumirpc.RegisterResourceResponse.PropertyDependencies());
          });
       break;
     case 7:
-      var value = new pulumi_source_pb.SourcePosition;
-      reader.readMessage(value,pulumi_source_pb.SourcePosition.deserializeBinaryFromReader);
-      msg.setSourceposition(value);
+      var value = /** @type {!proto.pulumirpc.Result} */ (reader.readEnum());
+      msg.setResult(value);
       break;
     default:
       reader.skipField();
@@ -3879,9 +4150,9 @@ proto.pulumirpc.ResourceInvokeRequest.deserializeBinaryFromReader = function(msg
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.serializeBinary = function() {
+proto.pulumirpc.RegisterResourceResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.pulumirpc.ResourceInvokeRequest.serializeBinaryToWriter(this, writer);
+  proto.pulumirpc.RegisterResourceResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -3889,244 +4160,279 @@ proto.pulumirpc.ResourceInvokeRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.pulumirpc.ResourceInvokeRequest} message
+ * @param {!proto.pulumirpc.RegisterResourceResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.ResourceInvokeRequest.serializeBinaryToWriter = function(message, writer) {
+proto.pulumirpc.RegisterResourceResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getTok();
+  f = message.getUrn();
   if (f.length > 0) {
     writer.writeString(
       1,
       f
     );
   }
-  f = message.getArgs();
-  if (f != null) {
-    writer.writeMessage(
-      2,
-      f,
-      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
-    );
-  }
-  f = message.getProvider();
+  f = message.getId();
   if (f.length > 0) {
     writer.writeString(
-      3,
+      2,
       f
     );
   }
-  f = message.getVersion();
-  if (f.length > 0) {
-    writer.writeString(
-      4,
-      f
+  f = message.getObject();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
     );
   }
-  f = message.getAcceptresources();
+  f = message.getStable();
   if (f) {
     writer.writeBool(
-      5,
+      4,
       f
     );
   }
-  f = message.getPlugindownloadurl();
+  f = message.getStablesList();
   if (f.length > 0) {
-    writer.writeString(
-      6,
+    writer.writeRepeatedString(
+      5,
       f
     );
   }
-  f = message.getPluginchecksumsMap(true);
+  f = message.getPropertydependenciesMap(true);
   if (f && f.getLength() > 0) {
-    f.serializeBinary(8, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeBytes);
+    f.serializeBinary(6, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeMessage, proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.serializeBinaryToWriter);
   }
-  f = message.getSourceposition();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getResult();
+  if (f !== 0.0) {
+    writer.writeEnum(
       7,
-      f,
-      pulumi_source_pb.SourcePosition.serializeBinaryToWriter
+      f
     );
   }
 };
 
 
+
 /**
- * optional string tok = 1;
- * @return {string}
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.getTok = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
-};
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.repeatedFields_ = [1];
+
 
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.setTok = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * optional google.protobuf.Struct args = 2;
- * @return {?proto.google.protobuf.Struct}
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.getArgs = function() {
-  return /** @type{?proto.google.protobuf.Struct} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 2));
-};
-
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    urnsList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f
+  };
 
-/**
- * @param {?proto.google.protobuf.Struct|undefined} value
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
-*/
-proto.pulumirpc.ResourceInvokeRequest.prototype.setArgs = function(value) {
-  return jspb.Message.setWrapperField(this, 2, value);
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.clearArgs = function() {
-  return this.setArgs(undefined);
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.RegisterResourceResponse.PropertyDependencies;
+  return proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.hasArgs = function() {
-  return jspb.Message.getField(this, 2) != null;
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addUrns(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * optional string provider = 3;
- * @return {string}
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.getProvider = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.setProvider = function(value) {
-  return jspb.Message.setProto3StringField(this, 3, value);
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrnsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      1,
+      f
+    );
+  }
 };
 
 
 /**
- * optional string version = 4;
- * @return {string}
+ * repeated string urns = 1;
+ * @return {!Array<string>}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.getVersion = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.getUrnsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} returns this
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.setVersion = function(value) {
-  return jspb.Message.setProto3StringField(this, 4, value);
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.setUrnsList = function(value) {
+  return jspb.Message.setField(this, 1, value || []);
 };
 
 
 /**
- * optional bool acceptResources = 5;
- * @return {boolean}
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} returns this
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.getAcceptresources = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 5, false));
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.addUrns = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies} returns this
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.setAcceptresources = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 5, value);
+proto.pulumirpc.RegisterResourceResponse.PropertyDependencies.prototype.clearUrnsList = function() {
+  return this.setUrnsList([]);
 };
 
 
 /**
- * optional string pluginDownloadURL = 6;
+ * optional string urn = 1;
  * @return {string}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.getPlugindownloadurl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+proto.pulumirpc.RegisterResourceResponse.prototype.getUrn = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.setPlugindownloadurl = function(value) {
-  return jspb.Message.setProto3StringField(this, 6, value);
+proto.pulumirpc.RegisterResourceResponse.prototype.setUrn = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * map<string, bytes> pluginChecksums = 8;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,!(string|Uint8Array)>}
+ * optional string id = 2;
+ * @return {string}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.getPluginchecksumsMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,!(string|Uint8Array)>} */ (
-      jspb.Message.getMapField(this, 8, opt_noLazyCreate,
-      null));
+proto.pulumirpc.RegisterResourceResponse.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ * @param {string} value
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.clearPluginchecksumsMap = function() {
-  this.getPluginchecksumsMap().clear();
-  return this;};
+proto.pulumirpc.RegisterResourceResponse.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
 
 
 /**
- * optional SourcePosition sourcePosition = 7;
- * @return {?proto.pulumirpc.SourcePosition}
+ * optional google.protobuf.Struct object = 3;
+ * @return {?proto.google.protobuf.Struct}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.getSourceposition = function() {
-  return /** @type{?proto.pulumirpc.SourcePosition} */ (
-    jspb.Message.getWrapperField(this, pulumi_source_pb.SourcePosition, 7));
+proto.pulumirpc.RegisterResourceResponse.prototype.getObject = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 3));
 };
 
 
 /**
- * @param {?proto.pulumirpc.SourcePosition|undefined} value
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ * @param {?proto.google.protobuf.Struct|undefined} value
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
 */
-proto.pulumirpc.ResourceInvokeRequest.prototype.setSourceposition = function(value) {
-  return jspb.Message.setWrapperField(this, 7, value);
+proto.pulumirpc.RegisterResourceResponse.prototype.setObject = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.clearSourceposition = function() {
-  return this.setSourceposition(undefined);
+proto.pulumirpc.RegisterResourceResponse.prototype.clearObject = function() {
+  return this.setObject(undefined);
 };
 
 
@@ -4134,216 +4440,106 @@ proto.pulumirpc.ResourceInvokeRequest.prototype.clearSourceposition = function()
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.pulumirpc.ResourceInvokeRequest.prototype.hasSourceposition = function() {
-  return jspb.Message.getField(this, 7) != null;
+proto.pulumirpc.RegisterResourceResponse.prototype.hasObject = function() {
+  return jspb.Message.getField(this, 3) != null;
 };
 
 
+/**
+ * optional bool stable = 4;
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceResponse.prototype.getStable = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
+};
 
 
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
  */
-proto.pulumirpc.ResourceCallRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.pulumirpc.ResourceCallRequest.toObject(opt_includeInstance, this);
+proto.pulumirpc.RegisterResourceResponse.prototype.setStable = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 4, value);
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.pulumirpc.ResourceCallRequest} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * repeated string stables = 5;
+ * @return {!Array<string>}
  */
-proto.pulumirpc.ResourceCallRequest.toObject = function(includeInstance, msg) {
-  var f, obj = {
-    tok: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    args: (f = msg.getArgs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
-    argdependenciesMap: (f = msg.getArgdependenciesMap()) ? f.toObject(includeInstance, proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.toObject) : [],
-    provider: jspb.Message.getFieldWithDefault(msg, 4, ""),
-    version: jspb.Message.getFieldWithDefault(msg, 5, ""),
-    plugindownloadurl: jspb.Message.getFieldWithDefault(msg, 13, ""),
-    pluginchecksumsMap: (f = msg.getPluginchecksumsMap()) ? f.toObject(includeInstance, undefined) : [],
-    sourceposition: (f = msg.getSourceposition()) && pulumi_source_pb.SourcePosition.toObject(includeInstance, f)
-  };
-
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+proto.pulumirpc.RegisterResourceResponse.prototype.getStablesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 5));
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.pulumirpc.ResourceCallRequest}
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
  */
-proto.pulumirpc.ResourceCallRequest.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.pulumirpc.ResourceCallRequest;
-  return proto.pulumirpc.ResourceCallRequest.deserializeBinaryFromReader(msg, reader);
+proto.pulumirpc.RegisterResourceResponse.prototype.setStablesList = function(value) {
+  return jspb.Message.setField(this, 5, value || []);
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.pulumirpc.ResourceCallRequest} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.pulumirpc.ResourceCallRequest}
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
  */
-proto.pulumirpc.ResourceCallRequest.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setTok(value);
-      break;
-    case 2:
-      var value = new google_protobuf_struct_pb.Struct;
-      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
-      msg.setArgs(value);
-      break;
-    case 3:
-      var value = msg.getArgdependenciesMap();
-      reader.readMessage(value, function(message, reader) {
-        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readMessage, proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinaryFromReader, "", new proto.pulumirpc.ResourceCallRequest.ArgumentDependencies());
-         });
-      break;
-    case 4:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setProvider(value);
-      break;
-    case 5:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setVersion(value);
-      break;
-    case 13:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setPlugindownloadurl(value);
-      break;
-    case 16:
-      var value = msg.getPluginchecksumsMap();
-      reader.readMessage(value, function(message, reader) {
-        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readBytes, null, "", "");
-         });
-      break;
-    case 15:
-      var value = new pulumi_source_pb.SourcePosition;
-      reader.readMessage(value,pulumi_source_pb.SourcePosition.deserializeBinaryFromReader);
-      msg.setSourceposition(value);
-      break;
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+proto.pulumirpc.RegisterResourceResponse.prototype.addStables = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 5, value, opt_index);
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
  */
-proto.pulumirpc.ResourceCallRequest.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.pulumirpc.ResourceCallRequest.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.pulumirpc.RegisterResourceResponse.prototype.clearStablesList = function() {
+  return this.setStablesList([]);
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.pulumirpc.ResourceCallRequest} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * map<string, PropertyDependencies> propertyDependencies = 6;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies>}
  */
-proto.pulumirpc.ResourceCallRequest.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getTok();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-  f = message.getArgs();
-  if (f != null) {
-    writer.writeMessage(
-      2,
-      f,
-      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
-    );
-  }
-  f = message.getArgdependenciesMap(true);
-  if (f && f.getLength() > 0) {
-    f.serializeBinary(3, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeMessage, proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.serializeBinaryToWriter);
-  }
-  f = message.getProvider();
-  if (f.length > 0) {
-    writer.writeString(
-      4,
-      f
-    );
-  }
-  f = message.getVersion();
-  if (f.length > 0) {
-    writer.writeString(
-      5,
-      f
-    );
-  }
-  f = message.getPlugindownloadurl();
-  if (f.length > 0) {
-    writer.writeString(
-      13,
-      f
-    );
-  }
-  f = message.getPluginchecksumsMap(true);
-  if (f && f.getLength() > 0) {
-    f.serializeBinary(16, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeBytes);
-  }
-  f = message.getSourceposition();
-  if (f != null) {
-    writer.writeMessage(
-      15,
-      f,
-      pulumi_source_pb.SourcePosition.serializeBinaryToWriter
-    );
-  }
+proto.pulumirpc.RegisterResourceResponse.prototype.getPropertydependenciesMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,!proto.pulumirpc.RegisterResourceResponse.PropertyDependencies>} */ (
+      jspb.Message.getMapField(this, 6, opt_noLazyCreate,
+      proto.pulumirpc.RegisterResourceResponse.PropertyDependencies));
 };
 
 
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ */
+proto.pulumirpc.RegisterResourceResponse.prototype.clearPropertydependenciesMap = function() {
+  this.getPropertydependenciesMap().clear();
+  return this;};
+
 
 /**
- * List of repeated fields within this message type.
- * @private {!Array<number>}
- * @const
+ * optional Result result = 7;
+ * @return {!proto.pulumirpc.Result}
  */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.repeatedFields_ = [1];
+proto.pulumirpc.RegisterResourceResponse.prototype.getResult = function() {
+  return /** @type {!proto.pulumirpc.Result} */ (jspb.Message.getFieldWithDefault(this, 7, 0));
+};
+
+
+/**
+ * @param {!proto.pulumirpc.Result} value
+ * @return {!proto.pulumirpc.RegisterResourceResponse} returns this
+ */
+proto.pulumirpc.RegisterResourceResponse.prototype.setResult = function(value) {
+  return jspb.Message.setProto3EnumField(this, 7, value);
+};
+
+
 
 
 
@@ -4360,8 +4556,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.toObject = function(opt_includeInstance) {
-  return proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.toObject(opt_includeInstance, this);
+proto.pulumirpc.RegisterResourceOutputsRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.RegisterResourceOutputsRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -4370,13 +4566,14 @@ proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.toObject = fu
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} msg The msg instance to transform.
+ * @param {!proto.pulumirpc.RegisterResourceOutputsRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.toObject = function(includeInstance, msg) {
+proto.pulumirpc.RegisterResourceOutputsRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    urnsList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f
+    urn: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    outputs: (f = msg.getOutputs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -4390,23 +4587,23 @@ proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.toObject = function(inc
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies}
+ * @return {!proto.pulumirpc.RegisterResourceOutputsRequest}
  */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinary = function(bytes) {
+proto.pulumirpc.RegisterResourceOutputsRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.pulumirpc.ResourceCallRequest.ArgumentDependencies;
-  return proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.pulumirpc.RegisterResourceOutputsRequest;
+  return proto.pulumirpc.RegisterResourceOutputsRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} msg The message object to deserialize into.
+ * @param {!proto.pulumirpc.RegisterResourceOutputsRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies}
+ * @return {!proto.pulumirpc.RegisterResourceOutputsRequest}
  */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinaryFromReader = function(msg, reader) {
+proto.pulumirpc.RegisterResourceOutputsRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -4415,7 +4612,12 @@ proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinaryFromRe
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.addUrns(value);
+      msg.setUrn(value);
+      break;
+    case 2:
+      var value = new google_protobuf_struct_pb.Struct;
+      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
+      msg.setOutputs(value);
       break;
     default:
       reader.skipField();
@@ -4430,9 +4632,9 @@ proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinaryFromRe
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.serializeBinary = function() {
+proto.pulumirpc.RegisterResourceOutputsRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.serializeBinaryToWriter(this, writer);
+  proto.pulumirpc.RegisterResourceOutputsRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -4440,82 +4642,53 @@ proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.serializeBina
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} message
+ * @param {!proto.pulumirpc.RegisterResourceOutputsRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.serializeBinaryToWriter = function(message, writer) {
+proto.pulumirpc.RegisterResourceOutputsRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getUrnsList();
+  f = message.getUrn();
   if (f.length > 0) {
-    writer.writeRepeatedString(
+    writer.writeString(
       1,
       f
     );
   }
+  f = message.getOutputs();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
+    );
+  }
 };
 
 
 /**
- * repeated string urns = 1;
- * @return {!Array<string>}
- */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.getUrnsList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
-};
-
-
-/**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} returns this
- */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.setUrnsList = function(value) {
-  return jspb.Message.setField(this, 1, value || []);
-};
-
-
-/**
- * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} returns this
- */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.addUrns = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
-};
-
-
-/**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} returns this
- */
-proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.clearUrnsList = function() {
-  return this.setUrnsList([]);
-};
-
-
-/**
- * optional string tok = 1;
+ * optional string urn = 1;
  * @return {string}
  */
-proto.pulumirpc.ResourceCallRequest.prototype.getTok = function() {
+proto.pulumirpc.RegisterResourceOutputsRequest.prototype.getUrn = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ * @return {!proto.pulumirpc.RegisterResourceOutputsRequest} returns this
  */
-proto.pulumirpc.ResourceCallRequest.prototype.setTok = function(value) {
+proto.pulumirpc.RegisterResourceOutputsRequest.prototype.setUrn = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional google.protobuf.Struct args = 2;
+ * optional google.protobuf.Struct outputs = 2;
  * @return {?proto.google.protobuf.Struct}
  */
-proto.pulumirpc.ResourceCallRequest.prototype.getArgs = function() {
+proto.pulumirpc.RegisterResourceOutputsRequest.prototype.getOutputs = function() {
   return /** @type{?proto.google.protobuf.Struct} */ (
     jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 2));
 };
@@ -4523,19 +4696,19 @@ proto.pulumirpc.ResourceCallRequest.prototype.getArgs = function() {
 
 /**
  * @param {?proto.google.protobuf.Struct|undefined} value
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ * @return {!proto.pulumirpc.RegisterResourceOutputsRequest} returns this
 */
-proto.pulumirpc.ResourceCallRequest.prototype.setArgs = function(value) {
+proto.pulumirpc.RegisterResourceOutputsRequest.prototype.setOutputs = function(value) {
   return jspb.Message.setWrapperField(this, 2, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ * @return {!proto.pulumirpc.RegisterResourceOutputsRequest} returns this
  */
-proto.pulumirpc.ResourceCallRequest.prototype.clearArgs = function() {
-  return this.setArgs(undefined);
+proto.pulumirpc.RegisterResourceOutputsRequest.prototype.clearOutputs = function() {
+  return this.setOutputs(undefined);
 };
 
 
@@ -4543,153 +4716,11 @@ proto.pulumirpc.ResourceCallRequest.prototype.clearArgs = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.pulumirpc.ResourceCallRequest.prototype.hasArgs = function() {
+proto.pulumirpc.RegisterResourceOutputsRequest.prototype.hasOutputs = function() {
   return jspb.Message.getField(this, 2) != null;
 };
 
 
-/**
- * map<string, ArgumentDependencies> argDependencies = 3;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies>}
- */
-proto.pulumirpc.ResourceCallRequest.prototype.getArgdependenciesMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies>} */ (
-      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
-      proto.pulumirpc.ResourceCallRequest.ArgumentDependencies));
-};
-
-
-/**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
- */
-proto.pulumirpc.ResourceCallRequest.prototype.clearArgdependenciesMap = function() {
-  this.getArgdependenciesMap().clear();
-  return this;};
-
-
-/**
- * optional string provider = 4;
- * @return {string}
- */
-proto.pulumirpc.ResourceCallRequest.prototype.getProvider = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
- */
-proto.pulumirpc.ResourceCallRequest.prototype.setProvider = function(value) {
-  return jspb.Message.setProto3StringField(this, 4, value);
-};
-
-
-/**
- * optional string version = 5;
- * @return {string}
- */
-proto.pulumirpc.ResourceCallRequest.prototype.getVersion = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
- */
-proto.pulumirpc.ResourceCallRequest.prototype.setVersion = function(value) {
-  return jspb.Message.setProto3StringField(this, 5, value);
-};
-
-
-/**
- * optional string pluginDownloadURL = 13;
- * @return {string}
- */
-proto.pulumirpc.ResourceCallRequest.prototype.getPlugindownloadurl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 13, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
- */
-proto.pulumirpc.ResourceCallRequest.prototype.setPlugindownloadurl = function(value) {
-  return jspb.Message.setProto3StringField(this, 13, value);
-};
-
-
-/**
- * map<string, bytes> pluginChecksums = 16;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,!(string|Uint8Array)>}
- */
-proto.pulumirpc.ResourceCallRequest.prototype.getPluginchecksumsMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,!(string|Uint8Array)>} */ (
-      jspb.Message.getMapField(this, 16, opt_noLazyCreate,
-      null));
-};
-
-
-/**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
- */
-proto.pulumirpc.ResourceCallRequest.prototype.clearPluginchecksumsMap = function() {
-  this.getPluginchecksumsMap().clear();
-  return this;};
-
-
-/**
- * optional SourcePosition sourcePosition = 15;
- * @return {?proto.pulumirpc.SourcePosition}
- */
-proto.pulumirpc.ResourceCallRequest.prototype.getSourceposition = function() {
-  return /** @type{?proto.pulumirpc.SourcePosition} */ (
-    jspb.Message.getWrapperField(this, pulumi_source_pb.SourcePosition, 15));
-};
-
-
-/**
- * @param {?proto.pulumirpc.SourcePosition|undefined} value
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
-*/
-proto.pulumirpc.ResourceCallRequest.prototype.setSourceposition = function(value) {
-  return jspb.Message.setWrapperField(this, 15, value);
-};
-
-
-/**
- * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.ResourceCallRequest} returns this
- */
-proto.pulumirpc.ResourceCallRequest.prototype.clearSourceposition = function() {
-  return this.setSourceposition(undefined);
-};
-
-
-/**
- * Returns whether this field is set.
- * @return {boolean}
- */
-proto.pulumirpc.ResourceCallRequest.prototype.hasSourceposition = function() {
-  return jspb.Message.getField(this, 15) != null;
-};
-
-
-
-/**
- * List of repeated fields within this message type.
- * @private {!Array<number>}
- * @const
- */
-proto.pulumirpc.TransformResourceOptions.repeatedFields_ = [1,3,4,6,13];
 
 
 
@@ -4706,8 +4737,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.toObject = function(opt_includeInstance) {
-  return proto.pulumirpc.TransformResourceOptions.toObject(opt_includeInstance, this);
+proto.pulumirpc.ResourceInvokeRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.ResourceInvokeRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -4716,28 +4747,20 @@ proto.pulumirpc.TransformResourceOptions.prototype.toObject = function(opt_inclu
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.pulumirpc.TransformResourceOptions} msg The msg instance to transform.
+ * @param {!proto.pulumirpc.ResourceInvokeRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.TransformResourceOptions.toObject = function(includeInstance, msg) {
+proto.pulumirpc.ResourceInvokeRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    dependsOnList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f,
-    protect: jspb.Message.getBooleanFieldWithDefault(msg, 2, false),
-    ignoreChangesList: (f = jspb.Message.getRepeatedField(msg, 3)) == null ? undefined : f,
-    replaceOnChangesList: (f = jspb.Message.getRepeatedField(msg, 4)) == null ? undefined : f,
-    version: jspb.Message.getFieldWithDefault(msg, 5, ""),
-    aliasesList: jspb.Message.toObjectList(msg.getAliasesList(),
-    pulumi_alias_pb.Alias.toObject, includeInstance),
-    provider: jspb.Message.getFieldWithDefault(msg, 7, ""),
-    customTimeouts: (f = msg.getCustomTimeouts()) && proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.toObject(includeInstance, f),
-    pluginDownloadUrl: jspb.Message.getFieldWithDefault(msg, 9, ""),
-    retainOnDelete: jspb.Message.getBooleanFieldWithDefault(msg, 10, false),
-    deletedWith: jspb.Message.getFieldWithDefault(msg, 11, ""),
-    deleteBeforeReplace: jspb.Message.getBooleanFieldWithDefault(msg, 12, false),
-    additionalSecretOutputsList: (f = jspb.Message.getRepeatedField(msg, 13)) == null ? undefined : f,
-    providersMap: (f = msg.getProvidersMap()) ? f.toObject(includeInstance, undefined) : [],
-    pluginChecksumsMap: (f = msg.getPluginChecksumsMap()) ? f.toObject(includeInstance, undefined) : []
+    tok: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    args: (f = msg.getArgs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
+    provider: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    version: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    acceptresources: jspb.Message.getBooleanFieldWithDefault(msg, 5, false),
+    plugindownloadurl: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    pluginchecksumsMap: (f = msg.getPluginchecksumsMap()) ? f.toObject(includeInstance, undefined) : [],
+    sourceposition: (f = msg.getSourceposition()) && pulumi_source_pb.SourcePosition.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -4751,23 +4774,23 @@ proto.pulumirpc.TransformResourceOptions.toObject = function(includeInstance, ms
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.pulumirpc.TransformResourceOptions}
+ * @return {!proto.pulumirpc.ResourceInvokeRequest}
  */
-proto.pulumirpc.TransformResourceOptions.deserializeBinary = function(bytes) {
+proto.pulumirpc.ResourceInvokeRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.pulumirpc.TransformResourceOptions;
-  return proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.pulumirpc.ResourceInvokeRequest;
+  return proto.pulumirpc.ResourceInvokeRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.pulumirpc.TransformResourceOptions} msg The message object to deserialize into.
+ * @param {!proto.pulumirpc.ResourceInvokeRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.pulumirpc.TransformResourceOptions}
+ * @return {!proto.pulumirpc.ResourceInvokeRequest}
  */
-proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader = function(msg, reader) {
+proto.pulumirpc.ResourceInvokeRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -4776,70 +4799,40 @@ proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader = function(
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.addDependsOn(value);
+      msg.setTok(value);
       break;
     case 2:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setProtect(value);
+      var value = new google_protobuf_struct_pb.Struct;
+      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
+      msg.setArgs(value);
       break;
     case 3:
       var value = /** @type {string} */ (reader.readString());
-      msg.addIgnoreChanges(value);
+      msg.setProvider(value);
       break;
     case 4:
       var value = /** @type {string} */ (reader.readString());
-      msg.addReplaceOnChanges(value);
+      msg.setVersion(value);
       break;
     case 5:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setVersion(value);
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setAcceptresources(value);
       break;
     case 6:
-      var value = new pulumi_alias_pb.Alias;
-      reader.readMessage(value,pulumi_alias_pb.Alias.deserializeBinaryFromReader);
-      msg.addAliases(value);
-      break;
-    case 7:
       var value = /** @type {string} */ (reader.readString());
-      msg.setProvider(value);
+      msg.setPlugindownloadurl(value);
       break;
     case 8:
-      var value = new proto.pulumirpc.RegisterResourceRequest.CustomTimeouts;
-      reader.readMessage(value,proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.deserializeBinaryFromReader);
-      msg.setCustomTimeouts(value);
-      break;
-    case 9:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setPluginDownloadUrl(value);
-      break;
-    case 10:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setRetainOnDelete(value);
-      break;
-    case 11:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setDeletedWith(value);
-      break;
-    case 12:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setDeleteBeforeReplace(value);
-      break;
-    case 13:
-      var value = /** @type {string} */ (reader.readString());
-      msg.addAdditionalSecretOutputs(value);
-      break;
-    case 14:
-      var value = msg.getProvidersMap();
-      reader.readMessage(value, function(message, reader) {
-        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
-         });
-      break;
-    case 15:
-      var value = msg.getPluginChecksumsMap();
+      var value = msg.getPluginchecksumsMap();
       reader.readMessage(value, function(message, reader) {
         jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readBytes, null, "", "");
          });
       break;
+    case 7:
+      var value = new pulumi_source_pb.SourcePosition;
+      reader.readMessage(value,pulumi_source_pb.SourcePosition.deserializeBinaryFromReader);
+      msg.setSourceposition(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -4853,9 +4846,9 @@ proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader = function(
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.serializeBinary = function() {
+proto.pulumirpc.ResourceInvokeRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.pulumirpc.TransformResourceOptions.serializeBinaryToWriter(this, writer);
+  proto.pulumirpc.ResourceInvokeRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -4863,344 +4856,1774 @@ proto.pulumirpc.TransformResourceOptions.prototype.serializeBinary = function()
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.pulumirpc.TransformResourceOptions} message
+ * @param {!proto.pulumirpc.ResourceInvokeRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.TransformResourceOptions.serializeBinaryToWriter = function(message, writer) {
+proto.pulumirpc.ResourceInvokeRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getDependsOnList();
+  f = message.getTok();
   if (f.length > 0) {
-    writer.writeRepeatedString(
+    writer.writeString(
       1,
       f
     );
   }
-  f = message.getProtect();
-  if (f) {
-    writer.writeBool(
+  f = message.getArgs();
+  if (f != null) {
+    writer.writeMessage(
       2,
-      f
-    );
-  }
-  f = message.getIgnoreChangesList();
-  if (f.length > 0) {
-    writer.writeRepeatedString(
-      3,
-      f
-    );
-  }
-  f = message.getReplaceOnChangesList();
-  if (f.length > 0) {
-    writer.writeRepeatedString(
-      4,
-      f
-    );
-  }
-  f = message.getVersion();
-  if (f.length > 0) {
-    writer.writeString(
-      5,
-      f
-    );
-  }
-  f = message.getAliasesList();
-  if (f.length > 0) {
-    writer.writeRepeatedMessage(
-      6,
       f,
-      pulumi_alias_pb.Alias.serializeBinaryToWriter
+      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
     );
   }
   f = message.getProvider();
   if (f.length > 0) {
     writer.writeString(
-      7,
+      3,
       f
     );
   }
-  f = message.getCustomTimeouts();
-  if (f != null) {
-    writer.writeMessage(
-      8,
-      f,
-      proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.serializeBinaryToWriter
-    );
-  }
-  f = message.getPluginDownloadUrl();
+  f = message.getVersion();
   if (f.length > 0) {
     writer.writeString(
-      9,
+      4,
       f
     );
   }
-  f = message.getRetainOnDelete();
+  f = message.getAcceptresources();
   if (f) {
     writer.writeBool(
-      10,
+      5,
       f
     );
   }
-  f = message.getDeletedWith();
+  f = message.getPlugindownloadurl();
   if (f.length > 0) {
     writer.writeString(
-      11,
-      f
-    );
-  }
-  f = /** @type {boolean} */ (jspb.Message.getField(message, 12));
-  if (f != null) {
-    writer.writeBool(
-      12,
-      f
-    );
-  }
-  f = message.getAdditionalSecretOutputsList();
-  if (f.length > 0) {
-    writer.writeRepeatedString(
-      13,
+      6,
       f
     );
   }
-  f = message.getProvidersMap(true);
+  f = message.getPluginchecksumsMap(true);
   if (f && f.getLength() > 0) {
-    f.serializeBinary(14, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+    f.serializeBinary(8, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeBytes);
   }
-  f = message.getPluginChecksumsMap(true);
-  if (f && f.getLength() > 0) {
-    f.serializeBinary(15, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeBytes);
+  f = message.getSourceposition();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      pulumi_source_pb.SourcePosition.serializeBinaryToWriter
+    );
   }
 };
 
 
 /**
- * repeated string depends_on = 1;
- * @return {!Array<string>}
+ * optional string tok = 1;
+ * @return {string}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getDependsOnList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
+proto.pulumirpc.ResourceInvokeRequest.prototype.getTok = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * @param {string} value
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.setDependsOnList = function(value) {
-  return jspb.Message.setField(this, 1, value || []);
+proto.pulumirpc.ResourceInvokeRequest.prototype.setTok = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * optional google.protobuf.Struct args = 2;
+ * @return {?proto.google.protobuf.Struct}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.addDependsOn = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
+proto.pulumirpc.ResourceInvokeRequest.prototype.getArgs = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 2));
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * @param {?proto.google.protobuf.Struct|undefined} value
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+*/
+proto.pulumirpc.ResourceInvokeRequest.prototype.setArgs = function(value) {
+  return jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.clearDependsOnList = function() {
-  return this.setDependsOnList([]);
+proto.pulumirpc.ResourceInvokeRequest.prototype.clearArgs = function() {
+  return this.setArgs(undefined);
 };
 
 
 /**
- * optional bool protect = 2;
+ * Returns whether this field is set.
  * @return {boolean}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getProtect = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
+proto.pulumirpc.ResourceInvokeRequest.prototype.hasArgs = function() {
+  return jspb.Message.getField(this, 2) != null;
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * optional string provider = 3;
+ * @return {string}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.setProtect = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 2, value);
+proto.pulumirpc.ResourceInvokeRequest.prototype.getProvider = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
- * repeated string ignore_changes = 3;
- * @return {!Array<string>}
+ * @param {string} value
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getIgnoreChangesList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 3));
+proto.pulumirpc.ResourceInvokeRequest.prototype.setProvider = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 /**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * optional string version = 4;
+ * @return {string}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.setIgnoreChangesList = function(value) {
-  return jspb.Message.setField(this, 3, value || []);
+proto.pulumirpc.ResourceInvokeRequest.prototype.getVersion = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
 };
 
 
 /**
  * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.addIgnoreChanges = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+proto.pulumirpc.ResourceInvokeRequest.prototype.setVersion = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional bool acceptResources = 5;
+ * @return {boolean}
+ */
+proto.pulumirpc.ResourceInvokeRequest.prototype.getAcceptresources = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 5, false));
 };
 
 
-/**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
- */
-proto.pulumirpc.TransformResourceOptions.prototype.clearIgnoreChangesList = function() {
-  return this.setIgnoreChangesList([]);
+/**
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ */
+proto.pulumirpc.ResourceInvokeRequest.prototype.setAcceptresources = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 5, value);
+};
+
+
+/**
+ * optional string pluginDownloadURL = 6;
+ * @return {string}
+ */
+proto.pulumirpc.ResourceInvokeRequest.prototype.getPlugindownloadurl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ */
+proto.pulumirpc.ResourceInvokeRequest.prototype.setPlugindownloadurl = function(value) {
+  return jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+/**
+ * map<string, bytes> pluginChecksums = 8;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,!(string|Uint8Array)>}
+ */
+proto.pulumirpc.ResourceInvokeRequest.prototype.getPluginchecksumsMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,!(string|Uint8Array)>} */ (
+      jspb.Message.getMapField(this, 8, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ */
+proto.pulumirpc.ResourceInvokeRequest.prototype.clearPluginchecksumsMap = function() {
+  this.getPluginchecksumsMap().clear();
+  return this;};
+
+
+/**
+ * optional SourcePosition sourcePosition = 7;
+ * @return {?proto.pulumirpc.SourcePosition}
+ */
+proto.pulumirpc.ResourceInvokeRequest.prototype.getSourceposition = function() {
+  return /** @type{?proto.pulumirpc.SourcePosition} */ (
+    jspb.Message.getWrapperField(this, pulumi_source_pb.SourcePosition, 7));
+};
+
+
+/**
+ * @param {?proto.pulumirpc.SourcePosition|undefined} value
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+*/
+proto.pulumirpc.ResourceInvokeRequest.prototype.setSourceposition = function(value) {
+  return jspb.Message.setWrapperField(this, 7, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.ResourceInvokeRequest} returns this
+ */
+proto.pulumirpc.ResourceInvokeRequest.prototype.clearSourceposition = function() {
+  return this.setSourceposition(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.ResourceInvokeRequest.prototype.hasSourceposition = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.ResourceCallRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.ResourceCallRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.ResourceCallRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    tok: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    args: (f = msg.getArgs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
+    argdependenciesMap: (f = msg.getArgdependenciesMap()) ? f.toObject(includeInstance, proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.toObject) : [],
+    provider: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    version: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    plugindownloadurl: jspb.Message.getFieldWithDefault(msg, 13, ""),
+    pluginchecksumsMap: (f = msg.getPluginchecksumsMap()) ? f.toObject(includeInstance, undefined) : [],
+    sourceposition: (f = msg.getSourceposition()) && pulumi_source_pb.SourcePosition.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.ResourceCallRequest}
+ */
+proto.pulumirpc.ResourceCallRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.ResourceCallRequest;
+  return proto.pulumirpc.ResourceCallRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.ResourceCallRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.ResourceCallRequest}
+ */
+proto.pulumirpc.ResourceCallRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTok(value);
+      break;
+    case 2:
+      var value = new google_protobuf_struct_pb.Struct;
+      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
+      msg.setArgs(value);
+      break;
+    case 3:
+      var value = msg.getArgdependenciesMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readMessage, proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinaryFromReader, "", new proto.pulumirpc.ResourceCallRequest.ArgumentDependencies());
+         });
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProvider(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setVersion(value);
+      break;
+    case 13:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPlugindownloadurl(value);
+      break;
+    case 16:
+      var value = msg.getPluginchecksumsMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readBytes, null, "", "");
+         });
+      break;
+    case 15:
+      var value = new pulumi_source_pb.SourcePosition;
+      reader.readMessage(value,pulumi_source_pb.SourcePosition.deserializeBinaryFromReader);
+      msg.setSourceposition(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.ResourceCallRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.ResourceCallRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.ResourceCallRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getTok();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getArgs();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
+    );
+  }
+  f = message.getArgdependenciesMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(3, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeMessage, proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.serializeBinaryToWriter);
+  }
+  f = message.getProvider();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getVersion();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+  f = message.getPlugindownloadurl();
+  if (f.length > 0) {
+    writer.writeString(
+      13,
+      f
+    );
+  }
+  f = message.getPluginchecksumsMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(16, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeBytes);
+  }
+  f = message.getSourceposition();
+  if (f != null) {
+    writer.writeMessage(
+      15,
+      f,
+      pulumi_source_pb.SourcePosition.serializeBinaryToWriter
+    );
+  }
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.repeatedFields_ = [1];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    urnsList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies}
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.ResourceCallRequest.ArgumentDependencies;
+  return proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies}
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addUrns(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrnsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * repeated string urns = 1;
+ * @return {!Array<string>}
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.getUrnsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.setUrnsList = function(value) {
+  return jspb.Message.setField(this, 1, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.addUrns = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.ArgumentDependencies.prototype.clearUrnsList = function() {
+  return this.setUrnsList([]);
+};
+
+
+/**
+ * optional string tok = 1;
+ * @return {string}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.getTok = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.setTok = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional google.protobuf.Struct args = 2;
+ * @return {?proto.google.protobuf.Struct}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.getArgs = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 2));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Struct|undefined} value
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+*/
+proto.pulumirpc.ResourceCallRequest.prototype.setArgs = function(value) {
+  return jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.clearArgs = function() {
+  return this.setArgs(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.hasArgs = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * map<string, ArgumentDependencies> argDependencies = 3;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies>}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.getArgdependenciesMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,!proto.pulumirpc.ResourceCallRequest.ArgumentDependencies>} */ (
+      jspb.Message.getMapField(this, 3, opt_noLazyCreate,
+      proto.pulumirpc.ResourceCallRequest.ArgumentDependencies));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.clearArgdependenciesMap = function() {
+  this.getArgdependenciesMap().clear();
+  return this;};
+
+
+/**
+ * optional string provider = 4;
+ * @return {string}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.getProvider = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.setProvider = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional string version = 5;
+ * @return {string}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.getVersion = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.setVersion = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+/**
+ * optional string pluginDownloadURL = 13;
+ * @return {string}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.getPlugindownloadurl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 13, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.setPlugindownloadurl = function(value) {
+  return jspb.Message.setProto3StringField(this, 13, value);
+};
+
+
+/**
+ * map<string, bytes> pluginChecksums = 16;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,!(string|Uint8Array)>}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.getPluginchecksumsMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,!(string|Uint8Array)>} */ (
+      jspb.Message.getMapField(this, 16, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.clearPluginchecksumsMap = function() {
+  this.getPluginchecksumsMap().clear();
+  return this;};
+
+
+/**
+ * optional SourcePosition sourcePosition = 15;
+ * @return {?proto.pulumirpc.SourcePosition}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.getSourceposition = function() {
+  return /** @type{?proto.pulumirpc.SourcePosition} */ (
+    jspb.Message.getWrapperField(this, pulumi_source_pb.SourcePosition, 15));
+};
+
+
+/**
+ * @param {?proto.pulumirpc.SourcePosition|undefined} value
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+*/
+proto.pulumirpc.ResourceCallRequest.prototype.setSourceposition = function(value) {
+  return jspb.Message.setWrapperField(this, 15, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.ResourceCallRequest} returns this
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.clearSourceposition = function() {
+  return this.setSourceposition(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.ResourceCallRequest.prototype.hasSourceposition = function() {
+  return jspb.Message.getField(this, 15) != null;
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.pulumirpc.TransformResourceOptions.repeatedFields_ = [1,3,4,6,13];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.TransformResourceOptions.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.TransformResourceOptions} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.TransformResourceOptions.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    dependsOnList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f,
+    protect: jspb.Message.getBooleanFieldWithDefault(msg, 2, false),
+    ignoreChangesList: (f = jspb.Message.getRepeatedField(msg, 3)) == null ? undefined : f,
+    replaceOnChangesList: (f = jspb.Message.getRepeatedField(msg, 4)) == null ? undefined : f,
+    version: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    aliasesList: jspb.Message.toObjectList(msg.getAliasesList(),
+    pulumi_alias_pb.Alias.toObject, includeInstance),
+    provider: jspb.Message.getFieldWithDefault(msg, 7, ""),
+    customTimeouts: (f = msg.getCustomTimeouts()) && proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.toObject(includeInstance, f),
+    pluginDownloadUrl: jspb.Message.getFieldWithDefault(msg, 9, ""),
+    retainOnDelete: jspb.Message.getBooleanFieldWithDefault(msg, 10, false),
+    deletedWith: jspb.Message.getFieldWithDefault(msg, 11, ""),
+    deleteBeforeReplace: jspb.Message.getBooleanFieldWithDefault(msg, 12, false),
+    additionalSecretOutputsList: (f = jspb.Message.getRepeatedField(msg, 13)) == null ? undefined : f,
+    providersMap: (f = msg.getProvidersMap()) ? f.toObject(includeInstance, undefined) : [],
+    pluginChecksumsMap: (f = msg.getPluginChecksumsMap()) ? f.toObject(includeInstance, undefined) : []
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.TransformResourceOptions}
+ */
+proto.pulumirpc.TransformResourceOptions.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.TransformResourceOptions;
+  return proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.TransformResourceOptions} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.TransformResourceOptions}
+ */
+proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addDependsOn(value);
+      break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setProtect(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addIgnoreChanges(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addReplaceOnChanges(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setVersion(value);
+      break;
+    case 6:
+      var value = new pulumi_alias_pb.Alias;
+      reader.readMessage(value,pulumi_alias_pb.Alias.deserializeBinaryFromReader);
+      msg.addAliases(value);
+      break;
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProvider(value);
+      break;
+    case 8:
+      var value = new proto.pulumirpc.RegisterResourceRequest.CustomTimeouts;
+      reader.readMessage(value,proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.deserializeBinaryFromReader);
+      msg.setCustomTimeouts(value);
+      break;
+    case 9:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPluginDownloadUrl(value);
+      break;
+    case 10:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setRetainOnDelete(value);
+      break;
+    case 11:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDeletedWith(value);
+      break;
+    case 12:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setDeleteBeforeReplace(value);
+      break;
+    case 13:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addAdditionalSecretOutputs(value);
+      break;
+    case 14:
+      var value = msg.getProvidersMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
+         });
+      break;
+    case 15:
+      var value = msg.getPluginChecksumsMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readBytes, null, "", "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.TransformResourceOptions.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.TransformResourceOptions} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.TransformResourceOptions.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getDependsOnList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      1,
+      f
+    );
+  }
+  f = message.getProtect();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+  f = message.getIgnoreChangesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      3,
+      f
+    );
+  }
+  f = message.getReplaceOnChangesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      4,
+      f
+    );
+  }
+  f = message.getVersion();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+  f = message.getAliasesList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      6,
+      f,
+      pulumi_alias_pb.Alias.serializeBinaryToWriter
+    );
+  }
+  f = message.getProvider();
+  if (f.length > 0) {
+    writer.writeString(
+      7,
+      f
+    );
+  }
+  f = message.getCustomTimeouts();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.serializeBinaryToWriter
+    );
+  }
+  f = message.getPluginDownloadUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      9,
+      f
+    );
+  }
+  f = message.getRetainOnDelete();
+  if (f) {
+    writer.writeBool(
+      10,
+      f
+    );
+  }
+  f = message.getDeletedWith();
+  if (f.length > 0) {
+    writer.writeString(
+      11,
+      f
+    );
+  }
+  f = /** @type {boolean} */ (jspb.Message.getField(message, 12));
+  if (f != null) {
+    writer.writeBool(
+      12,
+      f
+    );
+  }
+  f = message.getAdditionalSecretOutputsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      13,
+      f
+    );
+  }
+  f = message.getProvidersMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(14, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+  f = message.getPluginChecksumsMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(15, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeBytes);
+  }
+};
+
+
+/**
+ * repeated string depends_on = 1;
+ * @return {!Array<string>}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getDependsOnList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setDependsOnList = function(value) {
+  return jspb.Message.setField(this, 1, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.addDependsOn = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.clearDependsOnList = function() {
+  return this.setDependsOnList([]);
+};
+
+
+/**
+ * optional bool protect = 2;
+ * @return {boolean}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getProtect = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setProtect = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
+/**
+ * repeated string ignore_changes = 3;
+ * @return {!Array<string>}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getIgnoreChangesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 3));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setIgnoreChangesList = function(value) {
+  return jspb.Message.setField(this, 3, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.addIgnoreChanges = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.clearIgnoreChangesList = function() {
+  return this.setIgnoreChangesList([]);
+};
+
+
+/**
+ * repeated string replace_on_changes = 4;
+ * @return {!Array<string>}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getReplaceOnChangesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 4));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setReplaceOnChangesList = function(value) {
+  return jspb.Message.setField(this, 4, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.addReplaceOnChanges = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 4, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.clearReplaceOnChangesList = function() {
+  return this.setReplaceOnChangesList([]);
+};
+
+
+/**
+ * optional string version = 5;
+ * @return {string}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getVersion = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setVersion = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+/**
+ * repeated Alias aliases = 6;
+ * @return {!Array<!proto.pulumirpc.Alias>}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getAliasesList = function() {
+  return /** @type{!Array<!proto.pulumirpc.Alias>} */ (
+    jspb.Message.getRepeatedWrapperField(this, pulumi_alias_pb.Alias, 6));
+};
+
+
+/**
+ * @param {!Array<!proto.pulumirpc.Alias>} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+*/
+proto.pulumirpc.TransformResourceOptions.prototype.setAliasesList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 6, value);
+};
+
+
+/**
+ * @param {!proto.pulumirpc.Alias=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.Alias}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.addAliases = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 6, opt_value, proto.pulumirpc.Alias, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.clearAliasesList = function() {
+  return this.setAliasesList([]);
+};
+
+
+/**
+ * optional string provider = 7;
+ * @return {string}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getProvider = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setProvider = function(value) {
+  return jspb.Message.setProto3StringField(this, 7, value);
+};
+
+
+/**
+ * optional RegisterResourceRequest.CustomTimeouts custom_timeouts = 8;
+ * @return {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getCustomTimeouts = function() {
+  return /** @type{?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts} */ (
+    jspb.Message.getWrapperField(this, proto.pulumirpc.RegisterResourceRequest.CustomTimeouts, 8));
+};
+
+
+/**
+ * @param {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts|undefined} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+*/
+proto.pulumirpc.TransformResourceOptions.prototype.setCustomTimeouts = function(value) {
+  return jspb.Message.setWrapperField(this, 8, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.clearCustomTimeouts = function() {
+  return this.setCustomTimeouts(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.hasCustomTimeouts = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+/**
+ * optional string plugin_download_url = 9;
+ * @return {string}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getPluginDownloadUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 9, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setPluginDownloadUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 9, value);
+};
+
+
+/**
+ * optional bool retain_on_delete = 10;
+ * @return {boolean}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getRetainOnDelete = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 10, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setRetainOnDelete = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 10, value);
+};
+
+
+/**
+ * optional string deleted_with = 11;
+ * @return {string}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getDeletedWith = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 11, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setDeletedWith = function(value) {
+  return jspb.Message.setProto3StringField(this, 11, value);
+};
+
+
+/**
+ * optional bool delete_before_replace = 12;
+ * @return {boolean}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getDeleteBeforeReplace = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 12, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setDeleteBeforeReplace = function(value) {
+  return jspb.Message.setField(this, 12, value);
+};
+
+
+/**
+ * Clears the field making it undefined.
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.clearDeleteBeforeReplace = function() {
+  return jspb.Message.setField(this, 12, undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.hasDeleteBeforeReplace = function() {
+  return jspb.Message.getField(this, 12) != null;
+};
+
+
+/**
+ * repeated string additional_secret_outputs = 13;
+ * @return {!Array<string>}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getAdditionalSecretOutputsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 13));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.setAdditionalSecretOutputsList = function(value) {
+  return jspb.Message.setField(this, 13, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.addAdditionalSecretOutputs = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 13, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.clearAdditionalSecretOutputsList = function() {
+  return this.setAdditionalSecretOutputsList([]);
+};
+
+
+/**
+ * map<string, string> providers = 14;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getProvidersMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 14, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.clearProvidersMap = function() {
+  this.getProvidersMap().clear();
+  return this;};
+
+
+/**
+ * map<string, bytes> plugin_checksums = 15;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,!(string|Uint8Array)>}
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.getPluginChecksumsMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,!(string|Uint8Array)>} */ (
+      jspb.Message.getMapField(this, 15, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ */
+proto.pulumirpc.TransformResourceOptions.prototype.clearPluginChecksumsMap = function() {
+  this.getPluginChecksumsMap().clear();
+  return this;};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.pulumirpc.TransformRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.TransformRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.TransformRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.TransformRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    type: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    name: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    custom: jspb.Message.getBooleanFieldWithDefault(msg, 3, false),
+    parent: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    properties: (f = msg.getProperties()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
+    options: (f = msg.getOptions()) && proto.pulumirpc.TransformResourceOptions.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * repeated string replace_on_changes = 4;
- * @return {!Array<string>}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.TransformRequest}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getReplaceOnChangesList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 4));
+proto.pulumirpc.TransformRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.TransformRequest;
+  return proto.pulumirpc.TransformRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.TransformRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.TransformRequest}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.setReplaceOnChangesList = function(value) {
-  return jspb.Message.setField(this, 4, value || []);
+proto.pulumirpc.TransformRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setType(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 3:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setCustom(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setParent(value);
+      break;
+    case 5:
+      var value = new google_protobuf_struct_pb.Struct;
+      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
+      msg.setProperties(value);
+      break;
+    case 6:
+      var value = new proto.pulumirpc.TransformResourceOptions;
+      reader.readMessage(value,proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader);
+      msg.setOptions(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.addReplaceOnChanges = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 4, value, opt_index);
+proto.pulumirpc.TransformRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.TransformRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.TransformRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.TransformResourceOptions.prototype.clearReplaceOnChangesList = function() {
-  return this.setReplaceOnChangesList([]);
+proto.pulumirpc.TransformRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getType();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getCustom();
+  if (f) {
+    writer.writeBool(
+      3,
+      f
+    );
+  }
+  f = message.getParent();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getProperties();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
+    );
+  }
+  f = message.getOptions();
+  if (f != null) {
+    writer.writeMessage(
+      6,
+      f,
+      proto.pulumirpc.TransformResourceOptions.serializeBinaryToWriter
+    );
+  }
 };
 
 
 /**
- * optional string version = 5;
+ * optional string type = 1;
  * @return {string}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getVersion = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+proto.pulumirpc.TransformRequest.prototype.getType = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * @return {!proto.pulumirpc.TransformRequest} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.setVersion = function(value) {
-  return jspb.Message.setProto3StringField(this, 5, value);
+proto.pulumirpc.TransformRequest.prototype.setType = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * repeated Alias aliases = 6;
- * @return {!Array<!proto.pulumirpc.Alias>}
+ * optional string name = 2;
+ * @return {string}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getAliasesList = function() {
-  return /** @type{!Array<!proto.pulumirpc.Alias>} */ (
-    jspb.Message.getRepeatedWrapperField(this, pulumi_alias_pb.Alias, 6));
+proto.pulumirpc.TransformRequest.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
- * @param {!Array<!proto.pulumirpc.Alias>} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
-*/
-proto.pulumirpc.TransformResourceOptions.prototype.setAliasesList = function(value) {
-  return jspb.Message.setRepeatedWrapperField(this, 6, value);
+ * @param {string} value
+ * @return {!proto.pulumirpc.TransformRequest} returns this
+ */
+proto.pulumirpc.TransformRequest.prototype.setName = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * @param {!proto.pulumirpc.Alias=} opt_value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.Alias}
+ * optional bool custom = 3;
+ * @return {boolean}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.addAliases = function(opt_value, opt_index) {
-  return jspb.Message.addToRepeatedWrapperField(this, 6, opt_value, proto.pulumirpc.Alias, opt_index);
+proto.pulumirpc.TransformRequest.prototype.getCustom = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 3, false));
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.TransformRequest} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.clearAliasesList = function() {
-  return this.setAliasesList([]);
+proto.pulumirpc.TransformRequest.prototype.setCustom = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 3, value);
 };
 
 
 /**
- * optional string provider = 7;
+ * optional string parent = 4;
  * @return {string}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getProvider = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+proto.pulumirpc.TransformRequest.prototype.getParent = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * @return {!proto.pulumirpc.TransformRequest} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.setProvider = function(value) {
-  return jspb.Message.setProto3StringField(this, 7, value);
+proto.pulumirpc.TransformRequest.prototype.setParent = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
 };
 
 
 /**
- * optional RegisterResourceRequest.CustomTimeouts custom_timeouts = 8;
- * @return {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts}
+ * optional google.protobuf.Struct properties = 5;
+ * @return {?proto.google.protobuf.Struct}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getCustomTimeouts = function() {
-  return /** @type{?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts} */ (
-    jspb.Message.getWrapperField(this, proto.pulumirpc.RegisterResourceRequest.CustomTimeouts, 8));
+proto.pulumirpc.TransformRequest.prototype.getProperties = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 5));
 };
 
 
 /**
- * @param {?proto.pulumirpc.RegisterResourceRequest.CustomTimeouts|undefined} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * @param {?proto.google.protobuf.Struct|undefined} value
+ * @return {!proto.pulumirpc.TransformRequest} returns this
 */
-proto.pulumirpc.TransformResourceOptions.prototype.setCustomTimeouts = function(value) {
-  return jspb.Message.setWrapperField(this, 8, value);
+proto.pulumirpc.TransformRequest.prototype.setProperties = function(value) {
+  return jspb.Message.setWrapperField(this, 5, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * @return {!proto.pulumirpc.TransformRequest} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.clearCustomTimeouts = function() {
-  return this.setCustomTimeouts(undefined);
+proto.pulumirpc.TransformRequest.prototype.clearProperties = function() {
+  return this.setProperties(undefined);
 };
 
 
@@ -5208,180 +6631,248 @@ proto.pulumirpc.TransformResourceOptions.prototype.clearCustomTimeouts = functio
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.hasCustomTimeouts = function() {
-  return jspb.Message.getField(this, 8) != null;
+proto.pulumirpc.TransformRequest.prototype.hasProperties = function() {
+  return jspb.Message.getField(this, 5) != null;
 };
 
 
 /**
- * optional string plugin_download_url = 9;
- * @return {string}
+ * optional TransformResourceOptions options = 6;
+ * @return {?proto.pulumirpc.TransformResourceOptions}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getPluginDownloadUrl = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 9, ""));
+proto.pulumirpc.TransformRequest.prototype.getOptions = function() {
+  return /** @type{?proto.pulumirpc.TransformResourceOptions} */ (
+    jspb.Message.getWrapperField(this, proto.pulumirpc.TransformResourceOptions, 6));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * @param {?proto.pulumirpc.TransformResourceOptions|undefined} value
+ * @return {!proto.pulumirpc.TransformRequest} returns this
+*/
+proto.pulumirpc.TransformRequest.prototype.setOptions = function(value) {
+  return jspb.Message.setWrapperField(this, 6, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.TransformRequest} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.setPluginDownloadUrl = function(value) {
-  return jspb.Message.setProto3StringField(this, 9, value);
+proto.pulumirpc.TransformRequest.prototype.clearOptions = function() {
+  return this.setOptions(undefined);
 };
 
 
 /**
- * optional bool retain_on_delete = 10;
+ * Returns whether this field is set.
  * @return {boolean}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getRetainOnDelete = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 10, false));
+proto.pulumirpc.TransformRequest.prototype.hasOptions = function() {
+  return jspb.Message.getField(this, 6) != null;
 };
 
 
-/**
- * @param {boolean} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
- */
-proto.pulumirpc.TransformResourceOptions.prototype.setRetainOnDelete = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 10, value);
-};
 
 
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * optional string deleted_with = 11;
- * @return {string}
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getDeletedWith = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 11, ""));
+proto.pulumirpc.TransformResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.TransformResponse.toObject(opt_includeInstance, this);
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.TransformResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.TransformResourceOptions.prototype.setDeletedWith = function(value) {
-  return jspb.Message.setProto3StringField(this, 11, value);
+proto.pulumirpc.TransformResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    properties: (f = msg.getProperties()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
+    options: (f = msg.getOptions()) && proto.pulumirpc.TransformResourceOptions.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
 };
+}
 
 
 /**
- * optional bool delete_before_replace = 12;
- * @return {boolean}
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.TransformResponse}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getDeleteBeforeReplace = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 12, false));
+proto.pulumirpc.TransformResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.TransformResponse;
+  return proto.pulumirpc.TransformResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.TransformResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.TransformResponse}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.setDeleteBeforeReplace = function(value) {
-  return jspb.Message.setField(this, 12, value);
+proto.pulumirpc.TransformResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = new google_protobuf_struct_pb.Struct;
+      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
+      msg.setProperties(value);
+      break;
+    case 2:
+      var value = new proto.pulumirpc.TransformResourceOptions;
+      reader.readMessage(value,proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader);
+      msg.setOptions(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
 };
 
 
 /**
- * Clears the field making it undefined.
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.clearDeleteBeforeReplace = function() {
-  return jspb.Message.setField(this, 12, undefined);
+proto.pulumirpc.TransformResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.TransformResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.TransformResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.TransformResourceOptions.prototype.hasDeleteBeforeReplace = function() {
-  return jspb.Message.getField(this, 12) != null;
+proto.pulumirpc.TransformResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getProperties();
+  if (f != null) {
+    writer.writeMessage(
+      1,
+      f,
+      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
+    );
+  }
+  f = message.getOptions();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      proto.pulumirpc.TransformResourceOptions.serializeBinaryToWriter
+    );
+  }
 };
 
 
 /**
- * repeated string additional_secret_outputs = 13;
- * @return {!Array<string>}
+ * optional google.protobuf.Struct properties = 1;
+ * @return {?proto.google.protobuf.Struct}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getAdditionalSecretOutputsList = function() {
-  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 13));
+proto.pulumirpc.TransformResponse.prototype.getProperties = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 1));
 };
 
 
 /**
- * @param {!Array<string>} value
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
- */
-proto.pulumirpc.TransformResourceOptions.prototype.setAdditionalSecretOutputsList = function(value) {
-  return jspb.Message.setField(this, 13, value || []);
+ * @param {?proto.google.protobuf.Struct|undefined} value
+ * @return {!proto.pulumirpc.TransformResponse} returns this
+*/
+proto.pulumirpc.TransformResponse.prototype.setProperties = function(value) {
+  return jspb.Message.setWrapperField(this, 1, value);
 };
 
 
 /**
- * @param {string} value
- * @param {number=} opt_index
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.TransformResponse} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.addAdditionalSecretOutputs = function(value, opt_index) {
-  return jspb.Message.addToRepeatedField(this, 13, value, opt_index);
+proto.pulumirpc.TransformResponse.prototype.clearProperties = function() {
+  return this.setProperties(undefined);
 };
 
 
 /**
- * Clears the list making it empty but non-null.
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.clearAdditionalSecretOutputsList = function() {
-  return this.setAdditionalSecretOutputsList([]);
+proto.pulumirpc.TransformResponse.prototype.hasProperties = function() {
+  return jspb.Message.getField(this, 1) != null;
 };
 
 
 /**
- * map<string, string> providers = 14;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,string>}
+ * optional TransformResourceOptions options = 2;
+ * @return {?proto.pulumirpc.TransformResourceOptions}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getProvidersMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,string>} */ (
-      jspb.Message.getMapField(this, 14, opt_noLazyCreate,
-      null));
+proto.pulumirpc.TransformResponse.prototype.getOptions = function() {
+  return /** @type{?proto.pulumirpc.TransformResourceOptions} */ (
+    jspb.Message.getWrapperField(this, proto.pulumirpc.TransformResourceOptions, 2));
 };
 
 
 /**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
- */
-proto.pulumirpc.TransformResourceOptions.prototype.clearProvidersMap = function() {
-  this.getProvidersMap().clear();
-  return this;};
+ * @param {?proto.pulumirpc.TransformResourceOptions|undefined} value
+ * @return {!proto.pulumirpc.TransformResponse} returns this
+*/
+proto.pulumirpc.TransformResponse.prototype.setOptions = function(value) {
+  return jspb.Message.setWrapperField(this, 2, value);
+};
 
 
 /**
- * map<string, bytes> plugin_checksums = 15;
- * @param {boolean=} opt_noLazyCreate Do not create the map if
- * empty, instead returning `undefined`
- * @return {!jspb.Map<string,!(string|Uint8Array)>}
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.TransformResponse} returns this
  */
-proto.pulumirpc.TransformResourceOptions.prototype.getPluginChecksumsMap = function(opt_noLazyCreate) {
-  return /** @type {!jspb.Map<string,!(string|Uint8Array)>} */ (
-      jspb.Message.getMapField(this, 15, opt_noLazyCreate,
-      null));
+proto.pulumirpc.TransformResponse.prototype.clearOptions = function() {
+  return this.setOptions(undefined);
 };
 
 
 /**
- * Clears values from the map. The map will be non-null.
- * @return {!proto.pulumirpc.TransformResourceOptions} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.pulumirpc.TransformResourceOptions.prototype.clearPluginChecksumsMap = function() {
-  this.getPluginChecksumsMap().clear();
-  return this;};
+proto.pulumirpc.TransformResponse.prototype.hasOptions = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
 
 
 
@@ -5400,8 +6891,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.pulumirpc.TransformRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.pulumirpc.TransformRequest.toObject(opt_includeInstance, this);
+proto.pulumirpc.ResourceHookRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.ResourceHookRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -5410,18 +6901,20 @@ proto.pulumirpc.TransformRequest.prototype.toObject = function(opt_includeInstan
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.pulumirpc.TransformRequest} msg The msg instance to transform.
+ * @param {!proto.pulumirpc.ResourceHookRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.TransformRequest.toObject = function(includeInstance, msg) {
+proto.pulumirpc.ResourceHookRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-    type: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    name: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    custom: jspb.Message.getBooleanFieldWithDefault(msg, 3, false),
-    parent: jspb.Message.getFieldWithDefault(msg, 4, ""),
-    properties: (f = msg.getProperties()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
-    options: (f = msg.getOptions()) && proto.pulumirpc.TransformResourceOptions.toObject(includeInstance, f)
+    urn: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    id: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    operation: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    after: jspb.Message.getBooleanFieldWithDefault(msg, 4, false),
+    oldInputs: (f = msg.getOldInputs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
+    oldOutputs: (f = msg.getOldOutputs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
+    newInputs: (f = msg.getNewInputs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
+    newOutputs: (f = msg.getNewOutputs()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -5435,23 +6928,23 @@ proto.pulumirpc.TransformRequest.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.pulumirpc.TransformRequest}
+ * @return {!proto.pulumirpc.ResourceHookRequest}
  */
-proto.pulumirpc.TransformRequest.deserializeBinary = function(bytes) {
+proto.pulumirpc.ResourceHookRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.pulumirpc.TransformRequest;
-  return proto.pulumirpc.TransformRequest.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.pulumirpc.ResourceHookRequest;
+  return proto.pulumirpc.ResourceHookRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.pulumirpc.TransformRequest} msg The message object to deserialize into.
+ * @param {!proto.pulumirpc.ResourceHookRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.pulumirpc.TransformRequest}
+ * @return {!proto.pulumirpc.ResourceHookRequest}
  */
-proto.pulumirpc.TransformRequest.deserializeBinaryFromReader = function(msg, reader) {
+proto.pulumirpc.ResourceHookRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -5460,29 +6953,39 @@ proto.pulumirpc.TransformRequest.deserializeBinaryFromReader = function(msg, rea
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setType(value);
+      msg.setUrn(value);
       break;
     case 2:
       var value = /** @type {string} */ (reader.readString());
-      msg.setName(value);
+      msg.setId(value);
       break;
     case 3:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setCustom(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setOperation(value);
       break;
     case 4:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setParent(value);
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setAfter(value);
       break;
     case 5:
       var value = new google_protobuf_struct_pb.Struct;
       reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
-      msg.setProperties(value);
+      msg.setOldInputs(value);
       break;
     case 6:
-      var value = new proto.pulumirpc.TransformResourceOptions;
-      reader.readMessage(value,proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader);
-      msg.setOptions(value);
+      var value = new google_protobuf_struct_pb.Struct;
+      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
+      msg.setOldOutputs(value);
+      break;
+    case 7:
+      var value = new google_protobuf_struct_pb.Struct;
+      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
+      msg.setNewInputs(value);
+      break;
+    case 8:
+      var value = new google_protobuf_struct_pb.Struct;
+      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
+      msg.setNewOutputs(value);
       break;
     default:
       reader.skipField();
@@ -5497,9 +7000,9 @@ proto.pulumirpc.TransformRequest.deserializeBinaryFromReader = function(msg, rea
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.pulumirpc.TransformRequest.prototype.serializeBinary = function() {
+proto.pulumirpc.ResourceHookRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.pulumirpc.TransformRequest.serializeBinaryToWriter(this, writer);
+  proto.pulumirpc.ResourceHookRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -5507,41 +7010,41 @@ proto.pulumirpc.TransformRequest.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.pulumirpc.TransformRequest} message
+ * @param {!proto.pulumirpc.ResourceHookRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.TransformRequest.serializeBinaryToWriter = function(message, writer) {
+proto.pulumirpc.ResourceHookRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getType();
+  f = message.getUrn();
   if (f.length > 0) {
     writer.writeString(
       1,
       f
     );
   }
-  f = message.getName();
+  f = message.getId();
   if (f.length > 0) {
     writer.writeString(
       2,
       f
     );
   }
-  f = message.getCustom();
-  if (f) {
-    writer.writeBool(
+  f = message.getOperation();
+  if (f.length > 0) {
+    writer.writeString(
       3,
       f
     );
   }
-  f = message.getParent();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getAfter();
+  if (f) {
+    writer.writeBool(
       4,
       f
     );
   }
-  f = message.getProperties();
+  f = message.getOldInputs();
   if (f != null) {
     writer.writeMessage(
       5,
@@ -5549,94 +7052,110 @@ proto.pulumirpc.TransformRequest.serializeBinaryToWriter = function(message, wri
       google_protobuf_struct_pb.Struct.serializeBinaryToWriter
     );
   }
-  f = message.getOptions();
+  f = message.getOldOutputs();
   if (f != null) {
     writer.writeMessage(
       6,
       f,
-      proto.pulumirpc.TransformResourceOptions.serializeBinaryToWriter
+      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
+    );
+  }
+  f = message.getNewInputs();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
+    );
+  }
+  f = message.getNewOutputs();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
     );
   }
 };
 
 
 /**
- * optional string type = 1;
+ * optional string urn = 1;
  * @return {string}
  */
-proto.pulumirpc.TransformRequest.prototype.getType = function() {
+proto.pulumirpc.ResourceHookRequest.prototype.getUrn = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.pulumirpc.TransformRequest} returns this
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
  */
-proto.pulumirpc.TransformRequest.prototype.setType = function(value) {
+proto.pulumirpc.ResourceHookRequest.prototype.setUrn = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string name = 2;
+ * optional string id = 2;
  * @return {string}
  */
-proto.pulumirpc.TransformRequest.prototype.getName = function() {
+proto.pulumirpc.ResourceHookRequest.prototype.getId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.pulumirpc.TransformRequest} returns this
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
  */
-proto.pulumirpc.TransformRequest.prototype.setName = function(value) {
+proto.pulumirpc.ResourceHookRequest.prototype.setId = function(value) {
   return jspb.Message.setProto3StringField(this, 2, value);
 };
 
 
 /**
- * optional bool custom = 3;
- * @return {boolean}
+ * optional string operation = 3;
+ * @return {string}
  */
-proto.pulumirpc.TransformRequest.prototype.getCustom = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 3, false));
+proto.pulumirpc.ResourceHookRequest.prototype.getOperation = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
 };
 
 
 /**
- * @param {boolean} value
- * @return {!proto.pulumirpc.TransformRequest} returns this
+ * @param {string} value
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
  */
-proto.pulumirpc.TransformRequest.prototype.setCustom = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 3, value);
+proto.pulumirpc.ResourceHookRequest.prototype.setOperation = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 /**
- * optional string parent = 4;
- * @return {string}
+ * optional bool after = 4;
+ * @return {boolean}
  */
-proto.pulumirpc.TransformRequest.prototype.getParent = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+proto.pulumirpc.ResourceHookRequest.prototype.getAfter = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.pulumirpc.TransformRequest} returns this
+ * @param {boolean} value
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
  */
-proto.pulumirpc.TransformRequest.prototype.setParent = function(value) {
-  return jspb.Message.setProto3StringField(this, 4, value);
+proto.pulumirpc.ResourceHookRequest.prototype.setAfter = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 4, value);
 };
 
 
 /**
- * optional google.protobuf.Struct properties = 5;
+ * optional google.protobuf.Struct old_inputs = 5;
  * @return {?proto.google.protobuf.Struct}
  */
-proto.pulumirpc.TransformRequest.prototype.getProperties = function() {
+proto.pulumirpc.ResourceHookRequest.prototype.getOldInputs = function() {
   return /** @type{?proto.google.protobuf.Struct} */ (
     jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 5));
 };
@@ -5644,19 +7163,19 @@ proto.pulumirpc.TransformRequest.prototype.getProperties = function() {
 
 /**
  * @param {?proto.google.protobuf.Struct|undefined} value
- * @return {!proto.pulumirpc.TransformRequest} returns this
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
 */
-proto.pulumirpc.TransformRequest.prototype.setProperties = function(value) {
+proto.pulumirpc.ResourceHookRequest.prototype.setOldInputs = function(value) {
   return jspb.Message.setWrapperField(this, 5, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.TransformRequest} returns this
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
  */
-proto.pulumirpc.TransformRequest.prototype.clearProperties = function() {
-  return this.setProperties(undefined);
+proto.pulumirpc.ResourceHookRequest.prototype.clearOldInputs = function() {
+  return this.setOldInputs(undefined);
 };
 
 
@@ -5664,36 +7183,36 @@ proto.pulumirpc.TransformRequest.prototype.clearProperties = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.pulumirpc.TransformRequest.prototype.hasProperties = function() {
+proto.pulumirpc.ResourceHookRequest.prototype.hasOldInputs = function() {
   return jspb.Message.getField(this, 5) != null;
 };
 
 
 /**
- * optional TransformResourceOptions options = 6;
- * @return {?proto.pulumirpc.TransformResourceOptions}
+ * optional google.protobuf.Struct old_outputs = 6;
+ * @return {?proto.google.protobuf.Struct}
  */
-proto.pulumirpc.TransformRequest.prototype.getOptions = function() {
-  return /** @type{?proto.pulumirpc.TransformResourceOptions} */ (
-    jspb.Message.getWrapperField(this, proto.pulumirpc.TransformResourceOptions, 6));
+proto.pulumirpc.ResourceHookRequest.prototype.getOldOutputs = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 6));
 };
 
 
 /**
- * @param {?proto.pulumirpc.TransformResourceOptions|undefined} value
- * @return {!proto.pulumirpc.TransformRequest} returns this
+ * @param {?proto.google.protobuf.Struct|undefined} value
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
 */
-proto.pulumirpc.TransformRequest.prototype.setOptions = function(value) {
+proto.pulumirpc.ResourceHookRequest.prototype.setOldOutputs = function(value) {
   return jspb.Message.setWrapperField(this, 6, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.TransformRequest} returns this
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
  */
-proto.pulumirpc.TransformRequest.prototype.clearOptions = function() {
-  return this.setOptions(undefined);
+proto.pulumirpc.ResourceHookRequest.prototype.clearOldOutputs = function() {
+  return this.setOldOutputs(undefined);
 };
 
 
@@ -5701,11 +7220,85 @@ proto.pulumirpc.TransformRequest.prototype.clearOptions = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.pulumirpc.TransformRequest.prototype.hasOptions = function() {
+proto.pulumirpc.ResourceHookRequest.prototype.hasOldOutputs = function() {
   return jspb.Message.getField(this, 6) != null;
 };
 
 
+/**
+ * optional google.protobuf.Struct new_inputs = 7;
+ * @return {?proto.google.protobuf.Struct}
+ */
+proto.pulumirpc.ResourceHookRequest.prototype.getNewInputs = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 7));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Struct|undefined} value
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
+*/
+proto.pulumirpc.ResourceHookRequest.prototype.setNewInputs = function(value) {
+  return jspb.Message.setWrapperField(this, 7, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
+ */
+proto.pulumirpc.ResourceHookRequest.prototype.clearNewInputs = function() {
+  return this.setNewInputs(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.ResourceHookRequest.prototype.hasNewInputs = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+/**
+ * optional google.protobuf.Struct new_outputs = 8;
+ * @return {?proto.google.protobuf.Struct}
+ */
+proto.pulumirpc.ResourceHookRequest.prototype.getNewOutputs = function() {
+  return /** @type{?proto.google.protobuf.Struct} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 8));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Struct|undefined} value
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
+*/
+proto.pulumirpc.ResourceHookRequest.prototype.setNewOutputs = function(value) {
+  return jspb.Message.setWrapperField(this, 8, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.ResourceHookRequest} returns this
+ */
+proto.pulumirpc.ResourceHookRequest.prototype.clearNewOutputs = function() {
+  return this.setNewOutputs(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.ResourceHookRequest.prototype.hasNewOutputs = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
 
 
 
@@ -5722,8 +7315,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.pulumirpc.TransformResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.pulumirpc.TransformResponse.toObject(opt_includeInstance, this);
+proto.pulumirpc.ResourceHookResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.ResourceHookResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -5732,14 +7325,13 @@ proto.pulumirpc.TransformResponse.prototype.toObject = function(opt_includeInsta
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.pulumirpc.TransformResponse} msg The msg instance to transform.
+ * @param {!proto.pulumirpc.ResourceHookResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.TransformResponse.toObject = function(includeInstance, msg) {
+proto.pulumirpc.ResourceHookResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-    properties: (f = msg.getProperties()) && google_protobuf_struct_pb.Struct.toObject(includeInstance, f),
-    options: (f = msg.getOptions()) && proto.pulumirpc.TransformResourceOptions.toObject(includeInstance, f)
+    error: jspb.Message.getFieldWithDefault(msg, 1, "")
   };
 
   if (includeInstance) {
@@ -5753,23 +7345,23 @@ proto.pulumirpc.TransformResponse.toObject = function(includeInstance, msg) {
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.pulumirpc.TransformResponse}
+ * @return {!proto.pulumirpc.ResourceHookResponse}
  */
-proto.pulumirpc.TransformResponse.deserializeBinary = function(bytes) {
+proto.pulumirpc.ResourceHookResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.pulumirpc.TransformResponse;
-  return proto.pulumirpc.TransformResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.pulumirpc.ResourceHookResponse;
+  return proto.pulumirpc.ResourceHookResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.pulumirpc.TransformResponse} msg The message object to deserialize into.
+ * @param {!proto.pulumirpc.ResourceHookResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.pulumirpc.TransformResponse}
+ * @return {!proto.pulumirpc.ResourceHookResponse}
  */
-proto.pulumirpc.TransformResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.pulumirpc.ResourceHookResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -5777,14 +7369,8 @@ proto.pulumirpc.TransformResponse.deserializeBinaryFromReader = function(msg, re
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = new google_protobuf_struct_pb.Struct;
-      reader.readMessage(value,google_protobuf_struct_pb.Struct.deserializeBinaryFromReader);
-      msg.setProperties(value);
-      break;
-    case 2:
-      var value = new proto.pulumirpc.TransformResourceOptions;
-      reader.readMessage(value,proto.pulumirpc.TransformResourceOptions.deserializeBinaryFromReader);
-      msg.setOptions(value);
+      var value = /** @type {string} */ (reader.readString());
+      msg.setError(value);
       break;
     default:
       reader.skipField();
@@ -5799,9 +7385,9 @@ proto.pulumirpc.TransformResponse.deserializeBinaryFromReader = function(msg, re
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.pulumirpc.TransformResponse.prototype.serializeBinary = function() {
+proto.pulumirpc.ResourceHookResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.pulumirpc.TransformResponse.serializeBinaryToWriter(this, writer);
+  proto.pulumirpc.ResourceHookResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -5809,102 +7395,37 @@ proto.pulumirpc.TransformResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.pulumirpc.TransformResponse} message
+ * @param {!proto.pulumirpc.ResourceHookResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.pulumirpc.TransformResponse.serializeBinaryToWriter = function(message, writer) {
+proto.pulumirpc.ResourceHookResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getProperties();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getError();
+  if (f.length > 0) {
+    writer.writeString(
       1,
-      f,
-      google_protobuf_struct_pb.Struct.serializeBinaryToWriter
-    );
-  }
-  f = message.getOptions();
-  if (f != null) {
-    writer.writeMessage(
-      2,
-      f,
-      proto.pulumirpc.TransformResourceOptions.serializeBinaryToWriter
+      f
     );
   }
 };
 
 
 /**
- * optional google.protobuf.Struct properties = 1;
- * @return {?proto.google.protobuf.Struct}
- */
-proto.pulumirpc.TransformResponse.prototype.getProperties = function() {
-  return /** @type{?proto.google.protobuf.Struct} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_struct_pb.Struct, 1));
-};
-
-
-/**
- * @param {?proto.google.protobuf.Struct|undefined} value
- * @return {!proto.pulumirpc.TransformResponse} returns this
-*/
-proto.pulumirpc.TransformResponse.prototype.setProperties = function(value) {
-  return jspb.Message.setWrapperField(this, 1, value);
-};
-
-
-/**
- * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.TransformResponse} returns this
- */
-proto.pulumirpc.TransformResponse.prototype.clearProperties = function() {
-  return this.setProperties(undefined);
-};
-
-
-/**
- * Returns whether this field is set.
- * @return {boolean}
- */
-proto.pulumirpc.TransformResponse.prototype.hasProperties = function() {
-  return jspb.Message.getField(this, 1) != null;
-};
-
-
-/**
- * optional TransformResourceOptions options = 2;
- * @return {?proto.pulumirpc.TransformResourceOptions}
- */
-proto.pulumirpc.TransformResponse.prototype.getOptions = function() {
-  return /** @type{?proto.pulumirpc.TransformResourceOptions} */ (
-    jspb.Message.getWrapperField(this, proto.pulumirpc.TransformResourceOptions, 2));
-};
-
-
-/**
- * @param {?proto.pulumirpc.TransformResourceOptions|undefined} value
- * @return {!proto.pulumirpc.TransformResponse} returns this
-*/
-proto.pulumirpc.TransformResponse.prototype.setOptions = function(value) {
-  return jspb.Message.setWrapperField(this, 2, value);
-};
-
-
-/**
- * Clears the message field making it undefined.
- * @return {!proto.pulumirpc.TransformResponse} returns this
+ * optional string error = 1;
+ * @return {string}
  */
-proto.pulumirpc.TransformResponse.prototype.clearOptions = function() {
-  return this.setOptions(undefined);
+proto.pulumirpc.ResourceHookResponse.prototype.getError = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @return {!proto.pulumirpc.ResourceHookResponse} returns this
  */
-proto.pulumirpc.TransformResponse.prototype.hasOptions = function() {
-  return jspb.Message.getField(this, 2) != null;
+proto.pulumirpc.ResourceHookResponse.prototype.setError = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
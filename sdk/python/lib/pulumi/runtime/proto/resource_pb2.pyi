@@ -258,6 +258,89 @@ class RegisterResourceRequest(google.protobuf.message.Message):
         ) -> None: ...
         def ClearField(self, field_name: typing_extensions.Literal["create", b"create", "delete", b"delete", "update", b"update"]) -> None: ...
 
+    @typing_extensions.final
+    class RetryPolicy(google.protobuf.message.Message):
+        """RetryPolicy describes how the engine should retry provider operations on the resource that fail with transient
+        errors.
+        """
+
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        MAXATTEMPTS_FIELD_NUMBER: builtins.int
+        BACKOFF_FIELD_NUMBER: builtins.int
+        MAXBACKOFF_FIELD_NUMBER: builtins.int
+        RETRYON_FIELD_NUMBER: builtins.int
+        maxAttempts: builtins.int
+        """The maximum number of attempts at each operation, including the first."""
+        backoff: builtins.str
+        """The delay before the first retry represented as a string e.g. 5s, doubled for each later retry."""
+        maxBackoff: builtins.str
+        """The maximum delay between retries represented as a string e.g. 1m."""
+        @property
+        def retryOn(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.str]:
+            """The names of the gRPC status codes to retry on e.g. Unavailable."""
+        def __init__(
+            self,
+            *,
+            maxAttempts: builtins.int = ...,
+            backoff: builtins.str = ...,
+            maxBackoff: builtins.str = ...,
+            retryOn: collections.abc.Iterable[builtins.str] | None = ...,
+        ) -> None: ...
+        def ClearField(self, field_name: typing_extensions.Literal["backoff", b"backoff", "maxAttempts", b"maxAttempts", "maxBackoff", b"maxBackoff", "retryOn", b"retryOn"]) -> None: ...
+
+    @typing_extensions.final
+    class ResourceHooks(google.protobuf.message.Message):
+        """ResourceHooks describes the callbacks the engine should invoke before and after the operations on the resource."""
+
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        BEFORECREATE_FIELD_NUMBER: builtins.int
+        AFTERCREATE_FIELD_NUMBER: builtins.int
+        BEFOREUPDATE_FIELD_NUMBER: builtins.int
+        AFTERUPDATE_FIELD_NUMBER: builtins.int
+        BEFOREDELETE_FIELD_NUMBER: builtins.int
+        AFTERDELETE_FIELD_NUMBER: builtins.int
+        BEFOREREPLACE_FIELD_NUMBER: builtins.int
+        AFTERREPLACE_FIELD_NUMBER: builtins.int
+        @property
+        def beforeCreate(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[pulumi.callback_pb2.Callback]:
+            """hooks to invoke before the resource is created."""
+        @property
+        def afterCreate(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[pulumi.callback_pb2.Callback]:
+            """hooks to invoke after the resource is created."""
+        @property
+        def beforeUpdate(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[pulumi.callback_pb2.Callback]:
+            """hooks to invoke before the resource is updated."""
+        @property
+        def afterUpdate(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[pulumi.callback_pb2.Callback]:
+            """hooks to invoke after the resource is updated."""
+        @property
+        def beforeDelete(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[pulumi.callback_pb2.Callback]:
+            """hooks to invoke before the resource is deleted."""
+        @property
+        def afterDelete(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[pulumi.callback_pb2.Callback]:
+            """hooks to invoke after the resource is deleted."""
+        @property
+        def beforeReplace(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[pulumi.callback_pb2.Callback]:
+            """hooks to invoke before the resource is replaced."""
+        @property
+        def afterReplace(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[pulumi.callback_pb2.Callback]:
+            """hooks to invoke after the resource is replaced."""
+        def __init__(
+            self,
+            *,
+            beforeCreate: collections.abc.Iterable[pulumi.callback_pb2.Callback] | None = ...,
+            afterCreate: collections.abc.Iterable[pulumi.callback_pb2.Callback] | None = ...,
+            beforeUpdate: collections.abc.Iterable[pulumi.callback_pb2.Callback] | None = ...,
+            afterUpdate: collections.abc.Iterable[pulumi.callback_pb2.Callback] | None = ...,
+            beforeDelete: collections.abc.Iterable[pulumi.callback_pb2.Callback] | None = ...,
+            afterDelete: collections.abc.Iterable[pulumi.callback_pb2.Callback] | None = ...,
+            beforeReplace: collections.abc.Iterable[pulumi.callback_pb2.Callback] | None = ...,
+            afterReplace: collections.abc.Iterable[pulumi.callback_pb2.Callback] | None = ...,
+        ) -> None: ...
+        def ClearField(self, field_name: typing_extensions.Literal["afterCreate", b"afterCreate", "afterDelete", b"afterDelete", "afterReplace", b"afterReplace", "afterUpdate", b"afterUpdate", "beforeCreate", b"beforeCreate", "beforeDelete", b"beforeDelete", "beforeReplace", b"beforeReplace", "beforeUpdate", b"beforeUpdate"]) -> None: ...
+
     @typing_extensions.final
     class PropertyDependenciesEntry(google.protobuf.message.Message):
         DESCRIPTOR: google.protobuf.descriptor.Descriptor
@@ -340,6 +423,8 @@ class RegisterResourceRequest(google.protobuf.message.Message):
     SOURCEPOSITION_FIELD_NUMBER: builtins.int
     TRANSFORMS_FIELD_NUMBER: builtins.int
     SUPPORTSRESULTREPORTING_FIELD_NUMBER: builtins.int
+    RETRYPOLICY_FIELD_NUMBER: builtins.int
+    HOOKS_FIELD_NUMBER: builtins.int
     type: builtins.str
     """the type of the object allocated."""
     name: builtins.str
@@ -424,6 +509,12 @@ class RegisterResourceRequest(google.protobuf.message.Message):
         """a list of transforms to apply to the resource before registering it."""
     supportsResultReporting: builtins.bool
     """true if the request is from an SDK that supports the result field in the response."""
+    @property
+    def retryPolicy(self) -> global___RegisterResourceRequest.RetryPolicy:
+        """an optional policy for retrying failed provider operations on this resource."""
+    @property
+    def hooks(self) -> global___RegisterResourceRequest.ResourceHooks:
+        """an optional set of hooks to invoke around operations on this resource."""
     def __init__(
         self,
         *,
@@ -459,9 +550,11 @@ class RegisterResourceRequest(google.protobuf.message.Message):
         sourcePosition: pulumi.source_pb2.SourcePosition | None = ...,
         transforms: collections.abc.Iterable[pulumi.callback_pb2.Callback] | None = ...,
         supportsResultReporting: builtins.bool = ...,
+        retryPolicy: global___RegisterResourceRequest.RetryPolicy | None = ...,
+        hooks: global___RegisterResourceRequest.ResourceHooks | None = ...,
     ) -> None: ...
-    def HasField(self, field_name: typing_extensions.Literal["customTimeouts", b"customTimeouts", "object", b"object", "sourcePosition", b"sourcePosition"]) -> builtins.bool: ...
-    def ClearField(self, field_name: typing_extensions.Literal["acceptResources", b"acceptResources", "acceptSecrets", b"acceptSecrets", "additionalSecretOutputs", b"additionalSecretOutputs", "aliasSpecs", b"aliasSpecs", "aliasURNs", b"aliasURNs", "aliases", b"aliases", "custom", b"custom", "customTimeouts", b"customTimeouts", "deleteBeforeReplace", b"deleteBeforeReplace", "deleteBeforeReplaceDefined", b"deleteBeforeReplaceDefined", "deletedWith", b"deletedWith", "dependencies", b"dependencies", "ignoreChanges", b"ignoreChanges", "importId", b"importId", "name", b"name", "object", b"object", "parent", b"parent", "pluginChecksums", b"pluginChecksums", "pluginDownloadURL", b"pluginDownloadURL", "propertyDependencies", b"propertyDependencies", "protect", b"protect", "provider", b"provider", "providers", b"providers", "remote", b"remote", "replaceOnChanges", b"replaceOnChanges", "retainOnDelete", b"retainOnDelete", "sourcePosition", b"sourcePosition", "supportsPartialValues", b"supportsPartialValues", "supportsResultReporting", b"supportsResultReporting", "transforms", b"transforms", "type", b"type", "version", b"version"]) -> None: ...
+    def HasField(self, field_name: typing_extensions.Literal["customTimeouts", b"customTimeouts", "hooks", b"hooks", "object", b"object", "retryPolicy", b"retryPolicy", "sourcePosition", b"sourcePosition"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing_extensions.Literal["acceptResources", b"acceptResources", "acceptSecrets", b"acceptSecrets", "additionalSecretOutputs", b"additionalSecretOutputs", "aliasSpecs", b"aliasSpecs", "aliasURNs", b"aliasURNs", "aliases", b"aliases", "custom", b"custom", "customTimeouts", b"customTimeouts", "deleteBeforeReplace", b"deleteBeforeReplace", "deleteBeforeReplaceDefined", b"deleteBeforeReplaceDefined", "deletedWith", b"deletedWith", "dependencies", b"dependencies", "hooks", b"hooks", "ignoreChanges", b"ignoreChanges", "importId", b"importId", "name", b"name", "object", b"object", "parent", b"parent", "pluginChecksums", b"pluginChecksums", "pluginDownloadURL", b"pluginDownloadURL", "propertyDependencies", b"propertyDependencies", "protect", b"protect", "provider", b"provider", "providers", b"providers", "remote", b"remote", "replaceOnChanges", b"replaceOnChanges", "retainOnDelete", b"retainOnDelete", "retryPolicy", b"retryPolicy", "sourcePosition", b"sourcePosition", "supportsPartialValues", b"supportsPartialValues", "supportsResultReporting", b"supportsResultReporting", "transforms", b"transforms", "type", b"type", "version", b"version"]) -> None: ...
 
 global___RegisterResourceRequest = RegisterResourceRequest
 
@@ -898,3 +991,72 @@ class TransformResponse(google.protobuf.message.Message):
     def ClearField(self, field_name: typing_extensions.Literal["options", b"options", "properties", b"properties"]) -> None: ...
 
 global___TransformResponse = TransformResponse
+
+@typing_extensions.final
+class ResourceHookRequest(google.protobuf.message.Message):
+    """ResourceHookRequest is the request object for resource lifecycle hook callbacks."""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    URN_FIELD_NUMBER: builtins.int
+    ID_FIELD_NUMBER: builtins.int
+    OPERATION_FIELD_NUMBER: builtins.int
+    AFTER_FIELD_NUMBER: builtins.int
+    OLD_INPUTS_FIELD_NUMBER: builtins.int
+    OLD_OUTPUTS_FIELD_NUMBER: builtins.int
+    NEW_INPUTS_FIELD_NUMBER: builtins.int
+    NEW_OUTPUTS_FIELD_NUMBER: builtins.int
+    urn: builtins.str
+    """the URN of the resource."""
+    id: builtins.str
+    """the ID of the resource, if it has one."""
+    operation: builtins.str
+    """the operation the hook is invoked around: create, update, delete, or replace."""
+    after: builtins.bool
+    """true if the hook is invoked after the operation, false if before."""
+    @property
+    def old_inputs(self) -> google.protobuf.struct_pb2.Struct:
+        """the old input properties of the resource, if any."""
+    @property
+    def old_outputs(self) -> google.protobuf.struct_pb2.Struct:
+        """the old output properties of the resource, if any."""
+    @property
+    def new_inputs(self) -> google.protobuf.struct_pb2.Struct:
+        """the new input properties of the resource, if any."""
+    @property
+    def new_outputs(self) -> google.protobuf.struct_pb2.Struct:
+        """the new output properties of the resource, if any."""
+    def __init__(
+        self,
+        *,
+        urn: builtins.str = ...,
+        id: builtins.str = ...,
+        operation: builtins.str = ...,
+        after: builtins.bool = ...,
+        old_inputs: google.protobuf.struct_pb2.Struct | None = ...,
+        old_outputs: google.protobuf.struct_pb2.Struct | None = ...,
+        new_inputs: google.protobuf.struct_pb2.Struct | None = ...,
+        new_outputs: google.protobuf.struct_pb2.Struct | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing_extensions.Literal["new_inputs", b"new_inputs", "new_outputs", b"new_outputs", "old_inputs", b"old_inputs", "old_outputs", b"old_outputs"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing_extensions.Literal["after", b"after", "id", b"id", "new_inputs", b"new_inputs", "new_outputs", b"new_outputs", "old_inputs", b"old_inputs", "old_outputs", b"old_outputs", "operation", b"operation", "urn", b"urn"]) -> None: ...
+
+global___ResourceHookRequest = ResourceHookRequest
+
+@typing_extensions.final
+class ResourceHookResponse(google.protobuf.message.Message):
+    """ResourceHookResponse is the response object for resource lifecycle hook callbacks."""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ERROR_FIELD_NUMBER: builtins.int
+    error: builtins.str
+    """an optional error message; if set, the hook and the operation it was invoked around fail."""
+    def __init__(
+        self,
+        *,
+        error: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing_extensions.Literal["error", b"error"]) -> None: ...
+
+global___ResourceHookResponse = ResourceHookResponse
@@ -61,6 +61,14 @@ class ResourceMonitorStub:
         pulumi.callback_pb2.Callback,
         google.protobuf.empty_pb2.Empty,
     ]
+    SignalAndWaitForShutdown: grpc.UnaryUnaryMultiCallable[
+        google.protobuf.empty_pb2.Empty,
+        google.protobuf.empty_pb2.Empty,
+    ]
+    """SignalAndWaitForShutdown lets the engine know that the program has finished registering resources, and waits
+    for the deployment to finish before returning. Programs that registered callbacks, such as resource hooks, call
+    this so that the callbacks remain available while the engine deletes resources after the program has finished.
+    """
 
 class ResourceMonitorServicer(metaclass=abc.ABCMeta):
     """ResourceMonitor is the interface a source uses to talk back to the planning monitor orchestrating the execution."""
@@ -113,5 +121,15 @@ class ResourceMonitorServicer(metaclass=abc.ABCMeta):
         request: pulumi.callback_pb2.Callback,
         context: grpc.ServicerContext,
     ) -> google.protobuf.empty_pb2.Empty: ...
+    
+    def SignalAndWaitForShutdown(
+        self,
+        request: google.protobuf.empty_pb2.Empty,
+        context: grpc.ServicerContext,
+    ) -> google.protobuf.empty_pb2.Empty:
+        """SignalAndWaitForShutdown lets the engine know that the program has finished registering resources, and waits
+        for the deployment to finish before returning. Programs that registered callbacks, such as resource hooks, call
+        this so that the callbacks remain available while the engine deletes resources after the program has finished.
+        """
 
 def add_ResourceMonitorServicer_to_server(servicer: ResourceMonitorServicer, server: typing.Union[grpc.Server, grpc.aio.Server]) -> None: ...